@@ -0,0 +1,405 @@
+package users
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/smxlong/users/ent"
+	"github.com/smxlong/users/ent/predicate"
+	"github.com/smxlong/users/ent/user"
+	"github.com/smxlong/users/ent/userattribute"
+)
+
+// AttributeType is the type of a user attribute's values.
+type AttributeType int
+
+// Attribute types.
+const (
+	// AttributeString values are strings.
+	AttributeString AttributeType = iota
+	// AttributeInt values are int64. Other integer types are converted.
+	AttributeInt
+	// AttributeFloat values are float64. float32 values are converted.
+	AttributeFloat
+	// AttributeBool values are bool.
+	AttributeBool
+	// AttributeTime values are time.Time, in UTC.
+	AttributeTime
+)
+
+// String returns the name of the type.
+func (t AttributeType) String() string {
+	switch t {
+	case AttributeString:
+		return "string"
+	case AttributeInt:
+		return "int"
+	case AttributeFloat:
+		return "float"
+	case AttributeBool:
+		return "bool"
+	case AttributeTime:
+		return "time"
+	default:
+		return fmt.Sprintf("AttributeType(%d)", int(t))
+	}
+}
+
+// Attribute describes a user profile attribute.
+type Attribute struct {
+	// Name of the attribute. Required.
+	Name string
+	// Type of the attribute's values.
+	Type AttributeType
+	// Required attributes can't be deleted, and CheckRequiredAttributes
+	// fails for users without them.
+	Required bool
+	// Unique attributes can't have the same value for two users. Changing
+	// this doesn't affect values already set.
+	Unique bool
+	// Validate checks a value, which has already been converted to the
+	// attribute's type. Optional.
+	Validate func(value any) error
+}
+
+// AttributeRegistry holds the attributes that users may have. Attributes are
+// stored by name, so they can be added without changing the User schema.
+type AttributeRegistry struct {
+	mu    sync.RWMutex
+	attrs map[string]*Attribute
+}
+
+// NewAttributeRegistry returns a registry holding the attributes.
+func NewAttributeRegistry(attrs ...Attribute) (*AttributeRegistry, error) {
+	r := &AttributeRegistry{}
+	for _, a := range attrs {
+		if err := r.Register(a); err != nil {
+			return nil, err
+		}
+	}
+	return r, nil
+}
+
+// Register adds an attribute. It fails with ErrAttributeExists if an
+// attribute with the same name is already registered.
+func (r *AttributeRegistry) Register(a Attribute) error {
+	if a.Name == "" {
+		return fmt.Errorf("%w: name required", ErrAttributeInvalid)
+	}
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if _, ok := r.attrs[a.Name]; ok {
+		return fmt.Errorf("%w: %s", ErrAttributeExists, a.Name)
+	}
+	if r.attrs == nil {
+		r.attrs = map[string]*Attribute{}
+	}
+	r.attrs[a.Name] = &a
+	return nil
+}
+
+// Lookup returns the attribute with the given name, if registered.
+func (r *AttributeRegistry) Lookup(name string) (*Attribute, bool) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	a, ok := r.attrs[name]
+	return a, ok
+}
+
+// Get returns the value of a user's attribute, or nil if it isn't set.
+func (r *AttributeRegistry) Get(ctx context.Context, client *ent.Client, u *ent.User, name string) (any, error) {
+	a, err := r.lookup(name)
+	if err != nil {
+		return nil, err
+	}
+	ua, err := client.UserAttribute.Query().
+		Where(userattribute.Name(name), userattribute.HasUserWith(user.ID(u.ID))).
+		Only(ctx)
+	if ent.IsNotFound(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return a.decode(ua.Value)
+}
+
+// Attributes returns all of a user's registered attributes that are set.
+func (r *AttributeRegistry) Attributes(ctx context.Context, client *ent.Client, u *ent.User) (map[string]any, error) {
+	uas, err := client.UserAttribute.Query().
+		Where(userattribute.HasUserWith(user.ID(u.ID))).
+		All(ctx)
+	if err != nil {
+		return nil, err
+	}
+	values := map[string]any{}
+	for _, ua := range uas {
+		a, ok := r.Lookup(ua.Name)
+		if !ok {
+			continue
+		}
+		v, err := a.decode(ua.Value)
+		if err != nil {
+			return nil, err
+		}
+		values[ua.Name] = v
+	}
+	return values, nil
+}
+
+// Set sets a user's attribute. The value must have the attribute's type and
+// pass its validation, or the error wraps ErrAttributeInvalid. If the
+// attribute is unique and another user has the value, it fails with
+// ErrAttributeNotUnique.
+func (r *AttributeRegistry) Set(ctx context.Context, client *ent.Client, u *ent.User, name string, value any) error {
+	a, err := r.lookup(name)
+	if err != nil {
+		return err
+	}
+	s, err := a.encode(value)
+	if err != nil {
+		return err
+	}
+	return a.set(ctx, client, u, s)
+}
+
+// SetAll sets several of a user's attributes, checking all the values before
+// setting any. Afterwards the user must have all required attributes, or it
+// fails with ErrAttributeRequired. The attributes are set in a transaction,
+// so that if setting one fails, none are set.
+func (r *AttributeRegistry) SetAll(ctx context.Context, client *ent.Client, u *ent.User, values map[string]any) error {
+	encoded := map[*Attribute]string{}
+	for name, value := range values {
+		a, err := r.lookup(name)
+		if err != nil {
+			return err
+		}
+		s, err := a.encode(value)
+		if err != nil {
+			return err
+		}
+		encoded[a] = s
+	}
+	_, err := inTx(ctx, client, func(client *ent.Client) error {
+		if err := r.checkRequired(ctx, client, u, values); err != nil {
+			return err
+		}
+		for a, s := range encoded {
+			if err := a.set(ctx, client, u, s); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+	return err
+}
+
+// Delete removes a user's attribute. Required attributes can't be removed.
+func (r *AttributeRegistry) Delete(ctx context.Context, client *ent.Client, u *ent.User, name string) error {
+	a, err := r.lookup(name)
+	if err != nil {
+		return err
+	}
+	if a.Required {
+		return fmt.Errorf("%w: %s", ErrAttributeRequired, name)
+	}
+	_, err = client.UserAttribute.Delete().
+		Where(userattribute.Name(name), userattribute.HasUserWith(user.ID(u.ID))).
+		Exec(ctx)
+	return err
+}
+
+// CheckRequiredAttributes fails with ErrAttributeRequired if the user is
+// missing any required attributes.
+func (r *AttributeRegistry) CheckRequiredAttributes(ctx context.Context, client *ent.Client, u *ent.User) error {
+	return r.checkRequired(ctx, client, u, nil)
+}
+
+// Where returns a predicate matching users whose attribute has the value,
+// for use in user queries.
+func (r *AttributeRegistry) Where(name string, value any) (predicate.User, error) {
+	a, err := r.lookup(name)
+	if err != nil {
+		return nil, err
+	}
+	s, err := a.encode(value)
+	if err != nil {
+		return nil, err
+	}
+	return user.HasAttributesWith(userattribute.Name(name), userattribute.Value(s)), nil
+}
+
+// FindUsers finds the users whose attribute has the value.
+func (r *AttributeRegistry) FindUsers(ctx context.Context, client *ent.Client, name string, value any) ([]*ent.User, error) {
+	p, err := r.Where(name, value)
+	if err != nil {
+		return nil, err
+	}
+	return client.User.Query().
+		Where(p).
+		All(ctx)
+}
+
+// lookup returns the attribute with the given name, or fails with
+// ErrAttributeUnknown.
+func (r *AttributeRegistry) lookup(name string) (*Attribute, error) {
+	a, ok := r.Lookup(name)
+	if !ok {
+		return nil, fmt.Errorf("%w: %s", ErrAttributeUnknown, name)
+	}
+	return a, nil
+}
+
+// checkRequired fails with ErrAttributeRequired if the user is missing any
+// required attributes, not counting those about to be set.
+func (r *AttributeRegistry) checkRequired(ctx context.Context, client *ent.Client, u *ent.User, setting map[string]any) error {
+	names, err := client.UserAttribute.Query().
+		Where(userattribute.HasUserWith(user.ID(u.ID))).
+		Select(userattribute.FieldName).
+		Strings(ctx)
+	if err != nil {
+		return err
+	}
+	have := map[string]bool{}
+	for _, name := range names {
+		have[name] = true
+	}
+	var missing []string
+	r.mu.RLock()
+	for name, a := range r.attrs {
+		if _, ok := setting[name]; a.Required && !ok && !have[name] {
+			missing = append(missing, name)
+		}
+	}
+	r.mu.RUnlock()
+	if len(missing) > 0 {
+		sort.Strings(missing)
+		return fmt.Errorf("%w: %s", ErrAttributeRequired, strings.Join(missing, ", "))
+	}
+	return nil
+}
+
+// set stores an encoded value for a user's attribute.
+func (a *Attribute) set(ctx context.Context, client *ent.Client, u *ent.User, s string) error {
+	for retried := false; ; retried = true {
+		update := client.UserAttribute.Update().
+			Where(userattribute.Name(a.Name), userattribute.HasUserWith(user.ID(u.ID))).
+			SetValue(s)
+		create := client.UserAttribute.Create().
+			SetName(a.Name).
+			SetValue(s).
+			SetUser(u)
+		if a.Unique {
+			update.SetUniqueValue(s)
+			create.SetUniqueValue(s)
+		} else {
+			update.ClearUniqueValue()
+		}
+		n, err := update.Save(ctx)
+		if ent.IsConstraintError(err) {
+			return fmt.Errorf("%w: %s", ErrAttributeNotUnique, a.Name)
+		}
+		if err != nil {
+			return err
+		}
+		if n > 0 {
+			return nil
+		}
+		err = create.Exec(ctx)
+		if ent.IsConstraintError(err) {
+			// A concurrent Set may have created the attribute first, in which
+			// case updating it will now work.
+			if !retried {
+				continue
+			}
+			return fmt.Errorf("%w: %s", ErrAttributeNotUnique, a.Name)
+		}
+		return err
+	}
+}
+
+// encode converts a value to the attribute's type, validates it and encodes
+// it as text.
+func (a *Attribute) encode(value any) (string, error) {
+	v, err := a.convert(value)
+	if err != nil {
+		return "", err
+	}
+	if a.Validate != nil {
+		if err := a.Validate(v); err != nil {
+			return "", fmt.Errorf("%w: %s: %s", ErrAttributeInvalid, a.Name, err)
+		}
+	}
+	switch v := v.(type) {
+	case string:
+		return v, nil
+	case int64:
+		return strconv.FormatInt(v, 10), nil
+	case float64:
+		return strconv.FormatFloat(v, 'g', -1, 64), nil
+	case bool:
+		return strconv.FormatBool(v), nil
+	case time.Time:
+		return v.Format(time.RFC3339Nano), nil
+	default:
+		return "", fmt.Errorf("%w: %s has unknown type %s", ErrAttributeInvalid, a.Name, a.Type)
+	}
+}
+
+// convert converts a value to the attribute's type.
+func (a *Attribute) convert(value any) (any, error) {
+	switch a.Type {
+	case AttributeString:
+		if v, ok := value.(string); ok {
+			return v, nil
+		}
+	case AttributeInt:
+		switch v := value.(type) {
+		case int:
+			return int64(v), nil
+		case int32:
+			return int64(v), nil
+		case int64:
+			return v, nil
+		}
+	case AttributeFloat:
+		switch v := value.(type) {
+		case float32:
+			return float64(v), nil
+		case float64:
+			return v, nil
+		}
+	case AttributeBool:
+		if v, ok := value.(bool); ok {
+			return v, nil
+		}
+	case AttributeTime:
+		if v, ok := value.(time.Time); ok {
+			return v.UTC(), nil
+		}
+	}
+	return nil, fmt.Errorf("%w: %s must be %s, not %T", ErrAttributeInvalid, a.Name, a.Type, value)
+}
+
+// decode decodes a value stored as text.
+func (a *Attribute) decode(s string) (any, error) {
+	switch a.Type {
+	case AttributeString:
+		return s, nil
+	case AttributeInt:
+		return strconv.ParseInt(s, 10, 64)
+	case AttributeFloat:
+		return strconv.ParseFloat(s, 64)
+	case AttributeBool:
+		return strconv.ParseBool(s)
+	case AttributeTime:
+		return time.Parse(time.RFC3339Nano, s)
+	default:
+		return nil, fmt.Errorf("%w: %s has unknown type %s", ErrAttributeInvalid, a.Name, a.Type)
+	}
+}
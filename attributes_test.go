@@ -0,0 +1,126 @@
+package users
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func testAttributeRegistry(t *testing.T) *AttributeRegistry {
+	r, err := NewAttributeRegistry(
+		Attribute{
+			Name:   "phone",
+			Type:   AttributeString,
+			Unique: true,
+			Validate: func(v any) error {
+				if !strings.HasPrefix(v.(string), "+") {
+					return errors.New("must start with +")
+				}
+				return nil
+			},
+		},
+		Attribute{Name: "locale", Type: AttributeString, Required: true},
+		Attribute{Name: "department", Type: AttributeString},
+		Attribute{Name: "level", Type: AttributeInt},
+		Attribute{Name: "hired", Type: AttributeTime},
+	)
+	require.NoError(t, err)
+	return r
+}
+
+func Test_that_AttributeRegistry_Register_rejects_duplicates(t *testing.T) {
+	r := testAttributeRegistry(t)
+	require.ErrorIs(t, r.Register(Attribute{Name: "phone"}), ErrAttributeExists)
+}
+
+func Test_that_AttributeRegistry_Set_and_Get_work(t *testing.T) {
+	client := setupAndMigrate(t)
+	ctx := context.Background()
+	r := testAttributeRegistry(t)
+	u, err := Create(ctx, client, "user1", USER1_TEST_EMAIL, "password")
+	require.NoError(t, err)
+	v, err := r.Get(ctx, client, u, "level")
+	require.NoError(t, err)
+	require.Nil(t, v)
+	require.NoError(t, r.Set(ctx, client, u, "level", 3))
+	v, err = r.Get(ctx, client, u, "level")
+	require.NoError(t, err)
+	require.Equal(t, int64(3), v)
+	require.NoError(t, r.Set(ctx, client, u, "level", int64(4)))
+	v, err = r.Get(ctx, client, u, "level")
+	require.NoError(t, err)
+	require.Equal(t, int64(4), v)
+	hired := time.Date(2020, 1, 2, 3, 4, 5, 0, time.UTC)
+	require.NoError(t, r.Set(ctx, client, u, "hired", hired))
+	values, err := r.Attributes(ctx, client, u)
+	require.NoError(t, err)
+	require.Equal(t, map[string]any{"level": int64(4), "hired": hired}, values)
+}
+
+func Test_that_AttributeRegistry_Set_validates_values(t *testing.T) {
+	client := setupAndMigrate(t)
+	ctx := context.Background()
+	r := testAttributeRegistry(t)
+	u, err := Create(ctx, client, "user1", USER1_TEST_EMAIL, "password")
+	require.NoError(t, err)
+	require.ErrorIs(t, r.Set(ctx, client, u, "level", "three"), ErrAttributeInvalid)
+	require.ErrorIs(t, r.Set(ctx, client, u, "phone", "555-1234"), ErrAttributeInvalid)
+	require.ErrorIs(t, r.Set(ctx, client, u, "shoe size", 9), ErrAttributeUnknown)
+}
+
+func Test_that_AttributeRegistry_enforces_uniqueness(t *testing.T) {
+	client := setupAndMigrate(t)
+	ctx := context.Background()
+	r := testAttributeRegistry(t)
+	u1, err := Create(ctx, client, "user1", USER1_TEST_EMAIL, "password")
+	require.NoError(t, err)
+	u2, err := Create(ctx, client, "user2", USER2_TEST_EMAIL, "password")
+	require.NoError(t, err)
+	require.NoError(t, r.Set(ctx, client, u1, "phone", "+15551234"))
+	require.ErrorIs(t, r.Set(ctx, client, u2, "phone", "+15551234"), ErrAttributeNotUnique)
+	// attributes that needn't be unique can be shared
+	require.NoError(t, r.Set(ctx, client, u1, "department", "sales"))
+	require.NoError(t, r.Set(ctx, client, u2, "department", "sales"))
+}
+
+func Test_that_AttributeRegistry_enforces_required_attributes(t *testing.T) {
+	client := setupAndMigrate(t)
+	ctx := context.Background()
+	r := testAttributeRegistry(t)
+	u, err := Create(ctx, client, "user1", USER1_TEST_EMAIL, "password")
+	require.NoError(t, err)
+	require.ErrorIs(t, r.CheckRequiredAttributes(ctx, client, u), ErrAttributeRequired)
+	require.ErrorIs(t, r.SetAll(ctx, client, u, map[string]any{"department": "sales"}), ErrAttributeRequired)
+	require.NoError(t, r.SetAll(ctx, client, u, map[string]any{"department": "sales", "locale": "en-US"}))
+	require.NoError(t, r.CheckRequiredAttributes(ctx, client, u))
+	require.ErrorIs(t, r.Delete(ctx, client, u, "locale"), ErrAttributeRequired)
+	require.NoError(t, r.Delete(ctx, client, u, "department"))
+	v, err := r.Get(ctx, client, u, "department")
+	require.NoError(t, err)
+	require.Nil(t, v)
+}
+
+func Test_that_AttributeRegistry_FindUsers_works(t *testing.T) {
+	client := setupAndMigrate(t)
+	ctx := context.Background()
+	r := testAttributeRegistry(t)
+	u1, err := Create(ctx, client, "user1", USER1_TEST_EMAIL, "password")
+	require.NoError(t, err)
+	u2, err := Create(ctx, client, "user2", USER2_TEST_EMAIL, "password")
+	require.NoError(t, err)
+	require.NoError(t, r.Set(ctx, client, u1, "department", "sales"))
+	require.NoError(t, r.Set(ctx, client, u2, "department", "support"))
+	found, err := r.FindUsers(ctx, client, "department", "sales")
+	require.NoError(t, err)
+	require.Len(t, found, 1)
+	require.Equal(t, u1.ID, found[0].ID)
+	p, err := r.Where("department", "support")
+	require.NoError(t, err)
+	n, err := client.User.Query().Where(p).Count(ctx)
+	require.NoError(t, err)
+	require.Equal(t, 1, n)
+}
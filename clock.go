@@ -0,0 +1,78 @@
+package users
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/smxlong/users/ent/schema"
+)
+
+// Clock tells the time.
+type Clock interface {
+	Now() time.Time
+}
+
+// systemClock is the Clock using the system time.
+type systemClock struct{}
+
+// Now returns the current time.
+func (systemClock) Now() time.Time {
+	return time.Now()
+}
+
+// FakeClock is a Clock that only moves when told to. It is intended for
+// tests.
+type FakeClock struct {
+	mu  sync.Mutex
+	now time.Time
+}
+
+// NewFakeClock returns a FakeClock telling the given time.
+func NewFakeClock(now time.Time) *FakeClock {
+	return &FakeClock{now: now}
+}
+
+// Now returns the clock's time.
+func (c *FakeClock) Now() time.Time {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.now
+}
+
+// Set sets the clock's time.
+func (c *FakeClock) Set(now time.Time) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.now = now
+}
+
+// Advance moves the clock's time on by d.
+func (c *FakeClock) Advance(d time.Duration) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.now = c.now.Add(d)
+}
+
+type clockKey struct{}
+
+// ContextWithClock returns a context in which the package's functions read
+// the time from the clock, such as to issue and validate tokens, to check
+// expiry of one-time tokens, lockouts and suspensions, and to record login,
+// creation, update and deletion times.
+func ContextWithClock(parent context.Context, clock Clock) context.Context {
+	return context.WithValue(schema.WithNow(parent, clock.Now), clockKey{}, clock)
+}
+
+// clockFrom returns the context's Clock, or the system clock.
+func clockFrom(ctx context.Context) Clock {
+	if clock, ok := ctx.Value(clockKey{}).(Clock); ok {
+		return clock
+	}
+	return systemClock{}
+}
+
+// clockNow returns the time according to the context's Clock.
+func clockNow(ctx context.Context) time.Time {
+	return clockFrom(ctx).Now()
+}
@@ -2,10 +2,10 @@ package users
 
 import (
 	"context"
+	"errors"
+	"fmt"
 
 	"github.com/smxlong/users/ent"
-	"github.com/smxlong/users/ent/permission"
-	"github.com/smxlong/users/ent/role"
 )
 
 // Roles represents all the roles that should exist as well as their permissions.
@@ -15,6 +15,9 @@ type Roles map[string]*RoleWithPermissions
 type RoleWithPermissions struct {
 	Description string
 	Permissions []*Permission
+	// Inherits are the names of the roles this role inherits the
+	// permissions of. They must be in the same Roles.
+	Inherits []string
 }
 
 // Permission represents a role's permission.
@@ -23,32 +26,106 @@ type Permission struct {
 	Description string
 }
 
-// CreateRolesAndPermissions creates all the roles and permissions.
+// graph returns the roles as a roleGraph.
+func (r Roles) graph() roleGraph {
+	g := roleGraph{}
+	for name, rp := range r {
+		role := &Role{Name: name, Description: rp.Description, Inherits: rp.Inherits}
+		for _, p := range rp.Permissions {
+			role.Permissions = append(role.Permissions, p.Name)
+		}
+		g[name] = role
+	}
+	return g
+}
+
+// checkInherits checks that roles only inherit roles in the map, returning
+// ErrRoleUnknown if not, and that no role inherits itself, returning
+// ErrRoleCycle if one does.
+func (r Roles) checkInherits() error {
+	for _, name := range sortedRoleNames(r) {
+		for _, parent := range r[name].Inherits {
+			if _, ok := r[parent]; !ok {
+				return fmt.Errorf("%w: %q inherited by %q", ErrRoleUnknown, parent, name)
+			}
+		}
+	}
+	return r.graph().checkCycles()
+}
+
+// CreateRolesAndPermissions creates all the roles and permissions. It runs in
+// a transaction, so that if it fails, none of the changes are made.
 func CreateRolesAndPermissions(ctx context.Context, client *ent.Client, rolesAndPermissions Roles) error {
-	// Create a map of permission name to permission. Existing permissions are referenced.
-	// Missing permissions are created.
-	permissionByName := map[string]*ent.Permission{}
-	for _, rolePermissions := range rolesAndPermissions {
-		for _, rp := range rolePermissions.Permissions {
-			permission, err := FindOrCreatePermission(ctx, client, rp.Name, rp.Description)
+	return entService(ctx, client).CreateRolesAndPermissions(ctx, rolesAndPermissions)
+}
+
+// createRolesAndPermissions creates all the roles and permissions in the
+// store.
+func createRolesAndPermissions(ctx context.Context, store Store, rolesAndPermissions Roles) error {
+	if err := rolesAndPermissions.checkInherits(); err != nil {
+		return err
+	}
+	roleNames := sortedRoleNames(rolesAndPermissions)
+	// Find or create the permissions. Existing permissions must have the same
+	// description.
+	for _, roleName := range roleNames {
+		for _, rp := range rolesAndPermissions[roleName].Permissions {
+			p, err := store.FindPermission(ctx, rp.Name)
+			if errors.Is(err, ErrNotFound) {
+				_, err = store.CreatePermission(ctx, rp)
+				if err != nil {
+					return err
+				}
+				continue
+			}
 			if err != nil {
 				return err
 			}
-			permissionByName[rp.Name] = permission
+			if p.Description != rp.Description {
+				return ErrPermissionDescriptionMismatch
+			}
 		}
 	}
-	// Update each role
-	for roleName, rolePermissions := range rolesAndPermissions {
-		role, err := FindOrCreateRole(ctx, client, roleName, rolePermissions.Description)
+	// Find or create the roles, and set their descriptions and permissions.
+	for _, roleName := range roleNames {
+		rolePermissions := rolesAndPermissions[roleName]
+		permissions := make([]string, len(rolePermissions.Permissions))
+		for i, rp := range rolePermissions.Permissions {
+			permissions[i] = rp.Name
+		}
+		r, err := store.FindRole(ctx, roleName)
+		if errors.Is(err, ErrNotFound) {
+			_, err = store.CreateRole(ctx, &Role{
+				Name:        roleName,
+				Description: rolePermissions.Description,
+				Permissions: permissions,
+			})
+			if err != nil {
+				return err
+			}
+			continue
+		}
 		if err != nil {
 			return err
 		}
-		permissions := make([]*ent.Permission, len(rolePermissions.Permissions))
-		for i, rp := range rolePermissions.Permissions {
-			permissions[i] = permissionByName[rp.Name]
+		if r.Description != rolePermissions.Description {
+			if err := store.UpdateRoleDescription(ctx, roleName, rolePermissions.Description); err != nil {
+				return err
+			}
 		}
-		_, err = SetRolePermissions(ctx, client, role, permissions)
-		if err != nil {
+		if err := store.SetRolePermissions(ctx, roleName, permissions); err != nil {
+			return err
+		}
+	}
+	// Set the roles each role inherits once they all exist, clearing them
+	// first so that the inheritance being replaced can't make a cycle.
+	for _, roleName := range roleNames {
+		if err := store.SetRoleInherits(ctx, roleName, nil); err != nil {
+			return err
+		}
+	}
+	for _, roleName := range roleNames {
+		if err := store.SetRoleInherits(ctx, roleName, rolesAndPermissions[roleName].Inherits); err != nil {
 			return err
 		}
 	}
@@ -57,23 +134,23 @@ func CreateRolesAndPermissions(ctx context.Context, client *ent.Client, rolesAnd
 
 // SyncRolesAndPermissions synchronizes the roles and permissions. This calls
 // CreateRolesAndPermissions with the given roles and permissions, and then
-// deletes any roles or permissions that are not in the given map.
+// deletes any roles or permissions that are not in the given map. It runs in
+// a transaction, so that if it fails, none of the changes are made. Use
+// PlanRolesAndPermissions to review the changes before making them.
 func SyncRolesAndPermissions(ctx context.Context, client *ent.Client, rolesAndPermissions Roles) error {
-	// Create the roles and permissions
-	if err := CreateRolesAndPermissions(ctx, client, rolesAndPermissions); err != nil {
-		return err
-	}
-	var roleNames []string
-	for roleName := range rolesAndPermissions {
-		roleNames = append(roleNames, roleName)
-	}
-	// Delete any roles that are not in the slice
-	if _, err := client.Role.Delete().Where(role.NameNotIn(roleNames...)).Exec(ctx); err != nil {
+	return entService(ctx, client).SyncRolesAndPermissions(ctx, rolesAndPermissions)
+}
+
+// syncRolesAndPermissions synchronizes the roles and permissions in the store
+// by planning the changes and applying the plan. The extra permissions are
+// kept or created whether or not any role has them.
+func syncRolesAndPermissions(ctx context.Context, store Store, rolesAndPermissions Roles, extra []*Permission) error {
+	plan, _, err := planChanges(ctx, store, rolesAndPermissions, extra)
+	if err != nil {
 		return err
 	}
-	// Delete any permissions that aren't part of a role
-	if _, err := client.Permission.Delete().Where(permission.Not(permission.HasRoles())).Exec(ctx); err != nil {
-		return err
+	if len(plan.UpdatePermissions) > 0 {
+		return ErrPermissionDescriptionMismatch
 	}
-	return nil
+	return applyPlan(ctx, store, plan)
 }
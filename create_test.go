@@ -120,6 +120,13 @@ func Test_that_CreateRolesAndPermissions_fails_with_mismatched_permission_descri
 	err := CreateRolesAndPermissions(ctx, client, testRolesAndPermissions)
 	require.Error(t, err)
 	require.ErrorIs(t, err, ErrPermissionDescriptionMismatch)
+	// nothing was created
+	n, err := client.Permission.Query().Count(ctx)
+	require.NoError(t, err)
+	require.Zero(t, n)
+	n, err = client.Role.Query().Count(ctx)
+	require.NoError(t, err)
+	require.Zero(t, n)
 }
 
 func Test_that_SyncRolesAndPermissions_works_when_removing_roles(t *testing.T) {
@@ -0,0 +1,189 @@
+// Code generated by ent, DO NOT EDIT.
+
+package ent
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"entgo.io/ent"
+	"entgo.io/ent/dialect/sql"
+	"github.com/smxlong/users/ent/credential"
+	"github.com/smxlong/users/ent/user"
+)
+
+// Credential is the model entity for the Credential schema.
+type Credential struct {
+	config `json:"-"`
+	// ID of the ent.
+	ID int `json:"id,omitempty"`
+	// CredentialID holds the value of the "credential_id" field.
+	CredentialID []byte `json:"credential_id,omitempty"`
+	// PublicKey holds the value of the "public_key" field.
+	PublicKey []byte `json:"public_key,omitempty"`
+	// SignCount holds the value of the "sign_count" field.
+	SignCount uint32 `json:"sign_count,omitempty"`
+	// Transports holds the value of the "transports" field.
+	Transports []string `json:"transports,omitempty"`
+	// Aaguid holds the value of the "aaguid" field.
+	Aaguid []byte `json:"aaguid,omitempty"`
+	// Edges holds the relations/edges for other nodes in the graph.
+	// The values are being populated by the CredentialQuery when eager-loading is set.
+	Edges            CredentialEdges `json:"edges"`
+	user_credentials *int
+	selectValues     sql.SelectValues
+}
+
+// CredentialEdges holds the relations/edges for other nodes in the graph.
+type CredentialEdges struct {
+	// User holds the value of the user edge.
+	User *User `json:"user,omitempty"`
+	// loadedTypes holds the information for reporting if a
+	// type was loaded (or requested) in eager-loading or not.
+	loadedTypes [1]bool
+}
+
+// UserOrErr returns the User value or an error if the edge
+// was not loaded in eager-loading, or loaded but was not found.
+func (e CredentialEdges) UserOrErr() (*User, error) {
+	if e.User != nil {
+		return e.User, nil
+	} else if e.loadedTypes[0] {
+		return nil, &NotFoundError{label: user.Label}
+	}
+	return nil, &NotLoadedError{edge: "user"}
+}
+
+// scanValues returns the types for scanning values from sql.Rows.
+func (*Credential) scanValues(columns []string) ([]any, error) {
+	values := make([]any, len(columns))
+	for i := range columns {
+		switch columns[i] {
+		case credential.FieldCredentialID, credential.FieldPublicKey, credential.FieldTransports, credential.FieldAaguid:
+			values[i] = new([]byte)
+		case credential.FieldID, credential.FieldSignCount:
+			values[i] = new(sql.NullInt64)
+		case credential.ForeignKeys[0]: // user_credentials
+			values[i] = new(sql.NullInt64)
+		default:
+			values[i] = new(sql.UnknownType)
+		}
+	}
+	return values, nil
+}
+
+// assignValues assigns the values that were returned from sql.Rows (after scanning)
+// to the Credential fields.
+func (c *Credential) assignValues(columns []string, values []any) error {
+	if m, n := len(values), len(columns); m < n {
+		return fmt.Errorf("mismatch number of scan values: %d != %d", m, n)
+	}
+	for i := range columns {
+		switch columns[i] {
+		case credential.FieldID:
+			value, ok := values[i].(*sql.NullInt64)
+			if !ok {
+				return fmt.Errorf("unexpected type %T for field id", value)
+			}
+			c.ID = int(value.Int64)
+		case credential.FieldCredentialID:
+			if value, ok := values[i].(*[]byte); !ok {
+				return fmt.Errorf("unexpected type %T for field credential_id", values[i])
+			} else if value != nil {
+				c.CredentialID = *value
+			}
+		case credential.FieldPublicKey:
+			if value, ok := values[i].(*[]byte); !ok {
+				return fmt.Errorf("unexpected type %T for field public_key", values[i])
+			} else if value != nil {
+				c.PublicKey = *value
+			}
+		case credential.FieldSignCount:
+			if value, ok := values[i].(*sql.NullInt64); !ok {
+				return fmt.Errorf("unexpected type %T for field sign_count", values[i])
+			} else if value.Valid {
+				c.SignCount = uint32(value.Int64)
+			}
+		case credential.FieldTransports:
+			if value, ok := values[i].(*[]byte); !ok {
+				return fmt.Errorf("unexpected type %T for field transports", values[i])
+			} else if value != nil && len(*value) > 0 {
+				if err := json.Unmarshal(*value, &c.Transports); err != nil {
+					return fmt.Errorf("unmarshal field transports: %w", err)
+				}
+			}
+		case credential.FieldAaguid:
+			if value, ok := values[i].(*[]byte); !ok {
+				return fmt.Errorf("unexpected type %T for field aaguid", values[i])
+			} else if value != nil {
+				c.Aaguid = *value
+			}
+		case credential.ForeignKeys[0]:
+			if value, ok := values[i].(*sql.NullInt64); !ok {
+				return fmt.Errorf("unexpected type %T for edge-field user_credentials", value)
+			} else if value.Valid {
+				c.user_credentials = new(int)
+				*c.user_credentials = int(value.Int64)
+			}
+		default:
+			c.selectValues.Set(columns[i], values[i])
+		}
+	}
+	return nil
+}
+
+// Value returns the ent.Value that was dynamically selected and assigned to the Credential.
+// This includes values selected through modifiers, order, etc.
+func (c *Credential) Value(name string) (ent.Value, error) {
+	return c.selectValues.Get(name)
+}
+
+// QueryUser queries the "user" edge of the Credential entity.
+func (c *Credential) QueryUser() *UserQuery {
+	return NewCredentialClient(c.config).QueryUser(c)
+}
+
+// Update returns a builder for updating this Credential.
+// Note that you need to call Credential.Unwrap() before calling this method if this Credential
+// was returned from a transaction, and the transaction was committed or rolled back.
+func (c *Credential) Update() *CredentialUpdateOne {
+	return NewCredentialClient(c.config).UpdateOne(c)
+}
+
+// Unwrap unwraps the Credential entity that was returned from a transaction after it was closed,
+// so that all future queries will be executed through the driver which created the transaction.
+func (c *Credential) Unwrap() *Credential {
+	_tx, ok := c.config.driver.(*txDriver)
+	if !ok {
+		panic("ent: Credential is not a transactional entity")
+	}
+	c.config.driver = _tx.drv
+	return c
+}
+
+// String implements the fmt.Stringer.
+func (c *Credential) String() string {
+	var builder strings.Builder
+	builder.WriteString("Credential(")
+	builder.WriteString(fmt.Sprintf("id=%v, ", c.ID))
+	builder.WriteString("credential_id=")
+	builder.WriteString(fmt.Sprintf("%v", c.CredentialID))
+	builder.WriteString(", ")
+	builder.WriteString("public_key=")
+	builder.WriteString(fmt.Sprintf("%v", c.PublicKey))
+	builder.WriteString(", ")
+	builder.WriteString("sign_count=")
+	builder.WriteString(fmt.Sprintf("%v", c.SignCount))
+	builder.WriteString(", ")
+	builder.WriteString("transports=")
+	builder.WriteString(fmt.Sprintf("%v", c.Transports))
+	builder.WriteString(", ")
+	builder.WriteString("aaguid=")
+	builder.WriteString(fmt.Sprintf("%v", c.Aaguid))
+	builder.WriteByte(')')
+	return builder.String()
+}
+
+// Credentials is a parsable slice of Credential.
+type Credentials []*Credential
@@ -0,0 +1,495 @@
+// Code generated by ent, DO NOT EDIT.
+
+package ent
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"entgo.io/ent/dialect/sql"
+	"entgo.io/ent/dialect/sql/sqlgraph"
+	"entgo.io/ent/dialect/sql/sqljson"
+	"entgo.io/ent/schema/field"
+	"github.com/smxlong/users/ent/credential"
+	"github.com/smxlong/users/ent/predicate"
+	"github.com/smxlong/users/ent/user"
+)
+
+// CredentialUpdate is the builder for updating Credential entities.
+type CredentialUpdate struct {
+	config
+	hooks    []Hook
+	mutation *CredentialMutation
+}
+
+// Where appends a list predicates to the CredentialUpdate builder.
+func (cu *CredentialUpdate) Where(ps ...predicate.Credential) *CredentialUpdate {
+	cu.mutation.Where(ps...)
+	return cu
+}
+
+// SetCredentialID sets the "credential_id" field.
+func (cu *CredentialUpdate) SetCredentialID(b []byte) *CredentialUpdate {
+	cu.mutation.SetCredentialID(b)
+	return cu
+}
+
+// SetPublicKey sets the "public_key" field.
+func (cu *CredentialUpdate) SetPublicKey(b []byte) *CredentialUpdate {
+	cu.mutation.SetPublicKey(b)
+	return cu
+}
+
+// SetSignCount sets the "sign_count" field.
+func (cu *CredentialUpdate) SetSignCount(u uint32) *CredentialUpdate {
+	cu.mutation.ResetSignCount()
+	cu.mutation.SetSignCount(u)
+	return cu
+}
+
+// SetNillableSignCount sets the "sign_count" field if the given value is not nil.
+func (cu *CredentialUpdate) SetNillableSignCount(u *uint32) *CredentialUpdate {
+	if u != nil {
+		cu.SetSignCount(*u)
+	}
+	return cu
+}
+
+// AddSignCount adds u to the "sign_count" field.
+func (cu *CredentialUpdate) AddSignCount(u int32) *CredentialUpdate {
+	cu.mutation.AddSignCount(u)
+	return cu
+}
+
+// SetTransports sets the "transports" field.
+func (cu *CredentialUpdate) SetTransports(s []string) *CredentialUpdate {
+	cu.mutation.SetTransports(s)
+	return cu
+}
+
+// AppendTransports appends s to the "transports" field.
+func (cu *CredentialUpdate) AppendTransports(s []string) *CredentialUpdate {
+	cu.mutation.AppendTransports(s)
+	return cu
+}
+
+// ClearTransports clears the value of the "transports" field.
+func (cu *CredentialUpdate) ClearTransports() *CredentialUpdate {
+	cu.mutation.ClearTransports()
+	return cu
+}
+
+// SetAaguid sets the "aaguid" field.
+func (cu *CredentialUpdate) SetAaguid(b []byte) *CredentialUpdate {
+	cu.mutation.SetAaguid(b)
+	return cu
+}
+
+// ClearAaguid clears the value of the "aaguid" field.
+func (cu *CredentialUpdate) ClearAaguid() *CredentialUpdate {
+	cu.mutation.ClearAaguid()
+	return cu
+}
+
+// SetUserID sets the "user" edge to the User entity by ID.
+func (cu *CredentialUpdate) SetUserID(id int) *CredentialUpdate {
+	cu.mutation.SetUserID(id)
+	return cu
+}
+
+// SetUser sets the "user" edge to the User entity.
+func (cu *CredentialUpdate) SetUser(u *User) *CredentialUpdate {
+	return cu.SetUserID(u.ID)
+}
+
+// Mutation returns the CredentialMutation object of the builder.
+func (cu *CredentialUpdate) Mutation() *CredentialMutation {
+	return cu.mutation
+}
+
+// ClearUser clears the "user" edge to the User entity.
+func (cu *CredentialUpdate) ClearUser() *CredentialUpdate {
+	cu.mutation.ClearUser()
+	return cu
+}
+
+// Save executes the query and returns the number of nodes affected by the update operation.
+func (cu *CredentialUpdate) Save(ctx context.Context) (int, error) {
+	return withHooks(ctx, cu.sqlSave, cu.mutation, cu.hooks)
+}
+
+// SaveX is like Save, but panics if an error occurs.
+func (cu *CredentialUpdate) SaveX(ctx context.Context) int {
+	affected, err := cu.Save(ctx)
+	if err != nil {
+		panic(err)
+	}
+	return affected
+}
+
+// Exec executes the query.
+func (cu *CredentialUpdate) Exec(ctx context.Context) error {
+	_, err := cu.Save(ctx)
+	return err
+}
+
+// ExecX is like Exec, but panics if an error occurs.
+func (cu *CredentialUpdate) ExecX(ctx context.Context) {
+	if err := cu.Exec(ctx); err != nil {
+		panic(err)
+	}
+}
+
+// check runs all checks and user-defined validators on the builder.
+func (cu *CredentialUpdate) check() error {
+	if v, ok := cu.mutation.CredentialID(); ok {
+		if err := credential.CredentialIDValidator(v); err != nil {
+			return &ValidationError{Name: "credential_id", err: fmt.Errorf(`ent: validator failed for field "Credential.credential_id": %w`, err)}
+		}
+	}
+	if v, ok := cu.mutation.PublicKey(); ok {
+		if err := credential.PublicKeyValidator(v); err != nil {
+			return &ValidationError{Name: "public_key", err: fmt.Errorf(`ent: validator failed for field "Credential.public_key": %w`, err)}
+		}
+	}
+	if cu.mutation.UserCleared() && len(cu.mutation.UserIDs()) > 0 {
+		return errors.New(`ent: clearing a required unique edge "Credential.user"`)
+	}
+	return nil
+}
+
+func (cu *CredentialUpdate) sqlSave(ctx context.Context) (n int, err error) {
+	if err := cu.check(); err != nil {
+		return n, err
+	}
+	_spec := sqlgraph.NewUpdateSpec(credential.Table, credential.Columns, sqlgraph.NewFieldSpec(credential.FieldID, field.TypeInt))
+	if ps := cu.mutation.predicates; len(ps) > 0 {
+		_spec.Predicate = func(selector *sql.Selector) {
+			for i := range ps {
+				ps[i](selector)
+			}
+		}
+	}
+	if value, ok := cu.mutation.CredentialID(); ok {
+		_spec.SetField(credential.FieldCredentialID, field.TypeBytes, value)
+	}
+	if value, ok := cu.mutation.PublicKey(); ok {
+		_spec.SetField(credential.FieldPublicKey, field.TypeBytes, value)
+	}
+	if value, ok := cu.mutation.SignCount(); ok {
+		_spec.SetField(credential.FieldSignCount, field.TypeUint32, value)
+	}
+	if value, ok := cu.mutation.AddedSignCount(); ok {
+		_spec.AddField(credential.FieldSignCount, field.TypeUint32, value)
+	}
+	if value, ok := cu.mutation.Transports(); ok {
+		_spec.SetField(credential.FieldTransports, field.TypeJSON, value)
+	}
+	if value, ok := cu.mutation.AppendedTransports(); ok {
+		_spec.AddModifier(func(u *sql.UpdateBuilder) {
+			sqljson.Append(u, credential.FieldTransports, value)
+		})
+	}
+	if cu.mutation.TransportsCleared() {
+		_spec.ClearField(credential.FieldTransports, field.TypeJSON)
+	}
+	if value, ok := cu.mutation.Aaguid(); ok {
+		_spec.SetField(credential.FieldAaguid, field.TypeBytes, value)
+	}
+	if cu.mutation.AaguidCleared() {
+		_spec.ClearField(credential.FieldAaguid, field.TypeBytes)
+	}
+	if cu.mutation.UserCleared() {
+		edge := &sqlgraph.EdgeSpec{
+			Rel:     sqlgraph.M2O,
+			Inverse: true,
+			Table:   credential.UserTable,
+			Columns: []string{credential.UserColumn},
+			Bidi:    false,
+			Target: &sqlgraph.EdgeTarget{
+				IDSpec: sqlgraph.NewFieldSpec(user.FieldID, field.TypeInt),
+			},
+		}
+		_spec.Edges.Clear = append(_spec.Edges.Clear, edge)
+	}
+	if nodes := cu.mutation.UserIDs(); len(nodes) > 0 {
+		edge := &sqlgraph.EdgeSpec{
+			Rel:     sqlgraph.M2O,
+			Inverse: true,
+			Table:   credential.UserTable,
+			Columns: []string{credential.UserColumn},
+			Bidi:    false,
+			Target: &sqlgraph.EdgeTarget{
+				IDSpec: sqlgraph.NewFieldSpec(user.FieldID, field.TypeInt),
+			},
+		}
+		for _, k := range nodes {
+			edge.Target.Nodes = append(edge.Target.Nodes, k)
+		}
+		_spec.Edges.Add = append(_spec.Edges.Add, edge)
+	}
+	if n, err = sqlgraph.UpdateNodes(ctx, cu.driver, _spec); err != nil {
+		if _, ok := err.(*sqlgraph.NotFoundError); ok {
+			err = &NotFoundError{credential.Label}
+		} else if sqlgraph.IsConstraintError(err) {
+			err = &ConstraintError{msg: err.Error(), wrap: err}
+		}
+		return 0, err
+	}
+	cu.mutation.done = true
+	return n, nil
+}
+
+// CredentialUpdateOne is the builder for updating a single Credential entity.
+type CredentialUpdateOne struct {
+	config
+	fields   []string
+	hooks    []Hook
+	mutation *CredentialMutation
+}
+
+// SetCredentialID sets the "credential_id" field.
+func (cuo *CredentialUpdateOne) SetCredentialID(b []byte) *CredentialUpdateOne {
+	cuo.mutation.SetCredentialID(b)
+	return cuo
+}
+
+// SetPublicKey sets the "public_key" field.
+func (cuo *CredentialUpdateOne) SetPublicKey(b []byte) *CredentialUpdateOne {
+	cuo.mutation.SetPublicKey(b)
+	return cuo
+}
+
+// SetSignCount sets the "sign_count" field.
+func (cuo *CredentialUpdateOne) SetSignCount(u uint32) *CredentialUpdateOne {
+	cuo.mutation.ResetSignCount()
+	cuo.mutation.SetSignCount(u)
+	return cuo
+}
+
+// SetNillableSignCount sets the "sign_count" field if the given value is not nil.
+func (cuo *CredentialUpdateOne) SetNillableSignCount(u *uint32) *CredentialUpdateOne {
+	if u != nil {
+		cuo.SetSignCount(*u)
+	}
+	return cuo
+}
+
+// AddSignCount adds u to the "sign_count" field.
+func (cuo *CredentialUpdateOne) AddSignCount(u int32) *CredentialUpdateOne {
+	cuo.mutation.AddSignCount(u)
+	return cuo
+}
+
+// SetTransports sets the "transports" field.
+func (cuo *CredentialUpdateOne) SetTransports(s []string) *CredentialUpdateOne {
+	cuo.mutation.SetTransports(s)
+	return cuo
+}
+
+// AppendTransports appends s to the "transports" field.
+func (cuo *CredentialUpdateOne) AppendTransports(s []string) *CredentialUpdateOne {
+	cuo.mutation.AppendTransports(s)
+	return cuo
+}
+
+// ClearTransports clears the value of the "transports" field.
+func (cuo *CredentialUpdateOne) ClearTransports() *CredentialUpdateOne {
+	cuo.mutation.ClearTransports()
+	return cuo
+}
+
+// SetAaguid sets the "aaguid" field.
+func (cuo *CredentialUpdateOne) SetAaguid(b []byte) *CredentialUpdateOne {
+	cuo.mutation.SetAaguid(b)
+	return cuo
+}
+
+// ClearAaguid clears the value of the "aaguid" field.
+func (cuo *CredentialUpdateOne) ClearAaguid() *CredentialUpdateOne {
+	cuo.mutation.ClearAaguid()
+	return cuo
+}
+
+// SetUserID sets the "user" edge to the User entity by ID.
+func (cuo *CredentialUpdateOne) SetUserID(id int) *CredentialUpdateOne {
+	cuo.mutation.SetUserID(id)
+	return cuo
+}
+
+// SetUser sets the "user" edge to the User entity.
+func (cuo *CredentialUpdateOne) SetUser(u *User) *CredentialUpdateOne {
+	return cuo.SetUserID(u.ID)
+}
+
+// Mutation returns the CredentialMutation object of the builder.
+func (cuo *CredentialUpdateOne) Mutation() *CredentialMutation {
+	return cuo.mutation
+}
+
+// ClearUser clears the "user" edge to the User entity.
+func (cuo *CredentialUpdateOne) ClearUser() *CredentialUpdateOne {
+	cuo.mutation.ClearUser()
+	return cuo
+}
+
+// Where appends a list predicates to the CredentialUpdate builder.
+func (cuo *CredentialUpdateOne) Where(ps ...predicate.Credential) *CredentialUpdateOne {
+	cuo.mutation.Where(ps...)
+	return cuo
+}
+
+// Select allows selecting one or more fields (columns) of the returned entity.
+// The default is selecting all fields defined in the entity schema.
+func (cuo *CredentialUpdateOne) Select(field string, fields ...string) *CredentialUpdateOne {
+	cuo.fields = append([]string{field}, fields...)
+	return cuo
+}
+
+// Save executes the query and returns the updated Credential entity.
+func (cuo *CredentialUpdateOne) Save(ctx context.Context) (*Credential, error) {
+	return withHooks(ctx, cuo.sqlSave, cuo.mutation, cuo.hooks)
+}
+
+// SaveX is like Save, but panics if an error occurs.
+func (cuo *CredentialUpdateOne) SaveX(ctx context.Context) *Credential {
+	node, err := cuo.Save(ctx)
+	if err != nil {
+		panic(err)
+	}
+	return node
+}
+
+// Exec executes the query on the entity.
+func (cuo *CredentialUpdateOne) Exec(ctx context.Context) error {
+	_, err := cuo.Save(ctx)
+	return err
+}
+
+// ExecX is like Exec, but panics if an error occurs.
+func (cuo *CredentialUpdateOne) ExecX(ctx context.Context) {
+	if err := cuo.Exec(ctx); err != nil {
+		panic(err)
+	}
+}
+
+// check runs all checks and user-defined validators on the builder.
+func (cuo *CredentialUpdateOne) check() error {
+	if v, ok := cuo.mutation.CredentialID(); ok {
+		if err := credential.CredentialIDValidator(v); err != nil {
+			return &ValidationError{Name: "credential_id", err: fmt.Errorf(`ent: validator failed for field "Credential.credential_id": %w`, err)}
+		}
+	}
+	if v, ok := cuo.mutation.PublicKey(); ok {
+		if err := credential.PublicKeyValidator(v); err != nil {
+			return &ValidationError{Name: "public_key", err: fmt.Errorf(`ent: validator failed for field "Credential.public_key": %w`, err)}
+		}
+	}
+	if cuo.mutation.UserCleared() && len(cuo.mutation.UserIDs()) > 0 {
+		return errors.New(`ent: clearing a required unique edge "Credential.user"`)
+	}
+	return nil
+}
+
+func (cuo *CredentialUpdateOne) sqlSave(ctx context.Context) (_node *Credential, err error) {
+	if err := cuo.check(); err != nil {
+		return _node, err
+	}
+	_spec := sqlgraph.NewUpdateSpec(credential.Table, credential.Columns, sqlgraph.NewFieldSpec(credential.FieldID, field.TypeInt))
+	id, ok := cuo.mutation.ID()
+	if !ok {
+		return nil, &ValidationError{Name: "id", err: errors.New(`ent: missing "Credential.id" for update`)}
+	}
+	_spec.Node.ID.Value = id
+	if fields := cuo.fields; len(fields) > 0 {
+		_spec.Node.Columns = make([]string, 0, len(fields))
+		_spec.Node.Columns = append(_spec.Node.Columns, credential.FieldID)
+		for _, f := range fields {
+			if !credential.ValidColumn(f) {
+				return nil, &ValidationError{Name: f, err: fmt.Errorf("ent: invalid field %q for query", f)}
+			}
+			if f != credential.FieldID {
+				_spec.Node.Columns = append(_spec.Node.Columns, f)
+			}
+		}
+	}
+	if ps := cuo.mutation.predicates; len(ps) > 0 {
+		_spec.Predicate = func(selector *sql.Selector) {
+			for i := range ps {
+				ps[i](selector)
+			}
+		}
+	}
+	if value, ok := cuo.mutation.CredentialID(); ok {
+		_spec.SetField(credential.FieldCredentialID, field.TypeBytes, value)
+	}
+	if value, ok := cuo.mutation.PublicKey(); ok {
+		_spec.SetField(credential.FieldPublicKey, field.TypeBytes, value)
+	}
+	if value, ok := cuo.mutation.SignCount(); ok {
+		_spec.SetField(credential.FieldSignCount, field.TypeUint32, value)
+	}
+	if value, ok := cuo.mutation.AddedSignCount(); ok {
+		_spec.AddField(credential.FieldSignCount, field.TypeUint32, value)
+	}
+	if value, ok := cuo.mutation.Transports(); ok {
+		_spec.SetField(credential.FieldTransports, field.TypeJSON, value)
+	}
+	if value, ok := cuo.mutation.AppendedTransports(); ok {
+		_spec.AddModifier(func(u *sql.UpdateBuilder) {
+			sqljson.Append(u, credential.FieldTransports, value)
+		})
+	}
+	if cuo.mutation.TransportsCleared() {
+		_spec.ClearField(credential.FieldTransports, field.TypeJSON)
+	}
+	if value, ok := cuo.mutation.Aaguid(); ok {
+		_spec.SetField(credential.FieldAaguid, field.TypeBytes, value)
+	}
+	if cuo.mutation.AaguidCleared() {
+		_spec.ClearField(credential.FieldAaguid, field.TypeBytes)
+	}
+	if cuo.mutation.UserCleared() {
+		edge := &sqlgraph.EdgeSpec{
+			Rel:     sqlgraph.M2O,
+			Inverse: true,
+			Table:   credential.UserTable,
+			Columns: []string{credential.UserColumn},
+			Bidi:    false,
+			Target: &sqlgraph.EdgeTarget{
+				IDSpec: sqlgraph.NewFieldSpec(user.FieldID, field.TypeInt),
+			},
+		}
+		_spec.Edges.Clear = append(_spec.Edges.Clear, edge)
+	}
+	if nodes := cuo.mutation.UserIDs(); len(nodes) > 0 {
+		edge := &sqlgraph.EdgeSpec{
+			Rel:     sqlgraph.M2O,
+			Inverse: true,
+			Table:   credential.UserTable,
+			Columns: []string{credential.UserColumn},
+			Bidi:    false,
+			Target: &sqlgraph.EdgeTarget{
+				IDSpec: sqlgraph.NewFieldSpec(user.FieldID, field.TypeInt),
+			},
+		}
+		for _, k := range nodes {
+			edge.Target.Nodes = append(edge.Target.Nodes, k)
+		}
+		_spec.Edges.Add = append(_spec.Edges.Add, edge)
+	}
+	_node = &Credential{config: cuo.config}
+	_spec.Assign = _node.assignValues
+	_spec.ScanValues = _node.scanValues
+	if err = sqlgraph.UpdateNode(ctx, cuo.driver, _spec); err != nil {
+		if _, ok := err.(*sqlgraph.NotFoundError); ok {
+			err = &NotFoundError{credential.Label}
+		} else if sqlgraph.IsConstraintError(err) {
+			err = &ConstraintError{msg: err.Error(), wrap: err}
+		}
+		return nil, err
+	}
+	cuo.mutation.done = true
+	return _node, nil
+}
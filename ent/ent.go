@@ -12,9 +12,19 @@ import (
 	"entgo.io/ent"
 	"entgo.io/ent/dialect/sql"
 	"entgo.io/ent/dialect/sql/sqlgraph"
+	"github.com/smxlong/users/ent/credential"
+	"github.com/smxlong/users/ent/identity"
+	"github.com/smxlong/users/ent/lockout"
+	"github.com/smxlong/users/ent/mfa"
+	"github.com/smxlong/users/ent/onetimetoken"
 	"github.com/smxlong/users/ent/permission"
+	"github.com/smxlong/users/ent/recoverycode"
+	"github.com/smxlong/users/ent/relationtuple"
 	"github.com/smxlong/users/ent/role"
+	"github.com/smxlong/users/ent/roleassignment"
 	"github.com/smxlong/users/ent/user"
+	"github.com/smxlong/users/ent/userattribute"
+	"github.com/smxlong/users/ent/webauthnchallenge"
 )
 
 // ent aliases to avoid import conflicts in user's code.
@@ -75,9 +85,19 @@ var (
 func checkColumn(table, column string) error {
 	initCheck.Do(func() {
 		columnCheck = sql.NewColumnCheck(map[string]func(string) bool{
-			permission.Table: permission.ValidColumn,
-			role.Table:       role.ValidColumn,
-			user.Table:       user.ValidColumn,
+			credential.Table:        credential.ValidColumn,
+			identity.Table:          identity.ValidColumn,
+			lockout.Table:           lockout.ValidColumn,
+			mfa.Table:               mfa.ValidColumn,
+			onetimetoken.Table:      onetimetoken.ValidColumn,
+			permission.Table:        permission.ValidColumn,
+			recoverycode.Table:      recoverycode.ValidColumn,
+			relationtuple.Table:     relationtuple.ValidColumn,
+			role.Table:              role.ValidColumn,
+			roleassignment.Table:    roleassignment.ValidColumn,
+			user.Table:              user.ValidColumn,
+			userattribute.Table:     userattribute.ValidColumn,
+			webauthnchallenge.Table: webauthnchallenge.ValidColumn,
 		})
 	})
 	return columnCheck(table, column)
@@ -0,0 +1,164 @@
+// Code generated by ent, DO NOT EDIT.
+
+package ent
+
+import (
+	"fmt"
+	"strings"
+
+	"entgo.io/ent"
+	"entgo.io/ent/dialect/sql"
+	"github.com/smxlong/users/ent/identity"
+	"github.com/smxlong/users/ent/user"
+)
+
+// Identity is the model entity for the Identity schema.
+type Identity struct {
+	config `json:"-"`
+	// ID of the ent.
+	ID int `json:"id,omitempty"`
+	// Provider holds the value of the "provider" field.
+	Provider string `json:"provider,omitempty"`
+	// Subject holds the value of the "subject" field.
+	Subject string `json:"subject,omitempty"`
+	// Email holds the value of the "email" field.
+	Email string `json:"email,omitempty"`
+	// Edges holds the relations/edges for other nodes in the graph.
+	// The values are being populated by the IdentityQuery when eager-loading is set.
+	Edges           IdentityEdges `json:"edges"`
+	user_identities *int
+	selectValues    sql.SelectValues
+}
+
+// IdentityEdges holds the relations/edges for other nodes in the graph.
+type IdentityEdges struct {
+	// User holds the value of the user edge.
+	User *User `json:"user,omitempty"`
+	// loadedTypes holds the information for reporting if a
+	// type was loaded (or requested) in eager-loading or not.
+	loadedTypes [1]bool
+}
+
+// UserOrErr returns the User value or an error if the edge
+// was not loaded in eager-loading, or loaded but was not found.
+func (e IdentityEdges) UserOrErr() (*User, error) {
+	if e.User != nil {
+		return e.User, nil
+	} else if e.loadedTypes[0] {
+		return nil, &NotFoundError{label: user.Label}
+	}
+	return nil, &NotLoadedError{edge: "user"}
+}
+
+// scanValues returns the types for scanning values from sql.Rows.
+func (*Identity) scanValues(columns []string) ([]any, error) {
+	values := make([]any, len(columns))
+	for i := range columns {
+		switch columns[i] {
+		case identity.FieldID:
+			values[i] = new(sql.NullInt64)
+		case identity.FieldProvider, identity.FieldSubject, identity.FieldEmail:
+			values[i] = new(sql.NullString)
+		case identity.ForeignKeys[0]: // user_identities
+			values[i] = new(sql.NullInt64)
+		default:
+			values[i] = new(sql.UnknownType)
+		}
+	}
+	return values, nil
+}
+
+// assignValues assigns the values that were returned from sql.Rows (after scanning)
+// to the Identity fields.
+func (i *Identity) assignValues(columns []string, values []any) error {
+	if m, n := len(values), len(columns); m < n {
+		return fmt.Errorf("mismatch number of scan values: %d != %d", m, n)
+	}
+	for j := range columns {
+		switch columns[j] {
+		case identity.FieldID:
+			value, ok := values[j].(*sql.NullInt64)
+			if !ok {
+				return fmt.Errorf("unexpected type %T for field id", value)
+			}
+			i.ID = int(value.Int64)
+		case identity.FieldProvider:
+			if value, ok := values[j].(*sql.NullString); !ok {
+				return fmt.Errorf("unexpected type %T for field provider", values[j])
+			} else if value.Valid {
+				i.Provider = value.String
+			}
+		case identity.FieldSubject:
+			if value, ok := values[j].(*sql.NullString); !ok {
+				return fmt.Errorf("unexpected type %T for field subject", values[j])
+			} else if value.Valid {
+				i.Subject = value.String
+			}
+		case identity.FieldEmail:
+			if value, ok := values[j].(*sql.NullString); !ok {
+				return fmt.Errorf("unexpected type %T for field email", values[j])
+			} else if value.Valid {
+				i.Email = value.String
+			}
+		case identity.ForeignKeys[0]:
+			if value, ok := values[j].(*sql.NullInt64); !ok {
+				return fmt.Errorf("unexpected type %T for edge-field user_identities", value)
+			} else if value.Valid {
+				i.user_identities = new(int)
+				*i.user_identities = int(value.Int64)
+			}
+		default:
+			i.selectValues.Set(columns[j], values[j])
+		}
+	}
+	return nil
+}
+
+// Value returns the ent.Value that was dynamically selected and assigned to the Identity.
+// This includes values selected through modifiers, order, etc.
+func (i *Identity) Value(name string) (ent.Value, error) {
+	return i.selectValues.Get(name)
+}
+
+// QueryUser queries the "user" edge of the Identity entity.
+func (i *Identity) QueryUser() *UserQuery {
+	return NewIdentityClient(i.config).QueryUser(i)
+}
+
+// Update returns a builder for updating this Identity.
+// Note that you need to call Identity.Unwrap() before calling this method if this Identity
+// was returned from a transaction, and the transaction was committed or rolled back.
+func (i *Identity) Update() *IdentityUpdateOne {
+	return NewIdentityClient(i.config).UpdateOne(i)
+}
+
+// Unwrap unwraps the Identity entity that was returned from a transaction after it was closed,
+// so that all future queries will be executed through the driver which created the transaction.
+func (i *Identity) Unwrap() *Identity {
+	_tx, ok := i.config.driver.(*txDriver)
+	if !ok {
+		panic("ent: Identity is not a transactional entity")
+	}
+	i.config.driver = _tx.drv
+	return i
+}
+
+// String implements the fmt.Stringer.
+func (i *Identity) String() string {
+	var builder strings.Builder
+	builder.WriteString("Identity(")
+	builder.WriteString(fmt.Sprintf("id=%v, ", i.ID))
+	builder.WriteString("provider=")
+	builder.WriteString(i.Provider)
+	builder.WriteString(", ")
+	builder.WriteString("subject=")
+	builder.WriteString(i.Subject)
+	builder.WriteString(", ")
+	builder.WriteString("email=")
+	builder.WriteString(i.Email)
+	builder.WriteByte(')')
+	return builder.String()
+}
+
+// Identities is a parsable slice of Identity.
+type Identities []*Identity
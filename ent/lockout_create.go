@@ -0,0 +1,251 @@
+// Code generated by ent, DO NOT EDIT.
+
+package ent
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"entgo.io/ent/dialect/sql/sqlgraph"
+	"entgo.io/ent/schema/field"
+	"github.com/smxlong/users/ent/lockout"
+)
+
+// LockoutCreate is the builder for creating a Lockout entity.
+type LockoutCreate struct {
+	config
+	mutation *LockoutMutation
+	hooks    []Hook
+}
+
+// SetKey sets the "key" field.
+func (lc *LockoutCreate) SetKey(s string) *LockoutCreate {
+	lc.mutation.SetKey(s)
+	return lc
+}
+
+// SetFailures sets the "failures" field.
+func (lc *LockoutCreate) SetFailures(i int) *LockoutCreate {
+	lc.mutation.SetFailures(i)
+	return lc
+}
+
+// SetNillableFailures sets the "failures" field if the given value is not nil.
+func (lc *LockoutCreate) SetNillableFailures(i *int) *LockoutCreate {
+	if i != nil {
+		lc.SetFailures(*i)
+	}
+	return lc
+}
+
+// SetLastFailureAt sets the "last_failure_at" field.
+func (lc *LockoutCreate) SetLastFailureAt(t time.Time) *LockoutCreate {
+	lc.mutation.SetLastFailureAt(t)
+	return lc
+}
+
+// SetLockedUntil sets the "locked_until" field.
+func (lc *LockoutCreate) SetLockedUntil(t time.Time) *LockoutCreate {
+	lc.mutation.SetLockedUntil(t)
+	return lc
+}
+
+// SetNillableLockedUntil sets the "locked_until" field if the given value is not nil.
+func (lc *LockoutCreate) SetNillableLockedUntil(t *time.Time) *LockoutCreate {
+	if t != nil {
+		lc.SetLockedUntil(*t)
+	}
+	return lc
+}
+
+// Mutation returns the LockoutMutation object of the builder.
+func (lc *LockoutCreate) Mutation() *LockoutMutation {
+	return lc.mutation
+}
+
+// Save creates the Lockout in the database.
+func (lc *LockoutCreate) Save(ctx context.Context) (*Lockout, error) {
+	lc.defaults()
+	return withHooks(ctx, lc.sqlSave, lc.mutation, lc.hooks)
+}
+
+// SaveX calls Save and panics if Save returns an error.
+func (lc *LockoutCreate) SaveX(ctx context.Context) *Lockout {
+	v, err := lc.Save(ctx)
+	if err != nil {
+		panic(err)
+	}
+	return v
+}
+
+// Exec executes the query.
+func (lc *LockoutCreate) Exec(ctx context.Context) error {
+	_, err := lc.Save(ctx)
+	return err
+}
+
+// ExecX is like Exec, but panics if an error occurs.
+func (lc *LockoutCreate) ExecX(ctx context.Context) {
+	if err := lc.Exec(ctx); err != nil {
+		panic(err)
+	}
+}
+
+// defaults sets the default values of the builder before save.
+func (lc *LockoutCreate) defaults() {
+	if _, ok := lc.mutation.Failures(); !ok {
+		v := lockout.DefaultFailures
+		lc.mutation.SetFailures(v)
+	}
+}
+
+// check runs all checks and user-defined validators on the builder.
+func (lc *LockoutCreate) check() error {
+	if _, ok := lc.mutation.Key(); !ok {
+		return &ValidationError{Name: "key", err: errors.New(`ent: missing required field "Lockout.key"`)}
+	}
+	if v, ok := lc.mutation.Key(); ok {
+		if err := lockout.KeyValidator(v); err != nil {
+			return &ValidationError{Name: "key", err: fmt.Errorf(`ent: validator failed for field "Lockout.key": %w`, err)}
+		}
+	}
+	if _, ok := lc.mutation.Failures(); !ok {
+		return &ValidationError{Name: "failures", err: errors.New(`ent: missing required field "Lockout.failures"`)}
+	}
+	if _, ok := lc.mutation.LastFailureAt(); !ok {
+		return &ValidationError{Name: "last_failure_at", err: errors.New(`ent: missing required field "Lockout.last_failure_at"`)}
+	}
+	return nil
+}
+
+func (lc *LockoutCreate) sqlSave(ctx context.Context) (*Lockout, error) {
+	if err := lc.check(); err != nil {
+		return nil, err
+	}
+	_node, _spec := lc.createSpec()
+	if err := sqlgraph.CreateNode(ctx, lc.driver, _spec); err != nil {
+		if sqlgraph.IsConstraintError(err) {
+			err = &ConstraintError{msg: err.Error(), wrap: err}
+		}
+		return nil, err
+	}
+	id := _spec.ID.Value.(int64)
+	_node.ID = int(id)
+	lc.mutation.id = &_node.ID
+	lc.mutation.done = true
+	return _node, nil
+}
+
+func (lc *LockoutCreate) createSpec() (*Lockout, *sqlgraph.CreateSpec) {
+	var (
+		_node = &Lockout{config: lc.config}
+		_spec = sqlgraph.NewCreateSpec(lockout.Table, sqlgraph.NewFieldSpec(lockout.FieldID, field.TypeInt))
+	)
+	if value, ok := lc.mutation.Key(); ok {
+		_spec.SetField(lockout.FieldKey, field.TypeString, value)
+		_node.Key = value
+	}
+	if value, ok := lc.mutation.Failures(); ok {
+		_spec.SetField(lockout.FieldFailures, field.TypeInt, value)
+		_node.Failures = value
+	}
+	if value, ok := lc.mutation.LastFailureAt(); ok {
+		_spec.SetField(lockout.FieldLastFailureAt, field.TypeTime, value)
+		_node.LastFailureAt = value
+	}
+	if value, ok := lc.mutation.LockedUntil(); ok {
+		_spec.SetField(lockout.FieldLockedUntil, field.TypeTime, value)
+		_node.LockedUntil = &value
+	}
+	return _node, _spec
+}
+
+// LockoutCreateBulk is the builder for creating many Lockout entities in bulk.
+type LockoutCreateBulk struct {
+	config
+	err      error
+	builders []*LockoutCreate
+}
+
+// Save creates the Lockout entities in the database.
+func (lcb *LockoutCreateBulk) Save(ctx context.Context) ([]*Lockout, error) {
+	if lcb.err != nil {
+		return nil, lcb.err
+	}
+	specs := make([]*sqlgraph.CreateSpec, len(lcb.builders))
+	nodes := make([]*Lockout, len(lcb.builders))
+	mutators := make([]Mutator, len(lcb.builders))
+	for i := range lcb.builders {
+		func(i int, root context.Context) {
+			builder := lcb.builders[i]
+			builder.defaults()
+			var mut Mutator = MutateFunc(func(ctx context.Context, m Mutation) (Value, error) {
+				mutation, ok := m.(*LockoutMutation)
+				if !ok {
+					return nil, fmt.Errorf("unexpected mutation type %T", m)
+				}
+				if err := builder.check(); err != nil {
+					return nil, err
+				}
+				builder.mutation = mutation
+				var err error
+				nodes[i], specs[i] = builder.createSpec()
+				if i < len(mutators)-1 {
+					_, err = mutators[i+1].Mutate(root, lcb.builders[i+1].mutation)
+				} else {
+					spec := &sqlgraph.BatchCreateSpec{Nodes: specs}
+					// Invoke the actual operation on the latest mutation in the chain.
+					if err = sqlgraph.BatchCreate(ctx, lcb.driver, spec); err != nil {
+						if sqlgraph.IsConstraintError(err) {
+							err = &ConstraintError{msg: err.Error(), wrap: err}
+						}
+					}
+				}
+				if err != nil {
+					return nil, err
+				}
+				mutation.id = &nodes[i].ID
+				if specs[i].ID.Value != nil {
+					id := specs[i].ID.Value.(int64)
+					nodes[i].ID = int(id)
+				}
+				mutation.done = true
+				return nodes[i], nil
+			})
+			for i := len(builder.hooks) - 1; i >= 0; i-- {
+				mut = builder.hooks[i](mut)
+			}
+			mutators[i] = mut
+		}(i, ctx)
+	}
+	if len(mutators) > 0 {
+		if _, err := mutators[0].Mutate(ctx, lcb.builders[0].mutation); err != nil {
+			return nil, err
+		}
+	}
+	return nodes, nil
+}
+
+// SaveX is like Save, but panics if an error occurs.
+func (lcb *LockoutCreateBulk) SaveX(ctx context.Context) []*Lockout {
+	v, err := lcb.Save(ctx)
+	if err != nil {
+		panic(err)
+	}
+	return v
+}
+
+// Exec executes the query.
+func (lcb *LockoutCreateBulk) Exec(ctx context.Context) error {
+	_, err := lcb.Save(ctx)
+	return err
+}
+
+// ExecX is like Exec, but panics if an error occurs.
+func (lcb *LockoutCreateBulk) ExecX(ctx context.Context) {
+	if err := lcb.Exec(ctx); err != nil {
+		panic(err)
+	}
+}
@@ -0,0 +1,376 @@
+// Code generated by ent, DO NOT EDIT.
+
+package ent
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"entgo.io/ent/dialect/sql"
+	"entgo.io/ent/dialect/sql/sqlgraph"
+	"entgo.io/ent/schema/field"
+	"github.com/smxlong/users/ent/lockout"
+	"github.com/smxlong/users/ent/predicate"
+)
+
+// LockoutUpdate is the builder for updating Lockout entities.
+type LockoutUpdate struct {
+	config
+	hooks    []Hook
+	mutation *LockoutMutation
+}
+
+// Where appends a list predicates to the LockoutUpdate builder.
+func (lu *LockoutUpdate) Where(ps ...predicate.Lockout) *LockoutUpdate {
+	lu.mutation.Where(ps...)
+	return lu
+}
+
+// SetKey sets the "key" field.
+func (lu *LockoutUpdate) SetKey(s string) *LockoutUpdate {
+	lu.mutation.SetKey(s)
+	return lu
+}
+
+// SetNillableKey sets the "key" field if the given value is not nil.
+func (lu *LockoutUpdate) SetNillableKey(s *string) *LockoutUpdate {
+	if s != nil {
+		lu.SetKey(*s)
+	}
+	return lu
+}
+
+// SetFailures sets the "failures" field.
+func (lu *LockoutUpdate) SetFailures(i int) *LockoutUpdate {
+	lu.mutation.ResetFailures()
+	lu.mutation.SetFailures(i)
+	return lu
+}
+
+// SetNillableFailures sets the "failures" field if the given value is not nil.
+func (lu *LockoutUpdate) SetNillableFailures(i *int) *LockoutUpdate {
+	if i != nil {
+		lu.SetFailures(*i)
+	}
+	return lu
+}
+
+// AddFailures adds i to the "failures" field.
+func (lu *LockoutUpdate) AddFailures(i int) *LockoutUpdate {
+	lu.mutation.AddFailures(i)
+	return lu
+}
+
+// SetLastFailureAt sets the "last_failure_at" field.
+func (lu *LockoutUpdate) SetLastFailureAt(t time.Time) *LockoutUpdate {
+	lu.mutation.SetLastFailureAt(t)
+	return lu
+}
+
+// SetNillableLastFailureAt sets the "last_failure_at" field if the given value is not nil.
+func (lu *LockoutUpdate) SetNillableLastFailureAt(t *time.Time) *LockoutUpdate {
+	if t != nil {
+		lu.SetLastFailureAt(*t)
+	}
+	return lu
+}
+
+// SetLockedUntil sets the "locked_until" field.
+func (lu *LockoutUpdate) SetLockedUntil(t time.Time) *LockoutUpdate {
+	lu.mutation.SetLockedUntil(t)
+	return lu
+}
+
+// SetNillableLockedUntil sets the "locked_until" field if the given value is not nil.
+func (lu *LockoutUpdate) SetNillableLockedUntil(t *time.Time) *LockoutUpdate {
+	if t != nil {
+		lu.SetLockedUntil(*t)
+	}
+	return lu
+}
+
+// ClearLockedUntil clears the value of the "locked_until" field.
+func (lu *LockoutUpdate) ClearLockedUntil() *LockoutUpdate {
+	lu.mutation.ClearLockedUntil()
+	return lu
+}
+
+// Mutation returns the LockoutMutation object of the builder.
+func (lu *LockoutUpdate) Mutation() *LockoutMutation {
+	return lu.mutation
+}
+
+// Save executes the query and returns the number of nodes affected by the update operation.
+func (lu *LockoutUpdate) Save(ctx context.Context) (int, error) {
+	return withHooks(ctx, lu.sqlSave, lu.mutation, lu.hooks)
+}
+
+// SaveX is like Save, but panics if an error occurs.
+func (lu *LockoutUpdate) SaveX(ctx context.Context) int {
+	affected, err := lu.Save(ctx)
+	if err != nil {
+		panic(err)
+	}
+	return affected
+}
+
+// Exec executes the query.
+func (lu *LockoutUpdate) Exec(ctx context.Context) error {
+	_, err := lu.Save(ctx)
+	return err
+}
+
+// ExecX is like Exec, but panics if an error occurs.
+func (lu *LockoutUpdate) ExecX(ctx context.Context) {
+	if err := lu.Exec(ctx); err != nil {
+		panic(err)
+	}
+}
+
+// check runs all checks and user-defined validators on the builder.
+func (lu *LockoutUpdate) check() error {
+	if v, ok := lu.mutation.Key(); ok {
+		if err := lockout.KeyValidator(v); err != nil {
+			return &ValidationError{Name: "key", err: fmt.Errorf(`ent: validator failed for field "Lockout.key": %w`, err)}
+		}
+	}
+	return nil
+}
+
+func (lu *LockoutUpdate) sqlSave(ctx context.Context) (n int, err error) {
+	if err := lu.check(); err != nil {
+		return n, err
+	}
+	_spec := sqlgraph.NewUpdateSpec(lockout.Table, lockout.Columns, sqlgraph.NewFieldSpec(lockout.FieldID, field.TypeInt))
+	if ps := lu.mutation.predicates; len(ps) > 0 {
+		_spec.Predicate = func(selector *sql.Selector) {
+			for i := range ps {
+				ps[i](selector)
+			}
+		}
+	}
+	if value, ok := lu.mutation.Key(); ok {
+		_spec.SetField(lockout.FieldKey, field.TypeString, value)
+	}
+	if value, ok := lu.mutation.Failures(); ok {
+		_spec.SetField(lockout.FieldFailures, field.TypeInt, value)
+	}
+	if value, ok := lu.mutation.AddedFailures(); ok {
+		_spec.AddField(lockout.FieldFailures, field.TypeInt, value)
+	}
+	if value, ok := lu.mutation.LastFailureAt(); ok {
+		_spec.SetField(lockout.FieldLastFailureAt, field.TypeTime, value)
+	}
+	if value, ok := lu.mutation.LockedUntil(); ok {
+		_spec.SetField(lockout.FieldLockedUntil, field.TypeTime, value)
+	}
+	if lu.mutation.LockedUntilCleared() {
+		_spec.ClearField(lockout.FieldLockedUntil, field.TypeTime)
+	}
+	if n, err = sqlgraph.UpdateNodes(ctx, lu.driver, _spec); err != nil {
+		if _, ok := err.(*sqlgraph.NotFoundError); ok {
+			err = &NotFoundError{lockout.Label}
+		} else if sqlgraph.IsConstraintError(err) {
+			err = &ConstraintError{msg: err.Error(), wrap: err}
+		}
+		return 0, err
+	}
+	lu.mutation.done = true
+	return n, nil
+}
+
+// LockoutUpdateOne is the builder for updating a single Lockout entity.
+type LockoutUpdateOne struct {
+	config
+	fields   []string
+	hooks    []Hook
+	mutation *LockoutMutation
+}
+
+// SetKey sets the "key" field.
+func (luo *LockoutUpdateOne) SetKey(s string) *LockoutUpdateOne {
+	luo.mutation.SetKey(s)
+	return luo
+}
+
+// SetNillableKey sets the "key" field if the given value is not nil.
+func (luo *LockoutUpdateOne) SetNillableKey(s *string) *LockoutUpdateOne {
+	if s != nil {
+		luo.SetKey(*s)
+	}
+	return luo
+}
+
+// SetFailures sets the "failures" field.
+func (luo *LockoutUpdateOne) SetFailures(i int) *LockoutUpdateOne {
+	luo.mutation.ResetFailures()
+	luo.mutation.SetFailures(i)
+	return luo
+}
+
+// SetNillableFailures sets the "failures" field if the given value is not nil.
+func (luo *LockoutUpdateOne) SetNillableFailures(i *int) *LockoutUpdateOne {
+	if i != nil {
+		luo.SetFailures(*i)
+	}
+	return luo
+}
+
+// AddFailures adds i to the "failures" field.
+func (luo *LockoutUpdateOne) AddFailures(i int) *LockoutUpdateOne {
+	luo.mutation.AddFailures(i)
+	return luo
+}
+
+// SetLastFailureAt sets the "last_failure_at" field.
+func (luo *LockoutUpdateOne) SetLastFailureAt(t time.Time) *LockoutUpdateOne {
+	luo.mutation.SetLastFailureAt(t)
+	return luo
+}
+
+// SetNillableLastFailureAt sets the "last_failure_at" field if the given value is not nil.
+func (luo *LockoutUpdateOne) SetNillableLastFailureAt(t *time.Time) *LockoutUpdateOne {
+	if t != nil {
+		luo.SetLastFailureAt(*t)
+	}
+	return luo
+}
+
+// SetLockedUntil sets the "locked_until" field.
+func (luo *LockoutUpdateOne) SetLockedUntil(t time.Time) *LockoutUpdateOne {
+	luo.mutation.SetLockedUntil(t)
+	return luo
+}
+
+// SetNillableLockedUntil sets the "locked_until" field if the given value is not nil.
+func (luo *LockoutUpdateOne) SetNillableLockedUntil(t *time.Time) *LockoutUpdateOne {
+	if t != nil {
+		luo.SetLockedUntil(*t)
+	}
+	return luo
+}
+
+// ClearLockedUntil clears the value of the "locked_until" field.
+func (luo *LockoutUpdateOne) ClearLockedUntil() *LockoutUpdateOne {
+	luo.mutation.ClearLockedUntil()
+	return luo
+}
+
+// Mutation returns the LockoutMutation object of the builder.
+func (luo *LockoutUpdateOne) Mutation() *LockoutMutation {
+	return luo.mutation
+}
+
+// Where appends a list predicates to the LockoutUpdate builder.
+func (luo *LockoutUpdateOne) Where(ps ...predicate.Lockout) *LockoutUpdateOne {
+	luo.mutation.Where(ps...)
+	return luo
+}
+
+// Select allows selecting one or more fields (columns) of the returned entity.
+// The default is selecting all fields defined in the entity schema.
+func (luo *LockoutUpdateOne) Select(field string, fields ...string) *LockoutUpdateOne {
+	luo.fields = append([]string{field}, fields...)
+	return luo
+}
+
+// Save executes the query and returns the updated Lockout entity.
+func (luo *LockoutUpdateOne) Save(ctx context.Context) (*Lockout, error) {
+	return withHooks(ctx, luo.sqlSave, luo.mutation, luo.hooks)
+}
+
+// SaveX is like Save, but panics if an error occurs.
+func (luo *LockoutUpdateOne) SaveX(ctx context.Context) *Lockout {
+	node, err := luo.Save(ctx)
+	if err != nil {
+		panic(err)
+	}
+	return node
+}
+
+// Exec executes the query on the entity.
+func (luo *LockoutUpdateOne) Exec(ctx context.Context) error {
+	_, err := luo.Save(ctx)
+	return err
+}
+
+// ExecX is like Exec, but panics if an error occurs.
+func (luo *LockoutUpdateOne) ExecX(ctx context.Context) {
+	if err := luo.Exec(ctx); err != nil {
+		panic(err)
+	}
+}
+
+// check runs all checks and user-defined validators on the builder.
+func (luo *LockoutUpdateOne) check() error {
+	if v, ok := luo.mutation.Key(); ok {
+		if err := lockout.KeyValidator(v); err != nil {
+			return &ValidationError{Name: "key", err: fmt.Errorf(`ent: validator failed for field "Lockout.key": %w`, err)}
+		}
+	}
+	return nil
+}
+
+func (luo *LockoutUpdateOne) sqlSave(ctx context.Context) (_node *Lockout, err error) {
+	if err := luo.check(); err != nil {
+		return _node, err
+	}
+	_spec := sqlgraph.NewUpdateSpec(lockout.Table, lockout.Columns, sqlgraph.NewFieldSpec(lockout.FieldID, field.TypeInt))
+	id, ok := luo.mutation.ID()
+	if !ok {
+		return nil, &ValidationError{Name: "id", err: errors.New(`ent: missing "Lockout.id" for update`)}
+	}
+	_spec.Node.ID.Value = id
+	if fields := luo.fields; len(fields) > 0 {
+		_spec.Node.Columns = make([]string, 0, len(fields))
+		_spec.Node.Columns = append(_spec.Node.Columns, lockout.FieldID)
+		for _, f := range fields {
+			if !lockout.ValidColumn(f) {
+				return nil, &ValidationError{Name: f, err: fmt.Errorf("ent: invalid field %q for query", f)}
+			}
+			if f != lockout.FieldID {
+				_spec.Node.Columns = append(_spec.Node.Columns, f)
+			}
+		}
+	}
+	if ps := luo.mutation.predicates; len(ps) > 0 {
+		_spec.Predicate = func(selector *sql.Selector) {
+			for i := range ps {
+				ps[i](selector)
+			}
+		}
+	}
+	if value, ok := luo.mutation.Key(); ok {
+		_spec.SetField(lockout.FieldKey, field.TypeString, value)
+	}
+	if value, ok := luo.mutation.Failures(); ok {
+		_spec.SetField(lockout.FieldFailures, field.TypeInt, value)
+	}
+	if value, ok := luo.mutation.AddedFailures(); ok {
+		_spec.AddField(lockout.FieldFailures, field.TypeInt, value)
+	}
+	if value, ok := luo.mutation.LastFailureAt(); ok {
+		_spec.SetField(lockout.FieldLastFailureAt, field.TypeTime, value)
+	}
+	if value, ok := luo.mutation.LockedUntil(); ok {
+		_spec.SetField(lockout.FieldLockedUntil, field.TypeTime, value)
+	}
+	if luo.mutation.LockedUntilCleared() {
+		_spec.ClearField(lockout.FieldLockedUntil, field.TypeTime)
+	}
+	_node = &Lockout{config: luo.config}
+	_spec.Assign = _node.assignValues
+	_spec.ScanValues = _node.scanValues
+	if err = sqlgraph.UpdateNode(ctx, luo.driver, _spec); err != nil {
+		if _, ok := err.(*sqlgraph.NotFoundError); ok {
+			err = &NotFoundError{lockout.Label}
+		} else if sqlgraph.IsConstraintError(err) {
+			err = &ConstraintError{msg: err.Error(), wrap: err}
+		}
+		return nil, err
+	}
+	luo.mutation.done = true
+	return _node, nil
+}
@@ -0,0 +1,165 @@
+// Code generated by ent, DO NOT EDIT.
+
+package ent
+
+import (
+	"fmt"
+	"strings"
+
+	"entgo.io/ent"
+	"entgo.io/ent/dialect/sql"
+	"github.com/smxlong/users/ent/mfa"
+	"github.com/smxlong/users/ent/user"
+)
+
+// MFA is the model entity for the MFA schema.
+type MFA struct {
+	config `json:"-"`
+	// ID of the ent.
+	ID int `json:"id,omitempty"`
+	// Secret holds the value of the "secret" field.
+	Secret []byte `json:"-"`
+	// Enabled holds the value of the "enabled" field.
+	Enabled bool `json:"enabled,omitempty"`
+	// LastStep holds the value of the "last_step" field.
+	LastStep int64 `json:"last_step,omitempty"`
+	// Edges holds the relations/edges for other nodes in the graph.
+	// The values are being populated by the MFAQuery when eager-loading is set.
+	Edges        MFAEdges `json:"edges"`
+	user_mfa     *int
+	selectValues sql.SelectValues
+}
+
+// MFAEdges holds the relations/edges for other nodes in the graph.
+type MFAEdges struct {
+	// User holds the value of the user edge.
+	User *User `json:"user,omitempty"`
+	// loadedTypes holds the information for reporting if a
+	// type was loaded (or requested) in eager-loading or not.
+	loadedTypes [1]bool
+}
+
+// UserOrErr returns the User value or an error if the edge
+// was not loaded in eager-loading, or loaded but was not found.
+func (e MFAEdges) UserOrErr() (*User, error) {
+	if e.User != nil {
+		return e.User, nil
+	} else if e.loadedTypes[0] {
+		return nil, &NotFoundError{label: user.Label}
+	}
+	return nil, &NotLoadedError{edge: "user"}
+}
+
+// scanValues returns the types for scanning values from sql.Rows.
+func (*MFA) scanValues(columns []string) ([]any, error) {
+	values := make([]any, len(columns))
+	for i := range columns {
+		switch columns[i] {
+		case mfa.FieldSecret:
+			values[i] = new([]byte)
+		case mfa.FieldEnabled:
+			values[i] = new(sql.NullBool)
+		case mfa.FieldID, mfa.FieldLastStep:
+			values[i] = new(sql.NullInt64)
+		case mfa.ForeignKeys[0]: // user_mfa
+			values[i] = new(sql.NullInt64)
+		default:
+			values[i] = new(sql.UnknownType)
+		}
+	}
+	return values, nil
+}
+
+// assignValues assigns the values that were returned from sql.Rows (after scanning)
+// to the MFA fields.
+func (m *MFA) assignValues(columns []string, values []any) error {
+	if m, n := len(values), len(columns); m < n {
+		return fmt.Errorf("mismatch number of scan values: %d != %d", m, n)
+	}
+	for i := range columns {
+		switch columns[i] {
+		case mfa.FieldID:
+			value, ok := values[i].(*sql.NullInt64)
+			if !ok {
+				return fmt.Errorf("unexpected type %T for field id", value)
+			}
+			m.ID = int(value.Int64)
+		case mfa.FieldSecret:
+			if value, ok := values[i].(*[]byte); !ok {
+				return fmt.Errorf("unexpected type %T for field secret", values[i])
+			} else if value != nil {
+				m.Secret = *value
+			}
+		case mfa.FieldEnabled:
+			if value, ok := values[i].(*sql.NullBool); !ok {
+				return fmt.Errorf("unexpected type %T for field enabled", values[i])
+			} else if value.Valid {
+				m.Enabled = value.Bool
+			}
+		case mfa.FieldLastStep:
+			if value, ok := values[i].(*sql.NullInt64); !ok {
+				return fmt.Errorf("unexpected type %T for field last_step", values[i])
+			} else if value.Valid {
+				m.LastStep = value.Int64
+			}
+		case mfa.ForeignKeys[0]:
+			if value, ok := values[i].(*sql.NullInt64); !ok {
+				return fmt.Errorf("unexpected type %T for edge-field user_mfa", value)
+			} else if value.Valid {
+				m.user_mfa = new(int)
+				*m.user_mfa = int(value.Int64)
+			}
+		default:
+			m.selectValues.Set(columns[i], values[i])
+		}
+	}
+	return nil
+}
+
+// Value returns the ent.Value that was dynamically selected and assigned to the MFA.
+// This includes values selected through modifiers, order, etc.
+func (m *MFA) Value(name string) (ent.Value, error) {
+	return m.selectValues.Get(name)
+}
+
+// QueryUser queries the "user" edge of the MFA entity.
+func (m *MFA) QueryUser() *UserQuery {
+	return NewMFAClient(m.config).QueryUser(m)
+}
+
+// Update returns a builder for updating this MFA.
+// Note that you need to call MFA.Unwrap() before calling this method if this MFA
+// was returned from a transaction, and the transaction was committed or rolled back.
+func (m *MFA) Update() *MFAUpdateOne {
+	return NewMFAClient(m.config).UpdateOne(m)
+}
+
+// Unwrap unwraps the MFA entity that was returned from a transaction after it was closed,
+// so that all future queries will be executed through the driver which created the transaction.
+func (m *MFA) Unwrap() *MFA {
+	_tx, ok := m.config.driver.(*txDriver)
+	if !ok {
+		panic("ent: MFA is not a transactional entity")
+	}
+	m.config.driver = _tx.drv
+	return m
+}
+
+// String implements the fmt.Stringer.
+func (m *MFA) String() string {
+	var builder strings.Builder
+	builder.WriteString("MFA(")
+	builder.WriteString(fmt.Sprintf("id=%v, ", m.ID))
+	builder.WriteString("secret=<sensitive>")
+	builder.WriteString(", ")
+	builder.WriteString("enabled=")
+	builder.WriteString(fmt.Sprintf("%v", m.Enabled))
+	builder.WriteString(", ")
+	builder.WriteString("last_step=")
+	builder.WriteString(fmt.Sprintf("%v", m.LastStep))
+	builder.WriteByte(')')
+	return builder.String()
+}
+
+// MFAs is a parsable slice of MFA.
+type MFAs []*MFA
@@ -0,0 +1,102 @@
+// Code generated by ent, DO NOT EDIT.
+
+package mfa
+
+import (
+	"entgo.io/ent/dialect/sql"
+	"entgo.io/ent/dialect/sql/sqlgraph"
+)
+
+const (
+	// Label holds the string label denoting the mfa type in the database.
+	Label = "mfa"
+	// FieldID holds the string denoting the id field in the database.
+	FieldID = "id"
+	// FieldSecret holds the string denoting the secret field in the database.
+	FieldSecret = "secret"
+	// FieldEnabled holds the string denoting the enabled field in the database.
+	FieldEnabled = "enabled"
+	// FieldLastStep holds the string denoting the last_step field in the database.
+	FieldLastStep = "last_step"
+	// EdgeUser holds the string denoting the user edge name in mutations.
+	EdgeUser = "user"
+	// Table holds the table name of the mfa in the database.
+	Table = "mfa"
+	// UserTable is the table that holds the user relation/edge.
+	UserTable = "mfa"
+	// UserInverseTable is the table name for the User entity.
+	// It exists in this package in order to avoid circular dependency with the "user" package.
+	UserInverseTable = "users"
+	// UserColumn is the table column denoting the user relation/edge.
+	UserColumn = "user_mfa"
+)
+
+// Columns holds all SQL columns for mfa fields.
+var Columns = []string{
+	FieldID,
+	FieldSecret,
+	FieldEnabled,
+	FieldLastStep,
+}
+
+// ForeignKeys holds the SQL foreign-keys that are owned by the "mfa"
+// table and are not defined as standalone fields in the schema.
+var ForeignKeys = []string{
+	"user_mfa",
+}
+
+// ValidColumn reports if the column name is valid (part of the table columns).
+func ValidColumn(column string) bool {
+	for i := range Columns {
+		if column == Columns[i] {
+			return true
+		}
+	}
+	for i := range ForeignKeys {
+		if column == ForeignKeys[i] {
+			return true
+		}
+	}
+	return false
+}
+
+var (
+	// SecretValidator is a validator for the "secret" field. It is called by the builders before save.
+	SecretValidator func([]byte) error
+	// DefaultEnabled holds the default value on creation for the "enabled" field.
+	DefaultEnabled bool
+	// DefaultLastStep holds the default value on creation for the "last_step" field.
+	DefaultLastStep int64
+)
+
+// OrderOption defines the ordering options for the MFA queries.
+type OrderOption func(*sql.Selector)
+
+// ByID orders the results by the id field.
+func ByID(opts ...sql.OrderTermOption) OrderOption {
+	return sql.OrderByField(FieldID, opts...).ToFunc()
+}
+
+// ByEnabled orders the results by the enabled field.
+func ByEnabled(opts ...sql.OrderTermOption) OrderOption {
+	return sql.OrderByField(FieldEnabled, opts...).ToFunc()
+}
+
+// ByLastStep orders the results by the last_step field.
+func ByLastStep(opts ...sql.OrderTermOption) OrderOption {
+	return sql.OrderByField(FieldLastStep, opts...).ToFunc()
+}
+
+// ByUserField orders the results by user field.
+func ByUserField(field string, opts ...sql.OrderTermOption) OrderOption {
+	return func(s *sql.Selector) {
+		sqlgraph.OrderByNeighborTerms(s, newUserStep(), sql.OrderByField(field, opts...))
+	}
+}
+func newUserStep() *sqlgraph.Step {
+	return sqlgraph.NewStep(
+		sqlgraph.From(Table, FieldID),
+		sqlgraph.To(UserInverseTable, FieldID),
+		sqlgraph.Edge(sqlgraph.O2O, true, UserTable, UserColumn),
+	)
+}
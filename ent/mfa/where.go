@@ -0,0 +1,197 @@
+// Code generated by ent, DO NOT EDIT.
+
+package mfa
+
+import (
+	"entgo.io/ent/dialect/sql"
+	"entgo.io/ent/dialect/sql/sqlgraph"
+	"github.com/smxlong/users/ent/predicate"
+)
+
+// ID filters vertices based on their ID field.
+func ID(id int) predicate.MFA {
+	return predicate.MFA(sql.FieldEQ(FieldID, id))
+}
+
+// IDEQ applies the EQ predicate on the ID field.
+func IDEQ(id int) predicate.MFA {
+	return predicate.MFA(sql.FieldEQ(FieldID, id))
+}
+
+// IDNEQ applies the NEQ predicate on the ID field.
+func IDNEQ(id int) predicate.MFA {
+	return predicate.MFA(sql.FieldNEQ(FieldID, id))
+}
+
+// IDIn applies the In predicate on the ID field.
+func IDIn(ids ...int) predicate.MFA {
+	return predicate.MFA(sql.FieldIn(FieldID, ids...))
+}
+
+// IDNotIn applies the NotIn predicate on the ID field.
+func IDNotIn(ids ...int) predicate.MFA {
+	return predicate.MFA(sql.FieldNotIn(FieldID, ids...))
+}
+
+// IDGT applies the GT predicate on the ID field.
+func IDGT(id int) predicate.MFA {
+	return predicate.MFA(sql.FieldGT(FieldID, id))
+}
+
+// IDGTE applies the GTE predicate on the ID field.
+func IDGTE(id int) predicate.MFA {
+	return predicate.MFA(sql.FieldGTE(FieldID, id))
+}
+
+// IDLT applies the LT predicate on the ID field.
+func IDLT(id int) predicate.MFA {
+	return predicate.MFA(sql.FieldLT(FieldID, id))
+}
+
+// IDLTE applies the LTE predicate on the ID field.
+func IDLTE(id int) predicate.MFA {
+	return predicate.MFA(sql.FieldLTE(FieldID, id))
+}
+
+// Secret applies equality check predicate on the "secret" field. It's identical to SecretEQ.
+func Secret(v []byte) predicate.MFA {
+	return predicate.MFA(sql.FieldEQ(FieldSecret, v))
+}
+
+// Enabled applies equality check predicate on the "enabled" field. It's identical to EnabledEQ.
+func Enabled(v bool) predicate.MFA {
+	return predicate.MFA(sql.FieldEQ(FieldEnabled, v))
+}
+
+// LastStep applies equality check predicate on the "last_step" field. It's identical to LastStepEQ.
+func LastStep(v int64) predicate.MFA {
+	return predicate.MFA(sql.FieldEQ(FieldLastStep, v))
+}
+
+// SecretEQ applies the EQ predicate on the "secret" field.
+func SecretEQ(v []byte) predicate.MFA {
+	return predicate.MFA(sql.FieldEQ(FieldSecret, v))
+}
+
+// SecretNEQ applies the NEQ predicate on the "secret" field.
+func SecretNEQ(v []byte) predicate.MFA {
+	return predicate.MFA(sql.FieldNEQ(FieldSecret, v))
+}
+
+// SecretIn applies the In predicate on the "secret" field.
+func SecretIn(vs ...[]byte) predicate.MFA {
+	return predicate.MFA(sql.FieldIn(FieldSecret, vs...))
+}
+
+// SecretNotIn applies the NotIn predicate on the "secret" field.
+func SecretNotIn(vs ...[]byte) predicate.MFA {
+	return predicate.MFA(sql.FieldNotIn(FieldSecret, vs...))
+}
+
+// SecretGT applies the GT predicate on the "secret" field.
+func SecretGT(v []byte) predicate.MFA {
+	return predicate.MFA(sql.FieldGT(FieldSecret, v))
+}
+
+// SecretGTE applies the GTE predicate on the "secret" field.
+func SecretGTE(v []byte) predicate.MFA {
+	return predicate.MFA(sql.FieldGTE(FieldSecret, v))
+}
+
+// SecretLT applies the LT predicate on the "secret" field.
+func SecretLT(v []byte) predicate.MFA {
+	return predicate.MFA(sql.FieldLT(FieldSecret, v))
+}
+
+// SecretLTE applies the LTE predicate on the "secret" field.
+func SecretLTE(v []byte) predicate.MFA {
+	return predicate.MFA(sql.FieldLTE(FieldSecret, v))
+}
+
+// EnabledEQ applies the EQ predicate on the "enabled" field.
+func EnabledEQ(v bool) predicate.MFA {
+	return predicate.MFA(sql.FieldEQ(FieldEnabled, v))
+}
+
+// EnabledNEQ applies the NEQ predicate on the "enabled" field.
+func EnabledNEQ(v bool) predicate.MFA {
+	return predicate.MFA(sql.FieldNEQ(FieldEnabled, v))
+}
+
+// LastStepEQ applies the EQ predicate on the "last_step" field.
+func LastStepEQ(v int64) predicate.MFA {
+	return predicate.MFA(sql.FieldEQ(FieldLastStep, v))
+}
+
+// LastStepNEQ applies the NEQ predicate on the "last_step" field.
+func LastStepNEQ(v int64) predicate.MFA {
+	return predicate.MFA(sql.FieldNEQ(FieldLastStep, v))
+}
+
+// LastStepIn applies the In predicate on the "last_step" field.
+func LastStepIn(vs ...int64) predicate.MFA {
+	return predicate.MFA(sql.FieldIn(FieldLastStep, vs...))
+}
+
+// LastStepNotIn applies the NotIn predicate on the "last_step" field.
+func LastStepNotIn(vs ...int64) predicate.MFA {
+	return predicate.MFA(sql.FieldNotIn(FieldLastStep, vs...))
+}
+
+// LastStepGT applies the GT predicate on the "last_step" field.
+func LastStepGT(v int64) predicate.MFA {
+	return predicate.MFA(sql.FieldGT(FieldLastStep, v))
+}
+
+// LastStepGTE applies the GTE predicate on the "last_step" field.
+func LastStepGTE(v int64) predicate.MFA {
+	return predicate.MFA(sql.FieldGTE(FieldLastStep, v))
+}
+
+// LastStepLT applies the LT predicate on the "last_step" field.
+func LastStepLT(v int64) predicate.MFA {
+	return predicate.MFA(sql.FieldLT(FieldLastStep, v))
+}
+
+// LastStepLTE applies the LTE predicate on the "last_step" field.
+func LastStepLTE(v int64) predicate.MFA {
+	return predicate.MFA(sql.FieldLTE(FieldLastStep, v))
+}
+
+// HasUser applies the HasEdge predicate on the "user" edge.
+func HasUser() predicate.MFA {
+	return predicate.MFA(func(s *sql.Selector) {
+		step := sqlgraph.NewStep(
+			sqlgraph.From(Table, FieldID),
+			sqlgraph.Edge(sqlgraph.O2O, true, UserTable, UserColumn),
+		)
+		sqlgraph.HasNeighbors(s, step)
+	})
+}
+
+// HasUserWith applies the HasEdge predicate on the "user" edge with a given conditions (other predicates).
+func HasUserWith(preds ...predicate.User) predicate.MFA {
+	return predicate.MFA(func(s *sql.Selector) {
+		step := newUserStep()
+		sqlgraph.HasNeighborsWith(s, step, func(s *sql.Selector) {
+			for _, p := range preds {
+				p(s)
+			}
+		})
+	})
+}
+
+// And groups predicates with the AND operator between them.
+func And(predicates ...predicate.MFA) predicate.MFA {
+	return predicate.MFA(sql.AndPredicates(predicates...))
+}
+
+// Or groups predicates with the OR operator between them.
+func Or(predicates ...predicate.MFA) predicate.MFA {
+	return predicate.MFA(sql.OrPredicates(predicates...))
+}
+
+// Not applies the not operator on the given predicate.
+func Not(p predicate.MFA) predicate.MFA {
+	return predicate.MFA(sql.NotPredicates(p))
+}
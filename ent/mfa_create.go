@@ -0,0 +1,276 @@
+// Code generated by ent, DO NOT EDIT.
+
+package ent
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"entgo.io/ent/dialect/sql/sqlgraph"
+	"entgo.io/ent/schema/field"
+	"github.com/smxlong/users/ent/mfa"
+	"github.com/smxlong/users/ent/user"
+)
+
+// MFACreate is the builder for creating a MFA entity.
+type MFACreate struct {
+	config
+	mutation *MFAMutation
+	hooks    []Hook
+}
+
+// SetSecret sets the "secret" field.
+func (mc *MFACreate) SetSecret(b []byte) *MFACreate {
+	mc.mutation.SetSecret(b)
+	return mc
+}
+
+// SetEnabled sets the "enabled" field.
+func (mc *MFACreate) SetEnabled(b bool) *MFACreate {
+	mc.mutation.SetEnabled(b)
+	return mc
+}
+
+// SetNillableEnabled sets the "enabled" field if the given value is not nil.
+func (mc *MFACreate) SetNillableEnabled(b *bool) *MFACreate {
+	if b != nil {
+		mc.SetEnabled(*b)
+	}
+	return mc
+}
+
+// SetLastStep sets the "last_step" field.
+func (mc *MFACreate) SetLastStep(i int64) *MFACreate {
+	mc.mutation.SetLastStep(i)
+	return mc
+}
+
+// SetNillableLastStep sets the "last_step" field if the given value is not nil.
+func (mc *MFACreate) SetNillableLastStep(i *int64) *MFACreate {
+	if i != nil {
+		mc.SetLastStep(*i)
+	}
+	return mc
+}
+
+// SetUserID sets the "user" edge to the User entity by ID.
+func (mc *MFACreate) SetUserID(id int) *MFACreate {
+	mc.mutation.SetUserID(id)
+	return mc
+}
+
+// SetUser sets the "user" edge to the User entity.
+func (mc *MFACreate) SetUser(u *User) *MFACreate {
+	return mc.SetUserID(u.ID)
+}
+
+// Mutation returns the MFAMutation object of the builder.
+func (mc *MFACreate) Mutation() *MFAMutation {
+	return mc.mutation
+}
+
+// Save creates the MFA in the database.
+func (mc *MFACreate) Save(ctx context.Context) (*MFA, error) {
+	mc.defaults()
+	return withHooks(ctx, mc.sqlSave, mc.mutation, mc.hooks)
+}
+
+// SaveX calls Save and panics if Save returns an error.
+func (mc *MFACreate) SaveX(ctx context.Context) *MFA {
+	v, err := mc.Save(ctx)
+	if err != nil {
+		panic(err)
+	}
+	return v
+}
+
+// Exec executes the query.
+func (mc *MFACreate) Exec(ctx context.Context) error {
+	_, err := mc.Save(ctx)
+	return err
+}
+
+// ExecX is like Exec, but panics if an error occurs.
+func (mc *MFACreate) ExecX(ctx context.Context) {
+	if err := mc.Exec(ctx); err != nil {
+		panic(err)
+	}
+}
+
+// defaults sets the default values of the builder before save.
+func (mc *MFACreate) defaults() {
+	if _, ok := mc.mutation.Enabled(); !ok {
+		v := mfa.DefaultEnabled
+		mc.mutation.SetEnabled(v)
+	}
+	if _, ok := mc.mutation.LastStep(); !ok {
+		v := mfa.DefaultLastStep
+		mc.mutation.SetLastStep(v)
+	}
+}
+
+// check runs all checks and user-defined validators on the builder.
+func (mc *MFACreate) check() error {
+	if _, ok := mc.mutation.Secret(); !ok {
+		return &ValidationError{Name: "secret", err: errors.New(`ent: missing required field "MFA.secret"`)}
+	}
+	if v, ok := mc.mutation.Secret(); ok {
+		if err := mfa.SecretValidator(v); err != nil {
+			return &ValidationError{Name: "secret", err: fmt.Errorf(`ent: validator failed for field "MFA.secret": %w`, err)}
+		}
+	}
+	if _, ok := mc.mutation.Enabled(); !ok {
+		return &ValidationError{Name: "enabled", err: errors.New(`ent: missing required field "MFA.enabled"`)}
+	}
+	if _, ok := mc.mutation.LastStep(); !ok {
+		return &ValidationError{Name: "last_step", err: errors.New(`ent: missing required field "MFA.last_step"`)}
+	}
+	if len(mc.mutation.UserIDs()) == 0 {
+		return &ValidationError{Name: "user", err: errors.New(`ent: missing required edge "MFA.user"`)}
+	}
+	return nil
+}
+
+func (mc *MFACreate) sqlSave(ctx context.Context) (*MFA, error) {
+	if err := mc.check(); err != nil {
+		return nil, err
+	}
+	_node, _spec := mc.createSpec()
+	if err := sqlgraph.CreateNode(ctx, mc.driver, _spec); err != nil {
+		if sqlgraph.IsConstraintError(err) {
+			err = &ConstraintError{msg: err.Error(), wrap: err}
+		}
+		return nil, err
+	}
+	id := _spec.ID.Value.(int64)
+	_node.ID = int(id)
+	mc.mutation.id = &_node.ID
+	mc.mutation.done = true
+	return _node, nil
+}
+
+func (mc *MFACreate) createSpec() (*MFA, *sqlgraph.CreateSpec) {
+	var (
+		_node = &MFA{config: mc.config}
+		_spec = sqlgraph.NewCreateSpec(mfa.Table, sqlgraph.NewFieldSpec(mfa.FieldID, field.TypeInt))
+	)
+	if value, ok := mc.mutation.Secret(); ok {
+		_spec.SetField(mfa.FieldSecret, field.TypeBytes, value)
+		_node.Secret = value
+	}
+	if value, ok := mc.mutation.Enabled(); ok {
+		_spec.SetField(mfa.FieldEnabled, field.TypeBool, value)
+		_node.Enabled = value
+	}
+	if value, ok := mc.mutation.LastStep(); ok {
+		_spec.SetField(mfa.FieldLastStep, field.TypeInt64, value)
+		_node.LastStep = value
+	}
+	if nodes := mc.mutation.UserIDs(); len(nodes) > 0 {
+		edge := &sqlgraph.EdgeSpec{
+			Rel:     sqlgraph.O2O,
+			Inverse: true,
+			Table:   mfa.UserTable,
+			Columns: []string{mfa.UserColumn},
+			Bidi:    false,
+			Target: &sqlgraph.EdgeTarget{
+				IDSpec: sqlgraph.NewFieldSpec(user.FieldID, field.TypeInt),
+			},
+		}
+		for _, k := range nodes {
+			edge.Target.Nodes = append(edge.Target.Nodes, k)
+		}
+		_node.user_mfa = &nodes[0]
+		_spec.Edges = append(_spec.Edges, edge)
+	}
+	return _node, _spec
+}
+
+// MFACreateBulk is the builder for creating many MFA entities in bulk.
+type MFACreateBulk struct {
+	config
+	err      error
+	builders []*MFACreate
+}
+
+// Save creates the MFA entities in the database.
+func (mcb *MFACreateBulk) Save(ctx context.Context) ([]*MFA, error) {
+	if mcb.err != nil {
+		return nil, mcb.err
+	}
+	specs := make([]*sqlgraph.CreateSpec, len(mcb.builders))
+	nodes := make([]*MFA, len(mcb.builders))
+	mutators := make([]Mutator, len(mcb.builders))
+	for i := range mcb.builders {
+		func(i int, root context.Context) {
+			builder := mcb.builders[i]
+			builder.defaults()
+			var mut Mutator = MutateFunc(func(ctx context.Context, m Mutation) (Value, error) {
+				mutation, ok := m.(*MFAMutation)
+				if !ok {
+					return nil, fmt.Errorf("unexpected mutation type %T", m)
+				}
+				if err := builder.check(); err != nil {
+					return nil, err
+				}
+				builder.mutation = mutation
+				var err error
+				nodes[i], specs[i] = builder.createSpec()
+				if i < len(mutators)-1 {
+					_, err = mutators[i+1].Mutate(root, mcb.builders[i+1].mutation)
+				} else {
+					spec := &sqlgraph.BatchCreateSpec{Nodes: specs}
+					// Invoke the actual operation on the latest mutation in the chain.
+					if err = sqlgraph.BatchCreate(ctx, mcb.driver, spec); err != nil {
+						if sqlgraph.IsConstraintError(err) {
+							err = &ConstraintError{msg: err.Error(), wrap: err}
+						}
+					}
+				}
+				if err != nil {
+					return nil, err
+				}
+				mutation.id = &nodes[i].ID
+				if specs[i].ID.Value != nil {
+					id := specs[i].ID.Value.(int64)
+					nodes[i].ID = int(id)
+				}
+				mutation.done = true
+				return nodes[i], nil
+			})
+			for i := len(builder.hooks) - 1; i >= 0; i-- {
+				mut = builder.hooks[i](mut)
+			}
+			mutators[i] = mut
+		}(i, ctx)
+	}
+	if len(mutators) > 0 {
+		if _, err := mutators[0].Mutate(ctx, mcb.builders[0].mutation); err != nil {
+			return nil, err
+		}
+	}
+	return nodes, nil
+}
+
+// SaveX is like Save, but panics if an error occurs.
+func (mcb *MFACreateBulk) SaveX(ctx context.Context) []*MFA {
+	v, err := mcb.Save(ctx)
+	if err != nil {
+		panic(err)
+	}
+	return v
+}
+
+// Exec executes the query.
+func (mcb *MFACreateBulk) Exec(ctx context.Context) error {
+	_, err := mcb.Save(ctx)
+	return err
+}
+
+// ExecX is like Exec, but panics if an error occurs.
+func (mcb *MFACreateBulk) ExecX(ctx context.Context) {
+	if err := mcb.Exec(ctx); err != nil {
+		panic(err)
+	}
+}
@@ -0,0 +1,406 @@
+// Code generated by ent, DO NOT EDIT.
+
+package ent
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"entgo.io/ent/dialect/sql"
+	"entgo.io/ent/dialect/sql/sqlgraph"
+	"entgo.io/ent/schema/field"
+	"github.com/smxlong/users/ent/mfa"
+	"github.com/smxlong/users/ent/predicate"
+	"github.com/smxlong/users/ent/user"
+)
+
+// MFAUpdate is the builder for updating MFA entities.
+type MFAUpdate struct {
+	config
+	hooks    []Hook
+	mutation *MFAMutation
+}
+
+// Where appends a list predicates to the MFAUpdate builder.
+func (mu *MFAUpdate) Where(ps ...predicate.MFA) *MFAUpdate {
+	mu.mutation.Where(ps...)
+	return mu
+}
+
+// SetSecret sets the "secret" field.
+func (mu *MFAUpdate) SetSecret(b []byte) *MFAUpdate {
+	mu.mutation.SetSecret(b)
+	return mu
+}
+
+// SetEnabled sets the "enabled" field.
+func (mu *MFAUpdate) SetEnabled(b bool) *MFAUpdate {
+	mu.mutation.SetEnabled(b)
+	return mu
+}
+
+// SetNillableEnabled sets the "enabled" field if the given value is not nil.
+func (mu *MFAUpdate) SetNillableEnabled(b *bool) *MFAUpdate {
+	if b != nil {
+		mu.SetEnabled(*b)
+	}
+	return mu
+}
+
+// SetLastStep sets the "last_step" field.
+func (mu *MFAUpdate) SetLastStep(i int64) *MFAUpdate {
+	mu.mutation.ResetLastStep()
+	mu.mutation.SetLastStep(i)
+	return mu
+}
+
+// SetNillableLastStep sets the "last_step" field if the given value is not nil.
+func (mu *MFAUpdate) SetNillableLastStep(i *int64) *MFAUpdate {
+	if i != nil {
+		mu.SetLastStep(*i)
+	}
+	return mu
+}
+
+// AddLastStep adds i to the "last_step" field.
+func (mu *MFAUpdate) AddLastStep(i int64) *MFAUpdate {
+	mu.mutation.AddLastStep(i)
+	return mu
+}
+
+// SetUserID sets the "user" edge to the User entity by ID.
+func (mu *MFAUpdate) SetUserID(id int) *MFAUpdate {
+	mu.mutation.SetUserID(id)
+	return mu
+}
+
+// SetUser sets the "user" edge to the User entity.
+func (mu *MFAUpdate) SetUser(u *User) *MFAUpdate {
+	return mu.SetUserID(u.ID)
+}
+
+// Mutation returns the MFAMutation object of the builder.
+func (mu *MFAUpdate) Mutation() *MFAMutation {
+	return mu.mutation
+}
+
+// ClearUser clears the "user" edge to the User entity.
+func (mu *MFAUpdate) ClearUser() *MFAUpdate {
+	mu.mutation.ClearUser()
+	return mu
+}
+
+// Save executes the query and returns the number of nodes affected by the update operation.
+func (mu *MFAUpdate) Save(ctx context.Context) (int, error) {
+	return withHooks(ctx, mu.sqlSave, mu.mutation, mu.hooks)
+}
+
+// SaveX is like Save, but panics if an error occurs.
+func (mu *MFAUpdate) SaveX(ctx context.Context) int {
+	affected, err := mu.Save(ctx)
+	if err != nil {
+		panic(err)
+	}
+	return affected
+}
+
+// Exec executes the query.
+func (mu *MFAUpdate) Exec(ctx context.Context) error {
+	_, err := mu.Save(ctx)
+	return err
+}
+
+// ExecX is like Exec, but panics if an error occurs.
+func (mu *MFAUpdate) ExecX(ctx context.Context) {
+	if err := mu.Exec(ctx); err != nil {
+		panic(err)
+	}
+}
+
+// check runs all checks and user-defined validators on the builder.
+func (mu *MFAUpdate) check() error {
+	if v, ok := mu.mutation.Secret(); ok {
+		if err := mfa.SecretValidator(v); err != nil {
+			return &ValidationError{Name: "secret", err: fmt.Errorf(`ent: validator failed for field "MFA.secret": %w`, err)}
+		}
+	}
+	if mu.mutation.UserCleared() && len(mu.mutation.UserIDs()) > 0 {
+		return errors.New(`ent: clearing a required unique edge "MFA.user"`)
+	}
+	return nil
+}
+
+func (mu *MFAUpdate) sqlSave(ctx context.Context) (n int, err error) {
+	if err := mu.check(); err != nil {
+		return n, err
+	}
+	_spec := sqlgraph.NewUpdateSpec(mfa.Table, mfa.Columns, sqlgraph.NewFieldSpec(mfa.FieldID, field.TypeInt))
+	if ps := mu.mutation.predicates; len(ps) > 0 {
+		_spec.Predicate = func(selector *sql.Selector) {
+			for i := range ps {
+				ps[i](selector)
+			}
+		}
+	}
+	if value, ok := mu.mutation.Secret(); ok {
+		_spec.SetField(mfa.FieldSecret, field.TypeBytes, value)
+	}
+	if value, ok := mu.mutation.Enabled(); ok {
+		_spec.SetField(mfa.FieldEnabled, field.TypeBool, value)
+	}
+	if value, ok := mu.mutation.LastStep(); ok {
+		_spec.SetField(mfa.FieldLastStep, field.TypeInt64, value)
+	}
+	if value, ok := mu.mutation.AddedLastStep(); ok {
+		_spec.AddField(mfa.FieldLastStep, field.TypeInt64, value)
+	}
+	if mu.mutation.UserCleared() {
+		edge := &sqlgraph.EdgeSpec{
+			Rel:     sqlgraph.O2O,
+			Inverse: true,
+			Table:   mfa.UserTable,
+			Columns: []string{mfa.UserColumn},
+			Bidi:    false,
+			Target: &sqlgraph.EdgeTarget{
+				IDSpec: sqlgraph.NewFieldSpec(user.FieldID, field.TypeInt),
+			},
+		}
+		_spec.Edges.Clear = append(_spec.Edges.Clear, edge)
+	}
+	if nodes := mu.mutation.UserIDs(); len(nodes) > 0 {
+		edge := &sqlgraph.EdgeSpec{
+			Rel:     sqlgraph.O2O,
+			Inverse: true,
+			Table:   mfa.UserTable,
+			Columns: []string{mfa.UserColumn},
+			Bidi:    false,
+			Target: &sqlgraph.EdgeTarget{
+				IDSpec: sqlgraph.NewFieldSpec(user.FieldID, field.TypeInt),
+			},
+		}
+		for _, k := range nodes {
+			edge.Target.Nodes = append(edge.Target.Nodes, k)
+		}
+		_spec.Edges.Add = append(_spec.Edges.Add, edge)
+	}
+	if n, err = sqlgraph.UpdateNodes(ctx, mu.driver, _spec); err != nil {
+		if _, ok := err.(*sqlgraph.NotFoundError); ok {
+			err = &NotFoundError{mfa.Label}
+		} else if sqlgraph.IsConstraintError(err) {
+			err = &ConstraintError{msg: err.Error(), wrap: err}
+		}
+		return 0, err
+	}
+	mu.mutation.done = true
+	return n, nil
+}
+
+// MFAUpdateOne is the builder for updating a single MFA entity.
+type MFAUpdateOne struct {
+	config
+	fields   []string
+	hooks    []Hook
+	mutation *MFAMutation
+}
+
+// SetSecret sets the "secret" field.
+func (muo *MFAUpdateOne) SetSecret(b []byte) *MFAUpdateOne {
+	muo.mutation.SetSecret(b)
+	return muo
+}
+
+// SetEnabled sets the "enabled" field.
+func (muo *MFAUpdateOne) SetEnabled(b bool) *MFAUpdateOne {
+	muo.mutation.SetEnabled(b)
+	return muo
+}
+
+// SetNillableEnabled sets the "enabled" field if the given value is not nil.
+func (muo *MFAUpdateOne) SetNillableEnabled(b *bool) *MFAUpdateOne {
+	if b != nil {
+		muo.SetEnabled(*b)
+	}
+	return muo
+}
+
+// SetLastStep sets the "last_step" field.
+func (muo *MFAUpdateOne) SetLastStep(i int64) *MFAUpdateOne {
+	muo.mutation.ResetLastStep()
+	muo.mutation.SetLastStep(i)
+	return muo
+}
+
+// SetNillableLastStep sets the "last_step" field if the given value is not nil.
+func (muo *MFAUpdateOne) SetNillableLastStep(i *int64) *MFAUpdateOne {
+	if i != nil {
+		muo.SetLastStep(*i)
+	}
+	return muo
+}
+
+// AddLastStep adds i to the "last_step" field.
+func (muo *MFAUpdateOne) AddLastStep(i int64) *MFAUpdateOne {
+	muo.mutation.AddLastStep(i)
+	return muo
+}
+
+// SetUserID sets the "user" edge to the User entity by ID.
+func (muo *MFAUpdateOne) SetUserID(id int) *MFAUpdateOne {
+	muo.mutation.SetUserID(id)
+	return muo
+}
+
+// SetUser sets the "user" edge to the User entity.
+func (muo *MFAUpdateOne) SetUser(u *User) *MFAUpdateOne {
+	return muo.SetUserID(u.ID)
+}
+
+// Mutation returns the MFAMutation object of the builder.
+func (muo *MFAUpdateOne) Mutation() *MFAMutation {
+	return muo.mutation
+}
+
+// ClearUser clears the "user" edge to the User entity.
+func (muo *MFAUpdateOne) ClearUser() *MFAUpdateOne {
+	muo.mutation.ClearUser()
+	return muo
+}
+
+// Where appends a list predicates to the MFAUpdate builder.
+func (muo *MFAUpdateOne) Where(ps ...predicate.MFA) *MFAUpdateOne {
+	muo.mutation.Where(ps...)
+	return muo
+}
+
+// Select allows selecting one or more fields (columns) of the returned entity.
+// The default is selecting all fields defined in the entity schema.
+func (muo *MFAUpdateOne) Select(field string, fields ...string) *MFAUpdateOne {
+	muo.fields = append([]string{field}, fields...)
+	return muo
+}
+
+// Save executes the query and returns the updated MFA entity.
+func (muo *MFAUpdateOne) Save(ctx context.Context) (*MFA, error) {
+	return withHooks(ctx, muo.sqlSave, muo.mutation, muo.hooks)
+}
+
+// SaveX is like Save, but panics if an error occurs.
+func (muo *MFAUpdateOne) SaveX(ctx context.Context) *MFA {
+	node, err := muo.Save(ctx)
+	if err != nil {
+		panic(err)
+	}
+	return node
+}
+
+// Exec executes the query on the entity.
+func (muo *MFAUpdateOne) Exec(ctx context.Context) error {
+	_, err := muo.Save(ctx)
+	return err
+}
+
+// ExecX is like Exec, but panics if an error occurs.
+func (muo *MFAUpdateOne) ExecX(ctx context.Context) {
+	if err := muo.Exec(ctx); err != nil {
+		panic(err)
+	}
+}
+
+// check runs all checks and user-defined validators on the builder.
+func (muo *MFAUpdateOne) check() error {
+	if v, ok := muo.mutation.Secret(); ok {
+		if err := mfa.SecretValidator(v); err != nil {
+			return &ValidationError{Name: "secret", err: fmt.Errorf(`ent: validator failed for field "MFA.secret": %w`, err)}
+		}
+	}
+	if muo.mutation.UserCleared() && len(muo.mutation.UserIDs()) > 0 {
+		return errors.New(`ent: clearing a required unique edge "MFA.user"`)
+	}
+	return nil
+}
+
+func (muo *MFAUpdateOne) sqlSave(ctx context.Context) (_node *MFA, err error) {
+	if err := muo.check(); err != nil {
+		return _node, err
+	}
+	_spec := sqlgraph.NewUpdateSpec(mfa.Table, mfa.Columns, sqlgraph.NewFieldSpec(mfa.FieldID, field.TypeInt))
+	id, ok := muo.mutation.ID()
+	if !ok {
+		return nil, &ValidationError{Name: "id", err: errors.New(`ent: missing "MFA.id" for update`)}
+	}
+	_spec.Node.ID.Value = id
+	if fields := muo.fields; len(fields) > 0 {
+		_spec.Node.Columns = make([]string, 0, len(fields))
+		_spec.Node.Columns = append(_spec.Node.Columns, mfa.FieldID)
+		for _, f := range fields {
+			if !mfa.ValidColumn(f) {
+				return nil, &ValidationError{Name: f, err: fmt.Errorf("ent: invalid field %q for query", f)}
+			}
+			if f != mfa.FieldID {
+				_spec.Node.Columns = append(_spec.Node.Columns, f)
+			}
+		}
+	}
+	if ps := muo.mutation.predicates; len(ps) > 0 {
+		_spec.Predicate = func(selector *sql.Selector) {
+			for i := range ps {
+				ps[i](selector)
+			}
+		}
+	}
+	if value, ok := muo.mutation.Secret(); ok {
+		_spec.SetField(mfa.FieldSecret, field.TypeBytes, value)
+	}
+	if value, ok := muo.mutation.Enabled(); ok {
+		_spec.SetField(mfa.FieldEnabled, field.TypeBool, value)
+	}
+	if value, ok := muo.mutation.LastStep(); ok {
+		_spec.SetField(mfa.FieldLastStep, field.TypeInt64, value)
+	}
+	if value, ok := muo.mutation.AddedLastStep(); ok {
+		_spec.AddField(mfa.FieldLastStep, field.TypeInt64, value)
+	}
+	if muo.mutation.UserCleared() {
+		edge := &sqlgraph.EdgeSpec{
+			Rel:     sqlgraph.O2O,
+			Inverse: true,
+			Table:   mfa.UserTable,
+			Columns: []string{mfa.UserColumn},
+			Bidi:    false,
+			Target: &sqlgraph.EdgeTarget{
+				IDSpec: sqlgraph.NewFieldSpec(user.FieldID, field.TypeInt),
+			},
+		}
+		_spec.Edges.Clear = append(_spec.Edges.Clear, edge)
+	}
+	if nodes := muo.mutation.UserIDs(); len(nodes) > 0 {
+		edge := &sqlgraph.EdgeSpec{
+			Rel:     sqlgraph.O2O,
+			Inverse: true,
+			Table:   mfa.UserTable,
+			Columns: []string{mfa.UserColumn},
+			Bidi:    false,
+			Target: &sqlgraph.EdgeTarget{
+				IDSpec: sqlgraph.NewFieldSpec(user.FieldID, field.TypeInt),
+			},
+		}
+		for _, k := range nodes {
+			edge.Target.Nodes = append(edge.Target.Nodes, k)
+		}
+		_spec.Edges.Add = append(_spec.Edges.Add, edge)
+	}
+	_node = &MFA{config: muo.config}
+	_spec.Assign = _node.assignValues
+	_spec.ScanValues = _node.scanValues
+	if err = sqlgraph.UpdateNode(ctx, muo.driver, _spec); err != nil {
+		if _, ok := err.(*sqlgraph.NotFoundError); ok {
+			err = &NotFoundError{mfa.Label}
+		} else if sqlgraph.IsConstraintError(err) {
+			err = &ConstraintError{msg: err.Error(), wrap: err}
+		}
+		return nil, err
+	}
+	muo.mutation.done = true
+	return _node, nil
+}
@@ -3,14 +3,131 @@
 package migrate
 
 import (
+	"entgo.io/ent/dialect/entsql"
 	"entgo.io/ent/dialect/sql/schema"
 	"entgo.io/ent/schema/field"
 )
 
 var (
+	// CredentialsColumns holds the columns for the "credentials" table.
+	CredentialsColumns = []*schema.Column{
+		{Name: "id", Type: field.TypeInt, Increment: true},
+		{Name: "credential_id", Type: field.TypeBytes, Unique: true},
+		{Name: "public_key", Type: field.TypeBytes},
+		{Name: "sign_count", Type: field.TypeUint32, Default: 0},
+		{Name: "transports", Type: field.TypeJSON, Nullable: true},
+		{Name: "aaguid", Type: field.TypeBytes, Nullable: true},
+		{Name: "user_credentials", Type: field.TypeInt},
+	}
+	// CredentialsTable holds the schema information for the "credentials" table.
+	CredentialsTable = &schema.Table{
+		Name:       "credentials",
+		Columns:    CredentialsColumns,
+		PrimaryKey: []*schema.Column{CredentialsColumns[0]},
+		ForeignKeys: []*schema.ForeignKey{
+			{
+				Symbol:     "credentials_users_credentials",
+				Columns:    []*schema.Column{CredentialsColumns[6]},
+				RefColumns: []*schema.Column{UsersColumns[0]},
+				OnDelete:   schema.Cascade,
+			},
+		},
+	}
+	// IdentitiesColumns holds the columns for the "identities" table.
+	IdentitiesColumns = []*schema.Column{
+		{Name: "id", Type: field.TypeInt, Increment: true},
+		{Name: "provider", Type: field.TypeString},
+		{Name: "subject", Type: field.TypeString},
+		{Name: "email", Type: field.TypeString, Nullable: true},
+		{Name: "user_identities", Type: field.TypeInt},
+	}
+	// IdentitiesTable holds the schema information for the "identities" table.
+	IdentitiesTable = &schema.Table{
+		Name:       "identities",
+		Columns:    IdentitiesColumns,
+		PrimaryKey: []*schema.Column{IdentitiesColumns[0]},
+		ForeignKeys: []*schema.ForeignKey{
+			{
+				Symbol:     "identities_users_identities",
+				Columns:    []*schema.Column{IdentitiesColumns[4]},
+				RefColumns: []*schema.Column{UsersColumns[0]},
+				OnDelete:   schema.Cascade,
+			},
+		},
+		Indexes: []*schema.Index{
+			{
+				Name:    "identity_provider_subject",
+				Unique:  true,
+				Columns: []*schema.Column{IdentitiesColumns[1], IdentitiesColumns[2]},
+			},
+		},
+	}
+	// LockoutsColumns holds the columns for the "lockouts" table.
+	LockoutsColumns = []*schema.Column{
+		{Name: "id", Type: field.TypeInt, Increment: true},
+		{Name: "key", Type: field.TypeString, Unique: true},
+		{Name: "failures", Type: field.TypeInt, Default: 0},
+		{Name: "last_failure_at", Type: field.TypeTime},
+		{Name: "locked_until", Type: field.TypeTime, Nullable: true},
+	}
+	// LockoutsTable holds the schema information for the "lockouts" table.
+	LockoutsTable = &schema.Table{
+		Name:       "lockouts",
+		Columns:    LockoutsColumns,
+		PrimaryKey: []*schema.Column{LockoutsColumns[0]},
+	}
+	// MfaColumns holds the columns for the "mfa" table.
+	MfaColumns = []*schema.Column{
+		{Name: "id", Type: field.TypeInt, Increment: true},
+		{Name: "secret", Type: field.TypeBytes},
+		{Name: "enabled", Type: field.TypeBool, Default: false},
+		{Name: "last_step", Type: field.TypeInt64, Default: 0},
+		{Name: "user_mfa", Type: field.TypeInt, Unique: true},
+	}
+	// MfaTable holds the schema information for the "mfa" table.
+	MfaTable = &schema.Table{
+		Name:       "mfa",
+		Columns:    MfaColumns,
+		PrimaryKey: []*schema.Column{MfaColumns[0]},
+		ForeignKeys: []*schema.ForeignKey{
+			{
+				Symbol:     "mfa_users_mfa",
+				Columns:    []*schema.Column{MfaColumns[4]},
+				RefColumns: []*schema.Column{UsersColumns[0]},
+				OnDelete:   schema.Cascade,
+			},
+		},
+	}
+	// OneTimeTokensColumns holds the columns for the "one_time_tokens" table.
+	OneTimeTokensColumns = []*schema.Column{
+		{Name: "id", Type: field.TypeInt, Increment: true},
+		{Name: "purpose", Type: field.TypeEnum, Enums: []string{"email_verification", "password_reset", "magic_link", "mfa_challenge"}},
+		{Name: "token_hash", Type: field.TypeString, Unique: true},
+		{Name: "expires_at", Type: field.TypeTime},
+		{Name: "device_hash", Type: field.TypeString, Nullable: true},
+		{Name: "attempts", Type: field.TypeInt, Default: 0},
+		{Name: "used_at", Type: field.TypeTime, Nullable: true},
+		{Name: "user_one_time_tokens", Type: field.TypeInt},
+	}
+	// OneTimeTokensTable holds the schema information for the "one_time_tokens" table.
+	OneTimeTokensTable = &schema.Table{
+		Name:       "one_time_tokens",
+		Columns:    OneTimeTokensColumns,
+		PrimaryKey: []*schema.Column{OneTimeTokensColumns[0]},
+		ForeignKeys: []*schema.ForeignKey{
+			{
+				Symbol:     "one_time_tokens_users_one_time_tokens",
+				Columns:    []*schema.Column{OneTimeTokensColumns[7]},
+				RefColumns: []*schema.Column{UsersColumns[0]},
+				OnDelete:   schema.Cascade,
+			},
+		},
+	}
 	// PermissionsColumns holds the columns for the "permissions" table.
 	PermissionsColumns = []*schema.Column{
 		{Name: "id", Type: field.TypeInt, Increment: true},
+		{Name: "created_at", Type: field.TypeTime},
+		{Name: "updated_at", Type: field.TypeTime},
 		{Name: "name", Type: field.TypeString, Unique: true},
 		{Name: "description", Type: field.TypeString, Nullable: true},
 	}
@@ -20,9 +137,71 @@ var (
 		Columns:    PermissionsColumns,
 		PrimaryKey: []*schema.Column{PermissionsColumns[0]},
 	}
+	// RecoveryCodesColumns holds the columns for the "recovery_codes" table.
+	RecoveryCodesColumns = []*schema.Column{
+		{Name: "id", Type: field.TypeInt, Increment: true},
+		{Name: "code_hash", Type: field.TypeString},
+		{Name: "used_at", Type: field.TypeTime, Nullable: true},
+		{Name: "user_recovery_codes", Type: field.TypeInt},
+	}
+	// RecoveryCodesTable holds the schema information for the "recovery_codes" table.
+	RecoveryCodesTable = &schema.Table{
+		Name:       "recovery_codes",
+		Columns:    RecoveryCodesColumns,
+		PrimaryKey: []*schema.Column{RecoveryCodesColumns[0]},
+		ForeignKeys: []*schema.ForeignKey{
+			{
+				Symbol:     "recovery_codes_users_recovery_codes",
+				Columns:    []*schema.Column{RecoveryCodesColumns[3]},
+				RefColumns: []*schema.Column{UsersColumns[0]},
+				OnDelete:   schema.Cascade,
+			},
+		},
+	}
+	// RelationTuplesColumns holds the columns for the "relation_tuples" table.
+	RelationTuplesColumns = []*schema.Column{
+		{Name: "id", Type: field.TypeInt, Increment: true},
+		{Name: "created_at", Type: field.TypeTime},
+		{Name: "updated_at", Type: field.TypeTime},
+		{Name: "object_type", Type: field.TypeString},
+		{Name: "object_id", Type: field.TypeString},
+		{Name: "relation", Type: field.TypeString},
+		{Name: "subject_type", Type: field.TypeString},
+		{Name: "subject_id", Type: field.TypeString},
+		{Name: "subject_relation", Type: field.TypeString, Default: ""},
+		{Name: "user_relation_tuples", Type: field.TypeInt, Nullable: true},
+	}
+	// RelationTuplesTable holds the schema information for the "relation_tuples" table.
+	RelationTuplesTable = &schema.Table{
+		Name:       "relation_tuples",
+		Columns:    RelationTuplesColumns,
+		PrimaryKey: []*schema.Column{RelationTuplesColumns[0]},
+		ForeignKeys: []*schema.ForeignKey{
+			{
+				Symbol:     "relation_tuples_users_relation_tuples",
+				Columns:    []*schema.Column{RelationTuplesColumns[9]},
+				RefColumns: []*schema.Column{UsersColumns[0]},
+				OnDelete:   schema.Cascade,
+			},
+		},
+		Indexes: []*schema.Index{
+			{
+				Name:    "relationtuple_object_type_object_id_relation_subject_type_subject_id_subject_relation",
+				Unique:  true,
+				Columns: []*schema.Column{RelationTuplesColumns[3], RelationTuplesColumns[4], RelationTuplesColumns[5], RelationTuplesColumns[6], RelationTuplesColumns[7], RelationTuplesColumns[8]},
+			},
+			{
+				Name:    "relationtuple_subject_type_subject_id_subject_relation",
+				Unique:  false,
+				Columns: []*schema.Column{RelationTuplesColumns[6], RelationTuplesColumns[7], RelationTuplesColumns[8]},
+			},
+		},
+	}
 	// RolesColumns holds the columns for the "roles" table.
 	RolesColumns = []*schema.Column{
 		{Name: "id", Type: field.TypeInt, Increment: true},
+		{Name: "created_at", Type: field.TypeTime},
+		{Name: "updated_at", Type: field.TypeTime},
 		{Name: "name", Type: field.TypeString, Unique: true},
 		{Name: "description", Type: field.TypeString, Nullable: true},
 	}
@@ -32,12 +211,60 @@ var (
 		Columns:    RolesColumns,
 		PrimaryKey: []*schema.Column{RolesColumns[0]},
 	}
+	// RoleAssignmentsColumns holds the columns for the "role_assignments" table.
+	RoleAssignmentsColumns = []*schema.Column{
+		{Name: "id", Type: field.TypeInt, Increment: true},
+		{Name: "created_at", Type: field.TypeTime},
+		{Name: "updated_at", Type: field.TypeTime},
+		{Name: "resource_type", Type: field.TypeString},
+		{Name: "resource_id", Type: field.TypeString},
+		{Name: "role_assignments", Type: field.TypeInt},
+		{Name: "user_role_assignments", Type: field.TypeInt},
+	}
+	// RoleAssignmentsTable holds the schema information for the "role_assignments" table.
+	RoleAssignmentsTable = &schema.Table{
+		Name:       "role_assignments",
+		Columns:    RoleAssignmentsColumns,
+		PrimaryKey: []*schema.Column{RoleAssignmentsColumns[0]},
+		ForeignKeys: []*schema.ForeignKey{
+			{
+				Symbol:     "role_assignments_roles_assignments",
+				Columns:    []*schema.Column{RoleAssignmentsColumns[5]},
+				RefColumns: []*schema.Column{RolesColumns[0]},
+				OnDelete:   schema.Cascade,
+			},
+			{
+				Symbol:     "role_assignments_users_role_assignments",
+				Columns:    []*schema.Column{RoleAssignmentsColumns[6]},
+				RefColumns: []*schema.Column{UsersColumns[0]},
+				OnDelete:   schema.Cascade,
+			},
+		},
+		Indexes: []*schema.Index{
+			{
+				Name:    "roleassignment_resource_type_resource_id_user_role_assignments_role_assignments",
+				Unique:  true,
+				Columns: []*schema.Column{RoleAssignmentsColumns[3], RoleAssignmentsColumns[4], RoleAssignmentsColumns[6], RoleAssignmentsColumns[5]},
+			},
+		},
+	}
 	// UsersColumns holds the columns for the "users" table.
 	UsersColumns = []*schema.Column{
 		{Name: "id", Type: field.TypeInt, Increment: true},
+		{Name: "created_at", Type: field.TypeTime},
+		{Name: "updated_at", Type: field.TypeTime},
+		{Name: "deleted_at", Type: field.TypeTime, Nullable: true},
 		{Name: "name", Type: field.TypeString, Unique: true},
 		{Name: "email", Type: field.TypeString, Unique: true},
 		{Name: "password_hash", Type: field.TypeString},
+		{Name: "email_verified_at", Type: field.TypeTime, Nullable: true},
+		{Name: "token_generation", Type: field.TypeInt, Default: 0},
+		{Name: "status", Type: field.TypeEnum, Enums: []string{"active", "disabled", "suspended", "pending"}, Default: "active"},
+		{Name: "suspended_until", Type: field.TypeTime, Nullable: true},
+		{Name: "last_login_at", Type: field.TypeTime, Nullable: true},
+		{Name: "last_login_ip", Type: field.TypeString, Nullable: true},
+		{Name: "failed_login_count", Type: field.TypeInt, Default: 0},
+		{Name: "password_changed_at", Type: field.TypeTime, Nullable: true},
 	}
 	// UsersTable holds the schema information for the "users" table.
 	UsersTable = &schema.Table{
@@ -45,6 +272,57 @@ var (
 		Columns:    UsersColumns,
 		PrimaryKey: []*schema.Column{UsersColumns[0]},
 	}
+	// UserAttributesColumns holds the columns for the "user_attributes" table.
+	UserAttributesColumns = []*schema.Column{
+		{Name: "id", Type: field.TypeInt, Increment: true},
+		{Name: "name", Type: field.TypeString},
+		{Name: "value", Type: field.TypeString},
+		{Name: "unique_value", Type: field.TypeString, Nullable: true},
+		{Name: "user_attributes", Type: field.TypeInt},
+	}
+	// UserAttributesTable holds the schema information for the "user_attributes" table.
+	UserAttributesTable = &schema.Table{
+		Name:       "user_attributes",
+		Columns:    UserAttributesColumns,
+		PrimaryKey: []*schema.Column{UserAttributesColumns[0]},
+		ForeignKeys: []*schema.ForeignKey{
+			{
+				Symbol:     "user_attributes_users_attributes",
+				Columns:    []*schema.Column{UserAttributesColumns[4]},
+				RefColumns: []*schema.Column{UsersColumns[0]},
+				OnDelete:   schema.Cascade,
+			},
+		},
+		Indexes: []*schema.Index{
+			{
+				Name:    "userattribute_name_user_attributes",
+				Unique:  true,
+				Columns: []*schema.Column{UserAttributesColumns[1], UserAttributesColumns[4]},
+			},
+			{
+				Name:    "userattribute_name_unique_value",
+				Unique:  true,
+				Columns: []*schema.Column{UserAttributesColumns[1], UserAttributesColumns[3]},
+			},
+			{
+				Name:    "userattribute_name_value",
+				Unique:  false,
+				Columns: []*schema.Column{UserAttributesColumns[1], UserAttributesColumns[2]},
+			},
+		},
+	}
+	// WebAuthnChallengesColumns holds the columns for the "web_authn_challenges" table.
+	WebAuthnChallengesColumns = []*schema.Column{
+		{Name: "id", Type: field.TypeInt, Increment: true},
+		{Name: "challenge_hash", Type: field.TypeString, Unique: true},
+		{Name: "expires_at", Type: field.TypeTime},
+	}
+	// WebAuthnChallengesTable holds the schema information for the "web_authn_challenges" table.
+	WebAuthnChallengesTable = &schema.Table{
+		Name:       "web_authn_challenges",
+		Columns:    WebAuthnChallengesColumns,
+		PrimaryKey: []*schema.Column{WebAuthnChallengesColumns[0]},
+	}
 	// RolePermissionsColumns holds the columns for the "role_permissions" table.
 	RolePermissionsColumns = []*schema.Column{
 		{Name: "role_id", Type: field.TypeInt},
@@ -70,6 +348,31 @@ var (
 			},
 		},
 	}
+	// RoleParentsColumns holds the columns for the "role_parents" table.
+	RoleParentsColumns = []*schema.Column{
+		{Name: "role_id", Type: field.TypeInt},
+		{Name: "child_id", Type: field.TypeInt},
+	}
+	// RoleParentsTable holds the schema information for the "role_parents" table.
+	RoleParentsTable = &schema.Table{
+		Name:       "role_parents",
+		Columns:    RoleParentsColumns,
+		PrimaryKey: []*schema.Column{RoleParentsColumns[0], RoleParentsColumns[1]},
+		ForeignKeys: []*schema.ForeignKey{
+			{
+				Symbol:     "role_parents_role_id",
+				Columns:    []*schema.Column{RoleParentsColumns[0]},
+				RefColumns: []*schema.Column{RolesColumns[0]},
+				OnDelete:   schema.Cascade,
+			},
+			{
+				Symbol:     "role_parents_child_id",
+				Columns:    []*schema.Column{RoleParentsColumns[1]},
+				RefColumns: []*schema.Column{RolesColumns[0]},
+				OnDelete:   schema.Cascade,
+			},
+		},
+	}
 	// UserRolesColumns holds the columns for the "user_roles" table.
 	UserRolesColumns = []*schema.Column{
 		{Name: "user_id", Type: field.TypeInt},
@@ -97,17 +400,42 @@ var (
 	}
 	// Tables holds all the tables in the schema.
 	Tables = []*schema.Table{
+		CredentialsTable,
+		IdentitiesTable,
+		LockoutsTable,
+		MfaTable,
+		OneTimeTokensTable,
 		PermissionsTable,
+		RecoveryCodesTable,
+		RelationTuplesTable,
 		RolesTable,
+		RoleAssignmentsTable,
 		UsersTable,
+		UserAttributesTable,
+		WebAuthnChallengesTable,
 		RolePermissionsTable,
+		RoleParentsTable,
 		UserRolesTable,
 	}
 )
 
 func init() {
+	CredentialsTable.ForeignKeys[0].RefTable = UsersTable
+	IdentitiesTable.ForeignKeys[0].RefTable = UsersTable
+	MfaTable.ForeignKeys[0].RefTable = UsersTable
+	MfaTable.Annotation = &entsql.Annotation{
+		Table: "mfa",
+	}
+	OneTimeTokensTable.ForeignKeys[0].RefTable = UsersTable
+	RecoveryCodesTable.ForeignKeys[0].RefTable = UsersTable
+	RelationTuplesTable.ForeignKeys[0].RefTable = UsersTable
+	RoleAssignmentsTable.ForeignKeys[0].RefTable = RolesTable
+	RoleAssignmentsTable.ForeignKeys[1].RefTable = UsersTable
+	UserAttributesTable.ForeignKeys[0].RefTable = UsersTable
 	RolePermissionsTable.ForeignKeys[0].RefTable = RolesTable
 	RolePermissionsTable.ForeignKeys[1].RefTable = PermissionsTable
+	RoleParentsTable.ForeignKeys[0].RefTable = RolesTable
+	RoleParentsTable.ForeignKeys[1].RefTable = RolesTable
 	UserRolesTable.ForeignKeys[0].RefTable = UsersTable
 	UserRolesTable.ForeignKeys[1].RefTable = RolesTable
 }
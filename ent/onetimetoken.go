@@ -0,0 +1,201 @@
+// Code generated by ent, DO NOT EDIT.
+
+package ent
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"entgo.io/ent"
+	"entgo.io/ent/dialect/sql"
+	"github.com/smxlong/users/ent/onetimetoken"
+	"github.com/smxlong/users/ent/user"
+)
+
+// OneTimeToken is the model entity for the OneTimeToken schema.
+type OneTimeToken struct {
+	config `json:"-"`
+	// ID of the ent.
+	ID int `json:"id,omitempty"`
+	// Purpose holds the value of the "purpose" field.
+	Purpose onetimetoken.Purpose `json:"purpose,omitempty"`
+	// TokenHash holds the value of the "token_hash" field.
+	TokenHash string `json:"-"`
+	// ExpiresAt holds the value of the "expires_at" field.
+	ExpiresAt time.Time `json:"expires_at,omitempty"`
+	// DeviceHash holds the value of the "device_hash" field.
+	DeviceHash string `json:"-"`
+	// Attempts holds the value of the "attempts" field.
+	Attempts int `json:"attempts,omitempty"`
+	// UsedAt holds the value of the "used_at" field.
+	UsedAt *time.Time `json:"used_at,omitempty"`
+	// Edges holds the relations/edges for other nodes in the graph.
+	// The values are being populated by the OneTimeTokenQuery when eager-loading is set.
+	Edges                OneTimeTokenEdges `json:"edges"`
+	user_one_time_tokens *int
+	selectValues         sql.SelectValues
+}
+
+// OneTimeTokenEdges holds the relations/edges for other nodes in the graph.
+type OneTimeTokenEdges struct {
+	// User holds the value of the user edge.
+	User *User `json:"user,omitempty"`
+	// loadedTypes holds the information for reporting if a
+	// type was loaded (or requested) in eager-loading or not.
+	loadedTypes [1]bool
+}
+
+// UserOrErr returns the User value or an error if the edge
+// was not loaded in eager-loading, or loaded but was not found.
+func (e OneTimeTokenEdges) UserOrErr() (*User, error) {
+	if e.User != nil {
+		return e.User, nil
+	} else if e.loadedTypes[0] {
+		return nil, &NotFoundError{label: user.Label}
+	}
+	return nil, &NotLoadedError{edge: "user"}
+}
+
+// scanValues returns the types for scanning values from sql.Rows.
+func (*OneTimeToken) scanValues(columns []string) ([]any, error) {
+	values := make([]any, len(columns))
+	for i := range columns {
+		switch columns[i] {
+		case onetimetoken.FieldID, onetimetoken.FieldAttempts:
+			values[i] = new(sql.NullInt64)
+		case onetimetoken.FieldPurpose, onetimetoken.FieldTokenHash, onetimetoken.FieldDeviceHash:
+			values[i] = new(sql.NullString)
+		case onetimetoken.FieldExpiresAt, onetimetoken.FieldUsedAt:
+			values[i] = new(sql.NullTime)
+		case onetimetoken.ForeignKeys[0]: // user_one_time_tokens
+			values[i] = new(sql.NullInt64)
+		default:
+			values[i] = new(sql.UnknownType)
+		}
+	}
+	return values, nil
+}
+
+// assignValues assigns the values that were returned from sql.Rows (after scanning)
+// to the OneTimeToken fields.
+func (ott *OneTimeToken) assignValues(columns []string, values []any) error {
+	if m, n := len(values), len(columns); m < n {
+		return fmt.Errorf("mismatch number of scan values: %d != %d", m, n)
+	}
+	for i := range columns {
+		switch columns[i] {
+		case onetimetoken.FieldID:
+			value, ok := values[i].(*sql.NullInt64)
+			if !ok {
+				return fmt.Errorf("unexpected type %T for field id", value)
+			}
+			ott.ID = int(value.Int64)
+		case onetimetoken.FieldPurpose:
+			if value, ok := values[i].(*sql.NullString); !ok {
+				return fmt.Errorf("unexpected type %T for field purpose", values[i])
+			} else if value.Valid {
+				ott.Purpose = onetimetoken.Purpose(value.String)
+			}
+		case onetimetoken.FieldTokenHash:
+			if value, ok := values[i].(*sql.NullString); !ok {
+				return fmt.Errorf("unexpected type %T for field token_hash", values[i])
+			} else if value.Valid {
+				ott.TokenHash = value.String
+			}
+		case onetimetoken.FieldExpiresAt:
+			if value, ok := values[i].(*sql.NullTime); !ok {
+				return fmt.Errorf("unexpected type %T for field expires_at", values[i])
+			} else if value.Valid {
+				ott.ExpiresAt = value.Time
+			}
+		case onetimetoken.FieldDeviceHash:
+			if value, ok := values[i].(*sql.NullString); !ok {
+				return fmt.Errorf("unexpected type %T for field device_hash", values[i])
+			} else if value.Valid {
+				ott.DeviceHash = value.String
+			}
+		case onetimetoken.FieldAttempts:
+			if value, ok := values[i].(*sql.NullInt64); !ok {
+				return fmt.Errorf("unexpected type %T for field attempts", values[i])
+			} else if value.Valid {
+				ott.Attempts = int(value.Int64)
+			}
+		case onetimetoken.FieldUsedAt:
+			if value, ok := values[i].(*sql.NullTime); !ok {
+				return fmt.Errorf("unexpected type %T for field used_at", values[i])
+			} else if value.Valid {
+				ott.UsedAt = new(time.Time)
+				*ott.UsedAt = value.Time
+			}
+		case onetimetoken.ForeignKeys[0]:
+			if value, ok := values[i].(*sql.NullInt64); !ok {
+				return fmt.Errorf("unexpected type %T for edge-field user_one_time_tokens", value)
+			} else if value.Valid {
+				ott.user_one_time_tokens = new(int)
+				*ott.user_one_time_tokens = int(value.Int64)
+			}
+		default:
+			ott.selectValues.Set(columns[i], values[i])
+		}
+	}
+	return nil
+}
+
+// Value returns the ent.Value that was dynamically selected and assigned to the OneTimeToken.
+// This includes values selected through modifiers, order, etc.
+func (ott *OneTimeToken) Value(name string) (ent.Value, error) {
+	return ott.selectValues.Get(name)
+}
+
+// QueryUser queries the "user" edge of the OneTimeToken entity.
+func (ott *OneTimeToken) QueryUser() *UserQuery {
+	return NewOneTimeTokenClient(ott.config).QueryUser(ott)
+}
+
+// Update returns a builder for updating this OneTimeToken.
+// Note that you need to call OneTimeToken.Unwrap() before calling this method if this OneTimeToken
+// was returned from a transaction, and the transaction was committed or rolled back.
+func (ott *OneTimeToken) Update() *OneTimeTokenUpdateOne {
+	return NewOneTimeTokenClient(ott.config).UpdateOne(ott)
+}
+
+// Unwrap unwraps the OneTimeToken entity that was returned from a transaction after it was closed,
+// so that all future queries will be executed through the driver which created the transaction.
+func (ott *OneTimeToken) Unwrap() *OneTimeToken {
+	_tx, ok := ott.config.driver.(*txDriver)
+	if !ok {
+		panic("ent: OneTimeToken is not a transactional entity")
+	}
+	ott.config.driver = _tx.drv
+	return ott
+}
+
+// String implements the fmt.Stringer.
+func (ott *OneTimeToken) String() string {
+	var builder strings.Builder
+	builder.WriteString("OneTimeToken(")
+	builder.WriteString(fmt.Sprintf("id=%v, ", ott.ID))
+	builder.WriteString("purpose=")
+	builder.WriteString(fmt.Sprintf("%v", ott.Purpose))
+	builder.WriteString(", ")
+	builder.WriteString("token_hash=<sensitive>")
+	builder.WriteString(", ")
+	builder.WriteString("expires_at=")
+	builder.WriteString(ott.ExpiresAt.Format(time.ANSIC))
+	builder.WriteString(", ")
+	builder.WriteString("device_hash=<sensitive>")
+	builder.WriteString(", ")
+	builder.WriteString("attempts=")
+	builder.WriteString(fmt.Sprintf("%v", ott.Attempts))
+	builder.WriteString(", ")
+	if v := ott.UsedAt; v != nil {
+		builder.WriteString("used_at=")
+		builder.WriteString(v.Format(time.ANSIC))
+	}
+	builder.WriteByte(')')
+	return builder.String()
+}
+
+// OneTimeTokens is a parsable slice of OneTimeToken.
+type OneTimeTokens []*OneTimeToken
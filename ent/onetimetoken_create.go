@@ -0,0 +1,324 @@
+// Code generated by ent, DO NOT EDIT.
+
+package ent
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"entgo.io/ent/dialect/sql/sqlgraph"
+	"entgo.io/ent/schema/field"
+	"github.com/smxlong/users/ent/onetimetoken"
+	"github.com/smxlong/users/ent/user"
+)
+
+// OneTimeTokenCreate is the builder for creating a OneTimeToken entity.
+type OneTimeTokenCreate struct {
+	config
+	mutation *OneTimeTokenMutation
+	hooks    []Hook
+}
+
+// SetPurpose sets the "purpose" field.
+func (ottc *OneTimeTokenCreate) SetPurpose(o onetimetoken.Purpose) *OneTimeTokenCreate {
+	ottc.mutation.SetPurpose(o)
+	return ottc
+}
+
+// SetTokenHash sets the "token_hash" field.
+func (ottc *OneTimeTokenCreate) SetTokenHash(s string) *OneTimeTokenCreate {
+	ottc.mutation.SetTokenHash(s)
+	return ottc
+}
+
+// SetExpiresAt sets the "expires_at" field.
+func (ottc *OneTimeTokenCreate) SetExpiresAt(t time.Time) *OneTimeTokenCreate {
+	ottc.mutation.SetExpiresAt(t)
+	return ottc
+}
+
+// SetDeviceHash sets the "device_hash" field.
+func (ottc *OneTimeTokenCreate) SetDeviceHash(s string) *OneTimeTokenCreate {
+	ottc.mutation.SetDeviceHash(s)
+	return ottc
+}
+
+// SetNillableDeviceHash sets the "device_hash" field if the given value is not nil.
+func (ottc *OneTimeTokenCreate) SetNillableDeviceHash(s *string) *OneTimeTokenCreate {
+	if s != nil {
+		ottc.SetDeviceHash(*s)
+	}
+	return ottc
+}
+
+// SetAttempts sets the "attempts" field.
+func (ottc *OneTimeTokenCreate) SetAttempts(i int) *OneTimeTokenCreate {
+	ottc.mutation.SetAttempts(i)
+	return ottc
+}
+
+// SetNillableAttempts sets the "attempts" field if the given value is not nil.
+func (ottc *OneTimeTokenCreate) SetNillableAttempts(i *int) *OneTimeTokenCreate {
+	if i != nil {
+		ottc.SetAttempts(*i)
+	}
+	return ottc
+}
+
+// SetUsedAt sets the "used_at" field.
+func (ottc *OneTimeTokenCreate) SetUsedAt(t time.Time) *OneTimeTokenCreate {
+	ottc.mutation.SetUsedAt(t)
+	return ottc
+}
+
+// SetNillableUsedAt sets the "used_at" field if the given value is not nil.
+func (ottc *OneTimeTokenCreate) SetNillableUsedAt(t *time.Time) *OneTimeTokenCreate {
+	if t != nil {
+		ottc.SetUsedAt(*t)
+	}
+	return ottc
+}
+
+// SetUserID sets the "user" edge to the User entity by ID.
+func (ottc *OneTimeTokenCreate) SetUserID(id int) *OneTimeTokenCreate {
+	ottc.mutation.SetUserID(id)
+	return ottc
+}
+
+// SetUser sets the "user" edge to the User entity.
+func (ottc *OneTimeTokenCreate) SetUser(u *User) *OneTimeTokenCreate {
+	return ottc.SetUserID(u.ID)
+}
+
+// Mutation returns the OneTimeTokenMutation object of the builder.
+func (ottc *OneTimeTokenCreate) Mutation() *OneTimeTokenMutation {
+	return ottc.mutation
+}
+
+// Save creates the OneTimeToken in the database.
+func (ottc *OneTimeTokenCreate) Save(ctx context.Context) (*OneTimeToken, error) {
+	ottc.defaults()
+	return withHooks(ctx, ottc.sqlSave, ottc.mutation, ottc.hooks)
+}
+
+// SaveX calls Save and panics if Save returns an error.
+func (ottc *OneTimeTokenCreate) SaveX(ctx context.Context) *OneTimeToken {
+	v, err := ottc.Save(ctx)
+	if err != nil {
+		panic(err)
+	}
+	return v
+}
+
+// Exec executes the query.
+func (ottc *OneTimeTokenCreate) Exec(ctx context.Context) error {
+	_, err := ottc.Save(ctx)
+	return err
+}
+
+// ExecX is like Exec, but panics if an error occurs.
+func (ottc *OneTimeTokenCreate) ExecX(ctx context.Context) {
+	if err := ottc.Exec(ctx); err != nil {
+		panic(err)
+	}
+}
+
+// defaults sets the default values of the builder before save.
+func (ottc *OneTimeTokenCreate) defaults() {
+	if _, ok := ottc.mutation.Attempts(); !ok {
+		v := onetimetoken.DefaultAttempts
+		ottc.mutation.SetAttempts(v)
+	}
+}
+
+// check runs all checks and user-defined validators on the builder.
+func (ottc *OneTimeTokenCreate) check() error {
+	if _, ok := ottc.mutation.Purpose(); !ok {
+		return &ValidationError{Name: "purpose", err: errors.New(`ent: missing required field "OneTimeToken.purpose"`)}
+	}
+	if v, ok := ottc.mutation.Purpose(); ok {
+		if err := onetimetoken.PurposeValidator(v); err != nil {
+			return &ValidationError{Name: "purpose", err: fmt.Errorf(`ent: validator failed for field "OneTimeToken.purpose": %w`, err)}
+		}
+	}
+	if _, ok := ottc.mutation.TokenHash(); !ok {
+		return &ValidationError{Name: "token_hash", err: errors.New(`ent: missing required field "OneTimeToken.token_hash"`)}
+	}
+	if v, ok := ottc.mutation.TokenHash(); ok {
+		if err := onetimetoken.TokenHashValidator(v); err != nil {
+			return &ValidationError{Name: "token_hash", err: fmt.Errorf(`ent: validator failed for field "OneTimeToken.token_hash": %w`, err)}
+		}
+	}
+	if _, ok := ottc.mutation.ExpiresAt(); !ok {
+		return &ValidationError{Name: "expires_at", err: errors.New(`ent: missing required field "OneTimeToken.expires_at"`)}
+	}
+	if _, ok := ottc.mutation.Attempts(); !ok {
+		return &ValidationError{Name: "attempts", err: errors.New(`ent: missing required field "OneTimeToken.attempts"`)}
+	}
+	if v, ok := ottc.mutation.Attempts(); ok {
+		if err := onetimetoken.AttemptsValidator(v); err != nil {
+			return &ValidationError{Name: "attempts", err: fmt.Errorf(`ent: validator failed for field "OneTimeToken.attempts": %w`, err)}
+		}
+	}
+	if len(ottc.mutation.UserIDs()) == 0 {
+		return &ValidationError{Name: "user", err: errors.New(`ent: missing required edge "OneTimeToken.user"`)}
+	}
+	return nil
+}
+
+func (ottc *OneTimeTokenCreate) sqlSave(ctx context.Context) (*OneTimeToken, error) {
+	if err := ottc.check(); err != nil {
+		return nil, err
+	}
+	_node, _spec := ottc.createSpec()
+	if err := sqlgraph.CreateNode(ctx, ottc.driver, _spec); err != nil {
+		if sqlgraph.IsConstraintError(err) {
+			err = &ConstraintError{msg: err.Error(), wrap: err}
+		}
+		return nil, err
+	}
+	id := _spec.ID.Value.(int64)
+	_node.ID = int(id)
+	ottc.mutation.id = &_node.ID
+	ottc.mutation.done = true
+	return _node, nil
+}
+
+func (ottc *OneTimeTokenCreate) createSpec() (*OneTimeToken, *sqlgraph.CreateSpec) {
+	var (
+		_node = &OneTimeToken{config: ottc.config}
+		_spec = sqlgraph.NewCreateSpec(onetimetoken.Table, sqlgraph.NewFieldSpec(onetimetoken.FieldID, field.TypeInt))
+	)
+	if value, ok := ottc.mutation.Purpose(); ok {
+		_spec.SetField(onetimetoken.FieldPurpose, field.TypeEnum, value)
+		_node.Purpose = value
+	}
+	if value, ok := ottc.mutation.TokenHash(); ok {
+		_spec.SetField(onetimetoken.FieldTokenHash, field.TypeString, value)
+		_node.TokenHash = value
+	}
+	if value, ok := ottc.mutation.ExpiresAt(); ok {
+		_spec.SetField(onetimetoken.FieldExpiresAt, field.TypeTime, value)
+		_node.ExpiresAt = value
+	}
+	if value, ok := ottc.mutation.DeviceHash(); ok {
+		_spec.SetField(onetimetoken.FieldDeviceHash, field.TypeString, value)
+		_node.DeviceHash = value
+	}
+	if value, ok := ottc.mutation.Attempts(); ok {
+		_spec.SetField(onetimetoken.FieldAttempts, field.TypeInt, value)
+		_node.Attempts = value
+	}
+	if value, ok := ottc.mutation.UsedAt(); ok {
+		_spec.SetField(onetimetoken.FieldUsedAt, field.TypeTime, value)
+		_node.UsedAt = &value
+	}
+	if nodes := ottc.mutation.UserIDs(); len(nodes) > 0 {
+		edge := &sqlgraph.EdgeSpec{
+			Rel:     sqlgraph.M2O,
+			Inverse: true,
+			Table:   onetimetoken.UserTable,
+			Columns: []string{onetimetoken.UserColumn},
+			Bidi:    false,
+			Target: &sqlgraph.EdgeTarget{
+				IDSpec: sqlgraph.NewFieldSpec(user.FieldID, field.TypeInt),
+			},
+		}
+		for _, k := range nodes {
+			edge.Target.Nodes = append(edge.Target.Nodes, k)
+		}
+		_node.user_one_time_tokens = &nodes[0]
+		_spec.Edges = append(_spec.Edges, edge)
+	}
+	return _node, _spec
+}
+
+// OneTimeTokenCreateBulk is the builder for creating many OneTimeToken entities in bulk.
+type OneTimeTokenCreateBulk struct {
+	config
+	err      error
+	builders []*OneTimeTokenCreate
+}
+
+// Save creates the OneTimeToken entities in the database.
+func (ottcb *OneTimeTokenCreateBulk) Save(ctx context.Context) ([]*OneTimeToken, error) {
+	if ottcb.err != nil {
+		return nil, ottcb.err
+	}
+	specs := make([]*sqlgraph.CreateSpec, len(ottcb.builders))
+	nodes := make([]*OneTimeToken, len(ottcb.builders))
+	mutators := make([]Mutator, len(ottcb.builders))
+	for i := range ottcb.builders {
+		func(i int, root context.Context) {
+			builder := ottcb.builders[i]
+			builder.defaults()
+			var mut Mutator = MutateFunc(func(ctx context.Context, m Mutation) (Value, error) {
+				mutation, ok := m.(*OneTimeTokenMutation)
+				if !ok {
+					return nil, fmt.Errorf("unexpected mutation type %T", m)
+				}
+				if err := builder.check(); err != nil {
+					return nil, err
+				}
+				builder.mutation = mutation
+				var err error
+				nodes[i], specs[i] = builder.createSpec()
+				if i < len(mutators)-1 {
+					_, err = mutators[i+1].Mutate(root, ottcb.builders[i+1].mutation)
+				} else {
+					spec := &sqlgraph.BatchCreateSpec{Nodes: specs}
+					// Invoke the actual operation on the latest mutation in the chain.
+					if err = sqlgraph.BatchCreate(ctx, ottcb.driver, spec); err != nil {
+						if sqlgraph.IsConstraintError(err) {
+							err = &ConstraintError{msg: err.Error(), wrap: err}
+						}
+					}
+				}
+				if err != nil {
+					return nil, err
+				}
+				mutation.id = &nodes[i].ID
+				if specs[i].ID.Value != nil {
+					id := specs[i].ID.Value.(int64)
+					nodes[i].ID = int(id)
+				}
+				mutation.done = true
+				return nodes[i], nil
+			})
+			for i := len(builder.hooks) - 1; i >= 0; i-- {
+				mut = builder.hooks[i](mut)
+			}
+			mutators[i] = mut
+		}(i, ctx)
+	}
+	if len(mutators) > 0 {
+		if _, err := mutators[0].Mutate(ctx, ottcb.builders[0].mutation); err != nil {
+			return nil, err
+		}
+	}
+	return nodes, nil
+}
+
+// SaveX is like Save, but panics if an error occurs.
+func (ottcb *OneTimeTokenCreateBulk) SaveX(ctx context.Context) []*OneTimeToken {
+	v, err := ottcb.Save(ctx)
+	if err != nil {
+		panic(err)
+	}
+	return v
+}
+
+// Exec executes the query.
+func (ottcb *OneTimeTokenCreateBulk) Exec(ctx context.Context) error {
+	_, err := ottcb.Save(ctx)
+	return err
+}
+
+// ExecX is like Exec, but panics if an error occurs.
+func (ottcb *OneTimeTokenCreateBulk) ExecX(ctx context.Context) {
+	if err := ottcb.Exec(ctx); err != nil {
+		panic(err)
+	}
+}
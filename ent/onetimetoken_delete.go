@@ -0,0 +1,88 @@
+// Code generated by ent, DO NOT EDIT.
+
+package ent
+
+import (
+	"context"
+
+	"entgo.io/ent/dialect/sql"
+	"entgo.io/ent/dialect/sql/sqlgraph"
+	"entgo.io/ent/schema/field"
+	"github.com/smxlong/users/ent/onetimetoken"
+	"github.com/smxlong/users/ent/predicate"
+)
+
+// OneTimeTokenDelete is the builder for deleting a OneTimeToken entity.
+type OneTimeTokenDelete struct {
+	config
+	hooks    []Hook
+	mutation *OneTimeTokenMutation
+}
+
+// Where appends a list predicates to the OneTimeTokenDelete builder.
+func (ottd *OneTimeTokenDelete) Where(ps ...predicate.OneTimeToken) *OneTimeTokenDelete {
+	ottd.mutation.Where(ps...)
+	return ottd
+}
+
+// Exec executes the deletion query and returns how many vertices were deleted.
+func (ottd *OneTimeTokenDelete) Exec(ctx context.Context) (int, error) {
+	return withHooks(ctx, ottd.sqlExec, ottd.mutation, ottd.hooks)
+}
+
+// ExecX is like Exec, but panics if an error occurs.
+func (ottd *OneTimeTokenDelete) ExecX(ctx context.Context) int {
+	n, err := ottd.Exec(ctx)
+	if err != nil {
+		panic(err)
+	}
+	return n
+}
+
+func (ottd *OneTimeTokenDelete) sqlExec(ctx context.Context) (int, error) {
+	_spec := sqlgraph.NewDeleteSpec(onetimetoken.Table, sqlgraph.NewFieldSpec(onetimetoken.FieldID, field.TypeInt))
+	if ps := ottd.mutation.predicates; len(ps) > 0 {
+		_spec.Predicate = func(selector *sql.Selector) {
+			for i := range ps {
+				ps[i](selector)
+			}
+		}
+	}
+	affected, err := sqlgraph.DeleteNodes(ctx, ottd.driver, _spec)
+	if err != nil && sqlgraph.IsConstraintError(err) {
+		err = &ConstraintError{msg: err.Error(), wrap: err}
+	}
+	ottd.mutation.done = true
+	return affected, err
+}
+
+// OneTimeTokenDeleteOne is the builder for deleting a single OneTimeToken entity.
+type OneTimeTokenDeleteOne struct {
+	ottd *OneTimeTokenDelete
+}
+
+// Where appends a list predicates to the OneTimeTokenDelete builder.
+func (ottdo *OneTimeTokenDeleteOne) Where(ps ...predicate.OneTimeToken) *OneTimeTokenDeleteOne {
+	ottdo.ottd.mutation.Where(ps...)
+	return ottdo
+}
+
+// Exec executes the deletion query.
+func (ottdo *OneTimeTokenDeleteOne) Exec(ctx context.Context) error {
+	n, err := ottdo.ottd.Exec(ctx)
+	switch {
+	case err != nil:
+		return err
+	case n == 0:
+		return &NotFoundError{onetimetoken.Label}
+	default:
+		return nil
+	}
+}
+
+// ExecX is like Exec, but panics if an error occurs.
+func (ottdo *OneTimeTokenDeleteOne) ExecX(ctx context.Context) {
+	if err := ottdo.Exec(ctx); err != nil {
+		panic(err)
+	}
+}
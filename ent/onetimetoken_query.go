@@ -0,0 +1,614 @@
+// Code generated by ent, DO NOT EDIT.
+
+package ent
+
+import (
+	"context"
+	"fmt"
+	"math"
+
+	"entgo.io/ent"
+	"entgo.io/ent/dialect/sql"
+	"entgo.io/ent/dialect/sql/sqlgraph"
+	"entgo.io/ent/schema/field"
+	"github.com/smxlong/users/ent/onetimetoken"
+	"github.com/smxlong/users/ent/predicate"
+	"github.com/smxlong/users/ent/user"
+)
+
+// OneTimeTokenQuery is the builder for querying OneTimeToken entities.
+type OneTimeTokenQuery struct {
+	config
+	ctx        *QueryContext
+	order      []onetimetoken.OrderOption
+	inters     []Interceptor
+	predicates []predicate.OneTimeToken
+	withUser   *UserQuery
+	withFKs    bool
+	// intermediate query (i.e. traversal path).
+	sql  *sql.Selector
+	path func(context.Context) (*sql.Selector, error)
+}
+
+// Where adds a new predicate for the OneTimeTokenQuery builder.
+func (ottq *OneTimeTokenQuery) Where(ps ...predicate.OneTimeToken) *OneTimeTokenQuery {
+	ottq.predicates = append(ottq.predicates, ps...)
+	return ottq
+}
+
+// Limit the number of records to be returned by this query.
+func (ottq *OneTimeTokenQuery) Limit(limit int) *OneTimeTokenQuery {
+	ottq.ctx.Limit = &limit
+	return ottq
+}
+
+// Offset to start from.
+func (ottq *OneTimeTokenQuery) Offset(offset int) *OneTimeTokenQuery {
+	ottq.ctx.Offset = &offset
+	return ottq
+}
+
+// Unique configures the query builder to filter duplicate records on query.
+// By default, unique is set to true, and can be disabled using this method.
+func (ottq *OneTimeTokenQuery) Unique(unique bool) *OneTimeTokenQuery {
+	ottq.ctx.Unique = &unique
+	return ottq
+}
+
+// Order specifies how the records should be ordered.
+func (ottq *OneTimeTokenQuery) Order(o ...onetimetoken.OrderOption) *OneTimeTokenQuery {
+	ottq.order = append(ottq.order, o...)
+	return ottq
+}
+
+// QueryUser chains the current query on the "user" edge.
+func (ottq *OneTimeTokenQuery) QueryUser() *UserQuery {
+	query := (&UserClient{config: ottq.config}).Query()
+	query.path = func(ctx context.Context) (fromU *sql.Selector, err error) {
+		if err := ottq.prepareQuery(ctx); err != nil {
+			return nil, err
+		}
+		selector := ottq.sqlQuery(ctx)
+		if err := selector.Err(); err != nil {
+			return nil, err
+		}
+		step := sqlgraph.NewStep(
+			sqlgraph.From(onetimetoken.Table, onetimetoken.FieldID, selector),
+			sqlgraph.To(user.Table, user.FieldID),
+			sqlgraph.Edge(sqlgraph.M2O, true, onetimetoken.UserTable, onetimetoken.UserColumn),
+		)
+		fromU = sqlgraph.SetNeighbors(ottq.driver.Dialect(), step)
+		return fromU, nil
+	}
+	return query
+}
+
+// First returns the first OneTimeToken entity from the query.
+// Returns a *NotFoundError when no OneTimeToken was found.
+func (ottq *OneTimeTokenQuery) First(ctx context.Context) (*OneTimeToken, error) {
+	nodes, err := ottq.Limit(1).All(setContextOp(ctx, ottq.ctx, ent.OpQueryFirst))
+	if err != nil {
+		return nil, err
+	}
+	if len(nodes) == 0 {
+		return nil, &NotFoundError{onetimetoken.Label}
+	}
+	return nodes[0], nil
+}
+
+// FirstX is like First, but panics if an error occurs.
+func (ottq *OneTimeTokenQuery) FirstX(ctx context.Context) *OneTimeToken {
+	node, err := ottq.First(ctx)
+	if err != nil && !IsNotFound(err) {
+		panic(err)
+	}
+	return node
+}
+
+// FirstID returns the first OneTimeToken ID from the query.
+// Returns a *NotFoundError when no OneTimeToken ID was found.
+func (ottq *OneTimeTokenQuery) FirstID(ctx context.Context) (id int, err error) {
+	var ids []int
+	if ids, err = ottq.Limit(1).IDs(setContextOp(ctx, ottq.ctx, ent.OpQueryFirstID)); err != nil {
+		return
+	}
+	if len(ids) == 0 {
+		err = &NotFoundError{onetimetoken.Label}
+		return
+	}
+	return ids[0], nil
+}
+
+// FirstIDX is like FirstID, but panics if an error occurs.
+func (ottq *OneTimeTokenQuery) FirstIDX(ctx context.Context) int {
+	id, err := ottq.FirstID(ctx)
+	if err != nil && !IsNotFound(err) {
+		panic(err)
+	}
+	return id
+}
+
+// Only returns a single OneTimeToken entity found by the query, ensuring it only returns one.
+// Returns a *NotSingularError when more than one OneTimeToken entity is found.
+// Returns a *NotFoundError when no OneTimeToken entities are found.
+func (ottq *OneTimeTokenQuery) Only(ctx context.Context) (*OneTimeToken, error) {
+	nodes, err := ottq.Limit(2).All(setContextOp(ctx, ottq.ctx, ent.OpQueryOnly))
+	if err != nil {
+		return nil, err
+	}
+	switch len(nodes) {
+	case 1:
+		return nodes[0], nil
+	case 0:
+		return nil, &NotFoundError{onetimetoken.Label}
+	default:
+		return nil, &NotSingularError{onetimetoken.Label}
+	}
+}
+
+// OnlyX is like Only, but panics if an error occurs.
+func (ottq *OneTimeTokenQuery) OnlyX(ctx context.Context) *OneTimeToken {
+	node, err := ottq.Only(ctx)
+	if err != nil {
+		panic(err)
+	}
+	return node
+}
+
+// OnlyID is like Only, but returns the only OneTimeToken ID in the query.
+// Returns a *NotSingularError when more than one OneTimeToken ID is found.
+// Returns a *NotFoundError when no entities are found.
+func (ottq *OneTimeTokenQuery) OnlyID(ctx context.Context) (id int, err error) {
+	var ids []int
+	if ids, err = ottq.Limit(2).IDs(setContextOp(ctx, ottq.ctx, ent.OpQueryOnlyID)); err != nil {
+		return
+	}
+	switch len(ids) {
+	case 1:
+		id = ids[0]
+	case 0:
+		err = &NotFoundError{onetimetoken.Label}
+	default:
+		err = &NotSingularError{onetimetoken.Label}
+	}
+	return
+}
+
+// OnlyIDX is like OnlyID, but panics if an error occurs.
+func (ottq *OneTimeTokenQuery) OnlyIDX(ctx context.Context) int {
+	id, err := ottq.OnlyID(ctx)
+	if err != nil {
+		panic(err)
+	}
+	return id
+}
+
+// All executes the query and returns a list of OneTimeTokens.
+func (ottq *OneTimeTokenQuery) All(ctx context.Context) ([]*OneTimeToken, error) {
+	ctx = setContextOp(ctx, ottq.ctx, ent.OpQueryAll)
+	if err := ottq.prepareQuery(ctx); err != nil {
+		return nil, err
+	}
+	qr := querierAll[[]*OneTimeToken, *OneTimeTokenQuery]()
+	return withInterceptors[[]*OneTimeToken](ctx, ottq, qr, ottq.inters)
+}
+
+// AllX is like All, but panics if an error occurs.
+func (ottq *OneTimeTokenQuery) AllX(ctx context.Context) []*OneTimeToken {
+	nodes, err := ottq.All(ctx)
+	if err != nil {
+		panic(err)
+	}
+	return nodes
+}
+
+// IDs executes the query and returns a list of OneTimeToken IDs.
+func (ottq *OneTimeTokenQuery) IDs(ctx context.Context) (ids []int, err error) {
+	if ottq.ctx.Unique == nil && ottq.path != nil {
+		ottq.Unique(true)
+	}
+	ctx = setContextOp(ctx, ottq.ctx, ent.OpQueryIDs)
+	if err = ottq.Select(onetimetoken.FieldID).Scan(ctx, &ids); err != nil {
+		return nil, err
+	}
+	return ids, nil
+}
+
+// IDsX is like IDs, but panics if an error occurs.
+func (ottq *OneTimeTokenQuery) IDsX(ctx context.Context) []int {
+	ids, err := ottq.IDs(ctx)
+	if err != nil {
+		panic(err)
+	}
+	return ids
+}
+
+// Count returns the count of the given query.
+func (ottq *OneTimeTokenQuery) Count(ctx context.Context) (int, error) {
+	ctx = setContextOp(ctx, ottq.ctx, ent.OpQueryCount)
+	if err := ottq.prepareQuery(ctx); err != nil {
+		return 0, err
+	}
+	return withInterceptors[int](ctx, ottq, querierCount[*OneTimeTokenQuery](), ottq.inters)
+}
+
+// CountX is like Count, but panics if an error occurs.
+func (ottq *OneTimeTokenQuery) CountX(ctx context.Context) int {
+	count, err := ottq.Count(ctx)
+	if err != nil {
+		panic(err)
+	}
+	return count
+}
+
+// Exist returns true if the query has elements in the graph.
+func (ottq *OneTimeTokenQuery) Exist(ctx context.Context) (bool, error) {
+	ctx = setContextOp(ctx, ottq.ctx, ent.OpQueryExist)
+	switch _, err := ottq.FirstID(ctx); {
+	case IsNotFound(err):
+		return false, nil
+	case err != nil:
+		return false, fmt.Errorf("ent: check existence: %w", err)
+	default:
+		return true, nil
+	}
+}
+
+// ExistX is like Exist, but panics if an error occurs.
+func (ottq *OneTimeTokenQuery) ExistX(ctx context.Context) bool {
+	exist, err := ottq.Exist(ctx)
+	if err != nil {
+		panic(err)
+	}
+	return exist
+}
+
+// Clone returns a duplicate of the OneTimeTokenQuery builder, including all associated steps. It can be
+// used to prepare common query builders and use them differently after the clone is made.
+func (ottq *OneTimeTokenQuery) Clone() *OneTimeTokenQuery {
+	if ottq == nil {
+		return nil
+	}
+	return &OneTimeTokenQuery{
+		config:     ottq.config,
+		ctx:        ottq.ctx.Clone(),
+		order:      append([]onetimetoken.OrderOption{}, ottq.order...),
+		inters:     append([]Interceptor{}, ottq.inters...),
+		predicates: append([]predicate.OneTimeToken{}, ottq.predicates...),
+		withUser:   ottq.withUser.Clone(),
+		// clone intermediate query.
+		sql:  ottq.sql.Clone(),
+		path: ottq.path,
+	}
+}
+
+// WithUser tells the query-builder to eager-load the nodes that are connected to
+// the "user" edge. The optional arguments are used to configure the query builder of the edge.
+func (ottq *OneTimeTokenQuery) WithUser(opts ...func(*UserQuery)) *OneTimeTokenQuery {
+	query := (&UserClient{config: ottq.config}).Query()
+	for _, opt := range opts {
+		opt(query)
+	}
+	ottq.withUser = query
+	return ottq
+}
+
+// GroupBy is used to group vertices by one or more fields/columns.
+// It is often used with aggregate functions, like: count, max, mean, min, sum.
+//
+// Example:
+//
+//	var v []struct {
+//		Purpose onetimetoken.Purpose `json:"purpose,omitempty"`
+//		Count int `json:"count,omitempty"`
+//	}
+//
+//	client.OneTimeToken.Query().
+//		GroupBy(onetimetoken.FieldPurpose).
+//		Aggregate(ent.Count()).
+//		Scan(ctx, &v)
+func (ottq *OneTimeTokenQuery) GroupBy(field string, fields ...string) *OneTimeTokenGroupBy {
+	ottq.ctx.Fields = append([]string{field}, fields...)
+	grbuild := &OneTimeTokenGroupBy{build: ottq}
+	grbuild.flds = &ottq.ctx.Fields
+	grbuild.label = onetimetoken.Label
+	grbuild.scan = grbuild.Scan
+	return grbuild
+}
+
+// Select allows the selection one or more fields/columns for the given query,
+// instead of selecting all fields in the entity.
+//
+// Example:
+//
+//	var v []struct {
+//		Purpose onetimetoken.Purpose `json:"purpose,omitempty"`
+//	}
+//
+//	client.OneTimeToken.Query().
+//		Select(onetimetoken.FieldPurpose).
+//		Scan(ctx, &v)
+func (ottq *OneTimeTokenQuery) Select(fields ...string) *OneTimeTokenSelect {
+	ottq.ctx.Fields = append(ottq.ctx.Fields, fields...)
+	sbuild := &OneTimeTokenSelect{OneTimeTokenQuery: ottq}
+	sbuild.label = onetimetoken.Label
+	sbuild.flds, sbuild.scan = &ottq.ctx.Fields, sbuild.Scan
+	return sbuild
+}
+
+// Aggregate returns a OneTimeTokenSelect configured with the given aggregations.
+func (ottq *OneTimeTokenQuery) Aggregate(fns ...AggregateFunc) *OneTimeTokenSelect {
+	return ottq.Select().Aggregate(fns...)
+}
+
+func (ottq *OneTimeTokenQuery) prepareQuery(ctx context.Context) error {
+	for _, inter := range ottq.inters {
+		if inter == nil {
+			return fmt.Errorf("ent: uninitialized interceptor (forgotten import ent/runtime?)")
+		}
+		if trv, ok := inter.(Traverser); ok {
+			if err := trv.Traverse(ctx, ottq); err != nil {
+				return err
+			}
+		}
+	}
+	for _, f := range ottq.ctx.Fields {
+		if !onetimetoken.ValidColumn(f) {
+			return &ValidationError{Name: f, err: fmt.Errorf("ent: invalid field %q for query", f)}
+		}
+	}
+	if ottq.path != nil {
+		prev, err := ottq.path(ctx)
+		if err != nil {
+			return err
+		}
+		ottq.sql = prev
+	}
+	return nil
+}
+
+func (ottq *OneTimeTokenQuery) sqlAll(ctx context.Context, hooks ...queryHook) ([]*OneTimeToken, error) {
+	var (
+		nodes       = []*OneTimeToken{}
+		withFKs     = ottq.withFKs
+		_spec       = ottq.querySpec()
+		loadedTypes = [1]bool{
+			ottq.withUser != nil,
+		}
+	)
+	if ottq.withUser != nil {
+		withFKs = true
+	}
+	if withFKs {
+		_spec.Node.Columns = append(_spec.Node.Columns, onetimetoken.ForeignKeys...)
+	}
+	_spec.ScanValues = func(columns []string) ([]any, error) {
+		return (*OneTimeToken).scanValues(nil, columns)
+	}
+	_spec.Assign = func(columns []string, values []any) error {
+		node := &OneTimeToken{config: ottq.config}
+		nodes = append(nodes, node)
+		node.Edges.loadedTypes = loadedTypes
+		return node.assignValues(columns, values)
+	}
+	for i := range hooks {
+		hooks[i](ctx, _spec)
+	}
+	if err := sqlgraph.QueryNodes(ctx, ottq.driver, _spec); err != nil {
+		return nil, err
+	}
+	if len(nodes) == 0 {
+		return nodes, nil
+	}
+	if query := ottq.withUser; query != nil {
+		if err := ottq.loadUser(ctx, query, nodes, nil,
+			func(n *OneTimeToken, e *User) { n.Edges.User = e }); err != nil {
+			return nil, err
+		}
+	}
+	return nodes, nil
+}
+
+func (ottq *OneTimeTokenQuery) loadUser(ctx context.Context, query *UserQuery, nodes []*OneTimeToken, init func(*OneTimeToken), assign func(*OneTimeToken, *User)) error {
+	ids := make([]int, 0, len(nodes))
+	nodeids := make(map[int][]*OneTimeToken)
+	for i := range nodes {
+		if nodes[i].user_one_time_tokens == nil {
+			continue
+		}
+		fk := *nodes[i].user_one_time_tokens
+		if _, ok := nodeids[fk]; !ok {
+			ids = append(ids, fk)
+		}
+		nodeids[fk] = append(nodeids[fk], nodes[i])
+	}
+	if len(ids) == 0 {
+		return nil
+	}
+	query.Where(user.IDIn(ids...))
+	neighbors, err := query.All(ctx)
+	if err != nil {
+		return err
+	}
+	for _, n := range neighbors {
+		nodes, ok := nodeids[n.ID]
+		if !ok {
+			return fmt.Errorf(`unexpected foreign-key "user_one_time_tokens" returned %v`, n.ID)
+		}
+		for i := range nodes {
+			assign(nodes[i], n)
+		}
+	}
+	return nil
+}
+
+func (ottq *OneTimeTokenQuery) sqlCount(ctx context.Context) (int, error) {
+	_spec := ottq.querySpec()
+	_spec.Node.Columns = ottq.ctx.Fields
+	if len(ottq.ctx.Fields) > 0 {
+		_spec.Unique = ottq.ctx.Unique != nil && *ottq.ctx.Unique
+	}
+	return sqlgraph.CountNodes(ctx, ottq.driver, _spec)
+}
+
+func (ottq *OneTimeTokenQuery) querySpec() *sqlgraph.QuerySpec {
+	_spec := sqlgraph.NewQuerySpec(onetimetoken.Table, onetimetoken.Columns, sqlgraph.NewFieldSpec(onetimetoken.FieldID, field.TypeInt))
+	_spec.From = ottq.sql
+	if unique := ottq.ctx.Unique; unique != nil {
+		_spec.Unique = *unique
+	} else if ottq.path != nil {
+		_spec.Unique = true
+	}
+	if fields := ottq.ctx.Fields; len(fields) > 0 {
+		_spec.Node.Columns = make([]string, 0, len(fields))
+		_spec.Node.Columns = append(_spec.Node.Columns, onetimetoken.FieldID)
+		for i := range fields {
+			if fields[i] != onetimetoken.FieldID {
+				_spec.Node.Columns = append(_spec.Node.Columns, fields[i])
+			}
+		}
+	}
+	if ps := ottq.predicates; len(ps) > 0 {
+		_spec.Predicate = func(selector *sql.Selector) {
+			for i := range ps {
+				ps[i](selector)
+			}
+		}
+	}
+	if limit := ottq.ctx.Limit; limit != nil {
+		_spec.Limit = *limit
+	}
+	if offset := ottq.ctx.Offset; offset != nil {
+		_spec.Offset = *offset
+	}
+	if ps := ottq.order; len(ps) > 0 {
+		_spec.Order = func(selector *sql.Selector) {
+			for i := range ps {
+				ps[i](selector)
+			}
+		}
+	}
+	return _spec
+}
+
+func (ottq *OneTimeTokenQuery) sqlQuery(ctx context.Context) *sql.Selector {
+	builder := sql.Dialect(ottq.driver.Dialect())
+	t1 := builder.Table(onetimetoken.Table)
+	columns := ottq.ctx.Fields
+	if len(columns) == 0 {
+		columns = onetimetoken.Columns
+	}
+	selector := builder.Select(t1.Columns(columns...)...).From(t1)
+	if ottq.sql != nil {
+		selector = ottq.sql
+		selector.Select(selector.Columns(columns...)...)
+	}
+	if ottq.ctx.Unique != nil && *ottq.ctx.Unique {
+		selector.Distinct()
+	}
+	for _, p := range ottq.predicates {
+		p(selector)
+	}
+	for _, p := range ottq.order {
+		p(selector)
+	}
+	if offset := ottq.ctx.Offset; offset != nil {
+		// limit is mandatory for offset clause. We start
+		// with default value, and override it below if needed.
+		selector.Offset(*offset).Limit(math.MaxInt32)
+	}
+	if limit := ottq.ctx.Limit; limit != nil {
+		selector.Limit(*limit)
+	}
+	return selector
+}
+
+// OneTimeTokenGroupBy is the group-by builder for OneTimeToken entities.
+type OneTimeTokenGroupBy struct {
+	selector
+	build *OneTimeTokenQuery
+}
+
+// Aggregate adds the given aggregation functions to the group-by query.
+func (ottgb *OneTimeTokenGroupBy) Aggregate(fns ...AggregateFunc) *OneTimeTokenGroupBy {
+	ottgb.fns = append(ottgb.fns, fns...)
+	return ottgb
+}
+
+// Scan applies the selector query and scans the result into the given value.
+func (ottgb *OneTimeTokenGroupBy) Scan(ctx context.Context, v any) error {
+	ctx = setContextOp(ctx, ottgb.build.ctx, ent.OpQueryGroupBy)
+	if err := ottgb.build.prepareQuery(ctx); err != nil {
+		return err
+	}
+	return scanWithInterceptors[*OneTimeTokenQuery, *OneTimeTokenGroupBy](ctx, ottgb.build, ottgb, ottgb.build.inters, v)
+}
+
+func (ottgb *OneTimeTokenGroupBy) sqlScan(ctx context.Context, root *OneTimeTokenQuery, v any) error {
+	selector := root.sqlQuery(ctx).Select()
+	aggregation := make([]string, 0, len(ottgb.fns))
+	for _, fn := range ottgb.fns {
+		aggregation = append(aggregation, fn(selector))
+	}
+	if len(selector.SelectedColumns()) == 0 {
+		columns := make([]string, 0, len(*ottgb.flds)+len(ottgb.fns))
+		for _, f := range *ottgb.flds {
+			columns = append(columns, selector.C(f))
+		}
+		columns = append(columns, aggregation...)
+		selector.Select(columns...)
+	}
+	selector.GroupBy(selector.Columns(*ottgb.flds...)...)
+	if err := selector.Err(); err != nil {
+		return err
+	}
+	rows := &sql.Rows{}
+	query, args := selector.Query()
+	if err := ottgb.build.driver.Query(ctx, query, args, rows); err != nil {
+		return err
+	}
+	defer rows.Close()
+	return sql.ScanSlice(rows, v)
+}
+
+// OneTimeTokenSelect is the builder for selecting fields of OneTimeToken entities.
+type OneTimeTokenSelect struct {
+	*OneTimeTokenQuery
+	selector
+}
+
+// Aggregate adds the given aggregation functions to the selector query.
+func (otts *OneTimeTokenSelect) Aggregate(fns ...AggregateFunc) *OneTimeTokenSelect {
+	otts.fns = append(otts.fns, fns...)
+	return otts
+}
+
+// Scan applies the selector query and scans the result into the given value.
+func (otts *OneTimeTokenSelect) Scan(ctx context.Context, v any) error {
+	ctx = setContextOp(ctx, otts.ctx, ent.OpQuerySelect)
+	if err := otts.prepareQuery(ctx); err != nil {
+		return err
+	}
+	return scanWithInterceptors[*OneTimeTokenQuery, *OneTimeTokenSelect](ctx, otts.OneTimeTokenQuery, otts, otts.inters, v)
+}
+
+func (otts *OneTimeTokenSelect) sqlScan(ctx context.Context, root *OneTimeTokenQuery, v any) error {
+	selector := root.sqlQuery(ctx)
+	aggregation := make([]string, 0, len(otts.fns))
+	for _, fn := range otts.fns {
+		aggregation = append(aggregation, fn(selector))
+	}
+	switch n := len(*otts.selector.flds); {
+	case n == 0 && len(aggregation) > 0:
+		selector.Select(aggregation...)
+	case n != 0 && len(aggregation) > 0:
+		selector.AppendSelect(aggregation...)
+	}
+	rows := &sql.Rows{}
+	query, args := selector.Query()
+	if err := otts.driver.Query(ctx, query, args, rows); err != nil {
+		return err
+	}
+	defer rows.Close()
+	return sql.ScanSlice(rows, v)
+}
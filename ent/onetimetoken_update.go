@@ -0,0 +1,581 @@
+// Code generated by ent, DO NOT EDIT.
+
+package ent
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"entgo.io/ent/dialect/sql"
+	"entgo.io/ent/dialect/sql/sqlgraph"
+	"entgo.io/ent/schema/field"
+	"github.com/smxlong/users/ent/onetimetoken"
+	"github.com/smxlong/users/ent/predicate"
+	"github.com/smxlong/users/ent/user"
+)
+
+// OneTimeTokenUpdate is the builder for updating OneTimeToken entities.
+type OneTimeTokenUpdate struct {
+	config
+	hooks    []Hook
+	mutation *OneTimeTokenMutation
+}
+
+// Where appends a list predicates to the OneTimeTokenUpdate builder.
+func (ottu *OneTimeTokenUpdate) Where(ps ...predicate.OneTimeToken) *OneTimeTokenUpdate {
+	ottu.mutation.Where(ps...)
+	return ottu
+}
+
+// SetPurpose sets the "purpose" field.
+func (ottu *OneTimeTokenUpdate) SetPurpose(o onetimetoken.Purpose) *OneTimeTokenUpdate {
+	ottu.mutation.SetPurpose(o)
+	return ottu
+}
+
+// SetNillablePurpose sets the "purpose" field if the given value is not nil.
+func (ottu *OneTimeTokenUpdate) SetNillablePurpose(o *onetimetoken.Purpose) *OneTimeTokenUpdate {
+	if o != nil {
+		ottu.SetPurpose(*o)
+	}
+	return ottu
+}
+
+// SetTokenHash sets the "token_hash" field.
+func (ottu *OneTimeTokenUpdate) SetTokenHash(s string) *OneTimeTokenUpdate {
+	ottu.mutation.SetTokenHash(s)
+	return ottu
+}
+
+// SetNillableTokenHash sets the "token_hash" field if the given value is not nil.
+func (ottu *OneTimeTokenUpdate) SetNillableTokenHash(s *string) *OneTimeTokenUpdate {
+	if s != nil {
+		ottu.SetTokenHash(*s)
+	}
+	return ottu
+}
+
+// SetExpiresAt sets the "expires_at" field.
+func (ottu *OneTimeTokenUpdate) SetExpiresAt(t time.Time) *OneTimeTokenUpdate {
+	ottu.mutation.SetExpiresAt(t)
+	return ottu
+}
+
+// SetNillableExpiresAt sets the "expires_at" field if the given value is not nil.
+func (ottu *OneTimeTokenUpdate) SetNillableExpiresAt(t *time.Time) *OneTimeTokenUpdate {
+	if t != nil {
+		ottu.SetExpiresAt(*t)
+	}
+	return ottu
+}
+
+// SetDeviceHash sets the "device_hash" field.
+func (ottu *OneTimeTokenUpdate) SetDeviceHash(s string) *OneTimeTokenUpdate {
+	ottu.mutation.SetDeviceHash(s)
+	return ottu
+}
+
+// SetNillableDeviceHash sets the "device_hash" field if the given value is not nil.
+func (ottu *OneTimeTokenUpdate) SetNillableDeviceHash(s *string) *OneTimeTokenUpdate {
+	if s != nil {
+		ottu.SetDeviceHash(*s)
+	}
+	return ottu
+}
+
+// ClearDeviceHash clears the value of the "device_hash" field.
+func (ottu *OneTimeTokenUpdate) ClearDeviceHash() *OneTimeTokenUpdate {
+	ottu.mutation.ClearDeviceHash()
+	return ottu
+}
+
+// SetAttempts sets the "attempts" field.
+func (ottu *OneTimeTokenUpdate) SetAttempts(i int) *OneTimeTokenUpdate {
+	ottu.mutation.ResetAttempts()
+	ottu.mutation.SetAttempts(i)
+	return ottu
+}
+
+// SetNillableAttempts sets the "attempts" field if the given value is not nil.
+func (ottu *OneTimeTokenUpdate) SetNillableAttempts(i *int) *OneTimeTokenUpdate {
+	if i != nil {
+		ottu.SetAttempts(*i)
+	}
+	return ottu
+}
+
+// AddAttempts adds i to the "attempts" field.
+func (ottu *OneTimeTokenUpdate) AddAttempts(i int) *OneTimeTokenUpdate {
+	ottu.mutation.AddAttempts(i)
+	return ottu
+}
+
+// SetUsedAt sets the "used_at" field.
+func (ottu *OneTimeTokenUpdate) SetUsedAt(t time.Time) *OneTimeTokenUpdate {
+	ottu.mutation.SetUsedAt(t)
+	return ottu
+}
+
+// SetNillableUsedAt sets the "used_at" field if the given value is not nil.
+func (ottu *OneTimeTokenUpdate) SetNillableUsedAt(t *time.Time) *OneTimeTokenUpdate {
+	if t != nil {
+		ottu.SetUsedAt(*t)
+	}
+	return ottu
+}
+
+// ClearUsedAt clears the value of the "used_at" field.
+func (ottu *OneTimeTokenUpdate) ClearUsedAt() *OneTimeTokenUpdate {
+	ottu.mutation.ClearUsedAt()
+	return ottu
+}
+
+// SetUserID sets the "user" edge to the User entity by ID.
+func (ottu *OneTimeTokenUpdate) SetUserID(id int) *OneTimeTokenUpdate {
+	ottu.mutation.SetUserID(id)
+	return ottu
+}
+
+// SetUser sets the "user" edge to the User entity.
+func (ottu *OneTimeTokenUpdate) SetUser(u *User) *OneTimeTokenUpdate {
+	return ottu.SetUserID(u.ID)
+}
+
+// Mutation returns the OneTimeTokenMutation object of the builder.
+func (ottu *OneTimeTokenUpdate) Mutation() *OneTimeTokenMutation {
+	return ottu.mutation
+}
+
+// ClearUser clears the "user" edge to the User entity.
+func (ottu *OneTimeTokenUpdate) ClearUser() *OneTimeTokenUpdate {
+	ottu.mutation.ClearUser()
+	return ottu
+}
+
+// Save executes the query and returns the number of nodes affected by the update operation.
+func (ottu *OneTimeTokenUpdate) Save(ctx context.Context) (int, error) {
+	return withHooks(ctx, ottu.sqlSave, ottu.mutation, ottu.hooks)
+}
+
+// SaveX is like Save, but panics if an error occurs.
+func (ottu *OneTimeTokenUpdate) SaveX(ctx context.Context) int {
+	affected, err := ottu.Save(ctx)
+	if err != nil {
+		panic(err)
+	}
+	return affected
+}
+
+// Exec executes the query.
+func (ottu *OneTimeTokenUpdate) Exec(ctx context.Context) error {
+	_, err := ottu.Save(ctx)
+	return err
+}
+
+// ExecX is like Exec, but panics if an error occurs.
+func (ottu *OneTimeTokenUpdate) ExecX(ctx context.Context) {
+	if err := ottu.Exec(ctx); err != nil {
+		panic(err)
+	}
+}
+
+// check runs all checks and user-defined validators on the builder.
+func (ottu *OneTimeTokenUpdate) check() error {
+	if v, ok := ottu.mutation.Purpose(); ok {
+		if err := onetimetoken.PurposeValidator(v); err != nil {
+			return &ValidationError{Name: "purpose", err: fmt.Errorf(`ent: validator failed for field "OneTimeToken.purpose": %w`, err)}
+		}
+	}
+	if v, ok := ottu.mutation.TokenHash(); ok {
+		if err := onetimetoken.TokenHashValidator(v); err != nil {
+			return &ValidationError{Name: "token_hash", err: fmt.Errorf(`ent: validator failed for field "OneTimeToken.token_hash": %w`, err)}
+		}
+	}
+	if v, ok := ottu.mutation.Attempts(); ok {
+		if err := onetimetoken.AttemptsValidator(v); err != nil {
+			return &ValidationError{Name: "attempts", err: fmt.Errorf(`ent: validator failed for field "OneTimeToken.attempts": %w`, err)}
+		}
+	}
+	if ottu.mutation.UserCleared() && len(ottu.mutation.UserIDs()) > 0 {
+		return errors.New(`ent: clearing a required unique edge "OneTimeToken.user"`)
+	}
+	return nil
+}
+
+func (ottu *OneTimeTokenUpdate) sqlSave(ctx context.Context) (n int, err error) {
+	if err := ottu.check(); err != nil {
+		return n, err
+	}
+	_spec := sqlgraph.NewUpdateSpec(onetimetoken.Table, onetimetoken.Columns, sqlgraph.NewFieldSpec(onetimetoken.FieldID, field.TypeInt))
+	if ps := ottu.mutation.predicates; len(ps) > 0 {
+		_spec.Predicate = func(selector *sql.Selector) {
+			for i := range ps {
+				ps[i](selector)
+			}
+		}
+	}
+	if value, ok := ottu.mutation.Purpose(); ok {
+		_spec.SetField(onetimetoken.FieldPurpose, field.TypeEnum, value)
+	}
+	if value, ok := ottu.mutation.TokenHash(); ok {
+		_spec.SetField(onetimetoken.FieldTokenHash, field.TypeString, value)
+	}
+	if value, ok := ottu.mutation.ExpiresAt(); ok {
+		_spec.SetField(onetimetoken.FieldExpiresAt, field.TypeTime, value)
+	}
+	if value, ok := ottu.mutation.DeviceHash(); ok {
+		_spec.SetField(onetimetoken.FieldDeviceHash, field.TypeString, value)
+	}
+	if ottu.mutation.DeviceHashCleared() {
+		_spec.ClearField(onetimetoken.FieldDeviceHash, field.TypeString)
+	}
+	if value, ok := ottu.mutation.Attempts(); ok {
+		_spec.SetField(onetimetoken.FieldAttempts, field.TypeInt, value)
+	}
+	if value, ok := ottu.mutation.AddedAttempts(); ok {
+		_spec.AddField(onetimetoken.FieldAttempts, field.TypeInt, value)
+	}
+	if value, ok := ottu.mutation.UsedAt(); ok {
+		_spec.SetField(onetimetoken.FieldUsedAt, field.TypeTime, value)
+	}
+	if ottu.mutation.UsedAtCleared() {
+		_spec.ClearField(onetimetoken.FieldUsedAt, field.TypeTime)
+	}
+	if ottu.mutation.UserCleared() {
+		edge := &sqlgraph.EdgeSpec{
+			Rel:     sqlgraph.M2O,
+			Inverse: true,
+			Table:   onetimetoken.UserTable,
+			Columns: []string{onetimetoken.UserColumn},
+			Bidi:    false,
+			Target: &sqlgraph.EdgeTarget{
+				IDSpec: sqlgraph.NewFieldSpec(user.FieldID, field.TypeInt),
+			},
+		}
+		_spec.Edges.Clear = append(_spec.Edges.Clear, edge)
+	}
+	if nodes := ottu.mutation.UserIDs(); len(nodes) > 0 {
+		edge := &sqlgraph.EdgeSpec{
+			Rel:     sqlgraph.M2O,
+			Inverse: true,
+			Table:   onetimetoken.UserTable,
+			Columns: []string{onetimetoken.UserColumn},
+			Bidi:    false,
+			Target: &sqlgraph.EdgeTarget{
+				IDSpec: sqlgraph.NewFieldSpec(user.FieldID, field.TypeInt),
+			},
+		}
+		for _, k := range nodes {
+			edge.Target.Nodes = append(edge.Target.Nodes, k)
+		}
+		_spec.Edges.Add = append(_spec.Edges.Add, edge)
+	}
+	if n, err = sqlgraph.UpdateNodes(ctx, ottu.driver, _spec); err != nil {
+		if _, ok := err.(*sqlgraph.NotFoundError); ok {
+			err = &NotFoundError{onetimetoken.Label}
+		} else if sqlgraph.IsConstraintError(err) {
+			err = &ConstraintError{msg: err.Error(), wrap: err}
+		}
+		return 0, err
+	}
+	ottu.mutation.done = true
+	return n, nil
+}
+
+// OneTimeTokenUpdateOne is the builder for updating a single OneTimeToken entity.
+type OneTimeTokenUpdateOne struct {
+	config
+	fields   []string
+	hooks    []Hook
+	mutation *OneTimeTokenMutation
+}
+
+// SetPurpose sets the "purpose" field.
+func (ottuo *OneTimeTokenUpdateOne) SetPurpose(o onetimetoken.Purpose) *OneTimeTokenUpdateOne {
+	ottuo.mutation.SetPurpose(o)
+	return ottuo
+}
+
+// SetNillablePurpose sets the "purpose" field if the given value is not nil.
+func (ottuo *OneTimeTokenUpdateOne) SetNillablePurpose(o *onetimetoken.Purpose) *OneTimeTokenUpdateOne {
+	if o != nil {
+		ottuo.SetPurpose(*o)
+	}
+	return ottuo
+}
+
+// SetTokenHash sets the "token_hash" field.
+func (ottuo *OneTimeTokenUpdateOne) SetTokenHash(s string) *OneTimeTokenUpdateOne {
+	ottuo.mutation.SetTokenHash(s)
+	return ottuo
+}
+
+// SetNillableTokenHash sets the "token_hash" field if the given value is not nil.
+func (ottuo *OneTimeTokenUpdateOne) SetNillableTokenHash(s *string) *OneTimeTokenUpdateOne {
+	if s != nil {
+		ottuo.SetTokenHash(*s)
+	}
+	return ottuo
+}
+
+// SetExpiresAt sets the "expires_at" field.
+func (ottuo *OneTimeTokenUpdateOne) SetExpiresAt(t time.Time) *OneTimeTokenUpdateOne {
+	ottuo.mutation.SetExpiresAt(t)
+	return ottuo
+}
+
+// SetNillableExpiresAt sets the "expires_at" field if the given value is not nil.
+func (ottuo *OneTimeTokenUpdateOne) SetNillableExpiresAt(t *time.Time) *OneTimeTokenUpdateOne {
+	if t != nil {
+		ottuo.SetExpiresAt(*t)
+	}
+	return ottuo
+}
+
+// SetDeviceHash sets the "device_hash" field.
+func (ottuo *OneTimeTokenUpdateOne) SetDeviceHash(s string) *OneTimeTokenUpdateOne {
+	ottuo.mutation.SetDeviceHash(s)
+	return ottuo
+}
+
+// SetNillableDeviceHash sets the "device_hash" field if the given value is not nil.
+func (ottuo *OneTimeTokenUpdateOne) SetNillableDeviceHash(s *string) *OneTimeTokenUpdateOne {
+	if s != nil {
+		ottuo.SetDeviceHash(*s)
+	}
+	return ottuo
+}
+
+// ClearDeviceHash clears the value of the "device_hash" field.
+func (ottuo *OneTimeTokenUpdateOne) ClearDeviceHash() *OneTimeTokenUpdateOne {
+	ottuo.mutation.ClearDeviceHash()
+	return ottuo
+}
+
+// SetAttempts sets the "attempts" field.
+func (ottuo *OneTimeTokenUpdateOne) SetAttempts(i int) *OneTimeTokenUpdateOne {
+	ottuo.mutation.ResetAttempts()
+	ottuo.mutation.SetAttempts(i)
+	return ottuo
+}
+
+// SetNillableAttempts sets the "attempts" field if the given value is not nil.
+func (ottuo *OneTimeTokenUpdateOne) SetNillableAttempts(i *int) *OneTimeTokenUpdateOne {
+	if i != nil {
+		ottuo.SetAttempts(*i)
+	}
+	return ottuo
+}
+
+// AddAttempts adds i to the "attempts" field.
+func (ottuo *OneTimeTokenUpdateOne) AddAttempts(i int) *OneTimeTokenUpdateOne {
+	ottuo.mutation.AddAttempts(i)
+	return ottuo
+}
+
+// SetUsedAt sets the "used_at" field.
+func (ottuo *OneTimeTokenUpdateOne) SetUsedAt(t time.Time) *OneTimeTokenUpdateOne {
+	ottuo.mutation.SetUsedAt(t)
+	return ottuo
+}
+
+// SetNillableUsedAt sets the "used_at" field if the given value is not nil.
+func (ottuo *OneTimeTokenUpdateOne) SetNillableUsedAt(t *time.Time) *OneTimeTokenUpdateOne {
+	if t != nil {
+		ottuo.SetUsedAt(*t)
+	}
+	return ottuo
+}
+
+// ClearUsedAt clears the value of the "used_at" field.
+func (ottuo *OneTimeTokenUpdateOne) ClearUsedAt() *OneTimeTokenUpdateOne {
+	ottuo.mutation.ClearUsedAt()
+	return ottuo
+}
+
+// SetUserID sets the "user" edge to the User entity by ID.
+func (ottuo *OneTimeTokenUpdateOne) SetUserID(id int) *OneTimeTokenUpdateOne {
+	ottuo.mutation.SetUserID(id)
+	return ottuo
+}
+
+// SetUser sets the "user" edge to the User entity.
+func (ottuo *OneTimeTokenUpdateOne) SetUser(u *User) *OneTimeTokenUpdateOne {
+	return ottuo.SetUserID(u.ID)
+}
+
+// Mutation returns the OneTimeTokenMutation object of the builder.
+func (ottuo *OneTimeTokenUpdateOne) Mutation() *OneTimeTokenMutation {
+	return ottuo.mutation
+}
+
+// ClearUser clears the "user" edge to the User entity.
+func (ottuo *OneTimeTokenUpdateOne) ClearUser() *OneTimeTokenUpdateOne {
+	ottuo.mutation.ClearUser()
+	return ottuo
+}
+
+// Where appends a list predicates to the OneTimeTokenUpdate builder.
+func (ottuo *OneTimeTokenUpdateOne) Where(ps ...predicate.OneTimeToken) *OneTimeTokenUpdateOne {
+	ottuo.mutation.Where(ps...)
+	return ottuo
+}
+
+// Select allows selecting one or more fields (columns) of the returned entity.
+// The default is selecting all fields defined in the entity schema.
+func (ottuo *OneTimeTokenUpdateOne) Select(field string, fields ...string) *OneTimeTokenUpdateOne {
+	ottuo.fields = append([]string{field}, fields...)
+	return ottuo
+}
+
+// Save executes the query and returns the updated OneTimeToken entity.
+func (ottuo *OneTimeTokenUpdateOne) Save(ctx context.Context) (*OneTimeToken, error) {
+	return withHooks(ctx, ottuo.sqlSave, ottuo.mutation, ottuo.hooks)
+}
+
+// SaveX is like Save, but panics if an error occurs.
+func (ottuo *OneTimeTokenUpdateOne) SaveX(ctx context.Context) *OneTimeToken {
+	node, err := ottuo.Save(ctx)
+	if err != nil {
+		panic(err)
+	}
+	return node
+}
+
+// Exec executes the query on the entity.
+func (ottuo *OneTimeTokenUpdateOne) Exec(ctx context.Context) error {
+	_, err := ottuo.Save(ctx)
+	return err
+}
+
+// ExecX is like Exec, but panics if an error occurs.
+func (ottuo *OneTimeTokenUpdateOne) ExecX(ctx context.Context) {
+	if err := ottuo.Exec(ctx); err != nil {
+		panic(err)
+	}
+}
+
+// check runs all checks and user-defined validators on the builder.
+func (ottuo *OneTimeTokenUpdateOne) check() error {
+	if v, ok := ottuo.mutation.Purpose(); ok {
+		if err := onetimetoken.PurposeValidator(v); err != nil {
+			return &ValidationError{Name: "purpose", err: fmt.Errorf(`ent: validator failed for field "OneTimeToken.purpose": %w`, err)}
+		}
+	}
+	if v, ok := ottuo.mutation.TokenHash(); ok {
+		if err := onetimetoken.TokenHashValidator(v); err != nil {
+			return &ValidationError{Name: "token_hash", err: fmt.Errorf(`ent: validator failed for field "OneTimeToken.token_hash": %w`, err)}
+		}
+	}
+	if v, ok := ottuo.mutation.Attempts(); ok {
+		if err := onetimetoken.AttemptsValidator(v); err != nil {
+			return &ValidationError{Name: "attempts", err: fmt.Errorf(`ent: validator failed for field "OneTimeToken.attempts": %w`, err)}
+		}
+	}
+	if ottuo.mutation.UserCleared() && len(ottuo.mutation.UserIDs()) > 0 {
+		return errors.New(`ent: clearing a required unique edge "OneTimeToken.user"`)
+	}
+	return nil
+}
+
+func (ottuo *OneTimeTokenUpdateOne) sqlSave(ctx context.Context) (_node *OneTimeToken, err error) {
+	if err := ottuo.check(); err != nil {
+		return _node, err
+	}
+	_spec := sqlgraph.NewUpdateSpec(onetimetoken.Table, onetimetoken.Columns, sqlgraph.NewFieldSpec(onetimetoken.FieldID, field.TypeInt))
+	id, ok := ottuo.mutation.ID()
+	if !ok {
+		return nil, &ValidationError{Name: "id", err: errors.New(`ent: missing "OneTimeToken.id" for update`)}
+	}
+	_spec.Node.ID.Value = id
+	if fields := ottuo.fields; len(fields) > 0 {
+		_spec.Node.Columns = make([]string, 0, len(fields))
+		_spec.Node.Columns = append(_spec.Node.Columns, onetimetoken.FieldID)
+		for _, f := range fields {
+			if !onetimetoken.ValidColumn(f) {
+				return nil, &ValidationError{Name: f, err: fmt.Errorf("ent: invalid field %q for query", f)}
+			}
+			if f != onetimetoken.FieldID {
+				_spec.Node.Columns = append(_spec.Node.Columns, f)
+			}
+		}
+	}
+	if ps := ottuo.mutation.predicates; len(ps) > 0 {
+		_spec.Predicate = func(selector *sql.Selector) {
+			for i := range ps {
+				ps[i](selector)
+			}
+		}
+	}
+	if value, ok := ottuo.mutation.Purpose(); ok {
+		_spec.SetField(onetimetoken.FieldPurpose, field.TypeEnum, value)
+	}
+	if value, ok := ottuo.mutation.TokenHash(); ok {
+		_spec.SetField(onetimetoken.FieldTokenHash, field.TypeString, value)
+	}
+	if value, ok := ottuo.mutation.ExpiresAt(); ok {
+		_spec.SetField(onetimetoken.FieldExpiresAt, field.TypeTime, value)
+	}
+	if value, ok := ottuo.mutation.DeviceHash(); ok {
+		_spec.SetField(onetimetoken.FieldDeviceHash, field.TypeString, value)
+	}
+	if ottuo.mutation.DeviceHashCleared() {
+		_spec.ClearField(onetimetoken.FieldDeviceHash, field.TypeString)
+	}
+	if value, ok := ottuo.mutation.Attempts(); ok {
+		_spec.SetField(onetimetoken.FieldAttempts, field.TypeInt, value)
+	}
+	if value, ok := ottuo.mutation.AddedAttempts(); ok {
+		_spec.AddField(onetimetoken.FieldAttempts, field.TypeInt, value)
+	}
+	if value, ok := ottuo.mutation.UsedAt(); ok {
+		_spec.SetField(onetimetoken.FieldUsedAt, field.TypeTime, value)
+	}
+	if ottuo.mutation.UsedAtCleared() {
+		_spec.ClearField(onetimetoken.FieldUsedAt, field.TypeTime)
+	}
+	if ottuo.mutation.UserCleared() {
+		edge := &sqlgraph.EdgeSpec{
+			Rel:     sqlgraph.M2O,
+			Inverse: true,
+			Table:   onetimetoken.UserTable,
+			Columns: []string{onetimetoken.UserColumn},
+			Bidi:    false,
+			Target: &sqlgraph.EdgeTarget{
+				IDSpec: sqlgraph.NewFieldSpec(user.FieldID, field.TypeInt),
+			},
+		}
+		_spec.Edges.Clear = append(_spec.Edges.Clear, edge)
+	}
+	if nodes := ottuo.mutation.UserIDs(); len(nodes) > 0 {
+		edge := &sqlgraph.EdgeSpec{
+			Rel:     sqlgraph.M2O,
+			Inverse: true,
+			Table:   onetimetoken.UserTable,
+			Columns: []string{onetimetoken.UserColumn},
+			Bidi:    false,
+			Target: &sqlgraph.EdgeTarget{
+				IDSpec: sqlgraph.NewFieldSpec(user.FieldID, field.TypeInt),
+			},
+		}
+		for _, k := range nodes {
+			edge.Target.Nodes = append(edge.Target.Nodes, k)
+		}
+		_spec.Edges.Add = append(_spec.Edges.Add, edge)
+	}
+	_node = &OneTimeToken{config: ottuo.config}
+	_spec.Assign = _node.assignValues
+	_spec.ScanValues = _node.scanValues
+	if err = sqlgraph.UpdateNode(ctx, ottuo.driver, _spec); err != nil {
+		if _, ok := err.(*sqlgraph.NotFoundError); ok {
+			err = &NotFoundError{onetimetoken.Label}
+		} else if sqlgraph.IsConstraintError(err) {
+			err = &ConstraintError{msg: err.Error(), wrap: err}
+		}
+		return nil, err
+	}
+	ottuo.mutation.done = true
+	return _node, nil
+}
@@ -6,11 +6,41 @@ import (
 	"entgo.io/ent/dialect/sql"
 )
 
+// Credential is the predicate function for credential builders.
+type Credential func(*sql.Selector)
+
+// Identity is the predicate function for identity builders.
+type Identity func(*sql.Selector)
+
+// Lockout is the predicate function for lockout builders.
+type Lockout func(*sql.Selector)
+
+// MFA is the predicate function for mfa builders.
+type MFA func(*sql.Selector)
+
+// OneTimeToken is the predicate function for onetimetoken builders.
+type OneTimeToken func(*sql.Selector)
+
 // Permission is the predicate function for permission builders.
 type Permission func(*sql.Selector)
 
+// RecoveryCode is the predicate function for recoverycode builders.
+type RecoveryCode func(*sql.Selector)
+
+// RelationTuple is the predicate function for relationtuple builders.
+type RelationTuple func(*sql.Selector)
+
 // Role is the predicate function for role builders.
 type Role func(*sql.Selector)
 
+// RoleAssignment is the predicate function for roleassignment builders.
+type RoleAssignment func(*sql.Selector)
+
 // User is the predicate function for user builders.
 type User func(*sql.Selector)
+
+// UserAttribute is the predicate function for userattribute builders.
+type UserAttribute func(*sql.Selector)
+
+// WebAuthnChallenge is the predicate function for webauthnchallenge builders.
+type WebAuthnChallenge func(*sql.Selector)
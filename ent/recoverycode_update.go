@@ -0,0 +1,387 @@
+// Code generated by ent, DO NOT EDIT.
+
+package ent
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"entgo.io/ent/dialect/sql"
+	"entgo.io/ent/dialect/sql/sqlgraph"
+	"entgo.io/ent/schema/field"
+	"github.com/smxlong/users/ent/predicate"
+	"github.com/smxlong/users/ent/recoverycode"
+	"github.com/smxlong/users/ent/user"
+)
+
+// RecoveryCodeUpdate is the builder for updating RecoveryCode entities.
+type RecoveryCodeUpdate struct {
+	config
+	hooks    []Hook
+	mutation *RecoveryCodeMutation
+}
+
+// Where appends a list predicates to the RecoveryCodeUpdate builder.
+func (rcu *RecoveryCodeUpdate) Where(ps ...predicate.RecoveryCode) *RecoveryCodeUpdate {
+	rcu.mutation.Where(ps...)
+	return rcu
+}
+
+// SetCodeHash sets the "code_hash" field.
+func (rcu *RecoveryCodeUpdate) SetCodeHash(s string) *RecoveryCodeUpdate {
+	rcu.mutation.SetCodeHash(s)
+	return rcu
+}
+
+// SetNillableCodeHash sets the "code_hash" field if the given value is not nil.
+func (rcu *RecoveryCodeUpdate) SetNillableCodeHash(s *string) *RecoveryCodeUpdate {
+	if s != nil {
+		rcu.SetCodeHash(*s)
+	}
+	return rcu
+}
+
+// SetUsedAt sets the "used_at" field.
+func (rcu *RecoveryCodeUpdate) SetUsedAt(t time.Time) *RecoveryCodeUpdate {
+	rcu.mutation.SetUsedAt(t)
+	return rcu
+}
+
+// SetNillableUsedAt sets the "used_at" field if the given value is not nil.
+func (rcu *RecoveryCodeUpdate) SetNillableUsedAt(t *time.Time) *RecoveryCodeUpdate {
+	if t != nil {
+		rcu.SetUsedAt(*t)
+	}
+	return rcu
+}
+
+// ClearUsedAt clears the value of the "used_at" field.
+func (rcu *RecoveryCodeUpdate) ClearUsedAt() *RecoveryCodeUpdate {
+	rcu.mutation.ClearUsedAt()
+	return rcu
+}
+
+// SetUserID sets the "user" edge to the User entity by ID.
+func (rcu *RecoveryCodeUpdate) SetUserID(id int) *RecoveryCodeUpdate {
+	rcu.mutation.SetUserID(id)
+	return rcu
+}
+
+// SetUser sets the "user" edge to the User entity.
+func (rcu *RecoveryCodeUpdate) SetUser(u *User) *RecoveryCodeUpdate {
+	return rcu.SetUserID(u.ID)
+}
+
+// Mutation returns the RecoveryCodeMutation object of the builder.
+func (rcu *RecoveryCodeUpdate) Mutation() *RecoveryCodeMutation {
+	return rcu.mutation
+}
+
+// ClearUser clears the "user" edge to the User entity.
+func (rcu *RecoveryCodeUpdate) ClearUser() *RecoveryCodeUpdate {
+	rcu.mutation.ClearUser()
+	return rcu
+}
+
+// Save executes the query and returns the number of nodes affected by the update operation.
+func (rcu *RecoveryCodeUpdate) Save(ctx context.Context) (int, error) {
+	return withHooks(ctx, rcu.sqlSave, rcu.mutation, rcu.hooks)
+}
+
+// SaveX is like Save, but panics if an error occurs.
+func (rcu *RecoveryCodeUpdate) SaveX(ctx context.Context) int {
+	affected, err := rcu.Save(ctx)
+	if err != nil {
+		panic(err)
+	}
+	return affected
+}
+
+// Exec executes the query.
+func (rcu *RecoveryCodeUpdate) Exec(ctx context.Context) error {
+	_, err := rcu.Save(ctx)
+	return err
+}
+
+// ExecX is like Exec, but panics if an error occurs.
+func (rcu *RecoveryCodeUpdate) ExecX(ctx context.Context) {
+	if err := rcu.Exec(ctx); err != nil {
+		panic(err)
+	}
+}
+
+// check runs all checks and user-defined validators on the builder.
+func (rcu *RecoveryCodeUpdate) check() error {
+	if v, ok := rcu.mutation.CodeHash(); ok {
+		if err := recoverycode.CodeHashValidator(v); err != nil {
+			return &ValidationError{Name: "code_hash", err: fmt.Errorf(`ent: validator failed for field "RecoveryCode.code_hash": %w`, err)}
+		}
+	}
+	if rcu.mutation.UserCleared() && len(rcu.mutation.UserIDs()) > 0 {
+		return errors.New(`ent: clearing a required unique edge "RecoveryCode.user"`)
+	}
+	return nil
+}
+
+func (rcu *RecoveryCodeUpdate) sqlSave(ctx context.Context) (n int, err error) {
+	if err := rcu.check(); err != nil {
+		return n, err
+	}
+	_spec := sqlgraph.NewUpdateSpec(recoverycode.Table, recoverycode.Columns, sqlgraph.NewFieldSpec(recoverycode.FieldID, field.TypeInt))
+	if ps := rcu.mutation.predicates; len(ps) > 0 {
+		_spec.Predicate = func(selector *sql.Selector) {
+			for i := range ps {
+				ps[i](selector)
+			}
+		}
+	}
+	if value, ok := rcu.mutation.CodeHash(); ok {
+		_spec.SetField(recoverycode.FieldCodeHash, field.TypeString, value)
+	}
+	if value, ok := rcu.mutation.UsedAt(); ok {
+		_spec.SetField(recoverycode.FieldUsedAt, field.TypeTime, value)
+	}
+	if rcu.mutation.UsedAtCleared() {
+		_spec.ClearField(recoverycode.FieldUsedAt, field.TypeTime)
+	}
+	if rcu.mutation.UserCleared() {
+		edge := &sqlgraph.EdgeSpec{
+			Rel:     sqlgraph.M2O,
+			Inverse: true,
+			Table:   recoverycode.UserTable,
+			Columns: []string{recoverycode.UserColumn},
+			Bidi:    false,
+			Target: &sqlgraph.EdgeTarget{
+				IDSpec: sqlgraph.NewFieldSpec(user.FieldID, field.TypeInt),
+			},
+		}
+		_spec.Edges.Clear = append(_spec.Edges.Clear, edge)
+	}
+	if nodes := rcu.mutation.UserIDs(); len(nodes) > 0 {
+		edge := &sqlgraph.EdgeSpec{
+			Rel:     sqlgraph.M2O,
+			Inverse: true,
+			Table:   recoverycode.UserTable,
+			Columns: []string{recoverycode.UserColumn},
+			Bidi:    false,
+			Target: &sqlgraph.EdgeTarget{
+				IDSpec: sqlgraph.NewFieldSpec(user.FieldID, field.TypeInt),
+			},
+		}
+		for _, k := range nodes {
+			edge.Target.Nodes = append(edge.Target.Nodes, k)
+		}
+		_spec.Edges.Add = append(_spec.Edges.Add, edge)
+	}
+	if n, err = sqlgraph.UpdateNodes(ctx, rcu.driver, _spec); err != nil {
+		if _, ok := err.(*sqlgraph.NotFoundError); ok {
+			err = &NotFoundError{recoverycode.Label}
+		} else if sqlgraph.IsConstraintError(err) {
+			err = &ConstraintError{msg: err.Error(), wrap: err}
+		}
+		return 0, err
+	}
+	rcu.mutation.done = true
+	return n, nil
+}
+
+// RecoveryCodeUpdateOne is the builder for updating a single RecoveryCode entity.
+type RecoveryCodeUpdateOne struct {
+	config
+	fields   []string
+	hooks    []Hook
+	mutation *RecoveryCodeMutation
+}
+
+// SetCodeHash sets the "code_hash" field.
+func (rcuo *RecoveryCodeUpdateOne) SetCodeHash(s string) *RecoveryCodeUpdateOne {
+	rcuo.mutation.SetCodeHash(s)
+	return rcuo
+}
+
+// SetNillableCodeHash sets the "code_hash" field if the given value is not nil.
+func (rcuo *RecoveryCodeUpdateOne) SetNillableCodeHash(s *string) *RecoveryCodeUpdateOne {
+	if s != nil {
+		rcuo.SetCodeHash(*s)
+	}
+	return rcuo
+}
+
+// SetUsedAt sets the "used_at" field.
+func (rcuo *RecoveryCodeUpdateOne) SetUsedAt(t time.Time) *RecoveryCodeUpdateOne {
+	rcuo.mutation.SetUsedAt(t)
+	return rcuo
+}
+
+// SetNillableUsedAt sets the "used_at" field if the given value is not nil.
+func (rcuo *RecoveryCodeUpdateOne) SetNillableUsedAt(t *time.Time) *RecoveryCodeUpdateOne {
+	if t != nil {
+		rcuo.SetUsedAt(*t)
+	}
+	return rcuo
+}
+
+// ClearUsedAt clears the value of the "used_at" field.
+func (rcuo *RecoveryCodeUpdateOne) ClearUsedAt() *RecoveryCodeUpdateOne {
+	rcuo.mutation.ClearUsedAt()
+	return rcuo
+}
+
+// SetUserID sets the "user" edge to the User entity by ID.
+func (rcuo *RecoveryCodeUpdateOne) SetUserID(id int) *RecoveryCodeUpdateOne {
+	rcuo.mutation.SetUserID(id)
+	return rcuo
+}
+
+// SetUser sets the "user" edge to the User entity.
+func (rcuo *RecoveryCodeUpdateOne) SetUser(u *User) *RecoveryCodeUpdateOne {
+	return rcuo.SetUserID(u.ID)
+}
+
+// Mutation returns the RecoveryCodeMutation object of the builder.
+func (rcuo *RecoveryCodeUpdateOne) Mutation() *RecoveryCodeMutation {
+	return rcuo.mutation
+}
+
+// ClearUser clears the "user" edge to the User entity.
+func (rcuo *RecoveryCodeUpdateOne) ClearUser() *RecoveryCodeUpdateOne {
+	rcuo.mutation.ClearUser()
+	return rcuo
+}
+
+// Where appends a list predicates to the RecoveryCodeUpdate builder.
+func (rcuo *RecoveryCodeUpdateOne) Where(ps ...predicate.RecoveryCode) *RecoveryCodeUpdateOne {
+	rcuo.mutation.Where(ps...)
+	return rcuo
+}
+
+// Select allows selecting one or more fields (columns) of the returned entity.
+// The default is selecting all fields defined in the entity schema.
+func (rcuo *RecoveryCodeUpdateOne) Select(field string, fields ...string) *RecoveryCodeUpdateOne {
+	rcuo.fields = append([]string{field}, fields...)
+	return rcuo
+}
+
+// Save executes the query and returns the updated RecoveryCode entity.
+func (rcuo *RecoveryCodeUpdateOne) Save(ctx context.Context) (*RecoveryCode, error) {
+	return withHooks(ctx, rcuo.sqlSave, rcuo.mutation, rcuo.hooks)
+}
+
+// SaveX is like Save, but panics if an error occurs.
+func (rcuo *RecoveryCodeUpdateOne) SaveX(ctx context.Context) *RecoveryCode {
+	node, err := rcuo.Save(ctx)
+	if err != nil {
+		panic(err)
+	}
+	return node
+}
+
+// Exec executes the query on the entity.
+func (rcuo *RecoveryCodeUpdateOne) Exec(ctx context.Context) error {
+	_, err := rcuo.Save(ctx)
+	return err
+}
+
+// ExecX is like Exec, but panics if an error occurs.
+func (rcuo *RecoveryCodeUpdateOne) ExecX(ctx context.Context) {
+	if err := rcuo.Exec(ctx); err != nil {
+		panic(err)
+	}
+}
+
+// check runs all checks and user-defined validators on the builder.
+func (rcuo *RecoveryCodeUpdateOne) check() error {
+	if v, ok := rcuo.mutation.CodeHash(); ok {
+		if err := recoverycode.CodeHashValidator(v); err != nil {
+			return &ValidationError{Name: "code_hash", err: fmt.Errorf(`ent: validator failed for field "RecoveryCode.code_hash": %w`, err)}
+		}
+	}
+	if rcuo.mutation.UserCleared() && len(rcuo.mutation.UserIDs()) > 0 {
+		return errors.New(`ent: clearing a required unique edge "RecoveryCode.user"`)
+	}
+	return nil
+}
+
+func (rcuo *RecoveryCodeUpdateOne) sqlSave(ctx context.Context) (_node *RecoveryCode, err error) {
+	if err := rcuo.check(); err != nil {
+		return _node, err
+	}
+	_spec := sqlgraph.NewUpdateSpec(recoverycode.Table, recoverycode.Columns, sqlgraph.NewFieldSpec(recoverycode.FieldID, field.TypeInt))
+	id, ok := rcuo.mutation.ID()
+	if !ok {
+		return nil, &ValidationError{Name: "id", err: errors.New(`ent: missing "RecoveryCode.id" for update`)}
+	}
+	_spec.Node.ID.Value = id
+	if fields := rcuo.fields; len(fields) > 0 {
+		_spec.Node.Columns = make([]string, 0, len(fields))
+		_spec.Node.Columns = append(_spec.Node.Columns, recoverycode.FieldID)
+		for _, f := range fields {
+			if !recoverycode.ValidColumn(f) {
+				return nil, &ValidationError{Name: f, err: fmt.Errorf("ent: invalid field %q for query", f)}
+			}
+			if f != recoverycode.FieldID {
+				_spec.Node.Columns = append(_spec.Node.Columns, f)
+			}
+		}
+	}
+	if ps := rcuo.mutation.predicates; len(ps) > 0 {
+		_spec.Predicate = func(selector *sql.Selector) {
+			for i := range ps {
+				ps[i](selector)
+			}
+		}
+	}
+	if value, ok := rcuo.mutation.CodeHash(); ok {
+		_spec.SetField(recoverycode.FieldCodeHash, field.TypeString, value)
+	}
+	if value, ok := rcuo.mutation.UsedAt(); ok {
+		_spec.SetField(recoverycode.FieldUsedAt, field.TypeTime, value)
+	}
+	if rcuo.mutation.UsedAtCleared() {
+		_spec.ClearField(recoverycode.FieldUsedAt, field.TypeTime)
+	}
+	if rcuo.mutation.UserCleared() {
+		edge := &sqlgraph.EdgeSpec{
+			Rel:     sqlgraph.M2O,
+			Inverse: true,
+			Table:   recoverycode.UserTable,
+			Columns: []string{recoverycode.UserColumn},
+			Bidi:    false,
+			Target: &sqlgraph.EdgeTarget{
+				IDSpec: sqlgraph.NewFieldSpec(user.FieldID, field.TypeInt),
+			},
+		}
+		_spec.Edges.Clear = append(_spec.Edges.Clear, edge)
+	}
+	if nodes := rcuo.mutation.UserIDs(); len(nodes) > 0 {
+		edge := &sqlgraph.EdgeSpec{
+			Rel:     sqlgraph.M2O,
+			Inverse: true,
+			Table:   recoverycode.UserTable,
+			Columns: []string{recoverycode.UserColumn},
+			Bidi:    false,
+			Target: &sqlgraph.EdgeTarget{
+				IDSpec: sqlgraph.NewFieldSpec(user.FieldID, field.TypeInt),
+			},
+		}
+		for _, k := range nodes {
+			edge.Target.Nodes = append(edge.Target.Nodes, k)
+		}
+		_spec.Edges.Add = append(_spec.Edges.Add, edge)
+	}
+	_node = &RecoveryCode{config: rcuo.config}
+	_spec.Assign = _node.assignValues
+	_spec.ScanValues = _node.scanValues
+	if err = sqlgraph.UpdateNode(ctx, rcuo.driver, _spec); err != nil {
+		if _, ok := err.(*sqlgraph.NotFoundError); ok {
+			err = &NotFoundError{recoverycode.Label}
+		} else if sqlgraph.IsConstraintError(err) {
+			err = &ConstraintError{msg: err.Error(), wrap: err}
+		}
+		return nil, err
+	}
+	rcuo.mutation.done = true
+	return _node, nil
+}
@@ -0,0 +1,222 @@
+// Code generated by ent, DO NOT EDIT.
+
+package ent
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"entgo.io/ent"
+	"entgo.io/ent/dialect/sql"
+	"github.com/smxlong/users/ent/relationtuple"
+	"github.com/smxlong/users/ent/user"
+)
+
+// RelationTuple is the model entity for the RelationTuple schema.
+type RelationTuple struct {
+	config `json:"-"`
+	// ID of the ent.
+	ID int `json:"id,omitempty"`
+	// CreatedAt holds the value of the "created_at" field.
+	CreatedAt time.Time `json:"created_at,omitempty"`
+	// UpdatedAt holds the value of the "updated_at" field.
+	UpdatedAt time.Time `json:"updated_at,omitempty"`
+	// ObjectType holds the value of the "object_type" field.
+	ObjectType string `json:"object_type,omitempty"`
+	// ObjectID holds the value of the "object_id" field.
+	ObjectID string `json:"object_id,omitempty"`
+	// Relation holds the value of the "relation" field.
+	Relation string `json:"relation,omitempty"`
+	// SubjectType holds the value of the "subject_type" field.
+	SubjectType string `json:"subject_type,omitempty"`
+	// SubjectID holds the value of the "subject_id" field.
+	SubjectID string `json:"subject_id,omitempty"`
+	// SubjectRelation holds the value of the "subject_relation" field.
+	SubjectRelation string `json:"subject_relation,omitempty"`
+	// Edges holds the relations/edges for other nodes in the graph.
+	// The values are being populated by the RelationTupleQuery when eager-loading is set.
+	Edges                RelationTupleEdges `json:"edges"`
+	user_relation_tuples *int
+	selectValues         sql.SelectValues
+}
+
+// RelationTupleEdges holds the relations/edges for other nodes in the graph.
+type RelationTupleEdges struct {
+	// User holds the value of the user edge.
+	User *User `json:"user,omitempty"`
+	// loadedTypes holds the information for reporting if a
+	// type was loaded (or requested) in eager-loading or not.
+	loadedTypes [1]bool
+}
+
+// UserOrErr returns the User value or an error if the edge
+// was not loaded in eager-loading, or loaded but was not found.
+func (e RelationTupleEdges) UserOrErr() (*User, error) {
+	if e.User != nil {
+		return e.User, nil
+	} else if e.loadedTypes[0] {
+		return nil, &NotFoundError{label: user.Label}
+	}
+	return nil, &NotLoadedError{edge: "user"}
+}
+
+// scanValues returns the types for scanning values from sql.Rows.
+func (*RelationTuple) scanValues(columns []string) ([]any, error) {
+	values := make([]any, len(columns))
+	for i := range columns {
+		switch columns[i] {
+		case relationtuple.FieldID:
+			values[i] = new(sql.NullInt64)
+		case relationtuple.FieldObjectType, relationtuple.FieldObjectID, relationtuple.FieldRelation, relationtuple.FieldSubjectType, relationtuple.FieldSubjectID, relationtuple.FieldSubjectRelation:
+			values[i] = new(sql.NullString)
+		case relationtuple.FieldCreatedAt, relationtuple.FieldUpdatedAt:
+			values[i] = new(sql.NullTime)
+		case relationtuple.ForeignKeys[0]: // user_relation_tuples
+			values[i] = new(sql.NullInt64)
+		default:
+			values[i] = new(sql.UnknownType)
+		}
+	}
+	return values, nil
+}
+
+// assignValues assigns the values that were returned from sql.Rows (after scanning)
+// to the RelationTuple fields.
+func (rt *RelationTuple) assignValues(columns []string, values []any) error {
+	if m, n := len(values), len(columns); m < n {
+		return fmt.Errorf("mismatch number of scan values: %d != %d", m, n)
+	}
+	for i := range columns {
+		switch columns[i] {
+		case relationtuple.FieldID:
+			value, ok := values[i].(*sql.NullInt64)
+			if !ok {
+				return fmt.Errorf("unexpected type %T for field id", value)
+			}
+			rt.ID = int(value.Int64)
+		case relationtuple.FieldCreatedAt:
+			if value, ok := values[i].(*sql.NullTime); !ok {
+				return fmt.Errorf("unexpected type %T for field created_at", values[i])
+			} else if value.Valid {
+				rt.CreatedAt = value.Time
+			}
+		case relationtuple.FieldUpdatedAt:
+			if value, ok := values[i].(*sql.NullTime); !ok {
+				return fmt.Errorf("unexpected type %T for field updated_at", values[i])
+			} else if value.Valid {
+				rt.UpdatedAt = value.Time
+			}
+		case relationtuple.FieldObjectType:
+			if value, ok := values[i].(*sql.NullString); !ok {
+				return fmt.Errorf("unexpected type %T for field object_type", values[i])
+			} else if value.Valid {
+				rt.ObjectType = value.String
+			}
+		case relationtuple.FieldObjectID:
+			if value, ok := values[i].(*sql.NullString); !ok {
+				return fmt.Errorf("unexpected type %T for field object_id", values[i])
+			} else if value.Valid {
+				rt.ObjectID = value.String
+			}
+		case relationtuple.FieldRelation:
+			if value, ok := values[i].(*sql.NullString); !ok {
+				return fmt.Errorf("unexpected type %T for field relation", values[i])
+			} else if value.Valid {
+				rt.Relation = value.String
+			}
+		case relationtuple.FieldSubjectType:
+			if value, ok := values[i].(*sql.NullString); !ok {
+				return fmt.Errorf("unexpected type %T for field subject_type", values[i])
+			} else if value.Valid {
+				rt.SubjectType = value.String
+			}
+		case relationtuple.FieldSubjectID:
+			if value, ok := values[i].(*sql.NullString); !ok {
+				return fmt.Errorf("unexpected type %T for field subject_id", values[i])
+			} else if value.Valid {
+				rt.SubjectID = value.String
+			}
+		case relationtuple.FieldSubjectRelation:
+			if value, ok := values[i].(*sql.NullString); !ok {
+				return fmt.Errorf("unexpected type %T for field subject_relation", values[i])
+			} else if value.Valid {
+				rt.SubjectRelation = value.String
+			}
+		case relationtuple.ForeignKeys[0]:
+			if value, ok := values[i].(*sql.NullInt64); !ok {
+				return fmt.Errorf("unexpected type %T for edge-field user_relation_tuples", value)
+			} else if value.Valid {
+				rt.user_relation_tuples = new(int)
+				*rt.user_relation_tuples = int(value.Int64)
+			}
+		default:
+			rt.selectValues.Set(columns[i], values[i])
+		}
+	}
+	return nil
+}
+
+// Value returns the ent.Value that was dynamically selected and assigned to the RelationTuple.
+// This includes values selected through modifiers, order, etc.
+func (rt *RelationTuple) Value(name string) (ent.Value, error) {
+	return rt.selectValues.Get(name)
+}
+
+// QueryUser queries the "user" edge of the RelationTuple entity.
+func (rt *RelationTuple) QueryUser() *UserQuery {
+	return NewRelationTupleClient(rt.config).QueryUser(rt)
+}
+
+// Update returns a builder for updating this RelationTuple.
+// Note that you need to call RelationTuple.Unwrap() before calling this method if this RelationTuple
+// was returned from a transaction, and the transaction was committed or rolled back.
+func (rt *RelationTuple) Update() *RelationTupleUpdateOne {
+	return NewRelationTupleClient(rt.config).UpdateOne(rt)
+}
+
+// Unwrap unwraps the RelationTuple entity that was returned from a transaction after it was closed,
+// so that all future queries will be executed through the driver which created the transaction.
+func (rt *RelationTuple) Unwrap() *RelationTuple {
+	_tx, ok := rt.config.driver.(*txDriver)
+	if !ok {
+		panic("ent: RelationTuple is not a transactional entity")
+	}
+	rt.config.driver = _tx.drv
+	return rt
+}
+
+// String implements the fmt.Stringer.
+func (rt *RelationTuple) String() string {
+	var builder strings.Builder
+	builder.WriteString("RelationTuple(")
+	builder.WriteString(fmt.Sprintf("id=%v, ", rt.ID))
+	builder.WriteString("created_at=")
+	builder.WriteString(rt.CreatedAt.Format(time.ANSIC))
+	builder.WriteString(", ")
+	builder.WriteString("updated_at=")
+	builder.WriteString(rt.UpdatedAt.Format(time.ANSIC))
+	builder.WriteString(", ")
+	builder.WriteString("object_type=")
+	builder.WriteString(rt.ObjectType)
+	builder.WriteString(", ")
+	builder.WriteString("object_id=")
+	builder.WriteString(rt.ObjectID)
+	builder.WriteString(", ")
+	builder.WriteString("relation=")
+	builder.WriteString(rt.Relation)
+	builder.WriteString(", ")
+	builder.WriteString("subject_type=")
+	builder.WriteString(rt.SubjectType)
+	builder.WriteString(", ")
+	builder.WriteString("subject_id=")
+	builder.WriteString(rt.SubjectID)
+	builder.WriteString(", ")
+	builder.WriteString("subject_relation=")
+	builder.WriteString(rt.SubjectRelation)
+	builder.WriteByte(')')
+	return builder.String()
+}
+
+// RelationTuples is a parsable slice of RelationTuple.
+type RelationTuples []*RelationTuple
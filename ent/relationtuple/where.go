@@ -0,0 +1,604 @@
+// Code generated by ent, DO NOT EDIT.
+
+package relationtuple
+
+import (
+	"time"
+
+	"entgo.io/ent/dialect/sql"
+	"entgo.io/ent/dialect/sql/sqlgraph"
+	"github.com/smxlong/users/ent/predicate"
+)
+
+// ID filters vertices based on their ID field.
+func ID(id int) predicate.RelationTuple {
+	return predicate.RelationTuple(sql.FieldEQ(FieldID, id))
+}
+
+// IDEQ applies the EQ predicate on the ID field.
+func IDEQ(id int) predicate.RelationTuple {
+	return predicate.RelationTuple(sql.FieldEQ(FieldID, id))
+}
+
+// IDNEQ applies the NEQ predicate on the ID field.
+func IDNEQ(id int) predicate.RelationTuple {
+	return predicate.RelationTuple(sql.FieldNEQ(FieldID, id))
+}
+
+// IDIn applies the In predicate on the ID field.
+func IDIn(ids ...int) predicate.RelationTuple {
+	return predicate.RelationTuple(sql.FieldIn(FieldID, ids...))
+}
+
+// IDNotIn applies the NotIn predicate on the ID field.
+func IDNotIn(ids ...int) predicate.RelationTuple {
+	return predicate.RelationTuple(sql.FieldNotIn(FieldID, ids...))
+}
+
+// IDGT applies the GT predicate on the ID field.
+func IDGT(id int) predicate.RelationTuple {
+	return predicate.RelationTuple(sql.FieldGT(FieldID, id))
+}
+
+// IDGTE applies the GTE predicate on the ID field.
+func IDGTE(id int) predicate.RelationTuple {
+	return predicate.RelationTuple(sql.FieldGTE(FieldID, id))
+}
+
+// IDLT applies the LT predicate on the ID field.
+func IDLT(id int) predicate.RelationTuple {
+	return predicate.RelationTuple(sql.FieldLT(FieldID, id))
+}
+
+// IDLTE applies the LTE predicate on the ID field.
+func IDLTE(id int) predicate.RelationTuple {
+	return predicate.RelationTuple(sql.FieldLTE(FieldID, id))
+}
+
+// CreatedAt applies equality check predicate on the "created_at" field. It's identical to CreatedAtEQ.
+func CreatedAt(v time.Time) predicate.RelationTuple {
+	return predicate.RelationTuple(sql.FieldEQ(FieldCreatedAt, v))
+}
+
+// UpdatedAt applies equality check predicate on the "updated_at" field. It's identical to UpdatedAtEQ.
+func UpdatedAt(v time.Time) predicate.RelationTuple {
+	return predicate.RelationTuple(sql.FieldEQ(FieldUpdatedAt, v))
+}
+
+// ObjectType applies equality check predicate on the "object_type" field. It's identical to ObjectTypeEQ.
+func ObjectType(v string) predicate.RelationTuple {
+	return predicate.RelationTuple(sql.FieldEQ(FieldObjectType, v))
+}
+
+// ObjectID applies equality check predicate on the "object_id" field. It's identical to ObjectIDEQ.
+func ObjectID(v string) predicate.RelationTuple {
+	return predicate.RelationTuple(sql.FieldEQ(FieldObjectID, v))
+}
+
+// Relation applies equality check predicate on the "relation" field. It's identical to RelationEQ.
+func Relation(v string) predicate.RelationTuple {
+	return predicate.RelationTuple(sql.FieldEQ(FieldRelation, v))
+}
+
+// SubjectType applies equality check predicate on the "subject_type" field. It's identical to SubjectTypeEQ.
+func SubjectType(v string) predicate.RelationTuple {
+	return predicate.RelationTuple(sql.FieldEQ(FieldSubjectType, v))
+}
+
+// SubjectID applies equality check predicate on the "subject_id" field. It's identical to SubjectIDEQ.
+func SubjectID(v string) predicate.RelationTuple {
+	return predicate.RelationTuple(sql.FieldEQ(FieldSubjectID, v))
+}
+
+// SubjectRelation applies equality check predicate on the "subject_relation" field. It's identical to SubjectRelationEQ.
+func SubjectRelation(v string) predicate.RelationTuple {
+	return predicate.RelationTuple(sql.FieldEQ(FieldSubjectRelation, v))
+}
+
+// CreatedAtEQ applies the EQ predicate on the "created_at" field.
+func CreatedAtEQ(v time.Time) predicate.RelationTuple {
+	return predicate.RelationTuple(sql.FieldEQ(FieldCreatedAt, v))
+}
+
+// CreatedAtNEQ applies the NEQ predicate on the "created_at" field.
+func CreatedAtNEQ(v time.Time) predicate.RelationTuple {
+	return predicate.RelationTuple(sql.FieldNEQ(FieldCreatedAt, v))
+}
+
+// CreatedAtIn applies the In predicate on the "created_at" field.
+func CreatedAtIn(vs ...time.Time) predicate.RelationTuple {
+	return predicate.RelationTuple(sql.FieldIn(FieldCreatedAt, vs...))
+}
+
+// CreatedAtNotIn applies the NotIn predicate on the "created_at" field.
+func CreatedAtNotIn(vs ...time.Time) predicate.RelationTuple {
+	return predicate.RelationTuple(sql.FieldNotIn(FieldCreatedAt, vs...))
+}
+
+// CreatedAtGT applies the GT predicate on the "created_at" field.
+func CreatedAtGT(v time.Time) predicate.RelationTuple {
+	return predicate.RelationTuple(sql.FieldGT(FieldCreatedAt, v))
+}
+
+// CreatedAtGTE applies the GTE predicate on the "created_at" field.
+func CreatedAtGTE(v time.Time) predicate.RelationTuple {
+	return predicate.RelationTuple(sql.FieldGTE(FieldCreatedAt, v))
+}
+
+// CreatedAtLT applies the LT predicate on the "created_at" field.
+func CreatedAtLT(v time.Time) predicate.RelationTuple {
+	return predicate.RelationTuple(sql.FieldLT(FieldCreatedAt, v))
+}
+
+// CreatedAtLTE applies the LTE predicate on the "created_at" field.
+func CreatedAtLTE(v time.Time) predicate.RelationTuple {
+	return predicate.RelationTuple(sql.FieldLTE(FieldCreatedAt, v))
+}
+
+// UpdatedAtEQ applies the EQ predicate on the "updated_at" field.
+func UpdatedAtEQ(v time.Time) predicate.RelationTuple {
+	return predicate.RelationTuple(sql.FieldEQ(FieldUpdatedAt, v))
+}
+
+// UpdatedAtNEQ applies the NEQ predicate on the "updated_at" field.
+func UpdatedAtNEQ(v time.Time) predicate.RelationTuple {
+	return predicate.RelationTuple(sql.FieldNEQ(FieldUpdatedAt, v))
+}
+
+// UpdatedAtIn applies the In predicate on the "updated_at" field.
+func UpdatedAtIn(vs ...time.Time) predicate.RelationTuple {
+	return predicate.RelationTuple(sql.FieldIn(FieldUpdatedAt, vs...))
+}
+
+// UpdatedAtNotIn applies the NotIn predicate on the "updated_at" field.
+func UpdatedAtNotIn(vs ...time.Time) predicate.RelationTuple {
+	return predicate.RelationTuple(sql.FieldNotIn(FieldUpdatedAt, vs...))
+}
+
+// UpdatedAtGT applies the GT predicate on the "updated_at" field.
+func UpdatedAtGT(v time.Time) predicate.RelationTuple {
+	return predicate.RelationTuple(sql.FieldGT(FieldUpdatedAt, v))
+}
+
+// UpdatedAtGTE applies the GTE predicate on the "updated_at" field.
+func UpdatedAtGTE(v time.Time) predicate.RelationTuple {
+	return predicate.RelationTuple(sql.FieldGTE(FieldUpdatedAt, v))
+}
+
+// UpdatedAtLT applies the LT predicate on the "updated_at" field.
+func UpdatedAtLT(v time.Time) predicate.RelationTuple {
+	return predicate.RelationTuple(sql.FieldLT(FieldUpdatedAt, v))
+}
+
+// UpdatedAtLTE applies the LTE predicate on the "updated_at" field.
+func UpdatedAtLTE(v time.Time) predicate.RelationTuple {
+	return predicate.RelationTuple(sql.FieldLTE(FieldUpdatedAt, v))
+}
+
+// ObjectTypeEQ applies the EQ predicate on the "object_type" field.
+func ObjectTypeEQ(v string) predicate.RelationTuple {
+	return predicate.RelationTuple(sql.FieldEQ(FieldObjectType, v))
+}
+
+// ObjectTypeNEQ applies the NEQ predicate on the "object_type" field.
+func ObjectTypeNEQ(v string) predicate.RelationTuple {
+	return predicate.RelationTuple(sql.FieldNEQ(FieldObjectType, v))
+}
+
+// ObjectTypeIn applies the In predicate on the "object_type" field.
+func ObjectTypeIn(vs ...string) predicate.RelationTuple {
+	return predicate.RelationTuple(sql.FieldIn(FieldObjectType, vs...))
+}
+
+// ObjectTypeNotIn applies the NotIn predicate on the "object_type" field.
+func ObjectTypeNotIn(vs ...string) predicate.RelationTuple {
+	return predicate.RelationTuple(sql.FieldNotIn(FieldObjectType, vs...))
+}
+
+// ObjectTypeGT applies the GT predicate on the "object_type" field.
+func ObjectTypeGT(v string) predicate.RelationTuple {
+	return predicate.RelationTuple(sql.FieldGT(FieldObjectType, v))
+}
+
+// ObjectTypeGTE applies the GTE predicate on the "object_type" field.
+func ObjectTypeGTE(v string) predicate.RelationTuple {
+	return predicate.RelationTuple(sql.FieldGTE(FieldObjectType, v))
+}
+
+// ObjectTypeLT applies the LT predicate on the "object_type" field.
+func ObjectTypeLT(v string) predicate.RelationTuple {
+	return predicate.RelationTuple(sql.FieldLT(FieldObjectType, v))
+}
+
+// ObjectTypeLTE applies the LTE predicate on the "object_type" field.
+func ObjectTypeLTE(v string) predicate.RelationTuple {
+	return predicate.RelationTuple(sql.FieldLTE(FieldObjectType, v))
+}
+
+// ObjectTypeContains applies the Contains predicate on the "object_type" field.
+func ObjectTypeContains(v string) predicate.RelationTuple {
+	return predicate.RelationTuple(sql.FieldContains(FieldObjectType, v))
+}
+
+// ObjectTypeHasPrefix applies the HasPrefix predicate on the "object_type" field.
+func ObjectTypeHasPrefix(v string) predicate.RelationTuple {
+	return predicate.RelationTuple(sql.FieldHasPrefix(FieldObjectType, v))
+}
+
+// ObjectTypeHasSuffix applies the HasSuffix predicate on the "object_type" field.
+func ObjectTypeHasSuffix(v string) predicate.RelationTuple {
+	return predicate.RelationTuple(sql.FieldHasSuffix(FieldObjectType, v))
+}
+
+// ObjectTypeEqualFold applies the EqualFold predicate on the "object_type" field.
+func ObjectTypeEqualFold(v string) predicate.RelationTuple {
+	return predicate.RelationTuple(sql.FieldEqualFold(FieldObjectType, v))
+}
+
+// ObjectTypeContainsFold applies the ContainsFold predicate on the "object_type" field.
+func ObjectTypeContainsFold(v string) predicate.RelationTuple {
+	return predicate.RelationTuple(sql.FieldContainsFold(FieldObjectType, v))
+}
+
+// ObjectIDEQ applies the EQ predicate on the "object_id" field.
+func ObjectIDEQ(v string) predicate.RelationTuple {
+	return predicate.RelationTuple(sql.FieldEQ(FieldObjectID, v))
+}
+
+// ObjectIDNEQ applies the NEQ predicate on the "object_id" field.
+func ObjectIDNEQ(v string) predicate.RelationTuple {
+	return predicate.RelationTuple(sql.FieldNEQ(FieldObjectID, v))
+}
+
+// ObjectIDIn applies the In predicate on the "object_id" field.
+func ObjectIDIn(vs ...string) predicate.RelationTuple {
+	return predicate.RelationTuple(sql.FieldIn(FieldObjectID, vs...))
+}
+
+// ObjectIDNotIn applies the NotIn predicate on the "object_id" field.
+func ObjectIDNotIn(vs ...string) predicate.RelationTuple {
+	return predicate.RelationTuple(sql.FieldNotIn(FieldObjectID, vs...))
+}
+
+// ObjectIDGT applies the GT predicate on the "object_id" field.
+func ObjectIDGT(v string) predicate.RelationTuple {
+	return predicate.RelationTuple(sql.FieldGT(FieldObjectID, v))
+}
+
+// ObjectIDGTE applies the GTE predicate on the "object_id" field.
+func ObjectIDGTE(v string) predicate.RelationTuple {
+	return predicate.RelationTuple(sql.FieldGTE(FieldObjectID, v))
+}
+
+// ObjectIDLT applies the LT predicate on the "object_id" field.
+func ObjectIDLT(v string) predicate.RelationTuple {
+	return predicate.RelationTuple(sql.FieldLT(FieldObjectID, v))
+}
+
+// ObjectIDLTE applies the LTE predicate on the "object_id" field.
+func ObjectIDLTE(v string) predicate.RelationTuple {
+	return predicate.RelationTuple(sql.FieldLTE(FieldObjectID, v))
+}
+
+// ObjectIDContains applies the Contains predicate on the "object_id" field.
+func ObjectIDContains(v string) predicate.RelationTuple {
+	return predicate.RelationTuple(sql.FieldContains(FieldObjectID, v))
+}
+
+// ObjectIDHasPrefix applies the HasPrefix predicate on the "object_id" field.
+func ObjectIDHasPrefix(v string) predicate.RelationTuple {
+	return predicate.RelationTuple(sql.FieldHasPrefix(FieldObjectID, v))
+}
+
+// ObjectIDHasSuffix applies the HasSuffix predicate on the "object_id" field.
+func ObjectIDHasSuffix(v string) predicate.RelationTuple {
+	return predicate.RelationTuple(sql.FieldHasSuffix(FieldObjectID, v))
+}
+
+// ObjectIDEqualFold applies the EqualFold predicate on the "object_id" field.
+func ObjectIDEqualFold(v string) predicate.RelationTuple {
+	return predicate.RelationTuple(sql.FieldEqualFold(FieldObjectID, v))
+}
+
+// ObjectIDContainsFold applies the ContainsFold predicate on the "object_id" field.
+func ObjectIDContainsFold(v string) predicate.RelationTuple {
+	return predicate.RelationTuple(sql.FieldContainsFold(FieldObjectID, v))
+}
+
+// RelationEQ applies the EQ predicate on the "relation" field.
+func RelationEQ(v string) predicate.RelationTuple {
+	return predicate.RelationTuple(sql.FieldEQ(FieldRelation, v))
+}
+
+// RelationNEQ applies the NEQ predicate on the "relation" field.
+func RelationNEQ(v string) predicate.RelationTuple {
+	return predicate.RelationTuple(sql.FieldNEQ(FieldRelation, v))
+}
+
+// RelationIn applies the In predicate on the "relation" field.
+func RelationIn(vs ...string) predicate.RelationTuple {
+	return predicate.RelationTuple(sql.FieldIn(FieldRelation, vs...))
+}
+
+// RelationNotIn applies the NotIn predicate on the "relation" field.
+func RelationNotIn(vs ...string) predicate.RelationTuple {
+	return predicate.RelationTuple(sql.FieldNotIn(FieldRelation, vs...))
+}
+
+// RelationGT applies the GT predicate on the "relation" field.
+func RelationGT(v string) predicate.RelationTuple {
+	return predicate.RelationTuple(sql.FieldGT(FieldRelation, v))
+}
+
+// RelationGTE applies the GTE predicate on the "relation" field.
+func RelationGTE(v string) predicate.RelationTuple {
+	return predicate.RelationTuple(sql.FieldGTE(FieldRelation, v))
+}
+
+// RelationLT applies the LT predicate on the "relation" field.
+func RelationLT(v string) predicate.RelationTuple {
+	return predicate.RelationTuple(sql.FieldLT(FieldRelation, v))
+}
+
+// RelationLTE applies the LTE predicate on the "relation" field.
+func RelationLTE(v string) predicate.RelationTuple {
+	return predicate.RelationTuple(sql.FieldLTE(FieldRelation, v))
+}
+
+// RelationContains applies the Contains predicate on the "relation" field.
+func RelationContains(v string) predicate.RelationTuple {
+	return predicate.RelationTuple(sql.FieldContains(FieldRelation, v))
+}
+
+// RelationHasPrefix applies the HasPrefix predicate on the "relation" field.
+func RelationHasPrefix(v string) predicate.RelationTuple {
+	return predicate.RelationTuple(sql.FieldHasPrefix(FieldRelation, v))
+}
+
+// RelationHasSuffix applies the HasSuffix predicate on the "relation" field.
+func RelationHasSuffix(v string) predicate.RelationTuple {
+	return predicate.RelationTuple(sql.FieldHasSuffix(FieldRelation, v))
+}
+
+// RelationEqualFold applies the EqualFold predicate on the "relation" field.
+func RelationEqualFold(v string) predicate.RelationTuple {
+	return predicate.RelationTuple(sql.FieldEqualFold(FieldRelation, v))
+}
+
+// RelationContainsFold applies the ContainsFold predicate on the "relation" field.
+func RelationContainsFold(v string) predicate.RelationTuple {
+	return predicate.RelationTuple(sql.FieldContainsFold(FieldRelation, v))
+}
+
+// SubjectTypeEQ applies the EQ predicate on the "subject_type" field.
+func SubjectTypeEQ(v string) predicate.RelationTuple {
+	return predicate.RelationTuple(sql.FieldEQ(FieldSubjectType, v))
+}
+
+// SubjectTypeNEQ applies the NEQ predicate on the "subject_type" field.
+func SubjectTypeNEQ(v string) predicate.RelationTuple {
+	return predicate.RelationTuple(sql.FieldNEQ(FieldSubjectType, v))
+}
+
+// SubjectTypeIn applies the In predicate on the "subject_type" field.
+func SubjectTypeIn(vs ...string) predicate.RelationTuple {
+	return predicate.RelationTuple(sql.FieldIn(FieldSubjectType, vs...))
+}
+
+// SubjectTypeNotIn applies the NotIn predicate on the "subject_type" field.
+func SubjectTypeNotIn(vs ...string) predicate.RelationTuple {
+	return predicate.RelationTuple(sql.FieldNotIn(FieldSubjectType, vs...))
+}
+
+// SubjectTypeGT applies the GT predicate on the "subject_type" field.
+func SubjectTypeGT(v string) predicate.RelationTuple {
+	return predicate.RelationTuple(sql.FieldGT(FieldSubjectType, v))
+}
+
+// SubjectTypeGTE applies the GTE predicate on the "subject_type" field.
+func SubjectTypeGTE(v string) predicate.RelationTuple {
+	return predicate.RelationTuple(sql.FieldGTE(FieldSubjectType, v))
+}
+
+// SubjectTypeLT applies the LT predicate on the "subject_type" field.
+func SubjectTypeLT(v string) predicate.RelationTuple {
+	return predicate.RelationTuple(sql.FieldLT(FieldSubjectType, v))
+}
+
+// SubjectTypeLTE applies the LTE predicate on the "subject_type" field.
+func SubjectTypeLTE(v string) predicate.RelationTuple {
+	return predicate.RelationTuple(sql.FieldLTE(FieldSubjectType, v))
+}
+
+// SubjectTypeContains applies the Contains predicate on the "subject_type" field.
+func SubjectTypeContains(v string) predicate.RelationTuple {
+	return predicate.RelationTuple(sql.FieldContains(FieldSubjectType, v))
+}
+
+// SubjectTypeHasPrefix applies the HasPrefix predicate on the "subject_type" field.
+func SubjectTypeHasPrefix(v string) predicate.RelationTuple {
+	return predicate.RelationTuple(sql.FieldHasPrefix(FieldSubjectType, v))
+}
+
+// SubjectTypeHasSuffix applies the HasSuffix predicate on the "subject_type" field.
+func SubjectTypeHasSuffix(v string) predicate.RelationTuple {
+	return predicate.RelationTuple(sql.FieldHasSuffix(FieldSubjectType, v))
+}
+
+// SubjectTypeEqualFold applies the EqualFold predicate on the "subject_type" field.
+func SubjectTypeEqualFold(v string) predicate.RelationTuple {
+	return predicate.RelationTuple(sql.FieldEqualFold(FieldSubjectType, v))
+}
+
+// SubjectTypeContainsFold applies the ContainsFold predicate on the "subject_type" field.
+func SubjectTypeContainsFold(v string) predicate.RelationTuple {
+	return predicate.RelationTuple(sql.FieldContainsFold(FieldSubjectType, v))
+}
+
+// SubjectIDEQ applies the EQ predicate on the "subject_id" field.
+func SubjectIDEQ(v string) predicate.RelationTuple {
+	return predicate.RelationTuple(sql.FieldEQ(FieldSubjectID, v))
+}
+
+// SubjectIDNEQ applies the NEQ predicate on the "subject_id" field.
+func SubjectIDNEQ(v string) predicate.RelationTuple {
+	return predicate.RelationTuple(sql.FieldNEQ(FieldSubjectID, v))
+}
+
+// SubjectIDIn applies the In predicate on the "subject_id" field.
+func SubjectIDIn(vs ...string) predicate.RelationTuple {
+	return predicate.RelationTuple(sql.FieldIn(FieldSubjectID, vs...))
+}
+
+// SubjectIDNotIn applies the NotIn predicate on the "subject_id" field.
+func SubjectIDNotIn(vs ...string) predicate.RelationTuple {
+	return predicate.RelationTuple(sql.FieldNotIn(FieldSubjectID, vs...))
+}
+
+// SubjectIDGT applies the GT predicate on the "subject_id" field.
+func SubjectIDGT(v string) predicate.RelationTuple {
+	return predicate.RelationTuple(sql.FieldGT(FieldSubjectID, v))
+}
+
+// SubjectIDGTE applies the GTE predicate on the "subject_id" field.
+func SubjectIDGTE(v string) predicate.RelationTuple {
+	return predicate.RelationTuple(sql.FieldGTE(FieldSubjectID, v))
+}
+
+// SubjectIDLT applies the LT predicate on the "subject_id" field.
+func SubjectIDLT(v string) predicate.RelationTuple {
+	return predicate.RelationTuple(sql.FieldLT(FieldSubjectID, v))
+}
+
+// SubjectIDLTE applies the LTE predicate on the "subject_id" field.
+func SubjectIDLTE(v string) predicate.RelationTuple {
+	return predicate.RelationTuple(sql.FieldLTE(FieldSubjectID, v))
+}
+
+// SubjectIDContains applies the Contains predicate on the "subject_id" field.
+func SubjectIDContains(v string) predicate.RelationTuple {
+	return predicate.RelationTuple(sql.FieldContains(FieldSubjectID, v))
+}
+
+// SubjectIDHasPrefix applies the HasPrefix predicate on the "subject_id" field.
+func SubjectIDHasPrefix(v string) predicate.RelationTuple {
+	return predicate.RelationTuple(sql.FieldHasPrefix(FieldSubjectID, v))
+}
+
+// SubjectIDHasSuffix applies the HasSuffix predicate on the "subject_id" field.
+func SubjectIDHasSuffix(v string) predicate.RelationTuple {
+	return predicate.RelationTuple(sql.FieldHasSuffix(FieldSubjectID, v))
+}
+
+// SubjectIDEqualFold applies the EqualFold predicate on the "subject_id" field.
+func SubjectIDEqualFold(v string) predicate.RelationTuple {
+	return predicate.RelationTuple(sql.FieldEqualFold(FieldSubjectID, v))
+}
+
+// SubjectIDContainsFold applies the ContainsFold predicate on the "subject_id" field.
+func SubjectIDContainsFold(v string) predicate.RelationTuple {
+	return predicate.RelationTuple(sql.FieldContainsFold(FieldSubjectID, v))
+}
+
+// SubjectRelationEQ applies the EQ predicate on the "subject_relation" field.
+func SubjectRelationEQ(v string) predicate.RelationTuple {
+	return predicate.RelationTuple(sql.FieldEQ(FieldSubjectRelation, v))
+}
+
+// SubjectRelationNEQ applies the NEQ predicate on the "subject_relation" field.
+func SubjectRelationNEQ(v string) predicate.RelationTuple {
+	return predicate.RelationTuple(sql.FieldNEQ(FieldSubjectRelation, v))
+}
+
+// SubjectRelationIn applies the In predicate on the "subject_relation" field.
+func SubjectRelationIn(vs ...string) predicate.RelationTuple {
+	return predicate.RelationTuple(sql.FieldIn(FieldSubjectRelation, vs...))
+}
+
+// SubjectRelationNotIn applies the NotIn predicate on the "subject_relation" field.
+func SubjectRelationNotIn(vs ...string) predicate.RelationTuple {
+	return predicate.RelationTuple(sql.FieldNotIn(FieldSubjectRelation, vs...))
+}
+
+// SubjectRelationGT applies the GT predicate on the "subject_relation" field.
+func SubjectRelationGT(v string) predicate.RelationTuple {
+	return predicate.RelationTuple(sql.FieldGT(FieldSubjectRelation, v))
+}
+
+// SubjectRelationGTE applies the GTE predicate on the "subject_relation" field.
+func SubjectRelationGTE(v string) predicate.RelationTuple {
+	return predicate.RelationTuple(sql.FieldGTE(FieldSubjectRelation, v))
+}
+
+// SubjectRelationLT applies the LT predicate on the "subject_relation" field.
+func SubjectRelationLT(v string) predicate.RelationTuple {
+	return predicate.RelationTuple(sql.FieldLT(FieldSubjectRelation, v))
+}
+
+// SubjectRelationLTE applies the LTE predicate on the "subject_relation" field.
+func SubjectRelationLTE(v string) predicate.RelationTuple {
+	return predicate.RelationTuple(sql.FieldLTE(FieldSubjectRelation, v))
+}
+
+// SubjectRelationContains applies the Contains predicate on the "subject_relation" field.
+func SubjectRelationContains(v string) predicate.RelationTuple {
+	return predicate.RelationTuple(sql.FieldContains(FieldSubjectRelation, v))
+}
+
+// SubjectRelationHasPrefix applies the HasPrefix predicate on the "subject_relation" field.
+func SubjectRelationHasPrefix(v string) predicate.RelationTuple {
+	return predicate.RelationTuple(sql.FieldHasPrefix(FieldSubjectRelation, v))
+}
+
+// SubjectRelationHasSuffix applies the HasSuffix predicate on the "subject_relation" field.
+func SubjectRelationHasSuffix(v string) predicate.RelationTuple {
+	return predicate.RelationTuple(sql.FieldHasSuffix(FieldSubjectRelation, v))
+}
+
+// SubjectRelationEqualFold applies the EqualFold predicate on the "subject_relation" field.
+func SubjectRelationEqualFold(v string) predicate.RelationTuple {
+	return predicate.RelationTuple(sql.FieldEqualFold(FieldSubjectRelation, v))
+}
+
+// SubjectRelationContainsFold applies the ContainsFold predicate on the "subject_relation" field.
+func SubjectRelationContainsFold(v string) predicate.RelationTuple {
+	return predicate.RelationTuple(sql.FieldContainsFold(FieldSubjectRelation, v))
+}
+
+// HasUser applies the HasEdge predicate on the "user" edge.
+func HasUser() predicate.RelationTuple {
+	return predicate.RelationTuple(func(s *sql.Selector) {
+		step := sqlgraph.NewStep(
+			sqlgraph.From(Table, FieldID),
+			sqlgraph.Edge(sqlgraph.M2O, true, UserTable, UserColumn),
+		)
+		sqlgraph.HasNeighbors(s, step)
+	})
+}
+
+// HasUserWith applies the HasEdge predicate on the "user" edge with a given conditions (other predicates).
+func HasUserWith(preds ...predicate.User) predicate.RelationTuple {
+	return predicate.RelationTuple(func(s *sql.Selector) {
+		step := newUserStep()
+		sqlgraph.HasNeighborsWith(s, step, func(s *sql.Selector) {
+			for _, p := range preds {
+				p(s)
+			}
+		})
+	})
+}
+
+// And groups predicates with the AND operator between them.
+func And(predicates ...predicate.RelationTuple) predicate.RelationTuple {
+	return predicate.RelationTuple(sql.AndPredicates(predicates...))
+}
+
+// Or groups predicates with the OR operator between them.
+func Or(predicates ...predicate.RelationTuple) predicate.RelationTuple {
+	return predicate.RelationTuple(sql.OrPredicates(predicates...))
+}
+
+// Not applies the not operator on the given predicate.
+func Not(p predicate.RelationTuple) predicate.RelationTuple {
+	return predicate.RelationTuple(sql.NotPredicates(p))
+}
@@ -0,0 +1,358 @@
+// Code generated by ent, DO NOT EDIT.
+
+package ent
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"entgo.io/ent/dialect/sql/sqlgraph"
+	"entgo.io/ent/schema/field"
+	"github.com/smxlong/users/ent/relationtuple"
+	"github.com/smxlong/users/ent/user"
+)
+
+// RelationTupleCreate is the builder for creating a RelationTuple entity.
+type RelationTupleCreate struct {
+	config
+	mutation *RelationTupleMutation
+	hooks    []Hook
+}
+
+// SetCreatedAt sets the "created_at" field.
+func (rtc *RelationTupleCreate) SetCreatedAt(t time.Time) *RelationTupleCreate {
+	rtc.mutation.SetCreatedAt(t)
+	return rtc
+}
+
+// SetUpdatedAt sets the "updated_at" field.
+func (rtc *RelationTupleCreate) SetUpdatedAt(t time.Time) *RelationTupleCreate {
+	rtc.mutation.SetUpdatedAt(t)
+	return rtc
+}
+
+// SetObjectType sets the "object_type" field.
+func (rtc *RelationTupleCreate) SetObjectType(s string) *RelationTupleCreate {
+	rtc.mutation.SetObjectType(s)
+	return rtc
+}
+
+// SetObjectID sets the "object_id" field.
+func (rtc *RelationTupleCreate) SetObjectID(s string) *RelationTupleCreate {
+	rtc.mutation.SetObjectID(s)
+	return rtc
+}
+
+// SetRelation sets the "relation" field.
+func (rtc *RelationTupleCreate) SetRelation(s string) *RelationTupleCreate {
+	rtc.mutation.SetRelation(s)
+	return rtc
+}
+
+// SetSubjectType sets the "subject_type" field.
+func (rtc *RelationTupleCreate) SetSubjectType(s string) *RelationTupleCreate {
+	rtc.mutation.SetSubjectType(s)
+	return rtc
+}
+
+// SetSubjectID sets the "subject_id" field.
+func (rtc *RelationTupleCreate) SetSubjectID(s string) *RelationTupleCreate {
+	rtc.mutation.SetSubjectID(s)
+	return rtc
+}
+
+// SetSubjectRelation sets the "subject_relation" field.
+func (rtc *RelationTupleCreate) SetSubjectRelation(s string) *RelationTupleCreate {
+	rtc.mutation.SetSubjectRelation(s)
+	return rtc
+}
+
+// SetNillableSubjectRelation sets the "subject_relation" field if the given value is not nil.
+func (rtc *RelationTupleCreate) SetNillableSubjectRelation(s *string) *RelationTupleCreate {
+	if s != nil {
+		rtc.SetSubjectRelation(*s)
+	}
+	return rtc
+}
+
+// SetUserID sets the "user" edge to the User entity by ID.
+func (rtc *RelationTupleCreate) SetUserID(id int) *RelationTupleCreate {
+	rtc.mutation.SetUserID(id)
+	return rtc
+}
+
+// SetNillableUserID sets the "user" edge to the User entity by ID if the given value is not nil.
+func (rtc *RelationTupleCreate) SetNillableUserID(id *int) *RelationTupleCreate {
+	if id != nil {
+		rtc = rtc.SetUserID(*id)
+	}
+	return rtc
+}
+
+// SetUser sets the "user" edge to the User entity.
+func (rtc *RelationTupleCreate) SetUser(u *User) *RelationTupleCreate {
+	return rtc.SetUserID(u.ID)
+}
+
+// Mutation returns the RelationTupleMutation object of the builder.
+func (rtc *RelationTupleCreate) Mutation() *RelationTupleMutation {
+	return rtc.mutation
+}
+
+// Save creates the RelationTuple in the database.
+func (rtc *RelationTupleCreate) Save(ctx context.Context) (*RelationTuple, error) {
+	if err := rtc.defaults(); err != nil {
+		return nil, err
+	}
+	return withHooks(ctx, rtc.sqlSave, rtc.mutation, rtc.hooks)
+}
+
+// SaveX calls Save and panics if Save returns an error.
+func (rtc *RelationTupleCreate) SaveX(ctx context.Context) *RelationTuple {
+	v, err := rtc.Save(ctx)
+	if err != nil {
+		panic(err)
+	}
+	return v
+}
+
+// Exec executes the query.
+func (rtc *RelationTupleCreate) Exec(ctx context.Context) error {
+	_, err := rtc.Save(ctx)
+	return err
+}
+
+// ExecX is like Exec, but panics if an error occurs.
+func (rtc *RelationTupleCreate) ExecX(ctx context.Context) {
+	if err := rtc.Exec(ctx); err != nil {
+		panic(err)
+	}
+}
+
+// defaults sets the default values of the builder before save.
+func (rtc *RelationTupleCreate) defaults() error {
+	if _, ok := rtc.mutation.SubjectRelation(); !ok {
+		v := relationtuple.DefaultSubjectRelation
+		rtc.mutation.SetSubjectRelation(v)
+	}
+	return nil
+}
+
+// check runs all checks and user-defined validators on the builder.
+func (rtc *RelationTupleCreate) check() error {
+	if _, ok := rtc.mutation.CreatedAt(); !ok {
+		return &ValidationError{Name: "created_at", err: errors.New(`ent: missing required field "RelationTuple.created_at"`)}
+	}
+	if _, ok := rtc.mutation.UpdatedAt(); !ok {
+		return &ValidationError{Name: "updated_at", err: errors.New(`ent: missing required field "RelationTuple.updated_at"`)}
+	}
+	if _, ok := rtc.mutation.ObjectType(); !ok {
+		return &ValidationError{Name: "object_type", err: errors.New(`ent: missing required field "RelationTuple.object_type"`)}
+	}
+	if v, ok := rtc.mutation.ObjectType(); ok {
+		if err := relationtuple.ObjectTypeValidator(v); err != nil {
+			return &ValidationError{Name: "object_type", err: fmt.Errorf(`ent: validator failed for field "RelationTuple.object_type": %w`, err)}
+		}
+	}
+	if _, ok := rtc.mutation.ObjectID(); !ok {
+		return &ValidationError{Name: "object_id", err: errors.New(`ent: missing required field "RelationTuple.object_id"`)}
+	}
+	if v, ok := rtc.mutation.ObjectID(); ok {
+		if err := relationtuple.ObjectIDValidator(v); err != nil {
+			return &ValidationError{Name: "object_id", err: fmt.Errorf(`ent: validator failed for field "RelationTuple.object_id": %w`, err)}
+		}
+	}
+	if _, ok := rtc.mutation.Relation(); !ok {
+		return &ValidationError{Name: "relation", err: errors.New(`ent: missing required field "RelationTuple.relation"`)}
+	}
+	if v, ok := rtc.mutation.Relation(); ok {
+		if err := relationtuple.RelationValidator(v); err != nil {
+			return &ValidationError{Name: "relation", err: fmt.Errorf(`ent: validator failed for field "RelationTuple.relation": %w`, err)}
+		}
+	}
+	if _, ok := rtc.mutation.SubjectType(); !ok {
+		return &ValidationError{Name: "subject_type", err: errors.New(`ent: missing required field "RelationTuple.subject_type"`)}
+	}
+	if v, ok := rtc.mutation.SubjectType(); ok {
+		if err := relationtuple.SubjectTypeValidator(v); err != nil {
+			return &ValidationError{Name: "subject_type", err: fmt.Errorf(`ent: validator failed for field "RelationTuple.subject_type": %w`, err)}
+		}
+	}
+	if _, ok := rtc.mutation.SubjectID(); !ok {
+		return &ValidationError{Name: "subject_id", err: errors.New(`ent: missing required field "RelationTuple.subject_id"`)}
+	}
+	if v, ok := rtc.mutation.SubjectID(); ok {
+		if err := relationtuple.SubjectIDValidator(v); err != nil {
+			return &ValidationError{Name: "subject_id", err: fmt.Errorf(`ent: validator failed for field "RelationTuple.subject_id": %w`, err)}
+		}
+	}
+	if _, ok := rtc.mutation.SubjectRelation(); !ok {
+		return &ValidationError{Name: "subject_relation", err: errors.New(`ent: missing required field "RelationTuple.subject_relation"`)}
+	}
+	return nil
+}
+
+func (rtc *RelationTupleCreate) sqlSave(ctx context.Context) (*RelationTuple, error) {
+	if err := rtc.check(); err != nil {
+		return nil, err
+	}
+	_node, _spec := rtc.createSpec()
+	if err := sqlgraph.CreateNode(ctx, rtc.driver, _spec); err != nil {
+		if sqlgraph.IsConstraintError(err) {
+			err = &ConstraintError{msg: err.Error(), wrap: err}
+		}
+		return nil, err
+	}
+	id := _spec.ID.Value.(int64)
+	_node.ID = int(id)
+	rtc.mutation.id = &_node.ID
+	rtc.mutation.done = true
+	return _node, nil
+}
+
+func (rtc *RelationTupleCreate) createSpec() (*RelationTuple, *sqlgraph.CreateSpec) {
+	var (
+		_node = &RelationTuple{config: rtc.config}
+		_spec = sqlgraph.NewCreateSpec(relationtuple.Table, sqlgraph.NewFieldSpec(relationtuple.FieldID, field.TypeInt))
+	)
+	if value, ok := rtc.mutation.CreatedAt(); ok {
+		_spec.SetField(relationtuple.FieldCreatedAt, field.TypeTime, value)
+		_node.CreatedAt = value
+	}
+	if value, ok := rtc.mutation.UpdatedAt(); ok {
+		_spec.SetField(relationtuple.FieldUpdatedAt, field.TypeTime, value)
+		_node.UpdatedAt = value
+	}
+	if value, ok := rtc.mutation.ObjectType(); ok {
+		_spec.SetField(relationtuple.FieldObjectType, field.TypeString, value)
+		_node.ObjectType = value
+	}
+	if value, ok := rtc.mutation.ObjectID(); ok {
+		_spec.SetField(relationtuple.FieldObjectID, field.TypeString, value)
+		_node.ObjectID = value
+	}
+	if value, ok := rtc.mutation.Relation(); ok {
+		_spec.SetField(relationtuple.FieldRelation, field.TypeString, value)
+		_node.Relation = value
+	}
+	if value, ok := rtc.mutation.SubjectType(); ok {
+		_spec.SetField(relationtuple.FieldSubjectType, field.TypeString, value)
+		_node.SubjectType = value
+	}
+	if value, ok := rtc.mutation.SubjectID(); ok {
+		_spec.SetField(relationtuple.FieldSubjectID, field.TypeString, value)
+		_node.SubjectID = value
+	}
+	if value, ok := rtc.mutation.SubjectRelation(); ok {
+		_spec.SetField(relationtuple.FieldSubjectRelation, field.TypeString, value)
+		_node.SubjectRelation = value
+	}
+	if nodes := rtc.mutation.UserIDs(); len(nodes) > 0 {
+		edge := &sqlgraph.EdgeSpec{
+			Rel:     sqlgraph.M2O,
+			Inverse: true,
+			Table:   relationtuple.UserTable,
+			Columns: []string{relationtuple.UserColumn},
+			Bidi:    false,
+			Target: &sqlgraph.EdgeTarget{
+				IDSpec: sqlgraph.NewFieldSpec(user.FieldID, field.TypeInt),
+			},
+		}
+		for _, k := range nodes {
+			edge.Target.Nodes = append(edge.Target.Nodes, k)
+		}
+		_node.user_relation_tuples = &nodes[0]
+		_spec.Edges = append(_spec.Edges, edge)
+	}
+	return _node, _spec
+}
+
+// RelationTupleCreateBulk is the builder for creating many RelationTuple entities in bulk.
+type RelationTupleCreateBulk struct {
+	config
+	err      error
+	builders []*RelationTupleCreate
+}
+
+// Save creates the RelationTuple entities in the database.
+func (rtcb *RelationTupleCreateBulk) Save(ctx context.Context) ([]*RelationTuple, error) {
+	if rtcb.err != nil {
+		return nil, rtcb.err
+	}
+	specs := make([]*sqlgraph.CreateSpec, len(rtcb.builders))
+	nodes := make([]*RelationTuple, len(rtcb.builders))
+	mutators := make([]Mutator, len(rtcb.builders))
+	for i := range rtcb.builders {
+		func(i int, root context.Context) {
+			builder := rtcb.builders[i]
+			builder.defaults()
+			var mut Mutator = MutateFunc(func(ctx context.Context, m Mutation) (Value, error) {
+				mutation, ok := m.(*RelationTupleMutation)
+				if !ok {
+					return nil, fmt.Errorf("unexpected mutation type %T", m)
+				}
+				if err := builder.check(); err != nil {
+					return nil, err
+				}
+				builder.mutation = mutation
+				var err error
+				nodes[i], specs[i] = builder.createSpec()
+				if i < len(mutators)-1 {
+					_, err = mutators[i+1].Mutate(root, rtcb.builders[i+1].mutation)
+				} else {
+					spec := &sqlgraph.BatchCreateSpec{Nodes: specs}
+					// Invoke the actual operation on the latest mutation in the chain.
+					if err = sqlgraph.BatchCreate(ctx, rtcb.driver, spec); err != nil {
+						if sqlgraph.IsConstraintError(err) {
+							err = &ConstraintError{msg: err.Error(), wrap: err}
+						}
+					}
+				}
+				if err != nil {
+					return nil, err
+				}
+				mutation.id = &nodes[i].ID
+				if specs[i].ID.Value != nil {
+					id := specs[i].ID.Value.(int64)
+					nodes[i].ID = int(id)
+				}
+				mutation.done = true
+				return nodes[i], nil
+			})
+			for i := len(builder.hooks) - 1; i >= 0; i-- {
+				mut = builder.hooks[i](mut)
+			}
+			mutators[i] = mut
+		}(i, ctx)
+	}
+	if len(mutators) > 0 {
+		if _, err := mutators[0].Mutate(ctx, rtcb.builders[0].mutation); err != nil {
+			return nil, err
+		}
+	}
+	return nodes, nil
+}
+
+// SaveX is like Save, but panics if an error occurs.
+func (rtcb *RelationTupleCreateBulk) SaveX(ctx context.Context) []*RelationTuple {
+	v, err := rtcb.Save(ctx)
+	if err != nil {
+		panic(err)
+	}
+	return v
+}
+
+// Exec executes the query.
+func (rtcb *RelationTupleCreateBulk) Exec(ctx context.Context) error {
+	_, err := rtcb.Save(ctx)
+	return err
+}
+
+// ExecX is like Exec, but panics if an error occurs.
+func (rtcb *RelationTupleCreateBulk) ExecX(ctx context.Context) {
+	if err := rtcb.Exec(ctx); err != nil {
+		panic(err)
+	}
+}
@@ -0,0 +1,614 @@
+// Code generated by ent, DO NOT EDIT.
+
+package ent
+
+import (
+	"context"
+	"fmt"
+	"math"
+
+	"entgo.io/ent"
+	"entgo.io/ent/dialect/sql"
+	"entgo.io/ent/dialect/sql/sqlgraph"
+	"entgo.io/ent/schema/field"
+	"github.com/smxlong/users/ent/predicate"
+	"github.com/smxlong/users/ent/relationtuple"
+	"github.com/smxlong/users/ent/user"
+)
+
+// RelationTupleQuery is the builder for querying RelationTuple entities.
+type RelationTupleQuery struct {
+	config
+	ctx        *QueryContext
+	order      []relationtuple.OrderOption
+	inters     []Interceptor
+	predicates []predicate.RelationTuple
+	withUser   *UserQuery
+	withFKs    bool
+	// intermediate query (i.e. traversal path).
+	sql  *sql.Selector
+	path func(context.Context) (*sql.Selector, error)
+}
+
+// Where adds a new predicate for the RelationTupleQuery builder.
+func (rtq *RelationTupleQuery) Where(ps ...predicate.RelationTuple) *RelationTupleQuery {
+	rtq.predicates = append(rtq.predicates, ps...)
+	return rtq
+}
+
+// Limit the number of records to be returned by this query.
+func (rtq *RelationTupleQuery) Limit(limit int) *RelationTupleQuery {
+	rtq.ctx.Limit = &limit
+	return rtq
+}
+
+// Offset to start from.
+func (rtq *RelationTupleQuery) Offset(offset int) *RelationTupleQuery {
+	rtq.ctx.Offset = &offset
+	return rtq
+}
+
+// Unique configures the query builder to filter duplicate records on query.
+// By default, unique is set to true, and can be disabled using this method.
+func (rtq *RelationTupleQuery) Unique(unique bool) *RelationTupleQuery {
+	rtq.ctx.Unique = &unique
+	return rtq
+}
+
+// Order specifies how the records should be ordered.
+func (rtq *RelationTupleQuery) Order(o ...relationtuple.OrderOption) *RelationTupleQuery {
+	rtq.order = append(rtq.order, o...)
+	return rtq
+}
+
+// QueryUser chains the current query on the "user" edge.
+func (rtq *RelationTupleQuery) QueryUser() *UserQuery {
+	query := (&UserClient{config: rtq.config}).Query()
+	query.path = func(ctx context.Context) (fromU *sql.Selector, err error) {
+		if err := rtq.prepareQuery(ctx); err != nil {
+			return nil, err
+		}
+		selector := rtq.sqlQuery(ctx)
+		if err := selector.Err(); err != nil {
+			return nil, err
+		}
+		step := sqlgraph.NewStep(
+			sqlgraph.From(relationtuple.Table, relationtuple.FieldID, selector),
+			sqlgraph.To(user.Table, user.FieldID),
+			sqlgraph.Edge(sqlgraph.M2O, true, relationtuple.UserTable, relationtuple.UserColumn),
+		)
+		fromU = sqlgraph.SetNeighbors(rtq.driver.Dialect(), step)
+		return fromU, nil
+	}
+	return query
+}
+
+// First returns the first RelationTuple entity from the query.
+// Returns a *NotFoundError when no RelationTuple was found.
+func (rtq *RelationTupleQuery) First(ctx context.Context) (*RelationTuple, error) {
+	nodes, err := rtq.Limit(1).All(setContextOp(ctx, rtq.ctx, ent.OpQueryFirst))
+	if err != nil {
+		return nil, err
+	}
+	if len(nodes) == 0 {
+		return nil, &NotFoundError{relationtuple.Label}
+	}
+	return nodes[0], nil
+}
+
+// FirstX is like First, but panics if an error occurs.
+func (rtq *RelationTupleQuery) FirstX(ctx context.Context) *RelationTuple {
+	node, err := rtq.First(ctx)
+	if err != nil && !IsNotFound(err) {
+		panic(err)
+	}
+	return node
+}
+
+// FirstID returns the first RelationTuple ID from the query.
+// Returns a *NotFoundError when no RelationTuple ID was found.
+func (rtq *RelationTupleQuery) FirstID(ctx context.Context) (id int, err error) {
+	var ids []int
+	if ids, err = rtq.Limit(1).IDs(setContextOp(ctx, rtq.ctx, ent.OpQueryFirstID)); err != nil {
+		return
+	}
+	if len(ids) == 0 {
+		err = &NotFoundError{relationtuple.Label}
+		return
+	}
+	return ids[0], nil
+}
+
+// FirstIDX is like FirstID, but panics if an error occurs.
+func (rtq *RelationTupleQuery) FirstIDX(ctx context.Context) int {
+	id, err := rtq.FirstID(ctx)
+	if err != nil && !IsNotFound(err) {
+		panic(err)
+	}
+	return id
+}
+
+// Only returns a single RelationTuple entity found by the query, ensuring it only returns one.
+// Returns a *NotSingularError when more than one RelationTuple entity is found.
+// Returns a *NotFoundError when no RelationTuple entities are found.
+func (rtq *RelationTupleQuery) Only(ctx context.Context) (*RelationTuple, error) {
+	nodes, err := rtq.Limit(2).All(setContextOp(ctx, rtq.ctx, ent.OpQueryOnly))
+	if err != nil {
+		return nil, err
+	}
+	switch len(nodes) {
+	case 1:
+		return nodes[0], nil
+	case 0:
+		return nil, &NotFoundError{relationtuple.Label}
+	default:
+		return nil, &NotSingularError{relationtuple.Label}
+	}
+}
+
+// OnlyX is like Only, but panics if an error occurs.
+func (rtq *RelationTupleQuery) OnlyX(ctx context.Context) *RelationTuple {
+	node, err := rtq.Only(ctx)
+	if err != nil {
+		panic(err)
+	}
+	return node
+}
+
+// OnlyID is like Only, but returns the only RelationTuple ID in the query.
+// Returns a *NotSingularError when more than one RelationTuple ID is found.
+// Returns a *NotFoundError when no entities are found.
+func (rtq *RelationTupleQuery) OnlyID(ctx context.Context) (id int, err error) {
+	var ids []int
+	if ids, err = rtq.Limit(2).IDs(setContextOp(ctx, rtq.ctx, ent.OpQueryOnlyID)); err != nil {
+		return
+	}
+	switch len(ids) {
+	case 1:
+		id = ids[0]
+	case 0:
+		err = &NotFoundError{relationtuple.Label}
+	default:
+		err = &NotSingularError{relationtuple.Label}
+	}
+	return
+}
+
+// OnlyIDX is like OnlyID, but panics if an error occurs.
+func (rtq *RelationTupleQuery) OnlyIDX(ctx context.Context) int {
+	id, err := rtq.OnlyID(ctx)
+	if err != nil {
+		panic(err)
+	}
+	return id
+}
+
+// All executes the query and returns a list of RelationTuples.
+func (rtq *RelationTupleQuery) All(ctx context.Context) ([]*RelationTuple, error) {
+	ctx = setContextOp(ctx, rtq.ctx, ent.OpQueryAll)
+	if err := rtq.prepareQuery(ctx); err != nil {
+		return nil, err
+	}
+	qr := querierAll[[]*RelationTuple, *RelationTupleQuery]()
+	return withInterceptors[[]*RelationTuple](ctx, rtq, qr, rtq.inters)
+}
+
+// AllX is like All, but panics if an error occurs.
+func (rtq *RelationTupleQuery) AllX(ctx context.Context) []*RelationTuple {
+	nodes, err := rtq.All(ctx)
+	if err != nil {
+		panic(err)
+	}
+	return nodes
+}
+
+// IDs executes the query and returns a list of RelationTuple IDs.
+func (rtq *RelationTupleQuery) IDs(ctx context.Context) (ids []int, err error) {
+	if rtq.ctx.Unique == nil && rtq.path != nil {
+		rtq.Unique(true)
+	}
+	ctx = setContextOp(ctx, rtq.ctx, ent.OpQueryIDs)
+	if err = rtq.Select(relationtuple.FieldID).Scan(ctx, &ids); err != nil {
+		return nil, err
+	}
+	return ids, nil
+}
+
+// IDsX is like IDs, but panics if an error occurs.
+func (rtq *RelationTupleQuery) IDsX(ctx context.Context) []int {
+	ids, err := rtq.IDs(ctx)
+	if err != nil {
+		panic(err)
+	}
+	return ids
+}
+
+// Count returns the count of the given query.
+func (rtq *RelationTupleQuery) Count(ctx context.Context) (int, error) {
+	ctx = setContextOp(ctx, rtq.ctx, ent.OpQueryCount)
+	if err := rtq.prepareQuery(ctx); err != nil {
+		return 0, err
+	}
+	return withInterceptors[int](ctx, rtq, querierCount[*RelationTupleQuery](), rtq.inters)
+}
+
+// CountX is like Count, but panics if an error occurs.
+func (rtq *RelationTupleQuery) CountX(ctx context.Context) int {
+	count, err := rtq.Count(ctx)
+	if err != nil {
+		panic(err)
+	}
+	return count
+}
+
+// Exist returns true if the query has elements in the graph.
+func (rtq *RelationTupleQuery) Exist(ctx context.Context) (bool, error) {
+	ctx = setContextOp(ctx, rtq.ctx, ent.OpQueryExist)
+	switch _, err := rtq.FirstID(ctx); {
+	case IsNotFound(err):
+		return false, nil
+	case err != nil:
+		return false, fmt.Errorf("ent: check existence: %w", err)
+	default:
+		return true, nil
+	}
+}
+
+// ExistX is like Exist, but panics if an error occurs.
+func (rtq *RelationTupleQuery) ExistX(ctx context.Context) bool {
+	exist, err := rtq.Exist(ctx)
+	if err != nil {
+		panic(err)
+	}
+	return exist
+}
+
+// Clone returns a duplicate of the RelationTupleQuery builder, including all associated steps. It can be
+// used to prepare common query builders and use them differently after the clone is made.
+func (rtq *RelationTupleQuery) Clone() *RelationTupleQuery {
+	if rtq == nil {
+		return nil
+	}
+	return &RelationTupleQuery{
+		config:     rtq.config,
+		ctx:        rtq.ctx.Clone(),
+		order:      append([]relationtuple.OrderOption{}, rtq.order...),
+		inters:     append([]Interceptor{}, rtq.inters...),
+		predicates: append([]predicate.RelationTuple{}, rtq.predicates...),
+		withUser:   rtq.withUser.Clone(),
+		// clone intermediate query.
+		sql:  rtq.sql.Clone(),
+		path: rtq.path,
+	}
+}
+
+// WithUser tells the query-builder to eager-load the nodes that are connected to
+// the "user" edge. The optional arguments are used to configure the query builder of the edge.
+func (rtq *RelationTupleQuery) WithUser(opts ...func(*UserQuery)) *RelationTupleQuery {
+	query := (&UserClient{config: rtq.config}).Query()
+	for _, opt := range opts {
+		opt(query)
+	}
+	rtq.withUser = query
+	return rtq
+}
+
+// GroupBy is used to group vertices by one or more fields/columns.
+// It is often used with aggregate functions, like: count, max, mean, min, sum.
+//
+// Example:
+//
+//	var v []struct {
+//		CreatedAt time.Time `json:"created_at,omitempty"`
+//		Count int `json:"count,omitempty"`
+//	}
+//
+//	client.RelationTuple.Query().
+//		GroupBy(relationtuple.FieldCreatedAt).
+//		Aggregate(ent.Count()).
+//		Scan(ctx, &v)
+func (rtq *RelationTupleQuery) GroupBy(field string, fields ...string) *RelationTupleGroupBy {
+	rtq.ctx.Fields = append([]string{field}, fields...)
+	grbuild := &RelationTupleGroupBy{build: rtq}
+	grbuild.flds = &rtq.ctx.Fields
+	grbuild.label = relationtuple.Label
+	grbuild.scan = grbuild.Scan
+	return grbuild
+}
+
+// Select allows the selection one or more fields/columns for the given query,
+// instead of selecting all fields in the entity.
+//
+// Example:
+//
+//	var v []struct {
+//		CreatedAt time.Time `json:"created_at,omitempty"`
+//	}
+//
+//	client.RelationTuple.Query().
+//		Select(relationtuple.FieldCreatedAt).
+//		Scan(ctx, &v)
+func (rtq *RelationTupleQuery) Select(fields ...string) *RelationTupleSelect {
+	rtq.ctx.Fields = append(rtq.ctx.Fields, fields...)
+	sbuild := &RelationTupleSelect{RelationTupleQuery: rtq}
+	sbuild.label = relationtuple.Label
+	sbuild.flds, sbuild.scan = &rtq.ctx.Fields, sbuild.Scan
+	return sbuild
+}
+
+// Aggregate returns a RelationTupleSelect configured with the given aggregations.
+func (rtq *RelationTupleQuery) Aggregate(fns ...AggregateFunc) *RelationTupleSelect {
+	return rtq.Select().Aggregate(fns...)
+}
+
+func (rtq *RelationTupleQuery) prepareQuery(ctx context.Context) error {
+	for _, inter := range rtq.inters {
+		if inter == nil {
+			return fmt.Errorf("ent: uninitialized interceptor (forgotten import ent/runtime?)")
+		}
+		if trv, ok := inter.(Traverser); ok {
+			if err := trv.Traverse(ctx, rtq); err != nil {
+				return err
+			}
+		}
+	}
+	for _, f := range rtq.ctx.Fields {
+		if !relationtuple.ValidColumn(f) {
+			return &ValidationError{Name: f, err: fmt.Errorf("ent: invalid field %q for query", f)}
+		}
+	}
+	if rtq.path != nil {
+		prev, err := rtq.path(ctx)
+		if err != nil {
+			return err
+		}
+		rtq.sql = prev
+	}
+	return nil
+}
+
+func (rtq *RelationTupleQuery) sqlAll(ctx context.Context, hooks ...queryHook) ([]*RelationTuple, error) {
+	var (
+		nodes       = []*RelationTuple{}
+		withFKs     = rtq.withFKs
+		_spec       = rtq.querySpec()
+		loadedTypes = [1]bool{
+			rtq.withUser != nil,
+		}
+	)
+	if rtq.withUser != nil {
+		withFKs = true
+	}
+	if withFKs {
+		_spec.Node.Columns = append(_spec.Node.Columns, relationtuple.ForeignKeys...)
+	}
+	_spec.ScanValues = func(columns []string) ([]any, error) {
+		return (*RelationTuple).scanValues(nil, columns)
+	}
+	_spec.Assign = func(columns []string, values []any) error {
+		node := &RelationTuple{config: rtq.config}
+		nodes = append(nodes, node)
+		node.Edges.loadedTypes = loadedTypes
+		return node.assignValues(columns, values)
+	}
+	for i := range hooks {
+		hooks[i](ctx, _spec)
+	}
+	if err := sqlgraph.QueryNodes(ctx, rtq.driver, _spec); err != nil {
+		return nil, err
+	}
+	if len(nodes) == 0 {
+		return nodes, nil
+	}
+	if query := rtq.withUser; query != nil {
+		if err := rtq.loadUser(ctx, query, nodes, nil,
+			func(n *RelationTuple, e *User) { n.Edges.User = e }); err != nil {
+			return nil, err
+		}
+	}
+	return nodes, nil
+}
+
+func (rtq *RelationTupleQuery) loadUser(ctx context.Context, query *UserQuery, nodes []*RelationTuple, init func(*RelationTuple), assign func(*RelationTuple, *User)) error {
+	ids := make([]int, 0, len(nodes))
+	nodeids := make(map[int][]*RelationTuple)
+	for i := range nodes {
+		if nodes[i].user_relation_tuples == nil {
+			continue
+		}
+		fk := *nodes[i].user_relation_tuples
+		if _, ok := nodeids[fk]; !ok {
+			ids = append(ids, fk)
+		}
+		nodeids[fk] = append(nodeids[fk], nodes[i])
+	}
+	if len(ids) == 0 {
+		return nil
+	}
+	query.Where(user.IDIn(ids...))
+	neighbors, err := query.All(ctx)
+	if err != nil {
+		return err
+	}
+	for _, n := range neighbors {
+		nodes, ok := nodeids[n.ID]
+		if !ok {
+			return fmt.Errorf(`unexpected foreign-key "user_relation_tuples" returned %v`, n.ID)
+		}
+		for i := range nodes {
+			assign(nodes[i], n)
+		}
+	}
+	return nil
+}
+
+func (rtq *RelationTupleQuery) sqlCount(ctx context.Context) (int, error) {
+	_spec := rtq.querySpec()
+	_spec.Node.Columns = rtq.ctx.Fields
+	if len(rtq.ctx.Fields) > 0 {
+		_spec.Unique = rtq.ctx.Unique != nil && *rtq.ctx.Unique
+	}
+	return sqlgraph.CountNodes(ctx, rtq.driver, _spec)
+}
+
+func (rtq *RelationTupleQuery) querySpec() *sqlgraph.QuerySpec {
+	_spec := sqlgraph.NewQuerySpec(relationtuple.Table, relationtuple.Columns, sqlgraph.NewFieldSpec(relationtuple.FieldID, field.TypeInt))
+	_spec.From = rtq.sql
+	if unique := rtq.ctx.Unique; unique != nil {
+		_spec.Unique = *unique
+	} else if rtq.path != nil {
+		_spec.Unique = true
+	}
+	if fields := rtq.ctx.Fields; len(fields) > 0 {
+		_spec.Node.Columns = make([]string, 0, len(fields))
+		_spec.Node.Columns = append(_spec.Node.Columns, relationtuple.FieldID)
+		for i := range fields {
+			if fields[i] != relationtuple.FieldID {
+				_spec.Node.Columns = append(_spec.Node.Columns, fields[i])
+			}
+		}
+	}
+	if ps := rtq.predicates; len(ps) > 0 {
+		_spec.Predicate = func(selector *sql.Selector) {
+			for i := range ps {
+				ps[i](selector)
+			}
+		}
+	}
+	if limit := rtq.ctx.Limit; limit != nil {
+		_spec.Limit = *limit
+	}
+	if offset := rtq.ctx.Offset; offset != nil {
+		_spec.Offset = *offset
+	}
+	if ps := rtq.order; len(ps) > 0 {
+		_spec.Order = func(selector *sql.Selector) {
+			for i := range ps {
+				ps[i](selector)
+			}
+		}
+	}
+	return _spec
+}
+
+func (rtq *RelationTupleQuery) sqlQuery(ctx context.Context) *sql.Selector {
+	builder := sql.Dialect(rtq.driver.Dialect())
+	t1 := builder.Table(relationtuple.Table)
+	columns := rtq.ctx.Fields
+	if len(columns) == 0 {
+		columns = relationtuple.Columns
+	}
+	selector := builder.Select(t1.Columns(columns...)...).From(t1)
+	if rtq.sql != nil {
+		selector = rtq.sql
+		selector.Select(selector.Columns(columns...)...)
+	}
+	if rtq.ctx.Unique != nil && *rtq.ctx.Unique {
+		selector.Distinct()
+	}
+	for _, p := range rtq.predicates {
+		p(selector)
+	}
+	for _, p := range rtq.order {
+		p(selector)
+	}
+	if offset := rtq.ctx.Offset; offset != nil {
+		// limit is mandatory for offset clause. We start
+		// with default value, and override it below if needed.
+		selector.Offset(*offset).Limit(math.MaxInt32)
+	}
+	if limit := rtq.ctx.Limit; limit != nil {
+		selector.Limit(*limit)
+	}
+	return selector
+}
+
+// RelationTupleGroupBy is the group-by builder for RelationTuple entities.
+type RelationTupleGroupBy struct {
+	selector
+	build *RelationTupleQuery
+}
+
+// Aggregate adds the given aggregation functions to the group-by query.
+func (rtgb *RelationTupleGroupBy) Aggregate(fns ...AggregateFunc) *RelationTupleGroupBy {
+	rtgb.fns = append(rtgb.fns, fns...)
+	return rtgb
+}
+
+// Scan applies the selector query and scans the result into the given value.
+func (rtgb *RelationTupleGroupBy) Scan(ctx context.Context, v any) error {
+	ctx = setContextOp(ctx, rtgb.build.ctx, ent.OpQueryGroupBy)
+	if err := rtgb.build.prepareQuery(ctx); err != nil {
+		return err
+	}
+	return scanWithInterceptors[*RelationTupleQuery, *RelationTupleGroupBy](ctx, rtgb.build, rtgb, rtgb.build.inters, v)
+}
+
+func (rtgb *RelationTupleGroupBy) sqlScan(ctx context.Context, root *RelationTupleQuery, v any) error {
+	selector := root.sqlQuery(ctx).Select()
+	aggregation := make([]string, 0, len(rtgb.fns))
+	for _, fn := range rtgb.fns {
+		aggregation = append(aggregation, fn(selector))
+	}
+	if len(selector.SelectedColumns()) == 0 {
+		columns := make([]string, 0, len(*rtgb.flds)+len(rtgb.fns))
+		for _, f := range *rtgb.flds {
+			columns = append(columns, selector.C(f))
+		}
+		columns = append(columns, aggregation...)
+		selector.Select(columns...)
+	}
+	selector.GroupBy(selector.Columns(*rtgb.flds...)...)
+	if err := selector.Err(); err != nil {
+		return err
+	}
+	rows := &sql.Rows{}
+	query, args := selector.Query()
+	if err := rtgb.build.driver.Query(ctx, query, args, rows); err != nil {
+		return err
+	}
+	defer rows.Close()
+	return sql.ScanSlice(rows, v)
+}
+
+// RelationTupleSelect is the builder for selecting fields of RelationTuple entities.
+type RelationTupleSelect struct {
+	*RelationTupleQuery
+	selector
+}
+
+// Aggregate adds the given aggregation functions to the selector query.
+func (rts *RelationTupleSelect) Aggregate(fns ...AggregateFunc) *RelationTupleSelect {
+	rts.fns = append(rts.fns, fns...)
+	return rts
+}
+
+// Scan applies the selector query and scans the result into the given value.
+func (rts *RelationTupleSelect) Scan(ctx context.Context, v any) error {
+	ctx = setContextOp(ctx, rts.ctx, ent.OpQuerySelect)
+	if err := rts.prepareQuery(ctx); err != nil {
+		return err
+	}
+	return scanWithInterceptors[*RelationTupleQuery, *RelationTupleSelect](ctx, rts.RelationTupleQuery, rts, rts.inters, v)
+}
+
+func (rts *RelationTupleSelect) sqlScan(ctx context.Context, root *RelationTupleQuery, v any) error {
+	selector := root.sqlQuery(ctx)
+	aggregation := make([]string, 0, len(rts.fns))
+	for _, fn := range rts.fns {
+		aggregation = append(aggregation, fn(selector))
+	}
+	switch n := len(*rts.selector.flds); {
+	case n == 0 && len(aggregation) > 0:
+		selector.Select(aggregation...)
+	case n != 0 && len(aggregation) > 0:
+		selector.AppendSelect(aggregation...)
+	}
+	rows := &sql.Rows{}
+	query, args := selector.Query()
+	if err := rts.driver.Query(ctx, query, args, rows); err != nil {
+		return err
+	}
+	defer rows.Close()
+	return sql.ScanSlice(rows, v)
+}
@@ -0,0 +1,589 @@
+// Code generated by ent, DO NOT EDIT.
+
+package ent
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"entgo.io/ent/dialect/sql"
+	"entgo.io/ent/dialect/sql/sqlgraph"
+	"entgo.io/ent/schema/field"
+	"github.com/smxlong/users/ent/predicate"
+	"github.com/smxlong/users/ent/relationtuple"
+	"github.com/smxlong/users/ent/user"
+)
+
+// RelationTupleUpdate is the builder for updating RelationTuple entities.
+type RelationTupleUpdate struct {
+	config
+	hooks    []Hook
+	mutation *RelationTupleMutation
+}
+
+// Where appends a list predicates to the RelationTupleUpdate builder.
+func (rtu *RelationTupleUpdate) Where(ps ...predicate.RelationTuple) *RelationTupleUpdate {
+	rtu.mutation.Where(ps...)
+	return rtu
+}
+
+// SetUpdatedAt sets the "updated_at" field.
+func (rtu *RelationTupleUpdate) SetUpdatedAt(t time.Time) *RelationTupleUpdate {
+	rtu.mutation.SetUpdatedAt(t)
+	return rtu
+}
+
+// SetNillableUpdatedAt sets the "updated_at" field if the given value is not nil.
+func (rtu *RelationTupleUpdate) SetNillableUpdatedAt(t *time.Time) *RelationTupleUpdate {
+	if t != nil {
+		rtu.SetUpdatedAt(*t)
+	}
+	return rtu
+}
+
+// SetObjectType sets the "object_type" field.
+func (rtu *RelationTupleUpdate) SetObjectType(s string) *RelationTupleUpdate {
+	rtu.mutation.SetObjectType(s)
+	return rtu
+}
+
+// SetNillableObjectType sets the "object_type" field if the given value is not nil.
+func (rtu *RelationTupleUpdate) SetNillableObjectType(s *string) *RelationTupleUpdate {
+	if s != nil {
+		rtu.SetObjectType(*s)
+	}
+	return rtu
+}
+
+// SetObjectID sets the "object_id" field.
+func (rtu *RelationTupleUpdate) SetObjectID(s string) *RelationTupleUpdate {
+	rtu.mutation.SetObjectID(s)
+	return rtu
+}
+
+// SetNillableObjectID sets the "object_id" field if the given value is not nil.
+func (rtu *RelationTupleUpdate) SetNillableObjectID(s *string) *RelationTupleUpdate {
+	if s != nil {
+		rtu.SetObjectID(*s)
+	}
+	return rtu
+}
+
+// SetRelation sets the "relation" field.
+func (rtu *RelationTupleUpdate) SetRelation(s string) *RelationTupleUpdate {
+	rtu.mutation.SetRelation(s)
+	return rtu
+}
+
+// SetNillableRelation sets the "relation" field if the given value is not nil.
+func (rtu *RelationTupleUpdate) SetNillableRelation(s *string) *RelationTupleUpdate {
+	if s != nil {
+		rtu.SetRelation(*s)
+	}
+	return rtu
+}
+
+// SetSubjectType sets the "subject_type" field.
+func (rtu *RelationTupleUpdate) SetSubjectType(s string) *RelationTupleUpdate {
+	rtu.mutation.SetSubjectType(s)
+	return rtu
+}
+
+// SetNillableSubjectType sets the "subject_type" field if the given value is not nil.
+func (rtu *RelationTupleUpdate) SetNillableSubjectType(s *string) *RelationTupleUpdate {
+	if s != nil {
+		rtu.SetSubjectType(*s)
+	}
+	return rtu
+}
+
+// SetSubjectID sets the "subject_id" field.
+func (rtu *RelationTupleUpdate) SetSubjectID(s string) *RelationTupleUpdate {
+	rtu.mutation.SetSubjectID(s)
+	return rtu
+}
+
+// SetNillableSubjectID sets the "subject_id" field if the given value is not nil.
+func (rtu *RelationTupleUpdate) SetNillableSubjectID(s *string) *RelationTupleUpdate {
+	if s != nil {
+		rtu.SetSubjectID(*s)
+	}
+	return rtu
+}
+
+// SetSubjectRelation sets the "subject_relation" field.
+func (rtu *RelationTupleUpdate) SetSubjectRelation(s string) *RelationTupleUpdate {
+	rtu.mutation.SetSubjectRelation(s)
+	return rtu
+}
+
+// SetNillableSubjectRelation sets the "subject_relation" field if the given value is not nil.
+func (rtu *RelationTupleUpdate) SetNillableSubjectRelation(s *string) *RelationTupleUpdate {
+	if s != nil {
+		rtu.SetSubjectRelation(*s)
+	}
+	return rtu
+}
+
+// SetUserID sets the "user" edge to the User entity by ID.
+func (rtu *RelationTupleUpdate) SetUserID(id int) *RelationTupleUpdate {
+	rtu.mutation.SetUserID(id)
+	return rtu
+}
+
+// SetNillableUserID sets the "user" edge to the User entity by ID if the given value is not nil.
+func (rtu *RelationTupleUpdate) SetNillableUserID(id *int) *RelationTupleUpdate {
+	if id != nil {
+		rtu = rtu.SetUserID(*id)
+	}
+	return rtu
+}
+
+// SetUser sets the "user" edge to the User entity.
+func (rtu *RelationTupleUpdate) SetUser(u *User) *RelationTupleUpdate {
+	return rtu.SetUserID(u.ID)
+}
+
+// Mutation returns the RelationTupleMutation object of the builder.
+func (rtu *RelationTupleUpdate) Mutation() *RelationTupleMutation {
+	return rtu.mutation
+}
+
+// ClearUser clears the "user" edge to the User entity.
+func (rtu *RelationTupleUpdate) ClearUser() *RelationTupleUpdate {
+	rtu.mutation.ClearUser()
+	return rtu
+}
+
+// Save executes the query and returns the number of nodes affected by the update operation.
+func (rtu *RelationTupleUpdate) Save(ctx context.Context) (int, error) {
+	return withHooks(ctx, rtu.sqlSave, rtu.mutation, rtu.hooks)
+}
+
+// SaveX is like Save, but panics if an error occurs.
+func (rtu *RelationTupleUpdate) SaveX(ctx context.Context) int {
+	affected, err := rtu.Save(ctx)
+	if err != nil {
+		panic(err)
+	}
+	return affected
+}
+
+// Exec executes the query.
+func (rtu *RelationTupleUpdate) Exec(ctx context.Context) error {
+	_, err := rtu.Save(ctx)
+	return err
+}
+
+// ExecX is like Exec, but panics if an error occurs.
+func (rtu *RelationTupleUpdate) ExecX(ctx context.Context) {
+	if err := rtu.Exec(ctx); err != nil {
+		panic(err)
+	}
+}
+
+// check runs all checks and user-defined validators on the builder.
+func (rtu *RelationTupleUpdate) check() error {
+	if v, ok := rtu.mutation.ObjectType(); ok {
+		if err := relationtuple.ObjectTypeValidator(v); err != nil {
+			return &ValidationError{Name: "object_type", err: fmt.Errorf(`ent: validator failed for field "RelationTuple.object_type": %w`, err)}
+		}
+	}
+	if v, ok := rtu.mutation.ObjectID(); ok {
+		if err := relationtuple.ObjectIDValidator(v); err != nil {
+			return &ValidationError{Name: "object_id", err: fmt.Errorf(`ent: validator failed for field "RelationTuple.object_id": %w`, err)}
+		}
+	}
+	if v, ok := rtu.mutation.Relation(); ok {
+		if err := relationtuple.RelationValidator(v); err != nil {
+			return &ValidationError{Name: "relation", err: fmt.Errorf(`ent: validator failed for field "RelationTuple.relation": %w`, err)}
+		}
+	}
+	if v, ok := rtu.mutation.SubjectType(); ok {
+		if err := relationtuple.SubjectTypeValidator(v); err != nil {
+			return &ValidationError{Name: "subject_type", err: fmt.Errorf(`ent: validator failed for field "RelationTuple.subject_type": %w`, err)}
+		}
+	}
+	if v, ok := rtu.mutation.SubjectID(); ok {
+		if err := relationtuple.SubjectIDValidator(v); err != nil {
+			return &ValidationError{Name: "subject_id", err: fmt.Errorf(`ent: validator failed for field "RelationTuple.subject_id": %w`, err)}
+		}
+	}
+	return nil
+}
+
+func (rtu *RelationTupleUpdate) sqlSave(ctx context.Context) (n int, err error) {
+	if err := rtu.check(); err != nil {
+		return n, err
+	}
+	_spec := sqlgraph.NewUpdateSpec(relationtuple.Table, relationtuple.Columns, sqlgraph.NewFieldSpec(relationtuple.FieldID, field.TypeInt))
+	if ps := rtu.mutation.predicates; len(ps) > 0 {
+		_spec.Predicate = func(selector *sql.Selector) {
+			for i := range ps {
+				ps[i](selector)
+			}
+		}
+	}
+	if value, ok := rtu.mutation.UpdatedAt(); ok {
+		_spec.SetField(relationtuple.FieldUpdatedAt, field.TypeTime, value)
+	}
+	if value, ok := rtu.mutation.ObjectType(); ok {
+		_spec.SetField(relationtuple.FieldObjectType, field.TypeString, value)
+	}
+	if value, ok := rtu.mutation.ObjectID(); ok {
+		_spec.SetField(relationtuple.FieldObjectID, field.TypeString, value)
+	}
+	if value, ok := rtu.mutation.Relation(); ok {
+		_spec.SetField(relationtuple.FieldRelation, field.TypeString, value)
+	}
+	if value, ok := rtu.mutation.SubjectType(); ok {
+		_spec.SetField(relationtuple.FieldSubjectType, field.TypeString, value)
+	}
+	if value, ok := rtu.mutation.SubjectID(); ok {
+		_spec.SetField(relationtuple.FieldSubjectID, field.TypeString, value)
+	}
+	if value, ok := rtu.mutation.SubjectRelation(); ok {
+		_spec.SetField(relationtuple.FieldSubjectRelation, field.TypeString, value)
+	}
+	if rtu.mutation.UserCleared() {
+		edge := &sqlgraph.EdgeSpec{
+			Rel:     sqlgraph.M2O,
+			Inverse: true,
+			Table:   relationtuple.UserTable,
+			Columns: []string{relationtuple.UserColumn},
+			Bidi:    false,
+			Target: &sqlgraph.EdgeTarget{
+				IDSpec: sqlgraph.NewFieldSpec(user.FieldID, field.TypeInt),
+			},
+		}
+		_spec.Edges.Clear = append(_spec.Edges.Clear, edge)
+	}
+	if nodes := rtu.mutation.UserIDs(); len(nodes) > 0 {
+		edge := &sqlgraph.EdgeSpec{
+			Rel:     sqlgraph.M2O,
+			Inverse: true,
+			Table:   relationtuple.UserTable,
+			Columns: []string{relationtuple.UserColumn},
+			Bidi:    false,
+			Target: &sqlgraph.EdgeTarget{
+				IDSpec: sqlgraph.NewFieldSpec(user.FieldID, field.TypeInt),
+			},
+		}
+		for _, k := range nodes {
+			edge.Target.Nodes = append(edge.Target.Nodes, k)
+		}
+		_spec.Edges.Add = append(_spec.Edges.Add, edge)
+	}
+	if n, err = sqlgraph.UpdateNodes(ctx, rtu.driver, _spec); err != nil {
+		if _, ok := err.(*sqlgraph.NotFoundError); ok {
+			err = &NotFoundError{relationtuple.Label}
+		} else if sqlgraph.IsConstraintError(err) {
+			err = &ConstraintError{msg: err.Error(), wrap: err}
+		}
+		return 0, err
+	}
+	rtu.mutation.done = true
+	return n, nil
+}
+
+// RelationTupleUpdateOne is the builder for updating a single RelationTuple entity.
+type RelationTupleUpdateOne struct {
+	config
+	fields   []string
+	hooks    []Hook
+	mutation *RelationTupleMutation
+}
+
+// SetUpdatedAt sets the "updated_at" field.
+func (rtuo *RelationTupleUpdateOne) SetUpdatedAt(t time.Time) *RelationTupleUpdateOne {
+	rtuo.mutation.SetUpdatedAt(t)
+	return rtuo
+}
+
+// SetNillableUpdatedAt sets the "updated_at" field if the given value is not nil.
+func (rtuo *RelationTupleUpdateOne) SetNillableUpdatedAt(t *time.Time) *RelationTupleUpdateOne {
+	if t != nil {
+		rtuo.SetUpdatedAt(*t)
+	}
+	return rtuo
+}
+
+// SetObjectType sets the "object_type" field.
+func (rtuo *RelationTupleUpdateOne) SetObjectType(s string) *RelationTupleUpdateOne {
+	rtuo.mutation.SetObjectType(s)
+	return rtuo
+}
+
+// SetNillableObjectType sets the "object_type" field if the given value is not nil.
+func (rtuo *RelationTupleUpdateOne) SetNillableObjectType(s *string) *RelationTupleUpdateOne {
+	if s != nil {
+		rtuo.SetObjectType(*s)
+	}
+	return rtuo
+}
+
+// SetObjectID sets the "object_id" field.
+func (rtuo *RelationTupleUpdateOne) SetObjectID(s string) *RelationTupleUpdateOne {
+	rtuo.mutation.SetObjectID(s)
+	return rtuo
+}
+
+// SetNillableObjectID sets the "object_id" field if the given value is not nil.
+func (rtuo *RelationTupleUpdateOne) SetNillableObjectID(s *string) *RelationTupleUpdateOne {
+	if s != nil {
+		rtuo.SetObjectID(*s)
+	}
+	return rtuo
+}
+
+// SetRelation sets the "relation" field.
+func (rtuo *RelationTupleUpdateOne) SetRelation(s string) *RelationTupleUpdateOne {
+	rtuo.mutation.SetRelation(s)
+	return rtuo
+}
+
+// SetNillableRelation sets the "relation" field if the given value is not nil.
+func (rtuo *RelationTupleUpdateOne) SetNillableRelation(s *string) *RelationTupleUpdateOne {
+	if s != nil {
+		rtuo.SetRelation(*s)
+	}
+	return rtuo
+}
+
+// SetSubjectType sets the "subject_type" field.
+func (rtuo *RelationTupleUpdateOne) SetSubjectType(s string) *RelationTupleUpdateOne {
+	rtuo.mutation.SetSubjectType(s)
+	return rtuo
+}
+
+// SetNillableSubjectType sets the "subject_type" field if the given value is not nil.
+func (rtuo *RelationTupleUpdateOne) SetNillableSubjectType(s *string) *RelationTupleUpdateOne {
+	if s != nil {
+		rtuo.SetSubjectType(*s)
+	}
+	return rtuo
+}
+
+// SetSubjectID sets the "subject_id" field.
+func (rtuo *RelationTupleUpdateOne) SetSubjectID(s string) *RelationTupleUpdateOne {
+	rtuo.mutation.SetSubjectID(s)
+	return rtuo
+}
+
+// SetNillableSubjectID sets the "subject_id" field if the given value is not nil.
+func (rtuo *RelationTupleUpdateOne) SetNillableSubjectID(s *string) *RelationTupleUpdateOne {
+	if s != nil {
+		rtuo.SetSubjectID(*s)
+	}
+	return rtuo
+}
+
+// SetSubjectRelation sets the "subject_relation" field.
+func (rtuo *RelationTupleUpdateOne) SetSubjectRelation(s string) *RelationTupleUpdateOne {
+	rtuo.mutation.SetSubjectRelation(s)
+	return rtuo
+}
+
+// SetNillableSubjectRelation sets the "subject_relation" field if the given value is not nil.
+func (rtuo *RelationTupleUpdateOne) SetNillableSubjectRelation(s *string) *RelationTupleUpdateOne {
+	if s != nil {
+		rtuo.SetSubjectRelation(*s)
+	}
+	return rtuo
+}
+
+// SetUserID sets the "user" edge to the User entity by ID.
+func (rtuo *RelationTupleUpdateOne) SetUserID(id int) *RelationTupleUpdateOne {
+	rtuo.mutation.SetUserID(id)
+	return rtuo
+}
+
+// SetNillableUserID sets the "user" edge to the User entity by ID if the given value is not nil.
+func (rtuo *RelationTupleUpdateOne) SetNillableUserID(id *int) *RelationTupleUpdateOne {
+	if id != nil {
+		rtuo = rtuo.SetUserID(*id)
+	}
+	return rtuo
+}
+
+// SetUser sets the "user" edge to the User entity.
+func (rtuo *RelationTupleUpdateOne) SetUser(u *User) *RelationTupleUpdateOne {
+	return rtuo.SetUserID(u.ID)
+}
+
+// Mutation returns the RelationTupleMutation object of the builder.
+func (rtuo *RelationTupleUpdateOne) Mutation() *RelationTupleMutation {
+	return rtuo.mutation
+}
+
+// ClearUser clears the "user" edge to the User entity.
+func (rtuo *RelationTupleUpdateOne) ClearUser() *RelationTupleUpdateOne {
+	rtuo.mutation.ClearUser()
+	return rtuo
+}
+
+// Where appends a list predicates to the RelationTupleUpdate builder.
+func (rtuo *RelationTupleUpdateOne) Where(ps ...predicate.RelationTuple) *RelationTupleUpdateOne {
+	rtuo.mutation.Where(ps...)
+	return rtuo
+}
+
+// Select allows selecting one or more fields (columns) of the returned entity.
+// The default is selecting all fields defined in the entity schema.
+func (rtuo *RelationTupleUpdateOne) Select(field string, fields ...string) *RelationTupleUpdateOne {
+	rtuo.fields = append([]string{field}, fields...)
+	return rtuo
+}
+
+// Save executes the query and returns the updated RelationTuple entity.
+func (rtuo *RelationTupleUpdateOne) Save(ctx context.Context) (*RelationTuple, error) {
+	return withHooks(ctx, rtuo.sqlSave, rtuo.mutation, rtuo.hooks)
+}
+
+// SaveX is like Save, but panics if an error occurs.
+func (rtuo *RelationTupleUpdateOne) SaveX(ctx context.Context) *RelationTuple {
+	node, err := rtuo.Save(ctx)
+	if err != nil {
+		panic(err)
+	}
+	return node
+}
+
+// Exec executes the query on the entity.
+func (rtuo *RelationTupleUpdateOne) Exec(ctx context.Context) error {
+	_, err := rtuo.Save(ctx)
+	return err
+}
+
+// ExecX is like Exec, but panics if an error occurs.
+func (rtuo *RelationTupleUpdateOne) ExecX(ctx context.Context) {
+	if err := rtuo.Exec(ctx); err != nil {
+		panic(err)
+	}
+}
+
+// check runs all checks and user-defined validators on the builder.
+func (rtuo *RelationTupleUpdateOne) check() error {
+	if v, ok := rtuo.mutation.ObjectType(); ok {
+		if err := relationtuple.ObjectTypeValidator(v); err != nil {
+			return &ValidationError{Name: "object_type", err: fmt.Errorf(`ent: validator failed for field "RelationTuple.object_type": %w`, err)}
+		}
+	}
+	if v, ok := rtuo.mutation.ObjectID(); ok {
+		if err := relationtuple.ObjectIDValidator(v); err != nil {
+			return &ValidationError{Name: "object_id", err: fmt.Errorf(`ent: validator failed for field "RelationTuple.object_id": %w`, err)}
+		}
+	}
+	if v, ok := rtuo.mutation.Relation(); ok {
+		if err := relationtuple.RelationValidator(v); err != nil {
+			return &ValidationError{Name: "relation", err: fmt.Errorf(`ent: validator failed for field "RelationTuple.relation": %w`, err)}
+		}
+	}
+	if v, ok := rtuo.mutation.SubjectType(); ok {
+		if err := relationtuple.SubjectTypeValidator(v); err != nil {
+			return &ValidationError{Name: "subject_type", err: fmt.Errorf(`ent: validator failed for field "RelationTuple.subject_type": %w`, err)}
+		}
+	}
+	if v, ok := rtuo.mutation.SubjectID(); ok {
+		if err := relationtuple.SubjectIDValidator(v); err != nil {
+			return &ValidationError{Name: "subject_id", err: fmt.Errorf(`ent: validator failed for field "RelationTuple.subject_id": %w`, err)}
+		}
+	}
+	return nil
+}
+
+func (rtuo *RelationTupleUpdateOne) sqlSave(ctx context.Context) (_node *RelationTuple, err error) {
+	if err := rtuo.check(); err != nil {
+		return _node, err
+	}
+	_spec := sqlgraph.NewUpdateSpec(relationtuple.Table, relationtuple.Columns, sqlgraph.NewFieldSpec(relationtuple.FieldID, field.TypeInt))
+	id, ok := rtuo.mutation.ID()
+	if !ok {
+		return nil, &ValidationError{Name: "id", err: errors.New(`ent: missing "RelationTuple.id" for update`)}
+	}
+	_spec.Node.ID.Value = id
+	if fields := rtuo.fields; len(fields) > 0 {
+		_spec.Node.Columns = make([]string, 0, len(fields))
+		_spec.Node.Columns = append(_spec.Node.Columns, relationtuple.FieldID)
+		for _, f := range fields {
+			if !relationtuple.ValidColumn(f) {
+				return nil, &ValidationError{Name: f, err: fmt.Errorf("ent: invalid field %q for query", f)}
+			}
+			if f != relationtuple.FieldID {
+				_spec.Node.Columns = append(_spec.Node.Columns, f)
+			}
+		}
+	}
+	if ps := rtuo.mutation.predicates; len(ps) > 0 {
+		_spec.Predicate = func(selector *sql.Selector) {
+			for i := range ps {
+				ps[i](selector)
+			}
+		}
+	}
+	if value, ok := rtuo.mutation.UpdatedAt(); ok {
+		_spec.SetField(relationtuple.FieldUpdatedAt, field.TypeTime, value)
+	}
+	if value, ok := rtuo.mutation.ObjectType(); ok {
+		_spec.SetField(relationtuple.FieldObjectType, field.TypeString, value)
+	}
+	if value, ok := rtuo.mutation.ObjectID(); ok {
+		_spec.SetField(relationtuple.FieldObjectID, field.TypeString, value)
+	}
+	if value, ok := rtuo.mutation.Relation(); ok {
+		_spec.SetField(relationtuple.FieldRelation, field.TypeString, value)
+	}
+	if value, ok := rtuo.mutation.SubjectType(); ok {
+		_spec.SetField(relationtuple.FieldSubjectType, field.TypeString, value)
+	}
+	if value, ok := rtuo.mutation.SubjectID(); ok {
+		_spec.SetField(relationtuple.FieldSubjectID, field.TypeString, value)
+	}
+	if value, ok := rtuo.mutation.SubjectRelation(); ok {
+		_spec.SetField(relationtuple.FieldSubjectRelation, field.TypeString, value)
+	}
+	if rtuo.mutation.UserCleared() {
+		edge := &sqlgraph.EdgeSpec{
+			Rel:     sqlgraph.M2O,
+			Inverse: true,
+			Table:   relationtuple.UserTable,
+			Columns: []string{relationtuple.UserColumn},
+			Bidi:    false,
+			Target: &sqlgraph.EdgeTarget{
+				IDSpec: sqlgraph.NewFieldSpec(user.FieldID, field.TypeInt),
+			},
+		}
+		_spec.Edges.Clear = append(_spec.Edges.Clear, edge)
+	}
+	if nodes := rtuo.mutation.UserIDs(); len(nodes) > 0 {
+		edge := &sqlgraph.EdgeSpec{
+			Rel:     sqlgraph.M2O,
+			Inverse: true,
+			Table:   relationtuple.UserTable,
+			Columns: []string{relationtuple.UserColumn},
+			Bidi:    false,
+			Target: &sqlgraph.EdgeTarget{
+				IDSpec: sqlgraph.NewFieldSpec(user.FieldID, field.TypeInt),
+			},
+		}
+		for _, k := range nodes {
+			edge.Target.Nodes = append(edge.Target.Nodes, k)
+		}
+		_spec.Edges.Add = append(_spec.Edges.Add, edge)
+	}
+	_node = &RelationTuple{config: rtuo.config}
+	_spec.Assign = _node.assignValues
+	_spec.ScanValues = _node.scanValues
+	if err = sqlgraph.UpdateNode(ctx, rtuo.driver, _spec); err != nil {
+		if _, ok := err.(*sqlgraph.NotFoundError); ok {
+			err = &NotFoundError{relationtuple.Label}
+		} else if sqlgraph.IsConstraintError(err) {
+			err = &ConstraintError{msg: err.Error(), wrap: err}
+		}
+		return nil, err
+	}
+	rtuo.mutation.done = true
+	return _node, nil
+}
@@ -6,6 +6,7 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"time"
 
 	"entgo.io/ent/dialect/sql"
 	"entgo.io/ent/dialect/sql/sqlgraph"
@@ -13,6 +14,7 @@ import (
 	"github.com/smxlong/users/ent/permission"
 	"github.com/smxlong/users/ent/predicate"
 	"github.com/smxlong/users/ent/role"
+	"github.com/smxlong/users/ent/roleassignment"
 	"github.com/smxlong/users/ent/user"
 )
 
@@ -29,6 +31,20 @@ func (ru *RoleUpdate) Where(ps ...predicate.Role) *RoleUpdate {
 	return ru
 }
 
+// SetUpdatedAt sets the "updated_at" field.
+func (ru *RoleUpdate) SetUpdatedAt(t time.Time) *RoleUpdate {
+	ru.mutation.SetUpdatedAt(t)
+	return ru
+}
+
+// SetNillableUpdatedAt sets the "updated_at" field if the given value is not nil.
+func (ru *RoleUpdate) SetNillableUpdatedAt(t *time.Time) *RoleUpdate {
+	if t != nil {
+		ru.SetUpdatedAt(*t)
+	}
+	return ru
+}
+
 // SetName sets the "name" field.
 func (ru *RoleUpdate) SetName(s string) *RoleUpdate {
 	ru.mutation.SetName(s)
@@ -93,6 +109,51 @@ func (ru *RoleUpdate) AddUsers(u ...*User) *RoleUpdate {
 	return ru.AddUserIDs(ids...)
 }
 
+// AddChildIDs adds the "children" edge to the Role entity by IDs.
+func (ru *RoleUpdate) AddChildIDs(ids ...int) *RoleUpdate {
+	ru.mutation.AddChildIDs(ids...)
+	return ru
+}
+
+// AddChildren adds the "children" edges to the Role entity.
+func (ru *RoleUpdate) AddChildren(r ...*Role) *RoleUpdate {
+	ids := make([]int, len(r))
+	for i := range r {
+		ids[i] = r[i].ID
+	}
+	return ru.AddChildIDs(ids...)
+}
+
+// AddParentIDs adds the "parents" edge to the Role entity by IDs.
+func (ru *RoleUpdate) AddParentIDs(ids ...int) *RoleUpdate {
+	ru.mutation.AddParentIDs(ids...)
+	return ru
+}
+
+// AddParents adds the "parents" edges to the Role entity.
+func (ru *RoleUpdate) AddParents(r ...*Role) *RoleUpdate {
+	ids := make([]int, len(r))
+	for i := range r {
+		ids[i] = r[i].ID
+	}
+	return ru.AddParentIDs(ids...)
+}
+
+// AddAssignmentIDs adds the "assignments" edge to the RoleAssignment entity by IDs.
+func (ru *RoleUpdate) AddAssignmentIDs(ids ...int) *RoleUpdate {
+	ru.mutation.AddAssignmentIDs(ids...)
+	return ru
+}
+
+// AddAssignments adds the "assignments" edges to the RoleAssignment entity.
+func (ru *RoleUpdate) AddAssignments(r ...*RoleAssignment) *RoleUpdate {
+	ids := make([]int, len(r))
+	for i := range r {
+		ids[i] = r[i].ID
+	}
+	return ru.AddAssignmentIDs(ids...)
+}
+
 // Mutation returns the RoleMutation object of the builder.
 func (ru *RoleUpdate) Mutation() *RoleMutation {
 	return ru.mutation
@@ -140,6 +201,69 @@ func (ru *RoleUpdate) RemoveUsers(u ...*User) *RoleUpdate {
 	return ru.RemoveUserIDs(ids...)
 }
 
+// ClearChildren clears all "children" edges to the Role entity.
+func (ru *RoleUpdate) ClearChildren() *RoleUpdate {
+	ru.mutation.ClearChildren()
+	return ru
+}
+
+// RemoveChildIDs removes the "children" edge to Role entities by IDs.
+func (ru *RoleUpdate) RemoveChildIDs(ids ...int) *RoleUpdate {
+	ru.mutation.RemoveChildIDs(ids...)
+	return ru
+}
+
+// RemoveChildren removes "children" edges to Role entities.
+func (ru *RoleUpdate) RemoveChildren(r ...*Role) *RoleUpdate {
+	ids := make([]int, len(r))
+	for i := range r {
+		ids[i] = r[i].ID
+	}
+	return ru.RemoveChildIDs(ids...)
+}
+
+// ClearParents clears all "parents" edges to the Role entity.
+func (ru *RoleUpdate) ClearParents() *RoleUpdate {
+	ru.mutation.ClearParents()
+	return ru
+}
+
+// RemoveParentIDs removes the "parents" edge to Role entities by IDs.
+func (ru *RoleUpdate) RemoveParentIDs(ids ...int) *RoleUpdate {
+	ru.mutation.RemoveParentIDs(ids...)
+	return ru
+}
+
+// RemoveParents removes "parents" edges to Role entities.
+func (ru *RoleUpdate) RemoveParents(r ...*Role) *RoleUpdate {
+	ids := make([]int, len(r))
+	for i := range r {
+		ids[i] = r[i].ID
+	}
+	return ru.RemoveParentIDs(ids...)
+}
+
+// ClearAssignments clears all "assignments" edges to the RoleAssignment entity.
+func (ru *RoleUpdate) ClearAssignments() *RoleUpdate {
+	ru.mutation.ClearAssignments()
+	return ru
+}
+
+// RemoveAssignmentIDs removes the "assignments" edge to RoleAssignment entities by IDs.
+func (ru *RoleUpdate) RemoveAssignmentIDs(ids ...int) *RoleUpdate {
+	ru.mutation.RemoveAssignmentIDs(ids...)
+	return ru
+}
+
+// RemoveAssignments removes "assignments" edges to RoleAssignment entities.
+func (ru *RoleUpdate) RemoveAssignments(r ...*RoleAssignment) *RoleUpdate {
+	ids := make([]int, len(r))
+	for i := range r {
+		ids[i] = r[i].ID
+	}
+	return ru.RemoveAssignmentIDs(ids...)
+}
+
 // Save executes the query and returns the number of nodes affected by the update operation.
 func (ru *RoleUpdate) Save(ctx context.Context) (int, error) {
 	return withHooks(ctx, ru.sqlSave, ru.mutation, ru.hooks)
@@ -189,6 +313,9 @@ func (ru *RoleUpdate) sqlSave(ctx context.Context) (n int, err error) {
 			}
 		}
 	}
+	if value, ok := ru.mutation.UpdatedAt(); ok {
+		_spec.SetField(role.FieldUpdatedAt, field.TypeTime, value)
+	}
 	if value, ok := ru.mutation.Name(); ok {
 		_spec.SetField(role.FieldName, field.TypeString, value)
 	}
@@ -288,6 +415,141 @@ func (ru *RoleUpdate) sqlSave(ctx context.Context) (n int, err error) {
 		}
 		_spec.Edges.Add = append(_spec.Edges.Add, edge)
 	}
+	if ru.mutation.ChildrenCleared() {
+		edge := &sqlgraph.EdgeSpec{
+			Rel:     sqlgraph.M2M,
+			Inverse: true,
+			Table:   role.ChildrenTable,
+			Columns: role.ChildrenPrimaryKey,
+			Bidi:    false,
+			Target: &sqlgraph.EdgeTarget{
+				IDSpec: sqlgraph.NewFieldSpec(role.FieldID, field.TypeInt),
+			},
+		}
+		_spec.Edges.Clear = append(_spec.Edges.Clear, edge)
+	}
+	if nodes := ru.mutation.RemovedChildrenIDs(); len(nodes) > 0 && !ru.mutation.ChildrenCleared() {
+		edge := &sqlgraph.EdgeSpec{
+			Rel:     sqlgraph.M2M,
+			Inverse: true,
+			Table:   role.ChildrenTable,
+			Columns: role.ChildrenPrimaryKey,
+			Bidi:    false,
+			Target: &sqlgraph.EdgeTarget{
+				IDSpec: sqlgraph.NewFieldSpec(role.FieldID, field.TypeInt),
+			},
+		}
+		for _, k := range nodes {
+			edge.Target.Nodes = append(edge.Target.Nodes, k)
+		}
+		_spec.Edges.Clear = append(_spec.Edges.Clear, edge)
+	}
+	if nodes := ru.mutation.ChildrenIDs(); len(nodes) > 0 {
+		edge := &sqlgraph.EdgeSpec{
+			Rel:     sqlgraph.M2M,
+			Inverse: true,
+			Table:   role.ChildrenTable,
+			Columns: role.ChildrenPrimaryKey,
+			Bidi:    false,
+			Target: &sqlgraph.EdgeTarget{
+				IDSpec: sqlgraph.NewFieldSpec(role.FieldID, field.TypeInt),
+			},
+		}
+		for _, k := range nodes {
+			edge.Target.Nodes = append(edge.Target.Nodes, k)
+		}
+		_spec.Edges.Add = append(_spec.Edges.Add, edge)
+	}
+	if ru.mutation.ParentsCleared() {
+		edge := &sqlgraph.EdgeSpec{
+			Rel:     sqlgraph.M2M,
+			Inverse: false,
+			Table:   role.ParentsTable,
+			Columns: role.ParentsPrimaryKey,
+			Bidi:    false,
+			Target: &sqlgraph.EdgeTarget{
+				IDSpec: sqlgraph.NewFieldSpec(role.FieldID, field.TypeInt),
+			},
+		}
+		_spec.Edges.Clear = append(_spec.Edges.Clear, edge)
+	}
+	if nodes := ru.mutation.RemovedParentsIDs(); len(nodes) > 0 && !ru.mutation.ParentsCleared() {
+		edge := &sqlgraph.EdgeSpec{
+			Rel:     sqlgraph.M2M,
+			Inverse: false,
+			Table:   role.ParentsTable,
+			Columns: role.ParentsPrimaryKey,
+			Bidi:    false,
+			Target: &sqlgraph.EdgeTarget{
+				IDSpec: sqlgraph.NewFieldSpec(role.FieldID, field.TypeInt),
+			},
+		}
+		for _, k := range nodes {
+			edge.Target.Nodes = append(edge.Target.Nodes, k)
+		}
+		_spec.Edges.Clear = append(_spec.Edges.Clear, edge)
+	}
+	if nodes := ru.mutation.ParentsIDs(); len(nodes) > 0 {
+		edge := &sqlgraph.EdgeSpec{
+			Rel:     sqlgraph.M2M,
+			Inverse: false,
+			Table:   role.ParentsTable,
+			Columns: role.ParentsPrimaryKey,
+			Bidi:    false,
+			Target: &sqlgraph.EdgeTarget{
+				IDSpec: sqlgraph.NewFieldSpec(role.FieldID, field.TypeInt),
+			},
+		}
+		for _, k := range nodes {
+			edge.Target.Nodes = append(edge.Target.Nodes, k)
+		}
+		_spec.Edges.Add = append(_spec.Edges.Add, edge)
+	}
+	if ru.mutation.AssignmentsCleared() {
+		edge := &sqlgraph.EdgeSpec{
+			Rel:     sqlgraph.O2M,
+			Inverse: false,
+			Table:   role.AssignmentsTable,
+			Columns: []string{role.AssignmentsColumn},
+			Bidi:    false,
+			Target: &sqlgraph.EdgeTarget{
+				IDSpec: sqlgraph.NewFieldSpec(roleassignment.FieldID, field.TypeInt),
+			},
+		}
+		_spec.Edges.Clear = append(_spec.Edges.Clear, edge)
+	}
+	if nodes := ru.mutation.RemovedAssignmentsIDs(); len(nodes) > 0 && !ru.mutation.AssignmentsCleared() {
+		edge := &sqlgraph.EdgeSpec{
+			Rel:     sqlgraph.O2M,
+			Inverse: false,
+			Table:   role.AssignmentsTable,
+			Columns: []string{role.AssignmentsColumn},
+			Bidi:    false,
+			Target: &sqlgraph.EdgeTarget{
+				IDSpec: sqlgraph.NewFieldSpec(roleassignment.FieldID, field.TypeInt),
+			},
+		}
+		for _, k := range nodes {
+			edge.Target.Nodes = append(edge.Target.Nodes, k)
+		}
+		_spec.Edges.Clear = append(_spec.Edges.Clear, edge)
+	}
+	if nodes := ru.mutation.AssignmentsIDs(); len(nodes) > 0 {
+		edge := &sqlgraph.EdgeSpec{
+			Rel:     sqlgraph.O2M,
+			Inverse: false,
+			Table:   role.AssignmentsTable,
+			Columns: []string{role.AssignmentsColumn},
+			Bidi:    false,
+			Target: &sqlgraph.EdgeTarget{
+				IDSpec: sqlgraph.NewFieldSpec(roleassignment.FieldID, field.TypeInt),
+			},
+		}
+		for _, k := range nodes {
+			edge.Target.Nodes = append(edge.Target.Nodes, k)
+		}
+		_spec.Edges.Add = append(_spec.Edges.Add, edge)
+	}
 	if n, err = sqlgraph.UpdateNodes(ctx, ru.driver, _spec); err != nil {
 		if _, ok := err.(*sqlgraph.NotFoundError); ok {
 			err = &NotFoundError{role.Label}
@@ -308,6 +570,20 @@ type RoleUpdateOne struct {
 	mutation *RoleMutation
 }
 
+// SetUpdatedAt sets the "updated_at" field.
+func (ruo *RoleUpdateOne) SetUpdatedAt(t time.Time) *RoleUpdateOne {
+	ruo.mutation.SetUpdatedAt(t)
+	return ruo
+}
+
+// SetNillableUpdatedAt sets the "updated_at" field if the given value is not nil.
+func (ruo *RoleUpdateOne) SetNillableUpdatedAt(t *time.Time) *RoleUpdateOne {
+	if t != nil {
+		ruo.SetUpdatedAt(*t)
+	}
+	return ruo
+}
+
 // SetName sets the "name" field.
 func (ruo *RoleUpdateOne) SetName(s string) *RoleUpdateOne {
 	ruo.mutation.SetName(s)
@@ -372,6 +648,51 @@ func (ruo *RoleUpdateOne) AddUsers(u ...*User) *RoleUpdateOne {
 	return ruo.AddUserIDs(ids...)
 }
 
+// AddChildIDs adds the "children" edge to the Role entity by IDs.
+func (ruo *RoleUpdateOne) AddChildIDs(ids ...int) *RoleUpdateOne {
+	ruo.mutation.AddChildIDs(ids...)
+	return ruo
+}
+
+// AddChildren adds the "children" edges to the Role entity.
+func (ruo *RoleUpdateOne) AddChildren(r ...*Role) *RoleUpdateOne {
+	ids := make([]int, len(r))
+	for i := range r {
+		ids[i] = r[i].ID
+	}
+	return ruo.AddChildIDs(ids...)
+}
+
+// AddParentIDs adds the "parents" edge to the Role entity by IDs.
+func (ruo *RoleUpdateOne) AddParentIDs(ids ...int) *RoleUpdateOne {
+	ruo.mutation.AddParentIDs(ids...)
+	return ruo
+}
+
+// AddParents adds the "parents" edges to the Role entity.
+func (ruo *RoleUpdateOne) AddParents(r ...*Role) *RoleUpdateOne {
+	ids := make([]int, len(r))
+	for i := range r {
+		ids[i] = r[i].ID
+	}
+	return ruo.AddParentIDs(ids...)
+}
+
+// AddAssignmentIDs adds the "assignments" edge to the RoleAssignment entity by IDs.
+func (ruo *RoleUpdateOne) AddAssignmentIDs(ids ...int) *RoleUpdateOne {
+	ruo.mutation.AddAssignmentIDs(ids...)
+	return ruo
+}
+
+// AddAssignments adds the "assignments" edges to the RoleAssignment entity.
+func (ruo *RoleUpdateOne) AddAssignments(r ...*RoleAssignment) *RoleUpdateOne {
+	ids := make([]int, len(r))
+	for i := range r {
+		ids[i] = r[i].ID
+	}
+	return ruo.AddAssignmentIDs(ids...)
+}
+
 // Mutation returns the RoleMutation object of the builder.
 func (ruo *RoleUpdateOne) Mutation() *RoleMutation {
 	return ruo.mutation
@@ -419,6 +740,69 @@ func (ruo *RoleUpdateOne) RemoveUsers(u ...*User) *RoleUpdateOne {
 	return ruo.RemoveUserIDs(ids...)
 }
 
+// ClearChildren clears all "children" edges to the Role entity.
+func (ruo *RoleUpdateOne) ClearChildren() *RoleUpdateOne {
+	ruo.mutation.ClearChildren()
+	return ruo
+}
+
+// RemoveChildIDs removes the "children" edge to Role entities by IDs.
+func (ruo *RoleUpdateOne) RemoveChildIDs(ids ...int) *RoleUpdateOne {
+	ruo.mutation.RemoveChildIDs(ids...)
+	return ruo
+}
+
+// RemoveChildren removes "children" edges to Role entities.
+func (ruo *RoleUpdateOne) RemoveChildren(r ...*Role) *RoleUpdateOne {
+	ids := make([]int, len(r))
+	for i := range r {
+		ids[i] = r[i].ID
+	}
+	return ruo.RemoveChildIDs(ids...)
+}
+
+// ClearParents clears all "parents" edges to the Role entity.
+func (ruo *RoleUpdateOne) ClearParents() *RoleUpdateOne {
+	ruo.mutation.ClearParents()
+	return ruo
+}
+
+// RemoveParentIDs removes the "parents" edge to Role entities by IDs.
+func (ruo *RoleUpdateOne) RemoveParentIDs(ids ...int) *RoleUpdateOne {
+	ruo.mutation.RemoveParentIDs(ids...)
+	return ruo
+}
+
+// RemoveParents removes "parents" edges to Role entities.
+func (ruo *RoleUpdateOne) RemoveParents(r ...*Role) *RoleUpdateOne {
+	ids := make([]int, len(r))
+	for i := range r {
+		ids[i] = r[i].ID
+	}
+	return ruo.RemoveParentIDs(ids...)
+}
+
+// ClearAssignments clears all "assignments" edges to the RoleAssignment entity.
+func (ruo *RoleUpdateOne) ClearAssignments() *RoleUpdateOne {
+	ruo.mutation.ClearAssignments()
+	return ruo
+}
+
+// RemoveAssignmentIDs removes the "assignments" edge to RoleAssignment entities by IDs.
+func (ruo *RoleUpdateOne) RemoveAssignmentIDs(ids ...int) *RoleUpdateOne {
+	ruo.mutation.RemoveAssignmentIDs(ids...)
+	return ruo
+}
+
+// RemoveAssignments removes "assignments" edges to RoleAssignment entities.
+func (ruo *RoleUpdateOne) RemoveAssignments(r ...*RoleAssignment) *RoleUpdateOne {
+	ids := make([]int, len(r))
+	for i := range r {
+		ids[i] = r[i].ID
+	}
+	return ruo.RemoveAssignmentIDs(ids...)
+}
+
 // Where appends a list predicates to the RoleUpdate builder.
 func (ruo *RoleUpdateOne) Where(ps ...predicate.Role) *RoleUpdateOne {
 	ruo.mutation.Where(ps...)
@@ -498,6 +882,9 @@ func (ruo *RoleUpdateOne) sqlSave(ctx context.Context) (_node *Role, err error)
 			}
 		}
 	}
+	if value, ok := ruo.mutation.UpdatedAt(); ok {
+		_spec.SetField(role.FieldUpdatedAt, field.TypeTime, value)
+	}
 	if value, ok := ruo.mutation.Name(); ok {
 		_spec.SetField(role.FieldName, field.TypeString, value)
 	}
@@ -597,6 +984,141 @@ func (ruo *RoleUpdateOne) sqlSave(ctx context.Context) (_node *Role, err error)
 		}
 		_spec.Edges.Add = append(_spec.Edges.Add, edge)
 	}
+	if ruo.mutation.ChildrenCleared() {
+		edge := &sqlgraph.EdgeSpec{
+			Rel:     sqlgraph.M2M,
+			Inverse: true,
+			Table:   role.ChildrenTable,
+			Columns: role.ChildrenPrimaryKey,
+			Bidi:    false,
+			Target: &sqlgraph.EdgeTarget{
+				IDSpec: sqlgraph.NewFieldSpec(role.FieldID, field.TypeInt),
+			},
+		}
+		_spec.Edges.Clear = append(_spec.Edges.Clear, edge)
+	}
+	if nodes := ruo.mutation.RemovedChildrenIDs(); len(nodes) > 0 && !ruo.mutation.ChildrenCleared() {
+		edge := &sqlgraph.EdgeSpec{
+			Rel:     sqlgraph.M2M,
+			Inverse: true,
+			Table:   role.ChildrenTable,
+			Columns: role.ChildrenPrimaryKey,
+			Bidi:    false,
+			Target: &sqlgraph.EdgeTarget{
+				IDSpec: sqlgraph.NewFieldSpec(role.FieldID, field.TypeInt),
+			},
+		}
+		for _, k := range nodes {
+			edge.Target.Nodes = append(edge.Target.Nodes, k)
+		}
+		_spec.Edges.Clear = append(_spec.Edges.Clear, edge)
+	}
+	if nodes := ruo.mutation.ChildrenIDs(); len(nodes) > 0 {
+		edge := &sqlgraph.EdgeSpec{
+			Rel:     sqlgraph.M2M,
+			Inverse: true,
+			Table:   role.ChildrenTable,
+			Columns: role.ChildrenPrimaryKey,
+			Bidi:    false,
+			Target: &sqlgraph.EdgeTarget{
+				IDSpec: sqlgraph.NewFieldSpec(role.FieldID, field.TypeInt),
+			},
+		}
+		for _, k := range nodes {
+			edge.Target.Nodes = append(edge.Target.Nodes, k)
+		}
+		_spec.Edges.Add = append(_spec.Edges.Add, edge)
+	}
+	if ruo.mutation.ParentsCleared() {
+		edge := &sqlgraph.EdgeSpec{
+			Rel:     sqlgraph.M2M,
+			Inverse: false,
+			Table:   role.ParentsTable,
+			Columns: role.ParentsPrimaryKey,
+			Bidi:    false,
+			Target: &sqlgraph.EdgeTarget{
+				IDSpec: sqlgraph.NewFieldSpec(role.FieldID, field.TypeInt),
+			},
+		}
+		_spec.Edges.Clear = append(_spec.Edges.Clear, edge)
+	}
+	if nodes := ruo.mutation.RemovedParentsIDs(); len(nodes) > 0 && !ruo.mutation.ParentsCleared() {
+		edge := &sqlgraph.EdgeSpec{
+			Rel:     sqlgraph.M2M,
+			Inverse: false,
+			Table:   role.ParentsTable,
+			Columns: role.ParentsPrimaryKey,
+			Bidi:    false,
+			Target: &sqlgraph.EdgeTarget{
+				IDSpec: sqlgraph.NewFieldSpec(role.FieldID, field.TypeInt),
+			},
+		}
+		for _, k := range nodes {
+			edge.Target.Nodes = append(edge.Target.Nodes, k)
+		}
+		_spec.Edges.Clear = append(_spec.Edges.Clear, edge)
+	}
+	if nodes := ruo.mutation.ParentsIDs(); len(nodes) > 0 {
+		edge := &sqlgraph.EdgeSpec{
+			Rel:     sqlgraph.M2M,
+			Inverse: false,
+			Table:   role.ParentsTable,
+			Columns: role.ParentsPrimaryKey,
+			Bidi:    false,
+			Target: &sqlgraph.EdgeTarget{
+				IDSpec: sqlgraph.NewFieldSpec(role.FieldID, field.TypeInt),
+			},
+		}
+		for _, k := range nodes {
+			edge.Target.Nodes = append(edge.Target.Nodes, k)
+		}
+		_spec.Edges.Add = append(_spec.Edges.Add, edge)
+	}
+	if ruo.mutation.AssignmentsCleared() {
+		edge := &sqlgraph.EdgeSpec{
+			Rel:     sqlgraph.O2M,
+			Inverse: false,
+			Table:   role.AssignmentsTable,
+			Columns: []string{role.AssignmentsColumn},
+			Bidi:    false,
+			Target: &sqlgraph.EdgeTarget{
+				IDSpec: sqlgraph.NewFieldSpec(roleassignment.FieldID, field.TypeInt),
+			},
+		}
+		_spec.Edges.Clear = append(_spec.Edges.Clear, edge)
+	}
+	if nodes := ruo.mutation.RemovedAssignmentsIDs(); len(nodes) > 0 && !ruo.mutation.AssignmentsCleared() {
+		edge := &sqlgraph.EdgeSpec{
+			Rel:     sqlgraph.O2M,
+			Inverse: false,
+			Table:   role.AssignmentsTable,
+			Columns: []string{role.AssignmentsColumn},
+			Bidi:    false,
+			Target: &sqlgraph.EdgeTarget{
+				IDSpec: sqlgraph.NewFieldSpec(roleassignment.FieldID, field.TypeInt),
+			},
+		}
+		for _, k := range nodes {
+			edge.Target.Nodes = append(edge.Target.Nodes, k)
+		}
+		_spec.Edges.Clear = append(_spec.Edges.Clear, edge)
+	}
+	if nodes := ruo.mutation.AssignmentsIDs(); len(nodes) > 0 {
+		edge := &sqlgraph.EdgeSpec{
+			Rel:     sqlgraph.O2M,
+			Inverse: false,
+			Table:   role.AssignmentsTable,
+			Columns: []string{role.AssignmentsColumn},
+			Bidi:    false,
+			Target: &sqlgraph.EdgeTarget{
+				IDSpec: sqlgraph.NewFieldSpec(roleassignment.FieldID, field.TypeInt),
+			},
+		}
+		for _, k := range nodes {
+			edge.Target.Nodes = append(edge.Target.Nodes, k)
+		}
+		_spec.Edges.Add = append(_spec.Edges.Add, edge)
+	}
 	_node = &Role{config: ruo.config}
 	_spec.Assign = _node.assignValues
 	_spec.ScanValues = _node.scanValues
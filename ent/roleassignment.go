@@ -0,0 +1,207 @@
+// Code generated by ent, DO NOT EDIT.
+
+package ent
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"entgo.io/ent"
+	"entgo.io/ent/dialect/sql"
+	"github.com/smxlong/users/ent/role"
+	"github.com/smxlong/users/ent/roleassignment"
+	"github.com/smxlong/users/ent/user"
+)
+
+// RoleAssignment is the model entity for the RoleAssignment schema.
+type RoleAssignment struct {
+	config `json:"-"`
+	// ID of the ent.
+	ID int `json:"id,omitempty"`
+	// CreatedAt holds the value of the "created_at" field.
+	CreatedAt time.Time `json:"created_at,omitempty"`
+	// UpdatedAt holds the value of the "updated_at" field.
+	UpdatedAt time.Time `json:"updated_at,omitempty"`
+	// ResourceType holds the value of the "resource_type" field.
+	ResourceType string `json:"resource_type,omitempty"`
+	// ResourceID holds the value of the "resource_id" field.
+	ResourceID string `json:"resource_id,omitempty"`
+	// Edges holds the relations/edges for other nodes in the graph.
+	// The values are being populated by the RoleAssignmentQuery when eager-loading is set.
+	Edges                 RoleAssignmentEdges `json:"edges"`
+	role_assignments      *int
+	user_role_assignments *int
+	selectValues          sql.SelectValues
+}
+
+// RoleAssignmentEdges holds the relations/edges for other nodes in the graph.
+type RoleAssignmentEdges struct {
+	// User holds the value of the user edge.
+	User *User `json:"user,omitempty"`
+	// Role holds the value of the role edge.
+	Role *Role `json:"role,omitempty"`
+	// loadedTypes holds the information for reporting if a
+	// type was loaded (or requested) in eager-loading or not.
+	loadedTypes [2]bool
+}
+
+// UserOrErr returns the User value or an error if the edge
+// was not loaded in eager-loading, or loaded but was not found.
+func (e RoleAssignmentEdges) UserOrErr() (*User, error) {
+	if e.User != nil {
+		return e.User, nil
+	} else if e.loadedTypes[0] {
+		return nil, &NotFoundError{label: user.Label}
+	}
+	return nil, &NotLoadedError{edge: "user"}
+}
+
+// RoleOrErr returns the Role value or an error if the edge
+// was not loaded in eager-loading, or loaded but was not found.
+func (e RoleAssignmentEdges) RoleOrErr() (*Role, error) {
+	if e.Role != nil {
+		return e.Role, nil
+	} else if e.loadedTypes[1] {
+		return nil, &NotFoundError{label: role.Label}
+	}
+	return nil, &NotLoadedError{edge: "role"}
+}
+
+// scanValues returns the types for scanning values from sql.Rows.
+func (*RoleAssignment) scanValues(columns []string) ([]any, error) {
+	values := make([]any, len(columns))
+	for i := range columns {
+		switch columns[i] {
+		case roleassignment.FieldID:
+			values[i] = new(sql.NullInt64)
+		case roleassignment.FieldResourceType, roleassignment.FieldResourceID:
+			values[i] = new(sql.NullString)
+		case roleassignment.FieldCreatedAt, roleassignment.FieldUpdatedAt:
+			values[i] = new(sql.NullTime)
+		case roleassignment.ForeignKeys[0]: // role_assignments
+			values[i] = new(sql.NullInt64)
+		case roleassignment.ForeignKeys[1]: // user_role_assignments
+			values[i] = new(sql.NullInt64)
+		default:
+			values[i] = new(sql.UnknownType)
+		}
+	}
+	return values, nil
+}
+
+// assignValues assigns the values that were returned from sql.Rows (after scanning)
+// to the RoleAssignment fields.
+func (ra *RoleAssignment) assignValues(columns []string, values []any) error {
+	if m, n := len(values), len(columns); m < n {
+		return fmt.Errorf("mismatch number of scan values: %d != %d", m, n)
+	}
+	for i := range columns {
+		switch columns[i] {
+		case roleassignment.FieldID:
+			value, ok := values[i].(*sql.NullInt64)
+			if !ok {
+				return fmt.Errorf("unexpected type %T for field id", value)
+			}
+			ra.ID = int(value.Int64)
+		case roleassignment.FieldCreatedAt:
+			if value, ok := values[i].(*sql.NullTime); !ok {
+				return fmt.Errorf("unexpected type %T for field created_at", values[i])
+			} else if value.Valid {
+				ra.CreatedAt = value.Time
+			}
+		case roleassignment.FieldUpdatedAt:
+			if value, ok := values[i].(*sql.NullTime); !ok {
+				return fmt.Errorf("unexpected type %T for field updated_at", values[i])
+			} else if value.Valid {
+				ra.UpdatedAt = value.Time
+			}
+		case roleassignment.FieldResourceType:
+			if value, ok := values[i].(*sql.NullString); !ok {
+				return fmt.Errorf("unexpected type %T for field resource_type", values[i])
+			} else if value.Valid {
+				ra.ResourceType = value.String
+			}
+		case roleassignment.FieldResourceID:
+			if value, ok := values[i].(*sql.NullString); !ok {
+				return fmt.Errorf("unexpected type %T for field resource_id", values[i])
+			} else if value.Valid {
+				ra.ResourceID = value.String
+			}
+		case roleassignment.ForeignKeys[0]:
+			if value, ok := values[i].(*sql.NullInt64); !ok {
+				return fmt.Errorf("unexpected type %T for edge-field role_assignments", value)
+			} else if value.Valid {
+				ra.role_assignments = new(int)
+				*ra.role_assignments = int(value.Int64)
+			}
+		case roleassignment.ForeignKeys[1]:
+			if value, ok := values[i].(*sql.NullInt64); !ok {
+				return fmt.Errorf("unexpected type %T for edge-field user_role_assignments", value)
+			} else if value.Valid {
+				ra.user_role_assignments = new(int)
+				*ra.user_role_assignments = int(value.Int64)
+			}
+		default:
+			ra.selectValues.Set(columns[i], values[i])
+		}
+	}
+	return nil
+}
+
+// Value returns the ent.Value that was dynamically selected and assigned to the RoleAssignment.
+// This includes values selected through modifiers, order, etc.
+func (ra *RoleAssignment) Value(name string) (ent.Value, error) {
+	return ra.selectValues.Get(name)
+}
+
+// QueryUser queries the "user" edge of the RoleAssignment entity.
+func (ra *RoleAssignment) QueryUser() *UserQuery {
+	return NewRoleAssignmentClient(ra.config).QueryUser(ra)
+}
+
+// QueryRole queries the "role" edge of the RoleAssignment entity.
+func (ra *RoleAssignment) QueryRole() *RoleQuery {
+	return NewRoleAssignmentClient(ra.config).QueryRole(ra)
+}
+
+// Update returns a builder for updating this RoleAssignment.
+// Note that you need to call RoleAssignment.Unwrap() before calling this method if this RoleAssignment
+// was returned from a transaction, and the transaction was committed or rolled back.
+func (ra *RoleAssignment) Update() *RoleAssignmentUpdateOne {
+	return NewRoleAssignmentClient(ra.config).UpdateOne(ra)
+}
+
+// Unwrap unwraps the RoleAssignment entity that was returned from a transaction after it was closed,
+// so that all future queries will be executed through the driver which created the transaction.
+func (ra *RoleAssignment) Unwrap() *RoleAssignment {
+	_tx, ok := ra.config.driver.(*txDriver)
+	if !ok {
+		panic("ent: RoleAssignment is not a transactional entity")
+	}
+	ra.config.driver = _tx.drv
+	return ra
+}
+
+// String implements the fmt.Stringer.
+func (ra *RoleAssignment) String() string {
+	var builder strings.Builder
+	builder.WriteString("RoleAssignment(")
+	builder.WriteString(fmt.Sprintf("id=%v, ", ra.ID))
+	builder.WriteString("created_at=")
+	builder.WriteString(ra.CreatedAt.Format(time.ANSIC))
+	builder.WriteString(", ")
+	builder.WriteString("updated_at=")
+	builder.WriteString(ra.UpdatedAt.Format(time.ANSIC))
+	builder.WriteString(", ")
+	builder.WriteString("resource_type=")
+	builder.WriteString(ra.ResourceType)
+	builder.WriteString(", ")
+	builder.WriteString("resource_id=")
+	builder.WriteString(ra.ResourceID)
+	builder.WriteByte(')')
+	return builder.String()
+}
+
+// RoleAssignments is a parsable slice of RoleAssignment.
+type RoleAssignments []*RoleAssignment
@@ -0,0 +1,297 @@
+// Code generated by ent, DO NOT EDIT.
+
+package ent
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"entgo.io/ent/dialect/sql/sqlgraph"
+	"entgo.io/ent/schema/field"
+	"github.com/smxlong/users/ent/role"
+	"github.com/smxlong/users/ent/roleassignment"
+	"github.com/smxlong/users/ent/user"
+)
+
+// RoleAssignmentCreate is the builder for creating a RoleAssignment entity.
+type RoleAssignmentCreate struct {
+	config
+	mutation *RoleAssignmentMutation
+	hooks    []Hook
+}
+
+// SetCreatedAt sets the "created_at" field.
+func (rac *RoleAssignmentCreate) SetCreatedAt(t time.Time) *RoleAssignmentCreate {
+	rac.mutation.SetCreatedAt(t)
+	return rac
+}
+
+// SetUpdatedAt sets the "updated_at" field.
+func (rac *RoleAssignmentCreate) SetUpdatedAt(t time.Time) *RoleAssignmentCreate {
+	rac.mutation.SetUpdatedAt(t)
+	return rac
+}
+
+// SetResourceType sets the "resource_type" field.
+func (rac *RoleAssignmentCreate) SetResourceType(s string) *RoleAssignmentCreate {
+	rac.mutation.SetResourceType(s)
+	return rac
+}
+
+// SetResourceID sets the "resource_id" field.
+func (rac *RoleAssignmentCreate) SetResourceID(s string) *RoleAssignmentCreate {
+	rac.mutation.SetResourceID(s)
+	return rac
+}
+
+// SetUserID sets the "user" edge to the User entity by ID.
+func (rac *RoleAssignmentCreate) SetUserID(id int) *RoleAssignmentCreate {
+	rac.mutation.SetUserID(id)
+	return rac
+}
+
+// SetUser sets the "user" edge to the User entity.
+func (rac *RoleAssignmentCreate) SetUser(u *User) *RoleAssignmentCreate {
+	return rac.SetUserID(u.ID)
+}
+
+// SetRoleID sets the "role" edge to the Role entity by ID.
+func (rac *RoleAssignmentCreate) SetRoleID(id int) *RoleAssignmentCreate {
+	rac.mutation.SetRoleID(id)
+	return rac
+}
+
+// SetRole sets the "role" edge to the Role entity.
+func (rac *RoleAssignmentCreate) SetRole(r *Role) *RoleAssignmentCreate {
+	return rac.SetRoleID(r.ID)
+}
+
+// Mutation returns the RoleAssignmentMutation object of the builder.
+func (rac *RoleAssignmentCreate) Mutation() *RoleAssignmentMutation {
+	return rac.mutation
+}
+
+// Save creates the RoleAssignment in the database.
+func (rac *RoleAssignmentCreate) Save(ctx context.Context) (*RoleAssignment, error) {
+	return withHooks(ctx, rac.sqlSave, rac.mutation, rac.hooks)
+}
+
+// SaveX calls Save and panics if Save returns an error.
+func (rac *RoleAssignmentCreate) SaveX(ctx context.Context) *RoleAssignment {
+	v, err := rac.Save(ctx)
+	if err != nil {
+		panic(err)
+	}
+	return v
+}
+
+// Exec executes the query.
+func (rac *RoleAssignmentCreate) Exec(ctx context.Context) error {
+	_, err := rac.Save(ctx)
+	return err
+}
+
+// ExecX is like Exec, but panics if an error occurs.
+func (rac *RoleAssignmentCreate) ExecX(ctx context.Context) {
+	if err := rac.Exec(ctx); err != nil {
+		panic(err)
+	}
+}
+
+// check runs all checks and user-defined validators on the builder.
+func (rac *RoleAssignmentCreate) check() error {
+	if _, ok := rac.mutation.CreatedAt(); !ok {
+		return &ValidationError{Name: "created_at", err: errors.New(`ent: missing required field "RoleAssignment.created_at"`)}
+	}
+	if _, ok := rac.mutation.UpdatedAt(); !ok {
+		return &ValidationError{Name: "updated_at", err: errors.New(`ent: missing required field "RoleAssignment.updated_at"`)}
+	}
+	if _, ok := rac.mutation.ResourceType(); !ok {
+		return &ValidationError{Name: "resource_type", err: errors.New(`ent: missing required field "RoleAssignment.resource_type"`)}
+	}
+	if v, ok := rac.mutation.ResourceType(); ok {
+		if err := roleassignment.ResourceTypeValidator(v); err != nil {
+			return &ValidationError{Name: "resource_type", err: fmt.Errorf(`ent: validator failed for field "RoleAssignment.resource_type": %w`, err)}
+		}
+	}
+	if _, ok := rac.mutation.ResourceID(); !ok {
+		return &ValidationError{Name: "resource_id", err: errors.New(`ent: missing required field "RoleAssignment.resource_id"`)}
+	}
+	if v, ok := rac.mutation.ResourceID(); ok {
+		if err := roleassignment.ResourceIDValidator(v); err != nil {
+			return &ValidationError{Name: "resource_id", err: fmt.Errorf(`ent: validator failed for field "RoleAssignment.resource_id": %w`, err)}
+		}
+	}
+	if len(rac.mutation.UserIDs()) == 0 {
+		return &ValidationError{Name: "user", err: errors.New(`ent: missing required edge "RoleAssignment.user"`)}
+	}
+	if len(rac.mutation.RoleIDs()) == 0 {
+		return &ValidationError{Name: "role", err: errors.New(`ent: missing required edge "RoleAssignment.role"`)}
+	}
+	return nil
+}
+
+func (rac *RoleAssignmentCreate) sqlSave(ctx context.Context) (*RoleAssignment, error) {
+	if err := rac.check(); err != nil {
+		return nil, err
+	}
+	_node, _spec := rac.createSpec()
+	if err := sqlgraph.CreateNode(ctx, rac.driver, _spec); err != nil {
+		if sqlgraph.IsConstraintError(err) {
+			err = &ConstraintError{msg: err.Error(), wrap: err}
+		}
+		return nil, err
+	}
+	id := _spec.ID.Value.(int64)
+	_node.ID = int(id)
+	rac.mutation.id = &_node.ID
+	rac.mutation.done = true
+	return _node, nil
+}
+
+func (rac *RoleAssignmentCreate) createSpec() (*RoleAssignment, *sqlgraph.CreateSpec) {
+	var (
+		_node = &RoleAssignment{config: rac.config}
+		_spec = sqlgraph.NewCreateSpec(roleassignment.Table, sqlgraph.NewFieldSpec(roleassignment.FieldID, field.TypeInt))
+	)
+	if value, ok := rac.mutation.CreatedAt(); ok {
+		_spec.SetField(roleassignment.FieldCreatedAt, field.TypeTime, value)
+		_node.CreatedAt = value
+	}
+	if value, ok := rac.mutation.UpdatedAt(); ok {
+		_spec.SetField(roleassignment.FieldUpdatedAt, field.TypeTime, value)
+		_node.UpdatedAt = value
+	}
+	if value, ok := rac.mutation.ResourceType(); ok {
+		_spec.SetField(roleassignment.FieldResourceType, field.TypeString, value)
+		_node.ResourceType = value
+	}
+	if value, ok := rac.mutation.ResourceID(); ok {
+		_spec.SetField(roleassignment.FieldResourceID, field.TypeString, value)
+		_node.ResourceID = value
+	}
+	if nodes := rac.mutation.UserIDs(); len(nodes) > 0 {
+		edge := &sqlgraph.EdgeSpec{
+			Rel:     sqlgraph.M2O,
+			Inverse: true,
+			Table:   roleassignment.UserTable,
+			Columns: []string{roleassignment.UserColumn},
+			Bidi:    false,
+			Target: &sqlgraph.EdgeTarget{
+				IDSpec: sqlgraph.NewFieldSpec(user.FieldID, field.TypeInt),
+			},
+		}
+		for _, k := range nodes {
+			edge.Target.Nodes = append(edge.Target.Nodes, k)
+		}
+		_node.user_role_assignments = &nodes[0]
+		_spec.Edges = append(_spec.Edges, edge)
+	}
+	if nodes := rac.mutation.RoleIDs(); len(nodes) > 0 {
+		edge := &sqlgraph.EdgeSpec{
+			Rel:     sqlgraph.M2O,
+			Inverse: true,
+			Table:   roleassignment.RoleTable,
+			Columns: []string{roleassignment.RoleColumn},
+			Bidi:    false,
+			Target: &sqlgraph.EdgeTarget{
+				IDSpec: sqlgraph.NewFieldSpec(role.FieldID, field.TypeInt),
+			},
+		}
+		for _, k := range nodes {
+			edge.Target.Nodes = append(edge.Target.Nodes, k)
+		}
+		_node.role_assignments = &nodes[0]
+		_spec.Edges = append(_spec.Edges, edge)
+	}
+	return _node, _spec
+}
+
+// RoleAssignmentCreateBulk is the builder for creating many RoleAssignment entities in bulk.
+type RoleAssignmentCreateBulk struct {
+	config
+	err      error
+	builders []*RoleAssignmentCreate
+}
+
+// Save creates the RoleAssignment entities in the database.
+func (racb *RoleAssignmentCreateBulk) Save(ctx context.Context) ([]*RoleAssignment, error) {
+	if racb.err != nil {
+		return nil, racb.err
+	}
+	specs := make([]*sqlgraph.CreateSpec, len(racb.builders))
+	nodes := make([]*RoleAssignment, len(racb.builders))
+	mutators := make([]Mutator, len(racb.builders))
+	for i := range racb.builders {
+		func(i int, root context.Context) {
+			builder := racb.builders[i]
+			var mut Mutator = MutateFunc(func(ctx context.Context, m Mutation) (Value, error) {
+				mutation, ok := m.(*RoleAssignmentMutation)
+				if !ok {
+					return nil, fmt.Errorf("unexpected mutation type %T", m)
+				}
+				if err := builder.check(); err != nil {
+					return nil, err
+				}
+				builder.mutation = mutation
+				var err error
+				nodes[i], specs[i] = builder.createSpec()
+				if i < len(mutators)-1 {
+					_, err = mutators[i+1].Mutate(root, racb.builders[i+1].mutation)
+				} else {
+					spec := &sqlgraph.BatchCreateSpec{Nodes: specs}
+					// Invoke the actual operation on the latest mutation in the chain.
+					if err = sqlgraph.BatchCreate(ctx, racb.driver, spec); err != nil {
+						if sqlgraph.IsConstraintError(err) {
+							err = &ConstraintError{msg: err.Error(), wrap: err}
+						}
+					}
+				}
+				if err != nil {
+					return nil, err
+				}
+				mutation.id = &nodes[i].ID
+				if specs[i].ID.Value != nil {
+					id := specs[i].ID.Value.(int64)
+					nodes[i].ID = int(id)
+				}
+				mutation.done = true
+				return nodes[i], nil
+			})
+			for i := len(builder.hooks) - 1; i >= 0; i-- {
+				mut = builder.hooks[i](mut)
+			}
+			mutators[i] = mut
+		}(i, ctx)
+	}
+	if len(mutators) > 0 {
+		if _, err := mutators[0].Mutate(ctx, racb.builders[0].mutation); err != nil {
+			return nil, err
+		}
+	}
+	return nodes, nil
+}
+
+// SaveX is like Save, but panics if an error occurs.
+func (racb *RoleAssignmentCreateBulk) SaveX(ctx context.Context) []*RoleAssignment {
+	v, err := racb.Save(ctx)
+	if err != nil {
+		panic(err)
+	}
+	return v
+}
+
+// Exec executes the query.
+func (racb *RoleAssignmentCreateBulk) Exec(ctx context.Context) error {
+	_, err := racb.Save(ctx)
+	return err
+}
+
+// ExecX is like Exec, but panics if an error occurs.
+func (racb *RoleAssignmentCreateBulk) ExecX(ctx context.Context) {
+	if err := racb.Exec(ctx); err != nil {
+		panic(err)
+	}
+}
@@ -0,0 +1,689 @@
+// Code generated by ent, DO NOT EDIT.
+
+package ent
+
+import (
+	"context"
+	"fmt"
+	"math"
+
+	"entgo.io/ent"
+	"entgo.io/ent/dialect/sql"
+	"entgo.io/ent/dialect/sql/sqlgraph"
+	"entgo.io/ent/schema/field"
+	"github.com/smxlong/users/ent/predicate"
+	"github.com/smxlong/users/ent/role"
+	"github.com/smxlong/users/ent/roleassignment"
+	"github.com/smxlong/users/ent/user"
+)
+
+// RoleAssignmentQuery is the builder for querying RoleAssignment entities.
+type RoleAssignmentQuery struct {
+	config
+	ctx        *QueryContext
+	order      []roleassignment.OrderOption
+	inters     []Interceptor
+	predicates []predicate.RoleAssignment
+	withUser   *UserQuery
+	withRole   *RoleQuery
+	withFKs    bool
+	// intermediate query (i.e. traversal path).
+	sql  *sql.Selector
+	path func(context.Context) (*sql.Selector, error)
+}
+
+// Where adds a new predicate for the RoleAssignmentQuery builder.
+func (raq *RoleAssignmentQuery) Where(ps ...predicate.RoleAssignment) *RoleAssignmentQuery {
+	raq.predicates = append(raq.predicates, ps...)
+	return raq
+}
+
+// Limit the number of records to be returned by this query.
+func (raq *RoleAssignmentQuery) Limit(limit int) *RoleAssignmentQuery {
+	raq.ctx.Limit = &limit
+	return raq
+}
+
+// Offset to start from.
+func (raq *RoleAssignmentQuery) Offset(offset int) *RoleAssignmentQuery {
+	raq.ctx.Offset = &offset
+	return raq
+}
+
+// Unique configures the query builder to filter duplicate records on query.
+// By default, unique is set to true, and can be disabled using this method.
+func (raq *RoleAssignmentQuery) Unique(unique bool) *RoleAssignmentQuery {
+	raq.ctx.Unique = &unique
+	return raq
+}
+
+// Order specifies how the records should be ordered.
+func (raq *RoleAssignmentQuery) Order(o ...roleassignment.OrderOption) *RoleAssignmentQuery {
+	raq.order = append(raq.order, o...)
+	return raq
+}
+
+// QueryUser chains the current query on the "user" edge.
+func (raq *RoleAssignmentQuery) QueryUser() *UserQuery {
+	query := (&UserClient{config: raq.config}).Query()
+	query.path = func(ctx context.Context) (fromU *sql.Selector, err error) {
+		if err := raq.prepareQuery(ctx); err != nil {
+			return nil, err
+		}
+		selector := raq.sqlQuery(ctx)
+		if err := selector.Err(); err != nil {
+			return nil, err
+		}
+		step := sqlgraph.NewStep(
+			sqlgraph.From(roleassignment.Table, roleassignment.FieldID, selector),
+			sqlgraph.To(user.Table, user.FieldID),
+			sqlgraph.Edge(sqlgraph.M2O, true, roleassignment.UserTable, roleassignment.UserColumn),
+		)
+		fromU = sqlgraph.SetNeighbors(raq.driver.Dialect(), step)
+		return fromU, nil
+	}
+	return query
+}
+
+// QueryRole chains the current query on the "role" edge.
+func (raq *RoleAssignmentQuery) QueryRole() *RoleQuery {
+	query := (&RoleClient{config: raq.config}).Query()
+	query.path = func(ctx context.Context) (fromU *sql.Selector, err error) {
+		if err := raq.prepareQuery(ctx); err != nil {
+			return nil, err
+		}
+		selector := raq.sqlQuery(ctx)
+		if err := selector.Err(); err != nil {
+			return nil, err
+		}
+		step := sqlgraph.NewStep(
+			sqlgraph.From(roleassignment.Table, roleassignment.FieldID, selector),
+			sqlgraph.To(role.Table, role.FieldID),
+			sqlgraph.Edge(sqlgraph.M2O, true, roleassignment.RoleTable, roleassignment.RoleColumn),
+		)
+		fromU = sqlgraph.SetNeighbors(raq.driver.Dialect(), step)
+		return fromU, nil
+	}
+	return query
+}
+
+// First returns the first RoleAssignment entity from the query.
+// Returns a *NotFoundError when no RoleAssignment was found.
+func (raq *RoleAssignmentQuery) First(ctx context.Context) (*RoleAssignment, error) {
+	nodes, err := raq.Limit(1).All(setContextOp(ctx, raq.ctx, ent.OpQueryFirst))
+	if err != nil {
+		return nil, err
+	}
+	if len(nodes) == 0 {
+		return nil, &NotFoundError{roleassignment.Label}
+	}
+	return nodes[0], nil
+}
+
+// FirstX is like First, but panics if an error occurs.
+func (raq *RoleAssignmentQuery) FirstX(ctx context.Context) *RoleAssignment {
+	node, err := raq.First(ctx)
+	if err != nil && !IsNotFound(err) {
+		panic(err)
+	}
+	return node
+}
+
+// FirstID returns the first RoleAssignment ID from the query.
+// Returns a *NotFoundError when no RoleAssignment ID was found.
+func (raq *RoleAssignmentQuery) FirstID(ctx context.Context) (id int, err error) {
+	var ids []int
+	if ids, err = raq.Limit(1).IDs(setContextOp(ctx, raq.ctx, ent.OpQueryFirstID)); err != nil {
+		return
+	}
+	if len(ids) == 0 {
+		err = &NotFoundError{roleassignment.Label}
+		return
+	}
+	return ids[0], nil
+}
+
+// FirstIDX is like FirstID, but panics if an error occurs.
+func (raq *RoleAssignmentQuery) FirstIDX(ctx context.Context) int {
+	id, err := raq.FirstID(ctx)
+	if err != nil && !IsNotFound(err) {
+		panic(err)
+	}
+	return id
+}
+
+// Only returns a single RoleAssignment entity found by the query, ensuring it only returns one.
+// Returns a *NotSingularError when more than one RoleAssignment entity is found.
+// Returns a *NotFoundError when no RoleAssignment entities are found.
+func (raq *RoleAssignmentQuery) Only(ctx context.Context) (*RoleAssignment, error) {
+	nodes, err := raq.Limit(2).All(setContextOp(ctx, raq.ctx, ent.OpQueryOnly))
+	if err != nil {
+		return nil, err
+	}
+	switch len(nodes) {
+	case 1:
+		return nodes[0], nil
+	case 0:
+		return nil, &NotFoundError{roleassignment.Label}
+	default:
+		return nil, &NotSingularError{roleassignment.Label}
+	}
+}
+
+// OnlyX is like Only, but panics if an error occurs.
+func (raq *RoleAssignmentQuery) OnlyX(ctx context.Context) *RoleAssignment {
+	node, err := raq.Only(ctx)
+	if err != nil {
+		panic(err)
+	}
+	return node
+}
+
+// OnlyID is like Only, but returns the only RoleAssignment ID in the query.
+// Returns a *NotSingularError when more than one RoleAssignment ID is found.
+// Returns a *NotFoundError when no entities are found.
+func (raq *RoleAssignmentQuery) OnlyID(ctx context.Context) (id int, err error) {
+	var ids []int
+	if ids, err = raq.Limit(2).IDs(setContextOp(ctx, raq.ctx, ent.OpQueryOnlyID)); err != nil {
+		return
+	}
+	switch len(ids) {
+	case 1:
+		id = ids[0]
+	case 0:
+		err = &NotFoundError{roleassignment.Label}
+	default:
+		err = &NotSingularError{roleassignment.Label}
+	}
+	return
+}
+
+// OnlyIDX is like OnlyID, but panics if an error occurs.
+func (raq *RoleAssignmentQuery) OnlyIDX(ctx context.Context) int {
+	id, err := raq.OnlyID(ctx)
+	if err != nil {
+		panic(err)
+	}
+	return id
+}
+
+// All executes the query and returns a list of RoleAssignments.
+func (raq *RoleAssignmentQuery) All(ctx context.Context) ([]*RoleAssignment, error) {
+	ctx = setContextOp(ctx, raq.ctx, ent.OpQueryAll)
+	if err := raq.prepareQuery(ctx); err != nil {
+		return nil, err
+	}
+	qr := querierAll[[]*RoleAssignment, *RoleAssignmentQuery]()
+	return withInterceptors[[]*RoleAssignment](ctx, raq, qr, raq.inters)
+}
+
+// AllX is like All, but panics if an error occurs.
+func (raq *RoleAssignmentQuery) AllX(ctx context.Context) []*RoleAssignment {
+	nodes, err := raq.All(ctx)
+	if err != nil {
+		panic(err)
+	}
+	return nodes
+}
+
+// IDs executes the query and returns a list of RoleAssignment IDs.
+func (raq *RoleAssignmentQuery) IDs(ctx context.Context) (ids []int, err error) {
+	if raq.ctx.Unique == nil && raq.path != nil {
+		raq.Unique(true)
+	}
+	ctx = setContextOp(ctx, raq.ctx, ent.OpQueryIDs)
+	if err = raq.Select(roleassignment.FieldID).Scan(ctx, &ids); err != nil {
+		return nil, err
+	}
+	return ids, nil
+}
+
+// IDsX is like IDs, but panics if an error occurs.
+func (raq *RoleAssignmentQuery) IDsX(ctx context.Context) []int {
+	ids, err := raq.IDs(ctx)
+	if err != nil {
+		panic(err)
+	}
+	return ids
+}
+
+// Count returns the count of the given query.
+func (raq *RoleAssignmentQuery) Count(ctx context.Context) (int, error) {
+	ctx = setContextOp(ctx, raq.ctx, ent.OpQueryCount)
+	if err := raq.prepareQuery(ctx); err != nil {
+		return 0, err
+	}
+	return withInterceptors[int](ctx, raq, querierCount[*RoleAssignmentQuery](), raq.inters)
+}
+
+// CountX is like Count, but panics if an error occurs.
+func (raq *RoleAssignmentQuery) CountX(ctx context.Context) int {
+	count, err := raq.Count(ctx)
+	if err != nil {
+		panic(err)
+	}
+	return count
+}
+
+// Exist returns true if the query has elements in the graph.
+func (raq *RoleAssignmentQuery) Exist(ctx context.Context) (bool, error) {
+	ctx = setContextOp(ctx, raq.ctx, ent.OpQueryExist)
+	switch _, err := raq.FirstID(ctx); {
+	case IsNotFound(err):
+		return false, nil
+	case err != nil:
+		return false, fmt.Errorf("ent: check existence: %w", err)
+	default:
+		return true, nil
+	}
+}
+
+// ExistX is like Exist, but panics if an error occurs.
+func (raq *RoleAssignmentQuery) ExistX(ctx context.Context) bool {
+	exist, err := raq.Exist(ctx)
+	if err != nil {
+		panic(err)
+	}
+	return exist
+}
+
+// Clone returns a duplicate of the RoleAssignmentQuery builder, including all associated steps. It can be
+// used to prepare common query builders and use them differently after the clone is made.
+func (raq *RoleAssignmentQuery) Clone() *RoleAssignmentQuery {
+	if raq == nil {
+		return nil
+	}
+	return &RoleAssignmentQuery{
+		config:     raq.config,
+		ctx:        raq.ctx.Clone(),
+		order:      append([]roleassignment.OrderOption{}, raq.order...),
+		inters:     append([]Interceptor{}, raq.inters...),
+		predicates: append([]predicate.RoleAssignment{}, raq.predicates...),
+		withUser:   raq.withUser.Clone(),
+		withRole:   raq.withRole.Clone(),
+		// clone intermediate query.
+		sql:  raq.sql.Clone(),
+		path: raq.path,
+	}
+}
+
+// WithUser tells the query-builder to eager-load the nodes that are connected to
+// the "user" edge. The optional arguments are used to configure the query builder of the edge.
+func (raq *RoleAssignmentQuery) WithUser(opts ...func(*UserQuery)) *RoleAssignmentQuery {
+	query := (&UserClient{config: raq.config}).Query()
+	for _, opt := range opts {
+		opt(query)
+	}
+	raq.withUser = query
+	return raq
+}
+
+// WithRole tells the query-builder to eager-load the nodes that are connected to
+// the "role" edge. The optional arguments are used to configure the query builder of the edge.
+func (raq *RoleAssignmentQuery) WithRole(opts ...func(*RoleQuery)) *RoleAssignmentQuery {
+	query := (&RoleClient{config: raq.config}).Query()
+	for _, opt := range opts {
+		opt(query)
+	}
+	raq.withRole = query
+	return raq
+}
+
+// GroupBy is used to group vertices by one or more fields/columns.
+// It is often used with aggregate functions, like: count, max, mean, min, sum.
+//
+// Example:
+//
+//	var v []struct {
+//		CreatedAt time.Time `json:"created_at,omitempty"`
+//		Count int `json:"count,omitempty"`
+//	}
+//
+//	client.RoleAssignment.Query().
+//		GroupBy(roleassignment.FieldCreatedAt).
+//		Aggregate(ent.Count()).
+//		Scan(ctx, &v)
+func (raq *RoleAssignmentQuery) GroupBy(field string, fields ...string) *RoleAssignmentGroupBy {
+	raq.ctx.Fields = append([]string{field}, fields...)
+	grbuild := &RoleAssignmentGroupBy{build: raq}
+	grbuild.flds = &raq.ctx.Fields
+	grbuild.label = roleassignment.Label
+	grbuild.scan = grbuild.Scan
+	return grbuild
+}
+
+// Select allows the selection one or more fields/columns for the given query,
+// instead of selecting all fields in the entity.
+//
+// Example:
+//
+//	var v []struct {
+//		CreatedAt time.Time `json:"created_at,omitempty"`
+//	}
+//
+//	client.RoleAssignment.Query().
+//		Select(roleassignment.FieldCreatedAt).
+//		Scan(ctx, &v)
+func (raq *RoleAssignmentQuery) Select(fields ...string) *RoleAssignmentSelect {
+	raq.ctx.Fields = append(raq.ctx.Fields, fields...)
+	sbuild := &RoleAssignmentSelect{RoleAssignmentQuery: raq}
+	sbuild.label = roleassignment.Label
+	sbuild.flds, sbuild.scan = &raq.ctx.Fields, sbuild.Scan
+	return sbuild
+}
+
+// Aggregate returns a RoleAssignmentSelect configured with the given aggregations.
+func (raq *RoleAssignmentQuery) Aggregate(fns ...AggregateFunc) *RoleAssignmentSelect {
+	return raq.Select().Aggregate(fns...)
+}
+
+func (raq *RoleAssignmentQuery) prepareQuery(ctx context.Context) error {
+	for _, inter := range raq.inters {
+		if inter == nil {
+			return fmt.Errorf("ent: uninitialized interceptor (forgotten import ent/runtime?)")
+		}
+		if trv, ok := inter.(Traverser); ok {
+			if err := trv.Traverse(ctx, raq); err != nil {
+				return err
+			}
+		}
+	}
+	for _, f := range raq.ctx.Fields {
+		if !roleassignment.ValidColumn(f) {
+			return &ValidationError{Name: f, err: fmt.Errorf("ent: invalid field %q for query", f)}
+		}
+	}
+	if raq.path != nil {
+		prev, err := raq.path(ctx)
+		if err != nil {
+			return err
+		}
+		raq.sql = prev
+	}
+	return nil
+}
+
+func (raq *RoleAssignmentQuery) sqlAll(ctx context.Context, hooks ...queryHook) ([]*RoleAssignment, error) {
+	var (
+		nodes       = []*RoleAssignment{}
+		withFKs     = raq.withFKs
+		_spec       = raq.querySpec()
+		loadedTypes = [2]bool{
+			raq.withUser != nil,
+			raq.withRole != nil,
+		}
+	)
+	if raq.withUser != nil || raq.withRole != nil {
+		withFKs = true
+	}
+	if withFKs {
+		_spec.Node.Columns = append(_spec.Node.Columns, roleassignment.ForeignKeys...)
+	}
+	_spec.ScanValues = func(columns []string) ([]any, error) {
+		return (*RoleAssignment).scanValues(nil, columns)
+	}
+	_spec.Assign = func(columns []string, values []any) error {
+		node := &RoleAssignment{config: raq.config}
+		nodes = append(nodes, node)
+		node.Edges.loadedTypes = loadedTypes
+		return node.assignValues(columns, values)
+	}
+	for i := range hooks {
+		hooks[i](ctx, _spec)
+	}
+	if err := sqlgraph.QueryNodes(ctx, raq.driver, _spec); err != nil {
+		return nil, err
+	}
+	if len(nodes) == 0 {
+		return nodes, nil
+	}
+	if query := raq.withUser; query != nil {
+		if err := raq.loadUser(ctx, query, nodes, nil,
+			func(n *RoleAssignment, e *User) { n.Edges.User = e }); err != nil {
+			return nil, err
+		}
+	}
+	if query := raq.withRole; query != nil {
+		if err := raq.loadRole(ctx, query, nodes, nil,
+			func(n *RoleAssignment, e *Role) { n.Edges.Role = e }); err != nil {
+			return nil, err
+		}
+	}
+	return nodes, nil
+}
+
+func (raq *RoleAssignmentQuery) loadUser(ctx context.Context, query *UserQuery, nodes []*RoleAssignment, init func(*RoleAssignment), assign func(*RoleAssignment, *User)) error {
+	ids := make([]int, 0, len(nodes))
+	nodeids := make(map[int][]*RoleAssignment)
+	for i := range nodes {
+		if nodes[i].user_role_assignments == nil {
+			continue
+		}
+		fk := *nodes[i].user_role_assignments
+		if _, ok := nodeids[fk]; !ok {
+			ids = append(ids, fk)
+		}
+		nodeids[fk] = append(nodeids[fk], nodes[i])
+	}
+	if len(ids) == 0 {
+		return nil
+	}
+	query.Where(user.IDIn(ids...))
+	neighbors, err := query.All(ctx)
+	if err != nil {
+		return err
+	}
+	for _, n := range neighbors {
+		nodes, ok := nodeids[n.ID]
+		if !ok {
+			return fmt.Errorf(`unexpected foreign-key "user_role_assignments" returned %v`, n.ID)
+		}
+		for i := range nodes {
+			assign(nodes[i], n)
+		}
+	}
+	return nil
+}
+func (raq *RoleAssignmentQuery) loadRole(ctx context.Context, query *RoleQuery, nodes []*RoleAssignment, init func(*RoleAssignment), assign func(*RoleAssignment, *Role)) error {
+	ids := make([]int, 0, len(nodes))
+	nodeids := make(map[int][]*RoleAssignment)
+	for i := range nodes {
+		if nodes[i].role_assignments == nil {
+			continue
+		}
+		fk := *nodes[i].role_assignments
+		if _, ok := nodeids[fk]; !ok {
+			ids = append(ids, fk)
+		}
+		nodeids[fk] = append(nodeids[fk], nodes[i])
+	}
+	if len(ids) == 0 {
+		return nil
+	}
+	query.Where(role.IDIn(ids...))
+	neighbors, err := query.All(ctx)
+	if err != nil {
+		return err
+	}
+	for _, n := range neighbors {
+		nodes, ok := nodeids[n.ID]
+		if !ok {
+			return fmt.Errorf(`unexpected foreign-key "role_assignments" returned %v`, n.ID)
+		}
+		for i := range nodes {
+			assign(nodes[i], n)
+		}
+	}
+	return nil
+}
+
+func (raq *RoleAssignmentQuery) sqlCount(ctx context.Context) (int, error) {
+	_spec := raq.querySpec()
+	_spec.Node.Columns = raq.ctx.Fields
+	if len(raq.ctx.Fields) > 0 {
+		_spec.Unique = raq.ctx.Unique != nil && *raq.ctx.Unique
+	}
+	return sqlgraph.CountNodes(ctx, raq.driver, _spec)
+}
+
+func (raq *RoleAssignmentQuery) querySpec() *sqlgraph.QuerySpec {
+	_spec := sqlgraph.NewQuerySpec(roleassignment.Table, roleassignment.Columns, sqlgraph.NewFieldSpec(roleassignment.FieldID, field.TypeInt))
+	_spec.From = raq.sql
+	if unique := raq.ctx.Unique; unique != nil {
+		_spec.Unique = *unique
+	} else if raq.path != nil {
+		_spec.Unique = true
+	}
+	if fields := raq.ctx.Fields; len(fields) > 0 {
+		_spec.Node.Columns = make([]string, 0, len(fields))
+		_spec.Node.Columns = append(_spec.Node.Columns, roleassignment.FieldID)
+		for i := range fields {
+			if fields[i] != roleassignment.FieldID {
+				_spec.Node.Columns = append(_spec.Node.Columns, fields[i])
+			}
+		}
+	}
+	if ps := raq.predicates; len(ps) > 0 {
+		_spec.Predicate = func(selector *sql.Selector) {
+			for i := range ps {
+				ps[i](selector)
+			}
+		}
+	}
+	if limit := raq.ctx.Limit; limit != nil {
+		_spec.Limit = *limit
+	}
+	if offset := raq.ctx.Offset; offset != nil {
+		_spec.Offset = *offset
+	}
+	if ps := raq.order; len(ps) > 0 {
+		_spec.Order = func(selector *sql.Selector) {
+			for i := range ps {
+				ps[i](selector)
+			}
+		}
+	}
+	return _spec
+}
+
+func (raq *RoleAssignmentQuery) sqlQuery(ctx context.Context) *sql.Selector {
+	builder := sql.Dialect(raq.driver.Dialect())
+	t1 := builder.Table(roleassignment.Table)
+	columns := raq.ctx.Fields
+	if len(columns) == 0 {
+		columns = roleassignment.Columns
+	}
+	selector := builder.Select(t1.Columns(columns...)...).From(t1)
+	if raq.sql != nil {
+		selector = raq.sql
+		selector.Select(selector.Columns(columns...)...)
+	}
+	if raq.ctx.Unique != nil && *raq.ctx.Unique {
+		selector.Distinct()
+	}
+	for _, p := range raq.predicates {
+		p(selector)
+	}
+	for _, p := range raq.order {
+		p(selector)
+	}
+	if offset := raq.ctx.Offset; offset != nil {
+		// limit is mandatory for offset clause. We start
+		// with default value, and override it below if needed.
+		selector.Offset(*offset).Limit(math.MaxInt32)
+	}
+	if limit := raq.ctx.Limit; limit != nil {
+		selector.Limit(*limit)
+	}
+	return selector
+}
+
+// RoleAssignmentGroupBy is the group-by builder for RoleAssignment entities.
+type RoleAssignmentGroupBy struct {
+	selector
+	build *RoleAssignmentQuery
+}
+
+// Aggregate adds the given aggregation functions to the group-by query.
+func (ragb *RoleAssignmentGroupBy) Aggregate(fns ...AggregateFunc) *RoleAssignmentGroupBy {
+	ragb.fns = append(ragb.fns, fns...)
+	return ragb
+}
+
+// Scan applies the selector query and scans the result into the given value.
+func (ragb *RoleAssignmentGroupBy) Scan(ctx context.Context, v any) error {
+	ctx = setContextOp(ctx, ragb.build.ctx, ent.OpQueryGroupBy)
+	if err := ragb.build.prepareQuery(ctx); err != nil {
+		return err
+	}
+	return scanWithInterceptors[*RoleAssignmentQuery, *RoleAssignmentGroupBy](ctx, ragb.build, ragb, ragb.build.inters, v)
+}
+
+func (ragb *RoleAssignmentGroupBy) sqlScan(ctx context.Context, root *RoleAssignmentQuery, v any) error {
+	selector := root.sqlQuery(ctx).Select()
+	aggregation := make([]string, 0, len(ragb.fns))
+	for _, fn := range ragb.fns {
+		aggregation = append(aggregation, fn(selector))
+	}
+	if len(selector.SelectedColumns()) == 0 {
+		columns := make([]string, 0, len(*ragb.flds)+len(ragb.fns))
+		for _, f := range *ragb.flds {
+			columns = append(columns, selector.C(f))
+		}
+		columns = append(columns, aggregation...)
+		selector.Select(columns...)
+	}
+	selector.GroupBy(selector.Columns(*ragb.flds...)...)
+	if err := selector.Err(); err != nil {
+		return err
+	}
+	rows := &sql.Rows{}
+	query, args := selector.Query()
+	if err := ragb.build.driver.Query(ctx, query, args, rows); err != nil {
+		return err
+	}
+	defer rows.Close()
+	return sql.ScanSlice(rows, v)
+}
+
+// RoleAssignmentSelect is the builder for selecting fields of RoleAssignment entities.
+type RoleAssignmentSelect struct {
+	*RoleAssignmentQuery
+	selector
+}
+
+// Aggregate adds the given aggregation functions to the selector query.
+func (ras *RoleAssignmentSelect) Aggregate(fns ...AggregateFunc) *RoleAssignmentSelect {
+	ras.fns = append(ras.fns, fns...)
+	return ras
+}
+
+// Scan applies the selector query and scans the result into the given value.
+func (ras *RoleAssignmentSelect) Scan(ctx context.Context, v any) error {
+	ctx = setContextOp(ctx, ras.ctx, ent.OpQuerySelect)
+	if err := ras.prepareQuery(ctx); err != nil {
+		return err
+	}
+	return scanWithInterceptors[*RoleAssignmentQuery, *RoleAssignmentSelect](ctx, ras.RoleAssignmentQuery, ras, ras.inters, v)
+}
+
+func (ras *RoleAssignmentSelect) sqlScan(ctx context.Context, root *RoleAssignmentQuery, v any) error {
+	selector := root.sqlQuery(ctx)
+	aggregation := make([]string, 0, len(ras.fns))
+	for _, fn := range ras.fns {
+		aggregation = append(aggregation, fn(selector))
+	}
+	switch n := len(*ras.selector.flds); {
+	case n == 0 && len(aggregation) > 0:
+		selector.Select(aggregation...)
+	case n != 0 && len(aggregation) > 0:
+		selector.AppendSelect(aggregation...)
+	}
+	rows := &sql.Rows{}
+	query, args := selector.Query()
+	if err := ras.driver.Query(ctx, query, args, rows); err != nil {
+		return err
+	}
+	defer rows.Close()
+	return sql.ScanSlice(rows, v)
+}
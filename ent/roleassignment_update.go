@@ -0,0 +1,512 @@
+// Code generated by ent, DO NOT EDIT.
+
+package ent
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"entgo.io/ent/dialect/sql"
+	"entgo.io/ent/dialect/sql/sqlgraph"
+	"entgo.io/ent/schema/field"
+	"github.com/smxlong/users/ent/predicate"
+	"github.com/smxlong/users/ent/role"
+	"github.com/smxlong/users/ent/roleassignment"
+	"github.com/smxlong/users/ent/user"
+)
+
+// RoleAssignmentUpdate is the builder for updating RoleAssignment entities.
+type RoleAssignmentUpdate struct {
+	config
+	hooks    []Hook
+	mutation *RoleAssignmentMutation
+}
+
+// Where appends a list predicates to the RoleAssignmentUpdate builder.
+func (rau *RoleAssignmentUpdate) Where(ps ...predicate.RoleAssignment) *RoleAssignmentUpdate {
+	rau.mutation.Where(ps...)
+	return rau
+}
+
+// SetUpdatedAt sets the "updated_at" field.
+func (rau *RoleAssignmentUpdate) SetUpdatedAt(t time.Time) *RoleAssignmentUpdate {
+	rau.mutation.SetUpdatedAt(t)
+	return rau
+}
+
+// SetNillableUpdatedAt sets the "updated_at" field if the given value is not nil.
+func (rau *RoleAssignmentUpdate) SetNillableUpdatedAt(t *time.Time) *RoleAssignmentUpdate {
+	if t != nil {
+		rau.SetUpdatedAt(*t)
+	}
+	return rau
+}
+
+// SetResourceType sets the "resource_type" field.
+func (rau *RoleAssignmentUpdate) SetResourceType(s string) *RoleAssignmentUpdate {
+	rau.mutation.SetResourceType(s)
+	return rau
+}
+
+// SetNillableResourceType sets the "resource_type" field if the given value is not nil.
+func (rau *RoleAssignmentUpdate) SetNillableResourceType(s *string) *RoleAssignmentUpdate {
+	if s != nil {
+		rau.SetResourceType(*s)
+	}
+	return rau
+}
+
+// SetResourceID sets the "resource_id" field.
+func (rau *RoleAssignmentUpdate) SetResourceID(s string) *RoleAssignmentUpdate {
+	rau.mutation.SetResourceID(s)
+	return rau
+}
+
+// SetNillableResourceID sets the "resource_id" field if the given value is not nil.
+func (rau *RoleAssignmentUpdate) SetNillableResourceID(s *string) *RoleAssignmentUpdate {
+	if s != nil {
+		rau.SetResourceID(*s)
+	}
+	return rau
+}
+
+// SetUserID sets the "user" edge to the User entity by ID.
+func (rau *RoleAssignmentUpdate) SetUserID(id int) *RoleAssignmentUpdate {
+	rau.mutation.SetUserID(id)
+	return rau
+}
+
+// SetUser sets the "user" edge to the User entity.
+func (rau *RoleAssignmentUpdate) SetUser(u *User) *RoleAssignmentUpdate {
+	return rau.SetUserID(u.ID)
+}
+
+// SetRoleID sets the "role" edge to the Role entity by ID.
+func (rau *RoleAssignmentUpdate) SetRoleID(id int) *RoleAssignmentUpdate {
+	rau.mutation.SetRoleID(id)
+	return rau
+}
+
+// SetRole sets the "role" edge to the Role entity.
+func (rau *RoleAssignmentUpdate) SetRole(r *Role) *RoleAssignmentUpdate {
+	return rau.SetRoleID(r.ID)
+}
+
+// Mutation returns the RoleAssignmentMutation object of the builder.
+func (rau *RoleAssignmentUpdate) Mutation() *RoleAssignmentMutation {
+	return rau.mutation
+}
+
+// ClearUser clears the "user" edge to the User entity.
+func (rau *RoleAssignmentUpdate) ClearUser() *RoleAssignmentUpdate {
+	rau.mutation.ClearUser()
+	return rau
+}
+
+// ClearRole clears the "role" edge to the Role entity.
+func (rau *RoleAssignmentUpdate) ClearRole() *RoleAssignmentUpdate {
+	rau.mutation.ClearRole()
+	return rau
+}
+
+// Save executes the query and returns the number of nodes affected by the update operation.
+func (rau *RoleAssignmentUpdate) Save(ctx context.Context) (int, error) {
+	return withHooks(ctx, rau.sqlSave, rau.mutation, rau.hooks)
+}
+
+// SaveX is like Save, but panics if an error occurs.
+func (rau *RoleAssignmentUpdate) SaveX(ctx context.Context) int {
+	affected, err := rau.Save(ctx)
+	if err != nil {
+		panic(err)
+	}
+	return affected
+}
+
+// Exec executes the query.
+func (rau *RoleAssignmentUpdate) Exec(ctx context.Context) error {
+	_, err := rau.Save(ctx)
+	return err
+}
+
+// ExecX is like Exec, but panics if an error occurs.
+func (rau *RoleAssignmentUpdate) ExecX(ctx context.Context) {
+	if err := rau.Exec(ctx); err != nil {
+		panic(err)
+	}
+}
+
+// check runs all checks and user-defined validators on the builder.
+func (rau *RoleAssignmentUpdate) check() error {
+	if v, ok := rau.mutation.ResourceType(); ok {
+		if err := roleassignment.ResourceTypeValidator(v); err != nil {
+			return &ValidationError{Name: "resource_type", err: fmt.Errorf(`ent: validator failed for field "RoleAssignment.resource_type": %w`, err)}
+		}
+	}
+	if v, ok := rau.mutation.ResourceID(); ok {
+		if err := roleassignment.ResourceIDValidator(v); err != nil {
+			return &ValidationError{Name: "resource_id", err: fmt.Errorf(`ent: validator failed for field "RoleAssignment.resource_id": %w`, err)}
+		}
+	}
+	if rau.mutation.UserCleared() && len(rau.mutation.UserIDs()) > 0 {
+		return errors.New(`ent: clearing a required unique edge "RoleAssignment.user"`)
+	}
+	if rau.mutation.RoleCleared() && len(rau.mutation.RoleIDs()) > 0 {
+		return errors.New(`ent: clearing a required unique edge "RoleAssignment.role"`)
+	}
+	return nil
+}
+
+func (rau *RoleAssignmentUpdate) sqlSave(ctx context.Context) (n int, err error) {
+	if err := rau.check(); err != nil {
+		return n, err
+	}
+	_spec := sqlgraph.NewUpdateSpec(roleassignment.Table, roleassignment.Columns, sqlgraph.NewFieldSpec(roleassignment.FieldID, field.TypeInt))
+	if ps := rau.mutation.predicates; len(ps) > 0 {
+		_spec.Predicate = func(selector *sql.Selector) {
+			for i := range ps {
+				ps[i](selector)
+			}
+		}
+	}
+	if value, ok := rau.mutation.UpdatedAt(); ok {
+		_spec.SetField(roleassignment.FieldUpdatedAt, field.TypeTime, value)
+	}
+	if value, ok := rau.mutation.ResourceType(); ok {
+		_spec.SetField(roleassignment.FieldResourceType, field.TypeString, value)
+	}
+	if value, ok := rau.mutation.ResourceID(); ok {
+		_spec.SetField(roleassignment.FieldResourceID, field.TypeString, value)
+	}
+	if rau.mutation.UserCleared() {
+		edge := &sqlgraph.EdgeSpec{
+			Rel:     sqlgraph.M2O,
+			Inverse: true,
+			Table:   roleassignment.UserTable,
+			Columns: []string{roleassignment.UserColumn},
+			Bidi:    false,
+			Target: &sqlgraph.EdgeTarget{
+				IDSpec: sqlgraph.NewFieldSpec(user.FieldID, field.TypeInt),
+			},
+		}
+		_spec.Edges.Clear = append(_spec.Edges.Clear, edge)
+	}
+	if nodes := rau.mutation.UserIDs(); len(nodes) > 0 {
+		edge := &sqlgraph.EdgeSpec{
+			Rel:     sqlgraph.M2O,
+			Inverse: true,
+			Table:   roleassignment.UserTable,
+			Columns: []string{roleassignment.UserColumn},
+			Bidi:    false,
+			Target: &sqlgraph.EdgeTarget{
+				IDSpec: sqlgraph.NewFieldSpec(user.FieldID, field.TypeInt),
+			},
+		}
+		for _, k := range nodes {
+			edge.Target.Nodes = append(edge.Target.Nodes, k)
+		}
+		_spec.Edges.Add = append(_spec.Edges.Add, edge)
+	}
+	if rau.mutation.RoleCleared() {
+		edge := &sqlgraph.EdgeSpec{
+			Rel:     sqlgraph.M2O,
+			Inverse: true,
+			Table:   roleassignment.RoleTable,
+			Columns: []string{roleassignment.RoleColumn},
+			Bidi:    false,
+			Target: &sqlgraph.EdgeTarget{
+				IDSpec: sqlgraph.NewFieldSpec(role.FieldID, field.TypeInt),
+			},
+		}
+		_spec.Edges.Clear = append(_spec.Edges.Clear, edge)
+	}
+	if nodes := rau.mutation.RoleIDs(); len(nodes) > 0 {
+		edge := &sqlgraph.EdgeSpec{
+			Rel:     sqlgraph.M2O,
+			Inverse: true,
+			Table:   roleassignment.RoleTable,
+			Columns: []string{roleassignment.RoleColumn},
+			Bidi:    false,
+			Target: &sqlgraph.EdgeTarget{
+				IDSpec: sqlgraph.NewFieldSpec(role.FieldID, field.TypeInt),
+			},
+		}
+		for _, k := range nodes {
+			edge.Target.Nodes = append(edge.Target.Nodes, k)
+		}
+		_spec.Edges.Add = append(_spec.Edges.Add, edge)
+	}
+	if n, err = sqlgraph.UpdateNodes(ctx, rau.driver, _spec); err != nil {
+		if _, ok := err.(*sqlgraph.NotFoundError); ok {
+			err = &NotFoundError{roleassignment.Label}
+		} else if sqlgraph.IsConstraintError(err) {
+			err = &ConstraintError{msg: err.Error(), wrap: err}
+		}
+		return 0, err
+	}
+	rau.mutation.done = true
+	return n, nil
+}
+
+// RoleAssignmentUpdateOne is the builder for updating a single RoleAssignment entity.
+type RoleAssignmentUpdateOne struct {
+	config
+	fields   []string
+	hooks    []Hook
+	mutation *RoleAssignmentMutation
+}
+
+// SetUpdatedAt sets the "updated_at" field.
+func (rauo *RoleAssignmentUpdateOne) SetUpdatedAt(t time.Time) *RoleAssignmentUpdateOne {
+	rauo.mutation.SetUpdatedAt(t)
+	return rauo
+}
+
+// SetNillableUpdatedAt sets the "updated_at" field if the given value is not nil.
+func (rauo *RoleAssignmentUpdateOne) SetNillableUpdatedAt(t *time.Time) *RoleAssignmentUpdateOne {
+	if t != nil {
+		rauo.SetUpdatedAt(*t)
+	}
+	return rauo
+}
+
+// SetResourceType sets the "resource_type" field.
+func (rauo *RoleAssignmentUpdateOne) SetResourceType(s string) *RoleAssignmentUpdateOne {
+	rauo.mutation.SetResourceType(s)
+	return rauo
+}
+
+// SetNillableResourceType sets the "resource_type" field if the given value is not nil.
+func (rauo *RoleAssignmentUpdateOne) SetNillableResourceType(s *string) *RoleAssignmentUpdateOne {
+	if s != nil {
+		rauo.SetResourceType(*s)
+	}
+	return rauo
+}
+
+// SetResourceID sets the "resource_id" field.
+func (rauo *RoleAssignmentUpdateOne) SetResourceID(s string) *RoleAssignmentUpdateOne {
+	rauo.mutation.SetResourceID(s)
+	return rauo
+}
+
+// SetNillableResourceID sets the "resource_id" field if the given value is not nil.
+func (rauo *RoleAssignmentUpdateOne) SetNillableResourceID(s *string) *RoleAssignmentUpdateOne {
+	if s != nil {
+		rauo.SetResourceID(*s)
+	}
+	return rauo
+}
+
+// SetUserID sets the "user" edge to the User entity by ID.
+func (rauo *RoleAssignmentUpdateOne) SetUserID(id int) *RoleAssignmentUpdateOne {
+	rauo.mutation.SetUserID(id)
+	return rauo
+}
+
+// SetUser sets the "user" edge to the User entity.
+func (rauo *RoleAssignmentUpdateOne) SetUser(u *User) *RoleAssignmentUpdateOne {
+	return rauo.SetUserID(u.ID)
+}
+
+// SetRoleID sets the "role" edge to the Role entity by ID.
+func (rauo *RoleAssignmentUpdateOne) SetRoleID(id int) *RoleAssignmentUpdateOne {
+	rauo.mutation.SetRoleID(id)
+	return rauo
+}
+
+// SetRole sets the "role" edge to the Role entity.
+func (rauo *RoleAssignmentUpdateOne) SetRole(r *Role) *RoleAssignmentUpdateOne {
+	return rauo.SetRoleID(r.ID)
+}
+
+// Mutation returns the RoleAssignmentMutation object of the builder.
+func (rauo *RoleAssignmentUpdateOne) Mutation() *RoleAssignmentMutation {
+	return rauo.mutation
+}
+
+// ClearUser clears the "user" edge to the User entity.
+func (rauo *RoleAssignmentUpdateOne) ClearUser() *RoleAssignmentUpdateOne {
+	rauo.mutation.ClearUser()
+	return rauo
+}
+
+// ClearRole clears the "role" edge to the Role entity.
+func (rauo *RoleAssignmentUpdateOne) ClearRole() *RoleAssignmentUpdateOne {
+	rauo.mutation.ClearRole()
+	return rauo
+}
+
+// Where appends a list predicates to the RoleAssignmentUpdate builder.
+func (rauo *RoleAssignmentUpdateOne) Where(ps ...predicate.RoleAssignment) *RoleAssignmentUpdateOne {
+	rauo.mutation.Where(ps...)
+	return rauo
+}
+
+// Select allows selecting one or more fields (columns) of the returned entity.
+// The default is selecting all fields defined in the entity schema.
+func (rauo *RoleAssignmentUpdateOne) Select(field string, fields ...string) *RoleAssignmentUpdateOne {
+	rauo.fields = append([]string{field}, fields...)
+	return rauo
+}
+
+// Save executes the query and returns the updated RoleAssignment entity.
+func (rauo *RoleAssignmentUpdateOne) Save(ctx context.Context) (*RoleAssignment, error) {
+	return withHooks(ctx, rauo.sqlSave, rauo.mutation, rauo.hooks)
+}
+
+// SaveX is like Save, but panics if an error occurs.
+func (rauo *RoleAssignmentUpdateOne) SaveX(ctx context.Context) *RoleAssignment {
+	node, err := rauo.Save(ctx)
+	if err != nil {
+		panic(err)
+	}
+	return node
+}
+
+// Exec executes the query on the entity.
+func (rauo *RoleAssignmentUpdateOne) Exec(ctx context.Context) error {
+	_, err := rauo.Save(ctx)
+	return err
+}
+
+// ExecX is like Exec, but panics if an error occurs.
+func (rauo *RoleAssignmentUpdateOne) ExecX(ctx context.Context) {
+	if err := rauo.Exec(ctx); err != nil {
+		panic(err)
+	}
+}
+
+// check runs all checks and user-defined validators on the builder.
+func (rauo *RoleAssignmentUpdateOne) check() error {
+	if v, ok := rauo.mutation.ResourceType(); ok {
+		if err := roleassignment.ResourceTypeValidator(v); err != nil {
+			return &ValidationError{Name: "resource_type", err: fmt.Errorf(`ent: validator failed for field "RoleAssignment.resource_type": %w`, err)}
+		}
+	}
+	if v, ok := rauo.mutation.ResourceID(); ok {
+		if err := roleassignment.ResourceIDValidator(v); err != nil {
+			return &ValidationError{Name: "resource_id", err: fmt.Errorf(`ent: validator failed for field "RoleAssignment.resource_id": %w`, err)}
+		}
+	}
+	if rauo.mutation.UserCleared() && len(rauo.mutation.UserIDs()) > 0 {
+		return errors.New(`ent: clearing a required unique edge "RoleAssignment.user"`)
+	}
+	if rauo.mutation.RoleCleared() && len(rauo.mutation.RoleIDs()) > 0 {
+		return errors.New(`ent: clearing a required unique edge "RoleAssignment.role"`)
+	}
+	return nil
+}
+
+func (rauo *RoleAssignmentUpdateOne) sqlSave(ctx context.Context) (_node *RoleAssignment, err error) {
+	if err := rauo.check(); err != nil {
+		return _node, err
+	}
+	_spec := sqlgraph.NewUpdateSpec(roleassignment.Table, roleassignment.Columns, sqlgraph.NewFieldSpec(roleassignment.FieldID, field.TypeInt))
+	id, ok := rauo.mutation.ID()
+	if !ok {
+		return nil, &ValidationError{Name: "id", err: errors.New(`ent: missing "RoleAssignment.id" for update`)}
+	}
+	_spec.Node.ID.Value = id
+	if fields := rauo.fields; len(fields) > 0 {
+		_spec.Node.Columns = make([]string, 0, len(fields))
+		_spec.Node.Columns = append(_spec.Node.Columns, roleassignment.FieldID)
+		for _, f := range fields {
+			if !roleassignment.ValidColumn(f) {
+				return nil, &ValidationError{Name: f, err: fmt.Errorf("ent: invalid field %q for query", f)}
+			}
+			if f != roleassignment.FieldID {
+				_spec.Node.Columns = append(_spec.Node.Columns, f)
+			}
+		}
+	}
+	if ps := rauo.mutation.predicates; len(ps) > 0 {
+		_spec.Predicate = func(selector *sql.Selector) {
+			for i := range ps {
+				ps[i](selector)
+			}
+		}
+	}
+	if value, ok := rauo.mutation.UpdatedAt(); ok {
+		_spec.SetField(roleassignment.FieldUpdatedAt, field.TypeTime, value)
+	}
+	if value, ok := rauo.mutation.ResourceType(); ok {
+		_spec.SetField(roleassignment.FieldResourceType, field.TypeString, value)
+	}
+	if value, ok := rauo.mutation.ResourceID(); ok {
+		_spec.SetField(roleassignment.FieldResourceID, field.TypeString, value)
+	}
+	if rauo.mutation.UserCleared() {
+		edge := &sqlgraph.EdgeSpec{
+			Rel:     sqlgraph.M2O,
+			Inverse: true,
+			Table:   roleassignment.UserTable,
+			Columns: []string{roleassignment.UserColumn},
+			Bidi:    false,
+			Target: &sqlgraph.EdgeTarget{
+				IDSpec: sqlgraph.NewFieldSpec(user.FieldID, field.TypeInt),
+			},
+		}
+		_spec.Edges.Clear = append(_spec.Edges.Clear, edge)
+	}
+	if nodes := rauo.mutation.UserIDs(); len(nodes) > 0 {
+		edge := &sqlgraph.EdgeSpec{
+			Rel:     sqlgraph.M2O,
+			Inverse: true,
+			Table:   roleassignment.UserTable,
+			Columns: []string{roleassignment.UserColumn},
+			Bidi:    false,
+			Target: &sqlgraph.EdgeTarget{
+				IDSpec: sqlgraph.NewFieldSpec(user.FieldID, field.TypeInt),
+			},
+		}
+		for _, k := range nodes {
+			edge.Target.Nodes = append(edge.Target.Nodes, k)
+		}
+		_spec.Edges.Add = append(_spec.Edges.Add, edge)
+	}
+	if rauo.mutation.RoleCleared() {
+		edge := &sqlgraph.EdgeSpec{
+			Rel:     sqlgraph.M2O,
+			Inverse: true,
+			Table:   roleassignment.RoleTable,
+			Columns: []string{roleassignment.RoleColumn},
+			Bidi:    false,
+			Target: &sqlgraph.EdgeTarget{
+				IDSpec: sqlgraph.NewFieldSpec(role.FieldID, field.TypeInt),
+			},
+		}
+		_spec.Edges.Clear = append(_spec.Edges.Clear, edge)
+	}
+	if nodes := rauo.mutation.RoleIDs(); len(nodes) > 0 {
+		edge := &sqlgraph.EdgeSpec{
+			Rel:     sqlgraph.M2O,
+			Inverse: true,
+			Table:   roleassignment.RoleTable,
+			Columns: []string{roleassignment.RoleColumn},
+			Bidi:    false,
+			Target: &sqlgraph.EdgeTarget{
+				IDSpec: sqlgraph.NewFieldSpec(role.FieldID, field.TypeInt),
+			},
+		}
+		for _, k := range nodes {
+			edge.Target.Nodes = append(edge.Target.Nodes, k)
+		}
+		_spec.Edges.Add = append(_spec.Edges.Add, edge)
+	}
+	_node = &RoleAssignment{config: rauo.config}
+	_spec.Assign = _node.assignValues
+	_spec.ScanValues = _node.scanValues
+	if err = sqlgraph.UpdateNode(ctx, rauo.driver, _spec); err != nil {
+		if _, ok := err.(*sqlgraph.NotFoundError); ok {
+			err = &NotFoundError{roleassignment.Label}
+		} else if sqlgraph.IsConstraintError(err) {
+			err = &ConstraintError{msg: err.Error(), wrap: err}
+		}
+		return nil, err
+	}
+	rauo.mutation.done = true
+	return _node, nil
+}
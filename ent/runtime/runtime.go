@@ -2,7 +2,209 @@
 
 package runtime
 
-// The schema-stitching logic is generated in github.com/smxlong/users/ent/runtime.go
+import (
+	"github.com/smxlong/users/ent/credential"
+	"github.com/smxlong/users/ent/identity"
+	"github.com/smxlong/users/ent/lockout"
+	"github.com/smxlong/users/ent/mfa"
+	"github.com/smxlong/users/ent/onetimetoken"
+	"github.com/smxlong/users/ent/permission"
+	"github.com/smxlong/users/ent/recoverycode"
+	"github.com/smxlong/users/ent/relationtuple"
+	"github.com/smxlong/users/ent/role"
+	"github.com/smxlong/users/ent/roleassignment"
+	"github.com/smxlong/users/ent/schema"
+	"github.com/smxlong/users/ent/user"
+	"github.com/smxlong/users/ent/userattribute"
+	"github.com/smxlong/users/ent/webauthnchallenge"
+)
+
+// The init function reads all schema descriptors with runtime code
+// (default values, validators, hooks and policies) and stitches it
+// to their package variables.
+func init() {
+	credentialFields := schema.Credential{}.Fields()
+	_ = credentialFields
+	// credentialDescCredentialID is the schema descriptor for credential_id field.
+	credentialDescCredentialID := credentialFields[0].Descriptor()
+	// credential.CredentialIDValidator is a validator for the "credential_id" field. It is called by the builders before save.
+	credential.CredentialIDValidator = credentialDescCredentialID.Validators[0].(func([]byte) error)
+	// credentialDescPublicKey is the schema descriptor for public_key field.
+	credentialDescPublicKey := credentialFields[1].Descriptor()
+	// credential.PublicKeyValidator is a validator for the "public_key" field. It is called by the builders before save.
+	credential.PublicKeyValidator = credentialDescPublicKey.Validators[0].(func([]byte) error)
+	// credentialDescSignCount is the schema descriptor for sign_count field.
+	credentialDescSignCount := credentialFields[2].Descriptor()
+	// credential.DefaultSignCount holds the default value on creation for the sign_count field.
+	credential.DefaultSignCount = credentialDescSignCount.Default.(uint32)
+	identityFields := schema.Identity{}.Fields()
+	_ = identityFields
+	// identityDescProvider is the schema descriptor for provider field.
+	identityDescProvider := identityFields[0].Descriptor()
+	// identity.ProviderValidator is a validator for the "provider" field. It is called by the builders before save.
+	identity.ProviderValidator = identityDescProvider.Validators[0].(func(string) error)
+	// identityDescSubject is the schema descriptor for subject field.
+	identityDescSubject := identityFields[1].Descriptor()
+	// identity.SubjectValidator is a validator for the "subject" field. It is called by the builders before save.
+	identity.SubjectValidator = identityDescSubject.Validators[0].(func(string) error)
+	lockoutFields := schema.Lockout{}.Fields()
+	_ = lockoutFields
+	// lockoutDescKey is the schema descriptor for key field.
+	lockoutDescKey := lockoutFields[0].Descriptor()
+	// lockout.KeyValidator is a validator for the "key" field. It is called by the builders before save.
+	lockout.KeyValidator = lockoutDescKey.Validators[0].(func(string) error)
+	// lockoutDescFailures is the schema descriptor for failures field.
+	lockoutDescFailures := lockoutFields[1].Descriptor()
+	// lockout.DefaultFailures holds the default value on creation for the failures field.
+	lockout.DefaultFailures = lockoutDescFailures.Default.(int)
+	mfaFields := schema.MFA{}.Fields()
+	_ = mfaFields
+	// mfaDescSecret is the schema descriptor for secret field.
+	mfaDescSecret := mfaFields[0].Descriptor()
+	// mfa.SecretValidator is a validator for the "secret" field. It is called by the builders before save.
+	mfa.SecretValidator = mfaDescSecret.Validators[0].(func([]byte) error)
+	// mfaDescEnabled is the schema descriptor for enabled field.
+	mfaDescEnabled := mfaFields[1].Descriptor()
+	// mfa.DefaultEnabled holds the default value on creation for the enabled field.
+	mfa.DefaultEnabled = mfaDescEnabled.Default.(bool)
+	// mfaDescLastStep is the schema descriptor for last_step field.
+	mfaDescLastStep := mfaFields[2].Descriptor()
+	// mfa.DefaultLastStep holds the default value on creation for the last_step field.
+	mfa.DefaultLastStep = mfaDescLastStep.Default.(int64)
+	onetimetokenFields := schema.OneTimeToken{}.Fields()
+	_ = onetimetokenFields
+	// onetimetokenDescTokenHash is the schema descriptor for token_hash field.
+	onetimetokenDescTokenHash := onetimetokenFields[1].Descriptor()
+	// onetimetoken.TokenHashValidator is a validator for the "token_hash" field. It is called by the builders before save.
+	onetimetoken.TokenHashValidator = onetimetokenDescTokenHash.Validators[0].(func(string) error)
+	// onetimetokenDescAttempts is the schema descriptor for attempts field.
+	onetimetokenDescAttempts := onetimetokenFields[4].Descriptor()
+	// onetimetoken.DefaultAttempts holds the default value on creation for the attempts field.
+	onetimetoken.DefaultAttempts = onetimetokenDescAttempts.Default.(int)
+	// onetimetoken.AttemptsValidator is a validator for the "attempts" field. It is called by the builders before save.
+	onetimetoken.AttemptsValidator = onetimetokenDescAttempts.Validators[0].(func(int) error)
+	permissionMixin := schema.Permission{}.Mixin()
+	permissionMixinHooks0 := permissionMixin[0].Hooks()
+	permission.Hooks[0] = permissionMixinHooks0[0]
+	permissionFields := schema.Permission{}.Fields()
+	_ = permissionFields
+	// permissionDescName is the schema descriptor for name field.
+	permissionDescName := permissionFields[0].Descriptor()
+	// permission.NameValidator is a validator for the "name" field. It is called by the builders before save.
+	permission.NameValidator = permissionDescName.Validators[0].(func(string) error)
+	recoverycodeFields := schema.RecoveryCode{}.Fields()
+	_ = recoverycodeFields
+	// recoverycodeDescCodeHash is the schema descriptor for code_hash field.
+	recoverycodeDescCodeHash := recoverycodeFields[0].Descriptor()
+	// recoverycode.CodeHashValidator is a validator for the "code_hash" field. It is called by the builders before save.
+	recoverycode.CodeHashValidator = recoverycodeDescCodeHash.Validators[0].(func(string) error)
+	relationtupleMixin := schema.RelationTuple{}.Mixin()
+	relationtupleMixinHooks0 := relationtupleMixin[0].Hooks()
+	relationtuple.Hooks[0] = relationtupleMixinHooks0[0]
+	relationtupleFields := schema.RelationTuple{}.Fields()
+	_ = relationtupleFields
+	// relationtupleDescObjectType is the schema descriptor for object_type field.
+	relationtupleDescObjectType := relationtupleFields[0].Descriptor()
+	// relationtuple.ObjectTypeValidator is a validator for the "object_type" field. It is called by the builders before save.
+	relationtuple.ObjectTypeValidator = relationtupleDescObjectType.Validators[0].(func(string) error)
+	// relationtupleDescObjectID is the schema descriptor for object_id field.
+	relationtupleDescObjectID := relationtupleFields[1].Descriptor()
+	// relationtuple.ObjectIDValidator is a validator for the "object_id" field. It is called by the builders before save.
+	relationtuple.ObjectIDValidator = relationtupleDescObjectID.Validators[0].(func(string) error)
+	// relationtupleDescRelation is the schema descriptor for relation field.
+	relationtupleDescRelation := relationtupleFields[2].Descriptor()
+	// relationtuple.RelationValidator is a validator for the "relation" field. It is called by the builders before save.
+	relationtuple.RelationValidator = relationtupleDescRelation.Validators[0].(func(string) error)
+	// relationtupleDescSubjectType is the schema descriptor for subject_type field.
+	relationtupleDescSubjectType := relationtupleFields[3].Descriptor()
+	// relationtuple.SubjectTypeValidator is a validator for the "subject_type" field. It is called by the builders before save.
+	relationtuple.SubjectTypeValidator = relationtupleDescSubjectType.Validators[0].(func(string) error)
+	// relationtupleDescSubjectID is the schema descriptor for subject_id field.
+	relationtupleDescSubjectID := relationtupleFields[4].Descriptor()
+	// relationtuple.SubjectIDValidator is a validator for the "subject_id" field. It is called by the builders before save.
+	relationtuple.SubjectIDValidator = relationtupleDescSubjectID.Validators[0].(func(string) error)
+	// relationtupleDescSubjectRelation is the schema descriptor for subject_relation field.
+	relationtupleDescSubjectRelation := relationtupleFields[5].Descriptor()
+	// relationtuple.DefaultSubjectRelation holds the default value on creation for the subject_relation field.
+	relationtuple.DefaultSubjectRelation = relationtupleDescSubjectRelation.Default.(string)
+	roleMixin := schema.Role{}.Mixin()
+	roleMixinHooks0 := roleMixin[0].Hooks()
+	role.Hooks[0] = roleMixinHooks0[0]
+	roleFields := schema.Role{}.Fields()
+	_ = roleFields
+	// roleDescName is the schema descriptor for name field.
+	roleDescName := roleFields[0].Descriptor()
+	// role.NameValidator is a validator for the "name" field. It is called by the builders before save.
+	role.NameValidator = roleDescName.Validators[0].(func(string) error)
+	roleassignmentMixin := schema.RoleAssignment{}.Mixin()
+	roleassignmentMixinHooks0 := roleassignmentMixin[0].Hooks()
+	roleassignment.Hooks[0] = roleassignmentMixinHooks0[0]
+	roleassignmentFields := schema.RoleAssignment{}.Fields()
+	_ = roleassignmentFields
+	// roleassignmentDescResourceType is the schema descriptor for resource_type field.
+	roleassignmentDescResourceType := roleassignmentFields[0].Descriptor()
+	// roleassignment.ResourceTypeValidator is a validator for the "resource_type" field. It is called by the builders before save.
+	roleassignment.ResourceTypeValidator = roleassignmentDescResourceType.Validators[0].(func(string) error)
+	// roleassignmentDescResourceID is the schema descriptor for resource_id field.
+	roleassignmentDescResourceID := roleassignmentFields[1].Descriptor()
+	// roleassignment.ResourceIDValidator is a validator for the "resource_id" field. It is called by the builders before save.
+	roleassignment.ResourceIDValidator = roleassignmentDescResourceID.Validators[0].(func(string) error)
+	userMixin := schema.User{}.Mixin()
+	userMixinHooks0 := userMixin[0].Hooks()
+	userMixinHooks1 := userMixin[1].Hooks()
+	user.Hooks[0] = userMixinHooks0[0]
+	user.Hooks[1] = userMixinHooks1[0]
+	userMixinInters1 := userMixin[1].Interceptors()
+	user.Interceptors[0] = userMixinInters1[0]
+	userFields := schema.User{}.Fields()
+	_ = userFields
+	// userDescName is the schema descriptor for name field.
+	userDescName := userFields[0].Descriptor()
+	// user.NameValidator is a validator for the "name" field. It is called by the builders before save.
+	user.NameValidator = userDescName.Validators[0].(func(string) error)
+	// userDescEmail is the schema descriptor for email field.
+	userDescEmail := userFields[1].Descriptor()
+	// user.EmailValidator is a validator for the "email" field. It is called by the builders before save.
+	user.EmailValidator = func() func(string) error {
+		validators := userDescEmail.Validators
+		fns := [...]func(string) error{
+			validators[0].(func(string) error),
+			validators[1].(func(string) error),
+		}
+		return func(email string) error {
+			for _, fn := range fns {
+				if err := fn(email); err != nil {
+					return err
+				}
+			}
+			return nil
+		}
+	}()
+	// userDescPasswordHash is the schema descriptor for password_hash field.
+	userDescPasswordHash := userFields[2].Descriptor()
+	// user.PasswordHashValidator is a validator for the "password_hash" field. It is called by the builders before save.
+	user.PasswordHashValidator = userDescPasswordHash.Validators[0].(func(string) error)
+	// userDescTokenGeneration is the schema descriptor for token_generation field.
+	userDescTokenGeneration := userFields[4].Descriptor()
+	// user.DefaultTokenGeneration holds the default value on creation for the token_generation field.
+	user.DefaultTokenGeneration = userDescTokenGeneration.Default.(int)
+	// userDescFailedLoginCount is the schema descriptor for failed_login_count field.
+	userDescFailedLoginCount := userFields[9].Descriptor()
+	// user.DefaultFailedLoginCount holds the default value on creation for the failed_login_count field.
+	user.DefaultFailedLoginCount = userDescFailedLoginCount.Default.(int)
+	userattributeFields := schema.UserAttribute{}.Fields()
+	_ = userattributeFields
+	// userattributeDescName is the schema descriptor for name field.
+	userattributeDescName := userattributeFields[0].Descriptor()
+	// userattribute.NameValidator is a validator for the "name" field. It is called by the builders before save.
+	userattribute.NameValidator = userattributeDescName.Validators[0].(func(string) error)
+	webauthnchallengeFields := schema.WebAuthnChallenge{}.Fields()
+	_ = webauthnchallengeFields
+	// webauthnchallengeDescChallengeHash is the schema descriptor for challenge_hash field.
+	webauthnchallengeDescChallengeHash := webauthnchallengeFields[0].Descriptor()
+	// webauthnchallenge.ChallengeHashValidator is a validator for the "challenge_hash" field. It is called by the builders before save.
+	webauthnchallenge.ChallengeHashValidator = webauthnchallengeDescChallengeHash.Validators[0].(func(string) error)
+}
 
 const (
 	Version = "v0.14.1"                                         // Version of ent codegen.
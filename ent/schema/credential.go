@@ -0,0 +1,44 @@
+package schema
+
+import (
+	"entgo.io/ent"
+	"entgo.io/ent/schema/edge"
+	"entgo.io/ent/schema/field"
+)
+
+// Credential holds the schema definition for the Credential entity. A
+// credential is a WebAuthn public key credential, such as a passkey.
+type Credential struct {
+	ent.Schema
+}
+
+// Fields of the Credential.
+func (Credential) Fields() []ent.Field {
+	return []ent.Field{
+		field.Bytes("credential_id").
+			NotEmpty().
+			Unique(),
+		// The COSE-encoded public key.
+		field.Bytes("public_key").
+			NotEmpty(),
+		// The last signature counter reported by the authenticator.
+		field.Uint32("sign_count").
+			Default(0),
+		field.Strings("transports").
+			Optional(),
+		// The authenticator model.
+		field.Bytes("aaguid").
+			Optional(),
+	}
+}
+
+// Edges of the Credential.
+func (Credential) Edges() []ent.Edge {
+	return []ent.Edge{
+		// The credential belongs to exactly one user.
+		edge.From("user", User.Type).
+			Ref("credentials").
+			Unique().
+			Required(),
+	}
+}
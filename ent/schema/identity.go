@@ -0,0 +1,48 @@
+package schema
+
+import (
+	"entgo.io/ent"
+	"entgo.io/ent/schema/edge"
+	"entgo.io/ent/schema/field"
+	"entgo.io/ent/schema/index"
+)
+
+// Identity holds the schema definition for the Identity entity. An identity
+// links a user to an account at an external identity provider.
+type Identity struct {
+	ent.Schema
+}
+
+// Fields of the Identity.
+func (Identity) Fields() []ent.Field {
+	return []ent.Field{
+		// The provider is the issuer of the external identity.
+		field.String("provider").
+			NotEmpty(),
+		// The subject is the provider's identifier for the account.
+		field.String("subject").
+			NotEmpty(),
+		field.String("email").
+			Optional(),
+	}
+}
+
+// Edges of the Identity.
+func (Identity) Edges() []ent.Edge {
+	return []ent.Edge{
+		// The identity belongs to exactly one user.
+		edge.From("user", User.Type).
+			Ref("identities").
+			Unique().
+			Required(),
+	}
+}
+
+// Indexes of the Identity.
+func (Identity) Indexes() []ent.Index {
+	return []ent.Index{
+		// An external account can only be linked once.
+		index.Fields("provider", "subject").
+			Unique(),
+	}
+}
@@ -0,0 +1,28 @@
+package schema
+
+import (
+	"entgo.io/ent"
+	"entgo.io/ent/schema/field"
+)
+
+// Lockout holds the schema definition for the Lockout entity. It tracks
+// failed logins for a key, such as a user or a client IP address.
+type Lockout struct {
+	ent.Schema
+}
+
+// Fields of the Lockout.
+func (Lockout) Fields() []ent.Field {
+	return []ent.Field{
+		field.String("key").
+			NotEmpty().
+			Unique(),
+		field.Int("failures").
+			Default(0),
+		field.Time("last_failure_at"),
+		// When the lockout expires, if the key is locked out.
+		field.Time("locked_until").
+			Optional().
+			Nillable(),
+	}
+}
@@ -0,0 +1,49 @@
+package schema
+
+import (
+	"entgo.io/ent"
+	"entgo.io/ent/dialect/entsql"
+	"entgo.io/ent/schema"
+	"entgo.io/ent/schema/edge"
+	"entgo.io/ent/schema/field"
+)
+
+// MFA holds the schema definition for the MFA entity. It stores a user's TOTP
+// multi-factor authentication enrollment.
+type MFA struct {
+	ent.Schema
+}
+
+// Fields of the MFA.
+func (MFA) Fields() []ent.Field {
+	return []ent.Field{
+		// The TOTP secret, encrypted.
+		field.Bytes("secret").
+			NotEmpty().
+			Sensitive(),
+		// MFA is enabled once the enrollment is confirmed with a valid code.
+		field.Bool("enabled").
+			Default(false),
+		// The last accepted TOTP time step, to prevent replay of codes.
+		field.Int64("last_step").
+			Default(0),
+	}
+}
+
+// Edges of the MFA.
+func (MFA) Edges() []ent.Edge {
+	return []ent.Edge{
+		// The MFA enrollment belongs to exactly one user.
+		edge.From("user", User.Type).
+			Ref("mfa").
+			Unique().
+			Required(),
+	}
+}
+
+// Annotations of the MFA.
+func (MFA) Annotations() []schema.Annotation {
+	return []schema.Annotation{
+		entsql.Annotation{Table: "mfa"},
+	}
+}
@@ -0,0 +1,53 @@
+package schema
+
+import (
+	"entgo.io/ent"
+	"entgo.io/ent/schema/edge"
+	"entgo.io/ent/schema/field"
+)
+
+// OneTimeToken holds the schema definition for the OneTimeToken entity. A
+// one-time token is a short-lived, single-use secret sent to a user, such as
+// an email verification link.
+type OneTimeToken struct {
+	ent.Schema
+}
+
+// Fields of the OneTimeToken.
+func (OneTimeToken) Fields() []ent.Field {
+	return []ent.Field{
+		// What the token may be used for.
+		field.Enum("purpose").
+			Values("email_verification", "password_reset", "magic_link", "mfa_challenge"),
+		// The SHA-256 hash of the token. The token itself is never stored.
+		field.String("token_hash").
+			NotEmpty().
+			Unique().
+			Sensitive(),
+		field.Time("expires_at"),
+		// The SHA-256 hash of the device the token is bound to, if any.
+		field.String("device_hash").
+			Optional().
+			Sensitive(),
+		// The number of attempts to use the token, for tokens that allow
+		// more than one, such as MFA challenges.
+		field.Int("attempts").
+			Default(0).
+			NonNegative(),
+		// When the token was used, if it has been.
+		field.Time("used_at").
+			Optional().
+			Nillable(),
+	}
+}
+
+// Edges of the OneTimeToken.
+func (OneTimeToken) Edges() []ent.Edge {
+	return []ent.Edge{
+		// The token belongs to exactly one user.
+		edge.From("user", User.Type).
+			Ref("one_time_tokens").
+			Unique().
+			Required(),
+	}
+}
@@ -11,6 +11,13 @@ type Permission struct {
 	ent.Schema
 }
 
+// Mixin of the Permission.
+func (Permission) Mixin() []ent.Mixin {
+	return []ent.Mixin{
+		TimeMixin{},
+	}
+}
+
 // Fields of the Permission.
 func (Permission) Fields() []ent.Field {
 	return []ent.Field{
@@ -0,0 +1,39 @@
+package schema
+
+import (
+	"entgo.io/ent"
+	"entgo.io/ent/schema/edge"
+	"entgo.io/ent/schema/field"
+)
+
+// RecoveryCode holds the schema definition for the RecoveryCode entity. A
+// recovery code is a single-use code that can be used in place of an MFA
+// code.
+type RecoveryCode struct {
+	ent.Schema
+}
+
+// Fields of the RecoveryCode.
+func (RecoveryCode) Fields() []ent.Field {
+	return []ent.Field{
+		// The code, hashed with PasswordHash.
+		field.String("code_hash").
+			NotEmpty().
+			Sensitive(),
+		// When the code was used, if it has been.
+		field.Time("used_at").
+			Optional().
+			Nillable(),
+	}
+}
+
+// Edges of the RecoveryCode.
+func (RecoveryCode) Edges() []ent.Edge {
+	return []ent.Edge{
+		// The recovery code belongs to exactly one user.
+		edge.From("user", User.Type).
+			Ref("recovery_codes").
+			Unique().
+			Required(),
+	}
+}
@@ -0,0 +1,64 @@
+package schema
+
+import (
+	"entgo.io/ent"
+	"entgo.io/ent/schema/edge"
+	"entgo.io/ent/schema/field"
+	"entgo.io/ent/schema/index"
+)
+
+// RelationTuple holds the schema definition for the RelationTuple entity. A
+// relation tuple relates an object to a subject, such as making user 1 a
+// viewer of document readme, or every member of group eng an editor of it.
+type RelationTuple struct {
+	ent.Schema
+}
+
+// Mixin of the RelationTuple.
+func (RelationTuple) Mixin() []ent.Mixin {
+	return []ent.Mixin{
+		TimeMixin{},
+	}
+}
+
+// Fields of the RelationTuple.
+func (RelationTuple) Fields() []ent.Field {
+	return []ent.Field{
+		field.String("object_type").
+			NotEmpty(),
+		field.String("object_id").
+			NotEmpty(),
+		field.String("relation").
+			NotEmpty(),
+		field.String("subject_type").
+			NotEmpty(),
+		field.String("subject_id").
+			NotEmpty(),
+		// The relation of the subjects related to the subject object, for
+		// tuples relating a userset such as the members of a group. Empty
+		// when the subject is the subject object itself.
+		field.String("subject_relation").
+			Default(""),
+	}
+}
+
+// Edges of the RelationTuple.
+func (RelationTuple) Edges() []ent.Edge {
+	return []ent.Edge{
+		// The user the tuple relates, if its subject is a user.
+		edge.From("user", User.Type).
+			Ref("relation_tuples").
+			Unique(),
+	}
+}
+
+// Indexes of the RelationTuple.
+func (RelationTuple) Indexes() []ent.Index {
+	return []ent.Index{
+		// A tuple is held at most once.
+		index.Fields("object_type", "object_id", "relation", "subject_type", "subject_id", "subject_relation").
+			Unique(),
+		// Tuples are looked up by subject as well as by object.
+		index.Fields("subject_type", "subject_id", "subject_relation"),
+	}
+}
@@ -2,6 +2,7 @@ package schema
 
 import (
 	"entgo.io/ent"
+	"entgo.io/ent/dialect/entsql"
 	"entgo.io/ent/schema/edge"
 	"entgo.io/ent/schema/field"
 )
@@ -11,6 +12,13 @@ type Role struct {
 	ent.Schema
 }
 
+// Mixin of the Role.
+func (Role) Mixin() []ent.Mixin {
+	return []ent.Mixin{
+		TimeMixin{},
+	}
+}
+
 // Fields of the Role.
 func (Role) Fields() []ent.Field {
 	return []ent.Field{
@@ -30,5 +38,13 @@ func (Role) Edges() []ent.Edge {
 		// The role has multiple users.
 		edge.From("users", User.Type).
 			Ref("roles"),
+		// The role inherits the permissions of its parent roles, and is
+		// inherited by its child roles.
+		edge.To("parents", Role.Type).
+			From("children"),
+		// The role is given to users on resources, and the assignments are
+		// removed along with the role.
+		edge.To("assignments", RoleAssignment.Type).
+			Annotations(entsql.OnDelete(entsql.Cascade)),
 	}
 }
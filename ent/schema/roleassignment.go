@@ -0,0 +1,60 @@
+package schema
+
+import (
+	"entgo.io/ent"
+	"entgo.io/ent/schema/edge"
+	"entgo.io/ent/schema/field"
+	"entgo.io/ent/schema/index"
+)
+
+// RoleAssignment holds the schema definition for the RoleAssignment entity.
+// A role assignment gives a user a role on one resource, such as editor of
+// project 42. Roles given on every resource are the user's roles edge.
+type RoleAssignment struct {
+	ent.Schema
+}
+
+// Mixin of the RoleAssignment.
+func (RoleAssignment) Mixin() []ent.Mixin {
+	return []ent.Mixin{
+		TimeMixin{},
+	}
+}
+
+// Fields of the RoleAssignment.
+func (RoleAssignment) Fields() []ent.Field {
+	return []ent.Field{
+		// The type of the resource the role is given on, such as "project".
+		field.String("resource_type").
+			NotEmpty(),
+		// The ID of the resource the role is given on.
+		field.String("resource_id").
+			NotEmpty(),
+	}
+}
+
+// Edges of the RoleAssignment.
+func (RoleAssignment) Edges() []ent.Edge {
+	return []ent.Edge{
+		// The assignment belongs to exactly one user.
+		edge.From("user", User.Type).
+			Ref("role_assignments").
+			Unique().
+			Required(),
+		// The assignment gives exactly one role.
+		edge.From("role", Role.Type).
+			Ref("assignments").
+			Unique().
+			Required(),
+	}
+}
+
+// Indexes of the RoleAssignment.
+func (RoleAssignment) Indexes() []ent.Index {
+	return []ent.Index{
+		// A user has a role on a resource at most once.
+		index.Fields("resource_type", "resource_id").
+			Edges("user", "role").
+			Unique(),
+	}
+}
@@ -0,0 +1,97 @@
+package schema
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"entgo.io/ent"
+	"entgo.io/ent/dialect/sql"
+	"entgo.io/ent/schema/field"
+	"entgo.io/ent/schema/mixin"
+
+	gen "github.com/smxlong/users/ent"
+	"github.com/smxlong/users/ent/hook"
+	"github.com/smxlong/users/ent/intercept"
+)
+
+// SoftDeleteMixin soft-deletes entities by setting deleted_at instead of
+// removing them. Deleted entities are hidden from queries unless the context
+// comes from IncludeDeleted, and are only removed if the context comes from
+// HardDelete.
+type SoftDeleteMixin struct {
+	mixin.Schema
+}
+
+// Fields of the SoftDeleteMixin.
+func (SoftDeleteMixin) Fields() []ent.Field {
+	return []ent.Field{
+		// When the entity was deleted, if it has been.
+		field.Time("deleted_at").
+			Optional().
+			Nillable(),
+	}
+}
+
+type includeDeletedKey struct{}
+
+// IncludeDeleted returns a context in which queries include soft-deleted
+// entities.
+func IncludeDeleted(parent context.Context) context.Context {
+	return context.WithValue(parent, includeDeletedKey{}, true)
+}
+
+type hardDeleteKey struct{}
+
+// HardDelete returns a context in which deletes remove entities, including
+// soft-deleted ones, instead of soft-deleting them.
+func HardDelete(parent context.Context) context.Context {
+	return context.WithValue(IncludeDeleted(parent), hardDeleteKey{}, true)
+}
+
+// Interceptors of the SoftDeleteMixin.
+func (d SoftDeleteMixin) Interceptors() []ent.Interceptor {
+	return []ent.Interceptor{
+		intercept.TraverseFunc(func(ctx context.Context, q intercept.Query) error {
+			if include, _ := ctx.Value(includeDeletedKey{}).(bool); include {
+				return nil
+			}
+			d.where(q)
+			return nil
+		}),
+	}
+}
+
+// Hooks of the SoftDeleteMixin.
+func (d SoftDeleteMixin) Hooks() []ent.Hook {
+	return []ent.Hook{
+		hook.On(
+			func(next ent.Mutator) ent.Mutator {
+				return ent.MutateFunc(func(ctx context.Context, m ent.Mutation) (ent.Value, error) {
+					if hard, _ := ctx.Value(hardDeleteKey{}).(bool); hard {
+						return next.Mutate(ctx, m)
+					}
+					mx, ok := m.(interface {
+						SetOp(ent.Op)
+						Client() *gen.Client
+						SetDeletedAt(time.Time)
+						WhereP(...func(*sql.Selector))
+					})
+					if !ok {
+						return nil, fmt.Errorf("unexpected mutation type %T", m)
+					}
+					d.where(mx)
+					mx.SetOp(ent.OpUpdate)
+					mx.SetDeletedAt(Now(ctx))
+					return mx.Client().Mutate(ctx, m)
+				})
+			},
+			ent.OpDeleteOne|ent.OpDelete,
+		),
+	}
+}
+
+// where restricts a query or mutation to entities that aren't deleted.
+func (d SoftDeleteMixin) where(w interface{ WhereP(...func(*sql.Selector)) }) {
+	w.WhereP(sql.FieldIsNull(d.Fields()[0].Descriptor().Name))
+}
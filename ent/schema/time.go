@@ -0,0 +1,72 @@
+package schema
+
+import (
+	"context"
+	"time"
+
+	"entgo.io/ent"
+	"entgo.io/ent/schema/field"
+	"entgo.io/ent/schema/mixin"
+
+	"github.com/smxlong/users/ent/hook"
+)
+
+// TimeMixin records when an entity was created and last updated, at the time
+// according to the context, as Now returns.
+type TimeMixin struct {
+	mixin.Schema
+}
+
+// Fields of the TimeMixin.
+func (TimeMixin) Fields() []ent.Field {
+	return []ent.Field{
+		field.Time("created_at").
+			Immutable(),
+		field.Time("updated_at"),
+	}
+}
+
+// Hooks of the TimeMixin. They set the times that aren't set explicitly, as
+// defaults would, but reading the time from the context.
+func (TimeMixin) Hooks() []ent.Hook {
+	return []ent.Hook{
+		hook.On(
+			func(next ent.Mutator) ent.Mutator {
+				return ent.MutateFunc(func(ctx context.Context, m ent.Mutation) (ent.Value, error) {
+					now := Now(ctx)
+					names := []string{"updated_at"}
+					if m.Op().Is(ent.OpCreate) {
+						names = append(names, "created_at")
+					}
+					for _, name := range names {
+						if _, ok := m.Field(name); ok {
+							continue
+						}
+						if err := m.SetField(name, now); err != nil {
+							return nil, err
+						}
+					}
+					return next.Mutate(ctx, m)
+				})
+			},
+			ent.OpCreate|ent.OpUpdate|ent.OpUpdateOne,
+		),
+	}
+}
+
+type nowKey struct{}
+
+// WithNow returns a context in which hooks, such as the TimeMixin's and the
+// SoftDeleteMixin's, read the time from now.
+func WithNow(parent context.Context, now func() time.Time) context.Context {
+	return context.WithValue(parent, nowKey{}, now)
+}
+
+// Now returns the time according to the context, which is the system time
+// unless the context comes from WithNow.
+func Now(ctx context.Context) time.Time {
+	if now, ok := ctx.Value(nowKey{}).(func() time.Time); ok {
+		return now()
+	}
+	return time.Now()
+}
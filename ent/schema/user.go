@@ -4,6 +4,7 @@ import (
 	"net/mail"
 
 	"entgo.io/ent"
+	"entgo.io/ent/dialect/entsql"
 	"entgo.io/ent/schema/edge"
 	"entgo.io/ent/schema/field"
 )
@@ -13,6 +14,14 @@ type User struct {
 	ent.Schema
 }
 
+// Mixin of the User.
+func (User) Mixin() []ent.Mixin {
+	return []ent.Mixin{
+		TimeMixin{},
+		SoftDeleteMixin{},
+	}
+}
+
 // Fields of the User.
 func (User) Fields() []ent.Field {
 	return []ent.Field{
@@ -21,10 +30,40 @@ func (User) Fields() []ent.Field {
 			Unique(),
 		field.String("email").
 			NotEmpty().
-			Validate(emailValidator).
+			Validate(ValidateEmail).
 			Unique(),
 		field.String("password_hash").
 			NotEmpty(),
+		// When the user proved they own their email address, if they have.
+		field.Time("email_verified_at").
+			Optional().
+			Nillable(),
+		// Incremented to revoke all tokens issued to the user.
+		field.Int("token_generation").
+			Default(0),
+		// Whether the user may log in. Pending users have not been activated
+		// yet, for example awaiting approval.
+		field.Enum("status").
+			Values("active", "disabled", "suspended", "pending").
+			Default("active"),
+		// When a suspension ends, if the user is suspended.
+		field.Time("suspended_until").
+			Optional().
+			Nillable(),
+		// When the user last logged in, if they have.
+		field.Time("last_login_at").
+			Optional().
+			Nillable(),
+		// The IP address the user last logged in from, if known.
+		field.String("last_login_ip").
+			Optional(),
+		// Wrong passwords given since the user last logged in.
+		field.Int("failed_login_count").
+			Default(0),
+		// When the password was last set.
+		field.Time("password_changed_at").
+			Optional().
+			Nillable(),
 	}
 }
 
@@ -32,6 +71,31 @@ func (User) Fields() []ent.Field {
 func (User) Edges() []ent.Edge {
 	return []ent.Edge{
 		edge.To("roles", Role.Type),
+		// The user has multiple external identities, removed along with the user.
+		edge.To("identities", Identity.Type).
+			Annotations(entsql.OnDelete(entsql.Cascade)),
+		// The user has at most one MFA enrollment.
+		edge.To("mfa", MFA.Type).
+			Unique().
+			Annotations(entsql.OnDelete(entsql.Cascade)),
+		// The user has multiple MFA recovery codes.
+		edge.To("recovery_codes", RecoveryCode.Type).
+			Annotations(entsql.OnDelete(entsql.Cascade)),
+		// The user has multiple WebAuthn credentials.
+		edge.To("credentials", Credential.Type).
+			Annotations(entsql.OnDelete(entsql.Cascade)),
+		// The user has multiple one-time tokens.
+		edge.To("one_time_tokens", OneTimeToken.Type).
+			Annotations(entsql.OnDelete(entsql.Cascade)),
+		// The user has multiple profile attributes.
+		edge.To("attributes", UserAttribute.Type).
+			Annotations(entsql.OnDelete(entsql.Cascade)),
+		// The user has multiple roles on resources.
+		edge.To("role_assignments", RoleAssignment.Type).
+			Annotations(entsql.OnDelete(entsql.Cascade)),
+		// The user is the subject of multiple relation tuples.
+		edge.To("relation_tuples", RelationTuple.Type).
+			Annotations(entsql.OnDelete(entsql.Cascade)),
 	}
 }
 
@@ -45,7 +109,9 @@ const (
 	ErrEmailAddressInvalid errEmailAddressInvalid = "invalid email address"
 )
 
-func emailValidator(s string) error {
+// ValidateEmail checks that s is an email address, as stored in the email
+// field.
+func ValidateEmail(s string) error {
 	addr, err := mail.ParseAddress(s)
 	if err == nil && addr.Address == "" {
 		err = ErrEmailAddressInvalid
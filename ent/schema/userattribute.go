@@ -0,0 +1,54 @@
+package schema
+
+import (
+	"entgo.io/ent"
+	"entgo.io/ent/schema/edge"
+	"entgo.io/ent/schema/field"
+	"entgo.io/ent/schema/index"
+)
+
+// UserAttribute holds the schema definition for the UserAttribute entity. It
+// is a named profile attribute of a user, such as a phone number.
+type UserAttribute struct {
+	ent.Schema
+}
+
+// Fields of the UserAttribute.
+func (UserAttribute) Fields() []ent.Field {
+	return []ent.Field{
+		field.String("name").
+			NotEmpty(),
+		// The value is encoded as text according to the attribute's type.
+		field.String("value"),
+		// The value again, if the attribute must be unique among users.
+		field.String("unique_value").
+			Optional().
+			Nillable(),
+	}
+}
+
+// Edges of the UserAttribute.
+func (UserAttribute) Edges() []ent.Edge {
+	return []ent.Edge{
+		// The attribute belongs to exactly one user.
+		edge.From("user", User.Type).
+			Ref("attributes").
+			Unique().
+			Required(),
+	}
+}
+
+// Indexes of the UserAttribute.
+func (UserAttribute) Indexes() []ent.Index {
+	return []ent.Index{
+		// A user has at most one value for each attribute.
+		index.Fields("name").
+			Edges("user").
+			Unique(),
+		// Unique values are indexed here. Values of attributes that needn't
+		// be unique are null, which doesn't conflict.
+		index.Fields("name", "unique_value").
+			Unique(),
+		index.Fields("name", "value"),
+	}
+}
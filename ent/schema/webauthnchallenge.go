@@ -0,0 +1,25 @@
+package schema
+
+import (
+	"entgo.io/ent"
+	"entgo.io/ent/schema/field"
+)
+
+// WebAuthnChallenge holds the schema definition for the WebAuthnChallenge
+// entity. It records the challenge of a WebAuthn ceremony that has begun and
+// not yet finished, so that each challenge is accepted only once.
+type WebAuthnChallenge struct {
+	ent.Schema
+}
+
+// Fields of the WebAuthnChallenge.
+func (WebAuthnChallenge) Fields() []ent.Field {
+	return []ent.Field{
+		// The SHA-256 hash of the challenge.
+		field.String("challenge_hash").
+			NotEmpty().
+			Unique().
+			Sensitive(),
+		field.Time("expires_at"),
+	}
+}
@@ -12,12 +12,32 @@ import (
 // Tx is a transactional client that is created by calling Client.Tx().
 type Tx struct {
 	config
+	// Credential is the client for interacting with the Credential builders.
+	Credential *CredentialClient
+	// Identity is the client for interacting with the Identity builders.
+	Identity *IdentityClient
+	// Lockout is the client for interacting with the Lockout builders.
+	Lockout *LockoutClient
+	// MFA is the client for interacting with the MFA builders.
+	MFA *MFAClient
+	// OneTimeToken is the client for interacting with the OneTimeToken builders.
+	OneTimeToken *OneTimeTokenClient
 	// Permission is the client for interacting with the Permission builders.
 	Permission *PermissionClient
+	// RecoveryCode is the client for interacting with the RecoveryCode builders.
+	RecoveryCode *RecoveryCodeClient
+	// RelationTuple is the client for interacting with the RelationTuple builders.
+	RelationTuple *RelationTupleClient
 	// Role is the client for interacting with the Role builders.
 	Role *RoleClient
+	// RoleAssignment is the client for interacting with the RoleAssignment builders.
+	RoleAssignment *RoleAssignmentClient
 	// User is the client for interacting with the User builders.
 	User *UserClient
+	// UserAttribute is the client for interacting with the UserAttribute builders.
+	UserAttribute *UserAttributeClient
+	// WebAuthnChallenge is the client for interacting with the WebAuthnChallenge builders.
+	WebAuthnChallenge *WebAuthnChallengeClient
 
 	// lazily loaded.
 	client     *Client
@@ -149,9 +169,19 @@ func (tx *Tx) Client() *Client {
 }
 
 func (tx *Tx) init() {
+	tx.Credential = NewCredentialClient(tx.config)
+	tx.Identity = NewIdentityClient(tx.config)
+	tx.Lockout = NewLockoutClient(tx.config)
+	tx.MFA = NewMFAClient(tx.config)
+	tx.OneTimeToken = NewOneTimeTokenClient(tx.config)
 	tx.Permission = NewPermissionClient(tx.config)
+	tx.RecoveryCode = NewRecoveryCodeClient(tx.config)
+	tx.RelationTuple = NewRelationTupleClient(tx.config)
 	tx.Role = NewRoleClient(tx.config)
+	tx.RoleAssignment = NewRoleAssignmentClient(tx.config)
 	tx.User = NewUserClient(tx.config)
+	tx.UserAttribute = NewUserAttributeClient(tx.config)
+	tx.WebAuthnChallenge = NewWebAuthnChallengeClient(tx.config)
 }
 
 // txDriver wraps the given dialect.Tx with a nop dialect.Driver implementation.
@@ -161,7 +191,7 @@ func (tx *Tx) init() {
 // of them in order to commit or rollback the transaction.
 //
 // If a closed transaction is embedded in one of the generated entities, and the entity
-// applies a query, for example: Permission.QueryXXX(), the query will be executed
+// applies a query, for example: Credential.QueryXXX(), the query will be executed
 // through the driver which created this transaction.
 //
 // Note that txDriver is not goroutine safe.
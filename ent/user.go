@@ -5,9 +5,11 @@ package ent
 import (
 	"fmt"
 	"strings"
+	"time"
 
 	"entgo.io/ent"
 	"entgo.io/ent/dialect/sql"
+	"github.com/smxlong/users/ent/mfa"
 	"github.com/smxlong/users/ent/user"
 )
 
@@ -16,12 +18,34 @@ type User struct {
 	config `json:"-"`
 	// ID of the ent.
 	ID int `json:"id,omitempty"`
+	// CreatedAt holds the value of the "created_at" field.
+	CreatedAt time.Time `json:"created_at,omitempty"`
+	// UpdatedAt holds the value of the "updated_at" field.
+	UpdatedAt time.Time `json:"updated_at,omitempty"`
+	// DeletedAt holds the value of the "deleted_at" field.
+	DeletedAt *time.Time `json:"deleted_at,omitempty"`
 	// Name holds the value of the "name" field.
 	Name string `json:"name,omitempty"`
 	// Email holds the value of the "email" field.
 	Email string `json:"email,omitempty"`
 	// PasswordHash holds the value of the "password_hash" field.
 	PasswordHash string `json:"password_hash,omitempty"`
+	// EmailVerifiedAt holds the value of the "email_verified_at" field.
+	EmailVerifiedAt *time.Time `json:"email_verified_at,omitempty"`
+	// TokenGeneration holds the value of the "token_generation" field.
+	TokenGeneration int `json:"token_generation,omitempty"`
+	// Status holds the value of the "status" field.
+	Status user.Status `json:"status,omitempty"`
+	// SuspendedUntil holds the value of the "suspended_until" field.
+	SuspendedUntil *time.Time `json:"suspended_until,omitempty"`
+	// LastLoginAt holds the value of the "last_login_at" field.
+	LastLoginAt *time.Time `json:"last_login_at,omitempty"`
+	// LastLoginIP holds the value of the "last_login_ip" field.
+	LastLoginIP string `json:"last_login_ip,omitempty"`
+	// FailedLoginCount holds the value of the "failed_login_count" field.
+	FailedLoginCount int `json:"failed_login_count,omitempty"`
+	// PasswordChangedAt holds the value of the "password_changed_at" field.
+	PasswordChangedAt *time.Time `json:"password_changed_at,omitempty"`
 	// Edges holds the relations/edges for other nodes in the graph.
 	// The values are being populated by the UserQuery when eager-loading is set.
 	Edges        UserEdges `json:"edges"`
@@ -32,9 +56,25 @@ type User struct {
 type UserEdges struct {
 	// Roles holds the value of the roles edge.
 	Roles []*Role `json:"roles,omitempty"`
+	// Identities holds the value of the identities edge.
+	Identities []*Identity `json:"identities,omitempty"`
+	// Mfa holds the value of the mfa edge.
+	Mfa *MFA `json:"mfa,omitempty"`
+	// RecoveryCodes holds the value of the recovery_codes edge.
+	RecoveryCodes []*RecoveryCode `json:"recovery_codes,omitempty"`
+	// Credentials holds the value of the credentials edge.
+	Credentials []*Credential `json:"credentials,omitempty"`
+	// OneTimeTokens holds the value of the one_time_tokens edge.
+	OneTimeTokens []*OneTimeToken `json:"one_time_tokens,omitempty"`
+	// Attributes holds the value of the attributes edge.
+	Attributes []*UserAttribute `json:"attributes,omitempty"`
+	// RoleAssignments holds the value of the role_assignments edge.
+	RoleAssignments []*RoleAssignment `json:"role_assignments,omitempty"`
+	// RelationTuples holds the value of the relation_tuples edge.
+	RelationTuples []*RelationTuple `json:"relation_tuples,omitempty"`
 	// loadedTypes holds the information for reporting if a
 	// type was loaded (or requested) in eager-loading or not.
-	loadedTypes [1]bool
+	loadedTypes [9]bool
 }
 
 // RolesOrErr returns the Roles value or an error if the edge
@@ -46,15 +86,91 @@ func (e UserEdges) RolesOrErr() ([]*Role, error) {
 	return nil, &NotLoadedError{edge: "roles"}
 }
 
+// IdentitiesOrErr returns the Identities value or an error if the edge
+// was not loaded in eager-loading.
+func (e UserEdges) IdentitiesOrErr() ([]*Identity, error) {
+	if e.loadedTypes[1] {
+		return e.Identities, nil
+	}
+	return nil, &NotLoadedError{edge: "identities"}
+}
+
+// MfaOrErr returns the Mfa value or an error if the edge
+// was not loaded in eager-loading, or loaded but was not found.
+func (e UserEdges) MfaOrErr() (*MFA, error) {
+	if e.Mfa != nil {
+		return e.Mfa, nil
+	} else if e.loadedTypes[2] {
+		return nil, &NotFoundError{label: mfa.Label}
+	}
+	return nil, &NotLoadedError{edge: "mfa"}
+}
+
+// RecoveryCodesOrErr returns the RecoveryCodes value or an error if the edge
+// was not loaded in eager-loading.
+func (e UserEdges) RecoveryCodesOrErr() ([]*RecoveryCode, error) {
+	if e.loadedTypes[3] {
+		return e.RecoveryCodes, nil
+	}
+	return nil, &NotLoadedError{edge: "recovery_codes"}
+}
+
+// CredentialsOrErr returns the Credentials value or an error if the edge
+// was not loaded in eager-loading.
+func (e UserEdges) CredentialsOrErr() ([]*Credential, error) {
+	if e.loadedTypes[4] {
+		return e.Credentials, nil
+	}
+	return nil, &NotLoadedError{edge: "credentials"}
+}
+
+// OneTimeTokensOrErr returns the OneTimeTokens value or an error if the edge
+// was not loaded in eager-loading.
+func (e UserEdges) OneTimeTokensOrErr() ([]*OneTimeToken, error) {
+	if e.loadedTypes[5] {
+		return e.OneTimeTokens, nil
+	}
+	return nil, &NotLoadedError{edge: "one_time_tokens"}
+}
+
+// AttributesOrErr returns the Attributes value or an error if the edge
+// was not loaded in eager-loading.
+func (e UserEdges) AttributesOrErr() ([]*UserAttribute, error) {
+	if e.loadedTypes[6] {
+		return e.Attributes, nil
+	}
+	return nil, &NotLoadedError{edge: "attributes"}
+}
+
+// RoleAssignmentsOrErr returns the RoleAssignments value or an error if the edge
+// was not loaded in eager-loading.
+func (e UserEdges) RoleAssignmentsOrErr() ([]*RoleAssignment, error) {
+	if e.loadedTypes[7] {
+		return e.RoleAssignments, nil
+	}
+	return nil, &NotLoadedError{edge: "role_assignments"}
+}
+
+// RelationTuplesOrErr returns the RelationTuples value or an error if the edge
+// was not loaded in eager-loading.
+func (e UserEdges) RelationTuplesOrErr() ([]*RelationTuple, error) {
+	if e.loadedTypes[8] {
+		return e.RelationTuples, nil
+	}
+	return nil, &NotLoadedError{edge: "relation_tuples"}
+}
+
 // scanValues returns the types for scanning values from sql.Rows.
 func (*User) scanValues(columns []string) ([]any, error) {
 	values := make([]any, len(columns))
 	for i := range columns {
 		switch columns[i] {
-		case user.FieldID:
+		case user.FieldID, user.FieldTokenGeneration, user.FieldFailedLoginCount:
 			values[i] = new(sql.NullInt64)
-		case user.FieldName, user.FieldEmail, user.FieldPasswordHash:
+		case user.FieldName, user.FieldEmail, user.FieldPasswordHash, user.FieldStatus, user.FieldLastLoginIP:
 			values[i] = new(sql.NullString)
+		case user.FieldCreatedAt, user.FieldUpdatedAt, user.FieldDeletedAt, user.FieldEmailVerifiedAt, user.FieldSuspendedUntil, user.FieldLastLoginAt, user.FieldPasswordChangedAt:
+			values[i] = new(sql.NullTime)
 		default:
 			values[i] = new(sql.UnknownType)
 		}
@@ -76,6 +192,25 @@ func (u *User) assignValues(columns []string, values []any) error {
 				return fmt.Errorf("unexpected type %T for field id", value)
 			}
 			u.ID = int(value.Int64)
+		case user.FieldCreatedAt:
+			if value, ok := values[i].(*sql.NullTime); !ok {
+				return fmt.Errorf("unexpected type %T for field created_at", values[i])
+			} else if value.Valid {
+				u.CreatedAt = value.Time
+			}
+		case user.FieldUpdatedAt:
+			if value, ok := values[i].(*sql.NullTime); !ok {
+				return fmt.Errorf("unexpected type %T for field updated_at", values[i])
+			} else if value.Valid {
+				u.UpdatedAt = value.Time
+			}
+		case user.FieldDeletedAt:
+			if value, ok := values[i].(*sql.NullTime); !ok {
+				return fmt.Errorf("unexpected type %T for field deleted_at", values[i])
+			} else if value.Valid {
+				u.DeletedAt = new(time.Time)
+				*u.DeletedAt = value.Time
+			}
 		case user.FieldName:
 			if value, ok := values[i].(*sql.NullString); !ok {
 				return fmt.Errorf("unexpected type %T for field name", values[i])
@@ -94,6 +229,58 @@ func (u *User) assignValues(columns []string, values []any) error {
 			} else if value.Valid {
 				u.PasswordHash = value.String
 			}
+		case user.FieldEmailVerifiedAt:
+			if value, ok := values[i].(*sql.NullTime); !ok {
+				return fmt.Errorf("unexpected type %T for field email_verified_at", values[i])
+			} else if value.Valid {
+				u.EmailVerifiedAt = new(time.Time)
+				*u.EmailVerifiedAt = value.Time
+			}
+		case user.FieldTokenGeneration:
+			if value, ok := values[i].(*sql.NullInt64); !ok {
+				return fmt.Errorf("unexpected type %T for field token_generation", values[i])
+			} else if value.Valid {
+				u.TokenGeneration = int(value.Int64)
+			}
+		case user.FieldStatus:
+			if value, ok := values[i].(*sql.NullString); !ok {
+				return fmt.Errorf("unexpected type %T for field status", values[i])
+			} else if value.Valid {
+				u.Status = user.Status(value.String)
+			}
+		case user.FieldSuspendedUntil:
+			if value, ok := values[i].(*sql.NullTime); !ok {
+				return fmt.Errorf("unexpected type %T for field suspended_until", values[i])
+			} else if value.Valid {
+				u.SuspendedUntil = new(time.Time)
+				*u.SuspendedUntil = value.Time
+			}
+		case user.FieldLastLoginAt:
+			if value, ok := values[i].(*sql.NullTime); !ok {
+				return fmt.Errorf("unexpected type %T for field last_login_at", values[i])
+			} else if value.Valid {
+				u.LastLoginAt = new(time.Time)
+				*u.LastLoginAt = value.Time
+			}
+		case user.FieldLastLoginIP:
+			if value, ok := values[i].(*sql.NullString); !ok {
+				return fmt.Errorf("unexpected type %T for field last_login_ip", values[i])
+			} else if value.Valid {
+				u.LastLoginIP = value.String
+			}
+		case user.FieldFailedLoginCount:
+			if value, ok := values[i].(*sql.NullInt64); !ok {
+				return fmt.Errorf("unexpected type %T for field failed_login_count", values[i])
+			} else if value.Valid {
+				u.FailedLoginCount = int(value.Int64)
+			}
+		case user.FieldPasswordChangedAt:
+			if value, ok := values[i].(*sql.NullTime); !ok {
+				return fmt.Errorf("unexpected type %T for field password_changed_at", values[i])
+			} else if value.Valid {
+				u.PasswordChangedAt = new(time.Time)
+				*u.PasswordChangedAt = value.Time
+			}
 		default:
 			u.selectValues.Set(columns[i], values[i])
 		}
@@ -112,6 +299,46 @@ func (u *User) QueryRoles() *RoleQuery {
 	return NewUserClient(u.config).QueryRoles(u)
 }
 
+// QueryIdentities queries the "identities" edge of the User entity.
+func (u *User) QueryIdentities() *IdentityQuery {
+	return NewUserClient(u.config).QueryIdentities(u)
+}
+
+// QueryMfa queries the "mfa" edge of the User entity.
+func (u *User) QueryMfa() *MFAQuery {
+	return NewUserClient(u.config).QueryMfa(u)
+}
+
+// QueryRecoveryCodes queries the "recovery_codes" edge of the User entity.
+func (u *User) QueryRecoveryCodes() *RecoveryCodeQuery {
+	return NewUserClient(u.config).QueryRecoveryCodes(u)
+}
+
+// QueryCredentials queries the "credentials" edge of the User entity.
+func (u *User) QueryCredentials() *CredentialQuery {
+	return NewUserClient(u.config).QueryCredentials(u)
+}
+
+// QueryOneTimeTokens queries the "one_time_tokens" edge of the User entity.
+func (u *User) QueryOneTimeTokens() *OneTimeTokenQuery {
+	return NewUserClient(u.config).QueryOneTimeTokens(u)
+}
+
+// QueryAttributes queries the "attributes" edge of the User entity.
+func (u *User) QueryAttributes() *UserAttributeQuery {
+	return NewUserClient(u.config).QueryAttributes(u)
+}
+
+// QueryRoleAssignments queries the "role_assignments" edge of the User entity.
+func (u *User) QueryRoleAssignments() *RoleAssignmentQuery {
+	return NewUserClient(u.config).QueryRoleAssignments(u)
+}
+
+// QueryRelationTuples queries the "relation_tuples" edge of the User entity.
+func (u *User) QueryRelationTuples() *RelationTupleQuery {
+	return NewUserClient(u.config).QueryRelationTuples(u)
+}
+
 // Update returns a builder for updating this User.
 // Note that you need to call User.Unwrap() before calling this method if this User
 // was returned from a transaction, and the transaction was committed or rolled back.
@@ -135,6 +362,17 @@ func (u *User) String() string {
 	var builder strings.Builder
 	builder.WriteString("User(")
 	builder.WriteString(fmt.Sprintf("id=%v, ", u.ID))
+	builder.WriteString("created_at=")
+	builder.WriteString(u.CreatedAt.Format(time.ANSIC))
+	builder.WriteString(", ")
+	builder.WriteString("updated_at=")
+	builder.WriteString(u.UpdatedAt.Format(time.ANSIC))
+	builder.WriteString(", ")
+	if v := u.DeletedAt; v != nil {
+		builder.WriteString("deleted_at=")
+		builder.WriteString(v.Format(time.ANSIC))
+	}
+	builder.WriteString(", ")
 	builder.WriteString("name=")
 	builder.WriteString(u.Name)
 	builder.WriteString(", ")
@@ -143,9 +381,49 @@ func (u *User) String() string {
 	builder.WriteString(", ")
 	builder.WriteString("password_hash=")
 	builder.WriteString(u.PasswordHash)
+	builder.WriteString(", ")
+	if v := u.EmailVerifiedAt; v != nil {
+		builder.WriteString("email_verified_at=")
+		builder.WriteString(v.Format(time.ANSIC))
+	}
+	builder.WriteString(", ")
+	builder.WriteString("token_generation=")
+	builder.WriteString(fmt.Sprintf("%v", u.TokenGeneration))
+	builder.WriteString(", ")
+	builder.WriteString("status=")
+	builder.WriteString(fmt.Sprintf("%v", u.Status))
+	builder.WriteString(", ")
+	if v := u.SuspendedUntil; v != nil {
+		builder.WriteString("suspended_until=")
+		builder.WriteString(v.Format(time.ANSIC))
+	}
+	builder.WriteString(", ")
+	if v := u.LastLoginAt; v != nil {
+		builder.WriteString("last_login_at=")
+		builder.WriteString(v.Format(time.ANSIC))
+	}
+	builder.WriteString(", ")
+	builder.WriteString("last_login_ip=")
+	builder.WriteString(u.LastLoginIP)
+	builder.WriteString(", ")
+	builder.WriteString("failed_login_count=")
+	builder.WriteString(fmt.Sprintf("%v", u.FailedLoginCount))
+	builder.WriteString(", ")
+	if v := u.PasswordChangedAt; v != nil {
+		builder.WriteString("password_changed_at=")
+		builder.WriteString(v.Format(time.ANSIC))
+	}
 	builder.WriteByte(')')
 	return builder.String()
 }
 
+// Client returns a client using the configuration u was loaded with,
+// including its transaction, if any.
+func (u *User) Client() *Client {
+	c := &Client{config: u.config}
+	c.init()
+	return c
+}
+
 // Users is a parsable slice of User.
 type Users []*User
@@ -0,0 +1,167 @@
+// Code generated by ent, DO NOT EDIT.
+
+package ent
+
+import (
+	"fmt"
+	"strings"
+
+	"entgo.io/ent"
+	"entgo.io/ent/dialect/sql"
+	"github.com/smxlong/users/ent/user"
+	"github.com/smxlong/users/ent/userattribute"
+)
+
+// UserAttribute is the model entity for the UserAttribute schema.
+type UserAttribute struct {
+	config `json:"-"`
+	// ID of the ent.
+	ID int `json:"id,omitempty"`
+	// Name holds the value of the "name" field.
+	Name string `json:"name,omitempty"`
+	// Value holds the value of the "value" field.
+	Value string `json:"value,omitempty"`
+	// UniqueValue holds the value of the "unique_value" field.
+	UniqueValue *string `json:"unique_value,omitempty"`
+	// Edges holds the relations/edges for other nodes in the graph.
+	// The values are being populated by the UserAttributeQuery when eager-loading is set.
+	Edges           UserAttributeEdges `json:"edges"`
+	user_attributes *int
+	selectValues    sql.SelectValues
+}
+
+// UserAttributeEdges holds the relations/edges for other nodes in the graph.
+type UserAttributeEdges struct {
+	// User holds the value of the user edge.
+	User *User `json:"user,omitempty"`
+	// loadedTypes holds the information for reporting if a
+	// type was loaded (or requested) in eager-loading or not.
+	loadedTypes [1]bool
+}
+
+// UserOrErr returns the User value or an error if the edge
+// was not loaded in eager-loading, or loaded but was not found.
+func (e UserAttributeEdges) UserOrErr() (*User, error) {
+	if e.User != nil {
+		return e.User, nil
+	} else if e.loadedTypes[0] {
+		return nil, &NotFoundError{label: user.Label}
+	}
+	return nil, &NotLoadedError{edge: "user"}
+}
+
+// scanValues returns the types for scanning values from sql.Rows.
+func (*UserAttribute) scanValues(columns []string) ([]any, error) {
+	values := make([]any, len(columns))
+	for i := range columns {
+		switch columns[i] {
+		case userattribute.FieldID:
+			values[i] = new(sql.NullInt64)
+		case userattribute.FieldName, userattribute.FieldValue, userattribute.FieldUniqueValue:
+			values[i] = new(sql.NullString)
+		case userattribute.ForeignKeys[0]: // user_attributes
+			values[i] = new(sql.NullInt64)
+		default:
+			values[i] = new(sql.UnknownType)
+		}
+	}
+	return values, nil
+}
+
+// assignValues assigns the values that were returned from sql.Rows (after scanning)
+// to the UserAttribute fields.
+func (ua *UserAttribute) assignValues(columns []string, values []any) error {
+	if m, n := len(values), len(columns); m < n {
+		return fmt.Errorf("mismatch number of scan values: %d != %d", m, n)
+	}
+	for i := range columns {
+		switch columns[i] {
+		case userattribute.FieldID:
+			value, ok := values[i].(*sql.NullInt64)
+			if !ok {
+				return fmt.Errorf("unexpected type %T for field id", value)
+			}
+			ua.ID = int(value.Int64)
+		case userattribute.FieldName:
+			if value, ok := values[i].(*sql.NullString); !ok {
+				return fmt.Errorf("unexpected type %T for field name", values[i])
+			} else if value.Valid {
+				ua.Name = value.String
+			}
+		case userattribute.FieldValue:
+			if value, ok := values[i].(*sql.NullString); !ok {
+				return fmt.Errorf("unexpected type %T for field value", values[i])
+			} else if value.Valid {
+				ua.Value = value.String
+			}
+		case userattribute.FieldUniqueValue:
+			if value, ok := values[i].(*sql.NullString); !ok {
+				return fmt.Errorf("unexpected type %T for field unique_value", values[i])
+			} else if value.Valid {
+				ua.UniqueValue = new(string)
+				*ua.UniqueValue = value.String
+			}
+		case userattribute.ForeignKeys[0]:
+			if value, ok := values[i].(*sql.NullInt64); !ok {
+				return fmt.Errorf("unexpected type %T for edge-field user_attributes", value)
+			} else if value.Valid {
+				ua.user_attributes = new(int)
+				*ua.user_attributes = int(value.Int64)
+			}
+		default:
+			ua.selectValues.Set(columns[i], values[i])
+		}
+	}
+	return nil
+}
+
+// GetValue returns the ent.Value that was dynamically selected and assigned to the UserAttribute.
+// This includes values selected through modifiers, order, etc.
+func (ua *UserAttribute) GetValue(name string) (ent.Value, error) {
+	return ua.selectValues.Get(name)
+}
+
+// QueryUser queries the "user" edge of the UserAttribute entity.
+func (ua *UserAttribute) QueryUser() *UserQuery {
+	return NewUserAttributeClient(ua.config).QueryUser(ua)
+}
+
+// Update returns a builder for updating this UserAttribute.
+// Note that you need to call UserAttribute.Unwrap() before calling this method if this UserAttribute
+// was returned from a transaction, and the transaction was committed or rolled back.
+func (ua *UserAttribute) Update() *UserAttributeUpdateOne {
+	return NewUserAttributeClient(ua.config).UpdateOne(ua)
+}
+
+// Unwrap unwraps the UserAttribute entity that was returned from a transaction after it was closed,
+// so that all future queries will be executed through the driver which created the transaction.
+func (ua *UserAttribute) Unwrap() *UserAttribute {
+	_tx, ok := ua.config.driver.(*txDriver)
+	if !ok {
+		panic("ent: UserAttribute is not a transactional entity")
+	}
+	ua.config.driver = _tx.drv
+	return ua
+}
+
+// String implements the fmt.Stringer.
+func (ua *UserAttribute) String() string {
+	var builder strings.Builder
+	builder.WriteString("UserAttribute(")
+	builder.WriteString(fmt.Sprintf("id=%v, ", ua.ID))
+	builder.WriteString("name=")
+	builder.WriteString(ua.Name)
+	builder.WriteString(", ")
+	builder.WriteString("value=")
+	builder.WriteString(ua.Value)
+	builder.WriteString(", ")
+	if v := ua.UniqueValue; v != nil {
+		builder.WriteString("unique_value=")
+		builder.WriteString(*v)
+	}
+	builder.WriteByte(')')
+	return builder.String()
+}
+
+// UserAttributes is a parsable slice of UserAttribute.
+type UserAttributes []*UserAttribute
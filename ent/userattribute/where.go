@@ -0,0 +1,312 @@
+// Code generated by ent, DO NOT EDIT.
+
+package userattribute
+
+import (
+	"entgo.io/ent/dialect/sql"
+	"entgo.io/ent/dialect/sql/sqlgraph"
+	"github.com/smxlong/users/ent/predicate"
+)
+
+// ID filters vertices based on their ID field.
+func ID(id int) predicate.UserAttribute {
+	return predicate.UserAttribute(sql.FieldEQ(FieldID, id))
+}
+
+// IDEQ applies the EQ predicate on the ID field.
+func IDEQ(id int) predicate.UserAttribute {
+	return predicate.UserAttribute(sql.FieldEQ(FieldID, id))
+}
+
+// IDNEQ applies the NEQ predicate on the ID field.
+func IDNEQ(id int) predicate.UserAttribute {
+	return predicate.UserAttribute(sql.FieldNEQ(FieldID, id))
+}
+
+// IDIn applies the In predicate on the ID field.
+func IDIn(ids ...int) predicate.UserAttribute {
+	return predicate.UserAttribute(sql.FieldIn(FieldID, ids...))
+}
+
+// IDNotIn applies the NotIn predicate on the ID field.
+func IDNotIn(ids ...int) predicate.UserAttribute {
+	return predicate.UserAttribute(sql.FieldNotIn(FieldID, ids...))
+}
+
+// IDGT applies the GT predicate on the ID field.
+func IDGT(id int) predicate.UserAttribute {
+	return predicate.UserAttribute(sql.FieldGT(FieldID, id))
+}
+
+// IDGTE applies the GTE predicate on the ID field.
+func IDGTE(id int) predicate.UserAttribute {
+	return predicate.UserAttribute(sql.FieldGTE(FieldID, id))
+}
+
+// IDLT applies the LT predicate on the ID field.
+func IDLT(id int) predicate.UserAttribute {
+	return predicate.UserAttribute(sql.FieldLT(FieldID, id))
+}
+
+// IDLTE applies the LTE predicate on the ID field.
+func IDLTE(id int) predicate.UserAttribute {
+	return predicate.UserAttribute(sql.FieldLTE(FieldID, id))
+}
+
+// Name applies equality check predicate on the "name" field. It's identical to NameEQ.
+func Name(v string) predicate.UserAttribute {
+	return predicate.UserAttribute(sql.FieldEQ(FieldName, v))
+}
+
+// Value applies equality check predicate on the "value" field. It's identical to ValueEQ.
+func Value(v string) predicate.UserAttribute {
+	return predicate.UserAttribute(sql.FieldEQ(FieldValue, v))
+}
+
+// UniqueValue applies equality check predicate on the "unique_value" field. It's identical to UniqueValueEQ.
+func UniqueValue(v string) predicate.UserAttribute {
+	return predicate.UserAttribute(sql.FieldEQ(FieldUniqueValue, v))
+}
+
+// NameEQ applies the EQ predicate on the "name" field.
+func NameEQ(v string) predicate.UserAttribute {
+	return predicate.UserAttribute(sql.FieldEQ(FieldName, v))
+}
+
+// NameNEQ applies the NEQ predicate on the "name" field.
+func NameNEQ(v string) predicate.UserAttribute {
+	return predicate.UserAttribute(sql.FieldNEQ(FieldName, v))
+}
+
+// NameIn applies the In predicate on the "name" field.
+func NameIn(vs ...string) predicate.UserAttribute {
+	return predicate.UserAttribute(sql.FieldIn(FieldName, vs...))
+}
+
+// NameNotIn applies the NotIn predicate on the "name" field.
+func NameNotIn(vs ...string) predicate.UserAttribute {
+	return predicate.UserAttribute(sql.FieldNotIn(FieldName, vs...))
+}
+
+// NameGT applies the GT predicate on the "name" field.
+func NameGT(v string) predicate.UserAttribute {
+	return predicate.UserAttribute(sql.FieldGT(FieldName, v))
+}
+
+// NameGTE applies the GTE predicate on the "name" field.
+func NameGTE(v string) predicate.UserAttribute {
+	return predicate.UserAttribute(sql.FieldGTE(FieldName, v))
+}
+
+// NameLT applies the LT predicate on the "name" field.
+func NameLT(v string) predicate.UserAttribute {
+	return predicate.UserAttribute(sql.FieldLT(FieldName, v))
+}
+
+// NameLTE applies the LTE predicate on the "name" field.
+func NameLTE(v string) predicate.UserAttribute {
+	return predicate.UserAttribute(sql.FieldLTE(FieldName, v))
+}
+
+// NameContains applies the Contains predicate on the "name" field.
+func NameContains(v string) predicate.UserAttribute {
+	return predicate.UserAttribute(sql.FieldContains(FieldName, v))
+}
+
+// NameHasPrefix applies the HasPrefix predicate on the "name" field.
+func NameHasPrefix(v string) predicate.UserAttribute {
+	return predicate.UserAttribute(sql.FieldHasPrefix(FieldName, v))
+}
+
+// NameHasSuffix applies the HasSuffix predicate on the "name" field.
+func NameHasSuffix(v string) predicate.UserAttribute {
+	return predicate.UserAttribute(sql.FieldHasSuffix(FieldName, v))
+}
+
+// NameEqualFold applies the EqualFold predicate on the "name" field.
+func NameEqualFold(v string) predicate.UserAttribute {
+	return predicate.UserAttribute(sql.FieldEqualFold(FieldName, v))
+}
+
+// NameContainsFold applies the ContainsFold predicate on the "name" field.
+func NameContainsFold(v string) predicate.UserAttribute {
+	return predicate.UserAttribute(sql.FieldContainsFold(FieldName, v))
+}
+
+// ValueEQ applies the EQ predicate on the "value" field.
+func ValueEQ(v string) predicate.UserAttribute {
+	return predicate.UserAttribute(sql.FieldEQ(FieldValue, v))
+}
+
+// ValueNEQ applies the NEQ predicate on the "value" field.
+func ValueNEQ(v string) predicate.UserAttribute {
+	return predicate.UserAttribute(sql.FieldNEQ(FieldValue, v))
+}
+
+// ValueIn applies the In predicate on the "value" field.
+func ValueIn(vs ...string) predicate.UserAttribute {
+	return predicate.UserAttribute(sql.FieldIn(FieldValue, vs...))
+}
+
+// ValueNotIn applies the NotIn predicate on the "value" field.
+func ValueNotIn(vs ...string) predicate.UserAttribute {
+	return predicate.UserAttribute(sql.FieldNotIn(FieldValue, vs...))
+}
+
+// ValueGT applies the GT predicate on the "value" field.
+func ValueGT(v string) predicate.UserAttribute {
+	return predicate.UserAttribute(sql.FieldGT(FieldValue, v))
+}
+
+// ValueGTE applies the GTE predicate on the "value" field.
+func ValueGTE(v string) predicate.UserAttribute {
+	return predicate.UserAttribute(sql.FieldGTE(FieldValue, v))
+}
+
+// ValueLT applies the LT predicate on the "value" field.
+func ValueLT(v string) predicate.UserAttribute {
+	return predicate.UserAttribute(sql.FieldLT(FieldValue, v))
+}
+
+// ValueLTE applies the LTE predicate on the "value" field.
+func ValueLTE(v string) predicate.UserAttribute {
+	return predicate.UserAttribute(sql.FieldLTE(FieldValue, v))
+}
+
+// ValueContains applies the Contains predicate on the "value" field.
+func ValueContains(v string) predicate.UserAttribute {
+	return predicate.UserAttribute(sql.FieldContains(FieldValue, v))
+}
+
+// ValueHasPrefix applies the HasPrefix predicate on the "value" field.
+func ValueHasPrefix(v string) predicate.UserAttribute {
+	return predicate.UserAttribute(sql.FieldHasPrefix(FieldValue, v))
+}
+
+// ValueHasSuffix applies the HasSuffix predicate on the "value" field.
+func ValueHasSuffix(v string) predicate.UserAttribute {
+	return predicate.UserAttribute(sql.FieldHasSuffix(FieldValue, v))
+}
+
+// ValueEqualFold applies the EqualFold predicate on the "value" field.
+func ValueEqualFold(v string) predicate.UserAttribute {
+	return predicate.UserAttribute(sql.FieldEqualFold(FieldValue, v))
+}
+
+// ValueContainsFold applies the ContainsFold predicate on the "value" field.
+func ValueContainsFold(v string) predicate.UserAttribute {
+	return predicate.UserAttribute(sql.FieldContainsFold(FieldValue, v))
+}
+
+// UniqueValueEQ applies the EQ predicate on the "unique_value" field.
+func UniqueValueEQ(v string) predicate.UserAttribute {
+	return predicate.UserAttribute(sql.FieldEQ(FieldUniqueValue, v))
+}
+
+// UniqueValueNEQ applies the NEQ predicate on the "unique_value" field.
+func UniqueValueNEQ(v string) predicate.UserAttribute {
+	return predicate.UserAttribute(sql.FieldNEQ(FieldUniqueValue, v))
+}
+
+// UniqueValueIn applies the In predicate on the "unique_value" field.
+func UniqueValueIn(vs ...string) predicate.UserAttribute {
+	return predicate.UserAttribute(sql.FieldIn(FieldUniqueValue, vs...))
+}
+
+// UniqueValueNotIn applies the NotIn predicate on the "unique_value" field.
+func UniqueValueNotIn(vs ...string) predicate.UserAttribute {
+	return predicate.UserAttribute(sql.FieldNotIn(FieldUniqueValue, vs...))
+}
+
+// UniqueValueGT applies the GT predicate on the "unique_value" field.
+func UniqueValueGT(v string) predicate.UserAttribute {
+	return predicate.UserAttribute(sql.FieldGT(FieldUniqueValue, v))
+}
+
+// UniqueValueGTE applies the GTE predicate on the "unique_value" field.
+func UniqueValueGTE(v string) predicate.UserAttribute {
+	return predicate.UserAttribute(sql.FieldGTE(FieldUniqueValue, v))
+}
+
+// UniqueValueLT applies the LT predicate on the "unique_value" field.
+func UniqueValueLT(v string) predicate.UserAttribute {
+	return predicate.UserAttribute(sql.FieldLT(FieldUniqueValue, v))
+}
+
+// UniqueValueLTE applies the LTE predicate on the "unique_value" field.
+func UniqueValueLTE(v string) predicate.UserAttribute {
+	return predicate.UserAttribute(sql.FieldLTE(FieldUniqueValue, v))
+}
+
+// UniqueValueContains applies the Contains predicate on the "unique_value" field.
+func UniqueValueContains(v string) predicate.UserAttribute {
+	return predicate.UserAttribute(sql.FieldContains(FieldUniqueValue, v))
+}
+
+// UniqueValueHasPrefix applies the HasPrefix predicate on the "unique_value" field.
+func UniqueValueHasPrefix(v string) predicate.UserAttribute {
+	return predicate.UserAttribute(sql.FieldHasPrefix(FieldUniqueValue, v))
+}
+
+// UniqueValueHasSuffix applies the HasSuffix predicate on the "unique_value" field.
+func UniqueValueHasSuffix(v string) predicate.UserAttribute {
+	return predicate.UserAttribute(sql.FieldHasSuffix(FieldUniqueValue, v))
+}
+
+// UniqueValueIsNil applies the IsNil predicate on the "unique_value" field.
+func UniqueValueIsNil() predicate.UserAttribute {
+	return predicate.UserAttribute(sql.FieldIsNull(FieldUniqueValue))
+}
+
+// UniqueValueNotNil applies the NotNil predicate on the "unique_value" field.
+func UniqueValueNotNil() predicate.UserAttribute {
+	return predicate.UserAttribute(sql.FieldNotNull(FieldUniqueValue))
+}
+
+// UniqueValueEqualFold applies the EqualFold predicate on the "unique_value" field.
+func UniqueValueEqualFold(v string) predicate.UserAttribute {
+	return predicate.UserAttribute(sql.FieldEqualFold(FieldUniqueValue, v))
+}
+
+// UniqueValueContainsFold applies the ContainsFold predicate on the "unique_value" field.
+func UniqueValueContainsFold(v string) predicate.UserAttribute {
+	return predicate.UserAttribute(sql.FieldContainsFold(FieldUniqueValue, v))
+}
+
+// HasUser applies the HasEdge predicate on the "user" edge.
+func HasUser() predicate.UserAttribute {
+	return predicate.UserAttribute(func(s *sql.Selector) {
+		step := sqlgraph.NewStep(
+			sqlgraph.From(Table, FieldID),
+			sqlgraph.Edge(sqlgraph.M2O, true, UserTable, UserColumn),
+		)
+		sqlgraph.HasNeighbors(s, step)
+	})
+}
+
+// HasUserWith applies the HasEdge predicate on the "user" edge with a given conditions (other predicates).
+func HasUserWith(preds ...predicate.User) predicate.UserAttribute {
+	return predicate.UserAttribute(func(s *sql.Selector) {
+		step := newUserStep()
+		sqlgraph.HasNeighborsWith(s, step, func(s *sql.Selector) {
+			for _, p := range preds {
+				p(s)
+			}
+		})
+	})
+}
+
+// And groups predicates with the AND operator between them.
+func And(predicates ...predicate.UserAttribute) predicate.UserAttribute {
+	return predicate.UserAttribute(sql.AndPredicates(predicates...))
+}
+
+// Or groups predicates with the OR operator between them.
+func Or(predicates ...predicate.UserAttribute) predicate.UserAttribute {
+	return predicate.UserAttribute(sql.OrPredicates(predicates...))
+}
+
+// Not applies the not operator on the given predicate.
+func Not(p predicate.UserAttribute) predicate.UserAttribute {
+	return predicate.UserAttribute(sql.NotPredicates(p))
+}
@@ -0,0 +1,251 @@
+// Code generated by ent, DO NOT EDIT.
+
+package ent
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"entgo.io/ent/dialect/sql/sqlgraph"
+	"entgo.io/ent/schema/field"
+	"github.com/smxlong/users/ent/user"
+	"github.com/smxlong/users/ent/userattribute"
+)
+
+// UserAttributeCreate is the builder for creating a UserAttribute entity.
+type UserAttributeCreate struct {
+	config
+	mutation *UserAttributeMutation
+	hooks    []Hook
+}
+
+// SetName sets the "name" field.
+func (uac *UserAttributeCreate) SetName(s string) *UserAttributeCreate {
+	uac.mutation.SetName(s)
+	return uac
+}
+
+// SetValue sets the "value" field.
+func (uac *UserAttributeCreate) SetValue(s string) *UserAttributeCreate {
+	uac.mutation.SetValue(s)
+	return uac
+}
+
+// SetUniqueValue sets the "unique_value" field.
+func (uac *UserAttributeCreate) SetUniqueValue(s string) *UserAttributeCreate {
+	uac.mutation.SetUniqueValue(s)
+	return uac
+}
+
+// SetNillableUniqueValue sets the "unique_value" field if the given value is not nil.
+func (uac *UserAttributeCreate) SetNillableUniqueValue(s *string) *UserAttributeCreate {
+	if s != nil {
+		uac.SetUniqueValue(*s)
+	}
+	return uac
+}
+
+// SetUserID sets the "user" edge to the User entity by ID.
+func (uac *UserAttributeCreate) SetUserID(id int) *UserAttributeCreate {
+	uac.mutation.SetUserID(id)
+	return uac
+}
+
+// SetUser sets the "user" edge to the User entity.
+func (uac *UserAttributeCreate) SetUser(u *User) *UserAttributeCreate {
+	return uac.SetUserID(u.ID)
+}
+
+// Mutation returns the UserAttributeMutation object of the builder.
+func (uac *UserAttributeCreate) Mutation() *UserAttributeMutation {
+	return uac.mutation
+}
+
+// Save creates the UserAttribute in the database.
+func (uac *UserAttributeCreate) Save(ctx context.Context) (*UserAttribute, error) {
+	return withHooks(ctx, uac.sqlSave, uac.mutation, uac.hooks)
+}
+
+// SaveX calls Save and panics if Save returns an error.
+func (uac *UserAttributeCreate) SaveX(ctx context.Context) *UserAttribute {
+	v, err := uac.Save(ctx)
+	if err != nil {
+		panic(err)
+	}
+	return v
+}
+
+// Exec executes the query.
+func (uac *UserAttributeCreate) Exec(ctx context.Context) error {
+	_, err := uac.Save(ctx)
+	return err
+}
+
+// ExecX is like Exec, but panics if an error occurs.
+func (uac *UserAttributeCreate) ExecX(ctx context.Context) {
+	if err := uac.Exec(ctx); err != nil {
+		panic(err)
+	}
+}
+
+// check runs all checks and user-defined validators on the builder.
+func (uac *UserAttributeCreate) check() error {
+	if _, ok := uac.mutation.Name(); !ok {
+		return &ValidationError{Name: "name", err: errors.New(`ent: missing required field "UserAttribute.name"`)}
+	}
+	if v, ok := uac.mutation.Name(); ok {
+		if err := userattribute.NameValidator(v); err != nil {
+			return &ValidationError{Name: "name", err: fmt.Errorf(`ent: validator failed for field "UserAttribute.name": %w`, err)}
+		}
+	}
+	if _, ok := uac.mutation.Value(); !ok {
+		return &ValidationError{Name: "value", err: errors.New(`ent: missing required field "UserAttribute.value"`)}
+	}
+	if len(uac.mutation.UserIDs()) == 0 {
+		return &ValidationError{Name: "user", err: errors.New(`ent: missing required edge "UserAttribute.user"`)}
+	}
+	return nil
+}
+
+func (uac *UserAttributeCreate) sqlSave(ctx context.Context) (*UserAttribute, error) {
+	if err := uac.check(); err != nil {
+		return nil, err
+	}
+	_node, _spec := uac.createSpec()
+	if err := sqlgraph.CreateNode(ctx, uac.driver, _spec); err != nil {
+		if sqlgraph.IsConstraintError(err) {
+			err = &ConstraintError{msg: err.Error(), wrap: err}
+		}
+		return nil, err
+	}
+	id := _spec.ID.Value.(int64)
+	_node.ID = int(id)
+	uac.mutation.id = &_node.ID
+	uac.mutation.done = true
+	return _node, nil
+}
+
+func (uac *UserAttributeCreate) createSpec() (*UserAttribute, *sqlgraph.CreateSpec) {
+	var (
+		_node = &UserAttribute{config: uac.config}
+		_spec = sqlgraph.NewCreateSpec(userattribute.Table, sqlgraph.NewFieldSpec(userattribute.FieldID, field.TypeInt))
+	)
+	if value, ok := uac.mutation.Name(); ok {
+		_spec.SetField(userattribute.FieldName, field.TypeString, value)
+		_node.Name = value
+	}
+	if value, ok := uac.mutation.Value(); ok {
+		_spec.SetField(userattribute.FieldValue, field.TypeString, value)
+		_node.Value = value
+	}
+	if value, ok := uac.mutation.UniqueValue(); ok {
+		_spec.SetField(userattribute.FieldUniqueValue, field.TypeString, value)
+		_node.UniqueValue = &value
+	}
+	if nodes := uac.mutation.UserIDs(); len(nodes) > 0 {
+		edge := &sqlgraph.EdgeSpec{
+			Rel:     sqlgraph.M2O,
+			Inverse: true,
+			Table:   userattribute.UserTable,
+			Columns: []string{userattribute.UserColumn},
+			Bidi:    false,
+			Target: &sqlgraph.EdgeTarget{
+				IDSpec: sqlgraph.NewFieldSpec(user.FieldID, field.TypeInt),
+			},
+		}
+		for _, k := range nodes {
+			edge.Target.Nodes = append(edge.Target.Nodes, k)
+		}
+		_node.user_attributes = &nodes[0]
+		_spec.Edges = append(_spec.Edges, edge)
+	}
+	return _node, _spec
+}
+
+// UserAttributeCreateBulk is the builder for creating many UserAttribute entities in bulk.
+type UserAttributeCreateBulk struct {
+	config
+	err      error
+	builders []*UserAttributeCreate
+}
+
+// Save creates the UserAttribute entities in the database.
+func (uacb *UserAttributeCreateBulk) Save(ctx context.Context) ([]*UserAttribute, error) {
+	if uacb.err != nil {
+		return nil, uacb.err
+	}
+	specs := make([]*sqlgraph.CreateSpec, len(uacb.builders))
+	nodes := make([]*UserAttribute, len(uacb.builders))
+	mutators := make([]Mutator, len(uacb.builders))
+	for i := range uacb.builders {
+		func(i int, root context.Context) {
+			builder := uacb.builders[i]
+			var mut Mutator = MutateFunc(func(ctx context.Context, m Mutation) (Value, error) {
+				mutation, ok := m.(*UserAttributeMutation)
+				if !ok {
+					return nil, fmt.Errorf("unexpected mutation type %T", m)
+				}
+				if err := builder.check(); err != nil {
+					return nil, err
+				}
+				builder.mutation = mutation
+				var err error
+				nodes[i], specs[i] = builder.createSpec()
+				if i < len(mutators)-1 {
+					_, err = mutators[i+1].Mutate(root, uacb.builders[i+1].mutation)
+				} else {
+					spec := &sqlgraph.BatchCreateSpec{Nodes: specs}
+					// Invoke the actual operation on the latest mutation in the chain.
+					if err = sqlgraph.BatchCreate(ctx, uacb.driver, spec); err != nil {
+						if sqlgraph.IsConstraintError(err) {
+							err = &ConstraintError{msg: err.Error(), wrap: err}
+						}
+					}
+				}
+				if err != nil {
+					return nil, err
+				}
+				mutation.id = &nodes[i].ID
+				if specs[i].ID.Value != nil {
+					id := specs[i].ID.Value.(int64)
+					nodes[i].ID = int(id)
+				}
+				mutation.done = true
+				return nodes[i], nil
+			})
+			for i := len(builder.hooks) - 1; i >= 0; i-- {
+				mut = builder.hooks[i](mut)
+			}
+			mutators[i] = mut
+		}(i, ctx)
+	}
+	if len(mutators) > 0 {
+		if _, err := mutators[0].Mutate(ctx, uacb.builders[0].mutation); err != nil {
+			return nil, err
+		}
+	}
+	return nodes, nil
+}
+
+// SaveX is like Save, but panics if an error occurs.
+func (uacb *UserAttributeCreateBulk) SaveX(ctx context.Context) []*UserAttribute {
+	v, err := uacb.Save(ctx)
+	if err != nil {
+		panic(err)
+	}
+	return v
+}
+
+// Exec executes the query.
+func (uacb *UserAttributeCreateBulk) Exec(ctx context.Context) error {
+	_, err := uacb.Save(ctx)
+	return err
+}
+
+// ExecX is like Exec, but panics if an error occurs.
+func (uacb *UserAttributeCreateBulk) ExecX(ctx context.Context) {
+	if err := uacb.Exec(ctx); err != nil {
+		panic(err)
+	}
+}
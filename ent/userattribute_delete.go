@@ -0,0 +1,88 @@
+// Code generated by ent, DO NOT EDIT.
+
+package ent
+
+import (
+	"context"
+
+	"entgo.io/ent/dialect/sql"
+	"entgo.io/ent/dialect/sql/sqlgraph"
+	"entgo.io/ent/schema/field"
+	"github.com/smxlong/users/ent/predicate"
+	"github.com/smxlong/users/ent/userattribute"
+)
+
+// UserAttributeDelete is the builder for deleting a UserAttribute entity.
+type UserAttributeDelete struct {
+	config
+	hooks    []Hook
+	mutation *UserAttributeMutation
+}
+
+// Where appends a list predicates to the UserAttributeDelete builder.
+func (uad *UserAttributeDelete) Where(ps ...predicate.UserAttribute) *UserAttributeDelete {
+	uad.mutation.Where(ps...)
+	return uad
+}
+
+// Exec executes the deletion query and returns how many vertices were deleted.
+func (uad *UserAttributeDelete) Exec(ctx context.Context) (int, error) {
+	return withHooks(ctx, uad.sqlExec, uad.mutation, uad.hooks)
+}
+
+// ExecX is like Exec, but panics if an error occurs.
+func (uad *UserAttributeDelete) ExecX(ctx context.Context) int {
+	n, err := uad.Exec(ctx)
+	if err != nil {
+		panic(err)
+	}
+	return n
+}
+
+func (uad *UserAttributeDelete) sqlExec(ctx context.Context) (int, error) {
+	_spec := sqlgraph.NewDeleteSpec(userattribute.Table, sqlgraph.NewFieldSpec(userattribute.FieldID, field.TypeInt))
+	if ps := uad.mutation.predicates; len(ps) > 0 {
+		_spec.Predicate = func(selector *sql.Selector) {
+			for i := range ps {
+				ps[i](selector)
+			}
+		}
+	}
+	affected, err := sqlgraph.DeleteNodes(ctx, uad.driver, _spec)
+	if err != nil && sqlgraph.IsConstraintError(err) {
+		err = &ConstraintError{msg: err.Error(), wrap: err}
+	}
+	uad.mutation.done = true
+	return affected, err
+}
+
+// UserAttributeDeleteOne is the builder for deleting a single UserAttribute entity.
+type UserAttributeDeleteOne struct {
+	uad *UserAttributeDelete
+}
+
+// Where appends a list predicates to the UserAttributeDelete builder.
+func (uado *UserAttributeDeleteOne) Where(ps ...predicate.UserAttribute) *UserAttributeDeleteOne {
+	uado.uad.mutation.Where(ps...)
+	return uado
+}
+
+// Exec executes the deletion query.
+func (uado *UserAttributeDeleteOne) Exec(ctx context.Context) error {
+	n, err := uado.uad.Exec(ctx)
+	switch {
+	case err != nil:
+		return err
+	case n == 0:
+		return &NotFoundError{userattribute.Label}
+	default:
+		return nil
+	}
+}
+
+// ExecX is like Exec, but panics if an error occurs.
+func (uado *UserAttributeDeleteOne) ExecX(ctx context.Context) {
+	if err := uado.Exec(ctx); err != nil {
+		panic(err)
+	}
+}
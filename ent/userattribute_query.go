@@ -0,0 +1,614 @@
+// Code generated by ent, DO NOT EDIT.
+
+package ent
+
+import (
+	"context"
+	"fmt"
+	"math"
+
+	"entgo.io/ent"
+	"entgo.io/ent/dialect/sql"
+	"entgo.io/ent/dialect/sql/sqlgraph"
+	"entgo.io/ent/schema/field"
+	"github.com/smxlong/users/ent/predicate"
+	"github.com/smxlong/users/ent/user"
+	"github.com/smxlong/users/ent/userattribute"
+)
+
+// UserAttributeQuery is the builder for querying UserAttribute entities.
+type UserAttributeQuery struct {
+	config
+	ctx        *QueryContext
+	order      []userattribute.OrderOption
+	inters     []Interceptor
+	predicates []predicate.UserAttribute
+	withUser   *UserQuery
+	withFKs    bool
+	// intermediate query (i.e. traversal path).
+	sql  *sql.Selector
+	path func(context.Context) (*sql.Selector, error)
+}
+
+// Where adds a new predicate for the UserAttributeQuery builder.
+func (uaq *UserAttributeQuery) Where(ps ...predicate.UserAttribute) *UserAttributeQuery {
+	uaq.predicates = append(uaq.predicates, ps...)
+	return uaq
+}
+
+// Limit the number of records to be returned by this query.
+func (uaq *UserAttributeQuery) Limit(limit int) *UserAttributeQuery {
+	uaq.ctx.Limit = &limit
+	return uaq
+}
+
+// Offset to start from.
+func (uaq *UserAttributeQuery) Offset(offset int) *UserAttributeQuery {
+	uaq.ctx.Offset = &offset
+	return uaq
+}
+
+// Unique configures the query builder to filter duplicate records on query.
+// By default, unique is set to true, and can be disabled using this method.
+func (uaq *UserAttributeQuery) Unique(unique bool) *UserAttributeQuery {
+	uaq.ctx.Unique = &unique
+	return uaq
+}
+
+// Order specifies how the records should be ordered.
+func (uaq *UserAttributeQuery) Order(o ...userattribute.OrderOption) *UserAttributeQuery {
+	uaq.order = append(uaq.order, o...)
+	return uaq
+}
+
+// QueryUser chains the current query on the "user" edge.
+func (uaq *UserAttributeQuery) QueryUser() *UserQuery {
+	query := (&UserClient{config: uaq.config}).Query()
+	query.path = func(ctx context.Context) (fromU *sql.Selector, err error) {
+		if err := uaq.prepareQuery(ctx); err != nil {
+			return nil, err
+		}
+		selector := uaq.sqlQuery(ctx)
+		if err := selector.Err(); err != nil {
+			return nil, err
+		}
+		step := sqlgraph.NewStep(
+			sqlgraph.From(userattribute.Table, userattribute.FieldID, selector),
+			sqlgraph.To(user.Table, user.FieldID),
+			sqlgraph.Edge(sqlgraph.M2O, true, userattribute.UserTable, userattribute.UserColumn),
+		)
+		fromU = sqlgraph.SetNeighbors(uaq.driver.Dialect(), step)
+		return fromU, nil
+	}
+	return query
+}
+
+// First returns the first UserAttribute entity from the query.
+// Returns a *NotFoundError when no UserAttribute was found.
+func (uaq *UserAttributeQuery) First(ctx context.Context) (*UserAttribute, error) {
+	nodes, err := uaq.Limit(1).All(setContextOp(ctx, uaq.ctx, ent.OpQueryFirst))
+	if err != nil {
+		return nil, err
+	}
+	if len(nodes) == 0 {
+		return nil, &NotFoundError{userattribute.Label}
+	}
+	return nodes[0], nil
+}
+
+// FirstX is like First, but panics if an error occurs.
+func (uaq *UserAttributeQuery) FirstX(ctx context.Context) *UserAttribute {
+	node, err := uaq.First(ctx)
+	if err != nil && !IsNotFound(err) {
+		panic(err)
+	}
+	return node
+}
+
+// FirstID returns the first UserAttribute ID from the query.
+// Returns a *NotFoundError when no UserAttribute ID was found.
+func (uaq *UserAttributeQuery) FirstID(ctx context.Context) (id int, err error) {
+	var ids []int
+	if ids, err = uaq.Limit(1).IDs(setContextOp(ctx, uaq.ctx, ent.OpQueryFirstID)); err != nil {
+		return
+	}
+	if len(ids) == 0 {
+		err = &NotFoundError{userattribute.Label}
+		return
+	}
+	return ids[0], nil
+}
+
+// FirstIDX is like FirstID, but panics if an error occurs.
+func (uaq *UserAttributeQuery) FirstIDX(ctx context.Context) int {
+	id, err := uaq.FirstID(ctx)
+	if err != nil && !IsNotFound(err) {
+		panic(err)
+	}
+	return id
+}
+
+// Only returns a single UserAttribute entity found by the query, ensuring it only returns one.
+// Returns a *NotSingularError when more than one UserAttribute entity is found.
+// Returns a *NotFoundError when no UserAttribute entities are found.
+func (uaq *UserAttributeQuery) Only(ctx context.Context) (*UserAttribute, error) {
+	nodes, err := uaq.Limit(2).All(setContextOp(ctx, uaq.ctx, ent.OpQueryOnly))
+	if err != nil {
+		return nil, err
+	}
+	switch len(nodes) {
+	case 1:
+		return nodes[0], nil
+	case 0:
+		return nil, &NotFoundError{userattribute.Label}
+	default:
+		return nil, &NotSingularError{userattribute.Label}
+	}
+}
+
+// OnlyX is like Only, but panics if an error occurs.
+func (uaq *UserAttributeQuery) OnlyX(ctx context.Context) *UserAttribute {
+	node, err := uaq.Only(ctx)
+	if err != nil {
+		panic(err)
+	}
+	return node
+}
+
+// OnlyID is like Only, but returns the only UserAttribute ID in the query.
+// Returns a *NotSingularError when more than one UserAttribute ID is found.
+// Returns a *NotFoundError when no entities are found.
+func (uaq *UserAttributeQuery) OnlyID(ctx context.Context) (id int, err error) {
+	var ids []int
+	if ids, err = uaq.Limit(2).IDs(setContextOp(ctx, uaq.ctx, ent.OpQueryOnlyID)); err != nil {
+		return
+	}
+	switch len(ids) {
+	case 1:
+		id = ids[0]
+	case 0:
+		err = &NotFoundError{userattribute.Label}
+	default:
+		err = &NotSingularError{userattribute.Label}
+	}
+	return
+}
+
+// OnlyIDX is like OnlyID, but panics if an error occurs.
+func (uaq *UserAttributeQuery) OnlyIDX(ctx context.Context) int {
+	id, err := uaq.OnlyID(ctx)
+	if err != nil {
+		panic(err)
+	}
+	return id
+}
+
+// All executes the query and returns a list of UserAttributes.
+func (uaq *UserAttributeQuery) All(ctx context.Context) ([]*UserAttribute, error) {
+	ctx = setContextOp(ctx, uaq.ctx, ent.OpQueryAll)
+	if err := uaq.prepareQuery(ctx); err != nil {
+		return nil, err
+	}
+	qr := querierAll[[]*UserAttribute, *UserAttributeQuery]()
+	return withInterceptors[[]*UserAttribute](ctx, uaq, qr, uaq.inters)
+}
+
+// AllX is like All, but panics if an error occurs.
+func (uaq *UserAttributeQuery) AllX(ctx context.Context) []*UserAttribute {
+	nodes, err := uaq.All(ctx)
+	if err != nil {
+		panic(err)
+	}
+	return nodes
+}
+
+// IDs executes the query and returns a list of UserAttribute IDs.
+func (uaq *UserAttributeQuery) IDs(ctx context.Context) (ids []int, err error) {
+	if uaq.ctx.Unique == nil && uaq.path != nil {
+		uaq.Unique(true)
+	}
+	ctx = setContextOp(ctx, uaq.ctx, ent.OpQueryIDs)
+	if err = uaq.Select(userattribute.FieldID).Scan(ctx, &ids); err != nil {
+		return nil, err
+	}
+	return ids, nil
+}
+
+// IDsX is like IDs, but panics if an error occurs.
+func (uaq *UserAttributeQuery) IDsX(ctx context.Context) []int {
+	ids, err := uaq.IDs(ctx)
+	if err != nil {
+		panic(err)
+	}
+	return ids
+}
+
+// Count returns the count of the given query.
+func (uaq *UserAttributeQuery) Count(ctx context.Context) (int, error) {
+	ctx = setContextOp(ctx, uaq.ctx, ent.OpQueryCount)
+	if err := uaq.prepareQuery(ctx); err != nil {
+		return 0, err
+	}
+	return withInterceptors[int](ctx, uaq, querierCount[*UserAttributeQuery](), uaq.inters)
+}
+
+// CountX is like Count, but panics if an error occurs.
+func (uaq *UserAttributeQuery) CountX(ctx context.Context) int {
+	count, err := uaq.Count(ctx)
+	if err != nil {
+		panic(err)
+	}
+	return count
+}
+
+// Exist returns true if the query has elements in the graph.
+func (uaq *UserAttributeQuery) Exist(ctx context.Context) (bool, error) {
+	ctx = setContextOp(ctx, uaq.ctx, ent.OpQueryExist)
+	switch _, err := uaq.FirstID(ctx); {
+	case IsNotFound(err):
+		return false, nil
+	case err != nil:
+		return false, fmt.Errorf("ent: check existence: %w", err)
+	default:
+		return true, nil
+	}
+}
+
+// ExistX is like Exist, but panics if an error occurs.
+func (uaq *UserAttributeQuery) ExistX(ctx context.Context) bool {
+	exist, err := uaq.Exist(ctx)
+	if err != nil {
+		panic(err)
+	}
+	return exist
+}
+
+// Clone returns a duplicate of the UserAttributeQuery builder, including all associated steps. It can be
+// used to prepare common query builders and use them differently after the clone is made.
+func (uaq *UserAttributeQuery) Clone() *UserAttributeQuery {
+	if uaq == nil {
+		return nil
+	}
+	return &UserAttributeQuery{
+		config:     uaq.config,
+		ctx:        uaq.ctx.Clone(),
+		order:      append([]userattribute.OrderOption{}, uaq.order...),
+		inters:     append([]Interceptor{}, uaq.inters...),
+		predicates: append([]predicate.UserAttribute{}, uaq.predicates...),
+		withUser:   uaq.withUser.Clone(),
+		// clone intermediate query.
+		sql:  uaq.sql.Clone(),
+		path: uaq.path,
+	}
+}
+
+// WithUser tells the query-builder to eager-load the nodes that are connected to
+// the "user" edge. The optional arguments are used to configure the query builder of the edge.
+func (uaq *UserAttributeQuery) WithUser(opts ...func(*UserQuery)) *UserAttributeQuery {
+	query := (&UserClient{config: uaq.config}).Query()
+	for _, opt := range opts {
+		opt(query)
+	}
+	uaq.withUser = query
+	return uaq
+}
+
+// GroupBy is used to group vertices by one or more fields/columns.
+// It is often used with aggregate functions, like: count, max, mean, min, sum.
+//
+// Example:
+//
+//	var v []struct {
+//		Name string `json:"name,omitempty"`
+//		Count int `json:"count,omitempty"`
+//	}
+//
+//	client.UserAttribute.Query().
+//		GroupBy(userattribute.FieldName).
+//		Aggregate(ent.Count()).
+//		Scan(ctx, &v)
+func (uaq *UserAttributeQuery) GroupBy(field string, fields ...string) *UserAttributeGroupBy {
+	uaq.ctx.Fields = append([]string{field}, fields...)
+	grbuild := &UserAttributeGroupBy{build: uaq}
+	grbuild.flds = &uaq.ctx.Fields
+	grbuild.label = userattribute.Label
+	grbuild.scan = grbuild.Scan
+	return grbuild
+}
+
+// Select allows the selection one or more fields/columns for the given query,
+// instead of selecting all fields in the entity.
+//
+// Example:
+//
+//	var v []struct {
+//		Name string `json:"name,omitempty"`
+//	}
+//
+//	client.UserAttribute.Query().
+//		Select(userattribute.FieldName).
+//		Scan(ctx, &v)
+func (uaq *UserAttributeQuery) Select(fields ...string) *UserAttributeSelect {
+	uaq.ctx.Fields = append(uaq.ctx.Fields, fields...)
+	sbuild := &UserAttributeSelect{UserAttributeQuery: uaq}
+	sbuild.label = userattribute.Label
+	sbuild.flds, sbuild.scan = &uaq.ctx.Fields, sbuild.Scan
+	return sbuild
+}
+
+// Aggregate returns a UserAttributeSelect configured with the given aggregations.
+func (uaq *UserAttributeQuery) Aggregate(fns ...AggregateFunc) *UserAttributeSelect {
+	return uaq.Select().Aggregate(fns...)
+}
+
+func (uaq *UserAttributeQuery) prepareQuery(ctx context.Context) error {
+	for _, inter := range uaq.inters {
+		if inter == nil {
+			return fmt.Errorf("ent: uninitialized interceptor (forgotten import ent/runtime?)")
+		}
+		if trv, ok := inter.(Traverser); ok {
+			if err := trv.Traverse(ctx, uaq); err != nil {
+				return err
+			}
+		}
+	}
+	for _, f := range uaq.ctx.Fields {
+		if !userattribute.ValidColumn(f) {
+			return &ValidationError{Name: f, err: fmt.Errorf("ent: invalid field %q for query", f)}
+		}
+	}
+	if uaq.path != nil {
+		prev, err := uaq.path(ctx)
+		if err != nil {
+			return err
+		}
+		uaq.sql = prev
+	}
+	return nil
+}
+
+func (uaq *UserAttributeQuery) sqlAll(ctx context.Context, hooks ...queryHook) ([]*UserAttribute, error) {
+	var (
+		nodes       = []*UserAttribute{}
+		withFKs     = uaq.withFKs
+		_spec       = uaq.querySpec()
+		loadedTypes = [1]bool{
+			uaq.withUser != nil,
+		}
+	)
+	if uaq.withUser != nil {
+		withFKs = true
+	}
+	if withFKs {
+		_spec.Node.Columns = append(_spec.Node.Columns, userattribute.ForeignKeys...)
+	}
+	_spec.ScanValues = func(columns []string) ([]any, error) {
+		return (*UserAttribute).scanValues(nil, columns)
+	}
+	_spec.Assign = func(columns []string, values []any) error {
+		node := &UserAttribute{config: uaq.config}
+		nodes = append(nodes, node)
+		node.Edges.loadedTypes = loadedTypes
+		return node.assignValues(columns, values)
+	}
+	for i := range hooks {
+		hooks[i](ctx, _spec)
+	}
+	if err := sqlgraph.QueryNodes(ctx, uaq.driver, _spec); err != nil {
+		return nil, err
+	}
+	if len(nodes) == 0 {
+		return nodes, nil
+	}
+	if query := uaq.withUser; query != nil {
+		if err := uaq.loadUser(ctx, query, nodes, nil,
+			func(n *UserAttribute, e *User) { n.Edges.User = e }); err != nil {
+			return nil, err
+		}
+	}
+	return nodes, nil
+}
+
+func (uaq *UserAttributeQuery) loadUser(ctx context.Context, query *UserQuery, nodes []*UserAttribute, init func(*UserAttribute), assign func(*UserAttribute, *User)) error {
+	ids := make([]int, 0, len(nodes))
+	nodeids := make(map[int][]*UserAttribute)
+	for i := range nodes {
+		if nodes[i].user_attributes == nil {
+			continue
+		}
+		fk := *nodes[i].user_attributes
+		if _, ok := nodeids[fk]; !ok {
+			ids = append(ids, fk)
+		}
+		nodeids[fk] = append(nodeids[fk], nodes[i])
+	}
+	if len(ids) == 0 {
+		return nil
+	}
+	query.Where(user.IDIn(ids...))
+	neighbors, err := query.All(ctx)
+	if err != nil {
+		return err
+	}
+	for _, n := range neighbors {
+		nodes, ok := nodeids[n.ID]
+		if !ok {
+			return fmt.Errorf(`unexpected foreign-key "user_attributes" returned %v`, n.ID)
+		}
+		for i := range nodes {
+			assign(nodes[i], n)
+		}
+	}
+	return nil
+}
+
+func (uaq *UserAttributeQuery) sqlCount(ctx context.Context) (int, error) {
+	_spec := uaq.querySpec()
+	_spec.Node.Columns = uaq.ctx.Fields
+	if len(uaq.ctx.Fields) > 0 {
+		_spec.Unique = uaq.ctx.Unique != nil && *uaq.ctx.Unique
+	}
+	return sqlgraph.CountNodes(ctx, uaq.driver, _spec)
+}
+
+func (uaq *UserAttributeQuery) querySpec() *sqlgraph.QuerySpec {
+	_spec := sqlgraph.NewQuerySpec(userattribute.Table, userattribute.Columns, sqlgraph.NewFieldSpec(userattribute.FieldID, field.TypeInt))
+	_spec.From = uaq.sql
+	if unique := uaq.ctx.Unique; unique != nil {
+		_spec.Unique = *unique
+	} else if uaq.path != nil {
+		_spec.Unique = true
+	}
+	if fields := uaq.ctx.Fields; len(fields) > 0 {
+		_spec.Node.Columns = make([]string, 0, len(fields))
+		_spec.Node.Columns = append(_spec.Node.Columns, userattribute.FieldID)
+		for i := range fields {
+			if fields[i] != userattribute.FieldID {
+				_spec.Node.Columns = append(_spec.Node.Columns, fields[i])
+			}
+		}
+	}
+	if ps := uaq.predicates; len(ps) > 0 {
+		_spec.Predicate = func(selector *sql.Selector) {
+			for i := range ps {
+				ps[i](selector)
+			}
+		}
+	}
+	if limit := uaq.ctx.Limit; limit != nil {
+		_spec.Limit = *limit
+	}
+	if offset := uaq.ctx.Offset; offset != nil {
+		_spec.Offset = *offset
+	}
+	if ps := uaq.order; len(ps) > 0 {
+		_spec.Order = func(selector *sql.Selector) {
+			for i := range ps {
+				ps[i](selector)
+			}
+		}
+	}
+	return _spec
+}
+
+func (uaq *UserAttributeQuery) sqlQuery(ctx context.Context) *sql.Selector {
+	builder := sql.Dialect(uaq.driver.Dialect())
+	t1 := builder.Table(userattribute.Table)
+	columns := uaq.ctx.Fields
+	if len(columns) == 0 {
+		columns = userattribute.Columns
+	}
+	selector := builder.Select(t1.Columns(columns...)...).From(t1)
+	if uaq.sql != nil {
+		selector = uaq.sql
+		selector.Select(selector.Columns(columns...)...)
+	}
+	if uaq.ctx.Unique != nil && *uaq.ctx.Unique {
+		selector.Distinct()
+	}
+	for _, p := range uaq.predicates {
+		p(selector)
+	}
+	for _, p := range uaq.order {
+		p(selector)
+	}
+	if offset := uaq.ctx.Offset; offset != nil {
+		// limit is mandatory for offset clause. We start
+		// with default value, and override it below if needed.
+		selector.Offset(*offset).Limit(math.MaxInt32)
+	}
+	if limit := uaq.ctx.Limit; limit != nil {
+		selector.Limit(*limit)
+	}
+	return selector
+}
+
+// UserAttributeGroupBy is the group-by builder for UserAttribute entities.
+type UserAttributeGroupBy struct {
+	selector
+	build *UserAttributeQuery
+}
+
+// Aggregate adds the given aggregation functions to the group-by query.
+func (uagb *UserAttributeGroupBy) Aggregate(fns ...AggregateFunc) *UserAttributeGroupBy {
+	uagb.fns = append(uagb.fns, fns...)
+	return uagb
+}
+
+// Scan applies the selector query and scans the result into the given value.
+func (uagb *UserAttributeGroupBy) Scan(ctx context.Context, v any) error {
+	ctx = setContextOp(ctx, uagb.build.ctx, ent.OpQueryGroupBy)
+	if err := uagb.build.prepareQuery(ctx); err != nil {
+		return err
+	}
+	return scanWithInterceptors[*UserAttributeQuery, *UserAttributeGroupBy](ctx, uagb.build, uagb, uagb.build.inters, v)
+}
+
+func (uagb *UserAttributeGroupBy) sqlScan(ctx context.Context, root *UserAttributeQuery, v any) error {
+	selector := root.sqlQuery(ctx).Select()
+	aggregation := make([]string, 0, len(uagb.fns))
+	for _, fn := range uagb.fns {
+		aggregation = append(aggregation, fn(selector))
+	}
+	if len(selector.SelectedColumns()) == 0 {
+		columns := make([]string, 0, len(*uagb.flds)+len(uagb.fns))
+		for _, f := range *uagb.flds {
+			columns = append(columns, selector.C(f))
+		}
+		columns = append(columns, aggregation...)
+		selector.Select(columns...)
+	}
+	selector.GroupBy(selector.Columns(*uagb.flds...)...)
+	if err := selector.Err(); err != nil {
+		return err
+	}
+	rows := &sql.Rows{}
+	query, args := selector.Query()
+	if err := uagb.build.driver.Query(ctx, query, args, rows); err != nil {
+		return err
+	}
+	defer rows.Close()
+	return sql.ScanSlice(rows, v)
+}
+
+// UserAttributeSelect is the builder for selecting fields of UserAttribute entities.
+type UserAttributeSelect struct {
+	*UserAttributeQuery
+	selector
+}
+
+// Aggregate adds the given aggregation functions to the selector query.
+func (uas *UserAttributeSelect) Aggregate(fns ...AggregateFunc) *UserAttributeSelect {
+	uas.fns = append(uas.fns, fns...)
+	return uas
+}
+
+// Scan applies the selector query and scans the result into the given value.
+func (uas *UserAttributeSelect) Scan(ctx context.Context, v any) error {
+	ctx = setContextOp(ctx, uas.ctx, ent.OpQuerySelect)
+	if err := uas.prepareQuery(ctx); err != nil {
+		return err
+	}
+	return scanWithInterceptors[*UserAttributeQuery, *UserAttributeSelect](ctx, uas.UserAttributeQuery, uas, uas.inters, v)
+}
+
+func (uas *UserAttributeSelect) sqlScan(ctx context.Context, root *UserAttributeQuery, v any) error {
+	selector := root.sqlQuery(ctx)
+	aggregation := make([]string, 0, len(uas.fns))
+	for _, fn := range uas.fns {
+		aggregation = append(aggregation, fn(selector))
+	}
+	switch n := len(*uas.selector.flds); {
+	case n == 0 && len(aggregation) > 0:
+		selector.Select(aggregation...)
+	case n != 0 && len(aggregation) > 0:
+		selector.AppendSelect(aggregation...)
+	}
+	rows := &sql.Rows{}
+	query, args := selector.Query()
+	if err := uas.driver.Query(ctx, query, args, rows); err != nil {
+		return err
+	}
+	defer rows.Close()
+	return sql.ScanSlice(rows, v)
+}
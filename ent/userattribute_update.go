@@ -0,0 +1,420 @@
+// Code generated by ent, DO NOT EDIT.
+
+package ent
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"entgo.io/ent/dialect/sql"
+	"entgo.io/ent/dialect/sql/sqlgraph"
+	"entgo.io/ent/schema/field"
+	"github.com/smxlong/users/ent/predicate"
+	"github.com/smxlong/users/ent/user"
+	"github.com/smxlong/users/ent/userattribute"
+)
+
+// UserAttributeUpdate is the builder for updating UserAttribute entities.
+type UserAttributeUpdate struct {
+	config
+	hooks    []Hook
+	mutation *UserAttributeMutation
+}
+
+// Where appends a list predicates to the UserAttributeUpdate builder.
+func (uau *UserAttributeUpdate) Where(ps ...predicate.UserAttribute) *UserAttributeUpdate {
+	uau.mutation.Where(ps...)
+	return uau
+}
+
+// SetName sets the "name" field.
+func (uau *UserAttributeUpdate) SetName(s string) *UserAttributeUpdate {
+	uau.mutation.SetName(s)
+	return uau
+}
+
+// SetNillableName sets the "name" field if the given value is not nil.
+func (uau *UserAttributeUpdate) SetNillableName(s *string) *UserAttributeUpdate {
+	if s != nil {
+		uau.SetName(*s)
+	}
+	return uau
+}
+
+// SetValue sets the "value" field.
+func (uau *UserAttributeUpdate) SetValue(s string) *UserAttributeUpdate {
+	uau.mutation.SetValue(s)
+	return uau
+}
+
+// SetNillableValue sets the "value" field if the given value is not nil.
+func (uau *UserAttributeUpdate) SetNillableValue(s *string) *UserAttributeUpdate {
+	if s != nil {
+		uau.SetValue(*s)
+	}
+	return uau
+}
+
+// SetUniqueValue sets the "unique_value" field.
+func (uau *UserAttributeUpdate) SetUniqueValue(s string) *UserAttributeUpdate {
+	uau.mutation.SetUniqueValue(s)
+	return uau
+}
+
+// SetNillableUniqueValue sets the "unique_value" field if the given value is not nil.
+func (uau *UserAttributeUpdate) SetNillableUniqueValue(s *string) *UserAttributeUpdate {
+	if s != nil {
+		uau.SetUniqueValue(*s)
+	}
+	return uau
+}
+
+// ClearUniqueValue clears the value of the "unique_value" field.
+func (uau *UserAttributeUpdate) ClearUniqueValue() *UserAttributeUpdate {
+	uau.mutation.ClearUniqueValue()
+	return uau
+}
+
+// SetUserID sets the "user" edge to the User entity by ID.
+func (uau *UserAttributeUpdate) SetUserID(id int) *UserAttributeUpdate {
+	uau.mutation.SetUserID(id)
+	return uau
+}
+
+// SetUser sets the "user" edge to the User entity.
+func (uau *UserAttributeUpdate) SetUser(u *User) *UserAttributeUpdate {
+	return uau.SetUserID(u.ID)
+}
+
+// Mutation returns the UserAttributeMutation object of the builder.
+func (uau *UserAttributeUpdate) Mutation() *UserAttributeMutation {
+	return uau.mutation
+}
+
+// ClearUser clears the "user" edge to the User entity.
+func (uau *UserAttributeUpdate) ClearUser() *UserAttributeUpdate {
+	uau.mutation.ClearUser()
+	return uau
+}
+
+// Save executes the query and returns the number of nodes affected by the update operation.
+func (uau *UserAttributeUpdate) Save(ctx context.Context) (int, error) {
+	return withHooks(ctx, uau.sqlSave, uau.mutation, uau.hooks)
+}
+
+// SaveX is like Save, but panics if an error occurs.
+func (uau *UserAttributeUpdate) SaveX(ctx context.Context) int {
+	affected, err := uau.Save(ctx)
+	if err != nil {
+		panic(err)
+	}
+	return affected
+}
+
+// Exec executes the query.
+func (uau *UserAttributeUpdate) Exec(ctx context.Context) error {
+	_, err := uau.Save(ctx)
+	return err
+}
+
+// ExecX is like Exec, but panics if an error occurs.
+func (uau *UserAttributeUpdate) ExecX(ctx context.Context) {
+	if err := uau.Exec(ctx); err != nil {
+		panic(err)
+	}
+}
+
+// check runs all checks and user-defined validators on the builder.
+func (uau *UserAttributeUpdate) check() error {
+	if v, ok := uau.mutation.Name(); ok {
+		if err := userattribute.NameValidator(v); err != nil {
+			return &ValidationError{Name: "name", err: fmt.Errorf(`ent: validator failed for field "UserAttribute.name": %w`, err)}
+		}
+	}
+	if uau.mutation.UserCleared() && len(uau.mutation.UserIDs()) > 0 {
+		return errors.New(`ent: clearing a required unique edge "UserAttribute.user"`)
+	}
+	return nil
+}
+
+func (uau *UserAttributeUpdate) sqlSave(ctx context.Context) (n int, err error) {
+	if err := uau.check(); err != nil {
+		return n, err
+	}
+	_spec := sqlgraph.NewUpdateSpec(userattribute.Table, userattribute.Columns, sqlgraph.NewFieldSpec(userattribute.FieldID, field.TypeInt))
+	if ps := uau.mutation.predicates; len(ps) > 0 {
+		_spec.Predicate = func(selector *sql.Selector) {
+			for i := range ps {
+				ps[i](selector)
+			}
+		}
+	}
+	if value, ok := uau.mutation.Name(); ok {
+		_spec.SetField(userattribute.FieldName, field.TypeString, value)
+	}
+	if value, ok := uau.mutation.Value(); ok {
+		_spec.SetField(userattribute.FieldValue, field.TypeString, value)
+	}
+	if value, ok := uau.mutation.UniqueValue(); ok {
+		_spec.SetField(userattribute.FieldUniqueValue, field.TypeString, value)
+	}
+	if uau.mutation.UniqueValueCleared() {
+		_spec.ClearField(userattribute.FieldUniqueValue, field.TypeString)
+	}
+	if uau.mutation.UserCleared() {
+		edge := &sqlgraph.EdgeSpec{
+			Rel:     sqlgraph.M2O,
+			Inverse: true,
+			Table:   userattribute.UserTable,
+			Columns: []string{userattribute.UserColumn},
+			Bidi:    false,
+			Target: &sqlgraph.EdgeTarget{
+				IDSpec: sqlgraph.NewFieldSpec(user.FieldID, field.TypeInt),
+			},
+		}
+		_spec.Edges.Clear = append(_spec.Edges.Clear, edge)
+	}
+	if nodes := uau.mutation.UserIDs(); len(nodes) > 0 {
+		edge := &sqlgraph.EdgeSpec{
+			Rel:     sqlgraph.M2O,
+			Inverse: true,
+			Table:   userattribute.UserTable,
+			Columns: []string{userattribute.UserColumn},
+			Bidi:    false,
+			Target: &sqlgraph.EdgeTarget{
+				IDSpec: sqlgraph.NewFieldSpec(user.FieldID, field.TypeInt),
+			},
+		}
+		for _, k := range nodes {
+			edge.Target.Nodes = append(edge.Target.Nodes, k)
+		}
+		_spec.Edges.Add = append(_spec.Edges.Add, edge)
+	}
+	if n, err = sqlgraph.UpdateNodes(ctx, uau.driver, _spec); err != nil {
+		if _, ok := err.(*sqlgraph.NotFoundError); ok {
+			err = &NotFoundError{userattribute.Label}
+		} else if sqlgraph.IsConstraintError(err) {
+			err = &ConstraintError{msg: err.Error(), wrap: err}
+		}
+		return 0, err
+	}
+	uau.mutation.done = true
+	return n, nil
+}
+
+// UserAttributeUpdateOne is the builder for updating a single UserAttribute entity.
+type UserAttributeUpdateOne struct {
+	config
+	fields   []string
+	hooks    []Hook
+	mutation *UserAttributeMutation
+}
+
+// SetName sets the "name" field.
+func (uauo *UserAttributeUpdateOne) SetName(s string) *UserAttributeUpdateOne {
+	uauo.mutation.SetName(s)
+	return uauo
+}
+
+// SetNillableName sets the "name" field if the given value is not nil.
+func (uauo *UserAttributeUpdateOne) SetNillableName(s *string) *UserAttributeUpdateOne {
+	if s != nil {
+		uauo.SetName(*s)
+	}
+	return uauo
+}
+
+// SetValue sets the "value" field.
+func (uauo *UserAttributeUpdateOne) SetValue(s string) *UserAttributeUpdateOne {
+	uauo.mutation.SetValue(s)
+	return uauo
+}
+
+// SetNillableValue sets the "value" field if the given value is not nil.
+func (uauo *UserAttributeUpdateOne) SetNillableValue(s *string) *UserAttributeUpdateOne {
+	if s != nil {
+		uauo.SetValue(*s)
+	}
+	return uauo
+}
+
+// SetUniqueValue sets the "unique_value" field.
+func (uauo *UserAttributeUpdateOne) SetUniqueValue(s string) *UserAttributeUpdateOne {
+	uauo.mutation.SetUniqueValue(s)
+	return uauo
+}
+
+// SetNillableUniqueValue sets the "unique_value" field if the given value is not nil.
+func (uauo *UserAttributeUpdateOne) SetNillableUniqueValue(s *string) *UserAttributeUpdateOne {
+	if s != nil {
+		uauo.SetUniqueValue(*s)
+	}
+	return uauo
+}
+
+// ClearUniqueValue clears the value of the "unique_value" field.
+func (uauo *UserAttributeUpdateOne) ClearUniqueValue() *UserAttributeUpdateOne {
+	uauo.mutation.ClearUniqueValue()
+	return uauo
+}
+
+// SetUserID sets the "user" edge to the User entity by ID.
+func (uauo *UserAttributeUpdateOne) SetUserID(id int) *UserAttributeUpdateOne {
+	uauo.mutation.SetUserID(id)
+	return uauo
+}
+
+// SetUser sets the "user" edge to the User entity.
+func (uauo *UserAttributeUpdateOne) SetUser(u *User) *UserAttributeUpdateOne {
+	return uauo.SetUserID(u.ID)
+}
+
+// Mutation returns the UserAttributeMutation object of the builder.
+func (uauo *UserAttributeUpdateOne) Mutation() *UserAttributeMutation {
+	return uauo.mutation
+}
+
+// ClearUser clears the "user" edge to the User entity.
+func (uauo *UserAttributeUpdateOne) ClearUser() *UserAttributeUpdateOne {
+	uauo.mutation.ClearUser()
+	return uauo
+}
+
+// Where appends a list predicates to the UserAttributeUpdate builder.
+func (uauo *UserAttributeUpdateOne) Where(ps ...predicate.UserAttribute) *UserAttributeUpdateOne {
+	uauo.mutation.Where(ps...)
+	return uauo
+}
+
+// Select allows selecting one or more fields (columns) of the returned entity.
+// The default is selecting all fields defined in the entity schema.
+func (uauo *UserAttributeUpdateOne) Select(field string, fields ...string) *UserAttributeUpdateOne {
+	uauo.fields = append([]string{field}, fields...)
+	return uauo
+}
+
+// Save executes the query and returns the updated UserAttribute entity.
+func (uauo *UserAttributeUpdateOne) Save(ctx context.Context) (*UserAttribute, error) {
+	return withHooks(ctx, uauo.sqlSave, uauo.mutation, uauo.hooks)
+}
+
+// SaveX is like Save, but panics if an error occurs.
+func (uauo *UserAttributeUpdateOne) SaveX(ctx context.Context) *UserAttribute {
+	node, err := uauo.Save(ctx)
+	if err != nil {
+		panic(err)
+	}
+	return node
+}
+
+// Exec executes the query on the entity.
+func (uauo *UserAttributeUpdateOne) Exec(ctx context.Context) error {
+	_, err := uauo.Save(ctx)
+	return err
+}
+
+// ExecX is like Exec, but panics if an error occurs.
+func (uauo *UserAttributeUpdateOne) ExecX(ctx context.Context) {
+	if err := uauo.Exec(ctx); err != nil {
+		panic(err)
+	}
+}
+
+// check runs all checks and user-defined validators on the builder.
+func (uauo *UserAttributeUpdateOne) check() error {
+	if v, ok := uauo.mutation.Name(); ok {
+		if err := userattribute.NameValidator(v); err != nil {
+			return &ValidationError{Name: "name", err: fmt.Errorf(`ent: validator failed for field "UserAttribute.name": %w`, err)}
+		}
+	}
+	if uauo.mutation.UserCleared() && len(uauo.mutation.UserIDs()) > 0 {
+		return errors.New(`ent: clearing a required unique edge "UserAttribute.user"`)
+	}
+	return nil
+}
+
+func (uauo *UserAttributeUpdateOne) sqlSave(ctx context.Context) (_node *UserAttribute, err error) {
+	if err := uauo.check(); err != nil {
+		return _node, err
+	}
+	_spec := sqlgraph.NewUpdateSpec(userattribute.Table, userattribute.Columns, sqlgraph.NewFieldSpec(userattribute.FieldID, field.TypeInt))
+	id, ok := uauo.mutation.ID()
+	if !ok {
+		return nil, &ValidationError{Name: "id", err: errors.New(`ent: missing "UserAttribute.id" for update`)}
+	}
+	_spec.Node.ID.Value = id
+	if fields := uauo.fields; len(fields) > 0 {
+		_spec.Node.Columns = make([]string, 0, len(fields))
+		_spec.Node.Columns = append(_spec.Node.Columns, userattribute.FieldID)
+		for _, f := range fields {
+			if !userattribute.ValidColumn(f) {
+				return nil, &ValidationError{Name: f, err: fmt.Errorf("ent: invalid field %q for query", f)}
+			}
+			if f != userattribute.FieldID {
+				_spec.Node.Columns = append(_spec.Node.Columns, f)
+			}
+		}
+	}
+	if ps := uauo.mutation.predicates; len(ps) > 0 {
+		_spec.Predicate = func(selector *sql.Selector) {
+			for i := range ps {
+				ps[i](selector)
+			}
+		}
+	}
+	if value, ok := uauo.mutation.Name(); ok {
+		_spec.SetField(userattribute.FieldName, field.TypeString, value)
+	}
+	if value, ok := uauo.mutation.Value(); ok {
+		_spec.SetField(userattribute.FieldValue, field.TypeString, value)
+	}
+	if value, ok := uauo.mutation.UniqueValue(); ok {
+		_spec.SetField(userattribute.FieldUniqueValue, field.TypeString, value)
+	}
+	if uauo.mutation.UniqueValueCleared() {
+		_spec.ClearField(userattribute.FieldUniqueValue, field.TypeString)
+	}
+	if uauo.mutation.UserCleared() {
+		edge := &sqlgraph.EdgeSpec{
+			Rel:     sqlgraph.M2O,
+			Inverse: true,
+			Table:   userattribute.UserTable,
+			Columns: []string{userattribute.UserColumn},
+			Bidi:    false,
+			Target: &sqlgraph.EdgeTarget{
+				IDSpec: sqlgraph.NewFieldSpec(user.FieldID, field.TypeInt),
+			},
+		}
+		_spec.Edges.Clear = append(_spec.Edges.Clear, edge)
+	}
+	if nodes := uauo.mutation.UserIDs(); len(nodes) > 0 {
+		edge := &sqlgraph.EdgeSpec{
+			Rel:     sqlgraph.M2O,
+			Inverse: true,
+			Table:   userattribute.UserTable,
+			Columns: []string{userattribute.UserColumn},
+			Bidi:    false,
+			Target: &sqlgraph.EdgeTarget{
+				IDSpec: sqlgraph.NewFieldSpec(user.FieldID, field.TypeInt),
+			},
+		}
+		for _, k := range nodes {
+			edge.Target.Nodes = append(edge.Target.Nodes, k)
+		}
+		_spec.Edges.Add = append(_spec.Edges.Add, edge)
+	}
+	_node = &UserAttribute{config: uauo.config}
+	_spec.Assign = _node.assignValues
+	_spec.ScanValues = _node.scanValues
+	if err = sqlgraph.UpdateNode(ctx, uauo.driver, _spec); err != nil {
+		if _, ok := err.(*sqlgraph.NotFoundError); ok {
+			err = &NotFoundError{userattribute.Label}
+		} else if sqlgraph.IsConstraintError(err) {
+			err = &ConstraintError{msg: err.Error(), wrap: err}
+		}
+		return nil, err
+	}
+	uauo.mutation.done = true
+	return _node, nil
+}
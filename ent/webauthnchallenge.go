@@ -0,0 +1,116 @@
+// Code generated by ent, DO NOT EDIT.
+
+package ent
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"entgo.io/ent"
+	"entgo.io/ent/dialect/sql"
+	"github.com/smxlong/users/ent/webauthnchallenge"
+)
+
+// WebAuthnChallenge is the model entity for the WebAuthnChallenge schema.
+type WebAuthnChallenge struct {
+	config `json:"-"`
+	// ID of the ent.
+	ID int `json:"id,omitempty"`
+	// ChallengeHash holds the value of the "challenge_hash" field.
+	ChallengeHash string `json:"-"`
+	// ExpiresAt holds the value of the "expires_at" field.
+	ExpiresAt    time.Time `json:"expires_at,omitempty"`
+	selectValues sql.SelectValues
+}
+
+// scanValues returns the types for scanning values from sql.Rows.
+func (*WebAuthnChallenge) scanValues(columns []string) ([]any, error) {
+	values := make([]any, len(columns))
+	for i := range columns {
+		switch columns[i] {
+		case webauthnchallenge.FieldID:
+			values[i] = new(sql.NullInt64)
+		case webauthnchallenge.FieldChallengeHash:
+			values[i] = new(sql.NullString)
+		case webauthnchallenge.FieldExpiresAt:
+			values[i] = new(sql.NullTime)
+		default:
+			values[i] = new(sql.UnknownType)
+		}
+	}
+	return values, nil
+}
+
+// assignValues assigns the values that were returned from sql.Rows (after scanning)
+// to the WebAuthnChallenge fields.
+func (wac *WebAuthnChallenge) assignValues(columns []string, values []any) error {
+	if m, n := len(values), len(columns); m < n {
+		return fmt.Errorf("mismatch number of scan values: %d != %d", m, n)
+	}
+	for i := range columns {
+		switch columns[i] {
+		case webauthnchallenge.FieldID:
+			value, ok := values[i].(*sql.NullInt64)
+			if !ok {
+				return fmt.Errorf("unexpected type %T for field id", value)
+			}
+			wac.ID = int(value.Int64)
+		case webauthnchallenge.FieldChallengeHash:
+			if value, ok := values[i].(*sql.NullString); !ok {
+				return fmt.Errorf("unexpected type %T for field challenge_hash", values[i])
+			} else if value.Valid {
+				wac.ChallengeHash = value.String
+			}
+		case webauthnchallenge.FieldExpiresAt:
+			if value, ok := values[i].(*sql.NullTime); !ok {
+				return fmt.Errorf("unexpected type %T for field expires_at", values[i])
+			} else if value.Valid {
+				wac.ExpiresAt = value.Time
+			}
+		default:
+			wac.selectValues.Set(columns[i], values[i])
+		}
+	}
+	return nil
+}
+
+// Value returns the ent.Value that was dynamically selected and assigned to the WebAuthnChallenge.
+// This includes values selected through modifiers, order, etc.
+func (wac *WebAuthnChallenge) Value(name string) (ent.Value, error) {
+	return wac.selectValues.Get(name)
+}
+
+// Update returns a builder for updating this WebAuthnChallenge.
+// Note that you need to call WebAuthnChallenge.Unwrap() before calling this method if this WebAuthnChallenge
+// was returned from a transaction, and the transaction was committed or rolled back.
+func (wac *WebAuthnChallenge) Update() *WebAuthnChallengeUpdateOne {
+	return NewWebAuthnChallengeClient(wac.config).UpdateOne(wac)
+}
+
+// Unwrap unwraps the WebAuthnChallenge entity that was returned from a transaction after it was closed,
+// so that all future queries will be executed through the driver which created the transaction.
+func (wac *WebAuthnChallenge) Unwrap() *WebAuthnChallenge {
+	_tx, ok := wac.config.driver.(*txDriver)
+	if !ok {
+		panic("ent: WebAuthnChallenge is not a transactional entity")
+	}
+	wac.config.driver = _tx.drv
+	return wac
+}
+
+// String implements the fmt.Stringer.
+func (wac *WebAuthnChallenge) String() string {
+	var builder strings.Builder
+	builder.WriteString("WebAuthnChallenge(")
+	builder.WriteString(fmt.Sprintf("id=%v, ", wac.ID))
+	builder.WriteString("challenge_hash=<sensitive>")
+	builder.WriteString(", ")
+	builder.WriteString("expires_at=")
+	builder.WriteString(wac.ExpiresAt.Format(time.ANSIC))
+	builder.WriteByte(')')
+	return builder.String()
+}
+
+// WebAuthnChallenges is a parsable slice of WebAuthnChallenge.
+type WebAuthnChallenges []*WebAuthnChallenge
@@ -0,0 +1,60 @@
+// Code generated by ent, DO NOT EDIT.
+
+package webauthnchallenge
+
+import (
+	"entgo.io/ent/dialect/sql"
+)
+
+const (
+	// Label holds the string label denoting the webauthnchallenge type in the database.
+	Label = "web_authn_challenge"
+	// FieldID holds the string denoting the id field in the database.
+	FieldID = "id"
+	// FieldChallengeHash holds the string denoting the challenge_hash field in the database.
+	FieldChallengeHash = "challenge_hash"
+	// FieldExpiresAt holds the string denoting the expires_at field in the database.
+	FieldExpiresAt = "expires_at"
+	// Table holds the table name of the webauthnchallenge in the database.
+	Table = "web_authn_challenges"
+)
+
+// Columns holds all SQL columns for webauthnchallenge fields.
+var Columns = []string{
+	FieldID,
+	FieldChallengeHash,
+	FieldExpiresAt,
+}
+
+// ValidColumn reports if the column name is valid (part of the table columns).
+func ValidColumn(column string) bool {
+	for i := range Columns {
+		if column == Columns[i] {
+			return true
+		}
+	}
+	return false
+}
+
+var (
+	// ChallengeHashValidator is a validator for the "challenge_hash" field. It is called by the builders before save.
+	ChallengeHashValidator func(string) error
+)
+
+// OrderOption defines the ordering options for the WebAuthnChallenge queries.
+type OrderOption func(*sql.Selector)
+
+// ByID orders the results by the id field.
+func ByID(opts ...sql.OrderTermOption) OrderOption {
+	return sql.OrderByField(FieldID, opts...).ToFunc()
+}
+
+// ByChallengeHash orders the results by the challenge_hash field.
+func ByChallengeHash(opts ...sql.OrderTermOption) OrderOption {
+	return sql.OrderByField(FieldChallengeHash, opts...).ToFunc()
+}
+
+// ByExpiresAt orders the results by the expires_at field.
+func ByExpiresAt(opts ...sql.OrderTermOption) OrderOption {
+	return sql.OrderByField(FieldExpiresAt, opts...).ToFunc()
+}
@@ -0,0 +1,185 @@
+// Code generated by ent, DO NOT EDIT.
+
+package webauthnchallenge
+
+import (
+	"time"
+
+	"entgo.io/ent/dialect/sql"
+	"github.com/smxlong/users/ent/predicate"
+)
+
+// ID filters vertices based on their ID field.
+func ID(id int) predicate.WebAuthnChallenge {
+	return predicate.WebAuthnChallenge(sql.FieldEQ(FieldID, id))
+}
+
+// IDEQ applies the EQ predicate on the ID field.
+func IDEQ(id int) predicate.WebAuthnChallenge {
+	return predicate.WebAuthnChallenge(sql.FieldEQ(FieldID, id))
+}
+
+// IDNEQ applies the NEQ predicate on the ID field.
+func IDNEQ(id int) predicate.WebAuthnChallenge {
+	return predicate.WebAuthnChallenge(sql.FieldNEQ(FieldID, id))
+}
+
+// IDIn applies the In predicate on the ID field.
+func IDIn(ids ...int) predicate.WebAuthnChallenge {
+	return predicate.WebAuthnChallenge(sql.FieldIn(FieldID, ids...))
+}
+
+// IDNotIn applies the NotIn predicate on the ID field.
+func IDNotIn(ids ...int) predicate.WebAuthnChallenge {
+	return predicate.WebAuthnChallenge(sql.FieldNotIn(FieldID, ids...))
+}
+
+// IDGT applies the GT predicate on the ID field.
+func IDGT(id int) predicate.WebAuthnChallenge {
+	return predicate.WebAuthnChallenge(sql.FieldGT(FieldID, id))
+}
+
+// IDGTE applies the GTE predicate on the ID field.
+func IDGTE(id int) predicate.WebAuthnChallenge {
+	return predicate.WebAuthnChallenge(sql.FieldGTE(FieldID, id))
+}
+
+// IDLT applies the LT predicate on the ID field.
+func IDLT(id int) predicate.WebAuthnChallenge {
+	return predicate.WebAuthnChallenge(sql.FieldLT(FieldID, id))
+}
+
+// IDLTE applies the LTE predicate on the ID field.
+func IDLTE(id int) predicate.WebAuthnChallenge {
+	return predicate.WebAuthnChallenge(sql.FieldLTE(FieldID, id))
+}
+
+// ChallengeHash applies equality check predicate on the "challenge_hash" field. It's identical to ChallengeHashEQ.
+func ChallengeHash(v string) predicate.WebAuthnChallenge {
+	return predicate.WebAuthnChallenge(sql.FieldEQ(FieldChallengeHash, v))
+}
+
+// ExpiresAt applies equality check predicate on the "expires_at" field. It's identical to ExpiresAtEQ.
+func ExpiresAt(v time.Time) predicate.WebAuthnChallenge {
+	return predicate.WebAuthnChallenge(sql.FieldEQ(FieldExpiresAt, v))
+}
+
+// ChallengeHashEQ applies the EQ predicate on the "challenge_hash" field.
+func ChallengeHashEQ(v string) predicate.WebAuthnChallenge {
+	return predicate.WebAuthnChallenge(sql.FieldEQ(FieldChallengeHash, v))
+}
+
+// ChallengeHashNEQ applies the NEQ predicate on the "challenge_hash" field.
+func ChallengeHashNEQ(v string) predicate.WebAuthnChallenge {
+	return predicate.WebAuthnChallenge(sql.FieldNEQ(FieldChallengeHash, v))
+}
+
+// ChallengeHashIn applies the In predicate on the "challenge_hash" field.
+func ChallengeHashIn(vs ...string) predicate.WebAuthnChallenge {
+	return predicate.WebAuthnChallenge(sql.FieldIn(FieldChallengeHash, vs...))
+}
+
+// ChallengeHashNotIn applies the NotIn predicate on the "challenge_hash" field.
+func ChallengeHashNotIn(vs ...string) predicate.WebAuthnChallenge {
+	return predicate.WebAuthnChallenge(sql.FieldNotIn(FieldChallengeHash, vs...))
+}
+
+// ChallengeHashGT applies the GT predicate on the "challenge_hash" field.
+func ChallengeHashGT(v string) predicate.WebAuthnChallenge {
+	return predicate.WebAuthnChallenge(sql.FieldGT(FieldChallengeHash, v))
+}
+
+// ChallengeHashGTE applies the GTE predicate on the "challenge_hash" field.
+func ChallengeHashGTE(v string) predicate.WebAuthnChallenge {
+	return predicate.WebAuthnChallenge(sql.FieldGTE(FieldChallengeHash, v))
+}
+
+// ChallengeHashLT applies the LT predicate on the "challenge_hash" field.
+func ChallengeHashLT(v string) predicate.WebAuthnChallenge {
+	return predicate.WebAuthnChallenge(sql.FieldLT(FieldChallengeHash, v))
+}
+
+// ChallengeHashLTE applies the LTE predicate on the "challenge_hash" field.
+func ChallengeHashLTE(v string) predicate.WebAuthnChallenge {
+	return predicate.WebAuthnChallenge(sql.FieldLTE(FieldChallengeHash, v))
+}
+
+// ChallengeHashContains applies the Contains predicate on the "challenge_hash" field.
+func ChallengeHashContains(v string) predicate.WebAuthnChallenge {
+	return predicate.WebAuthnChallenge(sql.FieldContains(FieldChallengeHash, v))
+}
+
+// ChallengeHashHasPrefix applies the HasPrefix predicate on the "challenge_hash" field.
+func ChallengeHashHasPrefix(v string) predicate.WebAuthnChallenge {
+	return predicate.WebAuthnChallenge(sql.FieldHasPrefix(FieldChallengeHash, v))
+}
+
+// ChallengeHashHasSuffix applies the HasSuffix predicate on the "challenge_hash" field.
+func ChallengeHashHasSuffix(v string) predicate.WebAuthnChallenge {
+	return predicate.WebAuthnChallenge(sql.FieldHasSuffix(FieldChallengeHash, v))
+}
+
+// ChallengeHashEqualFold applies the EqualFold predicate on the "challenge_hash" field.
+func ChallengeHashEqualFold(v string) predicate.WebAuthnChallenge {
+	return predicate.WebAuthnChallenge(sql.FieldEqualFold(FieldChallengeHash, v))
+}
+
+// ChallengeHashContainsFold applies the ContainsFold predicate on the "challenge_hash" field.
+func ChallengeHashContainsFold(v string) predicate.WebAuthnChallenge {
+	return predicate.WebAuthnChallenge(sql.FieldContainsFold(FieldChallengeHash, v))
+}
+
+// ExpiresAtEQ applies the EQ predicate on the "expires_at" field.
+func ExpiresAtEQ(v time.Time) predicate.WebAuthnChallenge {
+	return predicate.WebAuthnChallenge(sql.FieldEQ(FieldExpiresAt, v))
+}
+
+// ExpiresAtNEQ applies the NEQ predicate on the "expires_at" field.
+func ExpiresAtNEQ(v time.Time) predicate.WebAuthnChallenge {
+	return predicate.WebAuthnChallenge(sql.FieldNEQ(FieldExpiresAt, v))
+}
+
+// ExpiresAtIn applies the In predicate on the "expires_at" field.
+func ExpiresAtIn(vs ...time.Time) predicate.WebAuthnChallenge {
+	return predicate.WebAuthnChallenge(sql.FieldIn(FieldExpiresAt, vs...))
+}
+
+// ExpiresAtNotIn applies the NotIn predicate on the "expires_at" field.
+func ExpiresAtNotIn(vs ...time.Time) predicate.WebAuthnChallenge {
+	return predicate.WebAuthnChallenge(sql.FieldNotIn(FieldExpiresAt, vs...))
+}
+
+// ExpiresAtGT applies the GT predicate on the "expires_at" field.
+func ExpiresAtGT(v time.Time) predicate.WebAuthnChallenge {
+	return predicate.WebAuthnChallenge(sql.FieldGT(FieldExpiresAt, v))
+}
+
+// ExpiresAtGTE applies the GTE predicate on the "expires_at" field.
+func ExpiresAtGTE(v time.Time) predicate.WebAuthnChallenge {
+	return predicate.WebAuthnChallenge(sql.FieldGTE(FieldExpiresAt, v))
+}
+
+// ExpiresAtLT applies the LT predicate on the "expires_at" field.
+func ExpiresAtLT(v time.Time) predicate.WebAuthnChallenge {
+	return predicate.WebAuthnChallenge(sql.FieldLT(FieldExpiresAt, v))
+}
+
+// ExpiresAtLTE applies the LTE predicate on the "expires_at" field.
+func ExpiresAtLTE(v time.Time) predicate.WebAuthnChallenge {
+	return predicate.WebAuthnChallenge(sql.FieldLTE(FieldExpiresAt, v))
+}
+
+// And groups predicates with the AND operator between them.
+func And(predicates ...predicate.WebAuthnChallenge) predicate.WebAuthnChallenge {
+	return predicate.WebAuthnChallenge(sql.AndPredicates(predicates...))
+}
+
+// Or groups predicates with the OR operator between them.
+func Or(predicates ...predicate.WebAuthnChallenge) predicate.WebAuthnChallenge {
+	return predicate.WebAuthnChallenge(sql.OrPredicates(predicates...))
+}
+
+// Not applies the not operator on the given predicate.
+func Not(p predicate.WebAuthnChallenge) predicate.WebAuthnChallenge {
+	return predicate.WebAuthnChallenge(sql.NotPredicates(p))
+}
@@ -0,0 +1,202 @@
+// Code generated by ent, DO NOT EDIT.
+
+package ent
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"entgo.io/ent/dialect/sql/sqlgraph"
+	"entgo.io/ent/schema/field"
+	"github.com/smxlong/users/ent/webauthnchallenge"
+)
+
+// WebAuthnChallengeCreate is the builder for creating a WebAuthnChallenge entity.
+type WebAuthnChallengeCreate struct {
+	config
+	mutation *WebAuthnChallengeMutation
+	hooks    []Hook
+}
+
+// SetChallengeHash sets the "challenge_hash" field.
+func (wacc *WebAuthnChallengeCreate) SetChallengeHash(s string) *WebAuthnChallengeCreate {
+	wacc.mutation.SetChallengeHash(s)
+	return wacc
+}
+
+// SetExpiresAt sets the "expires_at" field.
+func (wacc *WebAuthnChallengeCreate) SetExpiresAt(t time.Time) *WebAuthnChallengeCreate {
+	wacc.mutation.SetExpiresAt(t)
+	return wacc
+}
+
+// Mutation returns the WebAuthnChallengeMutation object of the builder.
+func (wacc *WebAuthnChallengeCreate) Mutation() *WebAuthnChallengeMutation {
+	return wacc.mutation
+}
+
+// Save creates the WebAuthnChallenge in the database.
+func (wacc *WebAuthnChallengeCreate) Save(ctx context.Context) (*WebAuthnChallenge, error) {
+	return withHooks(ctx, wacc.sqlSave, wacc.mutation, wacc.hooks)
+}
+
+// SaveX calls Save and panics if Save returns an error.
+func (wacc *WebAuthnChallengeCreate) SaveX(ctx context.Context) *WebAuthnChallenge {
+	v, err := wacc.Save(ctx)
+	if err != nil {
+		panic(err)
+	}
+	return v
+}
+
+// Exec executes the query.
+func (wacc *WebAuthnChallengeCreate) Exec(ctx context.Context) error {
+	_, err := wacc.Save(ctx)
+	return err
+}
+
+// ExecX is like Exec, but panics if an error occurs.
+func (wacc *WebAuthnChallengeCreate) ExecX(ctx context.Context) {
+	if err := wacc.Exec(ctx); err != nil {
+		panic(err)
+	}
+}
+
+// check runs all checks and user-defined validators on the builder.
+func (wacc *WebAuthnChallengeCreate) check() error {
+	if _, ok := wacc.mutation.ChallengeHash(); !ok {
+		return &ValidationError{Name: "challenge_hash", err: errors.New(`ent: missing required field "WebAuthnChallenge.challenge_hash"`)}
+	}
+	if v, ok := wacc.mutation.ChallengeHash(); ok {
+		if err := webauthnchallenge.ChallengeHashValidator(v); err != nil {
+			return &ValidationError{Name: "challenge_hash", err: fmt.Errorf(`ent: validator failed for field "WebAuthnChallenge.challenge_hash": %w`, err)}
+		}
+	}
+	if _, ok := wacc.mutation.ExpiresAt(); !ok {
+		return &ValidationError{Name: "expires_at", err: errors.New(`ent: missing required field "WebAuthnChallenge.expires_at"`)}
+	}
+	return nil
+}
+
+func (wacc *WebAuthnChallengeCreate) sqlSave(ctx context.Context) (*WebAuthnChallenge, error) {
+	if err := wacc.check(); err != nil {
+		return nil, err
+	}
+	_node, _spec := wacc.createSpec()
+	if err := sqlgraph.CreateNode(ctx, wacc.driver, _spec); err != nil {
+		if sqlgraph.IsConstraintError(err) {
+			err = &ConstraintError{msg: err.Error(), wrap: err}
+		}
+		return nil, err
+	}
+	id := _spec.ID.Value.(int64)
+	_node.ID = int(id)
+	wacc.mutation.id = &_node.ID
+	wacc.mutation.done = true
+	return _node, nil
+}
+
+func (wacc *WebAuthnChallengeCreate) createSpec() (*WebAuthnChallenge, *sqlgraph.CreateSpec) {
+	var (
+		_node = &WebAuthnChallenge{config: wacc.config}
+		_spec = sqlgraph.NewCreateSpec(webauthnchallenge.Table, sqlgraph.NewFieldSpec(webauthnchallenge.FieldID, field.TypeInt))
+	)
+	if value, ok := wacc.mutation.ChallengeHash(); ok {
+		_spec.SetField(webauthnchallenge.FieldChallengeHash, field.TypeString, value)
+		_node.ChallengeHash = value
+	}
+	if value, ok := wacc.mutation.ExpiresAt(); ok {
+		_spec.SetField(webauthnchallenge.FieldExpiresAt, field.TypeTime, value)
+		_node.ExpiresAt = value
+	}
+	return _node, _spec
+}
+
+// WebAuthnChallengeCreateBulk is the builder for creating many WebAuthnChallenge entities in bulk.
+type WebAuthnChallengeCreateBulk struct {
+	config
+	err      error
+	builders []*WebAuthnChallengeCreate
+}
+
+// Save creates the WebAuthnChallenge entities in the database.
+func (waccb *WebAuthnChallengeCreateBulk) Save(ctx context.Context) ([]*WebAuthnChallenge, error) {
+	if waccb.err != nil {
+		return nil, waccb.err
+	}
+	specs := make([]*sqlgraph.CreateSpec, len(waccb.builders))
+	nodes := make([]*WebAuthnChallenge, len(waccb.builders))
+	mutators := make([]Mutator, len(waccb.builders))
+	for i := range waccb.builders {
+		func(i int, root context.Context) {
+			builder := waccb.builders[i]
+			var mut Mutator = MutateFunc(func(ctx context.Context, m Mutation) (Value, error) {
+				mutation, ok := m.(*WebAuthnChallengeMutation)
+				if !ok {
+					return nil, fmt.Errorf("unexpected mutation type %T", m)
+				}
+				if err := builder.check(); err != nil {
+					return nil, err
+				}
+				builder.mutation = mutation
+				var err error
+				nodes[i], specs[i] = builder.createSpec()
+				if i < len(mutators)-1 {
+					_, err = mutators[i+1].Mutate(root, waccb.builders[i+1].mutation)
+				} else {
+					spec := &sqlgraph.BatchCreateSpec{Nodes: specs}
+					// Invoke the actual operation on the latest mutation in the chain.
+					if err = sqlgraph.BatchCreate(ctx, waccb.driver, spec); err != nil {
+						if sqlgraph.IsConstraintError(err) {
+							err = &ConstraintError{msg: err.Error(), wrap: err}
+						}
+					}
+				}
+				if err != nil {
+					return nil, err
+				}
+				mutation.id = &nodes[i].ID
+				if specs[i].ID.Value != nil {
+					id := specs[i].ID.Value.(int64)
+					nodes[i].ID = int(id)
+				}
+				mutation.done = true
+				return nodes[i], nil
+			})
+			for i := len(builder.hooks) - 1; i >= 0; i-- {
+				mut = builder.hooks[i](mut)
+			}
+			mutators[i] = mut
+		}(i, ctx)
+	}
+	if len(mutators) > 0 {
+		if _, err := mutators[0].Mutate(ctx, waccb.builders[0].mutation); err != nil {
+			return nil, err
+		}
+	}
+	return nodes, nil
+}
+
+// SaveX is like Save, but panics if an error occurs.
+func (waccb *WebAuthnChallengeCreateBulk) SaveX(ctx context.Context) []*WebAuthnChallenge {
+	v, err := waccb.Save(ctx)
+	if err != nil {
+		panic(err)
+	}
+	return v
+}
+
+// Exec executes the query.
+func (waccb *WebAuthnChallengeCreateBulk) Exec(ctx context.Context) error {
+	_, err := waccb.Save(ctx)
+	return err
+}
+
+// ExecX is like Exec, but panics if an error occurs.
+func (waccb *WebAuthnChallengeCreateBulk) ExecX(ctx context.Context) {
+	if err := waccb.Exec(ctx); err != nil {
+		panic(err)
+	}
+}
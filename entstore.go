@@ -0,0 +1,600 @@
+package users
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+	"strconv"
+	"time"
+
+	"github.com/smxlong/users/ent"
+	"github.com/smxlong/users/ent/permission"
+	"github.com/smxlong/users/ent/role"
+	"github.com/smxlong/users/ent/roleassignment"
+	"github.com/smxlong/users/ent/user"
+)
+
+// EntStore is a Store using the generated ent client. Users are identified
+// by their ent IDs, formatted as decimal strings. Deleting a user
+// soft-deletes it, as Delete does.
+type EntStore struct {
+	client *ent.Client
+}
+
+// NewEntStore returns an EntStore using the client.
+func NewEntStore(client *ent.Client) *EntStore {
+	return &EntStore{client: client}
+}
+
+// Client returns the ent client.
+func (s *EntStore) Client() *ent.Client {
+	return s.client
+}
+
+// InTx calls fn with an EntStore using a transaction, which is committed if
+// fn succeeds and rolled back if not. If the client is already in a
+// transaction, fn runs in that one.
+func (s *EntStore) InTx(ctx context.Context, fn func(store Store) error) error {
+	_, err := inTx(ctx, s.client, func(client *ent.Client) error {
+		return fn(NewEntStore(client))
+	})
+	return err
+}
+
+// CreateUser creates a user.
+func (s *EntStore) CreateUser(ctx context.Context, u *User) (*User, error) {
+	create := s.client.User.Create().
+		SetName(u.Name).
+		SetEmail(u.Email).
+		SetPasswordHash(u.PasswordHash).
+		SetPasswordChangedAt(clockNow(ctx)).
+		SetNillableSuspendedUntil(u.SuspendedUntil).
+		SetNillableEmailVerifiedAt(u.EmailVerifiedAt)
+	if u.Status != "" {
+		create.SetStatus(user.Status(u.Status))
+	}
+	eu, err := create.Save(ctx)
+	if err != nil {
+		return nil, entStoreError(err)
+	}
+	return entStoreUser(eu), nil
+}
+
+// FindUserByID finds a user by ID.
+func (s *EntStore) FindUserByID(ctx context.Context, id string) (*User, error) {
+	eu, err := s.user(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	return entStoreUser(eu), nil
+}
+
+// FindUserByName finds a user by name.
+func (s *EntStore) FindUserByName(ctx context.Context, name string) (*User, error) {
+	eu, err := FindByName(ctx, s.client, name)
+	if err != nil {
+		return nil, entStoreError(err)
+	}
+	return entStoreUser(eu), nil
+}
+
+// FindUserByEmail finds a user by email.
+func (s *EntStore) FindUserByEmail(ctx context.Context, email string) (*User, error) {
+	eu, err := FindByEmail(ctx, s.client, email)
+	if err != nil {
+		return nil, entStoreError(err)
+	}
+	return entStoreUser(eu), nil
+}
+
+// UpdateUser saves changes to a user. If the password hash changed, the
+// password change time is recorded.
+func (s *EntStore) UpdateUser(ctx context.Context, u *User) (*User, error) {
+	eu, err := s.user(ctx, u.ID)
+	if err != nil {
+		return nil, err
+	}
+	update := eu.Update().
+		SetName(u.Name).
+		SetEmail(u.Email).
+		SetPasswordHash(u.PasswordHash).
+		SetStatus(user.Status(u.Status)).
+		ClearSuspendedUntil().
+		SetNillableSuspendedUntil(u.SuspendedUntil).
+		ClearEmailVerifiedAt().
+		SetNillableEmailVerifiedAt(u.EmailVerifiedAt)
+	if u.PasswordHash != eu.PasswordHash {
+		update.SetPasswordChangedAt(clockNow(ctx))
+	}
+	eu, err = update.Save(ctx)
+	if err != nil {
+		return nil, entStoreError(err)
+	}
+	return entStoreUser(eu), nil
+}
+
+// RecordLogin records a successful login.
+func (s *EntStore) RecordLogin(ctx context.Context, id string, at time.Time, ip string) (*User, error) {
+	eu, err := s.user(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	update := eu.Update().
+		SetLastLoginAt(at).
+		SetFailedLoginCount(0)
+	if ip != "" {
+		update.SetLastLoginIP(ip)
+	}
+	eu, err = update.Save(ctx)
+	if err != nil {
+		return nil, entStoreError(err)
+	}
+	return entStoreUser(eu), nil
+}
+
+// RecordLoginFailure increments the user's failed login count.
+func (s *EntStore) RecordLoginFailure(ctx context.Context, id string) error {
+	eu, err := s.user(ctx, id)
+	if err != nil {
+		return err
+	}
+	return entStoreError(eu.Update().AddFailedLoginCount(1).Exec(ctx))
+}
+
+// DeleteUser deletes a user.
+func (s *EntStore) DeleteUser(ctx context.Context, id string) error {
+	eu, err := s.user(ctx, id)
+	if err != nil {
+		return err
+	}
+	return entStoreError(Delete(ctx, s.client, eu))
+}
+
+// AddUserRole gives a user a role.
+func (s *EntStore) AddUserRole(ctx context.Context, id, roleName string) error {
+	eu, err := s.user(ctx, id)
+	if err != nil {
+		return err
+	}
+	r, err := s.role(ctx, roleName)
+	if err != nil {
+		return err
+	}
+	has, err := eu.QueryRoles().
+		Where(role.ID(r.ID)).
+		Exist(ctx)
+	if err != nil || has {
+		return err
+	}
+	_, err = AddRole(ctx, s.client, eu, r)
+	return entStoreError(err)
+}
+
+// RemoveUserRole takes a role from a user.
+func (s *EntStore) RemoveUserRole(ctx context.Context, id, roleName string) error {
+	eu, err := s.user(ctx, id)
+	if err != nil {
+		return err
+	}
+	r, err := s.role(ctx, roleName)
+	if errors.Is(err, ErrNotFound) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	_, err = RemoveRole(ctx, s.client, eu, r)
+	return entStoreError(err)
+}
+
+// UserRoles returns the names of a user's roles.
+func (s *EntStore) UserRoles(ctx context.Context, id string) ([]string, error) {
+	eu, err := s.user(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	return eu.QueryRoles().
+		Order(ent.Asc(role.FieldName)).
+		Select(role.FieldName).
+		Strings(ctx)
+}
+
+// AddUserRoleOn gives a user a role on a resource.
+func (s *EntStore) AddUserRoleOn(ctx context.Context, id, roleName string, resource Resource) error {
+	eu, err := s.user(ctx, id)
+	if err != nil {
+		return err
+	}
+	r, err := s.role(ctx, roleName)
+	if err != nil {
+		return err
+	}
+	_, err = AddRoleOn(ctx, s.client, eu, r, resource)
+	return entStoreError(err)
+}
+
+// RemoveUserRoleOn takes a role on a resource from a user.
+func (s *EntStore) RemoveUserRoleOn(ctx context.Context, id, roleName string, resource Resource) error {
+	eu, err := s.user(ctx, id)
+	if err != nil {
+		return err
+	}
+	r, err := s.role(ctx, roleName)
+	if errors.Is(err, ErrNotFound) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	return entStoreError(RemoveRoleOn(ctx, s.client, eu, r, resource))
+}
+
+// UserRolesOn returns the names of the roles a user was given on the
+// resource.
+func (s *EntStore) UserRolesOn(ctx context.Context, id string, resource Resource) ([]string, error) {
+	eu, err := s.user(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	return s.client.RoleAssignment.Query().
+		Where(
+			roleassignment.HasUserWith(user.ID(eu.ID)),
+			roleassignment.ResourceType(resource.Type),
+			roleassignment.ResourceID(resource.ID),
+		).
+		QueryRole().
+		Order(ent.Asc(role.FieldName)).
+		Select(role.FieldName).
+		Strings(ctx)
+}
+
+// UserPermissions returns the names of the permissions of a user's roles and
+// the roles they inherit.
+func (s *EntStore) UserPermissions(ctx context.Context, id string) ([]string, error) {
+	roles, err := s.UserRoles(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	g, err := loadRoleAncestry(ctx, s.client, roles)
+	if err != nil {
+		return nil, err
+	}
+	return g.permissionNames(roles), nil
+}
+
+// CreateRole creates a role with its permissions and the roles it inherits.
+func (s *EntStore) CreateRole(ctx context.Context, r *Role) (*Role, error) {
+	ps, err := s.permissions(ctx, r.Permissions)
+	if err != nil {
+		return nil, err
+	}
+	parents, err := s.roles(ctx, r.Inherits)
+	if err != nil {
+		return nil, err
+	}
+	er, err := CreateRole(ctx, s.client, r.Name, r.Description, ps...)
+	if err != nil {
+		return nil, entStoreError(err)
+	}
+	if len(parents) > 0 {
+		er, err = SetRoleParents(ctx, s.client, er, parents)
+		if err != nil {
+			return nil, entStoreError(err)
+		}
+	}
+	return s.storeRole(ctx, er)
+}
+
+// FindRole finds a role by name.
+func (s *EntStore) FindRole(ctx context.Context, name string) (*Role, error) {
+	er, err := s.role(ctx, name)
+	if err != nil {
+		return nil, err
+	}
+	return s.storeRole(ctx, er)
+}
+
+// ListRoles returns all roles.
+func (s *EntStore) ListRoles(ctx context.Context) ([]*Role, error) {
+	ers, err := s.client.Role.Query().
+		WithPermissions().
+		WithParents().
+		Order(ent.Asc(role.FieldName)).
+		All(ctx)
+	if err != nil {
+		return nil, err
+	}
+	roles := make([]*Role, len(ers))
+	for i, er := range ers {
+		roles[i] = entStoreRole(er, er.Edges.Permissions, er.Edges.Parents)
+	}
+	return roles, nil
+}
+
+// UpdateRoleDescription changes a role's description.
+func (s *EntStore) UpdateRoleDescription(ctx context.Context, name, description string) error {
+	er, err := s.role(ctx, name)
+	if err != nil {
+		return err
+	}
+	return UpdateRoleDescription(ctx, s.client, er, description)
+}
+
+// AddRolePermission adds a permission to a role.
+func (s *EntStore) AddRolePermission(ctx context.Context, roleName, permissionName string) error {
+	er, err := s.role(ctx, roleName)
+	if err != nil {
+		return err
+	}
+	ep, err := s.permission(ctx, permissionName)
+	if err != nil {
+		return err
+	}
+	has, err := er.QueryPermissions().
+		Where(permission.ID(ep.ID)).
+		Exist(ctx)
+	if err != nil || has {
+		return err
+	}
+	_, err = AddRolePermission(ctx, s.client, er, ep)
+	return entStoreError(err)
+}
+
+// RemoveRolePermission removes a permission from a role.
+func (s *EntStore) RemoveRolePermission(ctx context.Context, roleName, permissionName string) error {
+	er, err := s.role(ctx, roleName)
+	if err != nil {
+		return err
+	}
+	ep, err := s.permission(ctx, permissionName)
+	if errors.Is(err, ErrNotFound) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	_, err = RemoveRolePermission(ctx, s.client, er, ep)
+	return entStoreError(err)
+}
+
+// SetRolePermissions sets a role's permissions.
+func (s *EntStore) SetRolePermissions(ctx context.Context, roleName string, permissions []string) error {
+	er, err := s.role(ctx, roleName)
+	if err != nil {
+		return err
+	}
+	ps, err := s.permissions(ctx, permissions)
+	if err != nil {
+		return err
+	}
+	_, err = SetRolePermissions(ctx, s.client, er, ps)
+	return entStoreError(err)
+}
+
+// SetRoleInherits sets the roles a role inherits.
+func (s *EntStore) SetRoleInherits(ctx context.Context, roleName string, inherits []string) error {
+	er, err := s.role(ctx, roleName)
+	if err != nil {
+		return err
+	}
+	parents, err := s.roles(ctx, inherits)
+	if err != nil {
+		return err
+	}
+	_, err = SetRoleParents(ctx, s.client, er, parents)
+	return entStoreError(err)
+}
+
+// DeleteRole deletes a role.
+func (s *EntStore) DeleteRole(ctx context.Context, name string) error {
+	er, err := s.role(ctx, name)
+	if err != nil {
+		return err
+	}
+	return entStoreError(DeleteRole(ctx, s.client, er))
+}
+
+// CreatePermission creates a permission.
+func (s *EntStore) CreatePermission(ctx context.Context, p *Permission) (*Permission, error) {
+	ep, err := CreatePermission(ctx, s.client, p.Name, p.Description)
+	if err != nil {
+		return nil, entStoreError(err)
+	}
+	return entStorePermission(ep), nil
+}
+
+// FindPermission finds a permission by name.
+func (s *EntStore) FindPermission(ctx context.Context, name string) (*Permission, error) {
+	ep, err := s.permission(ctx, name)
+	if err != nil {
+		return nil, err
+	}
+	return entStorePermission(ep), nil
+}
+
+// ListPermissions returns all permissions.
+func (s *EntStore) ListPermissions(ctx context.Context) ([]*Permission, error) {
+	eps, err := s.client.Permission.Query().
+		Order(ent.Asc(permission.FieldName)).
+		All(ctx)
+	if err != nil {
+		return nil, err
+	}
+	ps := make([]*Permission, len(eps))
+	for i, ep := range eps {
+		ps[i] = entStorePermission(ep)
+	}
+	return ps, nil
+}
+
+// UpdatePermissionDescription changes a permission's description.
+func (s *EntStore) UpdatePermissionDescription(ctx context.Context, name, description string) error {
+	ep, err := s.permission(ctx, name)
+	if err != nil {
+		return err
+	}
+	return UpdatePermissionDescription(ctx, s.client, ep, description)
+}
+
+// DeletePermission deletes a permission.
+func (s *EntStore) DeletePermission(ctx context.Context, name string) error {
+	ep, err := s.permission(ctx, name)
+	if err != nil {
+		return err
+	}
+	return entStoreError(DeletePermission(ctx, s.client, ep))
+}
+
+// TokenGeneration returns a user's token generation.
+func (s *EntStore) TokenGeneration(ctx context.Context, id string) (int, error) {
+	eu, err := s.user(ctx, id)
+	if err != nil {
+		return 0, err
+	}
+	return eu.TokenGeneration, nil
+}
+
+// RevokeSessions changes a user's token generation.
+func (s *EntStore) RevokeSessions(ctx context.Context, id string) error {
+	eu, err := s.user(ctx, id)
+	if err != nil {
+		return err
+	}
+	_, err = RevokeTokens(ctx, s.client, eu)
+	return err
+}
+
+// user finds a user by ID.
+func (s *EntStore) user(ctx context.Context, id string) (*ent.User, error) {
+	n, err := strconv.Atoi(id)
+	if err != nil {
+		return nil, fmt.Errorf("%w: user %q", ErrNotFound, id)
+	}
+	eu, err := s.client.User.Get(ctx, n)
+	if err != nil {
+		return nil, entStoreError(err)
+	}
+	return eu, nil
+}
+
+// role finds a role by name.
+func (s *EntStore) role(ctx context.Context, name string) (*ent.Role, error) {
+	er, err := s.client.Role.Query().
+		Where(role.Name(name)).
+		Only(ctx)
+	if err != nil {
+		return nil, entStoreError(err)
+	}
+	return er, nil
+}
+
+// permission finds a permission by name.
+func (s *EntStore) permission(ctx context.Context, name string) (*ent.Permission, error) {
+	ep, err := s.client.Permission.Query().
+		Where(permission.Name(name)).
+		Only(ctx)
+	if err != nil {
+		return nil, entStoreError(err)
+	}
+	return ep, nil
+}
+
+// roles finds roles by name.
+func (s *EntStore) roles(ctx context.Context, names []string) ([]*ent.Role, error) {
+	rs := make([]*ent.Role, len(names))
+	for i, name := range names {
+		r, err := s.role(ctx, name)
+		if err != nil {
+			return nil, err
+		}
+		rs[i] = r
+	}
+	return rs, nil
+}
+
+// permissions finds permissions by name.
+func (s *EntStore) permissions(ctx context.Context, names []string) ([]*ent.Permission, error) {
+	ps := make([]*ent.Permission, len(names))
+	for i, name := range names {
+		p, err := s.permission(ctx, name)
+		if err != nil {
+			return nil, err
+		}
+		ps[i] = p
+	}
+	return ps, nil
+}
+
+// storeRole converts a role, loading its permissions and parents.
+func (s *EntStore) storeRole(ctx context.Context, er *ent.Role) (*Role, error) {
+	eps, err := s.client.Role.QueryPermissions(er).
+		Order(ent.Asc(permission.FieldName)).
+		All(ctx)
+	if err != nil {
+		return nil, err
+	}
+	parents, err := s.client.Role.QueryParents(er).All(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return entStoreRole(er, eps, parents), nil
+}
+
+// entStoreUser converts an ent user.
+func entStoreUser(eu *ent.User) *User {
+	return &User{
+		ID:               strconv.Itoa(eu.ID),
+		Name:             eu.Name,
+		Email:            eu.Email,
+		PasswordHash:     eu.PasswordHash,
+		Status:           string(eu.Status),
+		SuspendedUntil:   eu.SuspendedUntil,
+		EmailVerifiedAt:  eu.EmailVerifiedAt,
+		LastLoginAt:      eu.LastLoginAt,
+		LastLoginIP:      eu.LastLoginIP,
+		FailedLoginCount: eu.FailedLoginCount,
+	}
+}
+
+// entStoreRole converts an ent role, its permissions and its parents.
+func entStoreRole(er *ent.Role, eps []*ent.Permission, parents []*ent.Role) *Role {
+	r := &Role{
+		Name:        er.Name,
+		Description: er.Description,
+		Permissions: []string{},
+		Inherits:    []string{},
+	}
+	for _, ep := range eps {
+		r.Permissions = append(r.Permissions, ep.Name)
+	}
+	sort.Strings(r.Permissions)
+	for _, parent := range parents {
+		r.Inherits = append(r.Inherits, parent.Name)
+	}
+	sort.Strings(r.Inherits)
+	return r
+}
+
+// entStorePermission converts an ent permission.
+func entStorePermission(ep *ent.Permission) *Permission {
+	return &Permission{
+		Name:        ep.Name,
+		Description: ep.Description,
+	}
+}
+
+// entStoreError converts ent's not found and constraint errors to
+// ErrNotFound and ErrAlreadyExists, still wrapping the ent errors.
+func entStoreError(err error) error {
+	switch {
+	case err == nil:
+		return nil
+	case ent.IsNotFound(err):
+		return fmt.Errorf("%w: %w", ErrNotFound, err)
+	case ent.IsConstraintError(err):
+		return fmt.Errorf("%w: %w", ErrAlreadyExists, err)
+	default:
+		return err
+	}
+}
@@ -10,10 +10,56 @@ func (e Error) Error() string {
 
 // Error values for users package.
 const (
-	ErrPasswordHashUnknownAlgorithm  Error = "unknown algorithm"
-	ErrPasswordHashMismatch          Error = "mismatched hash and password"
-	ErrEmailAddressInvalid           Error = "invalid email address"
-	ErrTokenSecretRequired           Error = "token secret required"
-	ErrTokenInvalid                  Error = "invalid token"
-	ErrPermissionDescriptionMismatch Error = "permission description mismatch"
+	ErrPasswordHashUnknownAlgorithm   Error = "unknown algorithm"
+	ErrPasswordHashMismatch           Error = "mismatched hash and password"
+	ErrInvalidCredentials             Error = "invalid credentials"
+	ErrNotFound                       Error = "not found"
+	ErrAlreadyExists                  Error = "already exists"
+	ErrEmailAddressInvalid            Error = "invalid email address"
+	ErrTokenSecretRequired            Error = "token secret required"
+	ErrTokenInvalid                   Error = "invalid token"
+	ErrTokenExpired                   Error = "token expired"
+	ErrTokenRevoked                   Error = "token revoked"
+	ErrTokenDeviceMismatch            Error = "token bound to another device"
+	ErrEmailNotVerified               Error = "email address not verified"
+	ErrPasswordPolicy                 Error = "password does not satisfy policy"
+	ErrAccountLocked                  Error = "account locked"
+	ErrAccountDisabled                Error = "account disabled"
+	ErrAccountSuspended               Error = "account suspended"
+	ErrAccountPending                 Error = "account pending activation"
+	ErrAttributeUnknown               Error = "unknown attribute"
+	ErrAttributeExists                Error = "attribute already registered"
+	ErrAttributeInvalid               Error = "invalid attribute value"
+	ErrAttributeRequired              Error = "attribute required"
+	ErrAttributeNotUnique             Error = "attribute value not unique"
+	ErrPermissionDescriptionMismatch  Error = "permission description mismatch"
+	ErrPermissionNameInvalid          Error = "invalid permission name"
+	ErrRoleUnknown                    Error = "unknown role"
+	ErrRoleCycle                      Error = "role inheritance cycle"
+	ErrPlanStale                      Error = "plan is stale"
+	ErrRolesFileInvalid               Error = "invalid roles file"
+	ErrFormatUnknown                  Error = "unknown format"
+	ErrResourceInvalid                Error = "invalid resource"
+	ErrResourceCycle                  Error = "resource hierarchy cycle"
+	ErrTupleInvalid                   Error = "invalid relation tuple"
+	ErrRelationUnknown                Error = "unknown relation"
+	ErrNamespaceInvalid               Error = "invalid namespace configuration"
+	ErrIdentityClaimsInvalid          Error = "invalid identity claims"
+	ErrIdentityNotLinked              Error = "identity not linked"
+	ErrIdentityRequired               Error = "identity required to log in"
+	ErrOIDCDiscovery                  Error = "oidc discovery failed"
+	ErrMFANotEnrolled                 Error = "mfa not enrolled"
+	ErrMFAAlreadyEnabled              Error = "mfa already enabled"
+	ErrMFACodeInvalid                 Error = "invalid mfa code"
+	ErrMFASecretInvalid               Error = "invalid mfa secret"
+	ErrMFAEncryptionKeyInvalid        Error = "invalid mfa encryption key"
+	ErrMFARequired                    Error = "mfa required"
+	ErrRecoveryCodeInvalid            Error = "invalid recovery code"
+	ErrRecoveryCodesExist             Error = "unused recovery codes exist"
+	ErrWebAuthnInvalid                Error = "invalid webauthn response"
+	ErrWebAuthnSessionExpired         Error = "webauthn session expired"
+	ErrWebAuthnAttestationUnsupported Error = "unsupported webauthn attestation format"
+	ErrWebAuthnAlgorithmUnsupported   Error = "unsupported webauthn algorithm"
+	ErrWebAuthnCredentialUnknown      Error = "unknown webauthn credential"
+	ErrWebAuthnSignCountRegression    Error = "webauthn sign count regression"
 )
@@ -0,0 +1,52 @@
+package users
+
+import (
+	"context"
+	"time"
+)
+
+// EventType identifies what happened in an Event.
+type EventType string
+
+// Event types emitted by the Service.
+const (
+	EventUserCreated     EventType = "user.created"
+	EventUserDeleted     EventType = "user.deleted"
+	EventUserDisabled    EventType = "user.disabled"
+	EventUserSuspended   EventType = "user.suspended"
+	EventUserReactivated EventType = "user.reactivated"
+	EventLoginSucceeded  EventType = "login.succeeded"
+	EventLoginFailed     EventType = "login.failed"
+	EventRoleAdded       EventType = "role.added"
+	EventRoleRemoved     EventType = "role.removed"
+	EventTokensRevoked   EventType = "tokens.revoked"
+)
+
+// Event records something that happened to a user, for auditing and
+// monitoring.
+type Event struct {
+	Type EventType
+	// Time is when the event happened, according to the Service's Clock.
+	Time time.Time
+	// UserID is the ID of the user concerned. It is empty if the user is
+	// unknown, such as for a failed login with an unknown name.
+	UserID string
+	// Detail adds to the event type, such as the name of the role added.
+	// Optional.
+	Detail string
+}
+
+// EventSink receives the Service's events. Emit is called synchronously
+// once the outcome of an operation is known, so it should be quick, and
+// handle its own errors.
+type EventSink interface {
+	Emit(ctx context.Context, e Event)
+}
+
+// EventSinkFunc is an EventSink calling a function.
+type EventSinkFunc func(ctx context.Context, e Event)
+
+// Emit calls the function.
+func (f EventSinkFunc) Emit(ctx context.Context, e Event) {
+	f(ctx, e)
+}
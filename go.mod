@@ -4,10 +4,13 @@ go 1.23.1
 
 require (
 	entgo.io/ent v0.14.1
+	github.com/fxamacker/cbor/v2 v2.7.0
 	github.com/lestrrat-go/jwx/v3 v3.0.0-alpha1
 	github.com/mattn/go-sqlite3 v1.14.16
+	github.com/pelletier/go-toml/v2 v2.4.3
 	github.com/stretchr/testify v1.9.0
 	golang.org/x/crypto v0.32.0
+	gopkg.in/yaml.v3 v3.0.1
 )
 
 require (
@@ -28,9 +31,9 @@ require (
 	github.com/mitchellh/go-wordwrap v0.0.0-20150314170334-ad45545899c7 // indirect
 	github.com/pmezard/go-difflib v1.0.0 // indirect
 	github.com/segmentio/asm v1.2.0 // indirect
+	github.com/x448/float16 v0.8.4 // indirect
 	github.com/zclconf/go-cty v1.8.0 // indirect
 	golang.org/x/mod v0.20.0 // indirect
 	golang.org/x/sys v0.29.0 // indirect
 	golang.org/x/text v0.21.0 // indirect
-	gopkg.in/yaml.v3 v3.0.1 // indirect
 )
@@ -0,0 +1,259 @@
+package users
+
+import (
+	"context"
+	"fmt"
+	"path"
+
+	"github.com/smxlong/users/ent"
+	"github.com/smxlong/users/ent/identity"
+	"github.com/smxlong/users/ent/role"
+	"github.com/smxlong/users/ent/user"
+)
+
+// FederatedClaims are the verified claims about an account asserted by an
+// external identity provider.
+type FederatedClaims struct {
+	// Provider identifies the identity provider, usually its issuer URL.
+	Provider string
+	// Subject is the provider's identifier for the account.
+	Subject string
+	// Email is the account's email address. Optional.
+	Email string
+	// EmailVerified is true if the provider asserts that it verified Email.
+	EmailVerified bool
+	// Name is the preferred user name. Optional. If not set, Email is used
+	// when provisioning a user.
+	Name string
+	// Groups are the account's groups at the provider. Optional.
+	Groups []string
+}
+
+// GroupRoleRule maps an upstream group to a local role.
+type GroupRoleRule struct {
+	// Group is the upstream group name. It may be a pattern as understood by
+	// path.Match.
+	Group string
+	// Role is the name of the local role.
+	Role string
+}
+
+// FederationOptions control how federated logins are resolved to users.
+type FederationOptions struct {
+	// Provision creates a user the first time an unlinked identity logs in.
+	// Optional. If not set, unlinked identities are rejected. Provisioning
+	// fails with an error wrapping ErrAlreadyExists if a user already has the
+	// identity's name or email address; see FederatedLogin.
+	Provision bool
+	// LinkByEmail links an unlinked identity to the existing user with the
+	// same email address, provided the identity provider asserts that the
+	// address is verified. Optional.
+	LinkByEmail bool
+	// GroupRoles maps upstream groups to local roles. Every role named by a
+	// rule is managed by the rules: it is added to the user when one of its
+	// groups is present in the claims and removed otherwise. Optional.
+	GroupRoles []GroupRoleRule
+}
+
+// GetProvision returns the FederationOptions Provision, or false if the
+// options are nil.
+func (o *FederationOptions) GetProvision() bool {
+	return o != nil && o.Provision
+}
+
+// GetLinkByEmail returns the FederationOptions LinkByEmail, or false if the
+// options are nil.
+func (o *FederationOptions) GetLinkByEmail() bool {
+	return o != nil && o.LinkByEmail
+}
+
+// GetGroupRoles returns the FederationOptions GroupRoles, or none if the
+// options are nil.
+func (o *FederationOptions) GetGroupRoles() []GroupRoleRule {
+	if o == nil {
+		return nil
+	}
+	return o.GroupRoles
+}
+
+// FederatedLogin finds the user linked to the identity in the claims,
+// provisioning or linking a user if the options allow it, and then applies
+// the group to role rules. The claims must already have been verified, for
+// example with OIDCProvider.Verify. Nil options only find linked users. The
+// changes are made in a transaction, so a user isn't provisioned or linked
+// unless the login succeeds. The login is recorded as Login records it, with
+// the login options.
+//
+// If provisioning fails with ErrAlreadyExists because a local user has the
+// identity's name or email address, the identity must be linked to that user
+// to log in: have the user log in some other way and call LinkIdentity with
+// the claims, or set LinkByEmail if the provider verifies email addresses.
+func FederatedLogin(ctx context.Context, client *ent.Client, claims *FederatedClaims, opts *FederationOptions, loginOpts ...LoginOption) (*ent.User, error) {
+	if claims.Provider == "" || claims.Subject == "" {
+		return nil, ErrIdentityClaimsInvalid
+	}
+	return inTxEntity(ctx, client, func(client *ent.Client) (*ent.User, error) {
+		u, err := findOrProvisionFederatedUser(ctx, client, claims, opts)
+		if err != nil {
+			return nil, err
+		}
+		if err := CheckStatus(ctx, u); err != nil {
+			return nil, err
+		}
+		if err := applyGroupRoleRules(ctx, client, u, claims.Groups, opts.GetGroupRoles()); err != nil {
+			return nil, err
+		}
+		return recordEntLogin(ctx, client, u, loginOpts)
+	})
+}
+
+// findOrProvisionFederatedUser resolves the claims to a user.
+func findOrProvisionFederatedUser(ctx context.Context, client *ent.Client, claims *FederatedClaims, opts *FederationOptions) (*ent.User, error) {
+	id, err := client.Identity.Query().
+		Where(identity.Provider(claims.Provider), identity.Subject(claims.Subject)).
+		WithUser().
+		Only(ctx)
+	if err == nil {
+		// The user isn't loaded if they have been deleted.
+		if id.Edges.User == nil {
+			return nil, ErrIdentityNotLinked
+		}
+		return id.Edges.User, nil
+	}
+	if !ent.IsNotFound(err) {
+		return nil, err
+	}
+	if opts.GetLinkByEmail() && claims.EmailVerified && claims.Email != "" {
+		u, err := FindByEmail(ctx, client, claims.Email)
+		if err == nil {
+			if _, err := LinkIdentity(ctx, client, u, claims); err != nil {
+				return nil, err
+			}
+			return u, nil
+		}
+		if !ent.IsNotFound(err) {
+			return nil, err
+		}
+	}
+	if !opts.GetProvision() {
+		return nil, ErrIdentityNotLinked
+	}
+	name := claims.Name
+	if name == "" {
+		name = claims.Email
+	}
+	u, err := client.User.Create().
+		SetName(name).
+		SetEmail(claims.Email).
+		SetPasswordHash(PasswordHashNone().String()).
+		Save(ctx)
+	if ent.IsConstraintError(err) {
+		return nil, fmt.Errorf("%w: user with name %q or email %q", ErrAlreadyExists, name, claims.Email)
+	}
+	if err != nil {
+		return nil, err
+	}
+	if _, err := LinkIdentity(ctx, client, u, claims); err != nil {
+		return nil, err
+	}
+	return u, nil
+}
+
+// applyGroupRoleRules adds and removes the roles managed by the rules.
+func applyGroupRoleRules(ctx context.Context, client *ent.Client, u *ent.User, groups []string, rules []GroupRoleRule) error {
+	if len(rules) == 0 {
+		return nil
+	}
+	grant := map[string]bool{}
+	for _, rule := range rules {
+		grant[rule.Role] = grant[rule.Role] || groupMatches(rule.Group, groups)
+	}
+	names := make([]string, 0, len(grant))
+	for name := range grant {
+		names = append(names, name)
+	}
+	roles, err := client.Role.Query().
+		Where(role.NameIn(names...)).
+		All(ctx)
+	if err != nil {
+		return err
+	}
+	if len(roles) != len(names) {
+		return ErrRoleUnknown
+	}
+	update := u.Update()
+	for _, r := range roles {
+		if grant[r.Name] {
+			update.AddRoles(r)
+		} else {
+			update.RemoveRoles(r)
+		}
+	}
+	return update.Exec(ctx)
+}
+
+// groupMatches checks if any of the groups matches the pattern.
+func groupMatches(pattern string, groups []string) bool {
+	for _, g := range groups {
+		if ok, _ := path.Match(pattern, g); ok {
+			return true
+		}
+	}
+	return false
+}
+
+// LinkIdentity links the identity in the claims to a user.
+func LinkIdentity(ctx context.Context, client *ent.Client, u *ent.User, claims *FederatedClaims) (*ent.Identity, error) {
+	if claims.Provider == "" || claims.Subject == "" {
+		return nil, ErrIdentityClaimsInvalid
+	}
+	return client.Identity.Create().
+		SetProvider(claims.Provider).
+		SetSubject(claims.Subject).
+		SetEmail(claims.Email).
+		SetUser(u).
+		Save(ctx)
+}
+
+// UnlinkIdentity removes the link between a user and an external identity. A
+// user without a password cannot unlink their last identity, since they
+// would no longer be able to log in.
+func UnlinkIdentity(ctx context.Context, client *ent.Client, u *ent.User, provider, subject string) error {
+	ph, err := PasswordHashParse(u.PasswordHash)
+	if err != nil {
+		return err
+	}
+	_, err = inTx(ctx, client, func(client *ent.Client) error {
+		if ph.Algorithm == "none" {
+			n, err := client.Identity.Query().
+				Where(identity.HasUserWith(user.ID(u.ID))).
+				Count(ctx)
+			if err != nil {
+				return err
+			}
+			if n <= 1 {
+				return ErrIdentityRequired
+			}
+		}
+		n, err := client.Identity.Delete().
+			Where(
+				identity.Provider(provider),
+				identity.Subject(subject),
+				identity.HasUserWith(user.ID(u.ID)),
+			).
+			Exec(ctx)
+		if err != nil {
+			return err
+		}
+		if n == 0 {
+			return ErrIdentityNotLinked
+		}
+		return nil
+	})
+	return err
+}
+
+// Identities lists the external identities linked to a user.
+func Identities(ctx context.Context, client *ent.Client, u *ent.User) ([]*ent.Identity, error) {
+	return u.QueryIdentities().All(ctx)
+}
@@ -0,0 +1,200 @@
+package users
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/smxlong/users/ent"
+)
+
+func testFederatedClaims() *FederatedClaims {
+	return &FederatedClaims{
+		Provider:      "https://idp.example.com",
+		Subject:       "sub1",
+		Email:         USER1_TEST_EMAIL,
+		EmailVerified: true,
+		Name:          "user1",
+	}
+}
+
+func Test_that_FederatedLogin_provisions_a_user(t *testing.T) {
+	client := setupAndMigrate(t)
+	ctx := context.Background()
+	u, err := FederatedLogin(ctx, client, testFederatedClaims(), &FederationOptions{Provision: true})
+	require.NoError(t, err)
+	require.Equal(t, "user1", u.Name)
+	require.Equal(t, USER1_TEST_EMAIL, u.Email)
+	_, err = Login(ctx, u, "")
+	require.ErrorIs(t, err, ErrInvalidCredentials)
+	u2, err := FederatedLogin(ctx, client, testFederatedClaims(), &FederationOptions{})
+	require.NoError(t, err)
+	require.Equal(t, u.ID, u2.ID)
+}
+
+func Test_that_FederatedLogin_records_the_login(t *testing.T) {
+	client := setupAndMigrate(t)
+	clock := NewFakeClock(time.Now())
+	ctx := ContextWithClock(context.Background(), clock)
+	u, err := FederatedLogin(ctx, client, testFederatedClaims(), &FederationOptions{Provision: true}, WithIP("192.0.2.1"))
+	require.NoError(t, err)
+	require.NotNil(t, u.LastLoginAt)
+	require.True(t, clock.Now().Equal(*u.LastLoginAt))
+	require.Equal(t, "192.0.2.1", u.LastLoginIP)
+	_, err = Login(ctx, u, "")
+	require.ErrorIs(t, err, ErrInvalidCredentials)
+	clock.Advance(time.Minute)
+	u, err = FederatedLogin(ctx, client, testFederatedClaims(), &FederationOptions{}, WithIP("192.0.2.2"))
+	require.NoError(t, err)
+	require.True(t, clock.Now().Equal(*u.LastLoginAt))
+	require.Equal(t, "192.0.2.2", u.LastLoginIP)
+	require.Zero(t, u.FailedLoginCount)
+}
+
+func Test_that_FederatedLogin_fails_for_unlinked_identity_without_Provision(t *testing.T) {
+	client := setupAndMigrate(t)
+	ctx := context.Background()
+	_, err := FederatedLogin(ctx, client, testFederatedClaims(), &FederationOptions{})
+	require.ErrorIs(t, err, ErrIdentityNotLinked)
+}
+
+func Test_that_FederatedLogin_accepts_nil_options(t *testing.T) {
+	client := setupAndMigrate(t)
+	ctx := context.Background()
+	_, err := FederatedLogin(ctx, client, testFederatedClaims(), nil)
+	require.ErrorIs(t, err, ErrIdentityNotLinked)
+	u, err := FederatedLogin(ctx, client, testFederatedClaims(), &FederationOptions{Provision: true})
+	require.NoError(t, err)
+	u2, err := FederatedLogin(ctx, client, testFederatedClaims(), nil)
+	require.NoError(t, err)
+	require.Equal(t, u.ID, u2.ID)
+}
+
+func Test_that_FederatedLogin_reports_provisioning_collisions(t *testing.T) {
+	client := setupAndMigrate(t)
+	ctx := context.Background()
+	u, err := Create(ctx, client, "user1", USER2_TEST_EMAIL, "password")
+	require.NoError(t, err)
+	_, err = FederatedLogin(ctx, client, testFederatedClaims(), &FederationOptions{Provision: true})
+	require.ErrorIs(t, err, ErrAlreadyExists)
+	// nothing was provisioned
+	_, err = FindByEmail(ctx, client, USER1_TEST_EMAIL)
+	require.True(t, ent.IsNotFound(err))
+	// linking the identity lets it log in
+	_, err = LinkIdentity(ctx, client, u, testFederatedClaims())
+	require.NoError(t, err)
+	u2, err := FederatedLogin(ctx, client, testFederatedClaims(), &FederationOptions{Provision: true})
+	require.NoError(t, err)
+	require.Equal(t, u.ID, u2.ID)
+}
+
+func Test_that_FederatedLogin_links_by_verified_email(t *testing.T) {
+	client := setupAndMigrate(t)
+	ctx := context.Background()
+	u, err := Create(ctx, client, "user1", USER1_TEST_EMAIL, "password")
+	require.NoError(t, err)
+	claims := testFederatedClaims()
+	claims.EmailVerified = false
+	_, err = FederatedLogin(ctx, client, claims, &FederationOptions{LinkByEmail: true})
+	require.ErrorIs(t, err, ErrIdentityNotLinked)
+	claims.EmailVerified = true
+	u2, err := FederatedLogin(ctx, client, claims, &FederationOptions{LinkByEmail: true})
+	require.NoError(t, err)
+	require.Equal(t, u.ID, u2.ID)
+	ids, err := Identities(ctx, client, u)
+	require.NoError(t, err)
+	require.Len(t, ids, 1)
+}
+
+func Test_that_FederatedLogin_maps_groups_to_roles(t *testing.T) {
+	client := setupAndMigrate(t)
+	ctx := context.Background()
+	require.NoError(t, CreateRolesAndPermissions(ctx, client, testRolesAndPermissions))
+	opts := &FederationOptions{
+		Provision: true,
+		GroupRoles: []GroupRoleRule{
+			{Group: "admins", Role: "admin"},
+			{Group: "staff-*", Role: "user"},
+		},
+	}
+	claims := testFederatedClaims()
+	claims.Groups = []string{"admins", "staff-eng"}
+	u, err := FederatedLogin(ctx, client, claims, opts)
+	require.NoError(t, err)
+	roles, err := u.QueryRoles().All(ctx)
+	require.NoError(t, err)
+	require.Len(t, roles, 2)
+	claims.Groups = []string{"staff-ops"}
+	u, err = FederatedLogin(ctx, client, claims, opts)
+	require.NoError(t, err)
+	roles, err = u.QueryRoles().All(ctx)
+	require.NoError(t, err)
+	require.Len(t, roles, 1)
+	require.Equal(t, "user", roles[0].Name)
+}
+
+func Test_that_FederatedLogin_fails_with_unknown_role_in_rules(t *testing.T) {
+	client := setupAndMigrate(t)
+	ctx := context.Background()
+	_, err := FederatedLogin(ctx, client, testFederatedClaims(), &FederationOptions{
+		Provision:  true,
+		GroupRoles: []GroupRoleRule{{Group: "admins", Role: "missing"}},
+	})
+	require.ErrorIs(t, err, ErrRoleUnknown)
+	// the user was not provisioned
+	_, err = FindByEmail(ctx, client, USER1_TEST_EMAIL)
+	require.True(t, ent.IsNotFound(err))
+}
+
+func Test_that_FederatedLogin_works_with_mock_OIDC_provider(t *testing.T) {
+	client := setupAndMigrate(t)
+	ctx := context.Background()
+	m := newMockOIDCProvider(t)
+	p, err := DiscoverOIDCProvider(ctx, m.server.URL, "client")
+	require.NoError(t, err)
+	claims, err := p.Verify(ctx, m.idToken(t, "client", "sub1", map[string]interface{}{
+		"email":          USER1_TEST_EMAIL,
+		"email_verified": true,
+	}))
+	require.NoError(t, err)
+	u, err := FederatedLogin(ctx, client, claims, &FederationOptions{Provision: true})
+	require.NoError(t, err)
+	require.Equal(t, USER1_TEST_EMAIL, u.Name)
+}
+
+func Test_that_LinkIdentity_fails_with_duplicate_identity(t *testing.T) {
+	client := setupAndMigrate(t)
+	ctx := context.Background()
+	u1, err := Create(ctx, client, "user1", USER1_TEST_EMAIL, "password")
+	require.NoError(t, err)
+	u2, err := Create(ctx, client, "user2", USER2_TEST_EMAIL, "password")
+	require.NoError(t, err)
+	_, err = LinkIdentity(ctx, client, u1, testFederatedClaims())
+	require.NoError(t, err)
+	_, err = LinkIdentity(ctx, client, u2, testFederatedClaims())
+	require.Error(t, err)
+}
+
+func Test_that_UnlinkIdentity_works(t *testing.T) {
+	client := setupAndMigrate(t)
+	ctx := context.Background()
+	u, err := Create(ctx, client, "user1", USER1_TEST_EMAIL, "password")
+	require.NoError(t, err)
+	claims := testFederatedClaims()
+	_, err = LinkIdentity(ctx, client, u, claims)
+	require.NoError(t, err)
+	require.NoError(t, UnlinkIdentity(ctx, client, u, claims.Provider, claims.Subject))
+	require.ErrorIs(t, UnlinkIdentity(ctx, client, u, claims.Provider, claims.Subject), ErrIdentityNotLinked)
+}
+
+func Test_that_UnlinkIdentity_refuses_to_remove_last_login_method(t *testing.T) {
+	client := setupAndMigrate(t)
+	ctx := context.Background()
+	claims := testFederatedClaims()
+	u, err := FederatedLogin(ctx, client, claims, &FederationOptions{Provision: true})
+	require.NoError(t, err)
+	err = UnlinkIdentity(ctx, client, u, claims.Provider, claims.Subject)
+	require.ErrorIs(t, err, ErrIdentityRequired)
+}
@@ -0,0 +1,169 @@
+package users
+
+import (
+	"context"
+	"fmt"
+	"maps"
+	"slices"
+
+	"github.com/smxlong/users/ent"
+	"github.com/smxlong/users/ent/role"
+)
+
+// EffectivePermission is a permission a user has, with the roles granting
+// it.
+type EffectivePermission struct {
+	Permission string
+	// Path is the chain of roles granting the permission. It starts with a
+	// role the user has, each role inherits the next, and the last role has
+	// the permission.
+	Path []string
+}
+
+// EffectivePermissions returns the permissions a user has through its roles
+// and the roles they inherit, ordered by name. Each permission is given with
+// the shortest role path granting it.
+func EffectivePermissions(ctx context.Context, client *ent.Client, u *ent.User) ([]*EffectivePermission, error) {
+	roles, err := client.User.QueryRoles(u).
+		Select(role.FieldName).
+		Strings(ctx)
+	if err != nil {
+		return nil, err
+	}
+	g, err := loadRoleAncestry(ctx, client, roles)
+	if err != nil {
+		return nil, err
+	}
+	return g.effectivePermissions(roles), nil
+}
+
+// roleGraph holds each role's parents and permissions, by name, to resolve
+// inheritance.
+type roleGraph map[string]*Role
+
+// loadRoleAncestry loads the named roles and the roles they inherit, with
+// their parents and permissions. Only those roles are loaded, one level of
+// inheritance at a time.
+func loadRoleAncestry(ctx context.Context, client *ent.Client, names []string) (roleGraph, error) {
+	g := roleGraph{}
+	for len(names) > 0 {
+		roles, err := client.Role.Query().
+			Where(role.NameIn(names...)).
+			WithParents().
+			WithPermissions().
+			All(ctx)
+		if err != nil {
+			return nil, err
+		}
+		for _, r := range roles {
+			g[r.Name] = entStoreRole(r, r.Edges.Permissions, r.Edges.Parents)
+		}
+		names = nil
+		for _, r := range roles {
+			for _, parent := range r.Edges.Parents {
+				if _, ok := g[parent.Name]; !ok && !slices.Contains(names, parent.Name) {
+					names = append(names, parent.Name)
+				}
+			}
+		}
+	}
+	return g, nil
+}
+
+// newRoleGraph returns the graph of the roles.
+func newRoleGraph(roles []*Role) roleGraph {
+	g := roleGraph{}
+	for _, r := range roles {
+		g[r.Name] = r
+	}
+	return g
+}
+
+// effectivePermissions returns the permissions granted by the roles and the
+// roles they inherit, ordered by name, each with the shortest role path
+// granting it. Roles are visited breadth first and in name order, so that
+// ties go to the first path by name.
+func (g roleGraph) effectivePermissions(roles []string) []*EffectivePermission {
+	granted := map[string]*EffectivePermission{}
+	visited := map[string]bool{}
+	var queue [][]string
+	for _, name := range slices.Sorted(slices.Values(roles)) {
+		queue = append(queue, []string{name})
+	}
+	for len(queue) > 0 {
+		path := queue[0]
+		queue = queue[1:]
+		name := path[len(path)-1]
+		r, ok := g[name]
+		if !ok || visited[name] {
+			continue
+		}
+		visited[name] = true
+		for _, p := range r.Permissions {
+			if _, ok := granted[p]; !ok {
+				granted[p] = &EffectivePermission{Permission: p, Path: path}
+			}
+		}
+		for _, parent := range slices.Sorted(slices.Values(r.Inherits)) {
+			queue = append(queue, append(slices.Clip(path), parent))
+		}
+	}
+	permissions := make([]*EffectivePermission, 0, len(granted))
+	for _, name := range slices.Sorted(maps.Keys(granted)) {
+		permissions = append(permissions, granted[name])
+	}
+	return permissions
+}
+
+// permissionNames returns the names of the permissions granted by the
+// roles and the roles they inherit, ordered by name.
+func (g roleGraph) permissionNames(roles []string) []string {
+	effective := g.effectivePermissions(roles)
+	names := make([]string, len(effective))
+	for i, p := range effective {
+		names[i] = p.Permission
+	}
+	return names
+}
+
+// inherits checks if a role inherits another, directly or indirectly.
+func (g roleGraph) inherits(name, ancestor string) bool {
+	visited := map[string]bool{}
+	queue := []string{name}
+	for len(queue) > 0 {
+		r, ok := g[queue[0]]
+		queue = queue[1:]
+		if !ok || visited[r.Name] {
+			continue
+		}
+		visited[r.Name] = true
+		for _, parent := range r.Inherits {
+			if parent == ancestor {
+				return true
+			}
+			queue = append(queue, parent)
+		}
+	}
+	return false
+}
+
+// checkParents returns ErrRoleCycle if the role inheriting the parents would
+// make a cycle.
+func (g roleGraph) checkParents(name string, parents []string) error {
+	for _, parent := range parents {
+		if parent == name || g.inherits(parent, name) {
+			return fmt.Errorf("%w: role %q inheriting %q", ErrRoleCycle, name, parent)
+		}
+	}
+	return nil
+}
+
+// checkCycles returns ErrRoleCycle if any role inherits itself.
+func (g roleGraph) checkCycles() error {
+	for _, name := range slices.Sorted(maps.Keys(g)) {
+		if g.inherits(name, name) {
+			return fmt.Errorf("%w: role %q inherits itself", ErrRoleCycle, name)
+		}
+	}
+	return nil
+}
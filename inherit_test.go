@@ -0,0 +1,133 @@
+package users
+
+import (
+	"context"
+	"testing"
+
+	"github.com/smxlong/users/ent"
+	"github.com/smxlong/users/ent/role"
+	"github.com/stretchr/testify/require"
+)
+
+var testInheritingRoles = Roles{
+	"viewer": &RoleWithPermissions{
+		Description: "Viewer role",
+		Permissions: []*Permission{{Name: "read", Description: "Read permission"}},
+	},
+	"editor": &RoleWithPermissions{
+		Description: "Editor role",
+		Permissions: []*Permission{{Name: "write", Description: "Write permission"}},
+		Inherits:    []string{"viewer"},
+	},
+	"admin": &RoleWithPermissions{
+		Description: "Admin role",
+		Permissions: []*Permission{{Name: "delete", Description: "Delete permission"}},
+		Inherits:    []string{"editor"},
+	},
+}
+
+func Test_that_CheckPermission_resolves_inherited_roles(t *testing.T) {
+	client := setupAndMigrate(t)
+	ctx := context.Background()
+	require.NoError(t, CreateRolesAndPermissions(ctx, client, testInheritingRoles))
+	u, err := Create(ctx, client, "user1", USER1_TEST_EMAIL, "password")
+	require.NoError(t, err)
+	admin, err := client.Role.Query().Where(role.Name("admin")).Only(ctx)
+	require.NoError(t, err)
+	u, err = AddRole(ctx, client, u, admin)
+	require.NoError(t, err)
+	ok, err := CheckPermission(ctx, client, u, "read", "write", "delete")
+	require.NoError(t, err)
+	require.True(t, ok)
+	ok, err = CheckPermission(ctx, client, u, "read", "other")
+	require.NoError(t, err)
+	require.False(t, ok)
+	effective, err := EffectivePermissions(ctx, client, u)
+	require.NoError(t, err)
+	require.Equal(t, []*EffectivePermission{
+		{Permission: "delete", Path: []string{"admin"}},
+		{Permission: "read", Path: []string{"admin", "editor", "viewer"}},
+		{Permission: "write", Path: []string{"admin", "editor"}},
+	}, effective)
+}
+
+func Test_that_EffectivePermissions_gives_the_shortest_path(t *testing.T) {
+	client := setupAndMigrate(t)
+	ctx := context.Background()
+	require.NoError(t, CreateRolesAndPermissions(ctx, client, testInheritingRoles))
+	u, err := Create(ctx, client, "user1", USER1_TEST_EMAIL, "password")
+	require.NoError(t, err)
+	roles, err := client.Role.Query().Where(role.NameIn("admin", "viewer")).All(ctx)
+	require.NoError(t, err)
+	u, err = u.Update().AddRoles(roles...).Save(ctx)
+	require.NoError(t, err)
+	effective, err := EffectivePermissions(ctx, client, u)
+	require.NoError(t, err)
+	require.Equal(t, []string{"viewer"}, effective[1].Path)
+}
+
+func Test_that_AddRoleParent_rejects_cycles(t *testing.T) {
+	client := setupAndMigrate(t)
+	ctx := context.Background()
+	require.NoError(t, CreateRolesAndPermissions(ctx, client, testInheritingRoles))
+	viewer, err := client.Role.Query().Where(role.Name("viewer")).Only(ctx)
+	require.NoError(t, err)
+	admin, err := client.Role.Query().Where(role.Name("admin")).Only(ctx)
+	require.NoError(t, err)
+	_, err = AddRoleParent(ctx, client, viewer, admin)
+	require.ErrorIs(t, err, ErrRoleCycle)
+	_, err = AddRoleParent(ctx, client, viewer, viewer)
+	require.ErrorIs(t, err, ErrRoleCycle)
+	_, err = SetRoleParents(ctx, client, viewer, []*ent.Role{admin})
+	require.ErrorIs(t, err, ErrRoleCycle)
+	// admin can inherit viewer directly as well as through editor
+	admin, err = AddRoleParent(ctx, client, admin, viewer)
+	require.NoError(t, err)
+	parents, err := admin.QueryParents().Order(role.ByName()).Select(role.FieldName).Strings(ctx)
+	require.NoError(t, err)
+	require.Equal(t, []string{"editor", "viewer"}, parents)
+	admin, err = RemoveRoleParent(ctx, client, admin, viewer)
+	require.NoError(t, err)
+	parents, err = admin.QueryParents().Select(role.FieldName).Strings(ctx)
+	require.NoError(t, err)
+	require.Equal(t, []string{"editor"}, parents)
+}
+
+func Test_that_CreateRolesAndPermissions_checks_inherited_roles(t *testing.T) {
+	client := setupAndMigrate(t)
+	ctx := context.Background()
+	err := CreateRolesAndPermissions(ctx, client, Roles{
+		"a": &RoleWithPermissions{Inherits: []string{"b"}},
+	})
+	require.ErrorIs(t, err, ErrRoleUnknown)
+	err = CreateRolesAndPermissions(ctx, client, Roles{
+		"a": &RoleWithPermissions{Inherits: []string{"b"}},
+		"b": &RoleWithPermissions{Inherits: []string{"a"}},
+	})
+	require.ErrorIs(t, err, ErrRoleCycle)
+	// reversing existing inheritance isn't a cycle
+	require.NoError(t, CreateRolesAndPermissions(ctx, client, Roles{
+		"a": &RoleWithPermissions{Inherits: []string{"b"}},
+		"b": &RoleWithPermissions{},
+	}))
+	require.NoError(t, CreateRolesAndPermissions(ctx, client, Roles{
+		"a": &RoleWithPermissions{},
+		"b": &RoleWithPermissions{Inherits: []string{"a"}},
+	}))
+	b, err := client.Role.Query().Where(role.Name("b")).Only(ctx)
+	require.NoError(t, err)
+	parents, err := b.QueryParents().Select(role.FieldName).Strings(ctx)
+	require.NoError(t, err)
+	require.Equal(t, []string{"a"}, parents)
+}
+
+func Test_that_loadRoleAncestry_loads_only_the_roles_and_their_ancestors(t *testing.T) {
+	client := setupAndMigrate(t)
+	ctx := context.Background()
+	require.NoError(t, CreateRolesAndPermissions(ctx, client, testInheritingRoles))
+	g, err := loadRoleAncestry(ctx, client, []string{"editor"})
+	require.NoError(t, err)
+	require.Len(t, g, 2)
+	require.Contains(t, g, "editor")
+	require.Contains(t, g, "viewer")
+}
@@ -0,0 +1,351 @@
+package users
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/smxlong/users/ent"
+	"github.com/smxlong/users/ent/lockout"
+)
+
+// LockoutState is the failed login state of a key.
+type LockoutState struct {
+	// Failures is the number of failed logins.
+	Failures int
+	// LastFailure is the time of the most recent failed login.
+	LastFailure time.Time
+	// LockedUntil is when the lockout expires. It is zero if the key has
+	// never been locked out.
+	LockedUntil time.Time
+}
+
+// LockoutStore stores failed login state by key. A key identifies a user or a
+// source of logins, such as a client IP address.
+type LockoutStore interface {
+	// Get returns the state of a key. A key with no failures has a zero
+	// state.
+	Get(ctx context.Context, key string) (*LockoutState, error)
+	// RecordFailure atomically adds a failed login at the given time, and
+	// returns the new state.
+	RecordFailure(ctx context.Context, key string, at time.Time) (*LockoutState, error)
+	// Lock locks out a key until the given time.
+	Lock(ctx context.Context, key string, until time.Time) error
+	// Reset clears the state of a key.
+	Reset(ctx context.Context, key string) error
+}
+
+// LockoutPolicy throttles password guessing. Failed logins are counted per
+// user and per source. Once either count reaches its threshold, logins are
+// rejected for Duration, as WithLockout describes. Each further failure
+// doubles the duration, up to MaxDuration. Counts are forgotten after Window
+// passes without a failure, and a successful login resets the user's count.
+type LockoutPolicy struct {
+	// Store holds the failed login state. Required. Use an EntLockoutStore to
+	// share state between replicas.
+	Store LockoutStore
+	// Threshold is the number of failures for a user before it is locked
+	// out. Optional. If not set, defaults to 5.
+	Threshold int
+	// SourceThreshold is the number of failures from a source before it is
+	// locked out. Optional. If not set, defaults to 20.
+	SourceThreshold int
+	// Duration is the first lockout duration. Optional. If not set, defaults
+	// to 1 minute.
+	Duration time.Duration
+	// MaxDuration is the maximum lockout duration. Optional. If not set,
+	// defaults to 1 hour.
+	MaxDuration time.Duration
+	// Window is how long failures are remembered. Optional. If not set,
+	// defaults to 24 hours.
+	Window time.Duration
+}
+
+// GetThreshold returns the LockoutPolicy Threshold, or the default if not set.
+func (p *LockoutPolicy) GetThreshold() int {
+	if p.Threshold == 0 {
+		return 5
+	}
+	return p.Threshold
+}
+
+// GetSourceThreshold returns the LockoutPolicy SourceThreshold, or the default
+// if not set.
+func (p *LockoutPolicy) GetSourceThreshold() int {
+	if p.SourceThreshold == 0 {
+		return 20
+	}
+	return p.SourceThreshold
+}
+
+// GetDuration returns the LockoutPolicy Duration, or the default if not set.
+func (p *LockoutPolicy) GetDuration() time.Duration {
+	if p.Duration == 0 {
+		return time.Minute
+	}
+	return p.Duration
+}
+
+// GetMaxDuration returns the LockoutPolicy MaxDuration, or the default if not
+// set.
+func (p *LockoutPolicy) GetMaxDuration() time.Duration {
+	if p.MaxDuration == 0 {
+		return time.Hour
+	}
+	return p.MaxDuration
+}
+
+// GetWindow returns the LockoutPolicy Window, or the default if not set.
+func (p *LockoutPolicy) GetWindow() time.Duration {
+	if p.Window == 0 {
+		return 24 * time.Hour
+	}
+	return p.Window
+}
+
+// Unlock clears the failed logins of the user with the ID, ending any
+// lockout. For ent users, the ID is strconv.Itoa(u.ID), as for an EntStore.
+func (p *LockoutPolicy) Unlock(ctx context.Context, id string) error {
+	return p.Store.Reset(ctx, lockoutUserKey(id))
+}
+
+// UnlockSource clears a source's failed logins, ending any lockout.
+func (p *LockoutPolicy) UnlockSource(ctx context.Context, source string) error {
+	return p.Store.Reset(ctx, lockoutSourceKey(source))
+}
+
+// LockedUntil returns when the lockout of the user with the ID expires, or
+// the zero time if it isn't locked out.
+func (p *LockoutPolicy) LockedUntil(ctx context.Context, id string) (time.Time, error) {
+	return p.lockedUntil(ctx, lockoutUserKey(id), clockNow(ctx))
+}
+
+// checkSource returns an error wrapping ErrAccountLocked if the source is
+// locked out. The source may be empty.
+func (p *LockoutPolicy) checkSource(ctx context.Context, source string) error {
+	if source == "" {
+		return nil
+	}
+	until, err := p.lockedUntil(ctx, lockoutSourceKey(source), clockNow(ctx))
+	if err != nil {
+		return err
+	}
+	if !until.IsZero() {
+		return lockedError(until)
+	}
+	return nil
+}
+
+// lockedError returns an error wrapping ErrAccountLocked for a lockout
+// expiring at until.
+func lockedError(until time.Time) error {
+	return fmt.Errorf("%w until %s", ErrAccountLocked, until.Format(time.RFC3339))
+}
+
+// lockedUntil returns when a key's lockout expires, or the zero time if it
+// isn't locked out.
+func (p *LockoutPolicy) lockedUntil(ctx context.Context, key string, now time.Time) (time.Time, error) {
+	state, err := p.Store.Get(ctx, key)
+	if err != nil {
+		return time.Time{}, err
+	}
+	if now.Before(state.LockedUntil) {
+		return state.LockedUntil, nil
+	}
+	return time.Time{}, nil
+}
+
+// fail records a failed login for the user with the ID and the source,
+// locking out either if it has reached its threshold. Either may be empty.
+func (p *LockoutPolicy) fail(ctx context.Context, id, source string) error {
+	now := clockNow(ctx)
+	if id != "" {
+		if err := p.record(ctx, lockoutUserKey(id), p.GetThreshold(), now); err != nil {
+			return err
+		}
+	}
+	if source != "" {
+		if err := p.record(ctx, lockoutSourceKey(source), p.GetSourceThreshold(), now); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// record records a failed login for a key, locking it out if it has reached
+// the threshold.
+func (p *LockoutPolicy) record(ctx context.Context, key string, threshold int, now time.Time) error {
+	state, err := p.Store.Get(ctx, key)
+	if err != nil {
+		return err
+	}
+	if state.Failures > 0 && now.Sub(state.LastFailure) >= p.GetWindow() {
+		if err := p.Store.Reset(ctx, key); err != nil {
+			return err
+		}
+	}
+	state, err = p.Store.RecordFailure(ctx, key, now)
+	if err != nil {
+		return err
+	}
+	if state.Failures < threshold {
+		return nil
+	}
+	return p.Store.Lock(ctx, key, now.Add(p.backoff(state.Failures-threshold)))
+}
+
+// backoff returns the lockout duration after n failures past the threshold.
+func (p *LockoutPolicy) backoff(n int) time.Duration {
+	d := p.GetDuration()
+	for i := 0; i < n && d < p.GetMaxDuration(); i++ {
+		d *= 2
+	}
+	return min(d, p.GetMaxDuration())
+}
+
+// succeed resets the failed logins of the user with the ID. The source's are
+// kept, so that a successful login to one account doesn't reset guessing
+// against others.
+func (p *LockoutPolicy) succeed(ctx context.Context, id string) error {
+	return p.Store.Reset(ctx, lockoutUserKey(id))
+}
+
+// lockoutUserKey returns the key for the user with the ID.
+func lockoutUserKey(id string) string {
+	return "user:" + id
+}
+
+// lockoutSourceKey returns the key for a source.
+func lockoutSourceKey(source string) string {
+	return "source:" + source
+}
+
+// MemoryLockoutStore is a LockoutStore that keeps state in memory. State is
+// not shared between processes.
+type MemoryLockoutStore struct {
+	mu     sync.Mutex
+	states map[string]LockoutState
+}
+
+// Get returns the state of a key.
+func (s *MemoryLockoutStore) Get(ctx context.Context, key string) (*LockoutState, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	state := s.states[key]
+	return &state, nil
+}
+
+// RecordFailure adds a failed login.
+func (s *MemoryLockoutStore) RecordFailure(ctx context.Context, key string, at time.Time) (*LockoutState, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.states == nil {
+		s.states = map[string]LockoutState{}
+	}
+	state := s.states[key]
+	state.Failures++
+	state.LastFailure = at
+	s.states[key] = state
+	return &state, nil
+}
+
+// Lock locks out a key.
+func (s *MemoryLockoutStore) Lock(ctx context.Context, key string, until time.Time) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.states == nil {
+		s.states = map[string]LockoutState{}
+	}
+	state := s.states[key]
+	state.LockedUntil = until
+	s.states[key] = state
+	return nil
+}
+
+// Reset clears the state of a key.
+func (s *MemoryLockoutStore) Reset(ctx context.Context, key string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	delete(s.states, key)
+	return nil
+}
+
+// EntLockoutStore is a LockoutStore that keeps state in the database, so that
+// it is shared between replicas.
+type EntLockoutStore struct {
+	client *ent.Client
+}
+
+// NewEntLockoutStore returns an EntLockoutStore using the client.
+func NewEntLockoutStore(client *ent.Client) *EntLockoutStore {
+	return &EntLockoutStore{client: client}
+}
+
+// Get returns the state of a key.
+func (s *EntLockoutStore) Get(ctx context.Context, key string) (*LockoutState, error) {
+	l, err := s.client.Lockout.Query().
+		Where(lockout.Key(key)).
+		Only(ctx)
+	if ent.IsNotFound(err) {
+		return &LockoutState{}, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	state := &LockoutState{
+		Failures:    l.Failures,
+		LastFailure: l.LastFailureAt,
+	}
+	if l.LockedUntil != nil {
+		state.LockedUntil = *l.LockedUntil
+	}
+	return state, nil
+}
+
+// RecordFailure adds a failed login. The count is incremented in the
+// database, so concurrent failures are all counted.
+func (s *EntLockoutStore) RecordFailure(ctx context.Context, key string, at time.Time) (*LockoutState, error) {
+	for {
+		n, err := s.client.Lockout.Update().
+			Where(lockout.Key(key)).
+			AddFailures(1).
+			SetLastFailureAt(at).
+			Save(ctx)
+		if err != nil {
+			return nil, err
+		}
+		if n > 0 {
+			return s.Get(ctx, key)
+		}
+		err = s.client.Lockout.Create().
+			SetKey(key).
+			SetFailures(1).
+			SetLastFailureAt(at).
+			Exec(ctx)
+		// If a concurrent failure created the key first, update it instead.
+		if ent.IsConstraintError(err) {
+			continue
+		}
+		if err != nil {
+			return nil, err
+		}
+		return s.Get(ctx, key)
+	}
+}
+
+// Lock locks out a key.
+func (s *EntLockoutStore) Lock(ctx context.Context, key string, until time.Time) error {
+	return s.client.Lockout.Update().
+		Where(lockout.Key(key)).
+		SetLockedUntil(until).
+		Exec(ctx)
+}
+
+// Reset clears the state of a key.
+func (s *EntLockoutStore) Reset(ctx context.Context, key string) error {
+	_, err := s.client.Lockout.Delete().
+		Where(lockout.Key(key)).
+		Exec(ctx)
+	return err
+}
@@ -0,0 +1,161 @@
+package users
+
+import (
+	"context"
+	"strconv"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func Test_that_LoginByName_locks_out_after_threshold(t *testing.T) {
+	client := setupAndMigrate(t)
+	ctx := context.Background()
+	_, err := Create(ctx, client, "user1", USER1_TEST_EMAIL, "password")
+	require.NoError(t, err)
+	policy := &LockoutPolicy{Store: NewEntLockoutStore(client), Threshold: 3}
+	for i := 0; i < 3; i++ {
+		_, err = LoginByName(ctx, client, "user1", "wrong", WithLockout(policy, ""))
+		require.ErrorIs(t, err, ErrInvalidCredentials)
+	}
+	// even the right password is rejected while locked out, with an error
+	// saying so
+	_, err = LoginByName(ctx, client, "user1", "password", WithLockout(policy, ""))
+	require.ErrorIs(t, err, ErrAccountLocked)
+	require.NotErrorIs(t, err, ErrInvalidCredentials)
+}
+
+func Test_that_locked_out_user_is_indistinguishable_from_unknown_user_without_the_password(t *testing.T) {
+	client := setupAndMigrate(t)
+	ctx := context.Background()
+	u, err := Create(ctx, client, "user1", USER1_TEST_EMAIL, "password")
+	require.NoError(t, err)
+	policy := &LockoutPolicy{Store: &MemoryLockoutStore{}, Threshold: 1, Duration: time.Minute}
+	_, err = LoginByName(ctx, client, "user1", "wrong", WithLockout(policy, ""))
+	require.ErrorIs(t, err, ErrInvalidCredentials)
+	until, err := policy.LockedUntil(ctx, strconv.Itoa(u.ID))
+	require.NoError(t, err)
+	_, err = LoginByName(ctx, client, "user1", "wrong", WithLockout(policy, ""))
+	require.Equal(t, ErrInvalidCredentials, err)
+	_, err = LoginByName(ctx, client, "nobody", "password", WithLockout(policy, ""))
+	require.Equal(t, ErrInvalidCredentials, err)
+	// guesses while locked out don't extend the lockout
+	after, err := policy.LockedUntil(ctx, strconv.Itoa(u.ID))
+	require.NoError(t, err)
+	require.Equal(t, until, after)
+}
+
+func Test_that_LockoutPolicy_Unlock_ends_lockout(t *testing.T) {
+	client := setupAndMigrate(t)
+	ctx := context.Background()
+	u, err := Create(ctx, client, "user1", USER1_TEST_EMAIL, "password")
+	require.NoError(t, err)
+	policy := &LockoutPolicy{Store: NewEntLockoutStore(client), Threshold: 1}
+	_, err = Login(ctx, u, "wrong", WithLockout(policy, ""))
+	require.ErrorIs(t, err, ErrInvalidCredentials)
+	until, err := policy.LockedUntil(ctx, strconv.Itoa(u.ID))
+	require.NoError(t, err)
+	require.False(t, until.IsZero())
+	require.NoError(t, policy.Unlock(ctx, strconv.Itoa(u.ID)))
+	_, err = Login(ctx, u, "password", WithLockout(policy, ""))
+	require.NoError(t, err)
+}
+
+func Test_that_lockout_ends_after_its_duration(t *testing.T) {
+	client := setupAndMigrate(t)
+	clock := NewFakeClock(time.Date(2024, 1, 2, 3, 4, 5, 0, time.UTC))
+	ctx := ContextWithClock(context.Background(), clock)
+	u, err := Create(ctx, client, "user1", USER1_TEST_EMAIL, "password")
+	require.NoError(t, err)
+	policy := &LockoutPolicy{Store: &MemoryLockoutStore{}, Threshold: 1, Duration: time.Minute}
+	_, err = Login(ctx, u, "wrong", WithLockout(policy, ""))
+	require.ErrorIs(t, err, ErrInvalidCredentials)
+	until, err := policy.LockedUntil(ctx, strconv.Itoa(u.ID))
+	require.NoError(t, err)
+	require.Equal(t, clock.Now().Add(time.Minute), until)
+	clock.Advance(time.Minute - time.Second)
+	_, err = Login(ctx, u, "password", WithLockout(policy, ""))
+	require.ErrorIs(t, err, ErrAccountLocked)
+	clock.Advance(time.Second)
+	_, err = Login(ctx, u, "password", WithLockout(policy, ""))
+	require.NoError(t, err)
+}
+
+func Test_that_successful_login_resets_failures(t *testing.T) {
+	client := setupAndMigrate(t)
+	ctx := context.Background()
+	u, err := Create(ctx, client, "user1", USER1_TEST_EMAIL, "password")
+	require.NoError(t, err)
+	policy := &LockoutPolicy{Store: &MemoryLockoutStore{}, Threshold: 2}
+	_, err = Login(ctx, u, "wrong", WithLockout(policy, ""))
+	require.Error(t, err)
+	_, err = Login(ctx, u, "password", WithLockout(policy, ""))
+	require.NoError(t, err)
+	_, err = Login(ctx, u, "wrong", WithLockout(policy, ""))
+	require.ErrorIs(t, err, ErrInvalidCredentials)
+	_, err = Login(ctx, u, "password", WithLockout(policy, ""))
+	require.NoError(t, err)
+}
+
+func Test_that_source_is_locked_out_across_users(t *testing.T) {
+	client := setupAndMigrate(t)
+	ctx := context.Background()
+	_, err := Create(ctx, client, "user1", USER1_TEST_EMAIL, "password")
+	require.NoError(t, err)
+	policy := &LockoutPolicy{Store: &MemoryLockoutStore{}, SourceThreshold: 2}
+	_, err = LoginByEmail(ctx, client, USER2_TEST_EMAIL, "wrong", WithLockout(policy, "10.0.0.1"))
+	require.Error(t, err)
+	_, err = LoginByEmail(ctx, client, "nobody@example.com", "wrong", WithLockout(policy, "10.0.0.1"))
+	require.Error(t, err)
+	_, err = LoginByEmail(ctx, client, USER1_TEST_EMAIL, "password", WithLockout(policy, "10.0.0.1"))
+	require.ErrorIs(t, err, ErrAccountLocked)
+	// other sources are unaffected
+	_, err = LoginByEmail(ctx, client, USER1_TEST_EMAIL, "password", WithLockout(policy, "10.0.0.2"))
+	require.NoError(t, err)
+	require.NoError(t, policy.UnlockSource(ctx, "10.0.0.1"))
+	_, err = LoginByEmail(ctx, client, USER1_TEST_EMAIL, "password", WithLockout(policy, "10.0.0.1"))
+	require.NoError(t, err)
+}
+
+func Test_that_LockoutPolicy_backoff_doubles_up_to_max(t *testing.T) {
+	policy := &LockoutPolicy{Duration: time.Minute, MaxDuration: 5 * time.Minute}
+	require.Equal(t, time.Minute, policy.backoff(0))
+	require.Equal(t, 2*time.Minute, policy.backoff(1))
+	require.Equal(t, 4*time.Minute, policy.backoff(2))
+	require.Equal(t, 5*time.Minute, policy.backoff(3))
+	require.Equal(t, 5*time.Minute, policy.backoff(100))
+}
+
+func Test_that_LockoutPolicy_forgets_failures_after_window(t *testing.T) {
+	ctx := context.Background()
+	store := &MemoryLockoutStore{}
+	policy := &LockoutPolicy{Store: store, Threshold: 2, Window: time.Hour}
+	now := time.Now()
+	require.NoError(t, policy.record(ctx, "k", 2, now.Add(-2*time.Hour)))
+	require.NoError(t, policy.record(ctx, "k", 2, now))
+	state, err := store.Get(ctx, "k")
+	require.NoError(t, err)
+	require.Equal(t, 1, state.Failures)
+	require.True(t, state.LockedUntil.IsZero())
+}
+
+func Test_that_EntLockoutStore_counts_concurrent_failures(t *testing.T) {
+	client := setupAndMigrate(t)
+	ctx := context.Background()
+	store := NewEntLockoutStore(client)
+	var wg sync.WaitGroup
+	for i := 0; i < 10; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			_, err := store.RecordFailure(ctx, "k", time.Now())
+			require.NoError(t, err)
+		}()
+	}
+	wg.Wait()
+	state, err := store.Get(ctx, "k")
+	require.NoError(t, err)
+	require.Equal(t, 10, state.Failures)
+}
@@ -0,0 +1,62 @@
+package users
+
+import (
+	"context"
+	"time"
+
+	"github.com/smxlong/users/ent"
+	"github.com/smxlong/users/ent/onetimetoken"
+)
+
+// magicLinkValidFor is the default validity of magic link tokens.
+const magicLinkValidFor = 15 * time.Minute
+
+// RequestMagicLink emails a one-click login link to the user with the given
+// email address. To avoid revealing which addresses have accounts, it returns
+// nil whether or not the user exists, and the link is sent in the background,
+// with errors passed to the EmailOptions OnError. The link is issued with the
+// client, so that it is part of the caller's transaction, if any. If device
+// is not empty, the link is bound to it and only works when consumed with the
+// same device, for example a random value kept in a cookie by the browser
+// that requested the link.
+func RequestMagicLink(ctx context.Context, client *ent.Client, email, device string, opts *EmailOptions) error {
+	u, err := FindByEmail(ctx, client, email)
+	if ent.IsNotFound(err) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	return opts.sendInBackground(ctx, client, u, onetimetoken.PurposeMagicLink, device,
+		opts.getSubject("Your login link"),
+		"Follow this link to log in:",
+		opts.getValidFor(magicLinkValidFor))
+}
+
+// ConsumeMagicLink checks a magic link token and returns the user it logs in.
+// Device must be the value given to RequestMagicLink, if any. The login is
+// recorded as Login records it, with the options.
+func ConsumeMagicLink(ctx context.Context, client *ent.Client, token, device string, loginOpts ...LoginOption) (*ent.User, error) {
+	u, err := consumeOneTimeToken(ctx, client, token, onetimetoken.PurposeMagicLink, device)
+	if err != nil {
+		return nil, err
+	}
+	if err := CheckStatus(ctx, u); err != nil {
+		return nil, err
+	}
+	return recordEntLogin(ctx, client, u, loginOpts)
+}
+
+// ConsumeMagicLinkToken is like ConsumeMagicLink, and also creates a token
+// for the user as NewToken does.
+func ConsumeMagicLinkToken(ctx context.Context, client *ent.Client, token, device string, opts *TokenOptions, loginOpts ...LoginOption) (*ent.User, string, error) {
+	u, err := ConsumeMagicLink(ctx, client, token, device, loginOpts...)
+	if err != nil {
+		return nil, "", err
+	}
+	tok, err := NewTokenContext(ctx, u, opts)
+	if err != nil {
+		return nil, "", err
+	}
+	return u, tok, nil
+}
@@ -0,0 +1,123 @@
+package users
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func Test_that_RequestMagicLink_emails_a_link(t *testing.T) {
+	client := setupAndMigrate(t)
+	ctx := context.Background()
+	_, err := Create(ctx, client, "user1", USER1_TEST_EMAIL, "password")
+	require.NoError(t, err)
+	m := &MemoryMailer{}
+	require.NoError(t, RequestMagicLink(ctx, client, USER1_TEST_EMAIL, "", testEmailOptions(m)))
+	msgs := testWaitForMessages(t, m, 1)
+	require.Len(t, msgs, 1)
+	require.Equal(t, []string{USER1_TEST_EMAIL}, msgs[0].To)
+	require.Equal(t, "Your login link", msgs[0].Subject)
+}
+
+func Test_that_RequestMagicLink_responds_identically_for_unknown_email(t *testing.T) {
+	client := setupAndMigrate(t)
+	ctx := context.Background()
+	m := &MemoryMailer{}
+	require.NoError(t, RequestMagicLink(ctx, client, USER2_TEST_EMAIL, "", testEmailOptions(m)))
+	require.Empty(t, m.Messages())
+}
+
+func Test_that_RequestMagicLink_passes_mailer_errors_to_OnError(t *testing.T) {
+	client := setupAndMigrate(t)
+	ctx := context.Background()
+	_, err := Create(ctx, client, "user1", USER1_TEST_EMAIL, "password")
+	require.NoError(t, err)
+	errs := make(chan error, 1)
+	opts := testEmailOptions(failingMailer{})
+	opts.OnError = func(err error) { errs <- err }
+	require.NoError(t, RequestMagicLink(ctx, client, USER1_TEST_EMAIL, "", opts))
+	require.ErrorIs(t, <-errs, errTestMailer)
+}
+
+func Test_that_ConsumeMagicLink_works_once(t *testing.T) {
+	client := setupAndMigrate(t)
+	ctx := context.Background()
+	u, err := Create(ctx, client, "user1", USER1_TEST_EMAIL, "password")
+	require.NoError(t, err)
+	m := &MemoryMailer{}
+	require.NoError(t, RequestMagicLink(ctx, client, USER1_TEST_EMAIL, "", testEmailOptions(m)))
+	testWaitForMessages(t, m, 1)
+	token := testEmailedToken(t, m.Messages()[0])
+	u2, err := ConsumeMagicLink(ctx, client, token, "")
+	require.NoError(t, err)
+	require.Equal(t, u.ID, u2.ID)
+	_, err = ConsumeMagicLink(ctx, client, token, "")
+	require.ErrorIs(t, err, ErrTokenInvalid)
+}
+
+func Test_that_ConsumeMagicLink_records_the_login(t *testing.T) {
+	client := setupAndMigrate(t)
+	clock := NewFakeClock(time.Now())
+	ctx := ContextWithClock(context.Background(), clock)
+	_, err := Create(ctx, client, "user1", USER1_TEST_EMAIL, "password")
+	require.NoError(t, err)
+	_, err = LoginByName(ctx, client, "user1", "wrong password")
+	require.ErrorIs(t, err, ErrInvalidCredentials)
+	m := &MemoryMailer{}
+	require.NoError(t, RequestMagicLink(ctx, client, USER1_TEST_EMAIL, "", testEmailOptions(m)))
+	testWaitForMessages(t, m, 1)
+	u, err := ConsumeMagicLink(ctx, client, testEmailedToken(t, m.Messages()[0]), "", WithIP("192.0.2.1"))
+	require.NoError(t, err)
+	require.NotNil(t, u.LastLoginAt)
+	require.True(t, clock.Now().Equal(*u.LastLoginAt))
+	require.Equal(t, "192.0.2.1", u.LastLoginIP)
+	require.Zero(t, u.FailedLoginCount)
+}
+
+func Test_that_ConsumeMagicLink_enforces_device_binding(t *testing.T) {
+	client := setupAndMigrate(t)
+	ctx := context.Background()
+	u, err := Create(ctx, client, "user1", USER1_TEST_EMAIL, "password")
+	require.NoError(t, err)
+	m := &MemoryMailer{}
+	require.NoError(t, RequestMagicLink(ctx, client, USER1_TEST_EMAIL, "device1", testEmailOptions(m)))
+	testWaitForMessages(t, m, 1)
+	token := testEmailedToken(t, m.Messages()[0])
+	_, err = ConsumeMagicLink(ctx, client, token, "")
+	require.ErrorIs(t, err, ErrTokenDeviceMismatch)
+	_, err = ConsumeMagicLink(ctx, client, token, "device2")
+	require.ErrorIs(t, err, ErrTokenDeviceMismatch)
+	// failed attempts from other devices don't use up the token
+	u2, err := ConsumeMagicLink(ctx, client, token, "device1")
+	require.NoError(t, err)
+	require.Equal(t, u.ID, u2.ID)
+}
+
+func Test_that_ConsumeMagicLink_rejects_other_token_purposes(t *testing.T) {
+	client := setupAndMigrate(t)
+	ctx := context.Background()
+	u, err := Create(ctx, client, "user1", USER1_TEST_EMAIL, "password")
+	require.NoError(t, err)
+	token, err := NewEmailVerificationToken(ctx, client, u, 0)
+	require.NoError(t, err)
+	_, err = ConsumeMagicLink(ctx, client, token, "")
+	require.ErrorIs(t, err, ErrTokenInvalid)
+}
+
+func Test_that_ConsumeMagicLinkToken_returns_a_valid_token(t *testing.T) {
+	client := setupAndMigrate(t)
+	ctx := context.Background()
+	u, err := Create(ctx, client, "user1", USER1_TEST_EMAIL, "password")
+	require.NoError(t, err)
+	m := &MemoryMailer{}
+	require.NoError(t, RequestMagicLink(ctx, client, USER1_TEST_EMAIL, "", testEmailOptions(m)))
+	testWaitForMessages(t, m, 1)
+	opts := &TokenOptions{Secret: "foo"}
+	_, tok, err := ConsumeMagicLinkToken(ctx, client, testEmailedToken(t, m.Messages()[0]), "", opts)
+	require.NoError(t, err)
+	u2, err := ValidateToken(ctx, client, tok, opts)
+	require.NoError(t, err)
+	require.Equal(t, u.ID, u2.ID)
+}
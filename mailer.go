@@ -0,0 +1,130 @@
+package users
+
+import (
+	"context"
+	"crypto/tls"
+	"fmt"
+	"net"
+	"net/smtp"
+	"strings"
+	"sync"
+)
+
+// Message is an email message.
+type Message struct {
+	From    string
+	To      []string
+	Subject string
+	// Body is the plain text body.
+	Body string
+}
+
+// Bytes formats the message for transmission.
+func (m *Message) Bytes() []byte {
+	var b strings.Builder
+	fmt.Fprintf(&b, "From: %s\r\n", m.From)
+	fmt.Fprintf(&b, "To: %s\r\n", strings.Join(m.To, ", "))
+	fmt.Fprintf(&b, "Subject: %s\r\n", m.Subject)
+	b.WriteString("MIME-Version: 1.0\r\n")
+	b.WriteString("Content-Type: text/plain; charset=utf-8\r\n")
+	b.WriteString("\r\n")
+	b.WriteString(strings.ReplaceAll(m.Body, "\n", "\r\n"))
+	return []byte(b.String())
+}
+
+// Mailer sends email.
+type Mailer interface {
+	Send(ctx context.Context, msg *Message) error
+}
+
+// MemoryMailer is a Mailer that keeps messages in memory instead of sending
+// them. It is intended for tests.
+type MemoryMailer struct {
+	mu       sync.Mutex
+	messages []*Message
+}
+
+// Send records the message.
+func (m *MemoryMailer) Send(ctx context.Context, msg *Message) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.messages = append(m.messages, msg)
+	return nil
+}
+
+// Messages returns the messages sent so far.
+func (m *MemoryMailer) Messages() []*Message {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return append([]*Message(nil), m.messages...)
+}
+
+// SMTPMailer is a Mailer that sends messages through an SMTP server. STARTTLS
+// is used if the server supports it.
+type SMTPMailer struct {
+	// Addr is the server's host:port. Required.
+	Addr string
+	// Auth authenticates with the server. Optional.
+	Auth smtp.Auth
+	// TLSConfig is used for STARTTLS. Optional. If not set, the server name
+	// is taken from Addr.
+	TLSConfig *tls.Config
+}
+
+// Send sends the message.
+func (m *SMTPMailer) Send(ctx context.Context, msg *Message) error {
+	host, _, err := net.SplitHostPort(m.Addr)
+	if err != nil {
+		return err
+	}
+	var d net.Dialer
+	conn, err := d.DialContext(ctx, "tcp", m.Addr)
+	if err != nil {
+		return err
+	}
+	if deadline, ok := ctx.Deadline(); ok {
+		if err := conn.SetDeadline(deadline); err != nil {
+			conn.Close()
+			return err
+		}
+	}
+	c, err := smtp.NewClient(conn, host)
+	if err != nil {
+		conn.Close()
+		return err
+	}
+	defer c.Close()
+	if ok, _ := c.Extension("STARTTLS"); ok {
+		config := m.TLSConfig
+		if config == nil {
+			config = &tls.Config{ServerName: host}
+		}
+		if err := c.StartTLS(config); err != nil {
+			return err
+		}
+	}
+	if m.Auth != nil {
+		if err := c.Auth(m.Auth); err != nil {
+			return err
+		}
+	}
+	if err := c.Mail(msg.From); err != nil {
+		return err
+	}
+	for _, to := range msg.To {
+		if err := c.Rcpt(to); err != nil {
+			return err
+		}
+	}
+	w, err := c.Data()
+	if err != nil {
+		return err
+	}
+	if _, err := w.Write(msg.Bytes()); err != nil {
+		return err
+	}
+	if err := w.Close(); err != nil {
+		return err
+	}
+	return c.Quit()
+}
@@ -0,0 +1,130 @@
+package users
+
+import (
+	"context"
+	"errors"
+	"net"
+	"net/textproto"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+// errTestMailer is the error from failingMailer.
+var errTestMailer = errors.New("mailer failed")
+
+// failingMailer is a Mailer that fails to send anything.
+type failingMailer struct{}
+
+// Send fails with errTestMailer.
+func (failingMailer) Send(ctx context.Context, msg *Message) error {
+	return errTestMailer
+}
+
+// fakeSMTPMessage is a message received by the fake SMTP server.
+type fakeSMTPMessage struct {
+	From string
+	To   []string
+	Data string
+}
+
+// newFakeSMTPServer starts a minimal SMTP server on a local port. Received
+// messages are delivered on the returned channel.
+func newFakeSMTPServer(t *testing.T) (string, <-chan *fakeSMTPMessage) {
+	l, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	t.Cleanup(func() { l.Close() })
+	messages := make(chan *fakeSMTPMessage, 10)
+	go func() {
+		for {
+			conn, err := l.Accept()
+			if err != nil {
+				return
+			}
+			go serveFakeSMTP(textproto.NewConn(conn), messages)
+		}
+	}()
+	return l.Addr().String(), messages
+}
+
+func serveFakeSMTP(c *textproto.Conn, messages chan<- *fakeSMTPMessage) {
+	defer c.Close()
+	msg := &fakeSMTPMessage{}
+	_ = c.PrintfLine("220 localhost ESMTP")
+	for {
+		line, err := c.ReadLine()
+		if err != nil {
+			return
+		}
+		cmd := strings.ToUpper(strings.SplitN(line, " ", 2)[0])
+		switch cmd {
+		case "EHLO", "HELO":
+			_ = c.PrintfLine("250 localhost")
+		case "MAIL":
+			msg.From = strings.Trim(strings.TrimPrefix(line[5:], "FROM:"), "<>")
+			_ = c.PrintfLine("250 OK")
+		case "RCPT":
+			msg.To = append(msg.To, strings.Trim(strings.TrimPrefix(line[5:], "TO:"), "<>"))
+			_ = c.PrintfLine("250 OK")
+		case "DATA":
+			_ = c.PrintfLine("354 Go ahead")
+			data, err := c.ReadDotBytes()
+			if err != nil {
+				return
+			}
+			msg.Data = string(data)
+			messages <- msg
+			msg = &fakeSMTPMessage{}
+			_ = c.PrintfLine("250 OK")
+		case "QUIT":
+			_ = c.PrintfLine("221 Bye")
+			return
+		default:
+			_ = c.PrintfLine("502 Not implemented")
+		}
+	}
+}
+
+func Test_that_MemoryMailer_records_messages(t *testing.T) {
+	m := &MemoryMailer{}
+	require.NoError(t, m.Send(context.Background(), &Message{Subject: "one"}))
+	require.NoError(t, m.Send(context.Background(), &Message{Subject: "two"}))
+	msgs := m.Messages()
+	require.Len(t, msgs, 2)
+	require.Equal(t, "one", msgs[0].Subject)
+	require.Equal(t, "two", msgs[1].Subject)
+}
+
+func Test_that_SMTPMailer_sends_messages(t *testing.T) {
+	addr, messages := newFakeSMTPServer(t)
+	m := &SMTPMailer{Addr: addr}
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	err := m.Send(ctx, &Message{
+		From:    "noreply@example.com",
+		To:      []string{USER1_TEST_EMAIL, USER2_TEST_EMAIL},
+		Subject: "Hello",
+		Body:    "line 1\nline 2\n",
+	})
+	require.NoError(t, err)
+	select {
+	case msg := <-messages:
+		require.Equal(t, "noreply@example.com", msg.From)
+		require.Equal(t, []string{USER1_TEST_EMAIL, USER2_TEST_EMAIL}, msg.To)
+		require.Contains(t, msg.Data, "Subject: Hello\n")
+		require.Contains(t, msg.Data, "\nline 1\nline 2\n")
+	case <-ctx.Done():
+		t.Fatal("message not received")
+	}
+}
+
+func Test_that_SMTPMailer_fails_when_server_unavailable(t *testing.T) {
+	l, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	addr := l.Addr().String()
+	l.Close()
+	m := &SMTPMailer{Addr: addr}
+	require.Error(t, m.Send(context.Background(), &Message{From: "a@example.com", To: []string{USER1_TEST_EMAIL}}))
+}
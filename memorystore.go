@@ -0,0 +1,599 @@
+package users
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"sort"
+	"strconv"
+	"sync"
+	"time"
+)
+
+// MemoryStore is a Store that keeps everything in memory. It is safe for
+// concurrent use. It is intended for tests and ephemeral deployments.
+type MemoryStore struct {
+	mu          sync.RWMutex
+	nextID      int
+	users       map[string]*memoryUser
+	roles       map[string]*Role
+	permissions map[string]*Permission
+}
+
+// memoryUser is a user held by a MemoryStore.
+type memoryUser struct {
+	user       User
+	roles      map[string]bool
+	rolesOn    map[Resource]map[string]bool
+	generation int
+	// deleted is set when the user is deleted. Deleted users are kept, so
+	// that their names and email addresses can't be reused.
+	deleted bool
+}
+
+// NewMemoryStore returns an empty MemoryStore.
+func NewMemoryStore() *MemoryStore {
+	return &MemoryStore{
+		users:       map[string]*memoryUser{},
+		roles:       map[string]*Role{},
+		permissions: map[string]*Permission{},
+	}
+}
+
+// CreateUser creates a user.
+func (s *MemoryStore) CreateUser(ctx context.Context, u *User) (*User, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if err := s.checkUnique(u, ""); err != nil {
+		return nil, err
+	}
+	s.nextID++
+	mu := &memoryUser{
+		user:    *copyUser(u),
+		roles:   map[string]bool{},
+		rolesOn: map[Resource]map[string]bool{},
+	}
+	mu.user.ID = strconv.Itoa(s.nextID)
+	if mu.user.Status == "" {
+		mu.user.Status = "active"
+	}
+	s.users[mu.user.ID] = mu
+	return copyUser(&mu.user), nil
+}
+
+// FindUserByID finds a user by ID.
+func (s *MemoryStore) FindUserByID(ctx context.Context, id string) (*User, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	mu, err := s.user(id)
+	if err != nil {
+		return nil, err
+	}
+	return copyUser(&mu.user), nil
+}
+
+// FindUserByName finds a user by name.
+func (s *MemoryStore) FindUserByName(ctx context.Context, name string) (*User, error) {
+	return s.findUser(func(u *User) bool { return u.Name == name }, "name", name)
+}
+
+// FindUserByEmail finds a user by email.
+func (s *MemoryStore) FindUserByEmail(ctx context.Context, email string) (*User, error) {
+	return s.findUser(func(u *User) bool { return u.Email == email }, "email", email)
+}
+
+// UpdateUser saves changes to a user. The user's login metadata is kept.
+func (s *MemoryStore) UpdateUser(ctx context.Context, u *User) (*User, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	mu, err := s.user(u.ID)
+	if err != nil {
+		return nil, err
+	}
+	if err := s.checkUnique(u, u.ID); err != nil {
+		return nil, err
+	}
+	updated := copyUser(u)
+	updated.LastLoginAt = mu.user.LastLoginAt
+	updated.LastLoginIP = mu.user.LastLoginIP
+	updated.FailedLoginCount = mu.user.FailedLoginCount
+	mu.user = *updated
+	return copyUser(&mu.user), nil
+}
+
+// RecordLogin records a successful login.
+func (s *MemoryStore) RecordLogin(ctx context.Context, id string, at time.Time, ip string) (*User, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	mu, err := s.user(id)
+	if err != nil {
+		return nil, err
+	}
+	mu.user.LastLoginAt = &at
+	if ip != "" {
+		mu.user.LastLoginIP = ip
+	}
+	mu.user.FailedLoginCount = 0
+	return copyUser(&mu.user), nil
+}
+
+// RecordLoginFailure increments the user's failed login count.
+func (s *MemoryStore) RecordLoginFailure(ctx context.Context, id string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	mu, err := s.user(id)
+	if err != nil {
+		return err
+	}
+	mu.user.FailedLoginCount++
+	return nil
+}
+
+// DeleteUser deletes a user. As with an EntStore, the user is soft-deleted:
+// it can't be found, but its name and email address can't be reused.
+func (s *MemoryStore) DeleteUser(ctx context.Context, id string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	mu, err := s.user(id)
+	if err != nil {
+		return err
+	}
+	mu.deleted = true
+	return nil
+}
+
+// AddUserRole gives a user a role.
+func (s *MemoryStore) AddUserRole(ctx context.Context, id, role string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	mu, err := s.user(id)
+	if err != nil {
+		return err
+	}
+	if _, err := s.role(role); err != nil {
+		return err
+	}
+	mu.roles[role] = true
+	return nil
+}
+
+// RemoveUserRole takes a role from a user.
+func (s *MemoryStore) RemoveUserRole(ctx context.Context, id, role string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	mu, err := s.user(id)
+	if err != nil {
+		return err
+	}
+	delete(mu.roles, role)
+	return nil
+}
+
+// UserRoles returns the names of a user's roles.
+func (s *MemoryStore) UserRoles(ctx context.Context, id string) ([]string, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	mu, err := s.user(id)
+	if err != nil {
+		return nil, err
+	}
+	return sortedKeys(mu.roles), nil
+}
+
+// AddUserRoleOn gives a user a role on a resource.
+func (s *MemoryStore) AddUserRoleOn(ctx context.Context, id, role string, resource Resource) error {
+	if err := resource.validate(); err != nil {
+		return err
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	mu, err := s.user(id)
+	if err != nil {
+		return err
+	}
+	if _, err := s.role(role); err != nil {
+		return err
+	}
+	if mu.rolesOn[resource] == nil {
+		mu.rolesOn[resource] = map[string]bool{}
+	}
+	mu.rolesOn[resource][role] = true
+	return nil
+}
+
+// RemoveUserRoleOn takes a role on a resource from a user.
+func (s *MemoryStore) RemoveUserRoleOn(ctx context.Context, id, role string, resource Resource) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	mu, err := s.user(id)
+	if err != nil {
+		return err
+	}
+	delete(mu.rolesOn[resource], role)
+	if len(mu.rolesOn[resource]) == 0 {
+		delete(mu.rolesOn, resource)
+	}
+	return nil
+}
+
+// UserRolesOn returns the names of the roles a user was given on the
+// resource.
+func (s *MemoryStore) UserRolesOn(ctx context.Context, id string, resource Resource) ([]string, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	mu, err := s.user(id)
+	if err != nil {
+		return nil, err
+	}
+	return sortedKeys(mu.rolesOn[resource]), nil
+}
+
+// UserPermissions returns the names of the permissions of a user's roles and
+// the roles they inherit.
+func (s *MemoryStore) UserPermissions(ctx context.Context, id string) ([]string, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	mu, err := s.user(id)
+	if err != nil {
+		return nil, err
+	}
+	return roleGraph(s.roles).permissionNames(sortedKeys(mu.roles)), nil
+}
+
+// CreateRole creates a role with its permissions and the roles it inherits.
+func (s *MemoryStore) CreateRole(ctx context.Context, r *Role) (*Role, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if _, ok := s.roles[r.Name]; ok {
+		return nil, fmt.Errorf("%w: role %q", ErrAlreadyExists, r.Name)
+	}
+	permissions, err := s.permissionSet(r.Permissions)
+	if err != nil {
+		return nil, err
+	}
+	inherits, err := s.roleSet(r.Inherits)
+	if err != nil {
+		return nil, err
+	}
+	s.roles[r.Name] = &Role{
+		Name:        r.Name,
+		Description: r.Description,
+		Permissions: permissions,
+		Inherits:    inherits,
+	}
+	return copyRole(s.roles[r.Name]), nil
+}
+
+// FindRole finds a role by name.
+func (s *MemoryStore) FindRole(ctx context.Context, name string) (*Role, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	r, err := s.role(name)
+	if err != nil {
+		return nil, err
+	}
+	return copyRole(r), nil
+}
+
+// ListRoles returns all roles.
+func (s *MemoryStore) ListRoles(ctx context.Context) ([]*Role, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	roles := make([]*Role, 0, len(s.roles))
+	for _, r := range s.roles {
+		roles = append(roles, copyRole(r))
+	}
+	sort.Slice(roles, func(i, j int) bool { return roles[i].Name < roles[j].Name })
+	return roles, nil
+}
+
+// UpdateRoleDescription changes a role's description.
+func (s *MemoryStore) UpdateRoleDescription(ctx context.Context, name, description string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	r, err := s.role(name)
+	if err != nil {
+		return err
+	}
+	r.Description = description
+	return nil
+}
+
+// AddRolePermission adds a permission to a role.
+func (s *MemoryStore) AddRolePermission(ctx context.Context, role, permission string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	r, err := s.role(role)
+	if err != nil {
+		return err
+	}
+	permissions, err := s.permissionSet(append(slices.Clone(r.Permissions), permission))
+	if err != nil {
+		return err
+	}
+	r.Permissions = permissions
+	return nil
+}
+
+// RemoveRolePermission removes a permission from a role.
+func (s *MemoryStore) RemoveRolePermission(ctx context.Context, role, permission string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	r, err := s.role(role)
+	if err != nil {
+		return err
+	}
+	r.Permissions = slices.DeleteFunc(r.Permissions, func(p string) bool { return p == permission })
+	return nil
+}
+
+// SetRolePermissions sets a role's permissions.
+func (s *MemoryStore) SetRolePermissions(ctx context.Context, role string, permissions []string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	r, err := s.role(role)
+	if err != nil {
+		return err
+	}
+	set, err := s.permissionSet(permissions)
+	if err != nil {
+		return err
+	}
+	r.Permissions = set
+	return nil
+}
+
+// SetRoleInherits sets the roles a role inherits.
+func (s *MemoryStore) SetRoleInherits(ctx context.Context, role string, inherits []string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	r, err := s.role(role)
+	if err != nil {
+		return err
+	}
+	set, err := s.roleSet(inherits)
+	if err != nil {
+		return err
+	}
+	if err := roleGraph(s.roles).checkParents(role, set); err != nil {
+		return err
+	}
+	r.Inherits = set
+	return nil
+}
+
+// DeleteRole deletes a role.
+func (s *MemoryStore) DeleteRole(ctx context.Context, name string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if _, err := s.role(name); err != nil {
+		return err
+	}
+	delete(s.roles, name)
+	for _, mu := range s.users {
+		delete(mu.roles, name)
+		for resource, roles := range mu.rolesOn {
+			delete(roles, name)
+			if len(roles) == 0 {
+				delete(mu.rolesOn, resource)
+			}
+		}
+	}
+	for _, r := range s.roles {
+		r.Inherits = slices.DeleteFunc(r.Inherits, func(parent string) bool { return parent == name })
+	}
+	return nil
+}
+
+// CreatePermission creates a permission.
+func (s *MemoryStore) CreatePermission(ctx context.Context, p *Permission) (*Permission, error) {
+	if err := ValidatePermissionName(p.Name); err != nil {
+		return nil, err
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if _, ok := s.permissions[p.Name]; ok {
+		return nil, fmt.Errorf("%w: permission %q", ErrAlreadyExists, p.Name)
+	}
+	s.permissions[p.Name] = &Permission{
+		Name:        p.Name,
+		Description: p.Description,
+	}
+	return copyPermission(s.permissions[p.Name]), nil
+}
+
+// FindPermission finds a permission by name.
+func (s *MemoryStore) FindPermission(ctx context.Context, name string) (*Permission, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	p, err := s.permission(name)
+	if err != nil {
+		return nil, err
+	}
+	return copyPermission(p), nil
+}
+
+// ListPermissions returns all permissions.
+func (s *MemoryStore) ListPermissions(ctx context.Context) ([]*Permission, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	permissions := make([]*Permission, 0, len(s.permissions))
+	for _, p := range s.permissions {
+		permissions = append(permissions, copyPermission(p))
+	}
+	sort.Slice(permissions, func(i, j int) bool { return permissions[i].Name < permissions[j].Name })
+	return permissions, nil
+}
+
+// UpdatePermissionDescription changes a permission's description.
+func (s *MemoryStore) UpdatePermissionDescription(ctx context.Context, name, description string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	p, err := s.permission(name)
+	if err != nil {
+		return err
+	}
+	p.Description = description
+	return nil
+}
+
+// DeletePermission deletes a permission.
+func (s *MemoryStore) DeletePermission(ctx context.Context, name string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if _, err := s.permission(name); err != nil {
+		return err
+	}
+	delete(s.permissions, name)
+	for _, r := range s.roles {
+		r.Permissions = slices.DeleteFunc(r.Permissions, func(p string) bool { return p == name })
+	}
+	return nil
+}
+
+// TokenGeneration returns a user's token generation.
+func (s *MemoryStore) TokenGeneration(ctx context.Context, id string) (int, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	mu, err := s.user(id)
+	if err != nil {
+		return 0, err
+	}
+	return mu.generation, nil
+}
+
+// RevokeSessions changes a user's token generation.
+func (s *MemoryStore) RevokeSessions(ctx context.Context, id string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	mu, err := s.user(id)
+	if err != nil {
+		return err
+	}
+	mu.generation++
+	return nil
+}
+
+// findUser finds the user matching a condition.
+func (s *MemoryStore) findUser(match func(*User) bool, field, value string) (*User, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	for _, mu := range s.users {
+		if !mu.deleted && match(&mu.user) {
+			return copyUser(&mu.user), nil
+		}
+	}
+	return nil, fmt.Errorf("%w: user with %s %q", ErrNotFound, field, value)
+}
+
+// checkUnique checks that no user other than the one with the given ID has
+// the user's name or email, including deleted users.
+func (s *MemoryStore) checkUnique(u *User, id string) error {
+	for _, mu := range s.users {
+		if mu.user.ID == id {
+			continue
+		}
+		if mu.user.Name == u.Name {
+			return fmt.Errorf("%w: user with name %q", ErrAlreadyExists, u.Name)
+		}
+		if mu.user.Email == u.Email {
+			return fmt.Errorf("%w: user with email %q", ErrAlreadyExists, u.Email)
+		}
+	}
+	return nil
+}
+
+// user finds a user by ID.
+func (s *MemoryStore) user(id string) (*memoryUser, error) {
+	mu, ok := s.users[id]
+	if !ok || mu.deleted {
+		return nil, fmt.Errorf("%w: user %q", ErrNotFound, id)
+	}
+	return mu, nil
+}
+
+// role finds a role by name.
+func (s *MemoryStore) role(name string) (*Role, error) {
+	r, ok := s.roles[name]
+	if !ok {
+		return nil, fmt.Errorf("%w: role %q", ErrNotFound, name)
+	}
+	return r, nil
+}
+
+// permission finds a permission by name.
+func (s *MemoryStore) permission(name string) (*Permission, error) {
+	p, ok := s.permissions[name]
+	if !ok {
+		return nil, fmt.Errorf("%w: permission %q", ErrNotFound, name)
+	}
+	return p, nil
+}
+
+// permissionSet checks that the permissions exist, and returns their names in
+// order without duplicates.
+func (s *MemoryStore) permissionSet(names []string) ([]string, error) {
+	set := map[string]bool{}
+	for _, name := range names {
+		if _, err := s.permission(name); err != nil {
+			return nil, err
+		}
+		set[name] = true
+	}
+	return sortedKeys(set), nil
+}
+
+// roleSet checks that the roles exist, and returns their names in order
+// without duplicates.
+func (s *MemoryStore) roleSet(names []string) ([]string, error) {
+	set := map[string]bool{}
+	for _, name := range names {
+		if _, err := s.role(name); err != nil {
+			return nil, err
+		}
+		set[name] = true
+	}
+	return sortedKeys(set), nil
+}
+
+// sortedKeys returns the keys of a set in order.
+func sortedKeys(set map[string]bool) []string {
+	keys := make([]string, 0, len(set))
+	for k := range set {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+// copyUser returns a copy of a user.
+func copyUser(u *User) *User {
+	c := *u
+	c.SuspendedUntil = copyTime(u.SuspendedUntil)
+	c.EmailVerifiedAt = copyTime(u.EmailVerifiedAt)
+	c.LastLoginAt = copyTime(u.LastLoginAt)
+	return &c
+}
+
+// copyTime returns a copy of a time, or nil.
+func copyTime(t *time.Time) *time.Time {
+	if t == nil {
+		return nil
+	}
+	c := *t
+	return &c
+}
+
+// copyRole returns a copy of a role.
+func copyRole(r *Role) *Role {
+	c := *r
+	c.Permissions = append([]string{}, r.Permissions...)
+	c.Inherits = append([]string{}, r.Inherits...)
+	return &c
+}
+
+// copyPermission returns a copy of a permission.
+func copyPermission(p *Permission) *Permission {
+	c := *p
+	return &c
+}
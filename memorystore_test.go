@@ -0,0 +1,69 @@
+package users
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func Test_that_MemoryStore_allows_one_of_concurrent_duplicate_users(t *testing.T) {
+	s := NewMemoryStore()
+	ctx := context.Background()
+	var wg sync.WaitGroup
+	errs := make(chan error, 10)
+	for i := 0; i < 10; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			_, err := s.CreateUser(ctx, &User{
+				Name:         "user1",
+				Email:        fmt.Sprintf("user1+%d@example.com", i),
+				PasswordHash: "hash",
+			})
+			errs <- err
+		}()
+	}
+	wg.Wait()
+	close(errs)
+	created := 0
+	for err := range errs {
+		if err == nil {
+			created++
+			continue
+		}
+		require.ErrorIs(t, err, ErrAlreadyExists)
+	}
+	require.Equal(t, 1, created)
+}
+
+func Test_that_MemoryStore_returns_copies(t *testing.T) {
+	s := NewMemoryStore()
+	ctx := context.Background()
+	_, err := s.CreatePermission(ctx, &Permission{Name: "read"})
+	require.NoError(t, err)
+	r, err := s.CreateRole(ctx, &Role{Name: "user", Permissions: []string{"read"}})
+	require.NoError(t, err)
+	r.Permissions[0] = "changed"
+	r, err = s.FindRole(ctx, "user")
+	require.NoError(t, err)
+	require.Equal(t, []string{"read"}, r.Permissions)
+}
+
+func Test_that_Service_works_with_MemoryStore(t *testing.T) {
+	s := NewService(WithStore(NewMemoryStore()))
+	ctx := context.Background()
+	require.NoError(t, s.CreateRolesAndPermissions(ctx, Roles{
+		"user": {Permissions: []*Permission{{Name: "read"}}},
+	}))
+	u, err := s.Create(ctx, "user1", USER1_TEST_EMAIL, "password")
+	require.NoError(t, err)
+	require.NoError(t, s.AddRole(ctx, u, "user"))
+	u, err = s.LoginByEmail(ctx, USER1_TEST_EMAIL, "password")
+	require.NoError(t, err)
+	ok, err := s.CheckPermission(ctx, u, "read")
+	require.NoError(t, err)
+	require.True(t, ok)
+}
@@ -0,0 +1,359 @@
+package users
+
+import (
+	"context"
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"fmt"
+	"strconv"
+	"time"
+
+	"github.com/smxlong/users/ent"
+	"github.com/smxlong/users/ent/mfa"
+	"github.com/smxlong/users/ent/onetimetoken"
+	"github.com/smxlong/users/ent/user"
+)
+
+// MFAOptions control TOTP multi-factor authentication.
+type MFAOptions struct {
+	// Issuer is the name shown by authenticator apps. Optional. If not set,
+	// defaults to "users".
+	Issuer string
+	// EncryptionKey is the AES key used to encrypt TOTP secrets at rest. It
+	// must be 16, 24 or 32 bytes long. Required.
+	EncryptionKey []byte
+	// Skew is the number of time steps before and after the current one
+	// within which codes are accepted, to allow for clock drift. Optional. If
+	// not set, defaults to 1. Set to a negative value to accept only the
+	// current time step.
+	Skew int
+	// ChallengeValidFor is the duration an MFA challenge token is valid for.
+	// Optional. If not set, defaults to 5 minutes.
+	ChallengeValidFor time.Duration
+	// ChallengeAttempts is the number of codes that may be tried against an
+	// MFA challenge token before it is used up. Optional. If not set,
+	// defaults to 5.
+	ChallengeAttempts int
+}
+
+// GetIssuer returns the MFAOptions Issuer, or the default if not set.
+func (o *MFAOptions) GetIssuer() string {
+	if o.Issuer == "" {
+		return "users"
+	}
+	return o.Issuer
+}
+
+// GetSkew returns the MFAOptions Skew, or the default if not set.
+func (o *MFAOptions) GetSkew() int {
+	if o.Skew == 0 {
+		return 1
+	}
+	if o.Skew < 0 {
+		return 0
+	}
+	return o.Skew
+}
+
+// GetChallengeValidFor returns the MFAOptions ChallengeValidFor, or the default
+// if not set.
+func (o *MFAOptions) GetChallengeValidFor() time.Duration {
+	if o.ChallengeValidFor == 0 {
+		return 5 * time.Minute
+	}
+	return o.ChallengeValidFor
+}
+
+// GetChallengeAttempts returns the MFAOptions ChallengeAttempts, or the
+// default if not set.
+func (o *MFAOptions) GetChallengeAttempts() int {
+	if o.ChallengeAttempts == 0 {
+		return 5
+	}
+	return o.ChallengeAttempts
+}
+
+// MFAEnrollment holds what a user needs to add a TOTP secret to their
+// authenticator app.
+type MFAEnrollment struct {
+	// Secret is the base32-encoded TOTP secret, for manual entry.
+	Secret string
+	// URI is the otpauth:// URI. This is the payload to encode in a QR code.
+	URI string
+}
+
+// EnrollMFA generates a new TOTP secret for a user. MFA is not enabled until
+// the enrollment is confirmed with ConfirmMFA. Enrolling again before
+// confirming replaces the pending secret.
+func EnrollMFA(ctx context.Context, client *ent.Client, u *ent.User, opts *MFAOptions) (*MFAEnrollment, error) {
+	existing, err := u.QueryMfa().Only(ctx)
+	if err != nil && !ent.IsNotFound(err) {
+		return nil, err
+	}
+	if existing != nil && existing.Enabled {
+		return nil, ErrMFAAlreadyEnabled
+	}
+	secret, err := totpNewSecret()
+	if err != nil {
+		return nil, err
+	}
+	sealed, err := mfaSeal(opts.EncryptionKey, secret)
+	if err != nil {
+		return nil, err
+	}
+	if existing != nil {
+		err = existing.Update().
+			SetSecret(sealed).
+			SetLastStep(0).
+			Exec(ctx)
+	} else {
+		err = client.MFA.Create().
+			SetSecret(sealed).
+			SetUser(u).
+			Exec(ctx)
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &MFAEnrollment{
+		Secret: totpEncoding.EncodeToString(secret),
+		URI:    totpURI(opts.GetIssuer(), u.Email, secret),
+	}, nil
+}
+
+// ConfirmMFA enables MFA for a user after checking a code generated from the
+// pending enrollment.
+func ConfirmMFA(ctx context.Context, client *ent.Client, u *ent.User, code string, opts *MFAOptions) error {
+	m, err := u.QueryMfa().Only(ctx)
+	if ent.IsNotFound(err) {
+		return ErrMFANotEnrolled
+	}
+	if err != nil {
+		return err
+	}
+	if m.Enabled {
+		return ErrMFAAlreadyEnabled
+	}
+	step, err := mfaCheck(m, code, clockNow(ctx), opts)
+	if err != nil {
+		return err
+	}
+	return m.Update().
+		SetEnabled(true).
+		SetLastStep(step).
+		Exec(ctx)
+}
+
+// MFAEnabled checks if a user has confirmed MFA enrollment.
+func MFAEnabled(ctx context.Context, client *ent.Client, u *ent.User) (bool, error) {
+	return u.QueryMfa().
+		Where(mfa.Enabled(true)).
+		Exist(ctx)
+}
+
+// mfaEnabled checks if the user with the EntStore user ID has confirmed MFA
+// enrollment.
+func mfaEnabled(ctx context.Context, client *ent.Client, id string) (bool, error) {
+	uid, err := strconv.Atoi(id)
+	if err != nil {
+		return false, err
+	}
+	return client.MFA.Query().
+		Where(mfa.Enabled(true), mfa.HasUserWith(user.ID(uid))).
+		Exist(ctx)
+}
+
+// VerifyMFA checks a TOTP code for a user with MFA enabled. Each code is
+// accepted only once.
+func VerifyMFA(ctx context.Context, client *ent.Client, u *ent.User, code string, opts *MFAOptions) error {
+	m, err := u.QueryMfa().
+		Where(mfa.Enabled(true)).
+		Only(ctx)
+	if ent.IsNotFound(err) {
+		return ErrMFANotEnrolled
+	}
+	if err != nil {
+		return err
+	}
+	step, err := mfaCheck(m, code, clockNow(ctx), opts)
+	if err != nil {
+		return err
+	}
+	// Only advance the last step if no concurrent verification already did,
+	// so that a code can't be replayed by racing requests.
+	n, err := client.MFA.Update().
+		Where(mfa.ID(m.ID), mfa.LastStepLT(step)).
+		SetLastStep(step).
+		Save(ctx)
+	if err != nil {
+		return err
+	}
+	if n == 0 {
+		return ErrMFACodeInvalid
+	}
+	return nil
+}
+
+// DisableMFA removes a user's MFA enrollment. The user must re-authenticate
+// with their password and a current code. This isn't a login, so the user's
+// login metadata isn't updated.
+func DisableMFA(ctx context.Context, client *ent.Client, u *ent.User, password, code string, opts *MFAOptions) error {
+	if err := checkPassword(u.PasswordHash, password); err != nil {
+		return err
+	}
+	if err := VerifyMFA(ctx, client, u, code, opts); err != nil {
+		return err
+	}
+	_, err := client.MFA.Delete().
+		Where(mfa.HasUserWith(user.ID(u.ID))).
+		Exec(ctx)
+	return err
+}
+
+// LoginMFA verifies the password for a user as Login does, with the options.
+// If the user has MFA enabled, no user is returned; instead an MFA challenge
+// token is returned, and the login must be completed with CompleteMFALogin.
+// Until then, the login isn't recorded, and doesn't reset the user's lockout
+// failures.
+func LoginMFA(ctx context.Context, client *ent.Client, u *ent.User, password string, opts *MFAOptions, loginOpts ...LoginOption) (*ent.User, string, error) {
+	return loginMFA(ctx, client, entStoreUser(u), password, opts, loginOpts)
+}
+
+// LoginMFAByName finds a user by name and logs in as LoginMFA does. An unknown
+// name fails with ErrInvalidCredentials, as does a wrong password.
+func LoginMFAByName(ctx context.Context, client *ent.Client, name, password string, opts *MFAOptions, loginOpts ...LoginOption) (*ent.User, string, error) {
+	s := entService(ctx, client)
+	u, err := s.store.FindUserByName(ctx, name)
+	if err != nil {
+		return nil, "", s.loginNotFound(s.context(ctx), err, newLoginOptions(loginOpts))
+	}
+	return loginMFA(ctx, client, u, password, opts, loginOpts)
+}
+
+// LoginMFAByEmail finds a user by email and logs in as LoginMFA does. An
+// unknown email fails with ErrInvalidCredentials, as does a wrong password.
+func LoginMFAByEmail(ctx context.Context, client *ent.Client, email, password string, opts *MFAOptions, loginOpts ...LoginOption) (*ent.User, string, error) {
+	s := entService(ctx, client)
+	u, err := s.store.FindUserByEmail(ctx, email)
+	if err != nil {
+		return nil, "", s.loginNotFound(s.context(ctx), err, newLoginOptions(loginOpts))
+	}
+	return loginMFA(ctx, client, u, password, opts, loginOpts)
+}
+
+// loginMFA logs in a user of an EntStore on the client as LoginMFA does.
+func loginMFA(ctx context.Context, client *ent.Client, u *User, password string, opts *MFAOptions, loginOpts []LoginOption) (*ent.User, string, error) {
+	s := entService(ctx, client)
+	ctx = s.context(ctx)
+	o := newLoginOptions(loginOpts)
+	if err := s.verifyLogin(ctx, u, password, o); err != nil {
+		return nil, "", err
+	}
+	enabled, err := mfaEnabled(ctx, client, u.ID)
+	if err != nil {
+		return nil, "", err
+	}
+	if !enabled {
+		lu, err := s.recordLogin(ctx, u.ID, o)
+		eu, err := toEntUser(ctx, client, lu, err)
+		if err != nil {
+			return nil, "", err
+		}
+		return eu, "", nil
+	}
+	eu, err := toEntUser(ctx, client, u, nil)
+	if err != nil {
+		return nil, "", err
+	}
+	challenge, err := newOneTimeToken(ctx, client, eu, onetimetoken.PurposeMfaChallenge, opts.GetChallengeValidFor(), "")
+	if err != nil {
+		return nil, "", err
+	}
+	return nil, challenge, nil
+}
+
+// CompleteMFALogin completes a login started with LoginMFA, checking the
+// challenge token and either a TOTP code or a recovery code, and returns the
+// user. The challenge is used up by a successful login, or after
+// ChallengeAttempts codes have been tried. The login is recorded as Login
+// records it, with the options, which should be those given to LoginMFA.
+func CompleteMFALogin(ctx context.Context, client *ent.Client, challenge, code string, opts *MFAOptions, loginOpts ...LoginOption) (*ent.User, error) {
+	ott, err := findOneTimeToken(ctx, client, challenge, onetimetoken.PurposeMfaChallenge)
+	if err != nil {
+		return nil, err
+	}
+	u := ott.Edges.User
+	if err := CheckStatus(ctx, u); err != nil {
+		return nil, err
+	}
+	if err := attemptOneTimeToken(ctx, client, ott, opts.GetChallengeAttempts()); err != nil {
+		return nil, err
+	}
+	if isTOTPCode(code) {
+		err = VerifyMFA(ctx, client, u, code, opts)
+	} else {
+		err = UseRecoveryCode(ctx, client, u, code)
+	}
+	if err != nil {
+		return nil, err
+	}
+	if err := useOneTimeToken(ctx, client, ott); err != nil {
+		return nil, err
+	}
+	return recordEntLogin(ctx, client, u, loginOpts)
+}
+
+// mfaCheck checks a code against an MFA enrollment, returning the matching
+// time step at the time now. Codes for steps at or before the last accepted
+// one are rejected.
+func mfaCheck(m *ent.MFA, code string, now time.Time, opts *MFAOptions) (int64, error) {
+	secret, err := mfaOpen(opts.EncryptionKey, m.Secret)
+	if err != nil {
+		return 0, err
+	}
+	step, ok := totpMatch(secret, code, now, opts.GetSkew())
+	if !ok || step <= m.LastStep {
+		return 0, ErrMFACodeInvalid
+	}
+	return step, nil
+}
+
+// mfaSeal encrypts a TOTP secret with AES-GCM. The nonce is prepended to the
+// ciphertext.
+func mfaSeal(key, secret []byte) ([]byte, error) {
+	gcm, err := mfaCipher(key)
+	if err != nil {
+		return nil, err
+	}
+	nonce := make([]byte, gcm.NonceSize())
+	if _, err := rand.Read(nonce); err != nil {
+		return nil, err
+	}
+	return gcm.Seal(nonce, nonce, secret, nil), nil
+}
+
+// mfaOpen decrypts a TOTP secret sealed by mfaSeal.
+func mfaOpen(key, sealed []byte) ([]byte, error) {
+	gcm, err := mfaCipher(key)
+	if err != nil {
+		return nil, err
+	}
+	if len(sealed) < gcm.NonceSize() {
+		return nil, ErrMFASecretInvalid
+	}
+	secret, err := gcm.Open(nil, sealed[:gcm.NonceSize()], sealed[gcm.NonceSize():], nil)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %s", ErrMFASecretInvalid, err)
+	}
+	return secret, nil
+}
+
+// mfaCipher creates the AES-GCM cipher for a key.
+func mfaCipher(key []byte) (cipher.AEAD, error) {
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %s", ErrMFAEncryptionKeyInvalid, err)
+	}
+	return cipher.NewGCM(block)
+}
@@ -0,0 +1,302 @@
+package users
+
+import (
+	"context"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/smxlong/users/ent"
+)
+
+func testMFAOptions() *MFAOptions {
+	return &MFAOptions{
+		EncryptionKey: []byte("0123456789abcdef0123456789abcdef"),
+	}
+}
+
+// testTOTPCode returns the code for an enrollment at an offset from the
+// current time step.
+func testTOTPCode(t *testing.T, e *MFAEnrollment, offset int64) string {
+	secret, err := totpEncoding.DecodeString(e.Secret)
+	require.NoError(t, err)
+	return totpCode(secret, totpStep(time.Now())+offset)
+}
+
+// setupMFAUser creates a user with confirmed MFA.
+func setupMFAUser(t *testing.T, client *ent.Client) (*ent.User, *MFAEnrollment) {
+	ctx := context.Background()
+	u, err := Create(ctx, client, "user1", USER1_TEST_EMAIL, "password")
+	require.NoError(t, err)
+	e, err := EnrollMFA(ctx, client, u, testMFAOptions())
+	require.NoError(t, err)
+	require.NoError(t, ConfirmMFA(ctx, client, u, testTOTPCode(t, e, -1), testMFAOptions()))
+	return u, e
+}
+
+func Test_that_EnrollMFA_returns_secret_and_URI(t *testing.T) {
+	client := setupAndMigrate(t)
+	ctx := context.Background()
+	u, err := Create(ctx, client, "user1", USER1_TEST_EMAIL, "password")
+	require.NoError(t, err)
+	e, err := EnrollMFA(ctx, client, u, testMFAOptions())
+	require.NoError(t, err)
+	require.NotEmpty(t, e.Secret)
+	require.Contains(t, e.URI, "otpauth://totp/users:")
+	require.Contains(t, e.URI, "secret="+e.Secret)
+	enabled, err := MFAEnabled(ctx, client, u)
+	require.NoError(t, err)
+	require.False(t, enabled)
+}
+
+func Test_that_EnrollMFA_stores_the_secret_encrypted(t *testing.T) {
+	client := setupAndMigrate(t)
+	ctx := context.Background()
+	u, err := Create(ctx, client, "user1", USER1_TEST_EMAIL, "password")
+	require.NoError(t, err)
+	e, err := EnrollMFA(ctx, client, u, testMFAOptions())
+	require.NoError(t, err)
+	m, err := u.QueryMfa().Only(ctx)
+	require.NoError(t, err)
+	secret, err := totpEncoding.DecodeString(e.Secret)
+	require.NoError(t, err)
+	require.NotContains(t, string(m.Secret), string(secret))
+	opened, err := mfaOpen(testMFAOptions().EncryptionKey, m.Secret)
+	require.NoError(t, err)
+	require.Equal(t, secret, opened)
+	_, err = mfaOpen([]byte("fedcba9876543210fedcba9876543210"), m.Secret)
+	require.ErrorIs(t, err, ErrMFASecretInvalid)
+}
+
+func Test_that_EnrollMFA_fails_with_invalid_key(t *testing.T) {
+	client := setupAndMigrate(t)
+	ctx := context.Background()
+	u, err := Create(ctx, client, "user1", USER1_TEST_EMAIL, "password")
+	require.NoError(t, err)
+	_, err = EnrollMFA(ctx, client, u, &MFAOptions{EncryptionKey: []byte("short")})
+	require.ErrorIs(t, err, ErrMFAEncryptionKeyInvalid)
+}
+
+func Test_that_ConfirmMFA_enables_MFA(t *testing.T) {
+	client := setupAndMigrate(t)
+	ctx := context.Background()
+	u, _ := setupMFAUser(t, client)
+	enabled, err := MFAEnabled(ctx, client, u)
+	require.NoError(t, err)
+	require.True(t, enabled)
+	_, err = EnrollMFA(ctx, client, u, testMFAOptions())
+	require.ErrorIs(t, err, ErrMFAAlreadyEnabled)
+}
+
+func Test_that_ConfirmMFA_fails_with_wrong_code(t *testing.T) {
+	client := setupAndMigrate(t)
+	ctx := context.Background()
+	u, err := Create(ctx, client, "user1", USER1_TEST_EMAIL, "password")
+	require.NoError(t, err)
+	e, err := EnrollMFA(ctx, client, u, testMFAOptions())
+	require.NoError(t, err)
+	err = ConfirmMFA(ctx, client, u, testTOTPCode(t, e, 5), testMFAOptions())
+	require.ErrorIs(t, err, ErrMFACodeInvalid)
+}
+
+func Test_that_VerifyMFA_rejects_replayed_codes(t *testing.T) {
+	client := setupAndMigrate(t)
+	ctx := context.Background()
+	u, e := setupMFAUser(t, client)
+	code := testTOTPCode(t, e, 0)
+	require.NoError(t, VerifyMFA(ctx, client, u, code, testMFAOptions()))
+	require.ErrorIs(t, VerifyMFA(ctx, client, u, code, testMFAOptions()), ErrMFACodeInvalid)
+	// codes from earlier steps than the last accepted one are also rejected
+	require.ErrorIs(t, VerifyMFA(ctx, client, u, testTOTPCode(t, e, -1), testMFAOptions()), ErrMFACodeInvalid)
+}
+
+func Test_that_VerifyMFA_fails_when_not_enrolled(t *testing.T) {
+	client := setupAndMigrate(t)
+	ctx := context.Background()
+	u, err := Create(ctx, client, "user1", USER1_TEST_EMAIL, "password")
+	require.NoError(t, err)
+	require.ErrorIs(t, VerifyMFA(ctx, client, u, "000000", testMFAOptions()), ErrMFANotEnrolled)
+}
+
+func Test_that_LoginMFA_returns_user_without_MFA(t *testing.T) {
+	client := setupAndMigrate(t)
+	ctx := context.Background()
+	u, err := Create(ctx, client, "user1", USER1_TEST_EMAIL, "password")
+	require.NoError(t, err)
+	u2, challenge, err := LoginMFA(ctx, client, u, "password", testMFAOptions())
+	require.NoError(t, err)
+	require.Empty(t, challenge)
+	require.Equal(t, u.ID, u2.ID)
+}
+
+func Test_that_LoginMFA_returns_challenge_with_MFA(t *testing.T) {
+	client := setupAndMigrate(t)
+	ctx := context.Background()
+	u, e := setupMFAUser(t, client)
+	u2, challenge, err := LoginMFA(ctx, client, u, "password", testMFAOptions())
+	require.NoError(t, err)
+	require.Nil(t, u2)
+	require.NotEmpty(t, challenge)
+	// the challenge is not a user token
+	_, err = ValidateToken(ctx, client, challenge, &TokenOptions{Secret: "foo"})
+	require.Error(t, err)
+	_, err = CompleteMFALogin(ctx, client, challenge, "000000", testMFAOptions())
+	require.ErrorIs(t, err, ErrMFACodeInvalid)
+	u3, err := CompleteMFALogin(ctx, client, challenge, testTOTPCode(t, e, 0), testMFAOptions())
+	require.NoError(t, err)
+	require.Equal(t, u.ID, u3.ID)
+	// the challenge is single-use
+	_, err = CompleteMFALogin(ctx, client, challenge, testTOTPCode(t, e, 1), testMFAOptions())
+	require.ErrorIs(t, err, ErrTokenInvalid)
+}
+
+func Test_that_CompleteMFALogin_limits_attempts(t *testing.T) {
+	client := setupAndMigrate(t)
+	ctx := context.Background()
+	u, e := setupMFAUser(t, client)
+	opts := testMFAOptions()
+	opts.ChallengeAttempts = 2
+	_, challenge, err := LoginMFA(ctx, client, u, "password", opts)
+	require.NoError(t, err)
+	for i := 0; i < 2; i++ {
+		_, err = CompleteMFALogin(ctx, client, challenge, "000000", opts)
+		require.ErrorIs(t, err, ErrMFACodeInvalid)
+	}
+	_, err = CompleteMFALogin(ctx, client, challenge, testTOTPCode(t, e, 0), opts)
+	require.ErrorIs(t, err, ErrTokenInvalid)
+}
+
+func Test_that_LoginMFA_applies_login_options(t *testing.T) {
+	client := setupAndMigrate(t)
+	ctx := context.Background()
+	u, _ := setupMFAUser(t, client)
+	_, _, err := LoginMFA(ctx, client, u, "password", testMFAOptions(), RequireVerifiedEmail())
+	require.ErrorIs(t, err, ErrEmailNotVerified)
+	policy := &LockoutPolicy{Store: &MemoryLockoutStore{}, Threshold: 1}
+	_, _, err = LoginMFA(ctx, client, u, "wrong password", testMFAOptions(), WithLockout(policy, ""))
+	require.ErrorIs(t, err, ErrInvalidCredentials)
+	until, err := policy.LockedUntil(ctx, strconv.Itoa(u.ID))
+	require.NoError(t, err)
+	require.False(t, until.IsZero())
+}
+
+func Test_that_LoginMFA_fails_with_wrong_password(t *testing.T) {
+	client := setupAndMigrate(t)
+	ctx := context.Background()
+	u, _ := setupMFAUser(t, client)
+	_, _, err := LoginMFA(ctx, client, u, "wrong password", testMFAOptions())
+	require.ErrorIs(t, err, ErrInvalidCredentials)
+}
+
+func Test_that_Login_rejects_users_with_MFA(t *testing.T) {
+	client := setupAndMigrate(t)
+	ctx := context.Background()
+	u, _ := setupMFAUser(t, client)
+	_, err := Login(ctx, u, "password")
+	require.ErrorIs(t, err, ErrMFARequired)
+	_, err = LoginByName(ctx, client, "user1", "password")
+	require.ErrorIs(t, err, ErrMFARequired)
+	_, err = LoginByEmail(ctx, client, USER1_TEST_EMAIL, "password")
+	require.ErrorIs(t, err, ErrMFARequired)
+	// a wrong password is rejected as usual
+	_, err = LoginByName(ctx, client, "user1", "wrong password")
+	require.ErrorIs(t, err, ErrInvalidCredentials)
+	u, err = client.User.Get(ctx, u.ID)
+	require.NoError(t, err)
+	require.Nil(t, u.LastLoginAt)
+}
+
+func Test_that_LoginMFAByName_and_LoginMFAByEmail_find_the_user(t *testing.T) {
+	client := setupAndMigrate(t)
+	ctx := context.Background()
+	u, e := setupMFAUser(t, client)
+	_, challenge, err := LoginMFAByName(ctx, client, "user1", "password", testMFAOptions())
+	require.NoError(t, err)
+	u2, err := CompleteMFALogin(ctx, client, challenge, testTOTPCode(t, e, 0), testMFAOptions())
+	require.NoError(t, err)
+	require.Equal(t, u.ID, u2.ID)
+	_, challenge, err = LoginMFAByEmail(ctx, client, USER1_TEST_EMAIL, "password", testMFAOptions())
+	require.NoError(t, err)
+	require.NotEmpty(t, challenge)
+	_, _, err = LoginMFAByName(ctx, client, "user2", "password", testMFAOptions())
+	require.ErrorIs(t, err, ErrInvalidCredentials)
+	_, _, err = LoginMFAByEmail(ctx, client, USER2_TEST_EMAIL, "password", testMFAOptions())
+	require.ErrorIs(t, err, ErrInvalidCredentials)
+}
+
+func Test_that_LoginMFA_does_not_reset_lockout_failures(t *testing.T) {
+	client := setupAndMigrate(t)
+	ctx := context.Background()
+	u, _ := setupMFAUser(t, client)
+	policy := &LockoutPolicy{Store: &MemoryLockoutStore{}, Threshold: 2}
+	_, _, err := LoginMFA(ctx, client, u, "wrong password", testMFAOptions(), WithLockout(policy, ""))
+	require.ErrorIs(t, err, ErrInvalidCredentials)
+	_, challenge, err := LoginMFA(ctx, client, u, "password", testMFAOptions(), WithLockout(policy, ""))
+	require.NoError(t, err)
+	require.NotEmpty(t, challenge)
+	_, _, err = LoginMFA(ctx, client, u, "wrong password", testMFAOptions(), WithLockout(policy, ""))
+	require.ErrorIs(t, err, ErrInvalidCredentials)
+	until, err := policy.LockedUntil(ctx, strconv.Itoa(u.ID))
+	require.NoError(t, err)
+	require.False(t, until.IsZero())
+}
+
+func Test_that_CompleteMFALogin_records_the_login(t *testing.T) {
+	client := setupAndMigrate(t)
+	clock := NewFakeClock(time.Now())
+	ctx := ContextWithClock(context.Background(), clock)
+	u, e := setupMFAUser(t, client)
+	policy := &LockoutPolicy{Store: &MemoryLockoutStore{}, Threshold: 2}
+	_, _, err := LoginMFA(ctx, client, u, "wrong password", testMFAOptions(), WithLockout(policy, ""))
+	require.ErrorIs(t, err, ErrInvalidCredentials)
+	_, challenge, err := LoginMFA(ctx, client, u, "password", testMFAOptions(), WithLockout(policy, ""), WithIP("192.0.2.1"))
+	require.NoError(t, err)
+	u, err = client.User.Get(ctx, u.ID)
+	require.NoError(t, err)
+	require.Nil(t, u.LastLoginAt)
+	require.Equal(t, 1, u.FailedLoginCount)
+	u, err = CompleteMFALogin(ctx, client, challenge, testTOTPCode(t, e, 0), testMFAOptions(), WithLockout(policy, ""), WithIP("192.0.2.1"))
+	require.NoError(t, err)
+	require.NotNil(t, u.LastLoginAt)
+	require.True(t, clock.Now().Equal(*u.LastLoginAt))
+	require.Equal(t, "192.0.2.1", u.LastLoginIP)
+	require.Zero(t, u.FailedLoginCount)
+	// the lockout failures were reset
+	_, _, err = LoginMFA(ctx, client, u, "wrong password", testMFAOptions(), WithLockout(policy, ""))
+	require.ErrorIs(t, err, ErrInvalidCredentials)
+	until, err := policy.LockedUntil(ctx, strconv.Itoa(u.ID))
+	require.NoError(t, err)
+	require.True(t, until.IsZero())
+}
+
+func Test_that_CompleteMFALogin_fails_with_user_token(t *testing.T) {
+	client := setupAndMigrate(t)
+	ctx := context.Background()
+	u, e := setupMFAUser(t, client)
+	tok, err := NewToken(u, &TokenOptions{Secret: "foo"})
+	require.NoError(t, err)
+	_, err = CompleteMFALogin(ctx, client, tok, testTOTPCode(t, e, 0), testMFAOptions())
+	require.ErrorIs(t, err, ErrTokenInvalid)
+}
+
+func Test_that_DisableMFA_requires_reauthentication(t *testing.T) {
+	client := setupAndMigrate(t)
+	ctx := context.Background()
+	u, e := setupMFAUser(t, client)
+	err := DisableMFA(ctx, client, u, "wrong password", testTOTPCode(t, e, 0), testMFAOptions())
+	require.ErrorIs(t, err, ErrInvalidCredentials)
+	err = DisableMFA(ctx, client, u, "password", "000000", testMFAOptions())
+	require.ErrorIs(t, err, ErrMFACodeInvalid)
+	err = DisableMFA(ctx, client, u, "password", testTOTPCode(t, e, 0), testMFAOptions())
+	require.NoError(t, err)
+	enabled, err := MFAEnabled(ctx, client, u)
+	require.NoError(t, err)
+	require.False(t, enabled)
+	// checking the password isn't a login
+	u, err = FindByName(ctx, client, "user1")
+	require.NoError(t, err)
+	require.Nil(t, u.LastLoginAt)
+	require.Equal(t, 0, u.FailedLoginCount)
+}
@@ -0,0 +1,119 @@
+package users
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/rsa"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/lestrrat-go/jwx/v3/jwa"
+	"github.com/lestrrat-go/jwx/v3/jwk"
+	"github.com/lestrrat-go/jwx/v3/jwt"
+	"github.com/stretchr/testify/require"
+)
+
+// mockOIDCProvider is a minimal OpenID Connect provider serving discovery and
+// keys, and issuing ID tokens on demand.
+type mockOIDCProvider struct {
+	server *httptest.Server
+	key    jwk.Key
+}
+
+func newMockOIDCProvider(t *testing.T) *mockOIDCProvider {
+	raw, err := rsa.GenerateKey(rand.Reader, 2048)
+	require.NoError(t, err)
+	key, err := jwk.Import(raw)
+	require.NoError(t, err)
+	require.NoError(t, key.Set(jwk.KeyIDKey, "test"))
+	require.NoError(t, key.Set(jwk.AlgorithmKey, jwa.RS256()))
+	pub, err := key.PublicKey()
+	require.NoError(t, err)
+	set := jwk.NewSet()
+	require.NoError(t, set.AddKey(pub))
+	m := &mockOIDCProvider{key: key}
+	mux := http.NewServeMux()
+	mux.HandleFunc("/.well-known/openid-configuration", func(w http.ResponseWriter, r *http.Request) {
+		_ = json.NewEncoder(w).Encode(map[string]string{
+			"issuer":   m.server.URL,
+			"jwks_uri": m.server.URL + "/jwks",
+		})
+	})
+	mux.HandleFunc("/jwks", func(w http.ResponseWriter, r *http.Request) {
+		_ = json.NewEncoder(w).Encode(set)
+	})
+	m.server = httptest.NewServer(mux)
+	t.Cleanup(m.server.Close)
+	return m
+}
+
+func (m *mockOIDCProvider) idToken(t *testing.T, audience, subject string, claims map[string]interface{}) string {
+	tok := jwt.New()
+	require.NoError(t, tok.Set(jwt.IssuerKey, m.server.URL))
+	require.NoError(t, tok.Set(jwt.AudienceKey, audience))
+	require.NoError(t, tok.Set(jwt.SubjectKey, subject))
+	require.NoError(t, tok.Set(jwt.ExpirationKey, time.Now().Add(time.Minute).Unix()))
+	for k, v := range claims {
+		require.NoError(t, tok.Set(k, v))
+	}
+	signed, err := jwt.Sign(tok, jwt.WithKey(jwa.RS256(), m.key))
+	require.NoError(t, err)
+	return string(signed)
+}
+
+func Test_that_OIDCProvider_Verify_returns_claims(t *testing.T) {
+	ctx := context.Background()
+	m := newMockOIDCProvider(t)
+	p, err := DiscoverOIDCProvider(ctx, m.server.URL, "client")
+	require.NoError(t, err)
+	claims, err := p.Verify(ctx, m.idToken(t, "client", "sub1", map[string]interface{}{
+		"email":              USER1_TEST_EMAIL,
+		"email_verified":     true,
+		"preferred_username": "user1",
+		"groups":             []string{"staff", "eng"},
+	}))
+	require.NoError(t, err)
+	require.Equal(t, m.server.URL, claims.Provider)
+	require.Equal(t, "sub1", claims.Subject)
+	require.Equal(t, USER1_TEST_EMAIL, claims.Email)
+	require.True(t, claims.EmailVerified)
+	require.Equal(t, "user1", claims.Name)
+	require.Equal(t, []string{"staff", "eng"}, claims.Groups)
+}
+
+func Test_that_OIDCProvider_Verify_fails_with_wrong_audience(t *testing.T) {
+	ctx := context.Background()
+	m := newMockOIDCProvider(t)
+	p, err := DiscoverOIDCProvider(ctx, m.server.URL, "client")
+	require.NoError(t, err)
+	_, err = p.Verify(ctx, m.idToken(t, "other", "sub1", nil))
+	require.Error(t, err)
+}
+
+func Test_that_OIDCProvider_Verify_fails_with_foreign_key(t *testing.T) {
+	ctx := context.Background()
+	m := newMockOIDCProvider(t)
+	other := newMockOIDCProvider(t)
+	p, err := DiscoverOIDCProvider(ctx, m.server.URL, "client")
+	require.NoError(t, err)
+	tok := other.idToken(t, "client", "sub1", map[string]interface{}{jwt.IssuerKey: m.server.URL})
+	_, err = p.Verify(ctx, tok)
+	require.Error(t, err)
+}
+
+func Test_that_OIDCProvider_Verify_uses_the_context_clock(t *testing.T) {
+	m := newMockOIDCProvider(t)
+	p, err := DiscoverOIDCProvider(context.Background(), m.server.URL, "client")
+	require.NoError(t, err)
+	tok := m.idToken(t, "client", "sub1", nil)
+	clock := NewFakeClock(time.Now())
+	ctx := ContextWithClock(context.Background(), clock)
+	_, err = p.Verify(ctx, tok)
+	require.NoError(t, err)
+	clock.Advance(time.Hour)
+	_, err = p.Verify(ctx, tok)
+	require.Error(t, err)
+}
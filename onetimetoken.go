@@ -0,0 +1,213 @@
+package users
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"crypto/subtle"
+	"encoding/base64"
+	"encoding/hex"
+	"fmt"
+	"net/url"
+	"time"
+
+	"github.com/smxlong/users/ent"
+	"github.com/smxlong/users/ent/onetimetoken"
+)
+
+// oneTimeTokenSize is the number of random bytes in a one-time token.
+const oneTimeTokenSize = 32
+
+// EmailOptions control how tokens are emailed to users as links.
+type EmailOptions struct {
+	// Mailer sends the email. Required.
+	Mailer Mailer
+	// From is the sender address. Required.
+	From string
+	// URL is the link target. The token is added to it as the "token" query
+	// parameter. Required.
+	URL string
+	// Subject is the email subject. Optional. If not set, a default suited
+	// to the email is used.
+	Subject string
+	// ValidFor is the duration the token is valid for. Optional. If not set,
+	// a default suited to the email is used.
+	ValidFor time.Duration
+	// OnError is called with errors sending emails that are sent in the
+	// background, such as password reset and magic links. Optional.
+	OnError func(error)
+}
+
+// getSubject returns the EmailOptions Subject, or the given default if not set.
+func (o *EmailOptions) getSubject(def string) string {
+	if o.Subject == "" {
+		return def
+	}
+	return o.Subject
+}
+
+// getValidFor returns the EmailOptions ValidFor, or the given default if not
+// set.
+func (o *EmailOptions) getValidFor(def time.Duration) time.Duration {
+	if o.ValidFor == 0 {
+		return def
+	}
+	return o.ValidFor
+}
+
+// link adds a token to the URL.
+func (o *EmailOptions) link(token string) (string, error) {
+	u, err := url.Parse(o.URL)
+	if err != nil {
+		return "", err
+	}
+	q := u.Query()
+	q.Set("token", token)
+	u.RawQuery = q.Encode()
+	return u.String(), nil
+}
+
+// send emails a link containing the token to a user.
+func (o *EmailOptions) send(ctx context.Context, u *ent.User, subject, text, token string, validFor time.Duration) error {
+	link, err := o.link(token)
+	if err != nil {
+		return err
+	}
+	return o.Mailer.Send(ctx, &Message{
+		From:    o.From,
+		To:      []string{u.Email},
+		Subject: subject,
+		Body:    fmt.Sprintf("%s\n\n%s\n\nThis link expires in %s.\n", text, link, validFor),
+	})
+}
+
+// sendInBackground issues a token for a user and emails it as send does,
+// without waiting for the email to be sent, so that callers can't tell from
+// the time taken or an error whether the email could be sent. The token is
+// issued with the client, so that it is part of the caller's transaction, if
+// any. Errors sending the email are passed to OnError.
+func (o *EmailOptions) sendInBackground(ctx context.Context, client *ent.Client, u *ent.User, purpose onetimetoken.Purpose, device, subject, text string, validFor time.Duration) error {
+	token, err := newOneTimeToken(ctx, client, u, purpose, validFor, device)
+	if err != nil {
+		return err
+	}
+	ctx = context.WithoutCancel(ctx)
+	go func() {
+		if err := o.send(ctx, u, subject, text, token, validFor); err != nil && o.OnError != nil {
+			o.OnError(err)
+		}
+	}()
+	return nil
+}
+
+// newOneTimeToken issues a random single-use token for a user. Only the
+// token's hash is stored, so the token can't be recovered from the database.
+// If device is not empty, the token is bound to it.
+func newOneTimeToken(ctx context.Context, client *ent.Client, u *ent.User, purpose onetimetoken.Purpose, validFor time.Duration, device string) (string, error) {
+	b := make([]byte, oneTimeTokenSize)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+	token := base64.RawURLEncoding.EncodeToString(b)
+	create := client.OneTimeToken.Create().
+		SetPurpose(purpose).
+		SetTokenHash(hashOneTimeToken(token)).
+		SetExpiresAt(clockNow(ctx).Add(validFor)).
+		SetUser(u)
+	if device != "" {
+		create.SetDeviceHash(hashOneTimeToken(device))
+	}
+	if err := create.Exec(ctx); err != nil {
+		return "", err
+	}
+	return token, nil
+}
+
+// consumeOneTimeToken checks a token issued for the purpose, marks it used
+// and returns its user. A token bound to a device is only accepted from that
+// device, and is not used up by attempts from other devices.
+func consumeOneTimeToken(ctx context.Context, client *ent.Client, token string, purpose onetimetoken.Purpose, device string) (*ent.User, error) {
+	ott, err := findOneTimeToken(ctx, client, token, purpose)
+	if err != nil {
+		return nil, err
+	}
+	if ott.DeviceHash != "" && subtle.ConstantTimeCompare([]byte(ott.DeviceHash), []byte(hashOneTimeToken(device))) != 1 {
+		return nil, ErrTokenDeviceMismatch
+	}
+	if err := useOneTimeToken(ctx, client, ott); err != nil {
+		return nil, err
+	}
+	return ott.Edges.User, nil
+}
+
+// findOneTimeToken returns an unused, unexpired token issued for the purpose,
+// with its user loaded.
+func findOneTimeToken(ctx context.Context, client *ent.Client, token string, purpose onetimetoken.Purpose) (*ent.OneTimeToken, error) {
+	ott, err := client.OneTimeToken.Query().
+		Where(
+			onetimetoken.TokenHash(hashOneTimeToken(token)),
+			onetimetoken.PurposeEQ(purpose),
+			onetimetoken.UsedAtIsNil(),
+		).
+		WithUser().
+		Only(ctx)
+	if ent.IsNotFound(err) {
+		return nil, ErrTokenInvalid
+	}
+	if err != nil {
+		return nil, err
+	}
+	// The user isn't loaded if they have been deleted since the token was
+	// issued.
+	if ott.Edges.User == nil {
+		return nil, ErrTokenInvalid
+	}
+	if !clockNow(ctx).Before(ott.ExpiresAt) {
+		return nil, ErrTokenExpired
+	}
+	return ott, nil
+}
+
+// useOneTimeToken marks a token used. Only a token no concurrent request
+// already used is marked, so that it can't be used twice by racing requests.
+func useOneTimeToken(ctx context.Context, client *ent.Client, ott *ent.OneTimeToken) error {
+	n, err := client.OneTimeToken.Update().
+		Where(onetimetoken.ID(ott.ID), onetimetoken.UsedAtIsNil()).
+		SetUsedAt(clockNow(ctx)).
+		Save(ctx)
+	if err != nil {
+		return err
+	}
+	if n == 0 {
+		return ErrTokenInvalid
+	}
+	return nil
+}
+
+// attemptOneTimeToken counts an attempt to use a token that allows up to max
+// attempts. The count is incremented in the database, so that racing
+// requests can't make more attempts between them.
+func attemptOneTimeToken(ctx context.Context, client *ent.Client, ott *ent.OneTimeToken, max int) error {
+	n, err := client.OneTimeToken.Update().
+		Where(
+			onetimetoken.ID(ott.ID),
+			onetimetoken.UsedAtIsNil(),
+			onetimetoken.AttemptsLT(max),
+		).
+		AddAttempts(1).
+		Save(ctx)
+	if err != nil {
+		return err
+	}
+	if n == 0 {
+		return ErrTokenInvalid
+	}
+	return nil
+}
+
+// hashOneTimeToken hashes a token or device for storage. Tokens are long and
+// random, so a fast hash is sufficient.
+func hashOneTimeToken(token string) string {
+	sum := sha256.Sum256([]byte(token))
+	return hex.EncodeToString(sum[:])
+}
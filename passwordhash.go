@@ -51,6 +51,15 @@ func PasswordHashBcrypt(password string) (*PasswordHash, error) {
 	}, nil
 }
 
+// PasswordHashNone returns a PasswordHash that never verifies. It is used for
+// users who have no password, such as those provisioned from an external
+// identity provider.
+func PasswordHashNone() *PasswordHash {
+	return &PasswordHash{
+		Algorithm: "none",
+	}
+}
+
 // Verify checks if the password matches the hash.
 func (p *PasswordHash) Verify(password string) error {
 	switch p.Algorithm {
@@ -59,6 +68,8 @@ func (p *PasswordHash) Verify(password string) error {
 			return fmt.Errorf("%w: %s", ErrPasswordHashMismatch, err)
 		}
 		return nil
+	case "none":
+		return fmt.Errorf("%w: no password set", ErrPasswordHashMismatch)
 	default:
 		return fmt.Errorf("%w: %s", ErrPasswordHashUnknownAlgorithm, p.Algorithm)
 	}
@@ -56,3 +56,10 @@ func Test_that_PasswordHash_Verify_returns_error_on_unknown_algorithm(t *testing
 	}
 	require.ErrorIs(t, ph.Verify("password"), ErrPasswordHashUnknownAlgorithm)
 }
+
+func Test_that_PasswordHashNone_never_verifies(t *testing.T) {
+	ph, err := PasswordHashParse(PasswordHashNone().String())
+	require.NoError(t, err)
+	require.ErrorIs(t, ph.Verify(""), ErrPasswordHashMismatch)
+	require.ErrorIs(t, ph.Verify("password"), ErrPasswordHashMismatch)
+}
@@ -0,0 +1,69 @@
+package users
+
+import (
+	"fmt"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+// PasswordPolicy describes which passwords are acceptable.
+type PasswordPolicy struct {
+	// MinLength is the minimum number of characters. Optional. If not set,
+	// defaults to 8.
+	MinLength int
+	// RequireMixedCase requires both upper and lower case letters.
+	RequireMixedCase bool
+	// RequireDigit requires at least one digit.
+	RequireDigit bool
+	// RequireSymbol requires at least one character that is not a letter or
+	// digit.
+	RequireSymbol bool
+	// Forbidden lists passwords that are rejected, such as common passwords.
+	// They are compared case-insensitively. Optional.
+	Forbidden []string
+}
+
+// GetMinLength returns the PasswordPolicy MinLength, or the default if not set.
+func (p *PasswordPolicy) GetMinLength() int {
+	if p.MinLength == 0 {
+		return 8
+	}
+	return p.MinLength
+}
+
+// Validate checks a password against the policy. The error wraps
+// ErrPasswordPolicy and describes the first requirement that isn't met.
+func (p *PasswordPolicy) Validate(password string) error {
+	if utf8.RuneCountInString(password) < p.GetMinLength() {
+		return fmt.Errorf("%w: must be at least %d characters", ErrPasswordPolicy, p.GetMinLength())
+	}
+	var upper, lower, digit, symbol bool
+	for _, r := range password {
+		switch {
+		case unicode.IsUpper(r):
+			upper = true
+		case unicode.IsLower(r):
+			lower = true
+		case unicode.IsDigit(r):
+			digit = true
+		case !unicode.IsLetter(r):
+			symbol = true
+		}
+	}
+	if p.RequireMixedCase && !(upper && lower) {
+		return fmt.Errorf("%w: must contain upper and lower case letters", ErrPasswordPolicy)
+	}
+	if p.RequireDigit && !digit {
+		return fmt.Errorf("%w: must contain a digit", ErrPasswordPolicy)
+	}
+	if p.RequireSymbol && !symbol {
+		return fmt.Errorf("%w: must contain a symbol", ErrPasswordPolicy)
+	}
+	for _, f := range p.Forbidden {
+		if strings.EqualFold(password, f) {
+			return fmt.Errorf("%w: too common", ErrPasswordPolicy)
+		}
+	}
+	return nil
+}
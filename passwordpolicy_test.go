@@ -0,0 +1,29 @@
+package users
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func Test_that_PasswordPolicy_enforces_minimum_length(t *testing.T) {
+	p := &PasswordPolicy{}
+	require.ErrorIs(t, p.Validate("short"), ErrPasswordPolicy)
+	require.NoError(t, p.Validate("long enough"))
+	p.MinLength = 12
+	require.ErrorIs(t, p.Validate("long enough"), ErrPasswordPolicy)
+}
+
+func Test_that_PasswordPolicy_enforces_character_classes(t *testing.T) {
+	p := &PasswordPolicy{RequireMixedCase: true, RequireDigit: true, RequireSymbol: true}
+	require.ErrorIs(t, p.Validate("password1!"), ErrPasswordPolicy)
+	require.ErrorIs(t, p.Validate("Password!"), ErrPasswordPolicy)
+	require.ErrorIs(t, p.Validate("Password1"), ErrPasswordPolicy)
+	require.NoError(t, p.Validate("Password1!"))
+}
+
+func Test_that_PasswordPolicy_rejects_forbidden_passwords(t *testing.T) {
+	p := &PasswordPolicy{Forbidden: []string{"password123"}}
+	require.ErrorIs(t, p.Validate("PASSWORD123"), ErrPasswordPolicy)
+	require.NoError(t, p.Validate("password124"))
+}
@@ -7,8 +7,12 @@ import (
 	"github.com/smxlong/users/ent/permission"
 )
 
-// CreatePermission creates a permission.
+// CreatePermission creates a permission. The name must satisfy
+// ValidatePermissionName.
 func CreatePermission(ctx context.Context, client *ent.Client, name, description string) (*ent.Permission, error) {
+	if err := ValidatePermissionName(name); err != nil {
+		return nil, err
+	}
 	permission, err := client.Permission.Create().
 		SetName(name).
 		SetDescription(description).
@@ -40,10 +44,7 @@ func FindOrCreatePermission(ctx context.Context, client *ent.Client, name, descr
 	if !ent.IsNotFound(err) {
 		return nil, err
 	}
-	return client.Permission.Create().
-		SetName(name).
-		SetDescription(description).
-		Save(ctx)
+	return CreatePermission(ctx, client, name, description)
 }
 
 // UpdatePermissionDescription changes a permission's description.
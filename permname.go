@@ -0,0 +1,79 @@
+package users
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+)
+
+// permissionWildcard is the permission name segment matching any segment.
+const permissionWildcard = "*"
+
+// ValidatePermissionName checks a permission name, failing with
+// ErrPermissionNameInvalid if it's not one or more segments separated by ":".
+// Each segment is "*", or made of letters, digits, "_", "-" and ".", such as
+// "reports:read", "reports:*" or "billing:invoices:write".
+func ValidatePermissionName(name string) error {
+	for _, segment := range strings.Split(name, ":") {
+		if !validPermissionSegment(segment) {
+			return fmt.Errorf("%w: %q", ErrPermissionNameInvalid, name)
+		}
+	}
+	return nil
+}
+
+// validPermissionSegment checks a segment of a permission name.
+func validPermissionSegment(segment string) bool {
+	if segment == permissionWildcard {
+		return true
+	}
+	if segment == "" {
+		return false
+	}
+	for _, c := range segment {
+		switch {
+		case c >= 'a' && c <= 'z', c >= 'A' && c <= 'Z', c >= '0' && c <= '9':
+		case c == '_', c == '-', c == '.':
+		default:
+			return false
+		}
+	}
+	return true
+}
+
+// PermissionMatches checks if a granted permission satisfies a wanted one.
+// Names are compared segment by segment. A "*" segment in the granted
+// permission matches any one segment, and a "*" last segment matches any one
+// or more, so "reports:*" satisfies "reports:read" and
+// "reports:invoices:write", and "*" satisfies everything. A "*" in the
+// wanted permission is only satisfied by a "*".
+func PermissionMatches(granted, wanted string) bool {
+	g := strings.Split(granted, ":")
+	w := strings.Split(wanted, ":")
+	for i, segment := range g {
+		if i == len(w) {
+			return false
+		}
+		if segment == permissionWildcard {
+			if i == len(g)-1 {
+				return true
+			}
+			continue
+		}
+		if segment != w[i] {
+			return false
+		}
+	}
+	return len(g) == len(w)
+}
+
+// hasPermissions checks if the granted permissions satisfy every wanted
+// permission.
+func hasPermissions(granted []string, wanted []string) bool {
+	for _, name := range wanted {
+		if !slices.ContainsFunc(granted, func(g string) bool { return PermissionMatches(g, name) }) {
+			return false
+		}
+	}
+	return true
+}
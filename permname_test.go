@@ -0,0 +1,81 @@
+package users
+
+import (
+	"context"
+	"testing"
+
+	"github.com/smxlong/users/ent/role"
+	"github.com/stretchr/testify/require"
+)
+
+func Test_that_ValidatePermissionName_checks_the_grammar(t *testing.T) {
+	for _, name := range []string{"read", "reports:read", "reports:*", "billing:invoices:write", "*", "*:read", "Test_Permission-1.0"} {
+		require.NoError(t, ValidatePermissionName(name), name)
+	}
+	for _, name := range []string{"", ":", "reports:", ":read", "reports::read", "reports:re*d", "reports read", "reports/read"} {
+		require.ErrorIs(t, ValidatePermissionName(name), ErrPermissionNameInvalid, name)
+	}
+}
+
+func Test_that_PermissionMatches_matches_wildcards(t *testing.T) {
+	for _, test := range []struct {
+		granted, wanted string
+		matches         bool
+	}{
+		{"reports:read", "reports:read", true},
+		{"reports:read", "reports:write", false},
+		{"reports:*", "reports:read", true},
+		{"reports:*", "reports:invoices:write", true},
+		{"reports:*", "reports", false},
+		{"reports:*", "reports:*", true},
+		{"reports:read", "reports:*", false},
+		{"*", "billing:invoices:write", true},
+		{"*:read", "reports:read", true},
+		{"*:read", "reports:write", false},
+		{"*:read", "reports:invoices:read", false},
+		{"billing:*:write", "billing:invoices:write", true},
+		{"billing:*:write", "billing:invoices:read", false},
+		{"reports", "reports:read", false},
+	} {
+		require.Equal(t, test.matches, PermissionMatches(test.granted, test.wanted), "%s satisfies %s", test.granted, test.wanted)
+	}
+}
+
+func Test_that_CreatePermission_rejects_invalid_names(t *testing.T) {
+	client := setupAndMigrate(t)
+	ctx := context.Background()
+	_, err := CreatePermission(ctx, client, "reports read", "Read reports")
+	require.ErrorIs(t, err, ErrPermissionNameInvalid)
+	_, err = FindOrCreatePermission(ctx, client, "reports:", "Reports")
+	require.ErrorIs(t, err, ErrPermissionNameInvalid)
+	err = CreateRolesAndPermissions(ctx, client, Roles{
+		"a": &RoleWithPermissions{Permissions: []*Permission{{Name: "a b"}}},
+	})
+	require.ErrorIs(t, err, ErrPermissionNameInvalid)
+	_, err = PlanRolesAndPermissions(ctx, client, Roles{
+		"a": &RoleWithPermissions{Permissions: []*Permission{{Name: "a b"}}},
+	})
+	require.ErrorIs(t, err, ErrPermissionNameInvalid)
+}
+
+func Test_that_CheckPermission_matches_wildcard_permissions(t *testing.T) {
+	client := setupAndMigrate(t)
+	ctx := context.Background()
+	require.NoError(t, CreateRolesAndPermissions(ctx, client, Roles{
+		"reporter": &RoleWithPermissions{
+			Permissions: []*Permission{{Name: "reports:*", Description: "Everything on reports"}},
+		},
+	}))
+	u, err := Create(ctx, client, "user1", USER1_TEST_EMAIL, "password")
+	require.NoError(t, err)
+	reporter, err := client.Role.Query().Where(role.Name("reporter")).Only(ctx)
+	require.NoError(t, err)
+	u, err = AddRole(ctx, client, u, reporter)
+	require.NoError(t, err)
+	ok, err := CheckPermission(ctx, client, u, "reports:read", "reports:invoices:write")
+	require.NoError(t, err)
+	require.True(t, ok)
+	ok, err = CheckPermission(ctx, client, u, "reports:read", "billing:read")
+	require.NoError(t, err)
+	require.False(t, ok)
+}
@@ -0,0 +1,531 @@
+package users
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"maps"
+	"slices"
+	"strings"
+
+	"github.com/smxlong/users/ent"
+	"github.com/smxlong/users/ent/roleassignment"
+	"github.com/smxlong/users/ent/user"
+)
+
+// Plan lists the changes that bring the roles and permissions in line with a
+// Roles map, as computed by PlanRolesAndPermissions. It can be printed with
+// String, reviewed, and then applied with ApplyPlan. All names are sorted, so
+// the same database and Roles always give the same plan.
+type Plan struct {
+	// CreatePermissions are the permissions to create.
+	CreatePermissions []*Permission
+	// UpdatePermissions are the permissions whose descriptions change.
+	UpdatePermissions []*DescriptionChange
+	// DeletePermissions are the names of the permissions to delete.
+	DeletePermissions []string
+	// CreateRoles are the roles to create, with their permissions.
+	CreateRoles []*Role
+	// UpdateRoles are the roles whose descriptions change.
+	UpdateRoles []*DescriptionChange
+	// DeleteRoles are the names of the roles to delete.
+	DeleteRoles []string
+	// AddRolePermissions are the permissions to add to existing roles.
+	AddRolePermissions []*RolePermission
+	// RemoveRolePermissions are the permissions to remove from existing roles.
+	RemoveRolePermissions []*RolePermission
+	// AddRoleInherits are the roles for existing roles to inherit.
+	AddRoleInherits []*RoleInherit
+	// RemoveRoleInherits are the roles for existing roles to stop inheriting.
+	RemoveRoleInherits []*RoleInherit
+	// UsersLosingAccess are the users who would lose roles or permissions.
+	UsersLosingAccess []*AccessLoss
+}
+
+// DescriptionChange is a change to the description of a role or permission.
+type DescriptionChange struct {
+	Name string
+	From string
+	To   string
+}
+
+// RolePermission is an edge between a role and a permission.
+type RolePermission struct {
+	Role       string
+	Permission string
+}
+
+// RoleInherit is an edge between a role and a role it inherits.
+type RoleInherit struct {
+	Role     string
+	Inherits string
+}
+
+// AccessLoss is the access a user would lose when a plan is applied.
+type AccessLoss struct {
+	// User is the user's name.
+	User  string
+	Email string
+	// Roles are the names of the user's roles that would be deleted.
+	Roles []string
+	// Permissions are the names of the permissions the user would lose.
+	Permissions []string
+	// Resources are the access the user would lose on resources, through
+	// the roles given to it on them, ordered by resource.
+	Resources []*ResourceAccessLoss
+}
+
+// ResourceAccessLoss is the access a user would lose on a resource when a
+// plan is applied.
+type ResourceAccessLoss struct {
+	Resource Resource
+	// Roles are the names of the user's roles on the resource that would be
+	// deleted.
+	Roles []string
+	// Permissions are the names of the permissions the user would lose on
+	// the resource, besides those it would lose on every resource.
+	Permissions []string
+}
+
+// Empty checks if the plan makes no changes.
+func (p *Plan) Empty() bool {
+	return len(p.CreatePermissions) == 0 &&
+		len(p.UpdatePermissions) == 0 &&
+		len(p.DeletePermissions) == 0 &&
+		len(p.CreateRoles) == 0 &&
+		len(p.UpdateRoles) == 0 &&
+		len(p.DeleteRoles) == 0 &&
+		len(p.AddRolePermissions) == 0 &&
+		len(p.RemoveRolePermissions) == 0 &&
+		len(p.AddRoleInherits) == 0 &&
+		len(p.RemoveRoleInherits) == 0
+}
+
+// String formats the plan for review, one change per line. Lines start with
+// "+" for additions, "~" for changes, "-" for removals and "!" for users
+// losing access.
+func (p *Plan) String() string {
+	if p.Empty() {
+		return "No changes.\n"
+	}
+	var b strings.Builder
+	for _, perm := range p.CreatePermissions {
+		fmt.Fprintf(&b, "+ permission %q: %q\n", perm.Name, perm.Description)
+	}
+	for _, c := range p.UpdatePermissions {
+		fmt.Fprintf(&b, "~ permission %q: %q -> %q\n", c.Name, c.From, c.To)
+	}
+	for _, r := range p.CreateRoles {
+		fmt.Fprintf(&b, "+ role %q: %q [%s]", r.Name, r.Description, strings.Join(r.Permissions, ", "))
+		if len(r.Inherits) > 0 {
+			fmt.Fprintf(&b, " inherits [%s]", strings.Join(r.Inherits, ", "))
+		}
+		b.WriteString("\n")
+	}
+	for _, c := range p.UpdateRoles {
+		fmt.Fprintf(&b, "~ role %q: %q -> %q\n", c.Name, c.From, c.To)
+	}
+	for _, rp := range p.AddRolePermissions {
+		fmt.Fprintf(&b, "+ role %q permission %q\n", rp.Role, rp.Permission)
+	}
+	for _, rp := range p.RemoveRolePermissions {
+		fmt.Fprintf(&b, "- role %q permission %q\n", rp.Role, rp.Permission)
+	}
+	for _, ri := range p.AddRoleInherits {
+		fmt.Fprintf(&b, "+ role %q inherits %q\n", ri.Role, ri.Inherits)
+	}
+	for _, ri := range p.RemoveRoleInherits {
+		fmt.Fprintf(&b, "- role %q inherits %q\n", ri.Role, ri.Inherits)
+	}
+	for _, name := range p.DeleteRoles {
+		fmt.Fprintf(&b, "- role %q\n", name)
+	}
+	for _, name := range p.DeletePermissions {
+		fmt.Fprintf(&b, "- permission %q\n", name)
+	}
+	for _, l := range p.UsersLosingAccess {
+		if len(l.Roles) > 0 || len(l.Permissions) > 0 {
+			fmt.Fprintf(&b, "! user %q <%s> loses", l.User, l.Email)
+			writeAccessLoss(&b, l.Roles, l.Permissions)
+		}
+		for _, r := range l.Resources {
+			fmt.Fprintf(&b, "! user %q <%s> loses on %s", l.User, l.Email, r.Resource)
+			writeAccessLoss(&b, r.Roles, r.Permissions)
+		}
+	}
+	return b.String()
+}
+
+// writeAccessLoss finishes a line of lost roles and permissions.
+func writeAccessLoss(b *strings.Builder, roles, permissions []string) {
+	if len(roles) > 0 {
+		fmt.Fprintf(b, " roles [%s]", strings.Join(roles, ", "))
+	}
+	if len(permissions) > 0 {
+		fmt.Fprintf(b, " permissions [%s]", strings.Join(permissions, ", "))
+	}
+	b.WriteString("\n")
+}
+
+// PlanRolesAndPermissions computes the changes SyncRolesAndPermissions would
+// make, without making them. Unlike SyncRolesAndPermissions, a plan changes
+// the descriptions of existing permissions rather than failing. It returns
+// ErrPermissionDescriptionMismatch if roles give the same permission
+// different descriptions, ErrPermissionNameInvalid for invalid permission
+// names, and the errors of CreateRolesAndPermissions if roles inherit unknown
+// roles or themselves.
+func PlanRolesAndPermissions(ctx context.Context, client *ent.Client, rolesAndPermissions Roles) (*Plan, error) {
+	return planRolesAndPermissions(ctx, client, rolesAndPermissions, nil)
+}
+
+// planRolesAndPermissions is PlanRolesAndPermissions, also keeping or
+// creating the extra permissions whether or not any role has them.
+func planRolesAndPermissions(ctx context.Context, client *ent.Client, rolesAndPermissions Roles, extra []*Permission) (*Plan, error) {
+	plan, have, err := planChanges(ctx, NewEntStore(client), rolesAndPermissions, extra)
+	if err != nil {
+		return nil, err
+	}
+	want := rolesAndPermissions.graph()
+	// Users losing access. Users only keep roles that still exist, and those
+	// roles have the wanted permissions and inherit the wanted roles. Roles
+	// given on resources are lost the same way, when deleting a role removes
+	// its assignments.
+	users, err := client.User.Query().
+		Where(user.Or(user.HasRoles(), user.HasRoleAssignments())).
+		WithRoles().
+		WithRoleAssignments(func(q *ent.RoleAssignmentQuery) {
+			q.WithRole().Order(ent.Asc(roleassignment.FieldResourceType), ent.Asc(roleassignment.FieldResourceID))
+		}).
+		Order(ent.Asc(user.FieldName)).
+		All(ctx)
+	if err != nil {
+		return nil, err
+	}
+	lose := func(before []string) (lost, kept []string) {
+		for _, name := range before {
+			if _, ok := rolesAndPermissions[name]; !ok {
+				lost = append(lost, name)
+				continue
+			}
+			kept = append(kept, name)
+		}
+		slices.Sort(lost)
+		return lost, kept
+	}
+	lostPermissions := func(before, kept []string) []string {
+		var lost []string
+		after := want.permissionNames(kept)
+		for _, name := range have.permissionNames(before) {
+			if !slices.Contains(after, name) {
+				lost = append(lost, name)
+			}
+		}
+		return lost
+	}
+	for _, u := range users {
+		loss := &AccessLoss{User: u.Name, Email: u.Email}
+		var before []string
+		for _, r := range u.Edges.Roles {
+			before = append(before, r.Name)
+		}
+		var kept []string
+		loss.Roles, kept = lose(before)
+		loss.Permissions = lostPermissions(before, kept)
+		// A user has the roles given on every resource on each resource too,
+		// so only the permissions lost on the resource alone are listed.
+		assignments := u.Edges.RoleAssignments
+		for len(assignments) > 0 {
+			resource := Resource{Type: assignments[0].ResourceType, ID: assignments[0].ResourceID}
+			var beforeOn []string
+			for len(assignments) > 0 && assignments[0].ResourceType == resource.Type && assignments[0].ResourceID == resource.ID {
+				beforeOn = append(beforeOn, assignments[0].Edges.Role.Name)
+				assignments = assignments[1:]
+			}
+			r := &ResourceAccessLoss{Resource: resource}
+			var keptOn []string
+			r.Roles, keptOn = lose(beforeOn)
+			for _, name := range lostPermissions(append(beforeOn, before...), append(keptOn, kept...)) {
+				if !slices.Contains(loss.Permissions, name) {
+					r.Permissions = append(r.Permissions, name)
+				}
+			}
+			if len(r.Roles) > 0 || len(r.Permissions) > 0 {
+				loss.Resources = append(loss.Resources, r)
+			}
+		}
+		if len(loss.Roles) > 0 || len(loss.Permissions) > 0 || len(loss.Resources) > 0 {
+			plan.UsersLosingAccess = append(plan.UsersLosingAccess, loss)
+		}
+	}
+	return plan, nil
+}
+
+// planChanges computes the changes to the roles and permissions in the store
+// that bring them in line with the roles and the extra permissions, without
+// the users losing access. It also returns the roles as they are.
+func planChanges(ctx context.Context, store Store, rolesAndPermissions Roles, extra []*Permission) (*Plan, roleGraph, error) {
+	if err := rolesAndPermissions.checkInherits(); err != nil {
+		return nil, nil, err
+	}
+	// Collect the wanted permissions and the permissions of each role.
+	wantPermissions := map[string]string{}
+	for _, p := range extra {
+		if err := ValidatePermissionName(p.Name); err != nil {
+			return nil, nil, err
+		}
+		wantPermissions[p.Name] = p.Description
+	}
+	wantRolePermissions := map[string][]string{}
+	for roleName, rolePermissions := range rolesAndPermissions {
+		var names []string
+		for _, rp := range rolePermissions.Permissions {
+			if err := ValidatePermissionName(rp.Name); err != nil {
+				return nil, nil, err
+			}
+			if description, ok := wantPermissions[rp.Name]; ok && description != rp.Description {
+				return nil, nil, ErrPermissionDescriptionMismatch
+			}
+			wantPermissions[rp.Name] = rp.Description
+			names = append(names, rp.Name)
+		}
+		slices.Sort(names)
+		wantRolePermissions[roleName] = slices.Compact(names)
+	}
+	permissions, err := store.ListPermissions(ctx)
+	if err != nil {
+		return nil, nil, err
+	}
+	roles, err := store.ListRoles(ctx)
+	if err != nil {
+		return nil, nil, err
+	}
+	plan := &Plan{}
+	// Permissions
+	havePermissions := map[string]bool{}
+	for _, p := range permissions {
+		havePermissions[p.Name] = true
+		description, ok := wantPermissions[p.Name]
+		switch {
+		case !ok:
+			plan.DeletePermissions = append(plan.DeletePermissions, p.Name)
+		case description != p.Description:
+			plan.UpdatePermissions = append(plan.UpdatePermissions, &DescriptionChange{Name: p.Name, From: p.Description, To: description})
+		}
+	}
+	for _, name := range slices.Sorted(maps.Keys(wantPermissions)) {
+		if !havePermissions[name] {
+			plan.CreatePermissions = append(plan.CreatePermissions, &Permission{Name: name, Description: wantPermissions[name]})
+		}
+	}
+	// Roles, their permissions and the roles they inherit
+	have := newRoleGraph(roles)
+	for _, r := range roles {
+		wantRole, ok := rolesAndPermissions[r.Name]
+		if !ok {
+			plan.DeleteRoles = append(plan.DeleteRoles, r.Name)
+			continue
+		}
+		if wantRole.Description != r.Description {
+			plan.UpdateRoles = append(plan.UpdateRoles, &DescriptionChange{Name: r.Name, From: r.Description, To: wantRole.Description})
+		}
+		for _, name := range wantRolePermissions[r.Name] {
+			if !slices.Contains(r.Permissions, name) {
+				plan.AddRolePermissions = append(plan.AddRolePermissions, &RolePermission{Role: r.Name, Permission: name})
+			}
+		}
+		for _, name := range r.Permissions {
+			if !slices.Contains(wantRolePermissions[r.Name], name) {
+				plan.RemoveRolePermissions = append(plan.RemoveRolePermissions, &RolePermission{Role: r.Name, Permission: name})
+			}
+		}
+		wantInherits := slices.Sorted(slices.Values(wantRole.Inherits))
+		for _, name := range slices.Compact(wantInherits) {
+			if !slices.Contains(r.Inherits, name) {
+				plan.AddRoleInherits = append(plan.AddRoleInherits, &RoleInherit{Role: r.Name, Inherits: name})
+			}
+		}
+		for _, name := range r.Inherits {
+			if !slices.Contains(wantInherits, name) {
+				plan.RemoveRoleInherits = append(plan.RemoveRoleInherits, &RoleInherit{Role: r.Name, Inherits: name})
+			}
+		}
+	}
+	for _, name := range slices.Sorted(maps.Keys(rolesAndPermissions)) {
+		if _, ok := have[name]; !ok {
+			plan.CreateRoles = append(plan.CreateRoles, &Role{
+				Name:        name,
+				Description: rolesAndPermissions[name].Description,
+				Permissions: wantRolePermissions[name],
+				Inherits:    slices.Compact(slices.Sorted(slices.Values(rolesAndPermissions[name].Inherits))),
+			})
+		}
+	}
+	return plan, have, nil
+}
+
+// ApplyPlan makes exactly the changes in the plan. It runs in a transaction,
+// and returns ErrPlanStale without making any changes if the roles and
+// permissions changed since the plan was computed in a way that conflicts
+// with it.
+func ApplyPlan(ctx context.Context, client *ent.Client, plan *Plan) error {
+	return NewEntStore(client).InTx(ctx, func(store Store) error {
+		return applyPlan(ctx, store, plan)
+	})
+}
+
+// applyPlan makes the changes in the plan to the store. Each change is
+// checked against the store first, so that conflicting changes made since the
+// plan was computed fail with ErrPlanStale.
+func applyPlan(ctx context.Context, store Store, plan *Plan) error {
+	// Permissions are created first, so that roles can reference them.
+	for _, p := range plan.CreatePermissions {
+		if _, err := store.CreatePermission(ctx, p); err != nil {
+			if errors.Is(err, ErrAlreadyExists) {
+				return fmt.Errorf("%w: permission %q exists", ErrPlanStale, p.Name)
+			}
+			return err
+		}
+	}
+	for _, c := range plan.UpdatePermissions {
+		p, err := store.FindPermission(ctx, c.Name)
+		if err != nil {
+			return planStale(err, "permission", c.Name)
+		}
+		if p.Description != c.From {
+			return fmt.Errorf("%w: permission %q changed", ErrPlanStale, c.Name)
+		}
+		if err := store.UpdatePermissionDescription(ctx, c.Name, c.To); err != nil {
+			return err
+		}
+	}
+	for _, r := range plan.CreateRoles {
+		_, err := store.CreateRole(ctx, &Role{Name: r.Name, Description: r.Description, Permissions: r.Permissions})
+		if errors.Is(err, ErrAlreadyExists) {
+			return fmt.Errorf("%w: role %q exists", ErrPlanStale, r.Name)
+		}
+		if errors.Is(err, ErrNotFound) {
+			return fmt.Errorf("%w: permissions changed", ErrPlanStale)
+		}
+		if err != nil {
+			return err
+		}
+	}
+	for _, c := range plan.UpdateRoles {
+		r, err := store.FindRole(ctx, c.Name)
+		if err != nil {
+			return planStale(err, "role", c.Name)
+		}
+		if r.Description != c.From {
+			return fmt.Errorf("%w: role %q changed", ErrPlanStale, c.Name)
+		}
+		if err := store.UpdateRoleDescription(ctx, c.Name, c.To); err != nil {
+			return err
+		}
+	}
+	for _, rp := range plan.AddRolePermissions {
+		r, err := store.FindRole(ctx, rp.Role)
+		if err != nil {
+			return planStale(err, "role", rp.Role)
+		}
+		if slices.Contains(r.Permissions, rp.Permission) {
+			return fmt.Errorf("%w: role %q changed", ErrPlanStale, rp.Role)
+		}
+		if err := store.AddRolePermission(ctx, rp.Role, rp.Permission); err != nil {
+			return planStale(err, "permission", rp.Permission)
+		}
+	}
+	for _, rp := range plan.RemoveRolePermissions {
+		r, err := store.FindRole(ctx, rp.Role)
+		if err != nil {
+			return planStale(err, "role", rp.Role)
+		}
+		if !slices.Contains(r.Permissions, rp.Permission) {
+			return fmt.Errorf("%w: role %q changed", ErrPlanStale, rp.Role)
+		}
+		if err := store.RemoveRolePermission(ctx, rp.Role, rp.Permission); err != nil {
+			return err
+		}
+	}
+	// Inheritance is removed before it is added, so that the inheritance
+	// being replaced can't make a cycle.
+	for _, ri := range plan.RemoveRoleInherits {
+		r, err := store.FindRole(ctx, ri.Role)
+		if err != nil {
+			return planStale(err, "role", ri.Role)
+		}
+		if !slices.Contains(r.Inherits, ri.Inherits) {
+			return fmt.Errorf("%w: role %q changed", ErrPlanStale, ri.Role)
+		}
+		inherits := slices.DeleteFunc(r.Inherits, func(name string) bool { return name == ri.Inherits })
+		if err := store.SetRoleInherits(ctx, ri.Role, inherits); err != nil {
+			return err
+		}
+	}
+	addInherits := slices.Clone(plan.AddRoleInherits)
+	for _, r := range plan.CreateRoles {
+		for _, parent := range r.Inherits {
+			addInherits = append(addInherits, &RoleInherit{Role: r.Name, Inherits: parent})
+		}
+	}
+	for _, ri := range addInherits {
+		r, err := store.FindRole(ctx, ri.Role)
+		if err != nil {
+			return planStale(err, "role", ri.Role)
+		}
+		if slices.Contains(r.Inherits, ri.Inherits) {
+			return fmt.Errorf("%w: role %q changed", ErrPlanStale, ri.Role)
+		}
+		// The planned inheritance has no cycles, but roles changed since the
+		// plan was computed might make one.
+		err = store.SetRoleInherits(ctx, ri.Role, append(r.Inherits, ri.Inherits))
+		if errors.Is(err, ErrRoleCycle) {
+			return fmt.Errorf("%w: %w", ErrPlanStale, err)
+		}
+		if err != nil {
+			return planStale(err, "role", ri.Inherits)
+		}
+	}
+	for _, name := range plan.DeleteRoles {
+		if err := store.DeleteRole(ctx, name); err != nil {
+			return planStale(err, "role", name)
+		}
+	}
+	// Permissions are deleted last, once no role has them. A permission
+	// that a role still has was given to it since the plan was computed.
+	if len(plan.DeletePermissions) > 0 {
+		roles, err := store.ListRoles(ctx)
+		if err != nil {
+			return err
+		}
+		for _, name := range plan.DeletePermissions {
+			for _, r := range roles {
+				if slices.Contains(r.Permissions, name) {
+					return fmt.Errorf("%w: permission %q changed", ErrPlanStale, name)
+				}
+			}
+			if err := store.DeletePermission(ctx, name); err != nil {
+				return planStale(err, "permission", name)
+			}
+		}
+	}
+	return nil
+}
+
+// planStale returns ErrPlanStale for a role or permission that a change in a
+// plan refers to, if err is ErrNotFound, and err otherwise.
+func planStale(err error, kind, name string) error {
+	if errors.Is(err, ErrNotFound) {
+		return fmt.Errorf("%w: %s %q changed", ErrPlanStale, kind, name)
+	}
+	return err
+}
+
+// permissionNames returns the sorted names of the permissions.
+func permissionNames(permissions []*ent.Permission) []string {
+	names := make([]string, len(permissions))
+	for i, p := range permissions {
+		names[i] = p.Name
+	}
+	slices.Sort(names)
+	return names
+}
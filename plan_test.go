@@ -0,0 +1,184 @@
+package users
+
+import (
+	"context"
+	"testing"
+
+	"github.com/smxlong/users/ent/permission"
+	"github.com/smxlong/users/ent/role"
+	"github.com/stretchr/testify/require"
+)
+
+func Test_that_PlanRolesAndPermissions_computes_the_changes(t *testing.T) {
+	client := setupAndMigrate(t)
+	ctx := context.Background()
+	require.NoError(t, CreateRolesAndPermissions(ctx, client, testMoreRolesAndPermissions))
+	u, err := Create(ctx, client, "user1", USER1_TEST_EMAIL, "password")
+	require.NoError(t, err)
+	x, err := client.Role.Query().Where(role.Name("x")).Only(ctx)
+	require.NoError(t, err)
+	_, err = AddRole(ctx, client, u, x)
+	require.NoError(t, err)
+	plan, err := PlanRolesAndPermissions(ctx, client, Roles{
+		"admin": &RoleWithPermissions{
+			Description: "Administrators",
+			Permissions: []*Permission{
+				{Name: "admin", Description: "Admin permission"},
+				{Name: "user", Description: "User permission"},
+			},
+		},
+		"user": &RoleWithPermissions{
+			Description: "User role",
+			Permissions: []*Permission{
+				{Name: "read", Description: "Read permission"},
+			},
+		},
+	})
+	require.NoError(t, err)
+	require.Equal(t, &Plan{
+		CreatePermissions:     []*Permission{{Name: "read", Description: "Read permission"}},
+		DeletePermissions:     []string{"x"},
+		UpdateRoles:           []*DescriptionChange{{Name: "admin", From: "Admin role", To: "Administrators"}},
+		DeleteRoles:           []string{"x"},
+		AddRolePermissions:    []*RolePermission{{Role: "admin", Permission: "user"}, {Role: "user", Permission: "read"}},
+		RemoveRolePermissions: []*RolePermission{{Role: "user", Permission: "user"}},
+		UsersLosingAccess:     []*AccessLoss{{User: "user1", Email: USER1_TEST_EMAIL, Roles: []string{"x"}, Permissions: []string{"x"}}},
+	}, plan)
+	require.Equal(t, `+ permission "read": "Read permission"
+~ role "admin": "Admin role" -> "Administrators"
++ role "admin" permission "user"
++ role "user" permission "read"
+- role "user" permission "user"
+- role "x"
+- permission "x"
+! user "user1" <`+USER1_TEST_EMAIL+`> loses roles [x] permissions [x]
+`, plan.String())
+	// nothing was changed
+	n, err := client.Role.Query().Count(ctx)
+	require.NoError(t, err)
+	require.Equal(t, 3, n)
+}
+
+func Test_that_ApplyPlan_applies_the_plan(t *testing.T) {
+	client := setupAndMigrate(t)
+	ctx := context.Background()
+	require.NoError(t, CreateRolesAndPermissions(ctx, client, testMoreRolesAndPermissions))
+	roles := Roles{
+		"admin": &RoleWithPermissions{
+			Description: "Admin role",
+			Permissions: []*Permission{{Name: "admin", Description: "Administer everything"}},
+		},
+		"viewer": &RoleWithPermissions{
+			Description: "Viewer role",
+			Permissions: []*Permission{{Name: "view", Description: "View permission"}},
+		},
+	}
+	plan, err := PlanRolesAndPermissions(ctx, client, roles)
+	require.NoError(t, err)
+	require.Equal(t, []*DescriptionChange{{Name: "admin", From: "Admin permission", To: "Administer everything"}}, plan.UpdatePermissions)
+	require.NoError(t, ApplyPlan(ctx, client, plan))
+	plan, err = PlanRolesAndPermissions(ctx, client, roles)
+	require.NoError(t, err)
+	require.True(t, plan.Empty())
+	require.Equal(t, "No changes.\n", plan.String())
+	names, err := client.Permission.Query().Order(permission.ByName()).Select(permission.FieldName).Strings(ctx)
+	require.NoError(t, err)
+	require.Equal(t, []string{"admin", "view"}, names)
+}
+
+func Test_that_ApplyPlan_fails_on_a_stale_plan(t *testing.T) {
+	client := setupAndMigrate(t)
+	ctx := context.Background()
+	require.NoError(t, CreateRolesAndPermissions(ctx, client, testRolesAndPermissions))
+	plan, err := PlanRolesAndPermissions(ctx, client, testMoreRolesAndPermissions)
+	require.NoError(t, err)
+	// The role the plan creates is created by someone else first.
+	_, err = CreateRole(ctx, client, "x", "X role")
+	require.NoError(t, err)
+	err = ApplyPlan(ctx, client, plan)
+	require.ErrorIs(t, err, ErrPlanStale)
+	// nothing was changed
+	n, err := client.Permission.Query().Count(ctx)
+	require.NoError(t, err)
+	require.Equal(t, 2, n)
+}
+
+func Test_that_PlanRolesAndPermissions_fails_with_mismatched_permission_descriptions(t *testing.T) {
+	client := setupAndMigrate(t)
+	ctx := context.Background()
+	_, err := PlanRolesAndPermissions(ctx, client, Roles{
+		"a": &RoleWithPermissions{Permissions: []*Permission{{Name: "p", Description: "P"}}},
+		"b": &RoleWithPermissions{Permissions: []*Permission{{Name: "p", Description: "Q"}}},
+	})
+	require.ErrorIs(t, err, ErrPermissionDescriptionMismatch)
+}
+
+func Test_that_PlanRolesAndPermissions_follows_inherited_roles(t *testing.T) {
+	client := setupAndMigrate(t)
+	ctx := context.Background()
+	require.NoError(t, CreateRolesAndPermissions(ctx, client, testInheritingRoles))
+	u, err := Create(ctx, client, "user1", USER1_TEST_EMAIL, "password")
+	require.NoError(t, err)
+	admin, err := client.Role.Query().Where(role.Name("admin")).Only(ctx)
+	require.NoError(t, err)
+	_, err = AddRole(ctx, client, u, admin)
+	require.NoError(t, err)
+	roles := Roles{
+		"viewer": testInheritingRoles["viewer"],
+		"editor": &RoleWithPermissions{
+			Description: "Editor role",
+			Permissions: []*Permission{{Name: "write", Description: "Write permission"}},
+		},
+		"admin":   testInheritingRoles["admin"],
+		"auditor": &RoleWithPermissions{Description: "Auditor role", Inherits: []string{"viewer"}},
+	}
+	plan, err := PlanRolesAndPermissions(ctx, client, roles)
+	require.NoError(t, err)
+	require.Equal(t, []*Role{{Name: "auditor", Description: "Auditor role", Inherits: []string{"viewer"}}}, plan.CreateRoles)
+	require.Equal(t, []*RoleInherit{{Role: "editor", Inherits: "viewer"}}, plan.RemoveRoleInherits)
+	require.Equal(t, []*AccessLoss{{User: "user1", Email: USER1_TEST_EMAIL, Permissions: []string{"read"}}}, plan.UsersLosingAccess)
+	require.Contains(t, plan.String(), `- role "editor" inherits "viewer"`)
+	require.NoError(t, ApplyPlan(ctx, client, plan))
+	plan, err = PlanRolesAndPermissions(ctx, client, roles)
+	require.NoError(t, err)
+	require.True(t, plan.Empty())
+	_, err = PlanRolesAndPermissions(ctx, client, Roles{
+		"a": &RoleWithPermissions{Inherits: []string{"a"}},
+	})
+	require.ErrorIs(t, err, ErrRoleCycle)
+}
+
+func Test_that_PlanRolesAndPermissions_lists_access_lost_on_resources(t *testing.T) {
+	client := setupAndMigrate(t)
+	ctx := context.Background()
+	roles := Roles{
+		"editor": &RoleWithPermissions{Permissions: []*Permission{{Name: "write"}}},
+		"viewer": &RoleWithPermissions{Permissions: []*Permission{{Name: "read"}}},
+	}
+	require.NoError(t, SyncRolesAndPermissions(ctx, client, roles))
+	u, err := Create(ctx, client, "user1", USER1_TEST_EMAIL, "password")
+	require.NoError(t, err)
+	editor, err := client.Role.Query().Where(role.Name("editor")).Only(ctx)
+	require.NoError(t, err)
+	viewer, err := client.Role.Query().Where(role.Name("viewer")).Only(ctx)
+	require.NoError(t, err)
+	project := Resource{Type: "project", ID: "42"}
+	_, err = AddRoleOn(ctx, client, u, editor, project)
+	require.NoError(t, err)
+	_, err = AddRoleOn(ctx, client, u, viewer, project)
+	require.NoError(t, err)
+	// deleting editor takes the role on the project, and viewer losing read
+	// takes read on the project
+	plan, err := PlanRolesAndPermissions(ctx, client, Roles{
+		"viewer": &RoleWithPermissions{},
+	})
+	require.NoError(t, err)
+	require.Equal(t, []*AccessLoss{{User: "user1", Email: USER1_TEST_EMAIL, Resources: []*ResourceAccessLoss{
+		{Resource: project, Roles: []string{"editor"}, Permissions: []string{"read", "write"}},
+	}}}, plan.UsersLosingAccess)
+	require.Contains(t, plan.String(), `! user "user1" <`+USER1_TEST_EMAIL+`> loses on project:42 roles [editor] permissions [read, write]`)
+	require.NoError(t, ApplyPlan(ctx, client, plan))
+	names, err := RolesOn(ctx, client, u, project, nil)
+	require.NoError(t, err)
+	require.Equal(t, []string{"viewer"}, names)
+}
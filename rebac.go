@@ -0,0 +1,663 @@
+package users
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"maps"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/smxlong/users/ent"
+	"github.com/smxlong/users/ent/predicate"
+	"github.com/smxlong/users/ent/relationtuple"
+	"github.com/smxlong/users/ent/user"
+)
+
+// SubjectTypeUser is the subject type of users. A user is the subject with
+// this type and the user's ID, as UserSubject gives for an ent user. The
+// users of a Service using an EntStore have the same IDs.
+const SubjectTypeUser = "user"
+
+// Subject is what a relation tuple relates an object to: an object such as a
+// user, or with a Relation, the userset of the subjects having that relation
+// on the object, such as the members of a group.
+type Subject struct {
+	Type string
+	ID   string
+	// Relation makes the subject a userset. Optional.
+	Relation string
+}
+
+// UserSubject returns the subject for a user.
+func UserSubject(u *ent.User) Subject {
+	return Subject{Type: SubjectTypeUser, ID: strconv.Itoa(u.ID)}
+}
+
+// String returns the subject as "type:id", or "type:id#relation" for a
+// userset.
+func (s Subject) String() string {
+	if s.Relation == "" {
+		return s.Type + ":" + s.ID
+	}
+	return s.Type + ":" + s.ID + "#" + s.Relation
+}
+
+// object returns the object the subject is, or whose relation it is.
+func (s Subject) object() Resource {
+	return Resource{Type: s.Type, ID: s.ID}
+}
+
+// Tuple relates an object to a subject through a relation. It's written
+// object#relation@subject, such as "document:readme#viewer@user:1", or
+// "document:readme#editor@group:eng#member" to relate the members of a group.
+type Tuple struct {
+	Object   Resource
+	Relation string
+	Subject  Subject
+}
+
+// String returns the tuple as object#relation@subject.
+func (t Tuple) String() string {
+	return t.Object.String() + "#" + t.Relation + "@" + t.Subject.String()
+}
+
+// ParseTuple parses a tuple written as object#relation@subject. It fails with
+// ErrTupleInvalid if the tuple is malformed.
+func ParseTuple(s string) (Tuple, error) {
+	object, subject, ok1 := strings.Cut(s, "@")
+	object, relation, ok2 := strings.Cut(object, "#")
+	objectType, objectID, ok3 := strings.Cut(object, ":")
+	subject, subjectRelation, userset := strings.Cut(subject, "#")
+	subjectType, subjectID, ok4 := strings.Cut(subject, ":")
+	if !ok1 || !ok2 || !ok3 || !ok4 || userset && subjectRelation == "" {
+		return Tuple{}, fmt.Errorf("%w: %q", ErrTupleInvalid, s)
+	}
+	t := Tuple{
+		Object:   Resource{Type: objectType, ID: objectID},
+		Relation: relation,
+		Subject:  Subject{Type: subjectType, ID: subjectID, Relation: subjectRelation},
+	}
+	if err := t.validate(); err != nil {
+		return Tuple{}, err
+	}
+	return t, nil
+}
+
+// validate returns ErrTupleInvalid if a part of the tuple is missing, or has
+// a character that would make its string ambiguous.
+func (t Tuple) validate() error {
+	for _, part := range []string{t.Object.Type, t.Object.ID, t.Relation, t.Subject.Type, t.Subject.ID, t.Subject.Relation} {
+		if strings.ContainsAny(part, "#@") {
+			return fmt.Errorf("%w: %q", ErrTupleInvalid, t.String())
+		}
+	}
+	if t.Object.Type == "" || t.Object.ID == "" || t.Relation == "" || t.Subject.Type == "" || t.Subject.ID == "" ||
+		strings.Contains(t.Object.Type, ":") || strings.Contains(t.Subject.Type, ":") {
+		return fmt.Errorf("%w: %q", ErrTupleInvalid, t.String())
+	}
+	return nil
+}
+
+// Namespaces configure the relations of object types, by type.
+type Namespaces map[string]*Namespace
+
+// Namespace configures the relations of an object type.
+type Namespace struct {
+	// Relations are the object type's relations, by name, each with the
+	// userset rewrite computing its subjects. A nil rewrite is This.
+	Relations map[string]Userset
+}
+
+// Userset is a userset rewrite, computing the subjects of a relation on an
+// object. It is This, ComputedUserset, TupleToUserset or Union.
+type Userset interface {
+	userset()
+}
+
+// This is the subjects the relation's own tuples relate the object to.
+type This struct{}
+
+// ComputedUserset is the subjects of another relation on the same object,
+// such as editors of a document being viewers of it.
+type ComputedUserset struct {
+	Relation string
+}
+
+// TupleToUserset is the subjects of a relation on the objects related to the
+// object by its Tupleset relation, such as viewers of a document's parent
+// folder being viewers of the document.
+type TupleToUserset struct {
+	// Tupleset is the relation relating the object to the other objects,
+	// such as "parent".
+	Tupleset string
+	// Relation is the relation on the other objects, such as "viewer".
+	Relation string
+}
+
+// Union is the subjects of any of the usersets.
+type Union []Userset
+
+func (This) userset()            {}
+func (ComputedUserset) userset() {}
+func (TupleToUserset) userset()  {}
+func (Union) userset()           {}
+
+// validate returns ErrNamespaceInvalid if a rewrite refers to a relation its
+// namespace doesn't have.
+func (ns Namespaces) validate() error {
+	for _, typ := range slices.Sorted(maps.Keys(ns)) {
+		n := ns[typ]
+		if n == nil {
+			continue
+		}
+		for _, relation := range slices.Sorted(maps.Keys(n.Relations)) {
+			if err := n.checkUserset(n.Relations[relation]); err != nil {
+				return fmt.Errorf("%w: %s#%s: %s", ErrNamespaceInvalid, typ, relation, err)
+			}
+		}
+	}
+	return nil
+}
+
+// checkUserset checks that a rewrite refers only to relations of the
+// namespace.
+func (n *Namespace) checkUserset(u Userset) error {
+	switch u := u.(type) {
+	case nil, This:
+	case ComputedUserset:
+		if _, ok := n.Relations[u.Relation]; !ok {
+			return fmt.Errorf("unknown relation %q", u.Relation)
+		}
+	case TupleToUserset:
+		if _, ok := n.Relations[u.Tupleset]; !ok {
+			return fmt.Errorf("unknown relation %q", u.Tupleset)
+		}
+		if u.Relation == "" {
+			return errors.New("tuple to userset has no relation")
+		}
+	case Union:
+		for _, child := range u {
+			if err := n.checkUserset(child); err != nil {
+				return err
+			}
+		}
+	default:
+		return fmt.Errorf("unsupported rewrite %T", u)
+	}
+	return nil
+}
+
+// rewrite returns the rewrite of a relation of an object type, or false if
+// the type has no such relation.
+func (ns Namespaces) rewrite(objectType, relation string) (Userset, bool) {
+	n := ns[objectType]
+	if n == nil {
+		return nil, false
+	}
+	u, ok := n.Relations[relation]
+	if !ok {
+		return nil, false
+	}
+	if u == nil {
+		u = This{}
+	}
+	return u, true
+}
+
+// checkRelation returns ErrRelationUnknown if the object type has no such
+// relation.
+func (ns Namespaces) checkRelation(objectType, relation string) error {
+	if _, ok := ns.rewrite(objectType, relation); !ok {
+		return fmt.Errorf("%w: %s#%s", ErrRelationUnknown, objectType, relation)
+	}
+	return nil
+}
+
+// checkTuple checks that a tuple is valid, and names relations the
+// namespaces have.
+func (ns Namespaces) checkTuple(t Tuple) error {
+	if err := t.validate(); err != nil {
+		return err
+	}
+	if err := ns.checkRelation(t.Object.Type, t.Relation); err != nil {
+		return err
+	}
+	if t.Subject.Relation != "" {
+		return ns.checkRelation(t.Subject.Type, t.Subject.Relation)
+	}
+	return nil
+}
+
+// WriteTuples writes relation tuples. Writing a tuple that's already written
+// succeeds. It fails with ErrTupleInvalid for malformed tuples, and with
+// ErrRelationUnknown for tuples naming relations the namespaces don't have.
+// Subjects of type SubjectTypeUser must be existing users, and their tuples
+// are removed along with the user.
+func WriteTuples(ctx context.Context, client *ent.Client, ns Namespaces, tuples ...Tuple) error {
+	if err := ns.validate(); err != nil {
+		return err
+	}
+	for _, t := range tuples {
+		if err := ns.checkTuple(t); err != nil {
+			return err
+		}
+	}
+	_, err := inTx(ctx, client, func(client *ent.Client) error {
+		for _, t := range tuples {
+			exists, err := client.RelationTuple.Query().
+				Where(tupleIs(t)).
+				Exist(ctx)
+			if err != nil {
+				return err
+			}
+			if exists {
+				continue
+			}
+			create := client.RelationTuple.Create().
+				SetObjectType(t.Object.Type).
+				SetObjectID(t.Object.ID).
+				SetRelation(t.Relation).
+				SetSubjectType(t.Subject.Type).
+				SetSubjectID(t.Subject.ID).
+				SetSubjectRelation(t.Subject.Relation)
+			if t.Subject.Type == SubjectTypeUser && t.Subject.Relation == "" {
+				id, err := strconv.Atoi(t.Subject.ID)
+				if err != nil {
+					return fmt.Errorf("%w: %q: user ID %q", ErrTupleInvalid, t.String(), t.Subject.ID)
+				}
+				if _, err := client.User.Get(ctx, id); err != nil {
+					return err
+				}
+				create.SetUserID(id)
+			}
+			if _, err := create.Save(ctx); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+	return err
+}
+
+// DeleteTuples deletes relation tuples. Deleting a tuple that isn't written
+// succeeds.
+func DeleteTuples(ctx context.Context, client *ent.Client, tuples ...Tuple) error {
+	_, err := inTx(ctx, client, func(client *ent.Client) error {
+		for _, t := range tuples {
+			if _, err := client.RelationTuple.Delete().Where(tupleIs(t)).Exec(ctx); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+	return err
+}
+
+// ReadTuples returns the tuples relating an object through a relation, or
+// through any relation if relation is empty, ordered by relation and
+// subject. Tuples relating deleted users are left out, so that deleted users
+// have no relations, until they are restored.
+func ReadTuples(ctx context.Context, client *ent.Client, object Resource, relation string) ([]Tuple, error) {
+	q := client.RelationTuple.Query().
+		Where(
+			relationtuple.ObjectType(object.Type),
+			relationtuple.ObjectID(object.ID),
+			relationtuple.Or(
+				relationtuple.Not(relationtuple.HasUser()),
+				relationtuple.HasUserWith(user.DeletedAtIsNil()),
+			),
+		)
+	if relation != "" {
+		q = q.Where(relationtuple.Relation(relation))
+	}
+	ets, err := q.
+		Order(
+			ent.Asc(relationtuple.FieldRelation),
+			ent.Asc(relationtuple.FieldSubjectType),
+			ent.Asc(relationtuple.FieldSubjectID),
+			ent.Asc(relationtuple.FieldSubjectRelation),
+		).
+		All(ctx)
+	if err != nil {
+		return nil, err
+	}
+	tuples := make([]Tuple, len(ets))
+	for i, et := range ets {
+		tuples[i] = Tuple{
+			Object:   Resource{Type: et.ObjectType, ID: et.ObjectID},
+			Relation: et.Relation,
+			Subject:  Subject{Type: et.SubjectType, ID: et.SubjectID, Relation: et.SubjectRelation},
+		}
+	}
+	return tuples, nil
+}
+
+// tupleIs is the predicate for a tuple.
+func tupleIs(t Tuple) predicate.RelationTuple {
+	return relationtuple.And(
+		relationtuple.ObjectType(t.Object.Type),
+		relationtuple.ObjectID(t.Object.ID),
+		relationtuple.Relation(t.Relation),
+		relationtuple.SubjectType(t.Subject.Type),
+		relationtuple.SubjectID(t.Subject.ID),
+		relationtuple.SubjectRelation(t.Subject.Relation),
+	)
+}
+
+// CheckRelation checks if a subject has a relation on an object, through the
+// relation's tuples and its rewrite. It fails with ErrRelationUnknown if the
+// object's type has no such relation.
+func CheckRelation(ctx context.Context, client *ent.Client, ns Namespaces, object Resource, relation string, subject Subject) (bool, error) {
+	if err := ns.validate(); err != nil {
+		return false, err
+	}
+	if err := ns.checkRelation(object.Type, relation); err != nil {
+		return false, err
+	}
+	return newRelationResolver(ctx, client, ns).checkObject(object, relation, subject)
+}
+
+// ExpandTree is the expansion of a relation on an object into the subjects
+// having it.
+type ExpandTree struct {
+	Object   Resource
+	Relation string
+	// Subjects are the subjects the relation's tuples relate the object to,
+	// including usersets.
+	Subjects []Subject
+	// Children are the expansions of the relations the relation includes,
+	// through its rewrite or its userset subjects.
+	Children []*ExpandTree
+}
+
+// Leaves returns the subjects in the tree that aren't usersets, ordered by
+// type and ID, without duplicates.
+func (t *ExpandTree) Leaves() []Subject {
+	var leaves []Subject
+	var walk func(t *ExpandTree)
+	walk = func(t *ExpandTree) {
+		for _, s := range t.Subjects {
+			if s.Relation == "" {
+				leaves = append(leaves, s)
+			}
+		}
+		for _, child := range t.Children {
+			walk(child)
+		}
+	}
+	walk(t)
+	slices.SortFunc(leaves, func(a, b Subject) int {
+		return strings.Compare(a.String(), b.String())
+	})
+	return slices.Compact(leaves)
+}
+
+// ExpandRelation expands a relation on an object into the tree of the
+// subjects having it. Each relation on an object is expanded once, where it's
+// first reached, so relations included more than once, or cyclically, have
+// no subtree elsewhere. It fails with ErrRelationUnknown if the object's type
+// has no such relation.
+func ExpandRelation(ctx context.Context, client *ent.Client, ns Namespaces, object Resource, relation string) (*ExpandTree, error) {
+	if err := ns.validate(); err != nil {
+		return nil, err
+	}
+	if err := ns.checkRelation(object.Type, relation); err != nil {
+		return nil, err
+	}
+	return newRelationResolver(ctx, client, ns).expand(object, relation)
+}
+
+// ListObjects returns the objects of a type a subject has a relation on,
+// ordered by ID. It fails with ErrRelationUnknown if the type has no such
+// relation. Every object of the type with tuples is checked, so its cost
+// grows with their number, but the tuples read and the relations checked
+// are shared between the checks, so that relations reached from many
+// objects, such as those of a common parent folder, are checked once.
+func ListObjects(ctx context.Context, client *ent.Client, ns Namespaces, objectType, relation string, subject Subject) ([]Resource, error) {
+	if err := ns.validate(); err != nil {
+		return nil, err
+	}
+	if err := ns.checkRelation(objectType, relation); err != nil {
+		return nil, err
+	}
+	// Objects without tuples have no relations, so only those with tuples
+	// are checked.
+	ids, err := client.RelationTuple.Query().
+		Where(relationtuple.ObjectType(objectType)).
+		Unique(true).
+		Order(ent.Asc(relationtuple.FieldObjectID)).
+		Select(relationtuple.FieldObjectID).
+		Strings(ctx)
+	if err != nil {
+		return nil, err
+	}
+	var objects []Resource
+	r := newRelationResolver(ctx, client, ns)
+	for _, id := range ids {
+		object := Resource{Type: objectType, ID: id}
+		ok, err := r.checkObject(object, relation, subject)
+		if err != nil {
+			return nil, err
+		}
+		if ok {
+			objects = append(objects, object)
+		}
+	}
+	return objects, nil
+}
+
+// objectRelation is a relation on an object.
+type objectRelation struct {
+	object   Resource
+	relation string
+}
+
+// subjectRelation is a subject's relation on an object.
+type subjectRelation struct {
+	objectRelation
+	subject Subject
+}
+
+// relationResolver resolves relations for one expansion, or for checks of
+// one request. Each check visits each relation on an object at most once, so
+// that cyclic rewrites and tuples end. The tuples read, and the results of
+// checks, are kept for the later checks.
+type relationResolver struct {
+	ctx      context.Context
+	client   *ent.Client
+	ns       Namespaces
+	visited  map[objectRelation]bool
+	tuplesOf map[objectRelation][]Tuple
+	// known are the results of checks that weren't cut short by a cycle.
+	known map[subjectRelation]bool
+	// cycles counts the checks cut short because their relation was
+	// visited.
+	cycles int
+}
+
+// newRelationResolver returns a relationResolver that has visited nothing.
+func newRelationResolver(ctx context.Context, client *ent.Client, ns Namespaces) *relationResolver {
+	return &relationResolver{
+		ctx:      ctx,
+		client:   client,
+		ns:       ns,
+		visited:  map[objectRelation]bool{},
+		tuplesOf: map[objectRelation][]Tuple{},
+		known:    map[subjectRelation]bool{},
+	}
+}
+
+// visit reports whether the relation on the object is yet to be visited,
+// marking it visited.
+func (r *relationResolver) visit(object Resource, relation string) bool {
+	key := objectRelation{object: object, relation: relation}
+	if r.visited[key] {
+		return false
+	}
+	r.visited[key] = true
+	return true
+}
+
+// tuples returns the tuples relating an object through a relation.
+func (r *relationResolver) tuples(object Resource, relation string) ([]Tuple, error) {
+	key := objectRelation{object: object, relation: relation}
+	if tuples, ok := r.tuplesOf[key]; ok {
+		return tuples, nil
+	}
+	tuples, err := ReadTuples(r.ctx, r.client, object, relation)
+	if err != nil {
+		return nil, err
+	}
+	r.tuplesOf[key] = tuples
+	return tuples, nil
+}
+
+// checkObject checks if the subject has the relation on the object, as a
+// new check, visiting again the relations earlier checks visited.
+func (r *relationResolver) checkObject(object Resource, relation string, subject Subject) (bool, error) {
+	clear(r.visited)
+	return r.check(object, relation, subject)
+}
+
+// check checks if the subject has the relation on the object. A relation
+// already visited is not checked again: either it is being checked, and a
+// cycle can't grant it, or it was, and didn't. A result reached without
+// cutting a cycle short holds for later checks too, and is kept.
+func (r *relationResolver) check(object Resource, relation string, subject Subject) (bool, error) {
+	// A userset includes itself.
+	if subject.Relation == relation && subject.object() == object {
+		return true, nil
+	}
+	key := subjectRelation{objectRelation: objectRelation{object: object, relation: relation}, subject: subject}
+	if ok, found := r.known[key]; found {
+		return ok, nil
+	}
+	if !r.visit(object, relation) {
+		r.cycles++
+		return false, nil
+	}
+	rewrite, ok := r.ns.rewrite(object.Type, relation)
+	if !ok {
+		return false, nil
+	}
+	cycles := r.cycles
+	ok, err := r.checkUserset(object, relation, rewrite, subject)
+	if err != nil {
+		return false, err
+	}
+	if ok || r.cycles == cycles {
+		r.known[key] = ok
+	}
+	return ok, nil
+}
+
+// checkUserset checks if the subject is in the userset of the relation on
+// the object.
+func (r *relationResolver) checkUserset(object Resource, relation string, u Userset, subject Subject) (bool, error) {
+	switch u := u.(type) {
+	case This:
+		tuples, err := r.tuples(object, relation)
+		if err != nil {
+			return false, err
+		}
+		for _, t := range tuples {
+			if t.Subject == subject {
+				return true, nil
+			}
+		}
+		for _, t := range tuples {
+			if t.Subject.Relation == "" {
+				continue
+			}
+			if ok, err := r.check(t.Subject.object(), t.Subject.Relation, subject); ok || err != nil {
+				return ok, err
+			}
+		}
+	case ComputedUserset:
+		return r.check(object, u.Relation, subject)
+	case TupleToUserset:
+		tuples, err := r.tuples(object, u.Tupleset)
+		if err != nil {
+			return false, err
+		}
+		for _, t := range tuples {
+			if ok, err := r.check(t.Subject.object(), u.Relation, subject); ok || err != nil {
+				return ok, err
+			}
+		}
+	case Union:
+		for _, child := range u {
+			if ok, err := r.checkUserset(object, relation, child, subject); ok || err != nil {
+				return ok, err
+			}
+		}
+	}
+	return false, nil
+}
+
+// expand expands the relation on the object, or returns nil if it was
+// already visited.
+func (r *relationResolver) expand(object Resource, relation string) (*ExpandTree, error) {
+	if !r.visit(object, relation) {
+		return nil, nil
+	}
+	tree := &ExpandTree{Object: object, Relation: relation}
+	rewrite, ok := r.ns.rewrite(object.Type, relation)
+	if !ok {
+		return tree, nil
+	}
+	if err := r.expandUserset(tree, rewrite); err != nil {
+		return nil, err
+	}
+	return tree, nil
+}
+
+// expandUserset adds the userset of the tree's relation to the tree.
+func (r *relationResolver) expandUserset(tree *ExpandTree, u Userset) error {
+	addChild := func(object Resource, relation string) error {
+		child, err := r.expand(object, relation)
+		if err != nil {
+			return err
+		}
+		if child != nil {
+			tree.Children = append(tree.Children, child)
+		}
+		return nil
+	}
+	switch u := u.(type) {
+	case This:
+		tuples, err := r.tuples(tree.Object, tree.Relation)
+		if err != nil {
+			return err
+		}
+		for _, t := range tuples {
+			tree.Subjects = append(tree.Subjects, t.Subject)
+			if t.Subject.Relation != "" {
+				if err := addChild(t.Subject.object(), t.Subject.Relation); err != nil {
+					return err
+				}
+			}
+		}
+	case ComputedUserset:
+		return addChild(tree.Object, u.Relation)
+	case TupleToUserset:
+		tuples, err := r.tuples(tree.Object, u.Tupleset)
+		if err != nil {
+			return err
+		}
+		for _, t := range tuples {
+			if err := addChild(t.Subject.object(), u.Relation); err != nil {
+				return err
+			}
+		}
+	case Union:
+		for _, child := range u {
+			if err := r.expandUserset(tree, child); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
@@ -0,0 +1,256 @@
+package users
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+// testNamespaces share documents with users and groups. Owners of a
+// document edit it, editors view it, and viewers of its parent folder view
+// it.
+var testNamespaces = Namespaces{
+	"group": {Relations: map[string]Userset{"member": nil}},
+	"folder": {Relations: map[string]Userset{
+		"owner":  nil,
+		"viewer": Union{This{}, ComputedUserset{Relation: "owner"}},
+	}},
+	"document": {Relations: map[string]Userset{
+		"parent": nil,
+		"owner":  nil,
+		"editor": Union{This{}, ComputedUserset{Relation: "owner"}},
+		"viewer": Union{
+			This{},
+			ComputedUserset{Relation: "editor"},
+			TupleToUserset{Tupleset: "parent", Relation: "viewer"},
+		},
+	}},
+}
+
+// writeTestTuples writes the tuples, parsed from their strings.
+func writeTestTuples(t *testing.T, ctx context.Context, write func(ctx context.Context, tuples ...Tuple) error, tuples ...string) {
+	parsed := make([]Tuple, len(tuples))
+	for i, s := range tuples {
+		var err error
+		parsed[i], err = ParseTuple(s)
+		require.NoError(t, err)
+	}
+	require.NoError(t, write(ctx, parsed...))
+}
+
+func Test_that_ParseTuple_parses_tuples(t *testing.T) {
+	tuple, err := ParseTuple("document:readme#editor@group:eng#member")
+	require.NoError(t, err)
+	require.Equal(t, Tuple{
+		Object:   Resource{Type: "document", ID: "readme"},
+		Relation: "editor",
+		Subject:  Subject{Type: "group", ID: "eng", Relation: "member"},
+	}, tuple)
+	require.Equal(t, "document:readme#editor@group:eng#member", tuple.String())
+	tuple, err = ParseTuple("document:a:b#viewer@user:1")
+	require.NoError(t, err)
+	require.Equal(t, Resource{Type: "document", ID: "a:b"}, tuple.Object)
+	require.Equal(t, Subject{Type: "user", ID: "1"}, tuple.Subject)
+	for _, s := range []string{"", "document:readme#viewer", "document:readme@user:1", "document#viewer@user:1", "document:readme#viewer@user", "document:#viewer@user:1", "document:readme#viewer@user:1#"} {
+		_, err := ParseTuple(s)
+		require.ErrorIs(t, err, ErrTupleInvalid, s)
+	}
+}
+
+func Test_that_CheckRelation_follows_rewrites_and_usersets(t *testing.T) {
+	client := setupAndMigrate(t)
+	ctx := context.Background()
+	u1, err := Create(ctx, client, "user1", USER1_TEST_EMAIL, "password")
+	require.NoError(t, err)
+	u2, err := Create(ctx, client, "user2", USER2_TEST_EMAIL, "password")
+	require.NoError(t, err)
+	write := func(ctx context.Context, tuples ...Tuple) error {
+		return WriteTuples(ctx, client, testNamespaces, tuples...)
+	}
+	writeTestTuples(t, ctx, write,
+		"document:readme#owner@user:"+UserSubject(u1).ID,
+		"document:readme#parent@folder:docs",
+		"folder:docs#viewer@group:eng#member",
+		"group:eng#member@group:core#member",
+		"group:core#member@group:eng#member",
+		"group:core#member@user:"+UserSubject(u2).ID,
+	)
+	readme := Resource{Type: "document", ID: "readme"}
+	for _, test := range []struct {
+		relation string
+		subject  Subject
+		ok       bool
+	}{
+		{"owner", UserSubject(u1), true},
+		{"editor", UserSubject(u1), true},
+		{"viewer", UserSubject(u1), true},
+		{"editor", UserSubject(u2), false},
+		{"viewer", UserSubject(u2), true},
+		{"viewer", Subject{Type: "group", ID: "eng", Relation: "member"}, true},
+		{"viewer", Subject{Type: "user", ID: "3"}, false},
+	} {
+		ok, err := CheckRelation(ctx, client, testNamespaces, readme, test.relation, test.subject)
+		require.NoError(t, err)
+		require.Equal(t, test.ok, ok, "%s %s", test.relation, test.subject)
+	}
+	_, err = CheckRelation(ctx, client, testNamespaces, readme, "commenter", UserSubject(u1))
+	require.ErrorIs(t, err, ErrRelationUnknown)
+}
+
+func Test_that_ExpandRelation_expands_the_subjects(t *testing.T) {
+	client := setupAndMigrate(t)
+	ctx := context.Background()
+	u1, err := Create(ctx, client, "user1", USER1_TEST_EMAIL, "password")
+	require.NoError(t, err)
+	u2, err := Create(ctx, client, "user2", USER2_TEST_EMAIL, "password")
+	require.NoError(t, err)
+	write := func(ctx context.Context, tuples ...Tuple) error {
+		return WriteTuples(ctx, client, testNamespaces, tuples...)
+	}
+	writeTestTuples(t, ctx, write,
+		"document:readme#owner@user:"+UserSubject(u1).ID,
+		"document:readme#viewer@group:eng#member",
+		"group:eng#member@user:"+UserSubject(u2).ID,
+		"group:eng#member@user:"+UserSubject(u1).ID,
+	)
+	tree, err := ExpandRelation(ctx, client, testNamespaces, Resource{Type: "document", ID: "readme"}, "viewer")
+	require.NoError(t, err)
+	require.Equal(t, []Subject{{Type: "group", ID: "eng", Relation: "member"}}, tree.Subjects)
+	require.Len(t, tree.Children, 2)
+	require.Equal(t, Resource{Type: "group", ID: "eng"}, tree.Children[0].Object)
+	require.Equal(t, "editor", tree.Children[1].Relation)
+	require.Equal(t, []Subject{UserSubject(u1), UserSubject(u2)}, tree.Leaves())
+}
+
+func Test_that_ListObjects_lists_the_related_objects(t *testing.T) {
+	client := setupAndMigrate(t)
+	ctx := context.Background()
+	u1, err := Create(ctx, client, "user1", USER1_TEST_EMAIL, "password")
+	require.NoError(t, err)
+	write := func(ctx context.Context, tuples ...Tuple) error {
+		return WriteTuples(ctx, client, testNamespaces, tuples...)
+	}
+	writeTestTuples(t, ctx, write,
+		"folder:docs#owner@user:"+UserSubject(u1).ID,
+		"document:b#parent@folder:docs",
+		"document:a#owner@user:"+UserSubject(u1).ID,
+		"document:c#parent@folder:other",
+	)
+	objects, err := ListObjects(ctx, client, testNamespaces, "document", "viewer", UserSubject(u1))
+	require.NoError(t, err)
+	require.Equal(t, []Resource{{Type: "document", ID: "a"}, {Type: "document", ID: "b"}}, objects)
+	objects, err = ListObjects(ctx, client, testNamespaces, "document", "editor", UserSubject(u1))
+	require.NoError(t, err)
+	require.Equal(t, []Resource{{Type: "document", ID: "a"}}, objects)
+}
+
+func Test_that_WriteTuples_checks_the_tuples(t *testing.T) {
+	client := setupAndMigrate(t)
+	ctx := context.Background()
+	u1, err := Create(ctx, client, "user1", USER1_TEST_EMAIL, "password")
+	require.NoError(t, err)
+	readme := Resource{Type: "document", ID: "readme"}
+	owner := Tuple{Object: readme, Relation: "owner", Subject: UserSubject(u1)}
+	require.NoError(t, WriteTuples(ctx, client, testNamespaces, owner, owner))
+	require.NoError(t, WriteTuples(ctx, client, testNamespaces, owner))
+	tuples, err := ReadTuples(ctx, client, readme, "")
+	require.NoError(t, err)
+	require.Equal(t, []Tuple{owner}, tuples)
+	err = WriteTuples(ctx, client, testNamespaces, Tuple{Object: readme, Relation: "commenter", Subject: UserSubject(u1)})
+	require.ErrorIs(t, err, ErrRelationUnknown)
+	err = WriteTuples(ctx, client, testNamespaces, Tuple{Object: readme, Relation: "viewer", Subject: Subject{Type: "group", ID: "eng", Relation: "owner"}})
+	require.ErrorIs(t, err, ErrRelationUnknown)
+	err = WriteTuples(ctx, client, testNamespaces, Tuple{Object: readme, Relation: "viewer", Subject: Subject{Type: SubjectTypeUser, ID: "alice"}})
+	require.ErrorIs(t, err, ErrTupleInvalid)
+	err = WriteTuples(ctx, client, testNamespaces, Tuple{Object: readme, Relation: "viewer", Subject: Subject{Type: SubjectTypeUser, ID: "12345"}})
+	require.Error(t, err)
+	err = WriteTuples(ctx, client, Namespaces{
+		"document": {Relations: map[string]Userset{"viewer": ComputedUserset{Relation: "editor"}}},
+	}, owner)
+	require.ErrorIs(t, err, ErrNamespaceInvalid)
+	require.NoError(t, DeleteTuples(ctx, client, owner, owner))
+	tuples, err = ReadTuples(ctx, client, readme, "")
+	require.NoError(t, err)
+	require.Empty(t, tuples)
+}
+
+func Test_that_tuples_are_removed_with_the_user(t *testing.T) {
+	client := setupAndMigrate(t)
+	ctx := context.Background()
+	u1, err := Create(ctx, client, "user1", USER1_TEST_EMAIL, "password")
+	require.NoError(t, err)
+	readme := Resource{Type: "document", ID: "readme"}
+	require.NoError(t, WriteTuples(ctx, client, testNamespaces,
+		Tuple{Object: readme, Relation: "owner", Subject: UserSubject(u1)},
+		Tuple{Object: readme, Relation: "viewer", Subject: Subject{Type: "group", ID: "eng", Relation: "member"}},
+	))
+	require.NoError(t, Delete(ctx, client, u1))
+	n, err := PurgeDeletedUsers(ctx, client, 0)
+	require.NoError(t, err)
+	require.Equal(t, 1, n)
+	tuples, err := ReadTuples(ctx, client, readme, "")
+	require.NoError(t, err)
+	require.Len(t, tuples, 1)
+	require.Equal(t, "viewer", tuples[0].Relation)
+}
+
+func Test_that_ListObjects_lists_objects_related_through_cycles(t *testing.T) {
+	client := setupAndMigrate(t)
+	ctx := context.Background()
+	u1, err := Create(ctx, client, "user1", USER1_TEST_EMAIL, "password")
+	require.NoError(t, err)
+	// Viewers of a node's parent are checked before its own viewers, so that
+	// checking a finds b's viewers through a cycle back to a.
+	ns := Namespaces{
+		"node": {Relations: map[string]Userset{
+			"parent": nil,
+			"viewer": Union{TupleToUserset{Tupleset: "parent", Relation: "viewer"}, This{}},
+		}},
+	}
+	write := func(ctx context.Context, tuples ...Tuple) error {
+		return WriteTuples(ctx, client, ns, tuples...)
+	}
+	writeTestTuples(t, ctx, write,
+		"node:a#parent@node:b",
+		"node:b#parent@node:a",
+		"node:a#viewer@user:"+UserSubject(u1).ID,
+	)
+	objects, err := ListObjects(ctx, client, ns, "node", "viewer", UserSubject(u1))
+	require.NoError(t, err)
+	require.Equal(t, []Resource{{Type: "node", ID: "a"}, {Type: "node", ID: "b"}}, objects)
+}
+
+func Test_that_deleted_users_have_no_relations(t *testing.T) {
+	client := setupAndMigrate(t)
+	ctx := context.Background()
+	u1, err := Create(ctx, client, "user1", USER1_TEST_EMAIL, "password")
+	require.NoError(t, err)
+	write := func(ctx context.Context, tuples ...Tuple) error {
+		return WriteTuples(ctx, client, testNamespaces, tuples...)
+	}
+	writeTestTuples(t, ctx, write,
+		"document:readme#owner@user:"+UserSubject(u1).ID,
+		"document:readme#viewer@group:eng#member",
+		"group:eng#member@user:"+UserSubject(u1).ID,
+	)
+	readme := Resource{Type: "document", ID: "readme"}
+	require.NoError(t, Delete(ctx, client, u1))
+	ok, err := CheckRelation(ctx, client, testNamespaces, readme, "viewer", UserSubject(u1))
+	require.NoError(t, err)
+	require.False(t, ok)
+	tree, err := ExpandRelation(ctx, client, testNamespaces, readme, "viewer")
+	require.NoError(t, err)
+	require.Empty(t, tree.Leaves())
+	objects, err := ListObjects(ctx, client, testNamespaces, "document", "viewer", UserSubject(u1))
+	require.NoError(t, err)
+	require.Empty(t, objects)
+	// restoring the user restores its relations
+	u1, err = FindByEmail(IncludeDeleted(ctx), client, USER1_TEST_EMAIL)
+	require.NoError(t, err)
+	_, err = Restore(ctx, client, u1)
+	require.NoError(t, err)
+	ok, err = CheckRelation(ctx, client, testNamespaces, readme, "viewer", UserSubject(u1))
+	require.NoError(t, err)
+	require.True(t, ok)
+}
@@ -0,0 +1,170 @@
+package users
+
+import (
+	"context"
+	"crypto/rand"
+	"math/big"
+	"strings"
+
+	"github.com/smxlong/users/ent"
+	"github.com/smxlong/users/ent/recoverycode"
+	"github.com/smxlong/users/ent/user"
+)
+
+// recoveryCodeAlphabet omits characters that are easily confused, such as 0
+// and o, or 1 and l.
+const recoveryCodeAlphabet = "abcdefghjkmnpqrstuvwxyz23456789"
+
+// recoveryCodeLength is the number of characters in a recovery code,
+// excluding the separator.
+const recoveryCodeLength = 10
+
+// GenerateRecoveryCodes generates n single-use recovery codes for a user. The
+// codes are returned for display to the user and only their hashes are
+// stored. It fails if the user has unused codes; use RegenerateRecoveryCodes
+// to replace them.
+func GenerateRecoveryCodes(ctx context.Context, client *ent.Client, u *ent.User, n int) ([]string, error) {
+	codes, hashes, err := newRecoveryCodes(n)
+	if err != nil {
+		return nil, err
+	}
+	_, err = inTx(ctx, client, func(client *ent.Client) error {
+		remaining, err := RecoveryCodesRemaining(ctx, client, u)
+		if err != nil {
+			return err
+		}
+		if remaining > 0 {
+			return ErrRecoveryCodesExist
+		}
+		return replaceRecoveryCodes(ctx, client, u, hashes)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return codes, nil
+}
+
+// RegenerateRecoveryCodes discards a user's recovery codes, used or not, and
+// generates n new ones. The codes are replaced in a transaction.
+func RegenerateRecoveryCodes(ctx context.Context, client *ent.Client, u *ent.User, n int) ([]string, error) {
+	codes, hashes, err := newRecoveryCodes(n)
+	if err != nil {
+		return nil, err
+	}
+	_, err = inTx(ctx, client, func(client *ent.Client) error {
+		return replaceRecoveryCodes(ctx, client, u, hashes)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return codes, nil
+}
+
+// newRecoveryCodes generates n recovery codes and their hashes.
+func newRecoveryCodes(n int) ([]string, []string, error) {
+	codes := make([]string, n)
+	hashes := make([]string, n)
+	for i := range codes {
+		code, err := newRecoveryCode()
+		if err != nil {
+			return nil, nil, err
+		}
+		ph, err := PasswordHashDefault(normalizeRecoveryCode(code))
+		if err != nil {
+			return nil, nil, err
+		}
+		codes[i] = code
+		hashes[i] = ph.String()
+	}
+	return codes, hashes, nil
+}
+
+// replaceRecoveryCodes replaces a user's recovery codes with the hashed
+// ones.
+func replaceRecoveryCodes(ctx context.Context, client *ent.Client, u *ent.User, hashes []string) error {
+	if _, err := client.RecoveryCode.Delete().
+		Where(recoverycode.HasUserWith(user.ID(u.ID))).
+		Exec(ctx); err != nil {
+		return err
+	}
+	builders := make([]*ent.RecoveryCodeCreate, len(hashes))
+	for i, hash := range hashes {
+		builders[i] = client.RecoveryCode.Create().
+			SetCodeHash(hash).
+			SetUser(u)
+	}
+	return client.RecoveryCode.CreateBulk(builders...).Exec(ctx)
+}
+
+// RecoveryCodesRemaining counts a user's unused recovery codes.
+func RecoveryCodesRemaining(ctx context.Context, client *ent.Client, u *ent.User) (int, error) {
+	return client.RecoveryCode.Query().
+		Where(
+			recoverycode.UsedAtIsNil(),
+			recoverycode.HasUserWith(user.ID(u.ID)),
+		).
+		Count(ctx)
+}
+
+// UseRecoveryCode checks a recovery code for a user and marks it used. Each
+// code is accepted only once.
+func UseRecoveryCode(ctx context.Context, client *ent.Client, u *ent.User, code string) error {
+	unused, err := u.QueryRecoveryCodes().
+		Where(recoverycode.UsedAtIsNil()).
+		All(ctx)
+	if err != nil {
+		return err
+	}
+	code = normalizeRecoveryCode(code)
+	for _, rc := range unused {
+		ph, err := PasswordHashParse(rc.CodeHash)
+		if err != nil {
+			return err
+		}
+		if ph.Verify(code) != nil {
+			continue
+		}
+		// Only mark the code used if no concurrent request already did, so
+		// that a code can't be used twice by racing requests.
+		n, err := client.RecoveryCode.Update().
+			Where(recoverycode.ID(rc.ID), recoverycode.UsedAtIsNil()).
+			SetUsedAt(clockNow(ctx)).
+			Save(ctx)
+		if err != nil {
+			return err
+		}
+		if n == 0 {
+			break
+		}
+		return nil
+	}
+	return ErrRecoveryCodeInvalid
+}
+
+// newRecoveryCode generates a random recovery code formatted for display.
+func newRecoveryCode() (string, error) {
+	var b strings.Builder
+	max := big.NewInt(int64(len(recoveryCodeAlphabet)))
+	for i := 0; i < recoveryCodeLength; i++ {
+		if i == recoveryCodeLength/2 {
+			b.WriteByte('-')
+		}
+		n, err := rand.Int(rand.Reader, max)
+		if err != nil {
+			return "", err
+		}
+		b.WriteByte(recoveryCodeAlphabet[n.Int64()])
+	}
+	return b.String(), nil
+}
+
+// normalizeRecoveryCode removes formatting from a recovery code as entered by
+// a user.
+func normalizeRecoveryCode(code string) string {
+	return strings.Map(func(r rune) rune {
+		if r == '-' || r == ' ' {
+			return -1
+		}
+		return r
+	}, strings.ToLower(code))
+}
@@ -0,0 +1,96 @@
+package users
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func Test_that_GenerateRecoveryCodes_works(t *testing.T) {
+	client := setupAndMigrate(t)
+	ctx := context.Background()
+	u, err := Create(ctx, client, "user1", USER1_TEST_EMAIL, "password")
+	require.NoError(t, err)
+	codes, err := GenerateRecoveryCodes(ctx, client, u, 5)
+	require.NoError(t, err)
+	require.Len(t, codes, 5)
+	for _, code := range codes {
+		require.Len(t, code, recoveryCodeLength+1)
+		require.False(t, isTOTPCode(code))
+	}
+	n, err := RecoveryCodesRemaining(ctx, client, u)
+	require.NoError(t, err)
+	require.Equal(t, 5, n)
+	rcs, err := u.QueryRecoveryCodes().All(ctx)
+	require.NoError(t, err)
+	for _, rc := range rcs {
+		for _, code := range codes {
+			require.NotContains(t, rc.CodeHash, code)
+		}
+	}
+}
+
+func Test_that_GenerateRecoveryCodes_fails_with_unused_codes(t *testing.T) {
+	client := setupAndMigrate(t)
+	ctx := context.Background()
+	u, err := Create(ctx, client, "user1", USER1_TEST_EMAIL, "password")
+	require.NoError(t, err)
+	_, err = GenerateRecoveryCodes(ctx, client, u, 2)
+	require.NoError(t, err)
+	_, err = GenerateRecoveryCodes(ctx, client, u, 2)
+	require.ErrorIs(t, err, ErrRecoveryCodesExist)
+}
+
+func Test_that_RegenerateRecoveryCodes_replaces_codes(t *testing.T) {
+	client := setupAndMigrate(t)
+	ctx := context.Background()
+	u, err := Create(ctx, client, "user1", USER1_TEST_EMAIL, "password")
+	require.NoError(t, err)
+	old, err := GenerateRecoveryCodes(ctx, client, u, 2)
+	require.NoError(t, err)
+	codes, err := RegenerateRecoveryCodes(ctx, client, u, 3)
+	require.NoError(t, err)
+	require.Len(t, codes, 3)
+	n, err := RecoveryCodesRemaining(ctx, client, u)
+	require.NoError(t, err)
+	require.Equal(t, 3, n)
+	require.ErrorIs(t, UseRecoveryCode(ctx, client, u, old[0]), ErrRecoveryCodeInvalid)
+}
+
+func Test_that_UseRecoveryCode_accepts_each_code_once(t *testing.T) {
+	client := setupAndMigrate(t)
+	ctx := context.Background()
+	u, err := Create(ctx, client, "user1", USER1_TEST_EMAIL, "password")
+	require.NoError(t, err)
+	codes, err := GenerateRecoveryCodes(ctx, client, u, 2)
+	require.NoError(t, err)
+	// formatting as entered by the user is ignored
+	require.NoError(t, UseRecoveryCode(ctx, client, u, " "+strings.ToUpper(codes[0])))
+	require.ErrorIs(t, UseRecoveryCode(ctx, client, u, codes[0]), ErrRecoveryCodeInvalid)
+	n, err := RecoveryCodesRemaining(ctx, client, u)
+	require.NoError(t, err)
+	require.Equal(t, 1, n)
+	require.ErrorIs(t, UseRecoveryCode(ctx, client, u, "wrong-codes"), ErrRecoveryCodeInvalid)
+}
+
+func Test_that_CompleteMFALogin_accepts_recovery_code(t *testing.T) {
+	client := setupAndMigrate(t)
+	ctx := context.Background()
+	u, _ := setupMFAUser(t, client)
+	codes, err := GenerateRecoveryCodes(ctx, client, u, 2)
+	require.NoError(t, err)
+	_, challenge, err := LoginMFA(ctx, client, u, "password", testMFAOptions())
+	require.NoError(t, err)
+	u2, err := CompleteMFALogin(ctx, client, challenge, codes[1], testMFAOptions())
+	require.NoError(t, err)
+	require.Equal(t, u.ID, u2.ID)
+	_, challenge, err = LoginMFA(ctx, client, u, "password", testMFAOptions())
+	require.NoError(t, err)
+	_, err = CompleteMFALogin(ctx, client, challenge, codes[1], testMFAOptions())
+	require.ErrorIs(t, err, ErrRecoveryCodeInvalid)
+	n, err := RecoveryCodesRemaining(ctx, client, u)
+	require.NoError(t, err)
+	require.Equal(t, 1, n)
+}
@@ -0,0 +1,70 @@
+package users
+
+import (
+	"context"
+	"time"
+
+	"github.com/smxlong/users/ent"
+	"github.com/smxlong/users/ent/onetimetoken"
+	"github.com/smxlong/users/ent/user"
+)
+
+// passwordResetValidFor is the default validity of password reset tokens.
+const passwordResetValidFor = 30 * time.Minute
+
+// RequestPasswordReset emails a password reset link to the user with the
+// given email address. To avoid revealing which addresses have accounts, it
+// returns nil whether or not the user exists, and the link is sent in the
+// background, with errors passed to the EmailOptions OnError. The link is
+// issued with the client, so that it is part of the caller's transaction, if
+// any.
+func RequestPasswordReset(ctx context.Context, client *ent.Client, email string, opts *EmailOptions) error {
+	u, err := FindByEmail(ctx, client, email)
+	if ent.IsNotFound(err) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	return opts.sendInBackground(ctx, client, u, onetimetoken.PurposePasswordReset, "",
+		opts.getSubject("Reset your password"),
+		"Follow this link to reset your password:",
+		opts.getValidFor(passwordResetValidFor))
+}
+
+// ResetPassword checks a password reset token and sets the user's password.
+// The new password must satisfy the policy. All tokens previously issued to
+// the user are revoked, as are any other outstanding reset tokens. The changes
+// are made in a transaction. If policy is nil, the password isn't checked.
+func ResetPassword(ctx context.Context, client *ent.Client, token, password string, policy *PasswordPolicy) (*ent.User, error) {
+	if policy != nil {
+		if err := policy.Validate(password); err != nil {
+			return nil, err
+		}
+	}
+	ph, err := PasswordHashDefault(password)
+	if err != nil {
+		return nil, err
+	}
+	return inTxEntity(ctx, client, func(client *ent.Client) (*ent.User, error) {
+		u, err := consumeOneTimeToken(ctx, client, token, onetimetoken.PurposePasswordReset, "")
+		if err != nil {
+			return nil, err
+		}
+		if _, err := client.OneTimeToken.Delete().
+			Where(
+				onetimetoken.PurposeEQ(onetimetoken.PurposePasswordReset),
+				onetimetoken.UsedAtIsNil(),
+				onetimetoken.HasUserWith(user.ID(u.ID)),
+			).
+			Exec(ctx); err != nil {
+			return nil, err
+		}
+		// Revoke tokens in the same update, as RevokeTokens would.
+		return u.Update().
+			SetPasswordHash(ph.String()).
+			SetPasswordChangedAt(clockNow(ctx)).
+			AddTokenGeneration(1).
+			Save(ctx)
+	})
+}
@@ -0,0 +1,123 @@
+package users
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func Test_that_RequestPasswordReset_emails_a_link(t *testing.T) {
+	client := setupAndMigrate(t)
+	ctx := context.Background()
+	_, err := Create(ctx, client, "user1", USER1_TEST_EMAIL, "password")
+	require.NoError(t, err)
+	m := &MemoryMailer{}
+	require.NoError(t, RequestPasswordReset(ctx, client, USER1_TEST_EMAIL, testEmailOptions(m)))
+	msgs := testWaitForMessages(t, m, 1)
+	require.Len(t, msgs, 1)
+	require.Equal(t, []string{USER1_TEST_EMAIL}, msgs[0].To)
+	require.NotEmpty(t, testEmailedToken(t, msgs[0]))
+}
+
+func Test_that_RequestPasswordReset_responds_identically_for_unknown_email(t *testing.T) {
+	client := setupAndMigrate(t)
+	ctx := context.Background()
+	m := &MemoryMailer{}
+	require.NoError(t, RequestPasswordReset(ctx, client, USER2_TEST_EMAIL, testEmailOptions(m)))
+	require.Empty(t, m.Messages())
+}
+
+func Test_that_RequestPasswordReset_passes_mailer_errors_to_OnError(t *testing.T) {
+	client := setupAndMigrate(t)
+	ctx := context.Background()
+	_, err := Create(ctx, client, "user1", USER1_TEST_EMAIL, "password")
+	require.NoError(t, err)
+	errs := make(chan error, 1)
+	opts := testEmailOptions(failingMailer{})
+	opts.OnError = func(err error) { errs <- err }
+	require.NoError(t, RequestPasswordReset(ctx, client, USER1_TEST_EMAIL, opts))
+	require.ErrorIs(t, <-errs, errTestMailer)
+}
+
+func Test_that_ResetPassword_works(t *testing.T) {
+	client := setupAndMigrate(t)
+	ctx := context.Background()
+	_, err := Create(ctx, client, "user1", USER1_TEST_EMAIL, "password")
+	require.NoError(t, err)
+	m := &MemoryMailer{}
+	require.NoError(t, RequestPasswordReset(ctx, client, USER1_TEST_EMAIL, testEmailOptions(m)))
+	testWaitForMessages(t, m, 1)
+	token := testEmailedToken(t, m.Messages()[0])
+	_, err = ResetPassword(ctx, client, token, "new password", &PasswordPolicy{})
+	require.NoError(t, err)
+	_, err = LoginByName(ctx, client, "user1", "password")
+	require.ErrorIs(t, err, ErrInvalidCredentials)
+	_, err = LoginByName(ctx, client, "user1", "new password")
+	require.NoError(t, err)
+	_, err = ResetPassword(ctx, client, token, "newer password", &PasswordPolicy{})
+	require.ErrorIs(t, err, ErrTokenInvalid)
+}
+
+func Test_that_ResetPassword_applies_the_policy_without_using_the_token(t *testing.T) {
+	client := setupAndMigrate(t)
+	ctx := context.Background()
+	u, err := Create(ctx, client, "user1", USER1_TEST_EMAIL, "password")
+	require.NoError(t, err)
+	token, err := newOneTimeToken(ctx, client, u, "password_reset", passwordResetValidFor, "")
+	require.NoError(t, err)
+	_, err = ResetPassword(ctx, client, token, "short", &PasswordPolicy{})
+	require.ErrorIs(t, err, ErrPasswordPolicy)
+	_, err = ResetPassword(ctx, client, token, "long enough", &PasswordPolicy{})
+	require.NoError(t, err)
+}
+
+func Test_that_ResetPassword_accepts_a_nil_policy(t *testing.T) {
+	client := setupAndMigrate(t)
+	ctx := context.Background()
+	u, err := Create(ctx, client, "user1", USER1_TEST_EMAIL, "password")
+	require.NoError(t, err)
+	token, err := newOneTimeToken(ctx, client, u, "password_reset", passwordResetValidFor, "")
+	require.NoError(t, err)
+	_, err = ResetPassword(ctx, client, token, "short", nil)
+	require.NoError(t, err)
+	_, err = LoginByName(ctx, client, "user1", "short")
+	require.NoError(t, err)
+}
+
+func Test_that_ResetPassword_revokes_tokens(t *testing.T) {
+	client := setupAndMigrate(t)
+	ctx := context.Background()
+	u, err := Create(ctx, client, "user1", USER1_TEST_EMAIL, "password")
+	require.NoError(t, err)
+	opts := &TokenOptions{Secret: "foo"}
+	tok, err := NewToken(u, opts)
+	require.NoError(t, err)
+	m := &MemoryMailer{}
+	require.NoError(t, RequestPasswordReset(ctx, client, USER1_TEST_EMAIL, testEmailOptions(m)))
+	require.NoError(t, RequestPasswordReset(ctx, client, USER1_TEST_EMAIL, testEmailOptions(m)))
+	testWaitForMessages(t, m, 2)
+	u, err = ResetPassword(ctx, client, testEmailedToken(t, m.Messages()[0]), "new password", &PasswordPolicy{})
+	require.NoError(t, err)
+	_, err = ValidateToken(ctx, client, tok, opts)
+	require.ErrorIs(t, err, ErrTokenRevoked)
+	// the other outstanding reset token is revoked too
+	_, err = ResetPassword(ctx, client, testEmailedToken(t, m.Messages()[1]), "newer password", &PasswordPolicy{})
+	require.ErrorIs(t, err, ErrTokenInvalid)
+	// new tokens are valid
+	tok, err = NewToken(u, opts)
+	require.NoError(t, err)
+	_, err = ValidateToken(ctx, client, tok, opts)
+	require.NoError(t, err)
+}
+
+func Test_that_ResetPassword_rejects_email_verification_tokens(t *testing.T) {
+	client := setupAndMigrate(t)
+	ctx := context.Background()
+	u, err := Create(ctx, client, "user1", USER1_TEST_EMAIL, "password")
+	require.NoError(t, err)
+	token, err := NewEmailVerificationToken(ctx, client, u, 0)
+	require.NoError(t, err)
+	_, err = ResetPassword(ctx, client, token, "new password", &PasswordPolicy{})
+	require.ErrorIs(t, err, ErrTokenInvalid)
+}
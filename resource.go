@@ -0,0 +1,174 @@
+package users
+
+import (
+	"context"
+	"fmt"
+	"slices"
+
+	"github.com/smxlong/users/ent"
+	"github.com/smxlong/users/ent/predicate"
+	"github.com/smxlong/users/ent/role"
+	"github.com/smxlong/users/ent/roleassignment"
+	"github.com/smxlong/users/ent/user"
+)
+
+// Resource identifies something roles can be given on, such as project 42.
+type Resource struct {
+	// Type is the type of the resource, such as "project".
+	Type string
+	// ID is the ID of the resource.
+	ID string
+}
+
+// String returns the resource as "type:id".
+func (r Resource) String() string {
+	return r.Type + ":" + r.ID
+}
+
+// validate returns ErrResourceInvalid if the resource's type or ID is empty.
+func (r Resource) validate() error {
+	if r.Type == "" || r.ID == "" {
+		return fmt.Errorf("%w: %q", ErrResourceInvalid, r.String())
+	}
+	return nil
+}
+
+// ResourceHierarchy resolves the parents of resources. A role given on a
+// resource applies to the resource's descendants as well.
+type ResourceHierarchy interface {
+	// Parent returns the parent of a resource, or nil if it has none.
+	Parent(ctx context.Context, r Resource) (*Resource, error)
+}
+
+// ResourceHierarchyFunc is a function implementing ResourceHierarchy.
+type ResourceHierarchyFunc func(ctx context.Context, r Resource) (*Resource, error)
+
+// Parent returns the parent of a resource, or nil if it has none.
+func (f ResourceHierarchyFunc) Parent(ctx context.Context, r Resource) (*Resource, error) {
+	return f(ctx, r)
+}
+
+// resourceAncestry returns the resource followed by its ancestors, nearest
+// first. With no hierarchy, the resource has no ancestors. It fails with
+// ErrResourceCycle if the resource is its own ancestor.
+func resourceAncestry(ctx context.Context, hierarchy ResourceHierarchy, r Resource) ([]Resource, error) {
+	if err := r.validate(); err != nil {
+		return nil, err
+	}
+	ancestry := []Resource{r}
+	if hierarchy == nil {
+		return ancestry, nil
+	}
+	for {
+		parent, err := hierarchy.Parent(ctx, ancestry[len(ancestry)-1])
+		if err != nil {
+			return nil, err
+		}
+		if parent == nil {
+			return ancestry, nil
+		}
+		if err := parent.validate(); err != nil {
+			return nil, err
+		}
+		if slices.Contains(ancestry, *parent) {
+			return nil, fmt.Errorf("%w: %s", ErrResourceCycle, parent)
+		}
+		ancestry = append(ancestry, *parent)
+	}
+}
+
+// AddRoleOn gives a user a role on a resource. Giving a user a role it
+// already has on the resource succeeds.
+func AddRoleOn(ctx context.Context, client *ent.Client, u *ent.User, r *ent.Role, resource Resource) (*ent.RoleAssignment, error) {
+	if err := resource.validate(); err != nil {
+		return nil, err
+	}
+	return inTxEntity(ctx, client, func(client *ent.Client) (*ent.RoleAssignment, error) {
+		a, err := client.RoleAssignment.Query().
+			Where(roleAssignmentOn(u, r, resource)).
+			Only(ctx)
+		if !ent.IsNotFound(err) {
+			return a, err
+		}
+		return client.RoleAssignment.Create().
+			SetUser(u).
+			SetRole(r).
+			SetResourceType(resource.Type).
+			SetResourceID(resource.ID).
+			Save(ctx)
+	})
+}
+
+// RemoveRoleOn takes a role on a resource from a user. Taking a role the
+// user doesn't have on the resource succeeds. Roles given on every resource,
+// and on the resource's ancestors, aren't taken.
+func RemoveRoleOn(ctx context.Context, client *ent.Client, u *ent.User, r *ent.Role, resource Resource) error {
+	_, err := client.RoleAssignment.Delete().
+		Where(roleAssignmentOn(u, r, resource)).
+		Exec(ctx)
+	return err
+}
+
+// roleAssignmentOn is the predicate for a user's assignment of a role on a
+// resource.
+func roleAssignmentOn(u *ent.User, r *ent.Role, resource Resource) predicate.RoleAssignment {
+	return roleassignment.And(
+		roleassignment.HasUserWith(user.ID(u.ID)),
+		roleassignment.HasRoleWith(role.ID(r.ID)),
+		roleassignment.ResourceType(resource.Type),
+		roleassignment.ResourceID(resource.ID),
+	)
+}
+
+// RolesOn returns the names of the roles a user has on a resource, ordered
+// by name: the roles given on every resource, and those given on the
+// resource or its ancestors. A nil hierarchy gives resources no ancestors.
+func RolesOn(ctx context.Context, client *ent.Client, u *ent.User, resource Resource, hierarchy ResourceHierarchy) ([]string, error) {
+	ancestry, err := resourceAncestry(ctx, hierarchy, resource)
+	if err != nil {
+		return nil, err
+	}
+	roles, err := client.User.QueryRoles(u).
+		Select(role.FieldName).
+		Strings(ctx)
+	if err != nil {
+		return nil, err
+	}
+	on := make([]predicate.RoleAssignment, len(ancestry))
+	for i, r := range ancestry {
+		on[i] = roleassignment.And(
+			roleassignment.ResourceType(r.Type),
+			roleassignment.ResourceID(r.ID),
+		)
+	}
+	scoped, err := client.RoleAssignment.Query().
+		Where(
+			roleassignment.HasUserWith(user.ID(u.ID)),
+			roleassignment.Or(on...),
+		).
+		QueryRole().
+		Select(role.FieldName).
+		Strings(ctx)
+	if err != nil {
+		return nil, err
+	}
+	roles = append(roles, scoped...)
+	slices.Sort(roles)
+	return slices.Compact(roles), nil
+}
+
+// CheckPermissionOn checks if a user has a permission on a resource, through
+// the roles given on every resource, or on the resource or its ancestors, and
+// the roles they inherit. A nil hierarchy gives resources no ancestors.
+// Granted wildcard permissions match as they do for CheckPermission.
+func CheckPermissionOn(ctx context.Context, client *ent.Client, u *ent.User, p string, resource Resource, hierarchy ResourceHierarchy) (bool, error) {
+	roles, err := RolesOn(ctx, client, u, resource, hierarchy)
+	if err != nil {
+		return false, err
+	}
+	g, err := loadRoleAncestry(ctx, client, roles)
+	if err != nil {
+		return false, err
+	}
+	return hasPermissions(g.permissionNames(roles), []string{p}), nil
+}
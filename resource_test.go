@@ -0,0 +1,86 @@
+package users
+
+import (
+	"context"
+	"testing"
+
+	"github.com/smxlong/users/ent/role"
+	"github.com/stretchr/testify/require"
+)
+
+// testFolders makes folders the parents of files, and folder "a" the parent
+// of folder "b".
+var testFolders = ResourceHierarchyFunc(func(ctx context.Context, r Resource) (*Resource, error) {
+	switch {
+	case r.Type == "file":
+		return &Resource{Type: "folder", ID: "b"}, nil
+	case r == Resource{Type: "folder", ID: "b"}:
+		return &Resource{Type: "folder", ID: "a"}, nil
+	}
+	return nil, nil
+})
+
+func Test_that_CheckPermissionOn_honours_roles_on_ancestors(t *testing.T) {
+	client := setupAndMigrate(t)
+	ctx := context.Background()
+	require.NoError(t, CreateRolesAndPermissions(ctx, client, testInheritingRoles))
+	u, err := Create(ctx, client, "user1", USER1_TEST_EMAIL, "password")
+	require.NoError(t, err)
+	editor, err := client.Role.Query().Where(role.Name("editor")).Only(ctx)
+	require.NoError(t, err)
+	a := Resource{Type: "folder", ID: "a"}
+	file := Resource{Type: "file", ID: "f"}
+	_, err = AddRoleOn(ctx, client, u, editor, a)
+	require.NoError(t, err)
+	_, err = AddRoleOn(ctx, client, u, editor, a)
+	require.NoError(t, err)
+	for _, p := range []string{"write", "read"} {
+		ok, err := CheckPermissionOn(ctx, client, u, p, file, testFolders)
+		require.NoError(t, err)
+		require.True(t, ok, p)
+	}
+	ok, err := CheckPermissionOn(ctx, client, u, "delete", file, testFolders)
+	require.NoError(t, err)
+	require.False(t, ok)
+	// without the hierarchy, the file has no ancestors
+	ok, err = CheckPermissionOn(ctx, client, u, "write", file, nil)
+	require.NoError(t, err)
+	require.False(t, ok)
+	ok, err = CheckPermission(ctx, client, u, "write")
+	require.NoError(t, err)
+	require.False(t, ok)
+	require.NoError(t, RemoveRoleOn(ctx, client, u, editor, a))
+	roles, err := RolesOn(ctx, client, u, file, testFolders)
+	require.NoError(t, err)
+	require.Empty(t, roles)
+}
+
+func Test_that_CheckPermissionOn_detects_hierarchy_cycles(t *testing.T) {
+	client := setupAndMigrate(t)
+	ctx := context.Background()
+	u, err := Create(ctx, client, "user1", USER1_TEST_EMAIL, "password")
+	require.NoError(t, err)
+	loop := ResourceHierarchyFunc(func(ctx context.Context, r Resource) (*Resource, error) {
+		return &Resource{Type: "folder", ID: "a"}, nil
+	})
+	_, err = CheckPermissionOn(ctx, client, u, "read", Resource{Type: "folder", ID: "b"}, loop)
+	require.ErrorIs(t, err, ErrResourceCycle)
+	_, err = CheckPermissionOn(ctx, client, u, "read", Resource{Type: "folder"}, nil)
+	require.ErrorIs(t, err, ErrResourceInvalid)
+}
+
+func Test_that_role_assignments_are_removed_with_the_role(t *testing.T) {
+	client := setupAndMigrate(t)
+	ctx := context.Background()
+	require.NoError(t, CreateRolesAndPermissions(ctx, client, testInheritingRoles))
+	u, err := Create(ctx, client, "user1", USER1_TEST_EMAIL, "password")
+	require.NoError(t, err)
+	editor, err := client.Role.Query().Where(role.Name("editor")).Only(ctx)
+	require.NoError(t, err)
+	_, err = AddRoleOn(ctx, client, u, editor, Resource{Type: "folder", ID: "a"})
+	require.NoError(t, err)
+	require.NoError(t, DeleteRole(ctx, client, editor))
+	n, err := client.RoleAssignment.Query().Count(ctx)
+	require.NoError(t, err)
+	require.Zero(t, n)
+}
@@ -42,6 +42,48 @@ func RemoveRolePermission(ctx context.Context, client *ent.Client, role *ent.Rol
 	return role, nil
 }
 
+// AddRoleParent makes a role inherit the permissions of a parent role. It
+// fails with ErrRoleCycle if the parent inherits the role.
+func AddRoleParent(ctx context.Context, client *ent.Client, role, parent *ent.Role) (*ent.Role, error) {
+	g, err := loadRoleAncestry(ctx, client, []string{parent.Name})
+	if err != nil {
+		return nil, err
+	}
+	if err := g.checkParents(role.Name, []string{parent.Name}); err != nil {
+		return nil, err
+	}
+	return client.Role.UpdateOne(role).
+		AddParents(parent).
+		Save(ctx)
+}
+
+// RemoveRoleParent stops a role inheriting the permissions of a parent role.
+func RemoveRoleParent(ctx context.Context, client *ent.Client, role, parent *ent.Role) (*ent.Role, error) {
+	return client.Role.UpdateOne(role).
+		RemoveParents(parent).
+		Save(ctx)
+}
+
+// SetRoleParents sets the roles a role inherits to exactly the given roles.
+// It fails with ErrRoleCycle if any of them inherits the role.
+func SetRoleParents(ctx context.Context, client *ent.Client, role *ent.Role, parents []*ent.Role) (*ent.Role, error) {
+	names := make([]string, len(parents))
+	for i, parent := range parents {
+		names[i] = parent.Name
+	}
+	g, err := loadRoleAncestry(ctx, client, names)
+	if err != nil {
+		return nil, err
+	}
+	if err := g.checkParents(role.Name, names); err != nil {
+		return nil, err
+	}
+	return client.Role.UpdateOne(role).
+		ClearParents().
+		AddParents(parents...).
+		Save(ctx)
+}
+
 // DeleteRole deletes a role.
 func DeleteRole(ctx context.Context, client *ent.Client, role *ent.Role) error {
 	return client.Role.DeleteOne(role).Exec(ctx)
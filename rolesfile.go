@@ -0,0 +1,818 @@
+package users
+
+import (
+	"bytes"
+	"cmp"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"maps"
+	"os"
+	"path/filepath"
+	"slices"
+	"strconv"
+
+	"github.com/pelletier/go-toml/v2"
+	"github.com/pelletier/go-toml/v2/unstable"
+	"gopkg.in/yaml.v3"
+
+	"github.com/smxlong/users/ent"
+	"github.com/smxlong/users/ent/role"
+	"github.com/smxlong/users/ent/user"
+)
+
+// RolesFileVersion is the version of the roles file format. Files must give
+// it as their version.
+const RolesFileVersion = 1
+
+// Format is the encoding of a roles file.
+type Format string
+
+// The supported roles file formats.
+const (
+	FormatYAML Format = "yaml"
+	FormatJSON Format = "json"
+	FormatTOML Format = "toml"
+)
+
+// FormatFromPath returns the format of a file from its extension: .yaml or
+// .yml, .json or .toml.
+func FormatFromPath(path string) (Format, error) {
+	switch filepath.Ext(path) {
+	case ".yaml", ".yml":
+		return FormatYAML, nil
+	case ".json":
+		return FormatJSON, nil
+	case ".toml":
+		return FormatTOML, nil
+	}
+	return "", fmt.Errorf("%w: %s", ErrFormatUnknown, path)
+}
+
+// RolesFile is a declarative description of the roles and permissions, so
+// that they can be kept in a file under version control. It can also seed
+// users and give them roles. In YAML:
+//
+//	version: 1
+//	permissions:
+//	  reports:read:
+//	    description: Read reports
+//	roles:
+//	  analyst:
+//	    description: Analysts
+//	    permissions: [reports:read]
+//	  manager:
+//	    description: Managers
+//	    inherits: [analyst]
+//	users:
+//	  - name: alice
+//	    email: alice@example.com
+//	    roles: [analyst]
+//
+// Every permission a role has must be listed under permissions, and every
+// role a role inherits must be listed under roles.
+type RolesFile struct {
+	Version     int                         `json:"version" yaml:"version" toml:"version"`
+	Permissions map[string]*PermissionEntry `json:"permissions,omitempty" yaml:"permissions,omitempty" toml:"permissions,omitempty"`
+	Roles       map[string]*RoleEntry       `json:"roles,omitempty" yaml:"roles,omitempty" toml:"roles,omitempty"`
+	Users       []*UserEntry                `json:"users,omitempty" yaml:"users,omitempty" toml:"users,omitempty"`
+}
+
+// PermissionEntry is a permission in a roles file.
+type PermissionEntry struct {
+	Description string `json:"description,omitempty" yaml:"description,omitempty" toml:"description,omitempty"`
+}
+
+// RoleEntry is a role in a roles file.
+type RoleEntry struct {
+	Description string `json:"description,omitempty" yaml:"description,omitempty" toml:"description,omitempty"`
+	// Permissions are the names of the role's permissions.
+	Permissions []string `json:"permissions,omitempty" yaml:"permissions,omitempty" toml:"permissions,omitempty"`
+	// Inherits are the names of the roles this role inherits.
+	Inherits []string `json:"inherits,omitempty" yaml:"inherits,omitempty" toml:"inherits,omitempty"`
+}
+
+// UserEntry is a user seeded by a roles file.
+type UserEntry struct {
+	Name  string `json:"name" yaml:"name" toml:"name"`
+	Email string `json:"email" yaml:"email" toml:"email"`
+	// Roles are the names of roles the user must have.
+	Roles []string `json:"roles,omitempty" yaml:"roles,omitempty" toml:"roles,omitempty"`
+	// Resources are the roles the user must have on resources.
+	Resources []*ResourceRolesEntry `json:"resources,omitempty" yaml:"resources,omitempty" toml:"resources,omitempty"`
+}
+
+// ResourceRolesEntry is the roles a seeded user has on a resource.
+type ResourceRolesEntry struct {
+	Type string `json:"type" yaml:"type" toml:"type"`
+	ID   string `json:"id" yaml:"id" toml:"id"`
+	// Roles are the names of roles the user must have on the resource.
+	Roles []string `json:"roles" yaml:"roles" toml:"roles"`
+}
+
+// RolesFileError is an error at a line of a roles file. Errors returned by
+// LoadRolesFile match ErrRolesFileInvalid.
+type RolesFileError struct {
+	Line    int
+	Message string
+}
+
+// Error returns the error message, prefixed with the line number.
+func (e *RolesFileError) Error() string {
+	return fmt.Sprintf("line %d: %s", e.Line, e.Message)
+}
+
+// Unwrap returns ErrRolesFileInvalid.
+func (e *RolesFileError) Unwrap() error {
+	return ErrRolesFileInvalid
+}
+
+// LoadRolesFile decodes and validates a roles file. If the file is invalid,
+// it returns all the problems found, each a *RolesFileError giving the line.
+func LoadRolesFile(data []byte, format Format) (*RolesFile, error) {
+	var root *yaml.Node
+	var err error
+	switch format {
+	case FormatYAML:
+		root, err = parseYAML(data)
+	case FormatJSON:
+		root, err = parseJSON(data)
+	case FormatTOML:
+		root, err = parseTOML(data)
+	default:
+		return nil, fmt.Errorf("%w: %s", ErrFormatUnknown, format)
+	}
+	if err != nil {
+		return nil, err
+	}
+	d := &rolesFileDecoder{}
+	f := d.file(root)
+	if len(d.errs) > 0 {
+		return nil, errors.Join(d.errs...)
+	}
+	return f, nil
+}
+
+// ReadRolesFile reads and validates a roles file, choosing the format from
+// the file's extension.
+func ReadRolesFile(path string) (*RolesFile, error) {
+	format, err := FormatFromPath(path)
+	if err != nil {
+		return nil, err
+	}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	f, err := LoadRolesFile(data, format)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", path, err)
+	}
+	return f, nil
+}
+
+// Marshal encodes the roles file in the format.
+func (f *RolesFile) Marshal(format Format) ([]byte, error) {
+	switch format {
+	case FormatYAML:
+		return yaml.Marshal(f)
+	case FormatJSON:
+		b, err := json.MarshalIndent(f, "", "  ")
+		if err != nil {
+			return nil, err
+		}
+		return append(b, '\n'), nil
+	case FormatTOML:
+		return toml.Marshal(f)
+	}
+	return nil, fmt.Errorf("%w: %s", ErrFormatUnknown, format)
+}
+
+// RolesAndPermissions returns the roles and permissions in the file, for use
+// with SyncRolesAndPermissions or PlanRolesAndPermissions.
+func (f *RolesFile) RolesAndPermissions() Roles {
+	roles := Roles{}
+	for name, r := range f.Roles {
+		rp := &RoleWithPermissions{Description: r.Description, Inherits: r.Inherits}
+		for _, p := range r.Permissions {
+			permission := &Permission{Name: p}
+			if entry, ok := f.Permissions[p]; ok {
+				permission.Description = entry.Description
+			}
+			rp.Permissions = append(rp.Permissions, permission)
+		}
+		roles[name] = rp
+	}
+	return roles
+}
+
+// permissions returns the permissions listed in the file, sorted by name.
+func (f *RolesFile) permissions() []*Permission {
+	var permissions []*Permission
+	for _, name := range slices.Sorted(maps.Keys(f.Permissions)) {
+		permissions = append(permissions, &Permission{Name: name, Description: f.Permissions[name].Description})
+	}
+	return permissions
+}
+
+// PlanRolesFile computes the changes ApplyRolesFile would make to the roles
+// and permissions, without making them, as PlanRolesAndPermissions does.
+// Users aren't seeded by the plan.
+func PlanRolesFile(ctx context.Context, client *ent.Client, f *RolesFile) (*Plan, error) {
+	return planRolesAndPermissions(ctx, client, f.RolesAndPermissions(), f.permissions())
+}
+
+// ExportRolesFile returns the roles and permissions in the database as a
+// roles file, with each user that has roles.
+func ExportRolesFile(ctx context.Context, client *ent.Client) (*RolesFile, error) {
+	f := &RolesFile{
+		Version:     RolesFileVersion,
+		Permissions: map[string]*PermissionEntry{},
+		Roles:       map[string]*RoleEntry{},
+	}
+	permissions, err := client.Permission.Query().All(ctx)
+	if err != nil {
+		return nil, err
+	}
+	for _, p := range permissions {
+		f.Permissions[p.Name] = &PermissionEntry{Description: p.Description}
+	}
+	roles, err := client.Role.Query().
+		WithPermissions().
+		WithParents(func(q *ent.RoleQuery) {
+			q.Order(ent.Asc(role.FieldName))
+		}).
+		All(ctx)
+	if err != nil {
+		return nil, err
+	}
+	for _, r := range roles {
+		entry := &RoleEntry{
+			Description: r.Description,
+			Permissions: permissionNames(r.Edges.Permissions),
+		}
+		for _, parent := range r.Edges.Parents {
+			entry.Inherits = append(entry.Inherits, parent.Name)
+		}
+		f.Roles[r.Name] = entry
+	}
+	users, err := client.User.Query().
+		Where(user.Or(user.HasRoles(), user.HasRoleAssignments())).
+		WithRoles(func(q *ent.RoleQuery) {
+			q.Order(ent.Asc(role.FieldName))
+		}).
+		WithRoleAssignments(func(q *ent.RoleAssignmentQuery) {
+			q.WithRole()
+		}).
+		Order(ent.Asc(user.FieldName)).
+		All(ctx)
+	if err != nil {
+		return nil, err
+	}
+	for _, u := range users {
+		entry := &UserEntry{Name: u.Name, Email: u.Email}
+		for _, r := range u.Edges.Roles {
+			entry.Roles = append(entry.Roles, r.Name)
+		}
+		assignments := u.Edges.RoleAssignments
+		slices.SortFunc(assignments, func(a, b *ent.RoleAssignment) int {
+			return cmp.Or(
+				cmp.Compare(a.ResourceType, b.ResourceType),
+				cmp.Compare(a.ResourceID, b.ResourceID),
+				cmp.Compare(a.Edges.Role.Name, b.Edges.Role.Name),
+			)
+		})
+		for i, a := range assignments {
+			if i == 0 || a.ResourceType != assignments[i-1].ResourceType || a.ResourceID != assignments[i-1].ResourceID {
+				entry.Resources = append(entry.Resources, &ResourceRolesEntry{Type: a.ResourceType, ID: a.ResourceID})
+			}
+			r := entry.Resources[len(entry.Resources)-1]
+			r.Roles = append(r.Roles, a.Edges.Role.Name)
+		}
+		f.Users = append(f.Users, entry)
+	}
+	return f, nil
+}
+
+// ApplyRolesFile synchronizes the roles and permissions with the file, as
+// SyncRolesAndPermissions does, and then seeds its users. Every permission
+// the file lists is kept, even if no role has it, for example because it is
+// only granted through a wildcard. Users are found by email and created if
+// missing, without a password, so that they must reset it to log in. They
+// are given the roles the file lists, on every resource and on the resources
+// listed, and keep any others they have. It runs in a transaction, so that
+// if it fails, none of the changes are made.
+func ApplyRolesFile(ctx context.Context, client *ent.Client, f *RolesFile) error {
+	_, err := inTx(ctx, client, func(client *ent.Client) error {
+		if err := syncRolesAndPermissions(ctx, NewEntStore(client), f.RolesAndPermissions(), f.permissions()); err != nil {
+			return err
+		}
+		for _, entry := range f.Users {
+			if err := seedUser(ctx, client, entry); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+	return err
+}
+
+// seedUser finds or creates a seeded user and gives it its roles, including
+// those on resources.
+func seedUser(ctx context.Context, client *ent.Client, entry *UserEntry) error {
+	u, err := FindByEmail(ctx, client, entry.Email)
+	if ent.IsNotFound(err) {
+		u, err = client.User.Create().
+			SetName(entry.Name).
+			SetEmail(entry.Email).
+			SetPasswordHash(PasswordHashNone().String()).
+			Save(ctx)
+	}
+	if err != nil {
+		return err
+	}
+	roleIDs, err := client.Role.Query().
+		Where(
+			role.NameIn(entry.Roles...),
+			role.Not(role.HasUsersWith(user.ID(u.ID))),
+		).
+		IDs(ctx)
+	if err != nil {
+		return err
+	}
+	if len(roleIDs) > 0 {
+		if err := client.User.UpdateOne(u).AddRoleIDs(roleIDs...).Exec(ctx); err != nil {
+			return err
+		}
+	}
+	for _, resource := range entry.Resources {
+		roles, err := client.Role.Query().Where(role.NameIn(resource.Roles...)).All(ctx)
+		if err != nil {
+			return err
+		}
+		for _, r := range roles {
+			if _, err := AddRoleOn(ctx, client, u, r, Resource{Type: resource.Type, ID: resource.ID}); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// parseYAML parses a YAML document.
+func parseYAML(data []byte) (*yaml.Node, error) {
+	var doc yaml.Node
+	if err := yaml.Unmarshal(data, &doc); err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrRolesFileInvalid, err)
+	}
+	if len(doc.Content) == 0 {
+		return &yaml.Node{Kind: yaml.MappingNode, Line: 1}, nil
+	}
+	return doc.Content[0], nil
+}
+
+// parseJSON parses a JSON document. JSON is checked with encoding/json, so
+// that YAML isn't accepted, and then parsed as YAML for the line numbers.
+func parseJSON(data []byte) (*yaml.Node, error) {
+	var v any
+	if err := json.Unmarshal(data, &v); err != nil {
+		var serr *json.SyntaxError
+		if errors.As(err, &serr) {
+			return nil, &RolesFileError{Line: lineAt(data, int(serr.Offset)), Message: serr.Error()}
+		}
+		return nil, fmt.Errorf("%w: %v", ErrRolesFileInvalid, err)
+	}
+	return parseYAML(data)
+}
+
+// lineAt returns the line number of the offset in data.
+func lineAt(data []byte, offset int) int {
+	return bytes.Count(data[:min(offset, len(data))], []byte("\n")) + 1
+}
+
+// parseTOML parses a TOML document into the YAML node tree used for the
+// other formats.
+func parseTOML(data []byte) (*yaml.Node, error) {
+	t := &tomlParser{
+		tables: map[*yaml.Node]bool{},
+	}
+	t.p.Reset(data)
+	root := &yaml.Node{Kind: yaml.MappingNode, Line: 1}
+	current := root
+	for t.p.NextExpression() {
+		e := t.p.Expression()
+		var err error
+		switch e.Kind {
+		case unstable.Table:
+			current, err = t.table(root, e, false)
+		case unstable.ArrayTable:
+			current, err = t.table(root, e, true)
+		case unstable.KeyValue:
+			err = t.keyValue(current, e)
+		}
+		if err != nil {
+			return nil, err
+		}
+	}
+	if err := t.p.Error(); err != nil {
+		var perr *unstable.ParserError
+		if errors.As(err, &perr) && perr.Highlight != nil {
+			return nil, &RolesFileError{Line: t.p.Shape(t.p.Range(perr.Highlight)).Start.Line, Message: perr.Message}
+		}
+		return nil, fmt.Errorf("%w: %v", ErrRolesFileInvalid, err)
+	}
+	return root, nil
+}
+
+// tomlParser converts TOML expressions to YAML nodes.
+type tomlParser struct {
+	p unstable.Parser
+	// tables holds the tables defined by headers, which can't be defined
+	// again.
+	tables map[*yaml.Node]bool
+}
+
+// line returns the line of a TOML node, or def if the node has no position.
+func (t *tomlParser) line(n *unstable.Node, def int) int {
+	if n.Raw.Length == 0 {
+		return def
+	}
+	return t.p.Shape(n.Raw).Start.Line
+}
+
+// table finds or creates the table named by a table or array table header.
+func (t *tomlParser) table(root *yaml.Node, e *unstable.Node, array bool) (*yaml.Node, error) {
+	current := root
+	line := 1
+	it := e.Key()
+	for it.Next() {
+		k := it.Node()
+		line = t.line(k, line)
+		child := mappingValue(current, string(k.Data))
+		if child == nil {
+			if it.IsLast() && array {
+				child = &yaml.Node{Kind: yaml.SequenceNode, Line: line}
+			} else {
+				child = &yaml.Node{Kind: yaml.MappingNode, Line: line}
+			}
+			current.Content = append(current.Content,
+				&yaml.Node{Kind: yaml.ScalarNode, Tag: "!!str", Value: string(k.Data), Line: line},
+				child)
+		}
+		if child.Kind == yaml.SequenceNode && !(it.IsLast() && !array) {
+			if it.IsLast() {
+				child.Content = append(child.Content, &yaml.Node{Kind: yaml.MappingNode, Line: line})
+			}
+			if len(child.Content) == 0 {
+				return nil, &RolesFileError{Line: line, Message: fmt.Sprintf("%q is not a table", k.Data)}
+			}
+			child = child.Content[len(child.Content)-1]
+		}
+		if child.Kind != yaml.MappingNode {
+			return nil, &RolesFileError{Line: line, Message: fmt.Sprintf("%q is not a table", k.Data)}
+		}
+		current = child
+	}
+	if !array {
+		if t.tables[current] {
+			return nil, &RolesFileError{Line: line, Message: "table defined twice"}
+		}
+		t.tables[current] = true
+	}
+	return current, nil
+}
+
+// keyValue adds a key-value expression to a table, creating the tables
+// named by a dotted key.
+func (t *tomlParser) keyValue(table *yaml.Node, e *unstable.Node) error {
+	line := 1
+	it := e.Key()
+	for it.Next() {
+		k := it.Node()
+		line = t.line(k, line)
+		if it.IsLast() {
+			table.Content = append(table.Content,
+				&yaml.Node{Kind: yaml.ScalarNode, Tag: "!!str", Value: string(k.Data), Line: line},
+				t.value(e.Value(), line))
+			return nil
+		}
+		child := mappingValue(table, string(k.Data))
+		if child == nil {
+			child = &yaml.Node{Kind: yaml.MappingNode, Line: line}
+			table.Content = append(table.Content,
+				&yaml.Node{Kind: yaml.ScalarNode, Tag: "!!str", Value: string(k.Data), Line: line},
+				child)
+		}
+		if child.Kind != yaml.MappingNode {
+			return &RolesFileError{Line: line, Message: fmt.Sprintf("%q is not a table", k.Data)}
+		}
+		table = child
+	}
+	return nil
+}
+
+// value converts a TOML value.
+func (t *tomlParser) value(v *unstable.Node, line int) *yaml.Node {
+	line = t.line(v, line)
+	switch v.Kind {
+	case unstable.Array:
+		n := &yaml.Node{Kind: yaml.SequenceNode, Line: line}
+		it := v.Children()
+		for it.Next() {
+			n.Content = append(n.Content, t.value(it.Node(), line))
+		}
+		return n
+	case unstable.InlineTable:
+		n := &yaml.Node{Kind: yaml.MappingNode, Line: line}
+		it := v.Children()
+		for it.Next() {
+			// Keys of inline tables can't conflict, as the parser checks.
+			_ = t.keyValue(n, it.Node())
+		}
+		return n
+	case unstable.String:
+		return &yaml.Node{Kind: yaml.ScalarNode, Tag: "!!str", Value: string(v.Data), Line: line}
+	case unstable.Integer:
+		return &yaml.Node{Kind: yaml.ScalarNode, Tag: "!!int", Value: string(v.Data), Line: line}
+	case unstable.Float:
+		return &yaml.Node{Kind: yaml.ScalarNode, Tag: "!!float", Value: string(v.Data), Line: line}
+	case unstable.Bool:
+		return &yaml.Node{Kind: yaml.ScalarNode, Tag: "!!bool", Value: string(v.Data), Line: line}
+	}
+	return &yaml.Node{Kind: yaml.ScalarNode, Tag: "!!timestamp", Value: string(v.Data), Line: line}
+}
+
+// mappingValue returns the value of a key in a mapping node, or nil.
+func mappingValue(n *yaml.Node, key string) *yaml.Node {
+	for i := 0; i+1 < len(n.Content); i += 2 {
+		if n.Content[i].Value == key {
+			return n.Content[i+1]
+		}
+	}
+	return nil
+}
+
+// rolesFileDecoder decodes a roles file from YAML nodes, collecting errors.
+type rolesFileDecoder struct {
+	errs []error
+}
+
+// errorf records an error at a node.
+func (d *rolesFileDecoder) errorf(n *yaml.Node, format string, args ...any) {
+	d.errs = append(d.errs, &RolesFileError{Line: n.Line, Message: fmt.Sprintf(format, args...)})
+}
+
+// nodePair is a key and value of a mapping node.
+type nodePair struct {
+	key   *yaml.Node
+	value *yaml.Node
+}
+
+// mapping returns the key-value pairs of a mapping node. Null is an empty
+// mapping.
+func (d *rolesFileDecoder) mapping(n *yaml.Node, what string) []nodePair {
+	n = resolveNode(n)
+	if isNull(n) {
+		return nil
+	}
+	if n.Kind != yaml.MappingNode {
+		d.errorf(n, "%s must be a mapping", what)
+		return nil
+	}
+	var pairs []nodePair
+	seen := map[string]bool{}
+	for i := 0; i+1 < len(n.Content); i += 2 {
+		key, value := resolveNode(n.Content[i]), n.Content[i+1]
+		if key.Kind != yaml.ScalarNode {
+			d.errorf(key, "%s keys must be strings", what)
+			continue
+		}
+		if seen[key.Value] {
+			d.errorf(key, "duplicate key %q in %s", key.Value, what)
+			continue
+		}
+		seen[key.Value] = true
+		pairs = append(pairs, nodePair{key: key, value: value})
+	}
+	return pairs
+}
+
+// fields decodes the fields of a mapping node by name, reporting unknown
+// fields.
+func (d *rolesFileDecoder) fields(n *yaml.Node, what string, decode map[string]func(*yaml.Node)) {
+	for _, pair := range d.mapping(n, what) {
+		f, ok := decode[pair.key.Value]
+		if !ok {
+			d.errorf(pair.key, "unknown field %q in %s", pair.key.Value, what)
+			continue
+		}
+		f(pair.value)
+	}
+}
+
+// str decodes a string.
+func (d *rolesFileDecoder) str(n *yaml.Node, what string) string {
+	n = resolveNode(n)
+	if n.Kind != yaml.ScalarNode || n.Tag != "!!str" {
+		d.errorf(n, "%s must be a string", what)
+		return ""
+	}
+	return n.Value
+}
+
+// strs decodes a sequence of strings. Null is an empty sequence.
+func (d *rolesFileDecoder) strs(n *yaml.Node, what string) ([]string, []*yaml.Node) {
+	n = resolveNode(n)
+	if isNull(n) {
+		return nil, nil
+	}
+	if n.Kind != yaml.SequenceNode {
+		d.errorf(n, "%s must be a list", what)
+		return nil, nil
+	}
+	var values []string
+	var nodes []*yaml.Node
+	for _, item := range n.Content {
+		values = append(values, d.str(item, what))
+		nodes = append(nodes, resolveNode(item))
+	}
+	return values, nodes
+}
+
+// file decodes and validates a roles file.
+func (d *rolesFileDecoder) file(root *yaml.Node) *RolesFile {
+	f := &RolesFile{
+		Permissions: map[string]*PermissionEntry{},
+		Roles:       map[string]*RoleEntry{},
+	}
+	var versionNode *yaml.Node
+	var roleRefs, inheritRefs, userRoleRefs []nodePair
+	roleKeys := map[string]*yaml.Node{}
+	d.fields(root, "roles file", map[string]func(*yaml.Node){
+		"version": func(n *yaml.Node) {
+			versionNode = resolveNode(n)
+			v, err := strconv.Atoi(versionNode.Value)
+			if versionNode.Kind != yaml.ScalarNode || versionNode.Tag != "!!int" || err != nil {
+				d.errorf(versionNode, "version must be an integer")
+				return
+			}
+			if v != RolesFileVersion {
+				d.errorf(versionNode, "unsupported version %d, want %d", v, RolesFileVersion)
+			}
+			f.Version = v
+		},
+		"permissions": func(n *yaml.Node) {
+			for _, pair := range d.mapping(n, "permissions") {
+				if err := ValidatePermissionName(pair.key.Value); err != nil {
+					d.errorf(pair.key, "%s", err)
+				}
+				p := &PermissionEntry{}
+				d.fields(pair.value, fmt.Sprintf("permission %q", pair.key.Value), map[string]func(*yaml.Node){
+					"description": func(n *yaml.Node) { p.Description = d.str(n, "description") },
+				})
+				f.Permissions[pair.key.Value] = p
+			}
+		},
+		"roles": func(n *yaml.Node) {
+			for _, pair := range d.mapping(n, "roles") {
+				r := &RoleEntry{}
+				what := fmt.Sprintf("role %q", pair.key.Value)
+				d.fields(pair.value, what, map[string]func(*yaml.Node){
+					"description": func(n *yaml.Node) { r.Description = d.str(n, "description") },
+					"permissions": func(n *yaml.Node) {
+						var nodes []*yaml.Node
+						r.Permissions, nodes = d.strs(n, "permissions")
+						for i, name := range r.Permissions {
+							if slices.Contains(r.Permissions[:i], name) {
+								d.errorf(nodes[i], "duplicate permission %q in %s", name, what)
+							}
+							roleRefs = append(roleRefs, nodePair{key: nodes[i], value: pair.key})
+						}
+					},
+					"inherits": func(n *yaml.Node) {
+						var nodes []*yaml.Node
+						r.Inherits, nodes = d.strs(n, "inherits")
+						for i, name := range r.Inherits {
+							if slices.Contains(r.Inherits[:i], name) {
+								d.errorf(nodes[i], "duplicate inherited role %q in %s", name, what)
+							}
+							inheritRefs = append(inheritRefs, nodePair{key: nodes[i], value: pair.key})
+						}
+					},
+				})
+				f.Roles[pair.key.Value] = r
+				roleKeys[pair.key.Value] = pair.key
+			}
+		},
+		"users": func(n *yaml.Node) {
+			n = resolveNode(n)
+			if isNull(n) {
+				return
+			}
+			if n.Kind != yaml.SequenceNode {
+				d.errorf(n, "users must be a list")
+				return
+			}
+			emails := map[string]bool{}
+			for _, item := range n.Content {
+				u := &UserEntry{}
+				d.fields(item, "user", map[string]func(*yaml.Node){
+					"name":  func(n *yaml.Node) { u.Name = d.str(n, "name") },
+					"email": func(n *yaml.Node) { u.Email = d.str(n, "email") },
+					"roles": func(n *yaml.Node) {
+						var nodes []*yaml.Node
+						u.Roles, nodes = d.strs(n, "roles")
+						for _, node := range nodes {
+							userRoleRefs = append(userRoleRefs, nodePair{key: node})
+						}
+					},
+					"resources": func(n *yaml.Node) {
+						n = resolveNode(n)
+						if isNull(n) {
+							return
+						}
+						if n.Kind != yaml.SequenceNode {
+							d.errorf(n, "resources must be a list")
+							return
+						}
+						for _, item := range n.Content {
+							r := &ResourceRolesEntry{}
+							d.fields(item, "resource", map[string]func(*yaml.Node){
+								"type": func(n *yaml.Node) { r.Type = d.str(n, "type") },
+								"id":   func(n *yaml.Node) { r.ID = d.str(n, "id") },
+								"roles": func(n *yaml.Node) {
+									var nodes []*yaml.Node
+									r.Roles, nodes = d.strs(n, "roles")
+									for _, node := range nodes {
+										userRoleRefs = append(userRoleRefs, nodePair{key: node})
+									}
+								},
+							})
+							item = resolveNode(item)
+							resource := Resource{Type: r.Type, ID: r.ID}
+							if err := resource.validate(); err != nil {
+								d.errorf(item, "resource must have a type and an id")
+							} else if slices.ContainsFunc(u.Resources, func(other *ResourceRolesEntry) bool {
+								return other.Type == r.Type && other.ID == r.ID
+							}) {
+								d.errorf(item, "duplicate resource %q", resource.String())
+							}
+							u.Resources = append(u.Resources, r)
+						}
+					},
+				})
+				item = resolveNode(item)
+				switch {
+				case u.Email == "":
+					d.errorf(item, "user must have an email")
+				case emails[u.Email]:
+					d.errorf(item, "duplicate user %q", u.Email)
+				}
+				if u.Name == "" {
+					d.errorf(item, "user must have a name")
+				}
+				emails[u.Email] = true
+				f.Users = append(f.Users, u)
+			}
+		},
+	})
+	if versionNode == nil {
+		d.errorf(root, "version is required")
+	}
+	for _, ref := range roleRefs {
+		if _, ok := f.Permissions[ref.key.Value]; !ok {
+			d.errorf(ref.key, "role %q has undeclared permission %q", ref.value.Value, ref.key.Value)
+		}
+	}
+	for _, ref := range inheritRefs {
+		if _, ok := f.Roles[ref.key.Value]; !ok {
+			d.errorf(ref.key, "role %q inherits unknown role %q", ref.value.Value, ref.key.Value)
+		}
+	}
+	g := f.RolesAndPermissions().graph()
+	for _, name := range slices.Sorted(maps.Keys(roleKeys)) {
+		if g.inherits(name, name) {
+			d.errorf(roleKeys[name], "role %q inherits itself", name)
+		}
+	}
+	for _, ref := range userRoleRefs {
+		if _, ok := f.Roles[ref.key.Value]; !ok {
+			d.errorf(ref.key, "unknown role %q", ref.key.Value)
+		}
+	}
+	return f
+}
+
+// resolveNode follows YAML aliases.
+func resolveNode(n *yaml.Node) *yaml.Node {
+	for n.Kind == yaml.AliasNode {
+		n = n.Alias
+	}
+	return n
+}
+
+// isNull checks if a node is null.
+func isNull(n *yaml.Node) bool {
+	return n.Kind == yaml.ScalarNode && n.Tag == "!!null"
+}
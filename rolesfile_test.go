@@ -0,0 +1,287 @@
+package users
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+const testRolesFileYAML = `version: 1
+permissions:
+  reports:read:
+    description: Read reports
+  reports:write:
+    description: Write reports
+roles:
+  analyst:
+    description: Analysts
+    permissions: [reports:read]
+  editor:
+    description: Editors
+    permissions:
+      - reports:write
+    inherits: [analyst]
+users:
+  - name: user1
+    email: user1@example.com
+    roles: [editor]
+  - name: user2
+    email: user2@example.com
+    resources:
+      - type: report
+        id: q3
+        roles: [editor]
+`
+
+const testRolesFileJSON = `{
+  "version": 1,
+  "permissions": {
+    "reports:read": {"description": "Read reports"},
+    "reports:write": {"description": "Write reports"}
+  },
+  "roles": {
+    "analyst": {"description": "Analysts", "permissions": ["reports:read"]},
+    "editor": {"description": "Editors", "permissions": ["reports:write"], "inherits": ["analyst"]}
+  },
+  "users": [
+    {"name": "user1", "email": "user1@example.com", "roles": ["editor"]},
+    {"name": "user2", "email": "user2@example.com", "resources": [{"type": "report", "id": "q3", "roles": ["editor"]}]}
+  ]
+}
+`
+
+const testRolesFileTOML = `version = 1
+
+[permissions."reports:read"]
+description = "Read reports"
+
+[permissions."reports:write"]
+description = "Write reports"
+
+[roles.analyst]
+description = "Analysts"
+permissions = ["reports:read"]
+
+[roles.editor]
+description = "Editors"
+permissions = [
+  "reports:write",
+]
+inherits = ["analyst"]
+
+[[users]]
+name = "user1"
+email = "user1@example.com"
+roles = ["editor"]
+
+[[users]]
+name = "user2"
+email = "user2@example.com"
+
+[[users.resources]]
+type = "report"
+id = "q3"
+roles = ["editor"]
+`
+
+var testRolesFile = &RolesFile{
+	Version: 1,
+	Permissions: map[string]*PermissionEntry{
+		"reports:read":  {Description: "Read reports"},
+		"reports:write": {Description: "Write reports"},
+	},
+	Roles: map[string]*RoleEntry{
+		"analyst": {Description: "Analysts", Permissions: []string{"reports:read"}},
+		"editor":  {Description: "Editors", Permissions: []string{"reports:write"}, Inherits: []string{"analyst"}},
+	},
+	Users: []*UserEntry{
+		{Name: "user1", Email: "user1@example.com", Roles: []string{"editor"}},
+		{Name: "user2", Email: "user2@example.com", Resources: []*ResourceRolesEntry{
+			{Type: "report", ID: "q3", Roles: []string{"editor"}},
+		}},
+	},
+}
+
+func Test_that_LoadRolesFile_loads_every_format(t *testing.T) {
+	for format, data := range map[Format]string{
+		FormatYAML: testRolesFileYAML,
+		FormatJSON: testRolesFileJSON,
+		FormatTOML: testRolesFileTOML,
+	} {
+		t.Run(string(format), func(t *testing.T) {
+			f, err := LoadRolesFile([]byte(data), format)
+			require.NoError(t, err)
+			require.Equal(t, testRolesFile, f)
+		})
+	}
+}
+
+func Test_that_LoadRolesFile_reports_errors_with_line_numbers(t *testing.T) {
+	for format, test := range map[Format]struct {
+		data  string
+		lines []int
+	}{
+		FormatYAML: {
+			data: `version: 2
+permissions:
+  a: {description: A}
+roles:
+  r:
+    permissions: [a, b]
+    colour: red
+users:
+  - name: u
+    roles: [s]
+    resources:
+      - type: report
+        roles: [t]
+`,
+			lines: []int{1, 7, 12, 9, 6, 10, 13},
+		},
+		FormatJSON: {
+			data: `{
+  "version": 1,
+  "roles": {
+    "r": {"permissions": ["a"]}
+  }
+}
+`,
+			lines: []int{4},
+		},
+		FormatTOML: {
+			data: `version = 1
+
+[roles.r]
+description = 1
+permissions = [
+  "a",
+]
+`,
+			lines: []int{4, 6},
+		},
+	} {
+		t.Run(string(format), func(t *testing.T) {
+			_, err := LoadRolesFile([]byte(test.data), format)
+			require.ErrorIs(t, err, ErrRolesFileInvalid)
+			var lines []int
+			for _, err := range err.(interface{ Unwrap() []error }).Unwrap() {
+				var ferr *RolesFileError
+				require.True(t, errors.As(err, &ferr))
+				lines = append(lines, ferr.Line)
+			}
+			require.Equal(t, test.lines, lines, err.Error())
+		})
+	}
+}
+
+func Test_that_LoadRolesFile_checks_inherited_roles(t *testing.T) {
+	_, err := LoadRolesFile([]byte(`version: 1
+roles:
+  a:
+    inherits: [b]
+  b:
+    inherits: [a, c]
+`), FormatYAML)
+	require.ErrorIs(t, err, ErrRolesFileInvalid)
+	require.Equal(t, "line 6: role \"b\" inherits unknown role \"c\"\n"+
+		"line 3: role \"a\" inherits itself\n"+
+		"line 5: role \"b\" inherits itself", err.Error())
+}
+
+func Test_that_LoadRolesFile_reports_syntax_errors_with_line_numbers(t *testing.T) {
+	for format, data := range map[Format]string{
+		FormatYAML: "version: 1\nroles:\n  r: [\n",
+		FormatJSON: "{\n  \"version\": 1,\n  \"roles\": {,\n}\n",
+		FormatTOML: "version = 1\n[roles\n",
+	} {
+		t.Run(string(format), func(t *testing.T) {
+			_, err := LoadRolesFile([]byte(data), format)
+			require.ErrorIs(t, err, ErrRolesFileInvalid)
+			require.Contains(t, err.Error(), "line ")
+		})
+	}
+}
+
+func Test_that_ExportRolesFile_round_trips_through_every_format(t *testing.T) {
+	client := setupAndMigrate(t)
+	ctx := context.Background()
+	require.NoError(t, ApplyRolesFile(ctx, client, testRolesFile))
+	f, err := ExportRolesFile(ctx, client)
+	require.NoError(t, err)
+	require.Equal(t, testRolesFile, f)
+	for _, format := range []Format{FormatYAML, FormatJSON, FormatTOML} {
+		data, err := f.Marshal(format)
+		require.NoError(t, err)
+		loaded, err := LoadRolesFile(data, format)
+		require.NoError(t, err, string(data))
+		require.Equal(t, f, loaded)
+	}
+}
+
+func Test_that_ApplyRolesFile_seeds_users(t *testing.T) {
+	client := setupAndMigrate(t)
+	ctx := context.Background()
+	require.NoError(t, ApplyRolesFile(ctx, client, testRolesFile))
+	// applying it again changes nothing
+	require.NoError(t, ApplyRolesFile(ctx, client, testRolesFile))
+	u, err := FindByEmail(ctx, client, "user1@example.com")
+	require.NoError(t, err)
+	ok, err := CheckPermission(ctx, client, u, "reports:write")
+	require.NoError(t, err)
+	require.True(t, ok)
+	// seeded users have no password
+	_, err = LoginByName(ctx, client, "user1", "")
+	require.Error(t, err)
+	// roles on resources are seeded too
+	u, err = FindByEmail(ctx, client, "user2@example.com")
+	require.NoError(t, err)
+	ok, err = CheckPermissionOn(ctx, client, u, "reports:write", Resource{Type: "report", ID: "q3"}, nil)
+	require.NoError(t, err)
+	require.True(t, ok)
+	ok, err = CheckPermission(ctx, client, u, "reports:write")
+	require.NoError(t, err)
+	require.False(t, ok)
+	exported, err := ExportRolesFile(ctx, client)
+	require.NoError(t, err)
+	require.Equal(t, testRolesFile.Users, exported.Users)
+}
+
+func Test_that_ApplyRolesFile_keeps_permissions_no_role_has(t *testing.T) {
+	client := setupAndMigrate(t)
+	ctx := context.Background()
+	f := &RolesFile{
+		Version: 1,
+		Permissions: map[string]*PermissionEntry{
+			"reports:*":    {Description: "All reports"},
+			"reports:read": {Description: "Read reports"},
+		},
+		Roles: map[string]*RoleEntry{
+			"admin": {Description: "Admins", Permissions: []string{"reports:*"}},
+		},
+	}
+	plan, err := PlanRolesFile(ctx, client, f)
+	require.NoError(t, err)
+	require.Len(t, plan.CreatePermissions, 2)
+	require.NoError(t, ApplyRolesFile(ctx, client, f))
+	plan, err = PlanRolesFile(ctx, client, f)
+	require.NoError(t, err)
+	require.True(t, plan.Empty())
+	exported, err := ExportRolesFile(ctx, client)
+	require.NoError(t, err)
+	require.Equal(t, f.Permissions, exported.Permissions)
+}
+
+func Test_that_ReadRolesFile_uses_the_file_extension(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "roles.toml")
+	require.NoError(t, os.WriteFile(path, []byte(testRolesFileTOML), 0o600))
+	f, err := ReadRolesFile(path)
+	require.NoError(t, err)
+	require.Equal(t, testRolesFile, f)
+	_, err = ReadRolesFile(filepath.Join(dir, "roles.ini"))
+	require.ErrorIs(t, err, ErrFormatUnknown)
+}
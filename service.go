@@ -0,0 +1,679 @@
+package users
+
+import (
+	"context"
+	"errors"
+	"slices"
+	"sort"
+	"time"
+
+	"github.com/smxlong/users/ent"
+	"github.com/smxlong/users/ent/schema"
+	"github.com/smxlong/users/ent/user"
+)
+
+// Service implements the package's operations on top of a Store, using the
+// package's domain types instead of generated ent types. It holds the
+// configuration that the package-level functions take as arguments. Features
+// that need the ent schema, such as MFA, WebAuthn, federated login, email
+// verification, password reset, magic links, roles files and relation
+// tuples, are only offered as package-level functions taking an ent client.
+type Service struct {
+	store  Store
+	tokens *TokenOptions
+	hasher PasswordHasher
+	policy *PasswordPolicy
+	clock  Clock
+	events EventSink
+	// hierarchy resolves resource parents for CheckPermissionOn.
+	hierarchy ResourceHierarchy
+}
+
+// PasswordHasher hashes a password, such as PasswordHashDefault does.
+type PasswordHasher func(password string) (*PasswordHash, error)
+
+// ServiceOption configures a Service.
+type ServiceOption func(*Service)
+
+// WithStore makes the Service use the Store.
+func WithStore(store Store) ServiceOption {
+	return func(s *Service) {
+		s.store = store
+	}
+}
+
+// WithClient makes the Service use an EntStore on the client.
+func WithClient(client *ent.Client) ServiceOption {
+	return func(s *Service) {
+		s.store = NewEntStore(client)
+	}
+}
+
+// WithTokenOptions sets the options for the tokens the Service creates and
+// validates. Without them, token operations fail with
+// ErrTokenSecretRequired.
+func WithTokenOptions(opts *TokenOptions) ServiceOption {
+	return func(s *Service) {
+		s.tokens = opts
+	}
+}
+
+// WithHasher sets how the Service hashes passwords. If not set,
+// PasswordHashDefault is used.
+func WithHasher(hasher PasswordHasher) ServiceOption {
+	return func(s *Service) {
+		s.hasher = hasher
+	}
+}
+
+// WithPasswordPolicy makes the Service check new passwords against the
+// policy. If not set, passwords aren't checked.
+func WithPasswordPolicy(policy *PasswordPolicy) ServiceOption {
+	return func(s *Service) {
+		s.policy = policy
+	}
+}
+
+// WithClock sets the Service's Clock, which it uses wherever it reads the
+// time, as the package's functions use the Clock from ContextWithClock. If
+// not set, the system time is used.
+func WithClock(clock Clock) ServiceOption {
+	return func(s *Service) {
+		s.clock = clock
+	}
+}
+
+// WithEventSink makes the Service emit Events to the sink.
+func WithEventSink(sink EventSink) ServiceOption {
+	return func(s *Service) {
+		s.events = sink
+	}
+}
+
+// WithResourceHierarchy makes roles given on a resource apply to its
+// descendants, as resolved by the hierarchy. If not set, resources have no
+// parents.
+func WithResourceHierarchy(hierarchy ResourceHierarchy) ServiceOption {
+	return func(s *Service) {
+		s.hierarchy = hierarchy
+	}
+}
+
+// NewService returns a Service configured by the options. Use WithStore or
+// WithClient to choose where users are kept. If neither is given, the
+// Service uses a new MemoryStore.
+func NewService(opts ...ServiceOption) *Service {
+	s := &Service{}
+	for _, opt := range opts {
+		opt(s)
+	}
+	if s.store == nil {
+		s.store = NewMemoryStore()
+	}
+	if s.tokens == nil {
+		s.tokens = &TokenOptions{}
+	}
+	if s.hasher == nil {
+		s.hasher = PasswordHashDefault
+	}
+	if s.clock == nil {
+		s.clock = systemClock{}
+	}
+	return s
+}
+
+// Store returns the Service's Store.
+func (s *Service) Store() Store {
+	return s.store
+}
+
+// context returns a context in which the package reads the time from the
+// Service's Clock.
+func (s *Service) context(ctx context.Context) context.Context {
+	return ContextWithClock(ctx, s.clock)
+}
+
+// inTx calls fn with the Service's Store, in a transaction if the Store is a
+// TxStore.
+func (s *Service) inTx(ctx context.Context, fn func(store Store) error) error {
+	if ts, ok := s.store.(TxStore); ok {
+		return ts.InTx(ctx, fn)
+	}
+	return fn(s.store)
+}
+
+// emit sends an event about the user to the event sink, if there is one.
+func (s *Service) emit(ctx context.Context, typ EventType, userID, detail string) {
+	if s.events == nil {
+		return
+	}
+	s.events.Emit(ctx, Event{
+		Type:   typ,
+		Time:   s.clock.Now(),
+		UserID: userID,
+		Detail: detail,
+	})
+}
+
+// hashPassword checks a new password against the policy, if set, and hashes
+// it.
+func (s *Service) hashPassword(password string) (*PasswordHash, error) {
+	if s.policy != nil {
+		if err := s.policy.Validate(password); err != nil {
+			return nil, err
+		}
+	}
+	return s.hasher(password)
+}
+
+// Create a user from name, email, and password, hashing the password. If the
+// Service has a password policy, the password must satisfy it. The email
+// address is checked as the ent schema checks it.
+func (s *Service) Create(ctx context.Context, name, email, password string) (*User, error) {
+	ctx = s.context(ctx)
+	if err := schema.ValidateEmail(email); err != nil {
+		return nil, ErrEmailAddressInvalid
+	}
+	ph, err := s.hashPassword(password)
+	if err != nil {
+		return nil, err
+	}
+	u, err := s.store.CreateUser(ctx, &User{
+		Name:         name,
+		Email:        email,
+		PasswordHash: ph.String(),
+	})
+	if err != nil {
+		return nil, err
+	}
+	s.emit(ctx, EventUserCreated, u.ID, "")
+	return u, nil
+}
+
+// FindByName finds a user by name.
+func (s *Service) FindByName(ctx context.Context, name string) (*User, error) {
+	return s.store.FindUserByName(ctx, name)
+}
+
+// FindByEmail finds a user by email.
+func (s *Service) FindByEmail(ctx context.Context, email string) (*User, error) {
+	return s.store.FindUserByEmail(ctx, email)
+}
+
+// LoginByName finds a user by name and verifies the password. An unknown name
+// fails with ErrInvalidCredentials, as does a wrong password.
+func (s *Service) LoginByName(ctx context.Context, name, password string, opts ...LoginOption) (*User, error) {
+	ctx = s.context(ctx)
+	u, err := s.store.FindUserByName(ctx, name)
+	if err != nil {
+		return nil, s.loginNotFound(ctx, err, newLoginOptions(opts))
+	}
+	return s.Login(ctx, u, password, opts...)
+}
+
+// LoginByEmail finds a user by email and verifies the password. An unknown
+// email fails with ErrInvalidCredentials, as does a wrong password.
+func (s *Service) LoginByEmail(ctx context.Context, email, password string, opts ...LoginOption) (*User, error) {
+	ctx = s.context(ctx)
+	u, err := s.store.FindUserByEmail(ctx, email)
+	if err != nil {
+		return nil, s.loginNotFound(ctx, err, newLoginOptions(opts))
+	}
+	return s.Login(ctx, u, password, opts...)
+}
+
+// loginNotFound handles a failure to find the user logging in. To avoid
+// revealing which users exist, it spends the time a password check would and
+// returns ErrInvalidCredentials, as for a wrong password. Guesses at unknown
+// users still count against the source.
+func (s *Service) loginNotFound(ctx context.Context, err error, o *loginOptions) error {
+	if !errors.Is(err, ErrNotFound) {
+		return err
+	}
+	if o.lockout != nil {
+		if err := o.lockout.checkSource(ctx, o.source); err != nil {
+			return err
+		}
+	}
+	_ = dummyPasswordHash().Verify("")
+	if o.lockout != nil {
+		if err := o.lockout.fail(ctx, "", o.source); err != nil {
+			return err
+		}
+	}
+	s.emit(ctx, EventLoginFailed, "", "")
+	return ErrInvalidCredentials
+}
+
+// Login verifies the password for a user. A wrong password fails with
+// ErrInvalidCredentials. Users who aren't active are rejected as CheckStatus
+// does. The user's login metadata is updated: a wrong password increments
+// FailedLoginCount, and a successful login resets it and sets LastLoginAt and
+// LastLoginIP. It returns the updated user.
+func (s *Service) Login(ctx context.Context, u *User, password string, opts ...LoginOption) (*User, error) {
+	ctx = s.context(ctx)
+	o := newLoginOptions(opts)
+	if err := s.verifyLogin(ctx, u, password, o); err != nil {
+		return nil, err
+	}
+	return s.recordLogin(ctx, u.ID, o)
+}
+
+// verifyLogin makes the checks of Login, without recording a successful
+// login, so that a second factor can be checked first.
+func (s *Service) verifyLogin(ctx context.Context, u *User, password string, o *loginOptions) error {
+	var lockedUntil time.Time
+	if o.lockout != nil {
+		if err := o.lockout.checkSource(ctx, o.source); err != nil {
+			return err
+		}
+		var err error
+		lockedUntil, err = o.lockout.LockedUntil(ctx, u.ID)
+		if err != nil {
+			return err
+		}
+	}
+	if err := checkPassword(u.PasswordHash, password); err != nil {
+		if !errors.Is(err, ErrInvalidCredentials) {
+			return err
+		}
+		if o.lockout != nil {
+			// Guesses at a locked out user count only against the source,
+			// so that they don't extend the user's lockout.
+			id := u.ID
+			if !lockedUntil.IsZero() {
+				id = ""
+			}
+			if err := o.lockout.fail(ctx, id, o.source); err != nil {
+				return err
+			}
+		}
+		if err := s.store.RecordLoginFailure(ctx, u.ID); err != nil {
+			return err
+		}
+		s.emit(ctx, EventLoginFailed, u.ID, "")
+		return ErrInvalidCredentials
+	}
+	if !lockedUntil.IsZero() {
+		err := lockedError(lockedUntil)
+		s.emit(ctx, EventLoginFailed, u.ID, err.Error())
+		return err
+	}
+	if err := checkStatus(userStatus(u), u.SuspendedUntil, clockNow(ctx)); err != nil {
+		s.emit(ctx, EventLoginFailed, u.ID, err.Error())
+		return err
+	}
+	if o.requireVerifiedEmail && u.EmailVerifiedAt == nil {
+		s.emit(ctx, EventLoginFailed, u.ID, ErrEmailNotVerified.Error())
+		return ErrEmailNotVerified
+	}
+	return nil
+}
+
+// recordLogin records a successful login for a user: it resets the user's
+// lockout failures, updates their login metadata, and emits the event. It
+// returns the updated user.
+func (s *Service) recordLogin(ctx context.Context, id string, o *loginOptions) (*User, error) {
+	if o.lockout != nil {
+		if err := o.lockout.succeed(ctx, id); err != nil {
+			return nil, err
+		}
+	}
+	u, err := s.store.RecordLogin(ctx, id, clockNow(ctx), o.ip)
+	if err != nil {
+		return nil, err
+	}
+	s.emit(ctx, EventLoginSucceeded, id, "")
+	return u, nil
+}
+
+// Delete a user.
+func (s *Service) Delete(ctx context.Context, u *User) error {
+	ctx = s.context(ctx)
+	if err := s.store.DeleteUser(ctx, u.ID); err != nil {
+		return err
+	}
+	s.emit(ctx, EventUserDeleted, u.ID, "")
+	return nil
+}
+
+// AddRole adds a role to a user.
+func (s *Service) AddRole(ctx context.Context, u *User, role string) error {
+	if err := s.store.AddUserRole(ctx, u.ID, role); err != nil {
+		return err
+	}
+	s.emit(ctx, EventRoleAdded, u.ID, role)
+	return nil
+}
+
+// RemoveRole removes a role from a user.
+func (s *Service) RemoveRole(ctx context.Context, u *User, role string) error {
+	if err := s.store.RemoveUserRole(ctx, u.ID, role); err != nil {
+		return err
+	}
+	s.emit(ctx, EventRoleRemoved, u.ID, role)
+	return nil
+}
+
+// CheckPermission checks if a user has all the listed permissions, through
+// its roles or the roles they inherit. Granted wildcard permissions satisfy
+// the permissions they match, as PermissionMatches does.
+func (s *Service) CheckPermission(ctx context.Context, u *User, p ...string) (bool, error) {
+	have, err := s.store.UserPermissions(ctx, u.ID)
+	if err != nil {
+		return false, err
+	}
+	return hasPermissions(have, p), nil
+}
+
+// AddRoleOn gives a user a role on a resource.
+func (s *Service) AddRoleOn(ctx context.Context, u *User, role string, resource Resource) error {
+	if err := s.store.AddUserRoleOn(ctx, u.ID, role, resource); err != nil {
+		return err
+	}
+	s.emit(ctx, EventRoleAdded, u.ID, role+" on "+resource.String())
+	return nil
+}
+
+// RemoveRoleOn takes a role on a resource from a user.
+func (s *Service) RemoveRoleOn(ctx context.Context, u *User, role string, resource Resource) error {
+	if err := s.store.RemoveUserRoleOn(ctx, u.ID, role, resource); err != nil {
+		return err
+	}
+	s.emit(ctx, EventRoleRemoved, u.ID, role+" on "+resource.String())
+	return nil
+}
+
+// RolesOn returns the names of the roles a user has on a resource, as the
+// package-level RolesOn does, using the Service's resource hierarchy.
+func (s *Service) RolesOn(ctx context.Context, u *User, resource Resource) ([]string, error) {
+	ancestry, err := resourceAncestry(ctx, s.hierarchy, resource)
+	if err != nil {
+		return nil, err
+	}
+	roles, err := s.store.UserRoles(ctx, u.ID)
+	if err != nil {
+		return nil, err
+	}
+	for _, r := range ancestry {
+		scoped, err := s.store.UserRolesOn(ctx, u.ID, r)
+		if err != nil {
+			return nil, err
+		}
+		roles = append(roles, scoped...)
+	}
+	slices.Sort(roles)
+	return slices.Compact(roles), nil
+}
+
+// CheckPermissionOn checks if a user has a permission on a resource, as the
+// package-level CheckPermissionOn does, using the Service's resource
+// hierarchy.
+func (s *Service) CheckPermissionOn(ctx context.Context, u *User, p string, resource Resource) (bool, error) {
+	roles, err := s.RolesOn(ctx, u, resource)
+	if err != nil {
+		return false, err
+	}
+	all, err := s.store.ListRoles(ctx)
+	if err != nil {
+		return false, err
+	}
+	return hasPermissions(newRoleGraph(all).permissionNames(roles), []string{p}), nil
+}
+
+// EffectivePermissions returns the permissions a user has through its roles
+// and the roles they inherit, as the package-level EffectivePermissions does.
+func (s *Service) EffectivePermissions(ctx context.Context, u *User) ([]*EffectivePermission, error) {
+	roles, err := s.store.UserRoles(ctx, u.ID)
+	if err != nil {
+		return nil, err
+	}
+	all, err := s.store.ListRoles(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return newRoleGraph(all).effectivePermissions(roles), nil
+}
+
+// CreateRolesAndPermissions creates all the roles and permissions, as the
+// package-level CreateRolesAndPermissions does. If the Store is a TxStore,
+// the changes are made in a transaction.
+func (s *Service) CreateRolesAndPermissions(ctx context.Context, rolesAndPermissions Roles) error {
+	return s.inTx(ctx, func(store Store) error {
+		return createRolesAndPermissions(ctx, store, rolesAndPermissions)
+	})
+}
+
+// SyncRolesAndPermissions synchronizes the roles and permissions, as the
+// package-level SyncRolesAndPermissions does. If the Store is a TxStore, the
+// changes are made in a transaction.
+func (s *Service) SyncRolesAndPermissions(ctx context.Context, rolesAndPermissions Roles) error {
+	return s.inTx(ctx, func(store Store) error {
+		return syncRolesAndPermissions(ctx, store, rolesAndPermissions, nil)
+	})
+}
+
+// NewToken creates a new JWT for a user, using the Service's token options.
+func (s *Service) NewToken(ctx context.Context, u *User) (string, error) {
+	generation, err := s.store.TokenGeneration(ctx, u.ID)
+	if err != nil {
+		return "", err
+	}
+	return signToken(s.context(ctx), u.Email, generation, nil, s.tokens)
+}
+
+// NewTokenWithPermissions creates a new JWT for a user holding its
+// permissions, as the package-level NewTokenWithPermissions does.
+func (s *Service) NewTokenWithPermissions(ctx context.Context, u *User) (string, error) {
+	generation, err := s.store.TokenGeneration(ctx, u.ID)
+	if err != nil {
+		return "", err
+	}
+	permissions, err := s.store.UserPermissions(ctx, u.ID)
+	if err != nil {
+		return "", err
+	}
+	return signToken(s.context(ctx), u.Email, generation, permissions, s.tokens)
+}
+
+// CheckTokenPermission validates a JWT, and checks if it holds all the listed
+// permissions, as the package-level CheckTokenPermission does.
+func (s *Service) CheckTokenPermission(ctx context.Context, token string, p ...string) (bool, error) {
+	return CheckTokenPermission(s.context(ctx), token, s.tokens, p...)
+}
+
+// ValidateToken validates a JWT for a user, returning the user. Revoked
+// tokens fail with ErrTokenRevoked. Users who aren't active are rejected as
+// CheckStatus does.
+func (s *Service) ValidateToken(ctx context.Context, token string) (*User, error) {
+	email, generation, err := parseToken(s.context(ctx), token, s.tokens)
+	if err != nil {
+		return nil, err
+	}
+	u, err := s.store.FindUserByEmail(ctx, email)
+	if err != nil {
+		return nil, err
+	}
+	current, err := s.store.TokenGeneration(ctx, u.ID)
+	if err != nil {
+		return nil, err
+	}
+	if generation != current {
+		return nil, ErrTokenRevoked
+	}
+	if err := checkStatus(userStatus(u), u.SuspendedUntil, s.clock.Now()); err != nil {
+		return nil, err
+	}
+	return u, nil
+}
+
+// RevokeTokens revokes all tokens issued to a user so far.
+func (s *Service) RevokeTokens(ctx context.Context, u *User) error {
+	if err := s.store.RevokeSessions(ctx, u.ID); err != nil {
+		return err
+	}
+	s.emit(ctx, EventTokensRevoked, u.ID, "")
+	return nil
+}
+
+// DisableUser disables a user until reactivated, and revokes their tokens.
+func (s *Service) DisableUser(ctx context.Context, u *User) (*User, error) {
+	return s.setStatus(ctx, u, user.StatusDisabled, nil, EventUserDisabled)
+}
+
+// SuspendUser suspends a user until the given time, and revokes their tokens.
+// If until is zero, the user is suspended until reactivated.
+func (s *Service) SuspendUser(ctx context.Context, u *User, until time.Time) (*User, error) {
+	var suspendedUntil *time.Time
+	if !until.IsZero() {
+		suspendedUntil = &until
+	}
+	return s.setStatus(ctx, u, user.StatusSuspended, suspendedUntil, EventUserSuspended)
+}
+
+// ReactivateUser makes a disabled, suspended or pending user active, and
+// revokes any tokens issued before they were deactivated.
+func (s *Service) ReactivateUser(ctx context.Context, u *User) (*User, error) {
+	return s.setStatus(ctx, u, user.StatusActive, nil, EventUserReactivated)
+}
+
+// setStatus changes a user's status and revokes their tokens.
+func (s *Service) setStatus(ctx context.Context, u *User, status user.Status, suspendedUntil *time.Time, typ EventType) (*User, error) {
+	ctx = s.context(ctx)
+	changed := *u
+	changed.Status = string(status)
+	changed.SuspendedUntil = suspendedUntil
+	var updated *User
+	err := s.inTx(ctx, func(store Store) error {
+		var err error
+		if updated, err = store.UpdateUser(ctx, &changed); err != nil {
+			return err
+		}
+		return store.RevokeSessions(ctx, u.ID)
+	})
+	if err != nil {
+		return nil, err
+	}
+	s.emit(ctx, typ, u.ID, "")
+	return updated, nil
+}
+
+// CreateRole creates a role and assigns it the named permissions.
+func (s *Service) CreateRole(ctx context.Context, name, description string, permissions ...string) (*Role, error) {
+	return s.store.CreateRole(ctx, &Role{
+		Name:        name,
+		Description: description,
+		Permissions: permissions,
+	})
+}
+
+// FindRole finds a role by name.
+func (s *Service) FindRole(ctx context.Context, name string) (*Role, error) {
+	return s.store.FindRole(ctx, name)
+}
+
+// RoleExists checks if a role exists.
+func (s *Service) RoleExists(ctx context.Context, name string) (bool, error) {
+	_, err := s.store.FindRole(ctx, name)
+	if errors.Is(err, ErrNotFound) {
+		return false, nil
+	}
+	if err != nil {
+		return false, err
+	}
+	return true, nil
+}
+
+// FindOrCreateRole finds a role by name or creates it if it doesn't exist. If
+// the role exists but the description doesn't match, the description is
+// updated.
+func (s *Service) FindOrCreateRole(ctx context.Context, name, description string) (*Role, error) {
+	r, err := s.store.FindRole(ctx, name)
+	if errors.Is(err, ErrNotFound) {
+		return s.store.CreateRole(ctx, &Role{Name: name, Description: description})
+	}
+	if err != nil {
+		return nil, err
+	}
+	if r.Description != description {
+		if err := s.store.UpdateRoleDescription(ctx, name, description); err != nil {
+			return nil, err
+		}
+		r.Description = description
+	}
+	return r, nil
+}
+
+// UpdateRoleDescription changes a role's description.
+func (s *Service) UpdateRoleDescription(ctx context.Context, name, description string) error {
+	return s.store.UpdateRoleDescription(ctx, name, description)
+}
+
+// AddRolePermission adds a permission to a role.
+func (s *Service) AddRolePermission(ctx context.Context, role, permission string) error {
+	return s.store.AddRolePermission(ctx, role, permission)
+}
+
+// RemoveRolePermission removes a permission from a role.
+func (s *Service) RemoveRolePermission(ctx context.Context, role, permission string) error {
+	return s.store.RemoveRolePermission(ctx, role, permission)
+}
+
+// SetRolePermissions sets a role's permissions to exactly the named
+// permissions.
+func (s *Service) SetRolePermissions(ctx context.Context, role string, permissions []string) error {
+	return s.store.SetRolePermissions(ctx, role, permissions)
+}
+
+// SetRoleInherits sets the roles a role inherits to exactly the given roles.
+// It fails with ErrRoleCycle if the role would inherit itself.
+func (s *Service) SetRoleInherits(ctx context.Context, role string, inherits []string) error {
+	return s.store.SetRoleInherits(ctx, role, inherits)
+}
+
+// DeleteRole deletes a role.
+func (s *Service) DeleteRole(ctx context.Context, name string) error {
+	return s.store.DeleteRole(ctx, name)
+}
+
+// CreatePermission creates a permission.
+func (s *Service) CreatePermission(ctx context.Context, name, description string) (*Permission, error) {
+	return s.store.CreatePermission(ctx, &Permission{Name: name, Description: description})
+}
+
+// FindOrCreatePermission finds a permission by name or creates it if it
+// doesn't exist. If the permission exists but the description doesn't match,
+// it fails with ErrPermissionDescriptionMismatch.
+func (s *Service) FindOrCreatePermission(ctx context.Context, name, description string) (*Permission, error) {
+	p, err := s.store.FindPermission(ctx, name)
+	if errors.Is(err, ErrNotFound) {
+		return s.store.CreatePermission(ctx, &Permission{Name: name, Description: description})
+	}
+	if err != nil {
+		return nil, err
+	}
+	if p.Description != description {
+		return nil, ErrPermissionDescriptionMismatch
+	}
+	return p, nil
+}
+
+// UpdatePermissionDescription changes a permission's description.
+func (s *Service) UpdatePermissionDescription(ctx context.Context, name, description string) error {
+	return s.store.UpdatePermissionDescription(ctx, name, description)
+}
+
+// DeletePermission deletes a permission.
+func (s *Service) DeletePermission(ctx context.Context, name string) error {
+	return s.store.DeletePermission(ctx, name)
+}
+
+// sortedRoleNames returns the names of the roles in order.
+func sortedRoleNames(roles Roles) []string {
+	names := make([]string, 0, len(roles))
+	for name := range roles {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
@@ -0,0 +1,179 @@
+package users
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func setupService(t *testing.T, opts ...ServiceOption) *Service {
+	return NewService(append([]ServiceOption{WithClient(setupAndMigrate(t))}, opts...)...)
+}
+
+func Test_that_Service_Create_and_Login_work(t *testing.T) {
+	s := setupService(t)
+	ctx := context.Background()
+	u, err := s.Create(ctx, "user1", USER1_TEST_EMAIL, "password")
+	require.NoError(t, err)
+	require.NotEmpty(t, u.ID)
+	require.Equal(t, "user1", u.Name)
+	u2, err := s.LoginByName(ctx, "user1", "password")
+	require.NoError(t, err)
+	require.Equal(t, u.ID, u2.ID)
+	u2, err = s.LoginByEmail(ctx, USER1_TEST_EMAIL, "password")
+	require.NoError(t, err)
+	require.Equal(t, u.ID, u2.ID)
+	_, err = s.LoginByName(ctx, "user1", "wrong password")
+	require.Equal(t, ErrInvalidCredentials, err)
+	_, err = s.LoginByEmail(ctx, USER2_TEST_EMAIL, "password")
+	require.Equal(t, ErrInvalidCredentials, err)
+}
+
+func Test_that_Service_Create_fails_with_duplicate_name(t *testing.T) {
+	s := setupService(t)
+	ctx := context.Background()
+	_, err := s.Create(ctx, "user1", USER1_TEST_EMAIL, "password")
+	require.NoError(t, err)
+	_, err = s.Create(ctx, "user1", USER2_TEST_EMAIL, "password")
+	require.ErrorIs(t, err, ErrAlreadyExists)
+	_, err = s.Create(ctx, "user2", "not an email", "password")
+	require.ErrorIs(t, err, ErrEmailAddressInvalid)
+}
+
+func Test_that_Service_CheckPermission_works(t *testing.T) {
+	s := setupService(t)
+	ctx := context.Background()
+	require.NoError(t, s.CreateRolesAndPermissions(ctx, Roles{
+		"admin": {
+			Description: "Administrator",
+			Permissions: []*Permission{
+				{Name: "read", Description: "Read"},
+				{Name: "write", Description: "Write"},
+			},
+		},
+		"user": {
+			Description: "User",
+			Permissions: []*Permission{
+				{Name: "read", Description: "Read"},
+			},
+		},
+	}))
+	u, err := s.Create(ctx, "user1", USER1_TEST_EMAIL, "password")
+	require.NoError(t, err)
+	require.NoError(t, s.AddRole(ctx, u, "user"))
+	ok, err := s.CheckPermission(ctx, u, "read")
+	require.NoError(t, err)
+	require.True(t, ok)
+	ok, err = s.CheckPermission(ctx, u, "read", "write")
+	require.NoError(t, err)
+	require.False(t, ok)
+	require.NoError(t, s.AddRole(ctx, u, "admin"))
+	ok, err = s.CheckPermission(ctx, u, "read", "write")
+	require.NoError(t, err)
+	require.True(t, ok)
+	require.ErrorIs(t, s.AddRole(ctx, u, "nobody"), ErrNotFound)
+}
+
+func Test_that_Service_SyncRolesAndPermissions_deletes_unlisted(t *testing.T) {
+	s := setupService(t)
+	ctx := context.Background()
+	require.NoError(t, s.CreateRolesAndPermissions(ctx, Roles{
+		"admin": {Permissions: []*Permission{{Name: "write"}}},
+		"user":  {Permissions: []*Permission{{Name: "read"}}},
+	}))
+	require.NoError(t, s.SyncRolesAndPermissions(ctx, Roles{
+		"user": {Permissions: []*Permission{{Name: "read"}}},
+	}))
+	_, err := s.Store().FindRole(ctx, "admin")
+	require.ErrorIs(t, err, ErrNotFound)
+	_, err = s.Store().FindPermission(ctx, "write")
+	require.ErrorIs(t, err, ErrNotFound)
+	r, err := s.Store().FindRole(ctx, "user")
+	require.NoError(t, err)
+	require.Equal(t, []string{"read"}, r.Permissions)
+}
+
+func Test_that_Service_tokens_work(t *testing.T) {
+	s := setupService(t, WithTokenOptions(&TokenOptions{Secret: "foo"}))
+	ctx := context.Background()
+	u, err := s.Create(ctx, "user1", USER1_TEST_EMAIL, "password")
+	require.NoError(t, err)
+	tok, err := s.NewToken(ctx, u)
+	require.NoError(t, err)
+	u2, err := s.ValidateToken(ctx, tok)
+	require.NoError(t, err)
+	require.Equal(t, u.ID, u2.ID)
+	require.NoError(t, s.RevokeTokens(ctx, u))
+	_, err = s.ValidateToken(ctx, tok)
+	require.ErrorIs(t, err, ErrTokenRevoked)
+	tok, err = s.NewToken(ctx, u)
+	require.NoError(t, err)
+	require.NoError(t, s.Delete(ctx, u))
+	_, err = s.ValidateToken(ctx, tok)
+	require.ErrorIs(t, err, ErrNotFound)
+}
+
+func Test_that_Service_uses_its_hasher_and_password_policy(t *testing.T) {
+	hashed := 0
+	s := NewService(
+		WithHasher(func(password string) (*PasswordHash, error) {
+			hashed++
+			return PasswordHashDefault(password)
+		}),
+		WithPasswordPolicy(&PasswordPolicy{MinLength: 10}),
+	)
+	ctx := context.Background()
+	_, err := s.Create(ctx, "user1", USER1_TEST_EMAIL, "password")
+	require.ErrorIs(t, err, ErrPasswordPolicy)
+	require.Equal(t, 0, hashed)
+	_, err = s.Create(ctx, "user1", USER1_TEST_EMAIL, "long password")
+	require.NoError(t, err)
+	require.Equal(t, 1, hashed)
+}
+
+func Test_that_Service_emits_events(t *testing.T) {
+	now := time.Date(2024, 1, 2, 3, 4, 5, 0, time.UTC)
+	var events []Event
+	s := NewService(
+		WithClock(NewFakeClock(now)),
+		WithEventSink(EventSinkFunc(func(ctx context.Context, e Event) {
+			events = append(events, e)
+		})),
+	)
+	ctx := context.Background()
+	require.NoError(t, s.CreateRolesAndPermissions(ctx, Roles{"user": {}}))
+	u, err := s.Create(ctx, "user1", USER1_TEST_EMAIL, "password")
+	require.NoError(t, err)
+	require.NoError(t, s.AddRole(ctx, u, "user"))
+	_, err = s.LoginByName(ctx, "user1", "wrong password")
+	require.ErrorIs(t, err, ErrInvalidCredentials)
+	_, err = s.DisableUser(ctx, u)
+	require.NoError(t, err)
+	require.Equal(t, []Event{
+		{Type: EventUserCreated, Time: now, UserID: u.ID},
+		{Type: EventRoleAdded, Time: now, UserID: u.ID, Detail: "user"},
+		{Type: EventLoginFailed, Time: now, UserID: u.ID},
+		{Type: EventUserDisabled, Time: now, UserID: u.ID},
+	}, events)
+}
+
+func Test_that_Service_DisableUser_blocks_login_and_tokens(t *testing.T) {
+	s := NewService(WithTokenOptions(&TokenOptions{Secret: "foo"}))
+	ctx := context.Background()
+	u, err := s.Create(ctx, "user1", USER1_TEST_EMAIL, "password")
+	require.NoError(t, err)
+	tok, err := s.NewToken(ctx, u)
+	require.NoError(t, err)
+	u, err = s.DisableUser(ctx, u)
+	require.NoError(t, err)
+	_, err = s.LoginByName(ctx, "user1", "password")
+	require.ErrorIs(t, err, ErrAccountDisabled)
+	_, err = s.ValidateToken(ctx, tok)
+	require.ErrorIs(t, err, ErrTokenRevoked)
+	_, err = s.ReactivateUser(ctx, u)
+	require.NoError(t, err)
+	_, err = s.LoginByName(ctx, "user1", "password")
+	require.NoError(t, err)
+}
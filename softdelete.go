@@ -0,0 +1,38 @@
+package users
+
+import (
+	"context"
+	"time"
+
+	"github.com/smxlong/users/ent"
+	"github.com/smxlong/users/ent/schema"
+	"github.com/smxlong/users/ent/user"
+)
+
+// IncludeDeleted returns a context in which queries, such as FindByName and
+// FindByEmail, include deleted users.
+func IncludeDeleted(ctx context.Context) context.Context {
+	return schema.IncludeDeleted(ctx)
+}
+
+// Deleted checks if a user has been deleted.
+func Deleted(u *ent.User) bool {
+	return u.DeletedAt != nil
+}
+
+// Restore undeletes a deleted user. Find the user with a context from
+// IncludeDeleted.
+func Restore(ctx context.Context, client *ent.Client, u *ent.User) (*ent.User, error) {
+	return u.Update().
+		ClearDeletedAt().
+		Save(ctx)
+}
+
+// PurgeDeletedUsers permanently deletes users that were deleted more than
+// retention ago, returning the number purged. Run it periodically to enforce
+// the retention period.
+func PurgeDeletedUsers(ctx context.Context, client *ent.Client, retention time.Duration) (int, error) {
+	return client.User.Delete().
+		Where(user.DeletedAtLT(clockNow(ctx).Add(-retention))).
+		Exec(schema.HardDelete(ctx))
+}
@@ -0,0 +1,155 @@
+package users
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/smxlong/users/ent"
+)
+
+func Test_that_Delete_hides_the_user(t *testing.T) {
+	client := setupAndMigrate(t)
+	ctx := context.Background()
+	u, err := Create(ctx, client, "user1", USER1_TEST_EMAIL, "password")
+	require.NoError(t, err)
+	require.NoError(t, Delete(ctx, client, u))
+	_, err = FindByName(ctx, client, "user1")
+	require.True(t, ent.IsNotFound(err))
+	_, err = FindByEmail(ctx, client, USER1_TEST_EMAIL)
+	require.True(t, ent.IsNotFound(err))
+	n, err := client.User.Query().Count(ctx)
+	require.NoError(t, err)
+	require.Zero(t, n)
+	_, err = LoginByName(ctx, client, "user1", "password")
+	require.ErrorIs(t, err, ErrInvalidCredentials)
+}
+
+func Test_that_Delete_keeps_the_user_and_its_roles(t *testing.T) {
+	client := setupAndMigrate(t)
+	ctx := context.Background()
+	u, err := Create(ctx, client, "user1", USER1_TEST_EMAIL, "password")
+	require.NoError(t, err)
+	r, err := client.Role.Create().SetName("role1").Save(ctx)
+	require.NoError(t, err)
+	_, err = AddRole(ctx, client, u, r)
+	require.NoError(t, err)
+	require.NoError(t, Delete(ctx, client, u))
+	u, err = FindByName(IncludeDeleted(ctx), client, "user1")
+	require.NoError(t, err)
+	require.True(t, Deleted(u))
+	n, err := u.QueryRoles().Count(ctx)
+	require.NoError(t, err)
+	require.Equal(t, 1, n)
+}
+
+func Test_that_Delete_fails_for_deleted_user(t *testing.T) {
+	client := setupAndMigrate(t)
+	ctx := context.Background()
+	u, err := Create(ctx, client, "user1", USER1_TEST_EMAIL, "password")
+	require.NoError(t, err)
+	require.NoError(t, Delete(ctx, client, u))
+	require.True(t, ent.IsNotFound(Delete(ctx, client, u)))
+}
+
+func Test_that_Restore_undeletes_the_user(t *testing.T) {
+	client := setupAndMigrate(t)
+	ctx := context.Background()
+	u, err := Create(ctx, client, "user1", USER1_TEST_EMAIL, "password")
+	require.NoError(t, err)
+	require.NoError(t, Delete(ctx, client, u))
+	u, err = FindByName(IncludeDeleted(ctx), client, "user1")
+	require.NoError(t, err)
+	u, err = Restore(ctx, client, u)
+	require.NoError(t, err)
+	require.False(t, Deleted(u))
+	_, err = LoginByName(ctx, client, "user1", "password")
+	require.NoError(t, err)
+}
+
+func Test_that_PurgeDeletedUsers_respects_retention(t *testing.T) {
+	client := setupAndMigrate(t)
+	ctx := context.Background()
+	u1, err := Create(ctx, client, "user1", USER1_TEST_EMAIL, "password")
+	require.NoError(t, err)
+	u2, err := Create(ctx, client, "user2", USER2_TEST_EMAIL, "password")
+	require.NoError(t, err)
+	_, err = Create(ctx, client, "user3", "user3@example.com", "password")
+	require.NoError(t, err)
+	clock := NewFakeClock(time.Now())
+	ctx = ContextWithClock(ctx, clock)
+	require.NoError(t, Delete(ctx, client, u1))
+	clock.Advance(24 * time.Hour)
+	require.NoError(t, Delete(ctx, client, u2))
+	clock.Advance(time.Second)
+	n, err := PurgeDeletedUsers(ctx, client, 24*time.Hour)
+	require.NoError(t, err)
+	require.Equal(t, 1, n)
+	all, err := client.User.Query().All(IncludeDeleted(ctx))
+	require.NoError(t, err)
+	require.Len(t, all, 2)
+	// the name of a purged user can be reused
+	_, err = Create(ctx, client, "user1", USER1_TEST_EMAIL, "password")
+	require.NoError(t, err)
+}
+
+func Test_that_ConsumeMagicLink_fails_for_deleted_user(t *testing.T) {
+	client := setupAndMigrate(t)
+	ctx := context.Background()
+	u, err := Create(ctx, client, "user1", USER1_TEST_EMAIL, "password")
+	require.NoError(t, err)
+	m := &MemoryMailer{}
+	require.NoError(t, RequestMagicLink(ctx, client, USER1_TEST_EMAIL, "", testEmailOptions(m)))
+	testWaitForMessages(t, m, 1)
+	require.NoError(t, Delete(ctx, client, u))
+	_, err = ConsumeMagicLink(ctx, client, testEmailedToken(t, m.Messages()[0]), "")
+	require.ErrorIs(t, err, ErrTokenInvalid)
+}
+
+func Test_that_VerifyEmail_fails_for_deleted_user(t *testing.T) {
+	client := setupAndMigrate(t)
+	ctx := context.Background()
+	u, err := Create(ctx, client, "user1", USER1_TEST_EMAIL, "password")
+	require.NoError(t, err)
+	m := &MemoryMailer{}
+	require.NoError(t, SendEmailVerification(ctx, client, u, testEmailOptions(m)))
+	require.NoError(t, Delete(ctx, client, u))
+	_, err = VerifyEmail(ctx, client, testEmailedToken(t, m.Messages()[0]))
+	require.ErrorIs(t, err, ErrTokenInvalid)
+}
+
+func Test_that_ResetPassword_fails_for_deleted_user(t *testing.T) {
+	client := setupAndMigrate(t)
+	ctx := context.Background()
+	u, err := Create(ctx, client, "user1", USER1_TEST_EMAIL, "password")
+	require.NoError(t, err)
+	m := &MemoryMailer{}
+	require.NoError(t, RequestPasswordReset(ctx, client, USER1_TEST_EMAIL, testEmailOptions(m)))
+	testWaitForMessages(t, m, 1)
+	require.NoError(t, Delete(ctx, client, u))
+	_, err = ResetPassword(ctx, client, testEmailedToken(t, m.Messages()[0]), "new password", &PasswordPolicy{})
+	require.ErrorIs(t, err, ErrTokenInvalid)
+}
+
+func Test_that_FederatedLogin_fails_for_deleted_user(t *testing.T) {
+	client := setupAndMigrate(t)
+	ctx := context.Background()
+	u, err := FederatedLogin(ctx, client, testFederatedClaims(), &FederationOptions{Provision: true})
+	require.NoError(t, err)
+	require.NoError(t, Delete(ctx, client, u))
+	_, err = FederatedLogin(ctx, client, testFederatedClaims(), &FederationOptions{Provision: true})
+	require.ErrorIs(t, err, ErrIdentityNotLinked)
+}
+
+func Test_that_FinishWebAuthnLogin_fails_for_deleted_user(t *testing.T) {
+	client := setupAndMigrate(t)
+	ctx := context.Background()
+	u, a := setupWebAuthnUser(t, client)
+	opts, session, err := BeginWebAuthnLogin(ctx, client, nil, testWebAuthnOptions())
+	require.NoError(t, err)
+	require.NoError(t, Delete(ctx, client, u))
+	_, err = FinishWebAuthnLogin(ctx, client, session, a.get(t, opts), testWebAuthnOptions())
+	require.ErrorIs(t, err, ErrWebAuthnCredentialUnknown)
+}
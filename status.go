@@ -0,0 +1,71 @@
+package users
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/smxlong/users/ent"
+	"github.com/smxlong/users/ent/user"
+)
+
+// CheckStatus checks that a user may log in. It fails with ErrAccountDisabled,
+// ErrAccountSuspended or ErrAccountPending if not. A suspension that has
+// ended, according to the context's Clock, doesn't prevent logging in.
+func CheckStatus(ctx context.Context, u *ent.User) error {
+	return checkStatus(u.Status, u.SuspendedUntil, clockNow(ctx))
+}
+
+// checkStatus checks that a user with the status may log in at the time now.
+func checkStatus(status user.Status, suspendedUntil *time.Time, now time.Time) error {
+	switch status {
+	case user.StatusActive:
+		return nil
+	case user.StatusDisabled:
+		return ErrAccountDisabled
+	case user.StatusSuspended:
+		if suspendedUntil == nil {
+			return ErrAccountSuspended
+		}
+		if now.Before(*suspendedUntil) {
+			return fmt.Errorf("%w until %s", ErrAccountSuspended, suspendedUntil.Format(time.RFC3339))
+		}
+		return nil
+	case user.StatusPending:
+		return ErrAccountPending
+	default:
+		return fmt.Errorf("unknown user status %q", status)
+	}
+}
+
+// DisableUser disables a user until reactivated, and revokes their tokens.
+func DisableUser(ctx context.Context, client *ent.Client, u *ent.User) (*ent.User, error) {
+	return u.Update().
+		SetStatus(user.StatusDisabled).
+		ClearSuspendedUntil().
+		AddTokenGeneration(1).
+		Save(ctx)
+}
+
+// SuspendUser suspends a user until the given time, and revokes their tokens.
+// If until is zero, the user is suspended until reactivated.
+func SuspendUser(ctx context.Context, client *ent.Client, u *ent.User, until time.Time) (*ent.User, error) {
+	update := u.Update().
+		SetStatus(user.StatusSuspended).
+		ClearSuspendedUntil().
+		AddTokenGeneration(1)
+	if !until.IsZero() {
+		update.SetSuspendedUntil(until)
+	}
+	return update.Save(ctx)
+}
+
+// ReactivateUser makes a disabled, suspended or pending user active, and
+// revokes any tokens issued before they were deactivated.
+func ReactivateUser(ctx context.Context, client *ent.Client, u *ent.User) (*ent.User, error) {
+	return u.Update().
+		SetStatus(user.StatusActive).
+		ClearSuspendedUntil().
+		AddTokenGeneration(1).
+		Save(ctx)
+}
@@ -0,0 +1,112 @@
+package users
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/smxlong/users/ent/user"
+)
+
+func Test_that_new_users_are_active(t *testing.T) {
+	client := setupAndMigrate(t)
+	ctx := context.Background()
+	u, err := Create(ctx, client, "user1", USER1_TEST_EMAIL, "password")
+	require.NoError(t, err)
+	require.Equal(t, user.StatusActive, u.Status)
+	require.NoError(t, CheckStatus(ctx, u))
+}
+
+func Test_that_DisableUser_blocks_login_and_tokens(t *testing.T) {
+	client := setupAndMigrate(t)
+	ctx := context.Background()
+	u, err := Create(ctx, client, "user1", USER1_TEST_EMAIL, "password")
+	require.NoError(t, err)
+	opts := &TokenOptions{Secret: "secret"}
+	tok, err := NewToken(u, opts)
+	require.NoError(t, err)
+	u, err = DisableUser(ctx, client, u)
+	require.NoError(t, err)
+	_, err = LoginByName(ctx, client, "user1", "password")
+	require.ErrorIs(t, err, ErrAccountDisabled)
+	// the old token is revoked
+	_, err = ValidateToken(ctx, client, tok, opts)
+	require.ErrorIs(t, err, ErrTokenRevoked)
+	// a new token is rejected by status
+	tok, err = NewToken(u, opts)
+	require.NoError(t, err)
+	_, err = ValidateToken(ctx, client, tok, opts)
+	require.ErrorIs(t, err, ErrAccountDisabled)
+}
+
+func Test_that_disabled_user_with_wrong_password_gets_invalid_credentials(t *testing.T) {
+	client := setupAndMigrate(t)
+	ctx := context.Background()
+	u, err := Create(ctx, client, "user1", USER1_TEST_EMAIL, "password")
+	require.NoError(t, err)
+	_, err = DisableUser(ctx, client, u)
+	require.NoError(t, err)
+	_, err = LoginByName(ctx, client, "user1", "wrong password")
+	require.ErrorIs(t, err, ErrInvalidCredentials)
+}
+
+func Test_that_SuspendUser_blocks_login_until_the_suspension_ends(t *testing.T) {
+	client := setupAndMigrate(t)
+	ctx := context.Background()
+	u, err := Create(ctx, client, "user1", USER1_TEST_EMAIL, "password")
+	require.NoError(t, err)
+	clock := NewFakeClock(time.Now())
+	ctx = ContextWithClock(ctx, clock)
+	u, err = SuspendUser(ctx, client, u, clock.Now().Add(time.Hour))
+	require.NoError(t, err)
+	_, err = LoginByName(ctx, client, "user1", "password")
+	require.ErrorIs(t, err, ErrAccountSuspended)
+	clock.Advance(time.Hour)
+	require.NoError(t, CheckStatus(ctx, u))
+	_, err = LoginByName(ctx, client, "user1", "password")
+	require.NoError(t, err)
+}
+
+func Test_that_SuspendUser_without_end_blocks_login(t *testing.T) {
+	client := setupAndMigrate(t)
+	ctx := context.Background()
+	u, err := Create(ctx, client, "user1", USER1_TEST_EMAIL, "password")
+	require.NoError(t, err)
+	u, err = SuspendUser(ctx, client, u, time.Time{})
+	require.NoError(t, err)
+	require.Nil(t, u.SuspendedUntil)
+	_, err = LoginByName(ctx, client, "user1", "password")
+	require.ErrorIs(t, err, ErrAccountSuspended)
+}
+
+func Test_that_pending_users_cannot_log_in(t *testing.T) {
+	client := setupAndMigrate(t)
+	ctx := context.Background()
+	u, err := Create(ctx, client, "user1", USER1_TEST_EMAIL, "password")
+	require.NoError(t, err)
+	_, err = u.Update().SetStatus(user.StatusPending).Save(ctx)
+	require.NoError(t, err)
+	_, err = LoginByName(ctx, client, "user1", "password")
+	require.ErrorIs(t, err, ErrAccountPending)
+}
+
+func Test_that_ReactivateUser_allows_login_and_revokes_old_tokens(t *testing.T) {
+	client := setupAndMigrate(t)
+	ctx := context.Background()
+	u, err := Create(ctx, client, "user1", USER1_TEST_EMAIL, "password")
+	require.NoError(t, err)
+	u, err = DisableUser(ctx, client, u)
+	require.NoError(t, err)
+	opts := &TokenOptions{Secret: "secret"}
+	tok, err := NewToken(u, opts)
+	require.NoError(t, err)
+	u, err = ReactivateUser(ctx, client, u)
+	require.NoError(t, err)
+	require.Equal(t, user.StatusActive, u.Status)
+	_, err = LoginByName(ctx, client, "user1", "password")
+	require.NoError(t, err)
+	_, err = ValidateToken(ctx, client, tok, opts)
+	require.ErrorIs(t, err, ErrTokenRevoked)
+}
@@ -0,0 +1,176 @@
+package users
+
+import (
+	"context"
+	"time"
+
+	"github.com/smxlong/users/ent/user"
+)
+
+// User is a user as held by a Store.
+type User struct {
+	// ID is assigned by the Store when the user is created.
+	ID           string
+	Name         string
+	Email        string
+	PasswordHash string
+	// Status is "active", "disabled", "suspended" or "pending". If empty
+	// when the user is created, it is "active".
+	Status string
+	// SuspendedUntil is when a suspension ends, if the user is suspended.
+	SuspendedUntil *time.Time
+	// EmailVerifiedAt is when the user proved they own their email address,
+	// if they have.
+	EmailVerifiedAt *time.Time
+	// LastLoginAt, LastLoginIP and FailedLoginCount are the user's login
+	// metadata. They are changed by RecordLogin and RecordLoginFailure, and
+	// not by UpdateUser.
+	LastLoginAt      *time.Time
+	LastLoginIP      string
+	FailedLoginCount int
+}
+
+// userStatus returns the user's status, which is "active" if not set.
+func userStatus(u *User) user.Status {
+	if u.Status == "" {
+		return user.StatusActive
+	}
+	return user.Status(u.Status)
+}
+
+// Role is a role as held by a Store.
+type Role struct {
+	Name        string
+	Description string
+	// Permissions are the names of the role's permissions.
+	Permissions []string
+	// Inherits are the names of the roles this role inherits the
+	// permissions of.
+	Inherits []string
+}
+
+// Store holds users, roles, permissions and sessions. The Service implements
+// the package's operations on top of a Store, so the package can be used with
+// any storage. EntStore is the Store for the generated ent client.
+//
+// Methods fail with ErrNotFound if a user, role or permission they refer to
+// doesn't exist, and with ErrAlreadyExists if creating would duplicate a
+// user's name or email, or a role or permission name.
+type Store interface {
+	UserStore
+	RoleStore
+	PermissionStore
+	SessionStore
+}
+
+// UserStore holds users and their roles.
+type UserStore interface {
+	// CreateUser creates a user, returning it with its ID.
+	CreateUser(ctx context.Context, u *User) (*User, error)
+	// FindUserByID finds a user by ID.
+	FindUserByID(ctx context.Context, id string) (*User, error)
+	// FindUserByName finds a user by name.
+	FindUserByName(ctx context.Context, name string) (*User, error)
+	// FindUserByEmail finds a user by email.
+	FindUserByEmail(ctx context.Context, email string) (*User, error)
+	// UpdateUser saves changes to a user.
+	UpdateUser(ctx context.Context, u *User) (*User, error)
+	// DeleteUser deletes a user. The user's name and email address can't be
+	// reused afterwards.
+	DeleteUser(ctx context.Context, id string) error
+	// RecordLogin records a successful login at the given time, from the IP
+	// address if it isn't empty, and resets the user's failed login count.
+	// It returns the updated user.
+	RecordLogin(ctx context.Context, id string, at time.Time, ip string) (*User, error)
+	// RecordLoginFailure atomically increments the user's failed login
+	// count.
+	RecordLoginFailure(ctx context.Context, id string) error
+	// AddUserRole gives a user a role. Giving a user a role it already has
+	// succeeds.
+	AddUserRole(ctx context.Context, id, role string) error
+	// RemoveUserRole takes a role from a user. Taking a role the user doesn't
+	// have, or that doesn't exist, succeeds.
+	RemoveUserRole(ctx context.Context, id, role string) error
+	// UserRoles returns the names of a user's roles, in order.
+	UserRoles(ctx context.Context, id string) ([]string, error)
+	// AddUserRoleOn gives a user a role on a resource. Giving a user a role
+	// it already has on the resource succeeds.
+	AddUserRoleOn(ctx context.Context, id, role string, resource Resource) error
+	// RemoveUserRoleOn takes a role on a resource from a user. Taking a role
+	// the user doesn't have on the resource, or that doesn't exist, succeeds.
+	RemoveUserRoleOn(ctx context.Context, id, role string, resource Resource) error
+	// UserRolesOn returns the names of the roles a user was given on the
+	// resource, in order. Roles given on every resource aren't included.
+	UserRolesOn(ctx context.Context, id string, resource Resource) ([]string, error)
+	// UserPermissions returns the names of the permissions of all of a
+	// user's roles and the roles they inherit, in order and without
+	// duplicates.
+	UserPermissions(ctx context.Context, id string) ([]string, error)
+}
+
+// RoleStore holds roles and their permissions.
+type RoleStore interface {
+	// CreateRole creates a role with its permissions and the roles it
+	// inherits. A role's permissions and inherited roles are always in
+	// order. It fails with ErrRoleCycle if the role would inherit itself.
+	CreateRole(ctx context.Context, r *Role) (*Role, error)
+	// FindRole finds a role by name.
+	FindRole(ctx context.Context, name string) (*Role, error)
+	// ListRoles returns all roles, ordered by name.
+	ListRoles(ctx context.Context) ([]*Role, error)
+	// UpdateRoleDescription changes a role's description.
+	UpdateRoleDescription(ctx context.Context, name, description string) error
+	// AddRolePermission adds a permission to a role. Adding a permission the
+	// role already has succeeds.
+	AddRolePermission(ctx context.Context, role, permission string) error
+	// RemoveRolePermission removes a permission from a role. Removing a
+	// permission the role doesn't have, or that doesn't exist, succeeds.
+	RemoveRolePermission(ctx context.Context, role, permission string) error
+	// SetRolePermissions sets a role's permissions to exactly the given
+	// permissions.
+	SetRolePermissions(ctx context.Context, role string, permissions []string) error
+	// SetRoleInherits sets the roles a role inherits to exactly the given
+	// roles. It fails with ErrRoleCycle if the role would inherit itself,
+	// directly or indirectly.
+	SetRoleInherits(ctx context.Context, role string, inherits []string) error
+	// DeleteRole deletes a role. Users lose the role, on every resource and
+	// on the resources they were given it on, and roles stop
+	// inheriting it.
+	DeleteRole(ctx context.Context, name string) error
+}
+
+// PermissionStore holds permissions.
+type PermissionStore interface {
+	// CreatePermission creates a permission. It fails with
+	// ErrPermissionNameInvalid if the name doesn't satisfy
+	// ValidatePermissionName.
+	CreatePermission(ctx context.Context, p *Permission) (*Permission, error)
+	// FindPermission finds a permission by name.
+	FindPermission(ctx context.Context, name string) (*Permission, error)
+	// ListPermissions returns all permissions, ordered by name.
+	ListPermissions(ctx context.Context) ([]*Permission, error)
+	// UpdatePermissionDescription changes a permission's description.
+	UpdatePermissionDescription(ctx context.Context, name, description string) error
+	// DeletePermission deletes a permission. Roles lose the permission.
+	DeletePermission(ctx context.Context, name string) error
+}
+
+// SessionStore holds the state that revokes a user's sessions. Each user
+// has a token generation, which is included in the user's tokens. Revoking
+// the user's sessions changes the generation, so that earlier tokens no
+// longer match it.
+type SessionStore interface {
+	// TokenGeneration returns a user's token generation.
+	TokenGeneration(ctx context.Context, id string) (int, error)
+	// RevokeSessions changes a user's token generation.
+	RevokeSessions(ctx context.Context, id string) error
+}
+
+// TxStore is a Store that can make several changes atomically. The Service
+// uses it, if its Store is one, for operations that make several changes.
+type TxStore interface {
+	Store
+	// InTx calls fn with a Store making its changes in a transaction, which
+	// is committed if fn succeeds and rolled back if not.
+	InTx(ctx context.Context, fn func(store Store) error) error
+}
@@ -0,0 +1,31 @@
+package users_test
+
+import (
+	"context"
+	"testing"
+
+	_ "github.com/mattn/go-sqlite3"
+	"github.com/stretchr/testify/require"
+
+	"github.com/smxlong/users"
+	"github.com/smxlong/users/ent"
+	"github.com/smxlong/users/userstest"
+)
+
+func Test_that_EntStore_conforms(t *testing.T) {
+	userstest.RunStoreConformance(t, func(t *testing.T) users.Store {
+		client, err := ent.Open("sqlite3", "file:ent?mode=memory&cache=shared&_fk=1")
+		require.NoError(t, err)
+		t.Cleanup(func() {
+			_ = client.Close()
+		})
+		require.NoError(t, client.Schema.Create(context.Background()))
+		return users.NewEntStore(client)
+	})
+}
+
+func Test_that_MemoryStore_conforms(t *testing.T) {
+	userstest.RunStoreConformance(t, func(t *testing.T) users.Store {
+		return users.NewMemoryStore()
+	})
+}
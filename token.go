@@ -21,7 +21,7 @@ type TokenOptions struct {
 	// defaults to 1 hour.
 	ValidFor time.Duration
 	// NotValidBefore is the time before which the token is invalid. Optional. If
-	// not set, defaults to now.
+	// not set, the token is valid from when it is issued, and has no nbf claim.
 	NotValidBefore time.Time
 }
 
@@ -49,26 +49,67 @@ func (o *TokenOptions) GetValidFor() time.Duration {
 	return o.ValidFor
 }
 
-// GetNotValidBefore returns the TokenOptions NotValidBefore, or the default if not set.
+// GetNotValidBefore returns the TokenOptions NotValidBefore, or now if not set.
 func (o *TokenOptions) GetNotValidBefore(now time.Time) time.Time {
+	if o.NotValidBefore.IsZero() {
+		return now
+	}
 	return o.NotValidBefore
 }
 
+// tokenGenerationKey is the private claim holding the user's token generation
+// at the time the token was issued.
+const tokenGenerationKey = "gen"
+
+// tokenPermissionsKey is the private claim holding the user's permissions at
+// the time the token was issued, for tokens from NewTokenWithPermissions.
+const tokenPermissionsKey = "perms"
+
 // NewToken creates a new JWT for a user.
 func NewToken(u *ent.User, opts *TokenOptions) (string, error) {
-	now := time.Now()
+	return NewTokenContext(context.Background(), u, opts)
+}
+
+// NewTokenContext creates a new JWT for a user, issued at the time according
+// to the context's Clock.
+func NewTokenContext(ctx context.Context, u *ent.User, opts *TokenOptions) (string, error) {
+	return signToken(ctx, u.Email, u.TokenGeneration, nil, opts)
+}
+
+// NewTokenWithPermissions creates a new JWT for a user, holding the
+// permissions the user has through its roles, so they can be checked with
+// CheckTokenPermission without loading the user.
+func NewTokenWithPermissions(ctx context.Context, client *ent.Client, u *ent.User, opts *TokenOptions) (string, error) {
+	effective, err := EffectivePermissions(ctx, client, u)
+	if err != nil {
+		return "", err
+	}
+	permissions := make([]string, len(effective))
+	for i, ep := range effective {
+		permissions[i] = ep.Permission
+	}
+	return signToken(ctx, u.Email, u.TokenGeneration, permissions, opts)
+}
+
+// signToken creates a new JWT for the user with the email address and token
+// generation. Permissions, if not nil, are held in the token.
+func signToken(ctx context.Context, email string, generation int, permissions []string, opts *TokenOptions) (string, error) {
 	if opts.Secret == "" {
 		return "", ErrTokenSecretRequired
 	}
+	now := clockNow(ctx)
 	claims := jwt.New()
-	claims.Set(jwt.SubjectKey, u.Email)
+	claims.Set(jwt.SubjectKey, email)
 	claims.Set(jwt.IssuerKey, opts.GetIssuer())
 	claims.Set(jwt.AudienceKey, opts.GetAudience())
 	claims.Set(jwt.IssuedAtKey, now.Unix())
 	claims.Set(jwt.ExpirationKey, now.Add(opts.GetValidFor()).Unix())
-	nbf := opts.GetNotValidBefore(now)
-	if !nbf.IsZero() {
-		claims.Set(jwt.NotBeforeKey, nbf.Unix())
+	claims.Set(tokenGenerationKey, generation)
+	if permissions != nil {
+		claims.Set(tokenPermissionsKey, permissions)
+	}
+	if !opts.NotValidBefore.IsZero() {
+		claims.Set(jwt.NotBeforeKey, opts.GetNotValidBefore(now).Unix())
 	}
 	token, err := jwt.Sign(claims, jwt.WithKey(jwa.HS256(), []byte(opts.Secret)))
 	if err != nil {
@@ -77,23 +118,88 @@ func NewToken(u *ent.User, opts *TokenOptions) (string, error) {
 	return string(token), nil
 }
 
-// ValidateToken validates a JWT for a user, returning the user.
+// ValidateToken validates a JWT for a user, returning the user. Users who
+// aren't active are rejected as CheckStatus does.
 func ValidateToken(ctx context.Context, client *ent.Client, token string, opts *TokenOptions) (*ent.User, error) {
+	email, generation, err := parseToken(ctx, token, opts)
+	if err != nil {
+		return nil, err
+	}
+	u, err := FindByEmail(ctx, client, email)
+	if err != nil {
+		return nil, err
+	}
+	if generation != u.TokenGeneration {
+		return nil, ErrTokenRevoked
+	}
+	if err := CheckStatus(ctx, u); err != nil {
+		return nil, err
+	}
+	return u, nil
+}
+
+// parseToken validates a JWT, returning the email address and token
+// generation of the user it was issued to.
+func parseToken(ctx context.Context, token string, opts *TokenOptions) (string, int, error) {
+	claims, err := parseClaims(ctx, token, opts)
+	if err != nil {
+		return "", 0, err
+	}
+	sub, _ := claims.Subject()
+	// Tokens issued before generations were introduced have none, and count
+	// as generation zero.
+	var gen float64
+	_ = claims.Get(tokenGenerationKey, &gen)
+	return sub, int(gen), nil
+}
+
+// parseClaims validates a JWT at the time according to the context's Clock,
+// returning its claims.
+func parseClaims(ctx context.Context, token string, opts *TokenOptions) (jwt.Token, error) {
 	if opts.Secret == "" {
 		return nil, ErrTokenSecretRequired
 	}
-	claims, err := jwt.Parse([]byte(token),
+	return jwt.Parse([]byte(token),
 		jwt.WithIssuer(opts.GetIssuer()),
 		jwt.WithAudience(opts.GetAudience()),
 		jwt.WithKey(jwa.HS256(), []byte(opts.Secret)),
+		jwt.WithClock(jwt.ClockFunc(clockFrom(ctx).Now)),
 	)
+}
+
+// TokenPermissions validates a JWT, returning the permissions it holds.
+// Tokens from NewToken hold none. The user isn't loaded, so revoked tokens
+// and users who aren't active aren't rejected; use ValidateToken for that.
+func TokenPermissions(ctx context.Context, token string, opts *TokenOptions) ([]string, error) {
+	claims, err := parseClaims(ctx, token, opts)
 	if err != nil {
 		return nil, err
 	}
-	sub, _ := claims.Subject()
-	u, err := FindByEmail(ctx, client, sub)
+	var claim []any
+	_ = claims.Get(tokenPermissionsKey, &claim)
+	permissions := make([]string, 0, len(claim))
+	for _, p := range claim {
+		if name, ok := p.(string); ok {
+			permissions = append(permissions, name)
+		}
+	}
+	return permissions, nil
+}
+
+// CheckTokenPermission validates a JWT, and checks if it holds all the listed
+// permissions. Wildcard permissions match as they do for CheckPermission.
+func CheckTokenPermission(ctx context.Context, token string, opts *TokenOptions, p ...string) (bool, error) {
+	permissions, err := TokenPermissions(ctx, token, opts)
 	if err != nil {
-		return nil, err
+		return false, err
 	}
-	return u, nil
+	return hasPermissions(permissions, p), nil
+}
+
+// RevokeTokens revokes all tokens issued to a user so far. ValidateToken
+// rejects them with ErrTokenRevoked.
+func RevokeTokens(ctx context.Context, client *ent.Client, u *ent.User) (*ent.User, error) {
+	return u.Update().
+		AddTokenGeneration(1).
+		Save(ctx)
 }
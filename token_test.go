@@ -7,6 +7,7 @@ import (
 
 	"github.com/lestrrat-go/jwx/v3/jwa"
 	"github.com/lestrrat-go/jwx/v3/jwt"
+	"github.com/smxlong/users/ent/role"
 	"github.com/stretchr/testify/require"
 )
 
@@ -41,22 +42,68 @@ func Test_that_NewToken_sets_the_audience_and_issuer(t *testing.T) {
 
 func Test_that_NewToken_sets_the_expiration(t *testing.T) {
 	client := setupAndMigrate(t)
-	ctx := context.Background()
+	clock := NewFakeClock(time.Date(2024, 1, 2, 3, 4, 5, 0, time.UTC))
+	ctx := ContextWithClock(context.Background(), clock)
 	u, err := Create(ctx, client, "user1", USER1_TEST_EMAIL, "password")
 	require.NoError(t, err)
 	validFor := 5 * time.Minute
-	tok, err := NewToken(u, &TokenOptions{
+	tok, err := NewTokenContext(ctx, u, &TokenOptions{
 		Secret:   "foo",
 		ValidFor: validFor,
 	})
 	require.NoError(t, err)
-	ptok, err := jwt.Parse([]byte(tok), jwt.WithIssuer("users"), jwt.WithAudience("users"), jwt.WithKey(jwa.HS256(), []byte("foo")))
+	ptok, err := jwt.Parse([]byte(tok), jwt.WithIssuer("users"), jwt.WithAudience("users"), jwt.WithKey(jwa.HS256(), []byte("foo")), jwt.WithClock(jwt.ClockFunc(clock.Now)))
 	require.NoError(t, err)
 	require.NotNil(t, ptok)
+	iat, ok := ptok.IssuedAt()
+	require.True(t, ok)
+	require.True(t, clock.Now().Equal(iat))
 	exp, ok := ptok.Expiration()
 	require.True(t, ok)
-	const TIME_COMPARE_TOLERANCE = 1 * time.Second
-	require.WithinDuration(t, time.Now().Add(validFor), exp, TIME_COMPARE_TOLERANCE)
+	require.True(t, clock.Now().Add(validFor).Equal(exp))
+}
+
+func Test_that_ValidateToken_rejects_tokens_from_their_expiry(t *testing.T) {
+	client := setupAndMigrate(t)
+	clock := NewFakeClock(time.Date(2024, 1, 2, 3, 4, 5, 0, time.UTC))
+	ctx := ContextWithClock(context.Background(), clock)
+	u, err := Create(ctx, client, "user1", USER1_TEST_EMAIL, "password")
+	require.NoError(t, err)
+	opts := &TokenOptions{Secret: "foo", ValidFor: time.Hour}
+	tok, err := NewTokenContext(ctx, u, opts)
+	require.NoError(t, err)
+	clock.Advance(time.Hour - time.Second)
+	_, err = ValidateToken(ctx, client, tok, opts)
+	require.NoError(t, err)
+	clock.Advance(time.Second)
+	_, err = ValidateToken(ctx, client, tok, opts)
+	require.Error(t, err)
+}
+
+func Test_that_ValidateToken_rejects_tokens_before_nbf(t *testing.T) {
+	client := setupAndMigrate(t)
+	now := time.Date(2024, 1, 2, 3, 4, 5, 0, time.UTC)
+	clock := NewFakeClock(now)
+	ctx := ContextWithClock(context.Background(), clock)
+	u, err := Create(ctx, client, "user1", USER1_TEST_EMAIL, "password")
+	require.NoError(t, err)
+	opts := &TokenOptions{Secret: "foo", NotValidBefore: now.Add(time.Minute)}
+	tok, err := NewTokenContext(ctx, u, opts)
+	require.NoError(t, err)
+	clock.Advance(time.Minute - time.Second)
+	_, err = ValidateToken(ctx, client, tok, opts)
+	require.Error(t, err)
+	clock.Advance(time.Second)
+	_, err = ValidateToken(ctx, client, tok, opts)
+	require.NoError(t, err)
+}
+
+func Test_that_GetNotValidBefore_defaults_to_now(t *testing.T) {
+	now := time.Date(2024, 1, 2, 3, 4, 5, 0, time.UTC)
+	opts := &TokenOptions{}
+	require.Equal(t, now, opts.GetNotValidBefore(now))
+	opts.NotValidBefore = now.Add(time.Minute)
+	require.Equal(t, now.Add(time.Minute), opts.GetNotValidBefore(now))
 }
 
 func Test_that_NewToken_omits_nbf_if_not_set(t *testing.T) {
@@ -150,3 +197,60 @@ func Test_that_ValidateToken_fails_if_user_does_not_exist_anymore(t *testing.T)
 	_, err = ValidateToken(ctx, client, tok, opts)
 	require.Error(t, err)
 }
+
+func Test_that_RevokeTokens_revokes_existing_tokens(t *testing.T) {
+	client := setupAndMigrate(t)
+	ctx := context.Background()
+	u, err := Create(ctx, client, "user1", USER1_TEST_EMAIL, "password")
+	require.NoError(t, err)
+	opts := &TokenOptions{Secret: "foo"}
+	tok, err := NewToken(u, opts)
+	require.NoError(t, err)
+	u, err = RevokeTokens(ctx, client, u)
+	require.NoError(t, err)
+	_, err = ValidateToken(ctx, client, tok, opts)
+	require.ErrorIs(t, err, ErrTokenRevoked)
+	tok, err = NewToken(u, opts)
+	require.NoError(t, err)
+	_, err = ValidateToken(ctx, client, tok, opts)
+	require.NoError(t, err)
+}
+
+func Test_that_CheckTokenPermission_checks_the_token_permissions(t *testing.T) {
+	client := setupAndMigrate(t)
+	ctx := context.Background()
+	require.NoError(t, CreateRolesAndPermissions(ctx, client, Roles{
+		"reporter": &RoleWithPermissions{
+			Permissions: []*Permission{
+				{Name: "reports:*", Description: "Everything on reports"},
+				{Name: "billing:read", Description: "Read billing"},
+			},
+		},
+	}))
+	u, err := Create(ctx, client, "user1", USER1_TEST_EMAIL, "password")
+	require.NoError(t, err)
+	reporter, err := client.Role.Query().Where(role.Name("reporter")).Only(ctx)
+	require.NoError(t, err)
+	u, err = AddRole(ctx, client, u, reporter)
+	require.NoError(t, err)
+	opts := &TokenOptions{Secret: "foo"}
+	tok, err := NewTokenWithPermissions(ctx, client, u, opts)
+	require.NoError(t, err)
+	permissions, err := TokenPermissions(ctx, tok, opts)
+	require.NoError(t, err)
+	require.Equal(t, []string{"billing:read", "reports:*"}, permissions)
+	ok, err := CheckTokenPermission(ctx, tok, opts, "reports:read", "billing:read")
+	require.NoError(t, err)
+	require.True(t, ok)
+	ok, err = CheckTokenPermission(ctx, tok, opts, "billing:write")
+	require.NoError(t, err)
+	require.False(t, ok)
+	// tokens from NewToken hold no permissions
+	tok, err = NewToken(u, opts)
+	require.NoError(t, err)
+	ok, err = CheckTokenPermission(ctx, tok, opts, "reports:read")
+	require.NoError(t, err)
+	require.False(t, ok)
+	_, err = CheckTokenPermission(ctx, tok, &TokenOptions{Secret: "bar"}, "reports:read")
+	require.Error(t, err)
+}
@@ -0,0 +1,41 @@
+package users
+
+import (
+	"net/url"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func Test_that_totpCode_matches_RFC_6238_test_vectors(t *testing.T) {
+	secret := []byte("12345678901234567890")
+	require.Equal(t, "287082", totpCode(secret, totpStep(time.Unix(59, 0))))
+	require.Equal(t, "081804", totpCode(secret, totpStep(time.Unix(1111111109, 0))))
+	require.Equal(t, "050471", totpCode(secret, totpStep(time.Unix(1111111111, 0))))
+	require.Equal(t, "005924", totpCode(secret, totpStep(time.Unix(1234567890, 0))))
+}
+
+func Test_that_totpMatch_honours_skew(t *testing.T) {
+	secret := []byte("12345678901234567890")
+	now := time.Unix(1111111109, 0)
+	prev := totpCode(secret, totpStep(now)-1)
+	step, ok := totpMatch(secret, prev, now, 1)
+	require.True(t, ok)
+	require.Equal(t, totpStep(now)-1, step)
+	_, ok = totpMatch(secret, prev, now, 0)
+	require.False(t, ok)
+	_, ok = totpMatch(secret, "12345", now, 1)
+	require.False(t, ok)
+}
+
+func Test_that_totpURI_builds_an_otpauth_URI(t *testing.T) {
+	uri := totpURI("Example", USER1_TEST_EMAIL, []byte("12345678901234567890"))
+	u, err := url.Parse(uri)
+	require.NoError(t, err)
+	require.Equal(t, "otpauth", u.Scheme)
+	require.Equal(t, "totp", u.Host)
+	require.Equal(t, "/Example:"+USER1_TEST_EMAIL, u.Path)
+	require.Equal(t, "GEZDGNBVGY3TQOJQGEZDGNBVGY3TQOJQ", u.Query().Get("secret"))
+	require.Equal(t, "Example", u.Query().Get("issuer"))
+}
@@ -0,0 +1,79 @@
+package users
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/smxlong/users/ent"
+)
+
+// WithTx runs fn in a new transaction on the client, committing it if fn
+// succeeds and rolling it back if not. To use the package's functions in the
+// transaction, pass them tx.Client(). Functions that make several changes,
+// such as SyncRolesAndPermissions, run in a transaction of their own when
+// given a plain client, and join the transaction when given tx.Client().
+//
+// Entities loaded in the transaction are bound to it. Use their Unwrap method
+// before using them after the transaction ends.
+func WithTx(ctx context.Context, client *ent.Client, fn func(tx *ent.Tx) error) error {
+	tx, err := client.Tx(ctx)
+	if err != nil {
+		return err
+	}
+	return runTx(tx, fn)
+}
+
+// runTx runs fn in the transaction, committing it if fn succeeds and rolling
+// it back if not.
+func runTx(tx *ent.Tx, fn func(tx *ent.Tx) error) error {
+	defer func() {
+		if v := recover(); v != nil {
+			_ = tx.Rollback()
+			panic(v)
+		}
+	}()
+	if err := fn(tx); err != nil {
+		if rerr := tx.Rollback(); rerr != nil {
+			err = fmt.Errorf("%w: rolling back transaction: %v", err, rerr)
+		}
+		return err
+	}
+	return tx.Commit()
+}
+
+// inTx runs fn in a transaction, passing it the transaction's client. If the
+// client is already in a transaction, fn runs in that one, which the caller
+// commits or rolls back. It reports whether it started the transaction.
+func inTx(ctx context.Context, client *ent.Client, fn func(client *ent.Client) error) (bool, error) {
+	tx, err := client.Tx(ctx)
+	if errors.Is(err, ent.ErrTxStarted) {
+		return false, fn(client)
+	}
+	if err != nil {
+		return false, err
+	}
+	return true, runTx(tx, func(tx *ent.Tx) error {
+		return fn(tx.Client())
+	})
+}
+
+// inTxEntity is inTx for functions returning an entity. If inTxEntity
+// started the transaction, the entity is unwrapped from it, so that it can
+// be used afterwards.
+func inTxEntity[T interface{ Unwrap() T }](ctx context.Context, client *ent.Client, fn func(client *ent.Client) (T, error)) (T, error) {
+	var entity T
+	started, err := inTx(ctx, client, func(client *ent.Client) error {
+		var err error
+		entity, err = fn(client)
+		return err
+	})
+	if err != nil {
+		var zero T
+		return zero, err
+	}
+	if started {
+		entity = entity.Unwrap()
+	}
+	return entity, nil
+}
@@ -0,0 +1,116 @@
+package users
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/smxlong/users/ent"
+)
+
+func Test_that_WithTx_commits_on_success(t *testing.T) {
+	client := setupAndMigrate(t)
+	ctx := context.Background()
+	var u *ent.User
+	err := WithTx(ctx, client, func(tx *ent.Tx) error {
+		var err error
+		u, err = Create(ctx, tx.Client(), "user1", USER1_TEST_EMAIL, "password")
+		return err
+	})
+	require.NoError(t, err)
+	u = u.Unwrap()
+	_, err = FindByName(ctx, client, "user1")
+	require.NoError(t, err)
+	_, err = RevokeTokens(ctx, client, u)
+	require.NoError(t, err)
+}
+
+func Test_that_WithTx_rolls_back_on_error(t *testing.T) {
+	client := setupAndMigrate(t)
+	ctx := context.Background()
+	errTest := errors.New("test")
+	err := WithTx(ctx, client, func(tx *ent.Tx) error {
+		if _, err := Create(ctx, tx.Client(), "user1", USER1_TEST_EMAIL, "password"); err != nil {
+			return err
+		}
+		// CreateRolesAndPermissions joins the transaction instead of
+		// committing its own.
+		if err := CreateRolesAndPermissions(ctx, tx.Client(), Roles{
+			"user": {Permissions: []*Permission{{Name: "read"}}},
+		}); err != nil {
+			return err
+		}
+		return errTest
+	})
+	require.ErrorIs(t, err, errTest)
+	_, err = FindByName(ctx, client, "user1")
+	require.True(t, ent.IsNotFound(err))
+	exists, err := RoleExists(ctx, client, "user")
+	require.NoError(t, err)
+	require.False(t, exists)
+}
+
+func Test_that_Login_uses_the_transaction_the_user_was_loaded_in(t *testing.T) {
+	client := setupAndMigrate(t)
+	ctx := context.Background()
+	errTest := errors.New("test")
+	err := WithTx(ctx, client, func(tx *ent.Tx) error {
+		u, err := Create(ctx, tx.Client(), "user1", USER1_TEST_EMAIL, "password")
+		if err != nil {
+			return err
+		}
+		// the user only exists in the transaction
+		u, err = Login(ctx, u, "password")
+		if err != nil {
+			return err
+		}
+		require.NotNil(t, u.LastLoginAt)
+		return errTest
+	})
+	require.ErrorIs(t, err, errTest)
+	_, err = FindByName(ctx, client, "user1")
+	require.True(t, ent.IsNotFound(err))
+}
+
+func Test_that_Service_CreateRolesAndPermissions_makes_no_changes_on_failure(t *testing.T) {
+	s := setupService(t)
+	ctx := context.Background()
+	_, err := s.CreatePermission(ctx, "write", "Write")
+	require.NoError(t, err)
+	err = s.CreateRolesAndPermissions(ctx, Roles{
+		"reader": {Permissions: []*Permission{{Name: "read", Description: "Read"}}},
+		"writer": {Permissions: []*Permission{{Name: "write", Description: "Write 2"}}},
+	})
+	require.ErrorIs(t, err, ErrPermissionDescriptionMismatch)
+	_, err = s.Store().FindPermission(ctx, "read")
+	require.ErrorIs(t, err, ErrNotFound)
+}
+
+func Test_that_RequestPasswordReset_issues_the_link_in_the_transaction(t *testing.T) {
+	client := setupAndMigrate(t)
+	ctx := context.Background()
+	_, err := Create(ctx, client, "user1", USER1_TEST_EMAIL, "password")
+	require.NoError(t, err)
+	m := &MemoryMailer{}
+	errTest := errors.New("test")
+	err = WithTx(ctx, client, func(tx *ent.Tx) error {
+		if err := RequestPasswordReset(ctx, tx.Client(), USER1_TEST_EMAIL, testEmailOptions(m)); err != nil {
+			return err
+		}
+		return errTest
+	})
+	require.ErrorIs(t, err, errTest)
+	testWaitForMessages(t, m, 1)
+	n, err := client.OneTimeToken.Query().Count(ctx)
+	require.NoError(t, err)
+	require.Zero(t, n)
+	err = WithTx(ctx, client, func(tx *ent.Tx) error {
+		return RequestPasswordReset(ctx, tx.Client(), USER1_TEST_EMAIL, testEmailOptions(m))
+	})
+	require.NoError(t, err)
+	testWaitForMessages(t, m, 2)
+	_, err = ResetPassword(ctx, client, testEmailedToken(t, m.Messages()[1]), "new password", nil)
+	require.NoError(t, err)
+}
@@ -2,26 +2,51 @@ package users
 
 import (
 	"context"
+	"errors"
+	"strconv"
+	"sync"
 
 	"github.com/smxlong/users/ent"
-	"github.com/smxlong/users/ent/permission"
+	// The runtime registers the schema hooks and interceptors, such as for
+	// soft deletion.
+	_ "github.com/smxlong/users/ent/runtime"
 	"github.com/smxlong/users/ent/user"
 )
 
-// These functions are for example only - you'll need to copy them into your
-// project to work on your own generated types.
+// These functions work on this package's generated ent types. To use the
+// package with other storage and without ent types, use a Service with a
+// Store. A Service also holds the token options and policies these functions
+// take as arguments, and offers the operations a Store supports as methods.
+// The functions shared with the Service, such as Create, Login and
+// CheckPermission, delegate to a Service using an EntStore on the client, so
+// that both behave the same.
+//
+// Every function taking a client runs in an existing transaction when given
+// the transaction's client, tx.Client(). See WithTx.
 
-// Create a user from name, email, and password, hashing the password.
-func Create(ctx context.Context, client *ent.Client, name, email, password string) (*ent.User, error) {
-	ph, err := PasswordHashDefault(password)
+// entService returns a Service using an EntStore on the client, for the
+// functions to delegate to. It reads the time from the context's Clock.
+func entService(ctx context.Context, client *ent.Client) *Service {
+	return NewService(WithClient(client), WithClock(clockFrom(ctx)))
+}
+
+// toEntUser loads the ent user for a user returned by a Service using an
+// EntStore on the client, or returns the Service's error.
+func toEntUser(ctx context.Context, client *ent.Client, u *User, err error) (*ent.User, error) {
 	if err != nil {
 		return nil, err
 	}
-	return client.User.Create().
-		SetName(name).
-		SetEmail(email).
-		SetPasswordHash(ph.String()).
-		Save(ctx)
+	id, err := strconv.Atoi(u.ID)
+	if err != nil {
+		return nil, err
+	}
+	return client.User.Get(ctx, id)
+}
+
+// Create a user from name, email, and password, hashing the password.
+func Create(ctx context.Context, client *ent.Client, name, email, password string) (*ent.User, error) {
+	u, err := entService(ctx, client).Create(ctx, name, email, password)
+	return toEntUser(ctx, client, u, err)
 }
 
 // FindByName finds a user by name.
@@ -38,34 +63,157 @@ func FindByEmail(ctx context.Context, client *ent.Client, email string) (*ent.Us
 		Only(ctx)
 }
 
-// LoginByName finds a user by name and verifies the password.
-func LoginByName(ctx context.Context, client *ent.Client, name, password string) (*ent.User, error) {
-	u, err := FindByName(ctx, client, name)
+// LoginOption enables optional checks made by the login functions.
+type LoginOption func(*loginOptions)
+
+// loginOptions holds the optional login checks.
+type loginOptions struct {
+	requireVerifiedEmail bool
+	lockout              *LockoutPolicy
+	source               string
+	ip                   string
+}
+
+// newLoginOptions applies the LoginOptions.
+func newLoginOptions(opts []LoginOption) *loginOptions {
+	o := &loginOptions{}
+	for _, opt := range opts {
+		opt(o)
+	}
+	return o
+}
+
+// RequireVerifiedEmail rejects users whose email address has not been
+// verified with ErrEmailNotVerified.
+func RequireVerifiedEmail() LoginOption {
+	return func(o *loginOptions) {
+		o.requireVerifiedEmail = true
+	}
+}
+
+// WithLockout throttles password guessing according to the policy. Failures
+// are counted against the user and against source, which identifies where
+// the login came from, such as the client IP address. If source is empty,
+// only the user is counted. Logins from a locked out source fail with an
+// error wrapping ErrAccountLocked. Logins to a locked out user with the right
+// password fail with an error wrapping ErrAccountLocked too. With a wrong
+// password, they fail with ErrInvalidCredentials, as for an unknown user, so
+// that lockouts don't reveal which users exist to those without the password.
+func WithLockout(policy *LockoutPolicy, source string) LoginOption {
+	return func(o *loginOptions) {
+		o.lockout = policy
+		o.source = source
+	}
+}
+
+// WithIP records the IP address the user logs in from as their
+// LastLoginIP.
+func WithIP(ip string) LoginOption {
+	return func(o *loginOptions) {
+		o.ip = ip
+	}
+}
+
+// LoginByName finds a user by name and verifies the password. An unknown name
+// fails with ErrInvalidCredentials, as does a wrong password. Users with MFA
+// enabled are rejected with ErrMFARequired, and must log in with
+// LoginMFAByName.
+func LoginByName(ctx context.Context, client *ent.Client, name, password string, opts ...LoginOption) (*ent.User, error) {
+	s := entService(ctx, client)
+	u, err := s.store.FindUserByName(ctx, name)
 	if err != nil {
-		return nil, err
+		return nil, s.loginNotFound(s.context(ctx), err, newLoginOptions(opts))
 	}
-	return Login(ctx, u, password)
+	return login(ctx, client, u, password, opts)
 }
 
-// LoginByEmail finds a user by email and verifies the password.
-func LoginByEmail(ctx context.Context, client *ent.Client, email, password string) (*ent.User, error) {
-	u, err := FindByEmail(ctx, client, email)
+// LoginByEmail finds a user by email and verifies the password. An unknown
+// email fails with ErrInvalidCredentials, as does a wrong password. Users with
+// MFA enabled are rejected with ErrMFARequired, and must log in with
+// LoginMFAByEmail.
+func LoginByEmail(ctx context.Context, client *ent.Client, email, password string, opts ...LoginOption) (*ent.User, error) {
+	s := entService(ctx, client)
+	u, err := s.store.FindUserByEmail(ctx, email)
 	if err != nil {
-		return nil, err
+		return nil, s.loginNotFound(s.context(ctx), err, newLoginOptions(opts))
+	}
+	return login(ctx, client, u, password, opts)
+}
+
+// dummyPasswordHash is verified against when there is no real password hash
+// to check, so that the response takes as long as if there were.
+var dummyPasswordHash = sync.OnceValue(func() *PasswordHash {
+	ph, err := PasswordHashDefault("dummy password")
+	if err != nil {
+		panic(err)
 	}
-	return Login(ctx, u, password)
+	return ph
+})
+
+// verifyPassword checks the password against a hash. A user without a
+// password takes as long to reject as one with a password.
+func verifyPassword(ph *PasswordHash, password string) error {
+	if ph.Algorithm == "none" {
+		_ = dummyPasswordHash().Verify(password)
+	}
+	return ph.Verify(password)
 }
 
-// Login verifies the password for a user.
-func Login(ctx context.Context, u *ent.User, password string) (*ent.User, error) {
-	ph, err := PasswordHashParse(u.PasswordHash)
+// checkPassword verifies the password against a user's password hash,
+// without the side effects of Login. A wrong password fails with
+// ErrInvalidCredentials.
+func checkPassword(passwordHash, password string) error {
+	ph, err := PasswordHashParse(passwordHash)
 	if err != nil {
+		return err
+	}
+	if err := verifyPassword(ph, password); err != nil {
+		if errors.Is(err, ErrPasswordHashMismatch) {
+			return ErrInvalidCredentials
+		}
+		return err
+	}
+	return nil
+}
+
+// Login verifies the password for a user. A wrong password fails with
+// ErrInvalidCredentials. Users who aren't active are rejected as CheckStatus
+// does, and users with MFA enabled with ErrMFARequired, as they must log in
+// with LoginMFA. The user's login metadata is updated: a wrong password
+// increments FailedLoginCount, and a successful login resets it and sets
+// LastLoginAt and LastLoginIP. It uses the client the user was loaded with.
+func Login(ctx context.Context, u *ent.User, password string, opts ...LoginOption) (*ent.User, error) {
+	return login(ctx, u.Client(), entStoreUser(u), password, opts)
+}
+
+// login logs in a user of an EntStore on the client as Service.Login does,
+// rejecting users with MFA enabled.
+func login(ctx context.Context, client *ent.Client, u *User, password string, opts []LoginOption) (*ent.User, error) {
+	s := entService(ctx, client)
+	ctx = s.context(ctx)
+	o := newLoginOptions(opts)
+	if err := s.verifyLogin(ctx, u, password, o); err != nil {
 		return nil, err
 	}
-	if err := ph.Verify(password); err != nil {
+	enabled, err := mfaEnabled(ctx, client, u.ID)
+	if err != nil {
 		return nil, err
 	}
-	return u, nil
+	if enabled {
+		s.emit(ctx, EventLoginFailed, u.ID, ErrMFARequired.Error())
+		return nil, ErrMFARequired
+	}
+	lu, err := s.recordLogin(ctx, u.ID, o)
+	return toEntUser(ctx, client, lu, err)
+}
+
+// recordEntLogin records a successful login for an ent user as Login does,
+// for the login functions that don't take a password. Of the options, only
+// WithLockout and WithIP apply. It returns the updated user.
+func recordEntLogin(ctx context.Context, client *ent.Client, u *ent.User, opts []LoginOption) (*ent.User, error) {
+	s := entService(ctx, client)
+	lu, err := s.recordLogin(s.context(ctx), strconv.Itoa(u.ID), newLoginOptions(opts))
+	return toEntUser(ctx, client, lu, err)
 }
 
 // AddRole adds a role to a user.
@@ -90,18 +238,21 @@ func RemoveRole(ctx context.Context, client *ent.Client, u *ent.User, role *ent.
 	return u, nil
 }
 
-// CheckPermission checks if a user has all the listed permissions.
+// CheckPermission checks if a user has all the listed permissions, through
+// its roles or the roles they inherit. Granted wildcard permissions satisfy
+// the permissions they match, as PermissionMatches does. A deleted user has
+// no permissions.
 func CheckPermission(ctx context.Context, client *ent.Client, u *ent.User, p ...string) (bool, error) {
-	count, err := u.QueryRoles().QueryPermissions().
-		Where(permission.NameIn(p...)).
-		Count(ctx)
-	if err != nil {
-		return false, err
+	ok, err := entService(ctx, client).CheckPermission(ctx, entStoreUser(u), p...)
+	if errors.Is(err, ErrNotFound) {
+		return false, nil
 	}
-	return count == len(p), nil
+	return ok, err
 }
 
-// Delete a user.
+// Delete a user. The user is soft-deleted: it is hidden from queries, and can
+// be restored with Restore until purged by PurgeDeletedUsers. Its name and
+// email address can't be reused until then.
 func Delete(ctx context.Context, client *ent.Client, u *ent.User) error {
 	return client.User.DeleteOne(u).Exec(ctx)
 }
@@ -155,7 +155,7 @@ func Test_that_LoginByName_fails_with_unknown_name(t *testing.T) {
 	_, err := Create(ctx, client, "user1", USER1_TEST_EMAIL, "password")
 	require.NoError(t, err)
 	_, err = LoginByName(ctx, client, "user2", "password")
-	require.Error(t, err)
+	require.ErrorIs(t, err, ErrInvalidCredentials)
 }
 
 func Test_that_LoginByName_fails_with_wrong_password(t *testing.T) {
@@ -164,7 +164,7 @@ func Test_that_LoginByName_fails_with_wrong_password(t *testing.T) {
 	_, err := Create(ctx, client, "user1", USER1_TEST_EMAIL, "password")
 	require.NoError(t, err)
 	_, err = LoginByName(ctx, client, "user1", "wrong password")
-	require.Error(t, err)
+	require.ErrorIs(t, err, ErrInvalidCredentials)
 }
 
 func Test_that_LoginByEmail_works(t *testing.T) {
@@ -185,7 +185,7 @@ func Test_that_LoginByEmail_fails_with_unknown_email(t *testing.T) {
 	_, err := Create(ctx, client, "user1", USER1_TEST_EMAIL, "password")
 	require.NoError(t, err)
 	_, err = LoginByEmail(ctx, client, USER2_TEST_EMAIL, "password")
-	require.Error(t, err)
+	require.ErrorIs(t, err, ErrInvalidCredentials)
 }
 
 func Test_that_LoginByEmail_fails_with_wrong_password(t *testing.T) {
@@ -194,7 +194,7 @@ func Test_that_LoginByEmail_fails_with_wrong_password(t *testing.T) {
 	_, err := Create(ctx, client, "user1", USER1_TEST_EMAIL, "password")
 	require.NoError(t, err)
 	_, err = LoginByEmail(ctx, client, USER1_TEST_EMAIL, "wrong password")
-	require.Error(t, err)
+	require.ErrorIs(t, err, ErrInvalidCredentials)
 }
 
 func Test_that_Login_works(t *testing.T) {
@@ -219,7 +219,98 @@ func Test_that_Login_fails_with_wrong_password(t *testing.T) {
 	u, err := FindByName(ctx, client, "user1")
 	require.NoError(t, err)
 	_, err = Login(ctx, u, "wrong password")
+	require.ErrorIs(t, err, ErrInvalidCredentials)
+}
+
+func Test_that_Login_maintains_login_metadata(t *testing.T) {
+	client := setupAndMigrate(t)
+	ctx := context.Background()
+	u, err := Create(ctx, client, "user1", USER1_TEST_EMAIL, "password")
+	require.NoError(t, err)
+	require.Nil(t, u.LastLoginAt)
+	require.NotNil(t, u.PasswordChangedAt)
+	_, err = Login(ctx, u, "wrong password")
+	require.Error(t, err)
+	_, err = LoginByName(ctx, client, "user1", "wrong password")
 	require.Error(t, err)
+	u, err = FindByName(ctx, client, "user1")
+	require.NoError(t, err)
+	require.Equal(t, 2, u.FailedLoginCount)
+	u, err = LoginByName(ctx, client, "user1", "password", WithIP("192.0.2.1"))
+	require.NoError(t, err)
+	require.NotNil(t, u.LastLoginAt)
+	require.WithinDuration(t, time.Now(), *u.LastLoginAt, time.Minute)
+	require.Equal(t, "192.0.2.1", u.LastLoginIP)
+	require.Zero(t, u.FailedLoginCount)
+}
+
+func Test_that_users_roles_and_permissions_have_timestamps(t *testing.T) {
+	client := setupAndMigrate(t)
+	now := time.Date(2024, 1, 2, 3, 4, 5, 0, time.UTC)
+	clock := NewFakeClock(now)
+	ctx := ContextWithClock(context.Background(), clock)
+	u, err := Create(ctx, client, "user1", USER1_TEST_EMAIL, "password")
+	require.NoError(t, err)
+	require.True(t, now.Equal(u.CreatedAt))
+	require.True(t, now.Equal(u.UpdatedAt))
+	r, err := client.Role.Create().SetName("role1").Save(ctx)
+	require.NoError(t, err)
+	require.True(t, now.Equal(r.CreatedAt))
+	p, err := client.Permission.Create().SetName("perm1").Save(ctx)
+	require.NoError(t, err)
+	require.True(t, now.Equal(p.CreatedAt))
+	clock.Advance(time.Minute)
+	u2, err := u.Update().SetName("user2").Save(ctx)
+	require.NoError(t, err)
+	require.True(t, now.Add(time.Minute).Equal(u2.UpdatedAt))
+	require.True(t, u.CreatedAt.Equal(u2.CreatedAt))
+}
+
+func Test_that_login_failures_are_indistinguishable(t *testing.T) {
+	client := setupAndMigrate(t)
+	ctx := context.Background()
+	_, err := Create(ctx, client, "user1", USER1_TEST_EMAIL, "password")
+	require.NoError(t, err)
+	_, err = FederatedLogin(ctx, client, &FederatedClaims{
+		Provider: "https://idp.example.com",
+		Subject:  "subject2",
+		Email:    USER2_TEST_EMAIL,
+		Name:     "user2",
+	}, &FederationOptions{Provision: true})
+	require.NoError(t, err)
+	attempts := map[string]func() error{
+		"unknown name": func() error {
+			_, err := LoginByName(ctx, client, "nobody", "password")
+			return err
+		},
+		"wrong password by name": func() error {
+			_, err := LoginByName(ctx, client, "user1", "wrong password")
+			return err
+		},
+		"no password by name": func() error {
+			_, err := LoginByName(ctx, client, "user2", "password")
+			return err
+		},
+		"unknown email": func() error {
+			_, err := LoginByEmail(ctx, client, "nobody@example.com", "password")
+			return err
+		},
+		"wrong password by email": func() error {
+			_, err := LoginByEmail(ctx, client, USER1_TEST_EMAIL, "wrong password")
+			return err
+		},
+		"no password by email": func() error {
+			_, err := LoginByEmail(ctx, client, USER2_TEST_EMAIL, "password")
+			return err
+		},
+	}
+	for name, attempt := range attempts {
+		t.Run(name, func(t *testing.T) {
+			err := attempt()
+			require.Equal(t, ErrInvalidCredentials, err)
+			require.False(t, ent.IsNotFound(err))
+		})
+	}
 }
 
 func Test_that_Login_fails_with_corrupted_password_hash(t *testing.T) {
@@ -327,6 +418,25 @@ func Test_that_CheckPermission_works(t *testing.T) {
 	require.True(t, ok)
 }
 
+func Test_that_CheckPermission_denies_deleted_users(t *testing.T) {
+	client := setupAndMigrate(t)
+	ctx := context.Background()
+	u, err := Create(ctx, client, "user1", USER1_TEST_EMAIL, "password")
+	require.NoError(t, err)
+	r, err := CreateRole(ctx, client, "test_role", "Test Role")
+	require.NoError(t, err)
+	p, err := CreatePermission(ctx, client, "test_permission", "Test Permission")
+	require.NoError(t, err)
+	_, err = AddRolePermission(ctx, client, r, p)
+	require.NoError(t, err)
+	u, err = AddRole(ctx, client, u, r)
+	require.NoError(t, err)
+	require.NoError(t, Delete(ctx, client, u))
+	ok, err := CheckPermission(ctx, client, u, "test_permission")
+	require.NoError(t, err)
+	require.False(t, ok)
+}
+
 func Test_that_CheckPermission_fails_with_missing_permission(t *testing.T) {
 	client := setupAndMigrate(t)
 	ctx := context.Background()
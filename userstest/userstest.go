@@ -0,0 +1,793 @@
+// Package userstest checks that implementations of users.Store behave as the
+// package expects. Run RunStoreConformance from a test of your Store:
+//
+//	func TestStore(t *testing.T) {
+//		userstest.RunStoreConformance(t, func(t *testing.T) users.Store {
+//			return newTestStore(t)
+//		})
+//	}
+package userstest
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/smxlong/users"
+)
+
+const (
+	email1 = "user1@example.com"
+	email2 = "user2@example.com"
+)
+
+// Factory returns a new, empty Store for a test. It may use t to clean up
+// the Store when the test ends.
+type Factory func(t *testing.T) users.Store
+
+// RunStoreConformance checks that Stores made by the factory behave as
+// users.Store documents, both directly and when used by a users.Service. Each
+// check runs as a subtest with its own Store.
+func RunStoreConformance(t *testing.T, factory Factory) {
+	t.Run("Store", func(t *testing.T) {
+		runStoreTests(t, factory)
+	})
+	t.Run("Service", func(t *testing.T) {
+		runServiceTests(t, factory)
+	})
+}
+
+// runStoreTests checks the Store methods.
+func runStoreTests(t *testing.T, newStore Factory) {
+	ctx := context.Background()
+	newUser := func(t *testing.T, s users.Store, name, email string) *users.User {
+		u, err := s.CreateUser(ctx, &users.User{Name: name, Email: email, PasswordHash: "hash"})
+		require.NoError(t, err)
+		return u
+	}
+	newPermissions := func(t *testing.T, s users.Store, names ...string) {
+		for _, name := range names {
+			_, err := s.CreatePermission(ctx, &users.Permission{Name: name, Description: name + " description"})
+			require.NoError(t, err)
+		}
+	}
+
+	t.Run("CreateUser assigns an ID", func(t *testing.T) {
+		s := newStore(t)
+		u := newUser(t, s, "user1", email1)
+		require.NotEmpty(t, u.ID)
+		require.Equal(t, "user1", u.Name)
+		require.Equal(t, email1, u.Email)
+		require.Equal(t, "hash", u.PasswordHash)
+		require.Equal(t, "active", u.Status)
+		u2 := newUser(t, s, "user2", email2)
+		require.NotEqual(t, u.ID, u2.ID)
+	})
+
+	t.Run("CreateUser rejects duplicate names and emails", func(t *testing.T) {
+		s := newStore(t)
+		newUser(t, s, "user1", email1)
+		_, err := s.CreateUser(ctx, &users.User{Name: "user1", Email: email2, PasswordHash: "hash"})
+		require.ErrorIs(t, err, users.ErrAlreadyExists)
+		_, err = s.CreateUser(ctx, &users.User{Name: "user2", Email: email1, PasswordHash: "hash"})
+		require.ErrorIs(t, err, users.ErrAlreadyExists)
+	})
+
+	t.Run("FindUser finds users by ID, name and email", func(t *testing.T) {
+		s := newStore(t)
+		u := newUser(t, s, "user1", email1)
+		found, err := s.FindUserByID(ctx, u.ID)
+		require.NoError(t, err)
+		require.Equal(t, u, found)
+		found, err = s.FindUserByName(ctx, "user1")
+		require.NoError(t, err)
+		require.Equal(t, u, found)
+		found, err = s.FindUserByEmail(ctx, email1)
+		require.NoError(t, err)
+		require.Equal(t, u, found)
+	})
+
+	t.Run("FindUser fails for unknown users", func(t *testing.T) {
+		s := newStore(t)
+		_, err := s.FindUserByID(ctx, "12345")
+		require.ErrorIs(t, err, users.ErrNotFound)
+		_, err = s.FindUserByID(ctx, "not an id")
+		require.ErrorIs(t, err, users.ErrNotFound)
+		_, err = s.FindUserByName(ctx, "user1")
+		require.ErrorIs(t, err, users.ErrNotFound)
+		_, err = s.FindUserByEmail(ctx, email1)
+		require.ErrorIs(t, err, users.ErrNotFound)
+	})
+
+	t.Run("UpdateUser saves changes", func(t *testing.T) {
+		s := newStore(t)
+		u := newUser(t, s, "user1", email1)
+		u.Name = "renamed"
+		u.PasswordHash = "new hash"
+		u.Status = "disabled"
+		_, err := s.UpdateUser(ctx, u)
+		require.NoError(t, err)
+		found, err := s.FindUserByID(ctx, u.ID)
+		require.NoError(t, err)
+		require.Equal(t, "renamed", found.Name)
+		require.Equal(t, "new hash", found.PasswordHash)
+		require.Equal(t, "disabled", found.Status)
+	})
+
+	t.Run("UpdateUser rejects duplicate names and unknown users", func(t *testing.T) {
+		s := newStore(t)
+		newUser(t, s, "user1", email1)
+		u := newUser(t, s, "user2", email2)
+		u.Name = "user1"
+		_, err := s.UpdateUser(ctx, u)
+		require.ErrorIs(t, err, users.ErrAlreadyExists)
+		_, err = s.UpdateUser(ctx, &users.User{ID: "12345", Name: "user3", Email: "user3@example.com"})
+		require.ErrorIs(t, err, users.ErrNotFound)
+	})
+
+	t.Run("RecordLogin and RecordLoginFailure update the login metadata", func(t *testing.T) {
+		s := newStore(t)
+		u := newUser(t, s, "user1", email1)
+		require.NoError(t, s.RecordLoginFailure(ctx, u.ID))
+		require.NoError(t, s.RecordLoginFailure(ctx, u.ID))
+		found, err := s.FindUserByID(ctx, u.ID)
+		require.NoError(t, err)
+		require.Equal(t, 2, found.FailedLoginCount)
+		// UpdateUser keeps the login metadata
+		found.Name = "renamed"
+		found.FailedLoginCount = 0
+		_, err = s.UpdateUser(ctx, found)
+		require.NoError(t, err)
+		found, err = s.FindUserByID(ctx, u.ID)
+		require.NoError(t, err)
+		require.Equal(t, 2, found.FailedLoginCount)
+		at := time.Date(2024, 1, 2, 3, 4, 5, 0, time.UTC)
+		found, err = s.RecordLogin(ctx, u.ID, at, "192.0.2.1")
+		require.NoError(t, err)
+		require.Equal(t, 0, found.FailedLoginCount)
+		require.True(t, at.Equal(*found.LastLoginAt))
+		require.Equal(t, "192.0.2.1", found.LastLoginIP)
+		// an empty IP address keeps the last one
+		found, err = s.RecordLogin(ctx, u.ID, at.Add(time.Hour), "")
+		require.NoError(t, err)
+		require.Equal(t, "192.0.2.1", found.LastLoginIP)
+		require.ErrorIs(t, s.RecordLoginFailure(ctx, "12345"), users.ErrNotFound)
+		_, err = s.RecordLogin(ctx, "12345", at, "")
+		require.ErrorIs(t, err, users.ErrNotFound)
+	})
+
+	t.Run("DeleteUser deletes users and reserves their names and emails", func(t *testing.T) {
+		s := newStore(t)
+		u := newUser(t, s, "user1", email1)
+		require.NoError(t, s.DeleteUser(ctx, u.ID))
+		_, err := s.FindUserByID(ctx, u.ID)
+		require.ErrorIs(t, err, users.ErrNotFound)
+		_, err = s.FindUserByEmail(ctx, email1)
+		require.ErrorIs(t, err, users.ErrNotFound)
+		_, err = s.FindUserByName(ctx, "user1")
+		require.ErrorIs(t, err, users.ErrNotFound)
+		require.ErrorIs(t, s.DeleteUser(ctx, u.ID), users.ErrNotFound)
+		// the name and email address stay reserved
+		_, err = s.CreateUser(ctx, &users.User{Name: "user1", Email: email2, PasswordHash: "hash"})
+		require.ErrorIs(t, err, users.ErrAlreadyExists)
+		_, err = s.CreateUser(ctx, &users.User{Name: "user2", Email: email1, PasswordHash: "hash"})
+		require.ErrorIs(t, err, users.ErrAlreadyExists)
+	})
+
+	t.Run("user roles can be added and removed", func(t *testing.T) {
+		s := newStore(t)
+		newPermissions(t, s, "read", "write")
+		_, err := s.CreateRole(ctx, &users.Role{Name: "user", Permissions: []string{"read"}})
+		require.NoError(t, err)
+		_, err = s.CreateRole(ctx, &users.Role{Name: "admin", Permissions: []string{"read", "write"}})
+		require.NoError(t, err)
+		u := newUser(t, s, "user1", email1)
+		require.NoError(t, s.AddUserRole(ctx, u.ID, "user"))
+		require.NoError(t, s.AddUserRole(ctx, u.ID, "admin"))
+		// adding a role twice is tolerated
+		require.NoError(t, s.AddUserRole(ctx, u.ID, "admin"))
+		roles, err := s.UserRoles(ctx, u.ID)
+		require.NoError(t, err)
+		require.Equal(t, []string{"admin", "user"}, roles)
+		permissions, err := s.UserPermissions(ctx, u.ID)
+		require.NoError(t, err)
+		require.Equal(t, []string{"read", "write"}, permissions)
+		require.NoError(t, s.RemoveUserRole(ctx, u.ID, "admin"))
+		require.NoError(t, s.RemoveUserRole(ctx, u.ID, "admin"))
+		permissions, err = s.UserPermissions(ctx, u.ID)
+		require.NoError(t, err)
+		require.Equal(t, []string{"read"}, permissions)
+	})
+
+	t.Run("user roles must exist", func(t *testing.T) {
+		s := newStore(t)
+		u := newUser(t, s, "user1", email1)
+		require.ErrorIs(t, s.AddUserRole(ctx, u.ID, "admin"), users.ErrNotFound)
+		_, err := s.CreateRole(ctx, &users.Role{Name: "admin"})
+		require.NoError(t, err)
+		require.ErrorIs(t, s.AddUserRole(ctx, "12345", "admin"), users.ErrNotFound)
+		_, err = s.UserRoles(ctx, "12345")
+		require.ErrorIs(t, err, users.ErrNotFound)
+	})
+
+	t.Run("CreateRole rejects duplicates and unknown permissions", func(t *testing.T) {
+		s := newStore(t)
+		_, err := s.CreateRole(ctx, &users.Role{Name: "admin"})
+		require.NoError(t, err)
+		_, err = s.CreateRole(ctx, &users.Role{Name: "admin"})
+		require.ErrorIs(t, err, users.ErrAlreadyExists)
+		_, err = s.CreateRole(ctx, &users.Role{Name: "user", Permissions: []string{"read"}})
+		require.ErrorIs(t, err, users.ErrNotFound)
+		_, err = s.FindRole(ctx, "user")
+		require.ErrorIs(t, err, users.ErrNotFound)
+	})
+
+	t.Run("roles can be found, listed and updated", func(t *testing.T) {
+		s := newStore(t)
+		newPermissions(t, s, "read", "write")
+		_, err := s.CreateRole(ctx, &users.Role{Name: "user", Description: "User", Permissions: []string{"read"}})
+		require.NoError(t, err)
+		_, err = s.CreateRole(ctx, &users.Role{Name: "admin", Description: "Admin", Permissions: []string{"write", "read"}})
+		require.NoError(t, err)
+		r, err := s.FindRole(ctx, "admin")
+		require.NoError(t, err)
+		require.Equal(t, &users.Role{Name: "admin", Description: "Admin", Permissions: []string{"read", "write"}, Inherits: []string{}}, r)
+		require.NoError(t, s.UpdateRoleDescription(ctx, "admin", "Administrator"))
+		roles, err := s.ListRoles(ctx)
+		require.NoError(t, err)
+		require.Equal(t, []*users.Role{
+			{Name: "admin", Description: "Administrator", Permissions: []string{"read", "write"}, Inherits: []string{}},
+			{Name: "user", Description: "User", Permissions: []string{"read"}, Inherits: []string{}},
+		}, roles)
+		require.ErrorIs(t, s.UpdateRoleDescription(ctx, "nobody", ""), users.ErrNotFound)
+	})
+
+	t.Run("role permissions can be added, removed and set", func(t *testing.T) {
+		s := newStore(t)
+		newPermissions(t, s, "read", "write", "delete")
+		_, err := s.CreateRole(ctx, &users.Role{Name: "admin"})
+		require.NoError(t, err)
+		require.NoError(t, s.AddRolePermission(ctx, "admin", "read"))
+		// adding a permission twice is tolerated
+		require.NoError(t, s.AddRolePermission(ctx, "admin", "read"))
+		require.NoError(t, s.AddRolePermission(ctx, "admin", "write"))
+		r, err := s.FindRole(ctx, "admin")
+		require.NoError(t, err)
+		require.Equal(t, []string{"read", "write"}, r.Permissions)
+		require.NoError(t, s.RemoveRolePermission(ctx, "admin", "read"))
+		require.NoError(t, s.RemoveRolePermission(ctx, "admin", "read"))
+		r, err = s.FindRole(ctx, "admin")
+		require.NoError(t, err)
+		require.Equal(t, []string{"write"}, r.Permissions)
+		require.NoError(t, s.SetRolePermissions(ctx, "admin", []string{"delete", "read"}))
+		r, err = s.FindRole(ctx, "admin")
+		require.NoError(t, err)
+		require.Equal(t, []string{"delete", "read"}, r.Permissions)
+		require.ErrorIs(t, s.AddRolePermission(ctx, "admin", "nothing"), users.ErrNotFound)
+		require.ErrorIs(t, s.SetRolePermissions(ctx, "admin", []string{"nothing"}), users.ErrNotFound)
+		require.ErrorIs(t, s.AddRolePermission(ctx, "nobody", "read"), users.ErrNotFound)
+	})
+
+	t.Run("DeleteRole removes the role from users", func(t *testing.T) {
+		s := newStore(t)
+		newPermissions(t, s, "read")
+		_, err := s.CreateRole(ctx, &users.Role{Name: "user", Permissions: []string{"read"}})
+		require.NoError(t, err)
+		u := newUser(t, s, "user1", email1)
+		require.NoError(t, s.AddUserRole(ctx, u.ID, "user"))
+		require.NoError(t, s.DeleteRole(ctx, "user"))
+		_, err = s.FindRole(ctx, "user")
+		require.ErrorIs(t, err, users.ErrNotFound)
+		roles, err := s.UserRoles(ctx, u.ID)
+		require.NoError(t, err)
+		require.Empty(t, roles)
+		permissions, err := s.UserPermissions(ctx, u.ID)
+		require.NoError(t, err)
+		require.Empty(t, permissions)
+		require.ErrorIs(t, s.DeleteRole(ctx, "user"), users.ErrNotFound)
+	})
+
+	t.Run("roles inherit the permissions of their parents", func(t *testing.T) {
+		s := newStore(t)
+		newPermissions(t, s, "read", "write", "delete")
+		_, err := s.CreateRole(ctx, &users.Role{Name: "viewer", Permissions: []string{"read"}})
+		require.NoError(t, err)
+		_, err = s.CreateRole(ctx, &users.Role{Name: "editor", Permissions: []string{"write"}, Inherits: []string{"viewer"}})
+		require.NoError(t, err)
+		_, err = s.CreateRole(ctx, &users.Role{Name: "admin", Permissions: []string{"delete"}})
+		require.NoError(t, err)
+		require.NoError(t, s.SetRoleInherits(ctx, "admin", []string{"editor"}))
+		r, err := s.FindRole(ctx, "admin")
+		require.NoError(t, err)
+		require.Equal(t, []string{"editor"}, r.Inherits)
+		u := newUser(t, s, "user1", email1)
+		require.NoError(t, s.AddUserRole(ctx, u.ID, "admin"))
+		permissions, err := s.UserPermissions(ctx, u.ID)
+		require.NoError(t, err)
+		require.Equal(t, []string{"delete", "read", "write"}, permissions)
+		// deleting an inherited role stops it being inherited
+		require.NoError(t, s.DeleteRole(ctx, "viewer"))
+		r, err = s.FindRole(ctx, "editor")
+		require.NoError(t, err)
+		require.Empty(t, r.Inherits)
+		permissions, err = s.UserPermissions(ctx, u.ID)
+		require.NoError(t, err)
+		require.Equal(t, []string{"delete", "write"}, permissions)
+		_, err = s.CreateRole(ctx, &users.Role{Name: "user", Inherits: []string{"nobody"}})
+		require.ErrorIs(t, err, users.ErrNotFound)
+	})
+
+	t.Run("SetRoleInherits rejects cycles", func(t *testing.T) {
+		s := newStore(t)
+		_, err := s.CreateRole(ctx, &users.Role{Name: "a"})
+		require.NoError(t, err)
+		_, err = s.CreateRole(ctx, &users.Role{Name: "b", Inherits: []string{"a"}})
+		require.NoError(t, err)
+		_, err = s.CreateRole(ctx, &users.Role{Name: "c", Inherits: []string{"b"}})
+		require.NoError(t, err)
+		require.ErrorIs(t, s.SetRoleInherits(ctx, "a", []string{"c"}), users.ErrRoleCycle)
+		require.ErrorIs(t, s.SetRoleInherits(ctx, "a", []string{"a"}), users.ErrRoleCycle)
+		require.ErrorIs(t, s.SetRoleInherits(ctx, "a", []string{"nobody"}), users.ErrNotFound)
+		// replacing a role's parents can't make a cycle through them
+		require.NoError(t, s.SetRoleInherits(ctx, "b", []string{}))
+		require.NoError(t, s.SetRoleInherits(ctx, "a", []string{"c"}))
+		r, err := s.FindRole(ctx, "a")
+		require.NoError(t, err)
+		require.Equal(t, []string{"c"}, r.Inherits)
+	})
+
+	t.Run("CreatePermission rejects duplicates", func(t *testing.T) {
+		s := newStore(t)
+		newPermissions(t, s, "read")
+		_, err := s.CreatePermission(ctx, &users.Permission{Name: "read"})
+		require.ErrorIs(t, err, users.ErrAlreadyExists)
+	})
+
+	t.Run("CreatePermission rejects invalid names", func(t *testing.T) {
+		s := newStore(t)
+		_, err := s.CreatePermission(ctx, &users.Permission{Name: "reports:"})
+		require.ErrorIs(t, err, users.ErrPermissionNameInvalid)
+		_, err = s.CreatePermission(ctx, &users.Permission{Name: "reports:*"})
+		require.NoError(t, err)
+	})
+
+	t.Run("permissions can be found, listed and updated", func(t *testing.T) {
+		s := newStore(t)
+		newPermissions(t, s, "write", "read")
+		p, err := s.FindPermission(ctx, "read")
+		require.NoError(t, err)
+		require.Equal(t, &users.Permission{Name: "read", Description: "read description"}, p)
+		require.NoError(t, s.UpdatePermissionDescription(ctx, "read", "Read"))
+		permissions, err := s.ListPermissions(ctx)
+		require.NoError(t, err)
+		require.Equal(t, []*users.Permission{
+			{Name: "read", Description: "Read"},
+			{Name: "write", Description: "write description"},
+		}, permissions)
+		_, err = s.FindPermission(ctx, "delete")
+		require.ErrorIs(t, err, users.ErrNotFound)
+		require.ErrorIs(t, s.UpdatePermissionDescription(ctx, "delete", ""), users.ErrNotFound)
+	})
+
+	t.Run("DeletePermission removes the permission from roles", func(t *testing.T) {
+		s := newStore(t)
+		newPermissions(t, s, "read", "write")
+		_, err := s.CreateRole(ctx, &users.Role{Name: "admin", Permissions: []string{"read", "write"}})
+		require.NoError(t, err)
+		require.NoError(t, s.DeletePermission(ctx, "write"))
+		r, err := s.FindRole(ctx, "admin")
+		require.NoError(t, err)
+		require.Equal(t, []string{"read"}, r.Permissions)
+		require.ErrorIs(t, s.DeletePermission(ctx, "write"), users.ErrNotFound)
+	})
+
+	t.Run("RevokeSessions changes the token generation", func(t *testing.T) {
+		s := newStore(t)
+		u := newUser(t, s, "user1", email1)
+		before, err := s.TokenGeneration(ctx, u.ID)
+		require.NoError(t, err)
+		require.NoError(t, s.RevokeSessions(ctx, u.ID))
+		after, err := s.TokenGeneration(ctx, u.ID)
+		require.NoError(t, err)
+		require.NotEqual(t, before, after)
+		_, err = s.TokenGeneration(ctx, "12345")
+		require.ErrorIs(t, err, users.ErrNotFound)
+		require.ErrorIs(t, s.RevokeSessions(ctx, "12345"), users.ErrNotFound)
+	})
+	t.Run("RemoveUserRole tolerates unknown roles", func(t *testing.T) {
+		s := newStore(t)
+		u := newUser(t, s, "user1", email1)
+		require.NoError(t, s.RemoveUserRole(ctx, u.ID, "admin"))
+		require.ErrorIs(t, s.RemoveUserRole(ctx, "12345", "admin"), users.ErrNotFound)
+	})
+
+	t.Run("roles can be given on resources", func(t *testing.T) {
+		s := newStore(t)
+		u := newUser(t, s, "user1", email1)
+		for _, name := range []string{"editor", "viewer"} {
+			_, err := s.CreateRole(ctx, &users.Role{Name: name})
+			require.NoError(t, err)
+		}
+		project := users.Resource{Type: "project", ID: "42"}
+		require.NoError(t, s.AddUserRoleOn(ctx, u.ID, "viewer", project))
+		require.NoError(t, s.AddUserRoleOn(ctx, u.ID, "editor", project))
+		require.NoError(t, s.AddUserRoleOn(ctx, u.ID, "editor", project))
+		require.NoError(t, s.AddUserRoleOn(ctx, u.ID, "editor", users.Resource{Type: "project", ID: "43"}))
+		roles, err := s.UserRolesOn(ctx, u.ID, project)
+		require.NoError(t, err)
+		require.Equal(t, []string{"editor", "viewer"}, roles)
+		roles, err = s.UserRoles(ctx, u.ID)
+		require.NoError(t, err)
+		require.Empty(t, roles)
+		require.ErrorIs(t, s.AddUserRoleOn(ctx, u.ID, "nobody", project), users.ErrNotFound)
+		require.ErrorIs(t, s.AddUserRoleOn(ctx, "12345", "editor", project), users.ErrNotFound)
+		require.ErrorIs(t, s.AddUserRoleOn(ctx, u.ID, "editor", users.Resource{Type: "project"}), users.ErrResourceInvalid)
+		require.NoError(t, s.RemoveUserRoleOn(ctx, u.ID, "viewer", project))
+		require.NoError(t, s.RemoveUserRoleOn(ctx, u.ID, "nobody", project))
+		require.NoError(t, s.DeleteRole(ctx, "editor"))
+		roles, err = s.UserRolesOn(ctx, u.ID, project)
+		require.NoError(t, err)
+		require.Empty(t, roles)
+	})
+
+	t.Run("RemoveRolePermission tolerates unknown permissions", func(t *testing.T) {
+		s := newStore(t)
+		_, err := s.CreateRole(ctx, &users.Role{Name: "admin"})
+		require.NoError(t, err)
+		require.NoError(t, s.RemoveRolePermission(ctx, "admin", "read"))
+		require.ErrorIs(t, s.RemoveRolePermission(ctx, "nobody", "read"), users.ErrNotFound)
+	})
+}
+
+// runServiceTests checks the Service operations using the Store.
+func runServiceTests(t *testing.T, newStore Factory) {
+	ctx := context.Background()
+	tokenOptions := &users.TokenOptions{Secret: "secret"}
+	newService := func(t *testing.T) *users.Service {
+		return users.NewService(
+			users.WithStore(newStore(t)),
+			users.WithTokenOptions(tokenOptions),
+		)
+	}
+	testRoles := func() users.Roles {
+		return users.Roles{
+			"admin": {
+				Description: "Administrator",
+				Permissions: []*users.Permission{
+					{Name: "read", Description: "Read"},
+					{Name: "write", Description: "Write"},
+				},
+			},
+			"user": {
+				Description: "User",
+				Permissions: []*users.Permission{
+					{Name: "read", Description: "Read"},
+				},
+			},
+		}
+	}
+
+	t.Run("Create rejects duplicate names and emails", func(t *testing.T) {
+		s := newService(t)
+		_, err := s.Create(ctx, "user1", email1, "password")
+		require.NoError(t, err)
+		_, err = s.Create(ctx, "user1", email2, "password")
+		require.ErrorIs(t, err, users.ErrAlreadyExists)
+		_, err = s.Create(ctx, "user2", email1, "password")
+		require.ErrorIs(t, err, users.ErrAlreadyExists)
+	})
+
+	t.Run("Create rejects invalid emails and long passwords", func(t *testing.T) {
+		s := newService(t)
+		_, err := s.Create(ctx, "user1", "not an email", "password")
+		require.ErrorIs(t, err, users.ErrEmailAddressInvalid)
+		long := make([]byte, 73)
+		for i := range long {
+			long[i] = 'a'
+		}
+		_, err = s.Create(ctx, "user1", email1, string(long))
+		require.Error(t, err)
+	})
+
+	t.Run("Login checks passwords", func(t *testing.T) {
+		s := newService(t)
+		u, err := s.Create(ctx, "user1", email1, "password")
+		require.NoError(t, err)
+		found, err := s.LoginByName(ctx, "user1", "password")
+		require.NoError(t, err)
+		require.Equal(t, u.ID, found.ID)
+		found, err = s.LoginByEmail(ctx, email1, "password")
+		require.NoError(t, err)
+		require.Equal(t, u.ID, found.ID)
+		_, err = s.LoginByName(ctx, "user1", "wrong password")
+		require.ErrorIs(t, err, users.ErrInvalidCredentials)
+		_, err = s.LoginByEmail(ctx, email1, "wrong password")
+		require.ErrorIs(t, err, users.ErrInvalidCredentials)
+	})
+
+	t.Run("Login fails identically for unknown users", func(t *testing.T) {
+		s := newService(t)
+		_, err := s.LoginByName(ctx, "user1", "password")
+		require.Equal(t, users.ErrInvalidCredentials, err)
+		_, err = s.LoginByEmail(ctx, email1, "password")
+		require.Equal(t, users.ErrInvalidCredentials, err)
+	})
+
+	t.Run("Login rejects disabled users", func(t *testing.T) {
+		s := newService(t)
+		u, err := s.Create(ctx, "user1", email1, "password")
+		require.NoError(t, err)
+		u.Status = "disabled"
+		_, err = s.Store().UpdateUser(ctx, u)
+		require.NoError(t, err)
+		_, err = s.LoginByName(ctx, "user1", "password")
+		require.ErrorIs(t, err, users.ErrAccountDisabled)
+	})
+
+	t.Run("Login records login metadata", func(t *testing.T) {
+		s := newService(t)
+		_, err := s.Create(ctx, "user1", email1, "password")
+		require.NoError(t, err)
+		_, err = s.LoginByName(ctx, "user1", "wrong password")
+		require.ErrorIs(t, err, users.ErrInvalidCredentials)
+		u, err := s.FindByName(ctx, "user1")
+		require.NoError(t, err)
+		require.Equal(t, 1, u.FailedLoginCount)
+		u, err = s.LoginByName(ctx, "user1", "password", users.WithIP("192.0.2.1"))
+		require.NoError(t, err)
+		require.Equal(t, 0, u.FailedLoginCount)
+		require.NotNil(t, u.LastLoginAt)
+		require.Equal(t, "192.0.2.1", u.LastLoginIP)
+	})
+
+	t.Run("Login applies the login options", func(t *testing.T) {
+		s := newService(t)
+		u, err := s.Create(ctx, "user1", email1, "password")
+		require.NoError(t, err)
+		_, err = s.Login(ctx, u, "password", users.RequireVerifiedEmail())
+		require.ErrorIs(t, err, users.ErrEmailNotVerified)
+		now := time.Now()
+		u.EmailVerifiedAt = &now
+		u, err = s.Store().UpdateUser(ctx, u)
+		require.NoError(t, err)
+		_, err = s.Login(ctx, u, "password", users.RequireVerifiedEmail())
+		require.NoError(t, err)
+		// a locked out user is rejected as an unknown user is, unless the
+		// password is right
+		policy := &users.LockoutPolicy{Store: &users.MemoryLockoutStore{}, Threshold: 1}
+		_, err = s.LoginByName(ctx, "user1", "wrong password", users.WithLockout(policy, ""))
+		require.ErrorIs(t, err, users.ErrInvalidCredentials)
+		_, err = s.LoginByName(ctx, "user1", "wrong password", users.WithLockout(policy, ""))
+		require.Equal(t, users.ErrInvalidCredentials, err)
+		_, err = s.LoginByName(ctx, "user1", "password", users.WithLockout(policy, ""))
+		require.ErrorIs(t, err, users.ErrAccountLocked)
+		until, err := policy.LockedUntil(ctx, u.ID)
+		require.NoError(t, err)
+		require.False(t, until.IsZero())
+		require.NoError(t, policy.Unlock(ctx, u.ID))
+		_, err = s.LoginByName(ctx, "user1", "password", users.WithLockout(policy, ""))
+		require.NoError(t, err)
+	})
+
+	t.Run("AddRole tolerates duplicates and rejects unknown roles", func(t *testing.T) {
+		s := newService(t)
+		require.NoError(t, s.CreateRolesAndPermissions(ctx, testRoles()))
+		u, err := s.Create(ctx, "user1", email1, "password")
+		require.NoError(t, err)
+		require.NoError(t, s.AddRole(ctx, u, "user"))
+		require.NoError(t, s.AddRole(ctx, u, "user"))
+		require.ErrorIs(t, s.AddRole(ctx, u, "nobody"), users.ErrNotFound)
+		require.NoError(t, s.RemoveRole(ctx, u, "user"))
+		require.NoError(t, s.RemoveRole(ctx, u, "nobody"))
+	})
+
+	t.Run("CheckPermission checks all permissions", func(t *testing.T) {
+		s := newService(t)
+		require.NoError(t, s.CreateRolesAndPermissions(ctx, testRoles()))
+		u, err := s.Create(ctx, "user1", email1, "password")
+		require.NoError(t, err)
+		ok, err := s.CheckPermission(ctx, u, "read")
+		require.NoError(t, err)
+		require.False(t, ok)
+		require.NoError(t, s.AddRole(ctx, u, "user"))
+		ok, err = s.CheckPermission(ctx, u, "read")
+		require.NoError(t, err)
+		require.True(t, ok)
+		ok, err = s.CheckPermission(ctx, u, "read", "write")
+		require.NoError(t, err)
+		require.False(t, ok)
+		require.NoError(t, s.AddRole(ctx, u, "admin"))
+		ok, err = s.CheckPermission(ctx, u, "read", "write")
+		require.NoError(t, err)
+		require.True(t, ok)
+	})
+
+	t.Run("CheckPermission matches wildcard permissions", func(t *testing.T) {
+		s := newService(t)
+		require.NoError(t, s.CreateRolesAndPermissions(ctx, users.Roles{
+			"reporter": &users.RoleWithPermissions{
+				Permissions: []*users.Permission{{Name: "reports:*", Description: "Everything on reports"}},
+			},
+		}))
+		u, err := s.Create(ctx, "user1", email1, "password")
+		require.NoError(t, err)
+		require.NoError(t, s.AddRole(ctx, u, "reporter"))
+		ok, err := s.CheckPermission(ctx, u, "reports:read", "reports:invoices:write")
+		require.NoError(t, err)
+		require.True(t, ok)
+		ok, err = s.CheckPermission(ctx, u, "billing:read")
+		require.NoError(t, err)
+		require.False(t, ok)
+		tok, err := s.NewTokenWithPermissions(ctx, u)
+		require.NoError(t, err)
+		ok, err = s.CheckTokenPermission(ctx, tok, "reports:read")
+		require.NoError(t, err)
+		require.True(t, ok)
+		ok, err = s.CheckTokenPermission(ctx, tok, "billing:read")
+		require.NoError(t, err)
+		require.False(t, ok)
+	})
+
+	t.Run("CheckPermissionOn honours roles on resources and their ancestors", func(t *testing.T) {
+		// Documents belong to projects.
+		s := users.NewService(
+			users.WithStore(newStore(t)),
+			users.WithResourceHierarchy(users.ResourceHierarchyFunc(func(ctx context.Context, r users.Resource) (*users.Resource, error) {
+				if r.Type == "document" {
+					return &users.Resource{Type: "project", ID: "42"}, nil
+				}
+				return nil, nil
+			})),
+		)
+		require.NoError(t, s.CreateRolesAndPermissions(ctx, testRoles()))
+		u, err := s.Create(ctx, "user1", email1, "password")
+		require.NoError(t, err)
+		project := users.Resource{Type: "project", ID: "42"}
+		document := users.Resource{Type: "document", ID: "7"}
+		require.NoError(t, s.AddRoleOn(ctx, u, "admin", project))
+		ok, err := s.CheckPermissionOn(ctx, u, "write", project)
+		require.NoError(t, err)
+		require.True(t, ok)
+		ok, err = s.CheckPermissionOn(ctx, u, "write", document)
+		require.NoError(t, err)
+		require.True(t, ok)
+		ok, err = s.CheckPermissionOn(ctx, u, "write", users.Resource{Type: "project", ID: "43"})
+		require.NoError(t, err)
+		require.False(t, ok)
+		ok, err = s.CheckPermission(ctx, u, "write")
+		require.NoError(t, err)
+		require.False(t, ok)
+		// roles given on every resource apply to each of them
+		require.NoError(t, s.AddRole(ctx, u, "user"))
+		roles, err := s.RolesOn(ctx, u, document)
+		require.NoError(t, err)
+		require.Equal(t, []string{"admin", "user"}, roles)
+		ok, err = s.CheckPermissionOn(ctx, u, "read", users.Resource{Type: "project", ID: "43"})
+		require.NoError(t, err)
+		require.True(t, ok)
+		require.NoError(t, s.RemoveRoleOn(ctx, u, "admin", project))
+		ok, err = s.CheckPermissionOn(ctx, u, "write", document)
+		require.NoError(t, err)
+		require.False(t, ok)
+	})
+
+	t.Run("CreateRolesAndPermissions creates and updates", func(t *testing.T) {
+		s := newService(t)
+		require.NoError(t, s.CreateRolesAndPermissions(ctx, testRoles()))
+		roles := testRoles()
+		roles["user"].Description = "Ordinary user"
+		roles["user"].Permissions = nil
+		require.NoError(t, s.CreateRolesAndPermissions(ctx, roles))
+		r, err := s.Store().FindRole(ctx, "user")
+		require.NoError(t, err)
+		require.Equal(t, &users.Role{Name: "user", Description: "Ordinary user", Permissions: []string{}, Inherits: []string{}}, r)
+		r, err = s.Store().FindRole(ctx, "admin")
+		require.NoError(t, err)
+		require.Equal(t, []string{"read", "write"}, r.Permissions)
+	})
+
+	t.Run("CreateRolesAndPermissions sets inherited roles", func(t *testing.T) {
+		s := newService(t)
+		roles := testRoles()
+		roles["admin"].Permissions = roles["admin"].Permissions[1:]
+		roles["admin"].Inherits = []string{"user"}
+		require.NoError(t, s.CreateRolesAndPermissions(ctx, roles))
+		u, err := s.Create(ctx, "user1", email1, "password")
+		require.NoError(t, err)
+		require.NoError(t, s.AddRole(ctx, u, "admin"))
+		ok, err := s.CheckPermission(ctx, u, "read", "write")
+		require.NoError(t, err)
+		require.True(t, ok)
+		effective, err := s.EffectivePermissions(ctx, u)
+		require.NoError(t, err)
+		require.Equal(t, []*users.EffectivePermission{
+			{Permission: "read", Path: []string{"admin", "user"}},
+			{Permission: "write", Path: []string{"admin"}},
+		}, effective)
+		roles["user"].Inherits = []string{"admin"}
+		require.ErrorIs(t, s.CreateRolesAndPermissions(ctx, roles), users.ErrRoleCycle)
+	})
+
+	t.Run("CreateRolesAndPermissions rejects mismatched permission descriptions", func(t *testing.T) {
+		s := newService(t)
+		require.NoError(t, s.CreateRolesAndPermissions(ctx, testRoles()))
+		roles := testRoles()
+		roles["admin"].Permissions[1].Description = "Write things"
+		require.ErrorIs(t, s.CreateRolesAndPermissions(ctx, roles), users.ErrPermissionDescriptionMismatch)
+	})
+
+	t.Run("SyncRolesAndPermissions deletes unlisted roles and orphaned permissions", func(t *testing.T) {
+		s := newService(t)
+		require.NoError(t, s.CreateRolesAndPermissions(ctx, testRoles()))
+		u, err := s.Create(ctx, "user1", email1, "password")
+		require.NoError(t, err)
+		require.NoError(t, s.AddRole(ctx, u, "admin"))
+		roles := testRoles()
+		delete(roles, "admin")
+		require.NoError(t, s.SyncRolesAndPermissions(ctx, roles))
+		_, err = s.Store().FindRole(ctx, "admin")
+		require.ErrorIs(t, err, users.ErrNotFound)
+		_, err = s.Store().FindPermission(ctx, "write")
+		require.ErrorIs(t, err, users.ErrNotFound)
+		_, err = s.Store().FindPermission(ctx, "read")
+		require.NoError(t, err)
+		ok, err := s.CheckPermission(ctx, u, "write")
+		require.NoError(t, err)
+		require.False(t, ok)
+	})
+
+	t.Run("SyncRolesAndPermissions rejects mismatched permission descriptions", func(t *testing.T) {
+		s := newService(t)
+		require.NoError(t, s.CreateRolesAndPermissions(ctx, testRoles()))
+		roles := testRoles()
+		delete(roles, "user")
+		roles["admin"].Permissions[1].Description = "Write things"
+		require.ErrorIs(t, s.SyncRolesAndPermissions(ctx, roles), users.ErrPermissionDescriptionMismatch)
+		// nothing was changed
+		_, err := s.Store().FindRole(ctx, "user")
+		require.NoError(t, err)
+	})
+
+	t.Run("ValidateToken accepts tokens from NewToken", func(t *testing.T) {
+		s := newService(t)
+		u, err := s.Create(ctx, "user1", email1, "password")
+		require.NoError(t, err)
+		tok, err := s.NewToken(ctx, u)
+		require.NoError(t, err)
+		found, err := s.ValidateToken(ctx, tok)
+		require.NoError(t, err)
+		require.Equal(t, u.ID, found.ID)
+		_, err = s.ValidateToken(ctx, "garbage")
+		require.Error(t, err)
+		other := users.NewService(
+			users.WithStore(s.Store()),
+			users.WithTokenOptions(&users.TokenOptions{Secret: "other secret"}),
+		)
+		_, err = other.ValidateToken(ctx, tok)
+		require.Error(t, err)
+	})
+
+	t.Run("ValidateToken rejects revoked tokens", func(t *testing.T) {
+		s := newService(t)
+		u, err := s.Create(ctx, "user1", email1, "password")
+		require.NoError(t, err)
+		tok, err := s.NewToken(ctx, u)
+		require.NoError(t, err)
+		require.NoError(t, s.RevokeTokens(ctx, u))
+		_, err = s.ValidateToken(ctx, tok)
+		require.ErrorIs(t, err, users.ErrTokenRevoked)
+	})
+
+	t.Run("ValidateToken fails after the user is deleted", func(t *testing.T) {
+		s := newService(t)
+		u, err := s.Create(ctx, "user1", email1, "password")
+		require.NoError(t, err)
+		tok, err := s.NewToken(ctx, u)
+		require.NoError(t, err)
+		require.NoError(t, s.Delete(ctx, u))
+		_, err = s.ValidateToken(ctx, tok)
+		require.ErrorIs(t, err, users.ErrNotFound)
+	})
+}
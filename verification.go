@@ -0,0 +1,54 @@
+package users
+
+import (
+	"context"
+	"time"
+
+	"github.com/smxlong/users/ent"
+	"github.com/smxlong/users/ent/onetimetoken"
+)
+
+// emailVerificationValidFor is the default validity of email verification
+// tokens.
+const emailVerificationValidFor = time.Hour
+
+// NewEmailVerificationToken issues a single-use token that verifies a user's
+// email address when passed to VerifyEmail. If validFor is zero, the token is
+// valid for one hour.
+func NewEmailVerificationToken(ctx context.Context, client *ent.Client, u *ent.User, validFor time.Duration) (string, error) {
+	if validFor == 0 {
+		validFor = emailVerificationValidFor
+	}
+	return newOneTimeToken(ctx, client, u, onetimetoken.PurposeEmailVerification, validFor, "")
+}
+
+// SendEmailVerification issues an email verification token and emails it to
+// the user as a link.
+func SendEmailVerification(ctx context.Context, client *ent.Client, u *ent.User, opts *EmailOptions) error {
+	validFor := opts.getValidFor(emailVerificationValidFor)
+	token, err := NewEmailVerificationToken(ctx, client, u, validFor)
+	if err != nil {
+		return err
+	}
+	return opts.send(ctx, u,
+		opts.getSubject("Verify your email address"),
+		"Follow this link to verify your email address:",
+		token, validFor)
+}
+
+// VerifyEmail checks an email verification token and marks the user's email
+// address as verified.
+func VerifyEmail(ctx context.Context, client *ent.Client, token string) (*ent.User, error) {
+	u, err := consumeOneTimeToken(ctx, client, token, onetimetoken.PurposeEmailVerification, "")
+	if err != nil {
+		return nil, err
+	}
+	return u.Update().
+		SetEmailVerifiedAt(clockNow(ctx)).
+		Save(ctx)
+}
+
+// EmailVerified checks if a user's email address has been verified.
+func EmailVerified(u *ent.User) bool {
+	return u.EmailVerifiedAt != nil
+}
@@ -0,0 +1,134 @@
+package users
+
+import (
+	"context"
+	"net/url"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func testEmailOptions(m Mailer) *EmailOptions {
+	return &EmailOptions{
+		Mailer: m,
+		From:   "noreply@example.com",
+		URL:    "https://example.com/link?x=1",
+	}
+}
+
+// testEmailedToken extracts the token from the link in an emailed message.
+func testEmailedToken(t *testing.T, msg *Message) string {
+	for _, line := range strings.Split(msg.Body, "\n") {
+		if strings.HasPrefix(line, "https://") {
+			u, err := url.Parse(line)
+			require.NoError(t, err)
+			return u.Query().Get("token")
+		}
+	}
+	t.Fatal("no link in message")
+	return ""
+}
+
+// testWaitForMessages waits for the mailer to have sent n messages, as emails
+// sent in the background may not have been sent yet.
+func testWaitForMessages(t *testing.T, m *MemoryMailer, n int) []*Message {
+	require.Eventually(t, func() bool {
+		return len(m.Messages()) >= n
+	}, 5*time.Second, time.Millisecond)
+	return m.Messages()
+}
+
+func Test_that_SendEmailVerification_emails_a_link(t *testing.T) {
+	client := setupAndMigrate(t)
+	ctx := context.Background()
+	u, err := Create(ctx, client, "user1", USER1_TEST_EMAIL, "password")
+	require.NoError(t, err)
+	require.False(t, EmailVerified(u))
+	m := &MemoryMailer{}
+	require.NoError(t, SendEmailVerification(ctx, client, u, testEmailOptions(m)))
+	msgs := m.Messages()
+	require.Len(t, msgs, 1)
+	require.Equal(t, []string{USER1_TEST_EMAIL}, msgs[0].To)
+	require.Equal(t, "noreply@example.com", msgs[0].From)
+	require.Contains(t, msgs[0].Body, "https://example.com/link?")
+	require.NotEmpty(t, testEmailedToken(t, msgs[0]))
+}
+
+func Test_that_VerifyEmail_works(t *testing.T) {
+	client := setupAndMigrate(t)
+	ctx := context.Background()
+	u, err := Create(ctx, client, "user1", USER1_TEST_EMAIL, "password")
+	require.NoError(t, err)
+	m := &MemoryMailer{}
+	require.NoError(t, SendEmailVerification(ctx, client, u, testEmailOptions(m)))
+	u2, err := VerifyEmail(ctx, client, testEmailedToken(t, m.Messages()[0]))
+	require.NoError(t, err)
+	require.Equal(t, u.ID, u2.ID)
+	require.True(t, EmailVerified(u2))
+	u3, err := FindByName(ctx, client, "user1")
+	require.NoError(t, err)
+	require.True(t, EmailVerified(u3))
+}
+
+func Test_that_VerifyEmail_tokens_are_single_use(t *testing.T) {
+	client := setupAndMigrate(t)
+	ctx := context.Background()
+	u, err := Create(ctx, client, "user1", USER1_TEST_EMAIL, "password")
+	require.NoError(t, err)
+	token, err := NewEmailVerificationToken(ctx, client, u, 0)
+	require.NoError(t, err)
+	_, err = VerifyEmail(ctx, client, token)
+	require.NoError(t, err)
+	_, err = VerifyEmail(ctx, client, token)
+	require.ErrorIs(t, err, ErrTokenInvalid)
+}
+
+func Test_that_VerifyEmail_fails_with_expired_token(t *testing.T) {
+	client := setupAndMigrate(t)
+	ctx := context.Background()
+	u, err := Create(ctx, client, "user1", USER1_TEST_EMAIL, "password")
+	require.NoError(t, err)
+	token, err := NewEmailVerificationToken(ctx, client, u, -time.Second)
+	require.NoError(t, err)
+	_, err = VerifyEmail(ctx, client, token)
+	require.ErrorIs(t, err, ErrTokenExpired)
+}
+
+func Test_that_VerifyEmail_fails_with_garbage_token(t *testing.T) {
+	client := setupAndMigrate(t)
+	ctx := context.Background()
+	_, err := VerifyEmail(ctx, client, "garbage")
+	require.ErrorIs(t, err, ErrTokenInvalid)
+}
+
+func Test_that_VerifyEmail_only_stores_token_hashes(t *testing.T) {
+	client := setupAndMigrate(t)
+	ctx := context.Background()
+	u, err := Create(ctx, client, "user1", USER1_TEST_EMAIL, "password")
+	require.NoError(t, err)
+	token, err := NewEmailVerificationToken(ctx, client, u, 0)
+	require.NoError(t, err)
+	otts, err := u.QueryOneTimeTokens().All(ctx)
+	require.NoError(t, err)
+	require.Len(t, otts, 1)
+	require.NotEqual(t, token, otts[0].TokenHash)
+}
+
+func Test_that_Login_can_require_verified_email(t *testing.T) {
+	client := setupAndMigrate(t)
+	ctx := context.Background()
+	u, err := Create(ctx, client, "user1", USER1_TEST_EMAIL, "password")
+	require.NoError(t, err)
+	_, err = LoginByName(ctx, client, "user1", "password")
+	require.NoError(t, err)
+	_, err = LoginByName(ctx, client, "user1", "password", RequireVerifiedEmail())
+	require.ErrorIs(t, err, ErrEmailNotVerified)
+	token, err := NewEmailVerificationToken(ctx, client, u, 0)
+	require.NoError(t, err)
+	_, err = VerifyEmail(ctx, client, token)
+	require.NoError(t, err)
+	_, err = LoginByEmail(ctx, client, USER1_TEST_EMAIL, "password", RequireVerifiedEmail())
+	require.NoError(t, err)
+}
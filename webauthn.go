@@ -0,0 +1,535 @@
+package users
+
+import (
+	"bytes"
+	"context"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/sha256"
+	"crypto/subtle"
+	"encoding/base64"
+	"encoding/binary"
+	"encoding/json"
+	"fmt"
+	"math/big"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/fxamacker/cbor/v2"
+	"github.com/smxlong/users/ent"
+	"github.com/smxlong/users/ent/credential"
+	"github.com/smxlong/users/ent/user"
+	"github.com/smxlong/users/ent/webauthnchallenge"
+)
+
+// COSE algorithm and key parameters. Only ES256 (ECDSA on P-256 with SHA-256)
+// is supported, which every WebAuthn authenticator implements.
+const (
+	coseAlgES256 = -7
+	coseKtyEC2   = 2
+	coseCrvP256  = 1
+)
+
+// Authenticator data flags.
+const (
+	authDataFlagUserPresent  = 0x01
+	authDataFlagAttestedData = 0x40
+)
+
+// webauthnChallengeSize is the number of random bytes in a challenge.
+const webauthnChallengeSize = 32
+
+// WebAuthnOptions identify the relying party for WebAuthn ceremonies.
+type WebAuthnOptions struct {
+	// RPID is the relying party ID, usually the site's domain. Required.
+	RPID string
+	// RPName is the relying party name shown to users. Optional. If not set,
+	// defaults to RPID.
+	RPName string
+	// Origin is the origin the browser reports, such as
+	// "https://example.com". Required.
+	Origin string
+	// Timeout is the duration a ceremony may take. Optional. If not set,
+	// defaults to 5 minutes.
+	Timeout time.Duration
+}
+
+// GetRPName returns the WebAuthnOptions RPName, or the default if not set.
+func (o *WebAuthnOptions) GetRPName() string {
+	if o.RPName == "" {
+		return o.RPID
+	}
+	return o.RPName
+}
+
+// GetTimeout returns the WebAuthnOptions Timeout, or the default if not set.
+func (o *WebAuthnOptions) GetTimeout() time.Duration {
+	if o.Timeout == 0 {
+		return 5 * time.Minute
+	}
+	return o.Timeout
+}
+
+// Base64URL is binary data encoded in JSON as unpadded base64url, as WebAuthn
+// does.
+type Base64URL []byte
+
+// MarshalJSON encodes the data as unpadded base64url.
+func (b Base64URL) MarshalJSON() ([]byte, error) {
+	return json.Marshal(base64.RawURLEncoding.EncodeToString(b))
+}
+
+// UnmarshalJSON decodes base64url data, with or without padding.
+func (b *Base64URL) UnmarshalJSON(data []byte) error {
+	var s string
+	if err := json.Unmarshal(data, &s); err != nil {
+		return err
+	}
+	decoded, err := base64.RawURLEncoding.DecodeString(strings.TrimRight(s, "="))
+	if err != nil {
+		return err
+	}
+	*b = decoded
+	return nil
+}
+
+// WebAuthnSession holds the state of a ceremony between its begin and finish
+// steps. It must be kept on the server, for example in the caller's session
+// store, and must not be trusted if it comes from the client.
+type WebAuthnSession struct {
+	Challenge Base64URL `json:"challenge"`
+	// UserID is the user taking part in the ceremony, or zero for a login
+	// with a discoverable credential.
+	UserID  int       `json:"user_id"`
+	Expires time.Time `json:"expires"`
+}
+
+// CredentialDescriptor identifies a credential.
+type CredentialDescriptor struct {
+	Type       string    `json:"type"`
+	ID         Base64URL `json:"id"`
+	Transports []string  `json:"transports,omitempty"`
+}
+
+// RelyingPartyEntity describes the relying party.
+type RelyingPartyEntity struct {
+	ID   string `json:"id"`
+	Name string `json:"name"`
+}
+
+// UserEntity describes the user to the authenticator.
+type UserEntity struct {
+	ID          Base64URL `json:"id"`
+	Name        string    `json:"name"`
+	DisplayName string    `json:"displayName"`
+}
+
+// CredentialParameter describes an acceptable credential type.
+type CredentialParameter struct {
+	Type string `json:"type"`
+	Alg  int    `json:"alg"`
+}
+
+// AuthenticatorSelection describes the authenticators the relying party
+// accepts.
+type AuthenticatorSelection struct {
+	ResidentKey      string `json:"residentKey"`
+	UserVerification string `json:"userVerification"`
+}
+
+// CredentialCreationOptions are the options to pass to
+// navigator.credentials.create() as publicKey.
+type CredentialCreationOptions struct {
+	Challenge              Base64URL              `json:"challenge"`
+	RP                     RelyingPartyEntity     `json:"rp"`
+	User                   UserEntity             `json:"user"`
+	PubKeyCredParams       []CredentialParameter  `json:"pubKeyCredParams"`
+	Timeout                int64                  `json:"timeout"`
+	ExcludeCredentials     []CredentialDescriptor `json:"excludeCredentials"`
+	AuthenticatorSelection AuthenticatorSelection `json:"authenticatorSelection"`
+	Attestation            string                 `json:"attestation"`
+}
+
+// CredentialRequestOptions are the options to pass to
+// navigator.credentials.get() as publicKey.
+type CredentialRequestOptions struct {
+	Challenge        Base64URL              `json:"challenge"`
+	Timeout          int64                  `json:"timeout"`
+	RPID             string                 `json:"rpId"`
+	AllowCredentials []CredentialDescriptor `json:"allowCredentials"`
+	UserVerification string                 `json:"userVerification"`
+}
+
+// AttestationResponse is the result of navigator.credentials.create().
+type AttestationResponse struct {
+	RawID             Base64URL `json:"rawId"`
+	ClientDataJSON    Base64URL `json:"clientDataJSON"`
+	AttestationObject Base64URL `json:"attestationObject"`
+	Transports        []string  `json:"transports"`
+}
+
+// AssertionResponse is the result of navigator.credentials.get().
+type AssertionResponse struct {
+	RawID             Base64URL `json:"rawId"`
+	ClientDataJSON    Base64URL `json:"clientDataJSON"`
+	AuthenticatorData Base64URL `json:"authenticatorData"`
+	Signature         Base64URL `json:"signature"`
+	UserHandle        Base64URL `json:"userHandle"`
+}
+
+// BeginWebAuthnRegistration starts registering a new credential for a user.
+func BeginWebAuthnRegistration(ctx context.Context, client *ent.Client, u *ent.User, opts *WebAuthnOptions) (*CredentialCreationOptions, *WebAuthnSession, error) {
+	session, err := newWebAuthnSession(ctx, client, u, opts)
+	if err != nil {
+		return nil, nil, err
+	}
+	exclude, err := credentialDescriptors(ctx, u)
+	if err != nil {
+		return nil, nil, err
+	}
+	return &CredentialCreationOptions{
+		Challenge: session.Challenge,
+		RP: RelyingPartyEntity{
+			ID:   opts.RPID,
+			Name: opts.GetRPName(),
+		},
+		User: UserEntity{
+			ID:          webauthnUserHandle(u),
+			Name:        u.Email,
+			DisplayName: u.Name,
+		},
+		PubKeyCredParams: []CredentialParameter{
+			{Type: "public-key", Alg: coseAlgES256},
+		},
+		Timeout:            opts.GetTimeout().Milliseconds(),
+		ExcludeCredentials: exclude,
+		AuthenticatorSelection: AuthenticatorSelection{
+			ResidentKey:      "preferred",
+			UserVerification: "preferred",
+		},
+		Attestation: "none",
+	}, session, nil
+}
+
+// FinishWebAuthnRegistration verifies the authenticator's response and stores
+// the new credential.
+func FinishWebAuthnRegistration(ctx context.Context, client *ent.Client, u *ent.User, session *WebAuthnSession, res *AttestationResponse, opts *WebAuthnOptions) (*ent.Credential, error) {
+	if session.UserID != u.ID {
+		return nil, fmt.Errorf("%w: session is for another user", ErrWebAuthnInvalid)
+	}
+	if err := verifyClientData(res.ClientDataJSON, "webauthn.create", session, clockNow(ctx), opts); err != nil {
+		return nil, err
+	}
+	if err := useWebAuthnSession(ctx, client, session); err != nil {
+		return nil, err
+	}
+	var att struct {
+		Fmt      string          `cbor:"fmt"`
+		AttStmt  cbor.RawMessage `cbor:"attStmt"`
+		AuthData []byte          `cbor:"authData"`
+	}
+	if err := cbor.Unmarshal(res.AttestationObject, &att); err != nil {
+		return nil, fmt.Errorf("%w: attestation object: %s", ErrWebAuthnInvalid, err)
+	}
+	if att.Fmt != "none" {
+		return nil, fmt.Errorf("%w: %s", ErrWebAuthnAttestationUnsupported, att.Fmt)
+	}
+	ad, err := parseAuthenticatorData(att.AuthData, opts)
+	if err != nil {
+		return nil, err
+	}
+	if ad.flags&authDataFlagAttestedData == 0 {
+		return nil, fmt.Errorf("%w: no attested credential data", ErrWebAuthnInvalid)
+	}
+	if _, err := parseCOSEKey(ad.publicKey); err != nil {
+		return nil, err
+	}
+	return client.Credential.Create().
+		SetCredentialID(ad.credentialID).
+		SetPublicKey(ad.publicKey).
+		SetSignCount(ad.signCount).
+		SetTransports(res.Transports).
+		SetAaguid(ad.aaguid).
+		SetUser(u).
+		Save(ctx)
+}
+
+// BeginWebAuthnLogin starts a login with a credential. If u is nil, any
+// discoverable credential (passkey) is accepted; otherwise only the user's
+// credentials are.
+func BeginWebAuthnLogin(ctx context.Context, client *ent.Client, u *ent.User, opts *WebAuthnOptions) (*CredentialRequestOptions, *WebAuthnSession, error) {
+	session, err := newWebAuthnSession(ctx, client, u, opts)
+	if err != nil {
+		return nil, nil, err
+	}
+	var allow []CredentialDescriptor
+	if u != nil {
+		if allow, err = credentialDescriptors(ctx, u); err != nil {
+			return nil, nil, err
+		}
+	}
+	return &CredentialRequestOptions{
+		Challenge:        session.Challenge,
+		Timeout:          opts.GetTimeout().Milliseconds(),
+		RPID:             opts.RPID,
+		AllowCredentials: allow,
+		UserVerification: "preferred",
+	}, session, nil
+}
+
+// FinishWebAuthnLogin verifies the authenticator's response and returns the
+// user who owns the credential. The session is used up, even if the login
+// fails. A signature counter that fails to increase indicates a cloned
+// authenticator, and the login is rejected with
+// ErrWebAuthnSignCountRegression. The login is recorded as Login records it,
+// with the login options.
+func FinishWebAuthnLogin(ctx context.Context, client *ent.Client, session *WebAuthnSession, res *AssertionResponse, opts *WebAuthnOptions, loginOpts ...LoginOption) (*ent.User, error) {
+	if err := verifyClientData(res.ClientDataJSON, "webauthn.get", session, clockNow(ctx), opts); err != nil {
+		return nil, err
+	}
+	if err := useWebAuthnSession(ctx, client, session); err != nil {
+		return nil, err
+	}
+	cred, err := client.Credential.Query().
+		Where(credential.CredentialID(res.RawID)).
+		WithUser().
+		Only(ctx)
+	if ent.IsNotFound(err) {
+		return nil, ErrWebAuthnCredentialUnknown
+	}
+	if err != nil {
+		return nil, err
+	}
+	// The user isn't loaded if they have been deleted.
+	u := cred.Edges.User
+	if u == nil || session.UserID != 0 && session.UserID != u.ID {
+		return nil, ErrWebAuthnCredentialUnknown
+	}
+	if len(res.UserHandle) > 0 && !bytes.Equal(res.UserHandle, webauthnUserHandle(u)) {
+		return nil, fmt.Errorf("%w: user handle mismatch", ErrWebAuthnInvalid)
+	}
+	ad, err := parseAuthenticatorData(res.AuthenticatorData, opts)
+	if err != nil {
+		return nil, err
+	}
+	key, err := parseCOSEKey(cred.PublicKey)
+	if err != nil {
+		return nil, err
+	}
+	clientDataHash := sha256.Sum256(res.ClientDataJSON)
+	digest := sha256.Sum256(append(append([]byte{}, res.AuthenticatorData...), clientDataHash[:]...))
+	if !ecdsa.VerifyASN1(key, digest[:], res.Signature) {
+		return nil, fmt.Errorf("%w: bad signature", ErrWebAuthnInvalid)
+	}
+	// Authenticators that don't implement a counter always report zero.
+	if ad.signCount != 0 || cred.SignCount != 0 {
+		if ad.signCount <= cred.SignCount {
+			return nil, ErrWebAuthnSignCountRegression
+		}
+		n, err := client.Credential.Update().
+			Where(credential.ID(cred.ID), credential.SignCountLT(ad.signCount)).
+			SetSignCount(ad.signCount).
+			Save(ctx)
+		if err != nil {
+			return nil, err
+		}
+		if n == 0 {
+			return nil, ErrWebAuthnSignCountRegression
+		}
+	}
+	if err := CheckStatus(ctx, u); err != nil {
+		return nil, err
+	}
+	return recordEntLogin(ctx, client, u, loginOpts)
+}
+
+// Credentials lists a user's WebAuthn credentials.
+func Credentials(ctx context.Context, client *ent.Client, u *ent.User) ([]*ent.Credential, error) {
+	return u.QueryCredentials().All(ctx)
+}
+
+// DeleteCredential removes one of a user's WebAuthn credentials.
+func DeleteCredential(ctx context.Context, client *ent.Client, u *ent.User, credentialID []byte) error {
+	n, err := client.Credential.Delete().
+		Where(credential.CredentialID(credentialID), credential.HasUserWith(user.ID(u.ID))).
+		Exec(ctx)
+	if err != nil {
+		return err
+	}
+	if n == 0 {
+		return ErrWebAuthnCredentialUnknown
+	}
+	return nil
+}
+
+// newWebAuthnSession starts a ceremony with a fresh challenge. The challenge
+// is recorded, so that finishing the ceremony can use it up, and the
+// challenges of ceremonies that expired unfinished are removed.
+func newWebAuthnSession(ctx context.Context, client *ent.Client, u *ent.User, opts *WebAuthnOptions) (*WebAuthnSession, error) {
+	challenge := make([]byte, webauthnChallengeSize)
+	if _, err := rand.Read(challenge); err != nil {
+		return nil, err
+	}
+	now := clockNow(ctx)
+	session := &WebAuthnSession{
+		Challenge: challenge,
+		Expires:   now.Add(opts.GetTimeout()),
+	}
+	if u != nil {
+		session.UserID = u.ID
+	}
+	if _, err := client.WebAuthnChallenge.Delete().
+		Where(webauthnchallenge.ExpiresAtLT(now)).
+		Exec(ctx); err != nil {
+		return nil, err
+	}
+	if err := client.WebAuthnChallenge.Create().
+		SetChallengeHash(hashOneTimeToken(string(challenge))).
+		SetExpiresAt(session.Expires).
+		Exec(ctx); err != nil {
+		return nil, err
+	}
+	return session, nil
+}
+
+// useWebAuthnSession uses up a session's challenge, so that a response to it
+// can't be replayed, such as from an authenticator without a signature
+// counter.
+func useWebAuthnSession(ctx context.Context, client *ent.Client, session *WebAuthnSession) error {
+	n, err := client.WebAuthnChallenge.Delete().
+		Where(webauthnchallenge.ChallengeHash(hashOneTimeToken(string(session.Challenge)))).
+		Exec(ctx)
+	if err != nil {
+		return err
+	}
+	if n == 0 {
+		return fmt.Errorf("%w: session already used", ErrWebAuthnInvalid)
+	}
+	return nil
+}
+
+// webauthnUserHandle returns the opaque user handle stored by authenticators.
+func webauthnUserHandle(u *ent.User) []byte {
+	return []byte(strconv.Itoa(u.ID))
+}
+
+// credentialDescriptors describes a user's credentials.
+func credentialDescriptors(ctx context.Context, u *ent.User) ([]CredentialDescriptor, error) {
+	creds, err := u.QueryCredentials().All(ctx)
+	if err != nil {
+		return nil, err
+	}
+	descriptors := make([]CredentialDescriptor, len(creds))
+	for i, c := range creds {
+		descriptors[i] = CredentialDescriptor{
+			Type:       "public-key",
+			ID:         c.CredentialID,
+			Transports: c.Transports,
+		}
+	}
+	return descriptors, nil
+}
+
+// verifyClientData checks the client data against the ceremony at the time
+// now.
+func verifyClientData(clientDataJSON []byte, typ string, session *WebAuthnSession, now time.Time, opts *WebAuthnOptions) error {
+	if now.After(session.Expires) {
+		return ErrWebAuthnSessionExpired
+	}
+	var cd struct {
+		Type      string    `json:"type"`
+		Challenge Base64URL `json:"challenge"`
+		Origin    string    `json:"origin"`
+	}
+	if err := json.Unmarshal(clientDataJSON, &cd); err != nil {
+		return fmt.Errorf("%w: client data: %s", ErrWebAuthnInvalid, err)
+	}
+	if cd.Type != typ {
+		return fmt.Errorf("%w: client data type %q", ErrWebAuthnInvalid, cd.Type)
+	}
+	if subtle.ConstantTimeCompare(cd.Challenge, session.Challenge) != 1 {
+		return fmt.Errorf("%w: challenge mismatch", ErrWebAuthnInvalid)
+	}
+	if cd.Origin != opts.Origin {
+		return fmt.Errorf("%w: origin %q", ErrWebAuthnInvalid, cd.Origin)
+	}
+	return nil
+}
+
+// authenticatorData is the parsed authenticator data.
+type authenticatorData struct {
+	flags        byte
+	signCount    uint32
+	aaguid       []byte
+	credentialID []byte
+	publicKey    []byte
+}
+
+// parseAuthenticatorData parses authenticator data and checks the relying
+// party ID hash and the user presence flag.
+func parseAuthenticatorData(data []byte, opts *WebAuthnOptions) (*authenticatorData, error) {
+	if len(data) < 37 {
+		return nil, fmt.Errorf("%w: authenticator data too short", ErrWebAuthnInvalid)
+	}
+	rpIDHash := sha256.Sum256([]byte(opts.RPID))
+	if !bytes.Equal(data[:32], rpIDHash[:]) {
+		return nil, fmt.Errorf("%w: relying party ID mismatch", ErrWebAuthnInvalid)
+	}
+	ad := &authenticatorData{
+		flags:     data[32],
+		signCount: binary.BigEndian.Uint32(data[33:37]),
+	}
+	if ad.flags&authDataFlagUserPresent == 0 {
+		return nil, fmt.Errorf("%w: user not present", ErrWebAuthnInvalid)
+	}
+	if ad.flags&authDataFlagAttestedData == 0 {
+		return ad, nil
+	}
+	rest := data[37:]
+	if len(rest) < 18 {
+		return nil, fmt.Errorf("%w: attested credential data too short", ErrWebAuthnInvalid)
+	}
+	ad.aaguid = rest[:16]
+	n := int(binary.BigEndian.Uint16(rest[16:18]))
+	rest = rest[18:]
+	if len(rest) < n {
+		return nil, fmt.Errorf("%w: credential ID too short", ErrWebAuthnInvalid)
+	}
+	ad.credentialID = rest[:n]
+	// The public key is followed by optional extensions, so decode it to
+	// find where it ends.
+	var key cbor.RawMessage
+	if _, err := cbor.UnmarshalFirst(rest[n:], &key); err != nil {
+		return nil, fmt.Errorf("%w: public key: %s", ErrWebAuthnInvalid, err)
+	}
+	ad.publicKey = key
+	return ad, nil
+}
+
+// parseCOSEKey parses a COSE-encoded ES256 public key.
+func parseCOSEKey(data []byte) (*ecdsa.PublicKey, error) {
+	var k struct {
+		Kty int64  `cbor:"1,keyasint"`
+		Alg int64  `cbor:"3,keyasint"`
+		Crv int64  `cbor:"-1,keyasint"`
+		X   []byte `cbor:"-2,keyasint"`
+		Y   []byte `cbor:"-3,keyasint"`
+	}
+	if err := cbor.Unmarshal(data, &k); err != nil {
+		return nil, fmt.Errorf("%w: public key: %s", ErrWebAuthnInvalid, err)
+	}
+	if k.Kty != coseKtyEC2 || k.Alg != coseAlgES256 || k.Crv != coseCrvP256 {
+		return nil, fmt.Errorf("%w: kty %d alg %d crv %d", ErrWebAuthnAlgorithmUnsupported, k.Kty, k.Alg, k.Crv)
+	}
+	key := &ecdsa.PublicKey{
+		Curve: elliptic.P256(),
+		X:     new(big.Int).SetBytes(k.X),
+		Y:     new(big.Int).SetBytes(k.Y),
+	}
+	if len(k.X) != 32 || len(k.Y) != 32 || !key.Curve.IsOnCurve(key.X, key.Y) {
+		return nil, fmt.Errorf("%w: public key not on curve", ErrWebAuthnInvalid)
+	}
+	return key, nil
+}
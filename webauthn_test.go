@@ -0,0 +1,302 @@
+package users
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/binary"
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/fxamacker/cbor/v2"
+	"github.com/stretchr/testify/require"
+
+	"github.com/smxlong/users/ent"
+)
+
+func testWebAuthnOptions() *WebAuthnOptions {
+	return &WebAuthnOptions{
+		RPID:   "example.com",
+		Origin: "https://example.com",
+	}
+}
+
+// softAuthenticator is a software WebAuthn authenticator holding a single
+// ES256 credential.
+type softAuthenticator struct {
+	key       *ecdsa.PrivateKey
+	id        []byte
+	signCount uint32
+	// noCounter makes the authenticator always report a zero signature
+	// counter, as authenticators that don't implement one do.
+	noCounter bool
+	origin    string
+	rpID      string
+}
+
+func newSoftAuthenticator(t *testing.T, opts *WebAuthnOptions) *softAuthenticator {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+	id := make([]byte, 16)
+	_, err = rand.Read(id)
+	require.NoError(t, err)
+	return &softAuthenticator{key: key, id: id, origin: opts.Origin, rpID: opts.RPID}
+}
+
+func (a *softAuthenticator) clientData(t *testing.T, typ string, challenge []byte) []byte {
+	cd, err := json.Marshal(map[string]string{
+		"type":      typ,
+		"challenge": base64.RawURLEncoding.EncodeToString(challenge),
+		"origin":    a.origin,
+	})
+	require.NoError(t, err)
+	return cd
+}
+
+func (a *softAuthenticator) authData(flags byte, attested []byte) []byte {
+	rpIDHash := sha256.Sum256([]byte(a.rpID))
+	data := append([]byte{}, rpIDHash[:]...)
+	data = append(data, flags)
+	data = binary.BigEndian.AppendUint32(data, a.signCount)
+	return append(data, attested...)
+}
+
+func (a *softAuthenticator) create(t *testing.T, opts *CredentialCreationOptions) *AttestationResponse {
+	coseKey, err := cbor.Marshal(map[int]interface{}{
+		1:  coseKtyEC2,
+		3:  coseAlgES256,
+		-1: coseCrvP256,
+		-2: a.key.X.FillBytes(make([]byte, 32)),
+		-3: a.key.Y.FillBytes(make([]byte, 32)),
+	})
+	require.NoError(t, err)
+	attested := make([]byte, 16)
+	attested = binary.BigEndian.AppendUint16(attested, uint16(len(a.id)))
+	attested = append(attested, a.id...)
+	attested = append(attested, coseKey...)
+	att, err := cbor.Marshal(map[string]interface{}{
+		"fmt":      "none",
+		"attStmt":  map[string]interface{}{},
+		"authData": a.authData(authDataFlagUserPresent|authDataFlagAttestedData, attested),
+	})
+	require.NoError(t, err)
+	return &AttestationResponse{
+		RawID:             a.id,
+		ClientDataJSON:    a.clientData(t, "webauthn.create", opts.Challenge),
+		AttestationObject: att,
+		Transports:        []string{"internal"},
+	}
+}
+
+func (a *softAuthenticator) get(t *testing.T, opts *CredentialRequestOptions) *AssertionResponse {
+	if !a.noCounter {
+		a.signCount++
+	}
+	authData := a.authData(authDataFlagUserPresent, nil)
+	clientData := a.clientData(t, "webauthn.get", opts.Challenge)
+	clientDataHash := sha256.Sum256(clientData)
+	digest := sha256.Sum256(append(append([]byte{}, authData...), clientDataHash[:]...))
+	sig, err := ecdsa.SignASN1(rand.Reader, a.key, digest[:])
+	require.NoError(t, err)
+	return &AssertionResponse{
+		RawID:             a.id,
+		ClientDataJSON:    clientData,
+		AuthenticatorData: authData,
+		Signature:         sig,
+	}
+}
+
+// setupWebAuthnUser creates a user with a credential registered on a software
+// authenticator.
+func setupWebAuthnUser(t *testing.T, client *ent.Client) (*ent.User, *softAuthenticator) {
+	ctx := context.Background()
+	u, err := Create(ctx, client, "user1", USER1_TEST_EMAIL, "password")
+	require.NoError(t, err)
+	a := newSoftAuthenticator(t, testWebAuthnOptions())
+	opts, session, err := BeginWebAuthnRegistration(ctx, client, u, testWebAuthnOptions())
+	require.NoError(t, err)
+	_, err = FinishWebAuthnRegistration(ctx, client, u, session, a.create(t, opts), testWebAuthnOptions())
+	require.NoError(t, err)
+	return u, a
+}
+
+func Test_that_WebAuthn_registration_works(t *testing.T) {
+	client := setupAndMigrate(t)
+	ctx := context.Background()
+	u, a := setupWebAuthnUser(t, client)
+	creds, err := Credentials(ctx, client, u)
+	require.NoError(t, err)
+	require.Len(t, creds, 1)
+	require.Equal(t, a.id, creds[0].CredentialID)
+	require.Equal(t, []string{"internal"}, creds[0].Transports)
+	require.Len(t, creds[0].Aaguid, 16)
+	// registering again excludes the existing credential
+	opts, _, err := BeginWebAuthnRegistration(ctx, client, u, testWebAuthnOptions())
+	require.NoError(t, err)
+	require.Len(t, opts.ExcludeCredentials, 1)
+	require.Equal(t, Base64URL(a.id), opts.ExcludeCredentials[0].ID)
+}
+
+func Test_that_WebAuthn_registration_fails_with_wrong_challenge(t *testing.T) {
+	client := setupAndMigrate(t)
+	ctx := context.Background()
+	u, err := Create(ctx, client, "user1", USER1_TEST_EMAIL, "password")
+	require.NoError(t, err)
+	a := newSoftAuthenticator(t, testWebAuthnOptions())
+	opts, _, err := BeginWebAuthnRegistration(ctx, client, u, testWebAuthnOptions())
+	require.NoError(t, err)
+	_, session, err := BeginWebAuthnRegistration(ctx, client, u, testWebAuthnOptions())
+	require.NoError(t, err)
+	_, err = FinishWebAuthnRegistration(ctx, client, u, session, a.create(t, opts), testWebAuthnOptions())
+	require.ErrorIs(t, err, ErrWebAuthnInvalid)
+}
+
+func Test_that_WebAuthn_registration_fails_with_wrong_origin(t *testing.T) {
+	client := setupAndMigrate(t)
+	ctx := context.Background()
+	u, err := Create(ctx, client, "user1", USER1_TEST_EMAIL, "password")
+	require.NoError(t, err)
+	a := newSoftAuthenticator(t, testWebAuthnOptions())
+	a.origin = "https://evil.example.com"
+	opts, session, err := BeginWebAuthnRegistration(ctx, client, u, testWebAuthnOptions())
+	require.NoError(t, err)
+	_, err = FinishWebAuthnRegistration(ctx, client, u, session, a.create(t, opts), testWebAuthnOptions())
+	require.ErrorIs(t, err, ErrWebAuthnInvalid)
+}
+
+func Test_that_WebAuthn_registration_fails_with_expired_session(t *testing.T) {
+	client := setupAndMigrate(t)
+	ctx := context.Background()
+	u, err := Create(ctx, client, "user1", USER1_TEST_EMAIL, "password")
+	require.NoError(t, err)
+	a := newSoftAuthenticator(t, testWebAuthnOptions())
+	clock := NewFakeClock(time.Now())
+	ctx = ContextWithClock(ctx, clock)
+	opts, session, err := BeginWebAuthnRegistration(ctx, client, u, testWebAuthnOptions())
+	require.NoError(t, err)
+	clock.Advance(testWebAuthnOptions().GetTimeout() + time.Second)
+	_, err = FinishWebAuthnRegistration(ctx, client, u, session, a.create(t, opts), testWebAuthnOptions())
+	require.ErrorIs(t, err, ErrWebAuthnSessionExpired)
+}
+
+func Test_that_WebAuthn_login_works(t *testing.T) {
+	client := setupAndMigrate(t)
+	ctx := context.Background()
+	u, a := setupWebAuthnUser(t, client)
+	opts, session, err := BeginWebAuthnLogin(ctx, client, u, testWebAuthnOptions())
+	require.NoError(t, err)
+	require.Len(t, opts.AllowCredentials, 1)
+	u2, err := FinishWebAuthnLogin(ctx, client, session, a.get(t, opts), testWebAuthnOptions())
+	require.NoError(t, err)
+	require.Equal(t, u.ID, u2.ID)
+	creds, err := Credentials(ctx, client, u)
+	require.NoError(t, err)
+	require.Equal(t, uint32(1), creds[0].SignCount)
+}
+
+func Test_that_WebAuthn_login_records_the_login(t *testing.T) {
+	client := setupAndMigrate(t)
+	clock := NewFakeClock(time.Now())
+	ctx := ContextWithClock(context.Background(), clock)
+	u, a := setupWebAuthnUser(t, client)
+	_, err := Login(ctx, u, "wrong password")
+	require.ErrorIs(t, err, ErrInvalidCredentials)
+	opts, session, err := BeginWebAuthnLogin(ctx, client, u, testWebAuthnOptions())
+	require.NoError(t, err)
+	u, err = FinishWebAuthnLogin(ctx, client, session, a.get(t, opts), testWebAuthnOptions(), WithIP("192.0.2.1"))
+	require.NoError(t, err)
+	require.NotNil(t, u.LastLoginAt)
+	require.True(t, clock.Now().Equal(*u.LastLoginAt))
+	require.Equal(t, "192.0.2.1", u.LastLoginIP)
+	require.Zero(t, u.FailedLoginCount)
+}
+
+func Test_that_WebAuthn_login_works_with_discoverable_credential(t *testing.T) {
+	client := setupAndMigrate(t)
+	ctx := context.Background()
+	u, a := setupWebAuthnUser(t, client)
+	opts, session, err := BeginWebAuthnLogin(ctx, client, nil, testWebAuthnOptions())
+	require.NoError(t, err)
+	require.Empty(t, opts.AllowCredentials)
+	res := a.get(t, opts)
+	res.UserHandle = webauthnUserHandle(u)
+	u2, err := FinishWebAuthnLogin(ctx, client, session, res, testWebAuthnOptions())
+	require.NoError(t, err)
+	require.Equal(t, u.ID, u2.ID)
+}
+
+func Test_that_WebAuthn_login_fails_with_bad_signature(t *testing.T) {
+	client := setupAndMigrate(t)
+	ctx := context.Background()
+	u, a := setupWebAuthnUser(t, client)
+	opts, session, err := BeginWebAuthnLogin(ctx, client, u, testWebAuthnOptions())
+	require.NoError(t, err)
+	res := a.get(t, opts)
+	res.Signature[len(res.Signature)-1] ^= 0xff
+	_, err = FinishWebAuthnLogin(ctx, client, session, res, testWebAuthnOptions())
+	require.ErrorIs(t, err, ErrWebAuthnInvalid)
+}
+
+func Test_that_WebAuthn_login_detects_sign_count_regression(t *testing.T) {
+	client := setupAndMigrate(t)
+	ctx := context.Background()
+	u, a := setupWebAuthnUser(t, client)
+	opts, session, err := BeginWebAuthnLogin(ctx, client, u, testWebAuthnOptions())
+	require.NoError(t, err)
+	_, err = FinishWebAuthnLogin(ctx, client, session, a.get(t, opts), testWebAuthnOptions())
+	require.NoError(t, err)
+	// a clone of the authenticator reuses an old counter value
+	a.signCount = 0
+	opts, session, err = BeginWebAuthnLogin(ctx, client, u, testWebAuthnOptions())
+	require.NoError(t, err)
+	_, err = FinishWebAuthnLogin(ctx, client, session, a.get(t, opts), testWebAuthnOptions())
+	require.ErrorIs(t, err, ErrWebAuthnSignCountRegression)
+}
+
+func Test_that_WebAuthn_login_sessions_are_single_use(t *testing.T) {
+	client := setupAndMigrate(t)
+	ctx := context.Background()
+	u, a := setupWebAuthnUser(t, client)
+	a.noCounter = true
+	opts, session, err := BeginWebAuthnLogin(ctx, client, u, testWebAuthnOptions())
+	require.NoError(t, err)
+	res := a.get(t, opts)
+	_, err = FinishWebAuthnLogin(ctx, client, session, res, testWebAuthnOptions())
+	require.NoError(t, err)
+	// a captured response can't be replayed
+	_, err = FinishWebAuthnLogin(ctx, client, session, res, testWebAuthnOptions())
+	require.ErrorIs(t, err, ErrWebAuthnInvalid)
+}
+
+func Test_that_WebAuthn_login_fails_with_unknown_credential(t *testing.T) {
+	client := setupAndMigrate(t)
+	ctx := context.Background()
+	u, _ := setupWebAuthnUser(t, client)
+	other := newSoftAuthenticator(t, testWebAuthnOptions())
+	opts, session, err := BeginWebAuthnLogin(ctx, client, u, testWebAuthnOptions())
+	require.NoError(t, err)
+	_, err = FinishWebAuthnLogin(ctx, client, session, other.get(t, opts), testWebAuthnOptions())
+	require.ErrorIs(t, err, ErrWebAuthnCredentialUnknown)
+}
+
+func Test_that_DeleteCredential_works(t *testing.T) {
+	client := setupAndMigrate(t)
+	ctx := context.Background()
+	u, a := setupWebAuthnUser(t, client)
+	require.NoError(t, DeleteCredential(ctx, client, u, a.id))
+	require.ErrorIs(t, DeleteCredential(ctx, client, u, a.id), ErrWebAuthnCredentialUnknown)
+}
+
+func Test_that_Base64URL_round_trips_through_JSON(t *testing.T) {
+	b := Base64URL{0xfb, 0xff, 0x01}
+	js, err := json.Marshal(b)
+	require.NoError(t, err)
+	require.Equal(t, `"-_8B"`, string(js))
+	var b2 Base64URL
+	require.NoError(t, json.Unmarshal(js, &b2))
+	require.Equal(t, b, b2)
+}